@@ -32,8 +32,22 @@ func main() {
 	}
 	defer app.Close()
 
+	// Bulk ingest jobs run in the API process that started them; fail those
+	// whose process stopped heartbeating.
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			app.BulkIngestUC.RecoverStale(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
 	rt := httpadapter.NewRouter(cfg, app.IngestUC, app.QueryUC, app.Repo, app.AgentUC, app.ModelProviderMap)
-	app.AgentUC.SetObsidianWriter(rt)
 	rt.SetGraphStore(app.GraphStore)
 	rt.SetFeedbackStore(app.FeedbackStore)
 	rt.SetEventStore(app.EventStore)
@@ -41,11 +55,29 @@ func main() {
 	rt.SetScheduleStore(app.ScheduleStore)
 	rt.SetDocumentRepository(app.Repo)
 	rt.SetObjectStorage(app.Storage)
+	rt.SetDocumentDeleter(app.DeleteUC)
+	rt.SetDocumentACLService(app.DocumentACLUC)
+	rt.SetConversationService(app.ConversationUC)
+	rt.SetMemoryFactService(app.MemoryFactUC)
+	rt.SetEvalService(app.EvalUC)
+	rt.SetBulkIngestService(app.BulkIngestUC)
+	rt.SetVaultSyncService(app.VaultSyncUC)
+	rt.SetAgentJobService(app.AgentJobUC)
+	rt.SetAgentRunService(app.AgentRunUC)
 	rt.SetHTTPToolDefs(app.ToolRegistry.ListHTTPToolDefs())
 	rt.SetRuntimeModelConfig(app.RuntimeModelCfg)
+	if app.AuthUC != nil {
+		rt.SetAuthService(app.AuthUC)
+		logger.Info("auth_enabled")
+	}
+	if app.OrchestratorUC != nil {
+		rt.SetOrchestrationService(app.OrchestratorUC)
+	}
 
 	// Populate agent system prompt with available Obsidian vaults.
-	if vaultList := rt.ListVaultIDs(); len(vaultList) > 0 {
+	if vaultList, err := app.VaultSyncUC.ListVaults(ctx); err != nil {
+		logger.Warn("agent_obsidian_vaults_load_failed", "error", err)
+	} else if len(vaultList) > 0 {
 		vaults := make([]ports.AgentVaultInfo, 0, len(vaultList))
 		for _, v := range vaultList {
 			vaults = append(vaults, ports.AgentVaultInfo{ID: v.ID, Name: v.Name})
@@ -58,7 +90,7 @@ func main() {
 		mcpHandler := paamcp.NewMCPHandler(paamcp.ServerDeps{
 			QuerySvc:       app.QueryUC,
 			WebSearcher:    app.WebSearcher,
-			ObsidianWriter: rt,
+			ObsidianWriter: app.VaultSyncUC,
 			Tasks:          app.Tasks,
 			KnowledgeTopK:  cfg.AgentKnowledgeTopK,
 		})
@@ -66,7 +98,10 @@ func main() {
 		logger.Info("mcp_server_enabled", "endpoint", "/mcp")
 	}
 
-	rt.SyncRegisteredVaults(ctx)
+	if cfg.ObsidianSyncRunner == "api" {
+		go app.VaultSyncUC.Run(ctx)
+		logger.Info("obsidian_sync_runner_started")
+	}
 	handler := rt.Handler()
 	server := &http.Server{
 		Addr:              ":" + cfg.APIPort,
@@ -133,6 +133,42 @@ func main() {
 		}
 	}()
 
+	// Agent job subscriber. Jobs run for minutes, so each one gets its own
+	// goroutine and the worker context; a job interrupted by shutdown is
+	// resumed from its checkpoints by the next worker.
+	go func() {
+		logger.Info("worker_agent_jobs_subscribed", "concurrency", cfg.AgentJobConcurrency)
+		slots := make(chan struct{}, max(cfg.AgentJobConcurrency, 1))
+		if err := app.AgentJobQueue.SubscribeAgentJob(ctx, func(_ context.Context, jobID string) error {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			go func() {
+				defer func() { <-slots }()
+				if err := app.AgentJobUC.Run(ctx, jobID); err != nil {
+					logger.Error("agent_job_run_failed", "job_id", jobID, "error", err)
+				}
+			}()
+			return nil
+		}); err != nil {
+			logger.Error("worker_agent_jobs_subscribe_error", "error", err)
+		}
+	}()
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+		for {
+			app.AgentJobUC.RecoverStale(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
 	// Self-improvement cron job.
 	if app.SelfImproveUC != nil {
 		go func() {
@@ -172,5 +208,10 @@ func main() {
 		}()
 	}
 
+	if cfg.ObsidianSyncRunner == "worker" {
+		go app.VaultSyncUC.Run(ctx)
+		logger.Info("obsidian_sync_runner_started")
+	}
+
 	<-ctx.Done()
 }
@@ -0,0 +1,180 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// agentJobPollInterval is how often an event stream checks for new events
+// of a job running on the worker.
+const agentJobPollInterval = time.Second
+
+type agentJobRequest struct {
+	Prompt         string `json:"prompt"`
+	ConversationID string `json:"conversation_id"`
+}
+
+type agentJobListResponse struct {
+	Jobs []domain.AgentJob `json:"jobs"`
+}
+
+// agentJobEventEntry is one SSE event of a job stream.
+type agentJobEventEntry struct {
+	Object string `json:"object"`
+	domain.AgentJobEvent
+}
+
+// agentJobDoneEntry ends a job event stream with the final job.
+type agentJobDoneEntry struct {
+	Object string           `json:"object"`
+	Job    *domain.AgentJob `json:"job"`
+}
+
+// handleSubmitAgentJob queues an agent run on the worker and answers 202
+// with the job; its result is read by polling or from the event stream.
+// POST /v1/agent/jobs {"prompt":"...","conversation_id":"c1"}
+func (rt *Router) handleSubmitAgentJob(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentJobSvc != nil, "agent job service") {
+		return
+	}
+	var req agentJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	job, err := rt.agentJobSvc.Submit(r.Context(), domain.AgentJobRequest{
+		UserID:         requestUserID(r),
+		ConversationID: req.ConversationID,
+		Prompt:         req.Prompt,
+		Source:         domain.AgentJobSourceAPI,
+	})
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleListAgentJobs lists the caller's recent jobs, newest first.
+// GET /v1/agent/jobs?limit=20
+func (rt *Router) handleListAgentJobs(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentJobSvc != nil, "agent job service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 200)
+	if !ok {
+		return
+	}
+	jobs, err := rt.agentJobSvc.List(r.Context(), requestUserID(r), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, agentJobListResponse{Jobs: jobs})
+}
+
+// handleGetAgentJob returns the status of a job and, once it completed, its
+// answer.
+// GET /v1/agent/jobs/{id}
+func (rt *Router) handleGetAgentJob(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentJobSvc != nil, "agent job service") {
+		return
+	}
+	job, err := rt.agentJobSvc.Get(r.Context(), requestUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleCancelAgentJob cancels a job. A running job stops at its next
+// heartbeat, so the returned job may still be running.
+// POST /v1/agent/jobs/{id}/cancel
+func (rt *Router) handleCancelAgentJob(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentJobSvc != nil, "agent job service") {
+		return
+	}
+	job, err := rt.agentJobSvc.Cancel(r.Context(), requestUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleAgentJobEvents streams the events of a job as SSE, starting after
+// the sequence number in "after", and ends with an "agent.job.done" event
+// once the job finished. A client that lost the stream reconnects with the
+// last seq it saw.
+// GET /v1/agent/jobs/{id}/events?after=0
+func (rt *Router) handleAgentJobEvents(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentJobSvc != nil, "agent job service") {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by response writer"))
+		return
+	}
+	var after int64
+	if raw := r.URL.Query().Get("after"); raw != "" {
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || v < 0 {
+			writeError(w, http.StatusBadRequest, errors.New("after must be a non-negative integer"))
+			return
+		}
+		after = v
+	}
+	userID := requestUserID(r)
+	jobID := r.PathValue("id")
+	if _, err := rt.agentJobSvc.Get(r.Context(), userID, jobID); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeSSEHeaders(w)
+	ticker := time.NewTicker(agentJobPollInterval)
+	defer ticker.Stop()
+	for {
+		// Read the job before its events, so a finished job has its answer
+		// sent before the done event that carries its final status.
+		job, err := rt.agentJobSvc.Get(r.Context(), userID, jobID)
+		if err != nil {
+			_ = writeSSEData(w, flusher, buildErrorStreamChunk(err))
+			_ = writeSSEDone(w, flusher)
+			return
+		}
+		events, err := rt.agentJobSvc.Events(r.Context(), userID, jobID, after, 0)
+		if err != nil {
+			_ = writeSSEData(w, flusher, buildErrorStreamChunk(err))
+			_ = writeSSEDone(w, flusher)
+			return
+		}
+		for _, event := range events {
+			if err := writeSSEData(w, flusher, agentJobEventEntry{Object: "agent.job.event", AgentJobEvent: event}); err != nil {
+				return
+			}
+			after = event.Seq
+		}
+		if job.Finished() && len(events) == 0 {
+			_ = writeSSEData(w, flusher, agentJobDoneEntry{Object: "agent.job.done", Job: job})
+			_ = writeSSEDone(w, flusher)
+			return
+		}
+		if len(events) > 0 {
+			continue
+		}
+		select {
+		case <-ticker.C:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeAgentJobService struct {
+	submitted domain.AgentJobRequest
+	job       domain.AgentJob
+	events    []domain.AgentJobEvent
+	canceled  bool
+}
+
+func (f *fakeAgentJobService) Submit(_ context.Context, req domain.AgentJobRequest) (*domain.AgentJob, error) {
+	f.submitted = req
+	if strings.TrimSpace(req.Prompt) == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "submit agent job", errors.New("prompt is required"))
+	}
+	return &domain.AgentJob{ID: "j-1", UserID: req.UserID, Prompt: req.Prompt, Status: domain.AgentJobStatusQueued}, nil
+}
+
+func (f *fakeAgentJobService) List(_ context.Context, userID string, _ int) ([]domain.AgentJob, error) {
+	return []domain.AgentJob{{ID: "j-1", UserID: userID}}, nil
+}
+
+func (f *fakeAgentJobService) Get(_ context.Context, userID, jobID string) (*domain.AgentJob, error) {
+	if jobID != f.job.ID || userID != f.job.UserID {
+		return nil, domain.WrapError(domain.ErrAgentJobNotFound, "get agent job", errors.New(jobID))
+	}
+	job := f.job
+	return &job, nil
+}
+
+func (f *fakeAgentJobService) Events(ctx context.Context, userID, jobID string, afterSeq int64, _ int) ([]domain.AgentJobEvent, error) {
+	if _, err := f.Get(ctx, userID, jobID); err != nil {
+		return nil, err
+	}
+	var events []domain.AgentJobEvent
+	for _, event := range f.events {
+		if event.Seq > afterSeq {
+			events = append(events, event)
+		}
+	}
+	// The job finishes once its events were read.
+	f.job.Status = domain.AgentJobStatusCompleted
+	return events, nil
+}
+
+func (f *fakeAgentJobService) HasUnfinishedScheduleJob(context.Context, string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeAgentJobService) Cancel(ctx context.Context, userID, jobID string) (*domain.AgentJob, error) {
+	f.canceled = true
+	return f.Get(ctx, userID, jobID)
+}
+
+func TestAgentJobEndpoints(t *testing.T) {
+	svc := &fakeAgentJobService{job: domain.AgentJob{ID: "j-1", UserID: "alice", Status: domain.AgentJobStatusRunning}}
+	handler := newTestRouter(func(rt *Router) { rt.SetAgentJobService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodPost, "/v1/agent/jobs", `{"prompt":"research","conversation_id":"c-1"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("submit status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if svc.submitted.UserID != "alice" || svc.submitted.ConversationID != "c-1" || svc.submitted.Source != domain.AgentJobSourceAPI {
+		t.Fatalf("unexpected submit request: %+v", svc.submitted)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodPost, "/v1/agent/jobs", `{}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("empty prompt: status = %d, want 400", rec.Code)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodGet, "/v1/agent/jobs/j-1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("get status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(handler, "admin-key", http.MethodGet, "/v1/agent/jobs/j-1", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("another user's job: status = %d, want 404", rec.Code)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodPost, "/v1/agent/jobs/j-1/cancel", ""); rec.Code != http.StatusOK || !svc.canceled {
+		t.Fatalf("cancel status = %d, canceled = %v", rec.Code, svc.canceled)
+	}
+	if rec := doAs(newTestRouter(), "alice-key", http.MethodGet, "/v1/agent/jobs", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without agent jobs: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestAgentJobEventsStreamUntilDone(t *testing.T) {
+	svc := &fakeAgentJobService{
+		job: domain.AgentJob{ID: "j-1", UserID: "alice", Status: domain.AgentJobStatusRunning},
+		events: []domain.AgentJobEvent{
+			{Seq: 1, Type: domain.AgentJobEventStatus, Status: domain.AgentJobStatusRunning},
+			{Seq: 2, Type: domain.AgentJobEventTool, Tool: "knowledge_search", Status: "ok"},
+			{Seq: 3, Type: domain.AgentJobEventAnswer, Content: "done"},
+		},
+	}
+	handler := newTestRouter(func(rt *Router) { rt.SetAgentJobService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/agent/jobs/j-1/events?after=1", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var events []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	if len(events) != 4 || events[3] != "[DONE]" {
+		t.Fatalf("unexpected events: %q", events)
+	}
+	var first agentJobEventEntry
+	if err := json.Unmarshal([]byte(events[0]), &first); err != nil || first.Seq != 2 || first.Tool != "knowledge_search" {
+		t.Fatalf("unexpected first event %q: %v", events[0], err)
+	}
+	var done agentJobDoneEntry
+	if err := json.Unmarshal([]byte(events[2]), &done); err != nil || done.Object != "agent.job.done" || done.Job.Status != domain.AgentJobStatusCompleted {
+		t.Fatalf("unexpected final event %q: %v", events[2], err)
+	}
+
+	if rec := doAs(handler, "alice-key", http.MethodGet, "/v1/agent/jobs/j-1/events?after=-1", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("negative cursor: status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,82 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type agentRunListResponse struct {
+	Runs []domain.AgentRun `json:"runs"`
+}
+
+type agentRunReplayRequest struct {
+	Model        string `json:"model"`
+	SystemPrompt string `json:"system_prompt"`
+}
+
+// handleListAgentRuns lists the caller's recorded agent runs without their
+// steps, newest first.
+// GET /v1/agent/runs?conversation_id=c1&limit=20
+func (rt *Router) handleListAgentRuns(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentRunSvc != nil, "agent run service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 200)
+	if !ok {
+		return
+	}
+	runs, err := rt.agentRunSvc.List(r.Context(), requestUserID(r), r.URL.Query().Get("conversation_id"), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, agentRunListResponse{Runs: runs})
+}
+
+// handleGetAgentRun returns the full trace of a run: routing, system prompt,
+// every model call and every tool call.
+// GET /v1/agent/runs/{id}
+func (rt *Router) handleGetAgentRun(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentRunSvc != nil, "agent run service") {
+		return
+	}
+	run, err := rt.agentRunSvc.Get(r.Context(), requestUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// handleReplayAgentRun replays a run with tool results taken from its trace
+// and answers with the trace of the replay. Model takes the model ids of
+// /v1/models; names of no configured provider are rejected with 400.
+// POST /v1/agent/runs/{id}/replay {"model":"paa-openrouter","system_prompt":"..."}
+func (rt *Router) handleReplayAgentRun(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.agentRunSvc != nil, "agent run service") {
+		return
+	}
+	var req agentRunReplayRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+	model := strings.TrimSpace(req.Model)
+	if provider, ok := rt.modelProviderMap[model]; ok {
+		model = provider
+	}
+	run, err := rt.agentRunSvc.Replay(r.Context(), requestUserID(r), r.PathValue("id"), domain.AgentReplayOptions{
+		Model:        model,
+		SystemPrompt: req.SystemPrompt,
+	})
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, run)
+}
@@ -0,0 +1,77 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeAgentRunService struct {
+	run      domain.AgentRun
+	listConv string
+	replayed domain.AgentReplayOptions
+}
+
+func (f *fakeAgentRunService) List(_ context.Context, userID, conversationID string, _ int) ([]domain.AgentRun, error) {
+	f.listConv = conversationID
+	return []domain.AgentRun{{ID: f.run.ID, UserID: userID}}, nil
+}
+
+func (f *fakeAgentRunService) Get(_ context.Context, userID, runID string) (*domain.AgentRun, error) {
+	if runID != f.run.ID || userID != f.run.UserID {
+		return nil, domain.WrapError(domain.ErrAgentRunNotFound, "get agent run", errors.New(runID))
+	}
+	run := f.run
+	return &run, nil
+}
+
+func (f *fakeAgentRunService) Replay(ctx context.Context, userID, runID string, opts domain.AgentReplayOptions) (*domain.AgentRun, error) {
+	if _, err := f.Get(ctx, userID, runID); err != nil {
+		return nil, err
+	}
+	f.replayed = opts
+	return &domain.AgentRun{ID: "r-2", UserID: userID, ReplayOf: runID, Model: opts.Model}, nil
+}
+
+func TestAgentRunEndpoints(t *testing.T) {
+	svc := &fakeAgentRunService{run: domain.AgentRun{ID: "r-1", UserID: "alice", Steps: []domain.AgentRunStep{{Seq: 1, Kind: domain.AgentRunStepLLM}}}}
+	handler := newTestRouter(func(rt *Router) {
+		rt.SetAgentRunService(svc)
+		rt.modelProviderMap = map[string]string{"paa-openrouter": "openrouter"}
+	})
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/agent/runs?conversation_id=c-1", "")
+	if rec.Code != http.StatusOK || svc.listConv != "c-1" {
+		t.Fatalf("list status = %d, conversation = %q", rec.Code, svc.listConv)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodGet, "/v1/agent/runs?limit=0", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("limit=0: status = %d, want 400", rec.Code)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/agent/runs/r-1", "")
+	var run domain.AgentRun
+	if rec.Code != http.StatusOK || json.Unmarshal(rec.Body.Bytes(), &run) != nil || len(run.Steps) != 1 {
+		t.Fatalf("get status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(handler, "admin-key", http.MethodGet, "/v1/agent/runs/r-1", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("another user's run: status = %d, want 404", rec.Code)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodPost, "/v1/agent/runs/r-1/replay", `{"model":"paa-openrouter","system_prompt":"be brief"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("replay status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if svc.replayed.Model != "openrouter" || svc.replayed.SystemPrompt != "be brief" {
+		t.Fatalf("unexpected replay options: %+v", svc.replayed)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodPost, "/v1/agent/runs/r-1/replay", ""); rec.Code != http.StatusCreated {
+		t.Fatalf("replay without body: status = %d, want 201", rec.Code)
+	}
+	if rec := doAs(newTestRouter(), "alice-key", http.MethodGet, "/v1/agent/runs", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without agent runs: status = %d, want 503", rec.Code)
+	}
+}
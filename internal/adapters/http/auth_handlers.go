@@ -0,0 +1,188 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type createUserRequest struct {
+	Name   string   `json:"name"`
+	Admin  bool     `json:"admin"`
+	Groups []string `json:"groups"`
+}
+
+type setUserGroupsRequest struct {
+	Groups []string `json:"groups"`
+}
+
+type createAPIKeyRequest struct {
+	Name   string `json:"name"`
+	UserID string `json:"user_id"`
+}
+
+type createAPIKeyResponse struct {
+	domain.APIKey
+	// Key is the plaintext API key. It is returned only once.
+	Key string `json:"key"`
+}
+
+type apiKeyListResponse struct {
+	Keys []domain.APIKey `json:"keys"`
+}
+
+type userListResponse struct {
+	Users []domain.User `json:"users"`
+}
+
+func (rt *Router) handleGetMe(w http.ResponseWriter, r *http.Request) {
+	principal, ok := rt.requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	user, err := rt.authSvc.GetUser(r.Context(), principal.UserID)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (rt *Router) handleListUsers(w http.ResponseWriter, r *http.Request) {
+	if _, ok := rt.requirePrincipal(w, r); !ok {
+		return
+	}
+	users, err := rt.authSvc.ListUsers(r.Context())
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, userListResponse{Users: users})
+}
+
+func (rt *Router) handleCreateUser(w http.ResponseWriter, r *http.Request) {
+	if _, ok := rt.requirePrincipal(w, r); !ok {
+		return
+	}
+	var req createUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	user, err := rt.authSvc.CreateUser(r.Context(), req.Name, req.Admin, req.Groups)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, user)
+}
+
+func (rt *Router) handleSetUserGroups(w http.ResponseWriter, r *http.Request) {
+	if _, ok := rt.requirePrincipal(w, r); !ok {
+		return
+	}
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, errors.New("id is required"))
+		return
+	}
+	var req setUserGroupsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	user, err := rt.authSvc.SetUserGroups(r.Context(), id, req.Groups)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
+
+// handleListAPIKeys lists the caller's keys; admins may pass ?user_id=.
+func (rt *Router) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	principal, ok := rt.requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	userID := targetUserID(principal, r.URL.Query().Get("user_id"))
+	keys, err := rt.authSvc.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, apiKeyListResponse{Keys: keys})
+}
+
+func (rt *Router) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := rt.requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	var req createAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	key, token, err := rt.authSvc.CreateAPIKey(r.Context(), targetUserID(principal, req.UserID), req.Name)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, createAPIKeyResponse{APIKey: *key, Key: token})
+}
+
+func (rt *Router) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	principal, ok := rt.requirePrincipal(w, r)
+	if !ok {
+		return
+	}
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, errors.New("id is required"))
+		return
+	}
+	userID := targetUserID(principal, r.URL.Query().Get("user_id"))
+	if err := rt.authSvc.RevokeAPIKey(r.Context(), userID, id); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requirePrincipal returns the authenticated caller, writing 503 when auth is
+// not configured and 401 when the request carries no principal.
+func (rt *Router) requirePrincipal(w http.ResponseWriter, r *http.Request) (domain.Principal, bool) {
+	if rt.authSvc == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("auth not enabled"))
+		return domain.Principal{}, false
+	}
+	principal, ok := domain.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+		return domain.Principal{}, false
+	}
+	return principal, true
+}
+
+// requireAdminRequest rejects non-admin callers of deployment-wide endpoints.
+// Without auth every caller is allowed, as before.
+func requireAdminRequest(w http.ResponseWriter, r *http.Request) bool {
+	if p, ok := domain.PrincipalFromContext(r.Context()); ok && !p.Admin {
+		writeError(w, http.StatusForbidden, errors.New("admin privileges required"))
+		return false
+	}
+	return true
+}
+
+// targetUserID picks the user a key-management call acts on, defaulting to
+// the caller. Access to other users is checked by the auth service.
+func targetUserID(principal domain.Principal, requested string) string {
+	if requested = strings.TrimSpace(requested); requested != "" {
+		return requested
+	}
+	return principal.UserID
+}
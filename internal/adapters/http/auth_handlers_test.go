@@ -0,0 +1,288 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/config"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/usecase"
+)
+
+type fakeAuthService struct {
+	principals    map[string]domain.Principal // by token
+	createUserErr error
+	keyUserID     string
+}
+
+func (f *fakeAuthService) Authenticate(_ context.Context, token string) (*domain.Principal, error) {
+	p, ok := f.principals[token]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrUnauthorized, "authenticate", errors.New("invalid api key"))
+	}
+	return &p, nil
+}
+
+func (f *fakeAuthService) CreateUser(_ context.Context, name string, admin bool, groups []string) (*domain.User, error) {
+	if f.createUserErr != nil {
+		return nil, f.createUserErr
+	}
+	return &domain.User{ID: "u-new", Name: name, Admin: admin, Groups: groups}, nil
+}
+
+func (f *fakeAuthService) SetUserGroups(_ context.Context, id string, groups []string) (*domain.User, error) {
+	return &domain.User{ID: id, Name: id, Groups: groups}, nil
+}
+
+func (f *fakeAuthService) ListUsers(context.Context) ([]domain.User, error) {
+	return []domain.User{}, nil
+}
+
+func (f *fakeAuthService) GetUser(_ context.Context, id string) (*domain.User, error) {
+	return &domain.User{ID: id, Name: id}, nil
+}
+
+func (f *fakeAuthService) CreateAPIKey(_ context.Context, userID, name string) (*domain.APIKey, string, error) {
+	f.keyUserID = userID
+	return &domain.APIKey{ID: "k-1", UserID: userID, Name: name, Prefix: "paa_abcdefgh"}, "paa_abcdefgh-secret", nil
+}
+
+func (f *fakeAuthService) ListAPIKeys(context.Context, string) ([]domain.APIKey, error) {
+	return []domain.APIKey{}, nil
+}
+
+func (f *fakeAuthService) RevokeAPIKey(context.Context, string, string) error { return nil }
+
+func newAuthFake() *fakeAuthService {
+	return &fakeAuthService{principals: map[string]domain.Principal{
+		"alice-key": {UserID: "alice", KeyID: "k-alice"},
+		"admin-key": {UserID: "root", KeyID: "k-root", Admin: true},
+	}}
+}
+
+func newAuthRouter(auth *fakeAuthService, ss *fakeScheduleStore) *Router {
+	rt := NewRouter(config.Config{RAGTopK: 5}, nil, nil, fakeDocumentRepo{}, nil, nil)
+	rt.SetAuthService(auth)
+	if ss != nil {
+		rt.SetScheduleStore(ss)
+	}
+	return rt
+}
+
+func TestAuthMiddlewareRejectsMissingAndUnknownKeys(t *testing.T) {
+	handler := newAuthRouter(newAuthFake(), &fakeScheduleStore{}).Handler()
+
+	for _, header := range []string{"", "Bearer wrong-key"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/schedules", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("Authorization %q: expected 401, got %d", header, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("healthz must stay public, got %d", rec.Code)
+	}
+}
+
+func TestAuthMiddlewareIgnoresUserIDHeader(t *testing.T) {
+	ss := &fakeScheduleStore{}
+	handler := newAuthRouter(newAuthFake(), ss).Handler()
+
+	body, _ := json.Marshal(map[string]string{"cron_expr": "0 9 * * *", "prompt": "standup"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/schedules", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	req.Header.Set("X-User-ID", "bob")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if len(ss.created) != 1 || ss.created[0].UserID != "alice" {
+		t.Fatalf("expected schedule owned by alice, got %+v", ss.created)
+	}
+}
+
+func TestScheduleOfAnotherUserIsNotFound(t *testing.T) {
+	ss := &fakeScheduleStore{tasks: []domain.ScheduledTask{
+		{ID: "s1", UserID: "bob", CronExpr: "0 9 * * *", Prompt: "bob's"},
+	}}
+	handler := newAuthRouter(newAuthFake(), ss).Handler()
+
+	for _, method := range []string{http.MethodPatch, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/v1/schedules/s1", bytes.NewReader([]byte(`{"prompt":"hijack"}`)))
+		req.Header.Set("Authorization", "Bearer alice-key")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected 404, got %d", method, rec.Code)
+		}
+	}
+	if len(ss.tasks) != 1 || ss.tasks[0].Prompt != "bob's" {
+		t.Fatalf("schedule must be untouched, got %+v", ss.tasks)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/schedules/s1", nil)
+	req.Header.Set("Authorization", "Bearer admin-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("admin delete: expected 204, got %d", rec.Code)
+	}
+}
+
+func TestCreateAPIKeyDefaultsToCaller(t *testing.T) {
+	auth := newAuthFake()
+	handler := newAuthRouter(auth, nil).Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/api-keys", bytes.NewReader([]byte(`{"name":"laptop"}`)))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if auth.keyUserID != "alice" {
+		t.Fatalf("expected key for alice, got %q", auth.keyUserID)
+	}
+	var resp createAPIKeyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Key == "" || resp.ID != "k-1" {
+		t.Fatalf("expected plaintext key and id in response, got %+v", resp)
+	}
+}
+
+func TestCreateUserMapsForbidden(t *testing.T) {
+	auth := newAuthFake()
+	auth.createUserErr = domain.WrapError(domain.ErrForbidden, "create user", errors.New("admin privileges required"))
+	handler := newAuthRouter(auth, nil).Handler()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/users", bytes.NewReader([]byte(`{"name":"mallory"}`)))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestAdminOnlyEndpointsRejectRegularUsers(t *testing.T) {
+	rt := newAuthRouter(newAuthFake(), nil)
+	rt.SetRuntimeModelConfig(&fakeRuntimeModelConfig{})
+	handler := rt.Handler()
+
+	req := httptest.NewRequest(http.MethodPut, "/v1/settings/models", bytes.NewReader([]byte(`{}`)))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
+
+func TestVaultAndSystemEndpointsRejectRegularUsers(t *testing.T) {
+	handler := newAuthRouter(newAuthFake(), nil).Handler()
+
+	for _, tc := range []struct{ method, target string }{
+		{http.MethodPost, "/v1/obsidian/vaults/work/sync"},
+		{http.MethodPost, "/v1/obsidian/vaults/work/notes"},
+		{http.MethodGet, "/v1/events/summary"},
+		{http.MethodGet, "/v1/improvements"},
+		{http.MethodPatch, "/v1/improvements/i-1"},
+	} {
+		req := httptest.NewRequest(tc.method, tc.target, bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Authorization", "Bearer alice-key")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: expected 403, got %d", tc.method, tc.target, rec.Code)
+		}
+	}
+}
+
+func TestAgentUsesPrincipalInsteadOfMetadataUserID(t *testing.T) {
+	agent := &fakeAgentService{}
+	queryUC := usecase.NewQueryUseCase(fakeEmbedder{}, fakeVectorStore{}, fakeAnswerGenerator{}, usecase.QueryOptions{})
+	rt := NewRouter(config.Config{OpenAICompatModelID: "paa-rag-v1", AgentModeEnabled: true, RAGTopK: 5}, nil, queryUC, fakeDocumentRepo{}, agent, nil)
+	rt.SetAuthService(newAuthFake())
+	handler := rt.Handler()
+
+	body, _ := json.Marshal(map[string]any{
+		"model":    "paa-rag-v1",
+		"messages": []map[string]any{{"role": "user", "content": "what are my tasks?"}},
+		"metadata": map[string]any{"user_id": "bob"},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if !agent.called || agent.lastReq.UserID != "alice" {
+		t.Fatalf("expected agent call as alice, got called=%v user=%q", agent.called, agent.lastReq.UserID)
+	}
+}
+
+type fakeDocumentACLService struct {
+	visibility domain.DocumentVisibility
+	sharedWith []string
+	err        error
+}
+
+func (f *fakeDocumentACLService) SetACL(_ context.Context, id string, visibility domain.DocumentVisibility, sharedWith []string) (*domain.Document, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.visibility, f.sharedWith = visibility, sharedWith
+	return &domain.Document{ID: id, Visibility: visibility, SharedWith: sharedWith}, nil
+}
+
+func TestPutDocumentACL(t *testing.T) {
+	acl := &fakeDocumentACLService{}
+	rt := newAuthRouter(newAuthFake(), nil)
+	rt.SetDocumentACLService(acl)
+	handler := rt.Handler()
+
+	body := []byte(`{"visibility":"shared","shared_with":["group:team"]}`)
+	req := httptest.NewRequest(http.MethodPut, "/v1/documents/doc-1/acl", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if acl.visibility != domain.VisibilityShared || len(acl.sharedWith) != 1 {
+		t.Fatalf("unexpected ACL passed to service: %q %v", acl.visibility, acl.sharedWith)
+	}
+
+	acl.err = domain.WrapError(domain.ErrForbidden, "set document acl", errors.New("not owner"))
+	req = httptest.NewRequest(http.MethodPut, "/v1/documents/doc-1/acl", bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
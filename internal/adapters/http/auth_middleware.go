@@ -0,0 +1,67 @@
+package httpadapter
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// authMiddleware authenticates /v1/* and /mcp requests with per-user API keys
+// and stores the resulting principal in the request context. It is a no-op
+// until an AuthService is configured.
+func (rt *Router) authMiddleware(next http.Handler) http.Handler {
+	if rt.authSvc == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requiresAuth(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, ok := bearerToken(r.Header.Get("Authorization"))
+		if !ok {
+			writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		principal, err := rt.authSvc.Authenticate(r.Context(), token)
+		if err != nil {
+			if domain.IsKind(err, domain.ErrUnauthorized) {
+				writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+				return
+			}
+			slog.Error("auth_failed", "request_id", requestIDFromContext(r.Context()), "error", err)
+			writeError(w, mapErrorToHTTPStatus(err), errors.New("authentication unavailable"))
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(domain.ContextWithPrincipal(r.Context(), *principal)))
+	})
+}
+
+func requiresAuth(path string) bool {
+	return strings.HasPrefix(path, "/v1/") || path == "/mcp" || strings.HasPrefix(path, "/mcp/")
+}
+
+// requestUserID returns the caller's user id: the authenticated principal
+// when auth is enabled, otherwise the legacy X-User-ID header.
+func requestUserID(r *http.Request) string {
+	if p, ok := domain.PrincipalFromContext(r.Context()); ok {
+		return p.UserID
+	}
+	return r.Header.Get("X-User-ID")
+}
+
+// canAccessOwned reports whether the caller may touch a record owned by
+// ownerID. Without auth every record is reachable, as before.
+func canAccessOwned(r *http.Request, ownerID string) bool {
+	p, ok := domain.PrincipalFromContext(r.Context())
+	return !ok || p.CanAccess(ownerID)
+}
+
+// canReadDocument reports whether the caller may read doc under its ACL.
+func canReadDocument(r *http.Request, doc domain.Document) bool {
+	p, ok := domain.PrincipalFromContext(r.Context())
+	return !ok || p.CanRead(doc)
+}
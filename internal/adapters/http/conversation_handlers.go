@@ -0,0 +1,161 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type conversationListResponse struct {
+	Conversations []domain.Conversation `json:"conversations"`
+}
+
+type conversationSearchResponse struct {
+	Messages []domain.ConversationMessage `json:"messages"`
+}
+
+type renameConversationRequest struct {
+	Title string `json:"title"`
+}
+
+// handleListConversations lists the caller's conversations, most recently
+// updated first.
+// GET /v1/conversations?limit=50&offset=0
+func (rt *Router) handleListConversations(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 50, 1, 200)
+	if !ok {
+		return
+	}
+	offset, ok := queryIntParam(w, r, "offset", 0, 0, 1<<30)
+	if !ok {
+		return
+	}
+	conversations, err := rt.conversationSvc.List(r.Context(), requestUserID(r), limit, offset)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, conversationListResponse{Conversations: conversations})
+}
+
+// handleSearchConversations finds user and assistant messages containing q.
+// GET /v1/conversations/search?q=budget&limit=20
+func (rt *Router) handleSearchConversations(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 100)
+	if !ok {
+		return
+	}
+	messages, err := rt.conversationSvc.Search(r.Context(), requestUserID(r), r.URL.Query().Get("q"), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, conversationSearchResponse{Messages: messages})
+}
+
+func (rt *Router) handleGetConversation(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	transcript, err := rt.conversationSvc.Get(r.Context(), requestUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, transcript)
+}
+
+func (rt *Router) handleRenameConversation(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	var req renameConversationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	conv, err := rt.conversationSvc.Rename(r.Context(), requestUserID(r), r.PathValue("id"), req.Title)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, conv)
+}
+
+func (rt *Router) handleDeleteConversation(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	if err := rt.conversationSvc.Delete(r.Context(), requestUserID(r), r.PathValue("id")); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleExportConversation downloads a transcript as Markdown (default) or JSON.
+// GET /v1/conversations/{id}/export?format=markdown|json
+func (rt *Router) handleExportConversation(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.conversationSvc != nil, "conversation service") {
+		return
+	}
+	id := r.PathValue("id")
+	format := domain.ConversationExportFormat(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))))
+	if format == "" {
+		format = domain.ConversationExportMarkdown
+	}
+	body, err := rt.conversationSvc.Export(r.Context(), requestUserID(r), id, format)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+
+	contentType, ext := "text/markdown; charset=utf-8", "md"
+	if format == domain.ConversationExportJSON {
+		contentType, ext = "application/json", "json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(id)+"."+ext))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// queryIntParam parses an optional integer query parameter, writing 400 when
+// it is malformed or outside [lo, hi].
+func queryIntParam(w http.ResponseWriter, r *http.Request, name string, def, lo, hi int) (int, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, true
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < lo || v > hi {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%s must be between %d and %d", name, lo, hi))
+		return 0, false
+	}
+	return v, true
+}
+
+// exportFileName keeps only filename-safe characters of a conversation id.
+func exportFileName(id string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, id)
+	if name == "" {
+		return "conversation"
+	}
+	return "conversation-" + name
+}
@@ -0,0 +1,144 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/config"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeConversationService struct {
+	userID    string
+	renamed   string
+	deleted   string
+	exportFmt domain.ConversationExportFormat
+}
+
+func (f *fakeConversationService) owns(userID, id string) error {
+	f.userID = userID
+	if userID != "alice" || id != "c1" {
+		return domain.WrapError(domain.ErrConversationNotFound, "conversation", errors.New("id="+id))
+	}
+	return nil
+}
+
+func (f *fakeConversationService) List(_ context.Context, userID string, _, _ int) ([]domain.Conversation, error) {
+	f.userID = userID
+	return []domain.Conversation{{UserID: userID, ConversationID: "c1"}}, nil
+}
+
+func (f *fakeConversationService) Get(_ context.Context, userID, id string) (*domain.ConversationTranscript, error) {
+	if err := f.owns(userID, id); err != nil {
+		return nil, err
+	}
+	return &domain.ConversationTranscript{Conversation: domain.Conversation{UserID: userID, ConversationID: id}}, nil
+}
+
+func (f *fakeConversationService) Search(_ context.Context, userID, query string, _ int) ([]domain.ConversationMessage, error) {
+	f.userID = userID
+	if strings.TrimSpace(query) == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "search conversations", errors.New("query is required"))
+	}
+	return []domain.ConversationMessage{}, nil
+}
+
+func (f *fakeConversationService) Rename(_ context.Context, userID, id, title string) (*domain.Conversation, error) {
+	if err := f.owns(userID, id); err != nil {
+		return nil, err
+	}
+	f.renamed = title
+	return &domain.Conversation{UserID: userID, ConversationID: id, Title: title}, nil
+}
+
+func (f *fakeConversationService) Delete(_ context.Context, userID, id string) error {
+	if err := f.owns(userID, id); err != nil {
+		return err
+	}
+	f.deleted = id
+	return nil
+}
+
+func (f *fakeConversationService) Export(_ context.Context, userID, id string, format domain.ConversationExportFormat) ([]byte, error) {
+	if err := f.owns(userID, id); err != nil {
+		return nil, err
+	}
+	f.exportFmt = format
+	return []byte("# c1\n"), nil
+}
+
+func newConversationRouter(svc *fakeConversationService) http.Handler {
+	rt := NewRouter(config.Config{RAGTopK: 5}, nil, nil, fakeDocumentRepo{}, nil, nil)
+	rt.SetAuthService(newAuthFake())
+	if svc != nil {
+		rt.SetConversationService(svc)
+	}
+	return rt.Handler()
+}
+
+func TestConversationEndpointsScopeToCaller(t *testing.T) {
+	svc := &fakeConversationService{}
+	handler := newConversationRouter(svc)
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/conversations", "")
+	if rec.Code != http.StatusOK || svc.userID != "alice" {
+		t.Fatalf("list: expected 200 for alice, got %d user=%q", rec.Code, svc.userID)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodPatch, "/v1/conversations/c1", `{"title":"Trip"}`)
+	if rec.Code != http.StatusOK || svc.renamed != "Trip" {
+		t.Fatalf("rename: expected 200, got %d renamed=%q", rec.Code, svc.renamed)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodDelete, "/v1/conversations/other", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("delete foreign: expected 404, got %d", rec.Code)
+	}
+	rec = doAs(handler, "alice-key", http.MethodDelete, "/v1/conversations/c1", "")
+	if rec.Code != http.StatusNoContent || svc.deleted != "c1" {
+		t.Fatalf("delete: expected 204, got %d deleted=%q", rec.Code, svc.deleted)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/conversations/search?q=", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("search without query: expected 400, got %d", rec.Code)
+	}
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/conversations?limit=0", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("bad limit: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestExportConversationSetsDownloadHeaders(t *testing.T) {
+	svc := &fakeConversationService{}
+	handler := newConversationRouter(svc)
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/conversations/c1/export", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if svc.exportFmt != domain.ConversationExportMarkdown {
+		t.Fatalf("expected markdown by default, got %q", svc.exportFmt)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); cd != `attachment; filename="conversation-c1.md"` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/conversations/c1/export?format=json", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("json export: got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+}
+
+func TestConversationEndpointsWithoutServiceReturn503(t *testing.T) {
+	rec := doAs(newConversationRouter(nil), "alice-key", http.MethodGet, "/v1/conversations", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
@@ -12,8 +12,17 @@ func mapErrorToHTTPStatus(err error) int {
 		return http.StatusBadRequest
 	case domain.IsKind(err, domain.ErrUnauthorized):
 		return http.StatusUnauthorized
-	case domain.IsKind(err, domain.ErrDocumentNotFound):
+	case domain.IsKind(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case domain.IsKind(err, domain.ErrDocumentNotFound), domain.IsKind(err, domain.ErrVaultNotFound),
+		domain.IsKind(err, domain.ErrUserNotFound), domain.IsKind(err, domain.ErrAPIKeyNotFound),
+		domain.IsKind(err, domain.ErrConversationNotFound), domain.IsKind(err, domain.ErrMemoryFactNotFound),
+		domain.IsKind(err, domain.ErrEvalCaseNotFound), domain.IsKind(err, domain.ErrEvalRunNotFound),
+		domain.IsKind(err, domain.ErrIngestJobNotFound), domain.IsKind(err, domain.ErrOrchestrationNotFound),
+		domain.IsKind(err, domain.ErrAgentJobNotFound), domain.IsKind(err, domain.ErrAgentRunNotFound):
 		return http.StatusNotFound
+	case domain.IsKind(err, domain.ErrConflict):
+		return http.StatusConflict
 	case domain.IsKind(err, domain.ErrTemporary):
 		return http.StatusServiceUnavailable
 	default:
@@ -0,0 +1,176 @@
+package httpadapter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// evalCasesMaxBody bounds a case upload; it fits the largest accepted batch.
+const evalCasesMaxBody = 8 << 20
+
+type evalCaseListResponse struct {
+	Cases []domain.EvalCase `json:"cases"`
+}
+
+type evalRunListResponse struct {
+	Runs []domain.EvalRun `json:"runs"`
+}
+
+func (rt *Router) handleListEvalCases(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	cases, err := rt.evalSvc.ListCases(r.Context())
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, evalCaseListResponse{Cases: cases})
+}
+
+// handleSaveEvalCases creates or replaces cases. The body is a single case,
+// a JSON array of cases or JSONL in the format of scripts/eval/*.jsonl.
+// POST /v1/eval/cases
+func (rt *Router) handleSaveEvalCases(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, evalCasesMaxBody))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cases, err := decodeEvalCases(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	saved, err := rt.evalSvc.SaveCases(r.Context(), cases)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, evalCaseListResponse{Cases: saved})
+}
+
+func (rt *Router) handleDeleteEvalCase(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	if err := rt.evalSvc.DeleteCase(r.Context(), r.PathValue("id")); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleStartEvalRun runs the stored cases once per config and answers when
+// all runs are finished.
+// POST /v1/eval/runs {"configs":[{"mode":"hybrid"}],"k":5,"baseline_run_id":"...","max_drop":0.02}
+func (rt *Router) handleStartEvalRun(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	var req domain.EvalRunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	runs, err := rt.evalSvc.Run(r.Context(), req)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, evalRunListResponse{Runs: runs})
+}
+
+// handleListEvalRuns lists recent runs without their per-case results.
+// GET /v1/eval/runs?limit=20
+func (rt *Router) handleListEvalRuns(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 200)
+	if !ok {
+		return
+	}
+	runs, err := rt.evalSvc.ListRuns(r.Context(), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, evalRunListResponse{Runs: runs})
+}
+
+func (rt *Router) handleGetEvalRun(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	run, err := rt.evalSvc.GetRun(r.Context(), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, run)
+}
+
+// handleDiffEvalRuns compares run {id} against the run given in base.
+// GET /v1/eval/runs/{id}/diff?base=<run id>
+func (rt *Router) handleDiffEvalRuns(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.evalSvc != nil, "eval service") {
+		return
+	}
+	diff, err := rt.evalSvc.Diff(r.Context(), r.URL.Query().Get("base"), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
+
+// decodeEvalCases accepts a JSON array, a single JSON object or JSONL where
+// blank lines and lines starting with # are skipped.
+func decodeEvalCases(body []byte) ([]domain.EvalCase, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 {
+		return nil, errors.New("request body is empty")
+	}
+	if trimmed[0] == '[' {
+		var cases []domain.EvalCase
+		if err := json.Unmarshal(trimmed, &cases); err != nil {
+			return nil, err
+		}
+		return cases, nil
+	}
+	var single domain.EvalCase
+	if err := json.Unmarshal(trimmed, &single); err == nil {
+		return []domain.EvalCase{single}, nil
+	}
+
+	var cases []domain.EvalCase
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 0, 64*1024), evalCasesMaxBody)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var c domain.EvalCase
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
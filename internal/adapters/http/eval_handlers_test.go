@@ -0,0 +1,104 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeEvalService struct {
+	saved      []domain.EvalCase
+	runReq     domain.EvalRunRequest
+	base, head string
+}
+
+func (f *fakeEvalService) ListCases(context.Context) ([]domain.EvalCase, error) {
+	return f.saved, nil
+}
+
+func (f *fakeEvalService) SaveCases(_ context.Context, cases []domain.EvalCase) ([]domain.EvalCase, error) {
+	f.saved = cases
+	return cases, nil
+}
+
+func (f *fakeEvalService) DeleteCase(context.Context, string) error { return nil }
+
+func (f *fakeEvalService) Run(_ context.Context, req domain.EvalRunRequest) ([]domain.EvalRun, error) {
+	f.runReq = req
+	return []domain.EvalRun{{ID: "run-1", Status: domain.EvalRunStatusOK}}, nil
+}
+
+func (f *fakeEvalService) ListRuns(context.Context, int) ([]domain.EvalRun, error) {
+	return []domain.EvalRun{}, nil
+}
+
+func (f *fakeEvalService) GetRun(context.Context, string) (*domain.EvalRun, error) {
+	return &domain.EvalRun{ID: "run-1"}, nil
+}
+
+func (f *fakeEvalService) Diff(_ context.Context, baseID, headID string) (*domain.EvalRunDiff, error) {
+	f.base, f.head = baseID, headID
+	return &domain.EvalRunDiff{BaseRunID: baseID, HeadRunID: headID}, nil
+}
+
+func TestSaveEvalCasesAcceptsJSONL(t *testing.T) {
+	svc := &fakeEvalService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetEvalService(svc) })
+
+	body := "# comment line\n" +
+		`{"id":"EX001","question":"q1","expected_filenames":["a.md"],"ground_truth":"gt"}` + "\n\n" +
+		`{"id":"EX002","question":"q2","expected_document_ids":["doc-2"]}` + "\n"
+	rec := doAs(handler, "admin-key", http.MethodPost, "/v1/eval/cases", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if len(svc.saved) != 2 || svc.saved[0].GroundTruth != "gt" || svc.saved[1].ExpectedDocumentIDs[0] != "doc-2" {
+		t.Fatalf("unexpected cases: %+v", svc.saved)
+	}
+
+	rec = doAs(handler, "admin-key", http.MethodPost, "/v1/eval/cases", "{\n  \"question\": \"q3\",\n  \"expected_filenames\": [\"c.md\"]\n}")
+	if rec.Code != http.StatusOK || len(svc.saved) != 1 || svc.saved[0].Question != "q3" {
+		t.Fatalf("single object: got %d %+v", rec.Code, svc.saved)
+	}
+
+	rec = doAs(handler, "admin-key", http.MethodPost, "/v1/eval/cases", "{\"question\":\"q\"}\nnot json\n")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("malformed line: expected 400, got %d", rec.Code)
+	}
+}
+
+func TestEvalRunsEndpoints(t *testing.T) {
+	svc := &fakeEvalService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetEvalService(svc) })
+
+	rec := doAs(handler, "admin-key", http.MethodPost, "/v1/eval/runs", `{"configs":[{"mode":"hybrid+rerank","rerank_top_n":10}],"k":3}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if svc.runReq.K != 3 || svc.runReq.Configs[0].Mode != domain.RetrievalModeHybridRerank || svc.runReq.Configs[0].RerankTopN != 10 {
+		t.Fatalf("unexpected run request: %+v", svc.runReq)
+	}
+
+	rec = doAs(handler, "admin-key", http.MethodPost, "/v1/eval/runs", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("empty body must run the default config, got %d", rec.Code)
+	}
+
+	rec = doAs(handler, "admin-key", http.MethodGet, "/v1/eval/runs/run-2/diff?base=run-1", "")
+	if rec.Code != http.StatusOK || svc.base != "run-1" || svc.head != "run-2" {
+		t.Fatalf("diff: got %d base=%q head=%q", rec.Code, svc.base, svc.head)
+	}
+}
+
+func TestEvalEndpointsRequireAdmin(t *testing.T) {
+	rec := doAs(newTestRouter(func(rt *Router) { rt.SetEvalService(&fakeEvalService{}) }), "alice-key", http.MethodGet, "/v1/eval/cases", "")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a regular user, got %d", rec.Code)
+	}
+	rec = doAs(newTestRouter(), "admin-key", http.MethodGet, "/v1/eval/runs", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without eval service, got %d", rec.Code)
+	}
+}
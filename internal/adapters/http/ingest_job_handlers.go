@@ -0,0 +1,95 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type ingestJobListResponse struct {
+	Jobs []domain.IngestJob `json:"jobs"`
+}
+
+type ingestDirectoryRequest struct {
+	Path string `json:"path"`
+}
+
+// handleIngestArchive starts a job that expands an uploaded ZIP or TAR into
+// documents and answers 202 with the job.
+// POST /v1/ingest/archives (multipart field "file")
+func (rt *Router) handleIngestArchive(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.bulkIngestSvc != nil, "bulk ingest service") {
+		return
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	part, err := getMultipartFilePart(reader, "file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	defer func() { _ = part.Close() }()
+
+	job, err := rt.bulkIngestSvc.StartArchive(r.Context(), part.FileName(), part)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleIngestDirectory starts a job that ingests a folder mounted on the
+// server, below the configured import root.
+// POST /v1/ingest/directories {"path":"team-wiki"}
+func (rt *Router) handleIngestDirectory(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) || !requireService(w, rt.bulkIngestSvc != nil, "bulk ingest service") {
+		return
+	}
+	var req ingestDirectoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	job, err := rt.bulkIngestSvc.StartDirectory(r.Context(), req.Path)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// handleListIngestJobs lists recent jobs without their files.
+// GET /v1/ingest/jobs?limit=20
+func (rt *Router) handleListIngestJobs(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.bulkIngestSvc != nil, "bulk ingest service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 200)
+	if !ok {
+		return
+	}
+	jobs, err := rt.bulkIngestSvc.ListJobs(r.Context(), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ingestJobListResponse{Jobs: jobs})
+}
+
+// handleGetIngestJob returns a job with the outcome of every processed file.
+// GET /v1/ingest/jobs/{id}?file_status=failed
+func (rt *Router) handleGetIngestJob(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.bulkIngestSvc != nil, "bulk ingest service") {
+		return
+	}
+	job, err := rt.bulkIngestSvc.GetJob(r.Context(), r.PathValue("id"), r.URL.Query().Get("file_status"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,130 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeBulkIngestService struct {
+	filename   string
+	body       string
+	dir        string
+	fileStatus string
+}
+
+func (f *fakeBulkIngestService) StartArchive(_ context.Context, filename string, body io.Reader) (*domain.IngestJob, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	f.filename, f.body = filename, string(data)
+	return &domain.IngestJob{ID: "job-1", Kind: domain.IngestJobArchive, Source: filename, Status: domain.IngestJobStatusQueued}, nil
+}
+
+func (f *fakeBulkIngestService) StartDirectory(_ context.Context, path string) (*domain.IngestJob, error) {
+	if path == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start directory", errors.New("path is required"))
+	}
+	f.dir = path
+	return &domain.IngestJob{ID: "job-2", Kind: domain.IngestJobDirectory, Source: path, Status: domain.IngestJobStatusQueued}, nil
+}
+
+func (f *fakeBulkIngestService) GetJob(_ context.Context, id, fileStatus string) (*domain.IngestJob, error) {
+	if id != "job-1" {
+		return nil, domain.ErrIngestJobNotFound
+	}
+	f.fileStatus = fileStatus
+	return &domain.IngestJob{ID: id, Files: []domain.IngestJobFile{{Path: "a/b.md", Status: domain.IngestFileFailed}}}, nil
+}
+
+func (f *fakeBulkIngestService) ListJobs(context.Context, int) ([]domain.IngestJob, error) {
+	return []domain.IngestJob{{ID: "job-1"}}, nil
+}
+
+func TestIngestArchiveStartsJob(t *testing.T) {
+	svc := &fakeBulkIngestService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetBulkIngestService(svc) })
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "notes.zip")
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	if _, err := part.Write([]byte("PK-archive")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/ingest/archives", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer alice-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if svc.filename != "notes.zip" || svc.body != "PK-archive" {
+		t.Fatalf("unexpected upload: %q %q", svc.filename, svc.body)
+	}
+	var job domain.IngestJob
+	if err := json.NewDecoder(rec.Body).Decode(&job); err != nil || job.ID != "job-1" {
+		t.Fatalf("unexpected response: %+v (%v)", job, err)
+	}
+}
+
+func TestIngestDirectoryRequiresAdmin(t *testing.T) {
+	svc := &fakeBulkIngestService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetBulkIngestService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodPost, "/v1/ingest/directories", `{"path":"wiki"}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for a regular user, got %d", rec.Code)
+	}
+	rec = doAs(handler, "admin-key", http.MethodPost, "/v1/ingest/directories", `{"path":"wiki"}`)
+	if rec.Code != http.StatusAccepted || svc.dir != "wiki" {
+		t.Fatalf("expected 202 for admin, got %d dir=%q", rec.Code, svc.dir)
+	}
+	rec = doAs(handler, "admin-key", http.MethodPost, "/v1/ingest/directories", `{}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty path, got %d", rec.Code)
+	}
+}
+
+func TestIngestJobEndpoints(t *testing.T) {
+	svc := &fakeBulkIngestService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetBulkIngestService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/ingest/jobs/job-1?file_status=failed", "")
+	if rec.Code != http.StatusOK || svc.fileStatus != "failed" {
+		t.Fatalf("get job: got %d file_status=%q", rec.Code, svc.fileStatus)
+	}
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/ingest/jobs/missing", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for an unknown job, got %d", rec.Code)
+	}
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/ingest/jobs?limit=500", "")
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an out-of-range limit, got %d", rec.Code)
+	}
+	rec = doAs(handler, "alice-key", http.MethodGet, "/v1/ingest/jobs", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list jobs: got %d", rec.Code)
+	}
+	rec = doAs(newTestRouter(), "alice-key", http.MethodGet, "/v1/ingest/jobs", "")
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without bulk ingest service, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,79 @@
+package httpadapter
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type memoryFactListResponse struct {
+	Facts []domain.MemoryFact `json:"facts"`
+}
+
+type memoryFactRequest struct {
+	Content string `json:"content"`
+}
+
+func (rt *Router) handleListMemoryFacts(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.memoryFactSvc != nil, "memory facts") {
+		return
+	}
+	facts, err := rt.memoryFactSvc.List(r.Context(), requestUserID(r))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, memoryFactListResponse{Facts: facts})
+}
+
+// handleRememberFact stores a fact. It answers 201 for a new fact and 200
+// when an existing fact was kept (duplicate) or overwritten (replaced).
+func (rt *Router) handleRememberFact(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.memoryFactSvc != nil, "memory facts") {
+		return
+	}
+	var req memoryFactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	res, err := rt.memoryFactSvc.Remember(r.Context(), requestUserID(r), req.Content, domain.MemoryFactSourceUser)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	status := http.StatusOK
+	if res.Action == domain.MemoryFactCreated {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, res)
+}
+
+func (rt *Router) handleUpdateMemoryFact(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.memoryFactSvc != nil, "memory facts") {
+		return
+	}
+	var req memoryFactRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	fact, err := rt.memoryFactSvc.Update(r.Context(), requestUserID(r), r.PathValue("id"), req.Content)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, fact)
+}
+
+func (rt *Router) handleForgetMemoryFact(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.memoryFactSvc != nil, "memory facts") {
+		return
+	}
+	if err := rt.memoryFactSvc.Forget(r.Context(), requestUserID(r), r.PathValue("id")); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeMemoryFactService struct {
+	userID  string
+	source  domain.MemoryFactSource
+	action  domain.MemoryFactAction
+	forgot  string
+	updated string
+}
+
+func (f *fakeMemoryFactService) List(_ context.Context, userID string) ([]domain.MemoryFact, error) {
+	f.userID = userID
+	return []domain.MemoryFact{{ID: "f-1", UserID: userID, Content: "likes tea"}}, nil
+}
+
+func (f *fakeMemoryFactService) Remember(_ context.Context, userID, content string, source domain.MemoryFactSource) (*domain.MemoryFactWrite, error) {
+	f.userID, f.source = userID, source
+	return &domain.MemoryFactWrite{Fact: domain.MemoryFact{ID: "f-2", UserID: userID, Content: content}, Action: f.action}, nil
+}
+
+func (f *fakeMemoryFactService) Update(_ context.Context, userID, id, content string) (*domain.MemoryFact, error) {
+	if id != "f-1" {
+		return nil, domain.WrapError(domain.ErrMemoryFactNotFound, "update memory fact", errors.New("id="+id))
+	}
+	f.updated = content
+	return &domain.MemoryFact{ID: id, UserID: userID, Content: content}, nil
+}
+
+func (f *fakeMemoryFactService) Forget(_ context.Context, _, id string) error {
+	if id != "f-1" {
+		return domain.WrapError(domain.ErrMemoryFactNotFound, "forget memory fact", errors.New("id="+id))
+	}
+	f.forgot = id
+	return nil
+}
+
+func TestRememberFactStatusReflectsAction(t *testing.T) {
+	svc := &fakeMemoryFactService{action: domain.MemoryFactCreated}
+	handler := newTestRouter(func(rt *Router) { rt.SetMemoryFactService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodPost, "/v1/memories", `{"content":"staging DB is db-2"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if svc.userID != "alice" || svc.source != domain.MemoryFactSourceUser {
+		t.Fatalf("unexpected caller/source: %q %q", svc.userID, svc.source)
+	}
+
+	svc.action = domain.MemoryFactReplaced
+	rec = doAs(handler, "alice-key", http.MethodPost, "/v1/memories", `{"content":"staging DB is db-3"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for replaced fact, got %d", rec.Code)
+	}
+	var res domain.MemoryFactWrite
+	if err := json.NewDecoder(rec.Body).Decode(&res); err != nil || res.Action != domain.MemoryFactReplaced {
+		t.Fatalf("unexpected response %+v (err %v)", res, err)
+	}
+}
+
+func TestMemoryFactEndpoints(t *testing.T) {
+	svc := &fakeMemoryFactService{}
+	handler := newTestRouter(func(rt *Router) { rt.SetMemoryFactService(svc) })
+
+	if rec := doAs(handler, "alice-key", http.MethodGet, "/v1/memories", ""); rec.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", rec.Code)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodPatch, "/v1/memories/f-1", `{"content":"likes coffee"}`); rec.Code != http.StatusOK || svc.updated != "likes coffee" {
+		t.Fatalf("update: expected 200, got %d", rec.Code)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodDelete, "/v1/memories/f-9", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("forget unknown: expected 404, got %d", rec.Code)
+	}
+	if rec := doAs(handler, "alice-key", http.MethodDelete, "/v1/memories/f-1", ""); rec.Code != http.StatusNoContent || svc.forgot != "f-1" {
+		t.Fatalf("forget: expected 204, got %d", rec.Code)
+	}
+}
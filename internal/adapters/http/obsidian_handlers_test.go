@@ -1,6 +1,7 @@
 package httpadapter
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -8,91 +9,60 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/usecase"
 )
 
-// newObsidianRouter creates a Router configured with temp directories for
-// obsidian config, state, and vaults root. Callers can register routes on the
-// returned http.ServeMux via the Router's handler methods.
-func newObsidianRouter(t *testing.T) (*Router, *http.ServeMux) {
-	t.Helper()
-	tmpDir := t.TempDir()
-	configPath := filepath.Join(tmpDir, "obsidian.json")
-	stateDir := filepath.Join(tmpDir, "state")
-	vaultsRoot := filepath.Join(tmpDir, "vaults")
+// obsidianTestEnv bundles the vaults root and in-memory store behind the
+// router's vault sync service so tests can seed and inspect vault state.
+type obsidianTestEnv struct {
+	root  string
+	store *memoryVaultStore
+}
 
-	if err := os.MkdirAll(stateDir, 0o755); err != nil {
-		t.Fatal(err)
+func (e *obsidianTestEnv) seed(vaults ...domain.Vault) {
+	for _, v := range vaults {
+		e.store.vaults[v.ID] = v
 	}
+}
+
+// newObsidianRouter creates a Router backed by a real vault sync usecase over
+// an in-memory store and a temp vaults root.
+func newObsidianRouter(t *testing.T) (*obsidianTestEnv, *http.ServeMux) {
+	t.Helper()
+	vaultsRoot := filepath.Join(t.TempDir(), "vaults")
 	if err := os.MkdirAll(vaultsRoot, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
+	store := newMemoryVaultStore()
+	svc := usecase.NewVaultSyncUseCase(store, store, ingestSuccessFake{}, docsErrFake{}, &fakeDocumentDeleter{}, nil, usecase.VaultSyncOptions{
+		VaultsRoot:             vaultsRoot,
+		DefaultIntervalMinutes: 15,
+	})
 	rt := &Router{
-		obsidianConfigPath:             configPath,
-		obsidianStateDir:               stateDir,
-		obsidianVaultsRoot:             vaultsRoot,
 		obsidianDefaultIntervalMinutes: 15,
 		ingestor:                       ingestSuccessFake{},
 		docs:                           docsErrFake{},
 	}
+	rt.SetVaultSyncService(svc)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("GET /v1/obsidian/vaults", rt.handleObsidianList)
 	mux.HandleFunc("POST /v1/obsidian/vaults", rt.handleObsidianUpsert)
 	mux.HandleFunc("DELETE /v1/obsidian/vaults/{id}", rt.handleObsidianRemove)
+	mux.HandleFunc("POST /v1/obsidian/vaults/{id}/sync", rt.handleObsidianSync)
+	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/sync-runs", rt.handleObsidianSyncRuns)
 	mux.HandleFunc("POST /v1/obsidian/vaults/{id}/notes", rt.handleObsidianCreateNote)
 	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/files", rt.handleObsidianListFiles)
 	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/files/content", rt.handleObsidianFileContent)
 
-	return rt, mux
-}
-
-// writeObsidianConfigFile writes an obsidianConfig JSON to the router's config path.
-func writeObsidianConfigFile(t *testing.T, rt *Router, cfg obsidianConfig) {
-	t.Helper()
-	data, err := json.MarshalIndent(cfg, "", "  ")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if err := os.MkdirAll(filepath.Dir(rt.obsidianConfigPath), 0o755); err != nil {
-		t.Fatal(err)
-	}
-	if err := os.WriteFile(rt.obsidianConfigPath, data, 0o644); err != nil {
-		t.Fatal(err)
-	}
+	return &obsidianTestEnv{root: vaultsRoot, store: store}, mux
 }
 
 // --- Unit tests for helper functions ---
 
-func TestSanitizeFilename(t *testing.T) {
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{"simple", "simple"},
-		{"Hello World", "Hello World"},
-		{"file/name", "file_name"},
-		{"a\\b", "a_b"},
-		{"co:lon", "co_lon"},
-		{"star*", "star_"},
-		{"q?mark", "q_mark"},
-		{`"quoted"`, `_quoted_`},
-		{"a<b>c", "a_b_c"},
-		{"pipe|line", "pipe_line"},
-		{"  spaces  ", "spaces"},
-		{"", ""},
-		{strings.Repeat("a", 250), strings.Repeat("a", 200)},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := sanitizeFilename(tt.input)
-			if got != tt.want {
-				t.Errorf("sanitizeFilename(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
 func TestSafePath(t *testing.T) {
 	root := "/vault/root"
 	tests := []struct {
@@ -128,32 +98,6 @@ func TestSafePath(t *testing.T) {
 	}
 }
 
-func TestSlugifyObsidian(t *testing.T) {
-	tests := []struct {
-		input string
-		want  string
-	}{
-		{"My Vault", "my_vault"},
-		{"simple", "simple"},
-		{"UPPER", "upper"},
-		{"  spaces  ", "spaces"},
-		{"special!@#$chars", "special_chars"},
-		{"a.b-c_d", "a.b-c_d"},
-		{"", "vault"},
-		{"   ", "vault"},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := slugifyObsidian(tt.input)
-			if got != tt.want {
-				t.Errorf("slugifyObsidian(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
-	}
-}
-
-// --- Handler tests ---
-
 func TestHandleObsidianList_Empty(t *testing.T) {
 	_, mux := newObsidianRouter(t)
 
@@ -178,18 +122,13 @@ func TestHandleObsidianList_Empty(t *testing.T) {
 }
 
 func TestHandleObsidianList_WithVaults(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultPath := filepath.Join(rt.obsidianVaultsRoot, "testvault")
+	vaultPath := filepath.Join(env.root, "testvault")
 	if err := os.MkdirAll(vaultPath, 0o755); err != nil {
 		t.Fatal(err)
 	}
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "testvault", Name: "Test Vault", Path: vaultPath, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "testvault", Name: "Test Vault", Path: vaultPath, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults", nil)
 	rec := httptest.NewRecorder()
@@ -215,10 +154,10 @@ func TestHandleObsidianList_WithVaults(t *testing.T) {
 }
 
 func TestHandleObsidianUpsert_CreateNew(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
 	// Create a vault directory so resolveVaultPath succeeds.
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "newvault")
+	vaultDir := filepath.Join(env.root, "newvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
@@ -241,16 +180,12 @@ func TestHandleObsidianUpsert_CreateNew(t *testing.T) {
 		t.Errorf("expected status=ok, got %v", resp["status"])
 	}
 
-	// Verify the config file was created.
-	cfg, err := rt.loadObsidianConfig()
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(cfg.Vaults) != 1 {
-		t.Fatalf("expected 1 vault in config, got %d", len(cfg.Vaults))
+	vault, ok := env.store.vaults["new_vault"]
+	if !ok {
+		t.Fatalf("expected vault persisted, got %+v", env.store.vaults)
 	}
-	if cfg.Vaults[0].Name != "New Vault" {
-		t.Errorf("expected vault name 'New Vault', got %q", cfg.Vaults[0].Name)
+	if vault.Name != "New Vault" || vault.Path != vaultDir {
+		t.Errorf("unexpected stored vault: %+v", vault)
 	}
 }
 
@@ -292,14 +227,9 @@ func TestHandleObsidianUpsert_MissingPath(t *testing.T) {
 }
 
 func TestHandleObsidianRemove_Success(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "myvault", Name: "My Vault", Path: "/tmp/v", Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "myvault", Name: "My Vault", Path: "/tmp/v", Enabled: true})
 
 	req := httptest.NewRequest(http.MethodDelete, "/v1/obsidian/vaults/myvault", nil)
 	rec := httptest.NewRecorder()
@@ -320,24 +250,13 @@ func TestHandleObsidianRemove_Success(t *testing.T) {
 		t.Errorf("expected removed=myvault, got %v", resp["removed"])
 	}
 
-	// Verify vault was removed from config.
-	cfg, err := rt.loadObsidianConfig()
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(cfg.Vaults) != 0 {
-		t.Errorf("expected 0 vaults after removal, got %d", len(cfg.Vaults))
+	if len(env.store.vaults) != 0 {
+		t.Errorf("expected 0 vaults after removal, got %d", len(env.store.vaults))
 	}
 }
 
 func TestHandleObsidianRemove_NotFound(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
-
-	// Empty config, no vaults.
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults:                 []obsidianVault{},
-		DefaultIntervalMinutes: 15,
-	})
+	_, mux := newObsidianRouter(t)
 
 	req := httptest.NewRequest(http.MethodDelete, "/v1/obsidian/vaults/nonexistent", nil)
 	rec := httptest.NewRecorder()
@@ -349,19 +268,14 @@ func TestHandleObsidianRemove_NotFound(t *testing.T) {
 }
 
 func TestHandleObsidianCreateNote_Success(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "notevault")
+	vaultDir := filepath.Join(env.root, "notevault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "notevault", Name: "Note Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "notevault", Name: "Note Vault", Path: vaultDir, Enabled: true})
 
 	body := `{"title":"Test Note","content":"Hello world"}`
 	req := httptest.NewRequest(http.MethodPost, "/v1/obsidian/vaults/notevault/notes", strings.NewReader(body))
@@ -396,19 +310,14 @@ func TestHandleObsidianCreateNote_Success(t *testing.T) {
 }
 
 func TestHandleObsidianCreateNote_WithFolder(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "foldervault")
+	vaultDir := filepath.Join(env.root, "foldervault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "foldervault", Name: "Folder Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "foldervault", Name: "Folder Vault", Path: vaultDir, Enabled: true})
 
 	body := `{"title":"Sub Note","content":"In subfolder","folder":"sub/dir"}`
 	req := httptest.NewRequest(http.MethodPost, "/v1/obsidian/vaults/foldervault/notes", strings.NewReader(body))
@@ -454,12 +363,7 @@ func TestHandleObsidianCreateNote_MissingFields(t *testing.T) {
 }
 
 func TestHandleObsidianCreateNote_VaultNotFound(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
-
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults:                 []obsidianVault{},
-		DefaultIntervalMinutes: 15,
-	})
+	_, mux := newObsidianRouter(t)
 
 	body := `{"title":"Note","content":"body"}`
 	req := httptest.NewRequest(http.MethodPost, "/v1/obsidian/vaults/nope/notes", strings.NewReader(body))
@@ -473,9 +377,9 @@ func TestHandleObsidianCreateNote_VaultNotFound(t *testing.T) {
 }
 
 func TestHandleObsidianListFiles(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "listvault")
+	vaultDir := filepath.Join(env.root, "listvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
@@ -494,12 +398,7 @@ func TestHandleObsidianListFiles(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "listvault", Name: "List Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "listvault", Name: "List Vault", Path: vaultDir, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/listvault/files", nil)
 	rec := httptest.NewRecorder()
@@ -532,12 +431,7 @@ func TestHandleObsidianListFiles(t *testing.T) {
 }
 
 func TestHandleObsidianListFiles_VaultNotFound(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
-
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults:                 []obsidianVault{},
-		DefaultIntervalMinutes: 15,
-	})
+	_, mux := newObsidianRouter(t)
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/nope/files", nil)
 	rec := httptest.NewRecorder()
@@ -549,9 +443,9 @@ func TestHandleObsidianListFiles_VaultNotFound(t *testing.T) {
 }
 
 func TestHandleObsidianFileContent(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "contentvault")
+	vaultDir := filepath.Join(env.root, "contentvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
@@ -560,12 +454,7 @@ func TestHandleObsidianFileContent(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "contentvault", Name: "Content Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "contentvault", Name: "Content Vault", Path: vaultDir, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/contentvault/files/content?path=note.md", nil)
 	rec := httptest.NewRecorder()
@@ -588,19 +477,14 @@ func TestHandleObsidianFileContent(t *testing.T) {
 }
 
 func TestHandleObsidianFileContent_PathTraversal(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "secvault")
+	vaultDir := filepath.Join(env.root, "secvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "secvault", Name: "Sec Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "secvault", Name: "Sec Vault", Path: vaultDir, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/secvault/files/content?path=../../../etc/passwd", nil)
 	rec := httptest.NewRecorder()
@@ -612,19 +496,14 @@ func TestHandleObsidianFileContent_PathTraversal(t *testing.T) {
 }
 
 func TestHandleObsidianFileContent_MissingPath(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "mpvault")
+	vaultDir := filepath.Join(env.root, "mpvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "mpvault", Name: "MP Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "mpvault", Name: "MP Vault", Path: vaultDir, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/mpvault/files/content", nil)
 	rec := httptest.NewRecorder()
@@ -636,19 +515,14 @@ func TestHandleObsidianFileContent_MissingPath(t *testing.T) {
 }
 
 func TestHandleObsidianFileContent_FileNotFound(t *testing.T) {
-	rt, mux := newObsidianRouter(t)
+	env, mux := newObsidianRouter(t)
 
-	vaultDir := filepath.Join(rt.obsidianVaultsRoot, "fnfvault")
+	vaultDir := filepath.Join(env.root, "fnfvault")
 	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
 		t.Fatal(err)
 	}
 
-	writeObsidianConfigFile(t, rt, obsidianConfig{
-		Vaults: []obsidianVault{
-			{ID: "fnfvault", Name: "FNF Vault", Path: vaultDir, Enabled: true},
-		},
-		DefaultIntervalMinutes: 15,
-	})
+	env.seed(domain.Vault{ID: "fnfvault", Name: "FNF Vault", Path: vaultDir, Enabled: true})
 
 	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/fnfvault/files/content?path=nonexistent.md", nil)
 	rec := httptest.NewRecorder()
@@ -678,3 +552,179 @@ func TestWriteJSON(t *testing.T) {
 		t.Errorf("expected key=value, got %q", resp["key"])
 	}
 }
+
+func TestHandleObsidianSync_RecordsRunHistory(t *testing.T) {
+	env, mux := newObsidianRouter(t)
+
+	vaultDir := filepath.Join(env.root, "syncvault")
+	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(vaultDir, "note.md"), []byte("# Note"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	env.seed(domain.Vault{ID: "syncvault", Name: "Sync Vault", Path: vaultDir, Enabled: true})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/obsidian/vaults/syncvault/sync", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var syncResp struct {
+		Results []obsidianSyncResult `json:"results"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&syncResp); err != nil {
+		t.Fatal(err)
+	}
+	if len(syncResp.Results) != 1 || syncResp.Results[0].Uploaded != 1 || syncResp.Results[0].RunID == "" {
+		t.Fatalf("unexpected sync results: %+v", syncResp.Results)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/syncvault/sync-runs?limit=5", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var runsResp struct {
+		Runs []domain.VaultSyncRun `json:"runs"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&runsResp); err != nil {
+		t.Fatal(err)
+	}
+	if len(runsResp.Runs) != 1 || runsResp.Runs[0].Trigger != domain.VaultSyncTriggerManual || runsResp.Runs[0].Status != domain.VaultSyncStatusOK {
+		t.Fatalf("unexpected runs: %+v", runsResp.Runs)
+	}
+}
+
+func TestHandleObsidianSync_LockedVaultReturnsConflict(t *testing.T) {
+	env, mux := newObsidianRouter(t)
+
+	vaultDir := filepath.Join(env.root, "busy")
+	if err := os.MkdirAll(vaultDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	env.seed(domain.Vault{ID: "busy", Name: "Busy", Path: vaultDir, Enabled: true})
+	env.store.locked["busy"] = true
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/obsidian/vaults/busy/sync", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleObsidianSyncRuns_NotConfigured(t *testing.T) {
+	rt := &Router{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/sync-runs", rt.handleObsidianSyncRuns)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/obsidian/vaults/x/sync-runs", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+// memoryVaultStore is an in-memory ports.VaultStore and
+// ports.VaultSyncStateStore used by the Obsidian handler tests.
+type memoryVaultStore struct {
+	vaults map[string]domain.Vault
+	states map[string]domain.VaultFileState
+	runs   []domain.VaultSyncRun
+	locked map[string]bool
+}
+
+func newMemoryVaultStore() *memoryVaultStore {
+	return &memoryVaultStore{
+		vaults: map[string]domain.Vault{},
+		states: map[string]domain.VaultFileState{},
+		locked: map[string]bool{},
+	}
+}
+
+func (m *memoryVaultStore) ListVaults(context.Context) ([]domain.Vault, error) {
+	out := make([]domain.Vault, 0, len(m.vaults))
+	for _, v := range m.vaults {
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (m *memoryVaultStore) GetVault(_ context.Context, id string) (*domain.Vault, error) {
+	v, ok := m.vaults[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrVaultNotFound, "get vault", os.ErrNotExist)
+	}
+	return &v, nil
+}
+
+func (m *memoryVaultStore) UpsertVault(_ context.Context, v *domain.Vault) error {
+	m.vaults[v.ID] = *v
+	return nil
+}
+
+func (m *memoryVaultStore) DeleteVault(_ context.Context, id string) error {
+	if _, ok := m.vaults[id]; !ok {
+		return domain.WrapError(domain.ErrVaultNotFound, "delete vault", os.ErrNotExist)
+	}
+	delete(m.vaults, id)
+	return nil
+}
+
+func (m *memoryVaultStore) ListFileStates(_ context.Context, vaultID string) ([]domain.VaultFileState, error) {
+	var out []domain.VaultFileState
+	for _, st := range m.states {
+		if st.VaultID == vaultID {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryVaultStore) UpsertFileStates(_ context.Context, states []domain.VaultFileState) error {
+	for _, st := range states {
+		m.states[st.VaultID+"/"+st.RelPath] = st
+	}
+	return nil
+}
+
+func (m *memoryVaultStore) DeleteFileStates(_ context.Context, vaultID string, relPaths []string) error {
+	for _, rel := range relPaths {
+		delete(m.states, vaultID+"/"+rel)
+	}
+	return nil
+}
+
+func (m *memoryVaultStore) TryLock(_ context.Context, vaultID string) (func(), bool, error) {
+	if m.locked[vaultID] {
+		return nil, false, nil
+	}
+	m.locked[vaultID] = true
+	return func() { delete(m.locked, vaultID) }, true, nil
+}
+
+func (m *memoryVaultStore) CreateSyncRun(_ context.Context, run *domain.VaultSyncRun) error {
+	run.ID = "run-" + run.VaultID
+	return nil
+}
+
+func (m *memoryVaultStore) FinishSyncRun(_ context.Context, run *domain.VaultSyncRun) error {
+	m.runs = append(m.runs, *run)
+	return nil
+}
+
+func (m *memoryVaultStore) ListSyncRuns(_ context.Context, vaultID string, _ int) ([]domain.VaultSyncRun, error) {
+	var out []domain.VaultSyncRun
+	for _, run := range m.runs {
+		if run.VaultID == vaultID {
+			out = append(out, run)
+		}
+	}
+	return out, nil
+}
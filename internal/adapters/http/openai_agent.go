@@ -19,7 +19,7 @@ func (rt *Router) tryAgentCompletion(
 	lastUser string,
 	stream bool,
 ) (apigen.ChatCompletionsResponseObject, bool, error) {
-	userID, conversationID, sessionEnd, ok := rt.agentMetadata(request.Body)
+	userID, conversationID, sessionEnd, ok := rt.agentMetadata(ctx, request.Body)
 	if !ok {
 		return nil, false, nil
 	}
@@ -49,31 +49,31 @@ func (rt *Router) tryAgentCompletion(
 	if stream {
 		agentReq.OnOrchStep = func(status domain.OrchestrationStatus) {
 			orchStepMu.Lock()
-			orchStepEvents = append(orchStepEvents, orchStepEntry{
-				Type:            "orchestration_step",
-				OrchestrationID: status.OrchestrationID,
-				StepIndex:       status.StepIndex,
-				AgentName:       status.AgentName,
-				Task:            status.Task,
-				Status:          status.Status,
-				Result:          status.Result,
-			})
+			orchStepEvents = append(orchStepEvents, newOrchStepEntry(status))
 			orchStepMu.Unlock()
 		}
 	}
 
-	// For streaming: set up real-time thinking delta via channel.
-	// The response visitor drains this channel and writes SSE events
-	// WHILE the agent is still running.
-	var thinkingCh chan string
+	// For streaming: set up real-time thinking and answer deltas via one
+	// channel so their order is preserved. The response visitor drains it
+	// and writes SSE events WHILE the agent is still running.
+	var deltaCh chan agentStreamDelta
 	if stream {
-		thinkingCh = make(chan string, 512)
+		deltaCh = make(chan agentStreamDelta, 512)
 		agentReq.OnThinkingDelta = func(text string) {
 			select {
-			case thinkingCh <- text:
+			case deltaCh <- agentStreamDelta{thinking: true, text: text}:
 			default:
 			}
 		}
+		// Answer tokens must not be dropped, so block until the visitor
+		// takes them or the request is gone.
+		agentReq.OnAnswerDelta = func(text string) {
+			select {
+			case deltaCh <- agentStreamDelta{text: text}:
+			case <-ctx.Done():
+			}
+		}
 	}
 
 	// Launch agent in background goroutine
@@ -81,8 +81,8 @@ func (rt *Router) tryAgentCompletion(
 
 	go func() {
 		defer func() {
-			if thinkingCh != nil {
-				close(thinkingCh)
+			if deltaCh != nil {
+				close(deltaCh)
 			}
 		}()
 		result, err := rt.agentSvc.Complete(ctx, agentReq, onToolStatus)
@@ -90,20 +90,20 @@ func (rt *Router) tryAgentCompletion(
 	}()
 
 	if stream {
-		// Return a streaming response that writes thinking tokens in real-time
+		// Return a streaming response that writes thinking and answer tokens in real-time
 		return &realtimeAgentSSEResponse{
-			rt:             rt,
-			thinkingCh:     thinkingCh,
-			resultCh:       resultCh,
-			toolStatusMu:   &toolStatusMu,
-			toolStatusPtr:  &toolStatusEvents,
-			orchStepMu:     &orchStepMu,
-			orchStepPtr:    &orchStepEvents,
-			completionID:   completionID,
-			created:        created,
-			modelID:        modelID,
-			lastUser:       lastUser,
-			userID:         userID,
+			rt:            rt,
+			deltaCh:       deltaCh,
+			resultCh:      resultCh,
+			toolStatusMu:  &toolStatusMu,
+			toolStatusPtr: &toolStatusEvents,
+			orchStepMu:    &orchStepMu,
+			orchStepPtr:   &orchStepEvents,
+			completionID:  completionID,
+			created:       created,
+			modelID:       modelID,
+			lastUser:      lastUser,
+			userID:        userID,
 		}, true, nil
 	}
 
@@ -171,17 +171,50 @@ func (rt *Router) buildAgentDebug(result *domain.AgentRunResult) *apigen.DebugIn
 		reason := result.FallbackReason
 		debug.FallbackReason = &reason
 	}
+	if result.OrchestrationID != "" {
+		orchID := result.OrchestrationID
+		steps := make([]apigen.OrchestrationStep, 0, len(result.OrchestrationSteps))
+		for _, step := range result.OrchestrationSteps {
+			steps = append(steps, apigen.OrchestrationStep{
+				Index:      step.Index,
+				StepId:     &step.StepID,
+				Agent:      step.Agent,
+				Task:       step.Task,
+				Status:     step.Status,
+				Result:     &step.Result,
+				Attempts:   &step.Attempts,
+				StartedAt:  &step.StartedAt,
+				DurationMs: &step.DurationMS,
+			})
+		}
+		debug.OrchestrationId = &orchID
+		debug.OrchestrationSteps = &steps
+	}
+	if result.RunID != "" {
+		runID := result.RunID
+		debug.RunId = &runID
+	}
 	return debug
 }
 
-func (rt *Router) agentMetadata(body *apigen.ChatCompletionsJSONRequestBody) (userID, conversationID string, sessionEnd bool, ok bool) {
-	if !rt.agentModeEnabled || rt.agentSvc == nil || body == nil || body.Metadata == nil {
+// agentMetadata resolves the agent identity of a chat request. An
+// authenticated principal always wins over metadata.user_id, which is only
+// trusted when auth is disabled.
+func (rt *Router) agentMetadata(ctx context.Context, body *apigen.ChatCompletionsJSONRequestBody) (userID, conversationID string, sessionEnd bool, ok bool) {
+	if !rt.agentModeEnabled || rt.agentSvc == nil || body == nil {
 		return "", "", false, false
 	}
-	userID = strings.TrimSpace(valueOrEmpty(body.Metadata.UserId))
+	if p, authenticated := domain.PrincipalFromContext(ctx); authenticated {
+		userID = p.UserID
+	} else if body.Metadata != nil {
+		userID = strings.TrimSpace(valueOrEmpty(body.Metadata.UserId))
+	}
 	if userID == "" {
 		return "", "", false, false
 	}
+	if body.Metadata == nil {
+		return userID, "", false, true
+	}
 	conversationID = strings.TrimSpace(valueOrEmpty(body.Metadata.ConversationId))
 	if body.Metadata.SessionEnd != nil {
 		sessionEnd = *body.Metadata.SessionEnd
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"sync"
 
+	apigen "github.com/kirillkom/personal-ai-assistant/internal/adapters/http/openapi"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 )
 
@@ -16,13 +17,20 @@ type agentResult struct {
 	err    error
 }
 
+// agentStreamDelta is a thinking or answer token produced while the agent runs.
+type agentStreamDelta struct {
+	thinking bool
+	text     string
+}
+
 // realtimeAgentSSEResponse writes SSE events in real-time as the agent executes.
-// Thinking tokens are streamed as they arrive from Ollama, before the agent finishes.
+// Thinking and final answer tokens are streamed as they arrive from the LLM,
+// before the agent finishes.
 type realtimeAgentSSEResponse struct {
 	rt *Router
 
-	thinkingCh chan string
-	resultCh   chan agentResult
+	deltaCh  chan agentStreamDelta
+	resultCh chan agentResult
 
 	toolStatusMu  *sync.Mutex
 	toolStatusPtr *[]toolStatusEntry
@@ -48,14 +56,23 @@ func (r *realtimeAgentSSEResponse) VisitChatCompletionsResponse(w http.ResponseW
 	w.Header().Set("Connection", "keep-alive")
 	w.WriteHeader(http.StatusOK)
 
-	// Phase 1: Stream thinking tokens in real-time as they arrive
-	for token := range r.thinkingCh {
+	// Phase 1: Stream thinking and answer tokens in real-time as they arrive
+	answerStarted := false
+	for delta := range r.deltaCh {
+		if !delta.thinking {
+			chunk := buildContentDeltaChunk(r.completionID, r.created, r.modelID, delta.text, !answerStarted)
+			answerStarted = true
+			if err := writeSSEData(w, flusher, chunk); err != nil {
+				return err
+			}
+			continue
+		}
 		chunk := map[string]any{
 			"id":     "thinking",
 			"object": "chat.completion.chunk",
 			"choices": []map[string]any{{
 				"index": 0,
-				"delta": map[string]any{"thinking_delta": token},
+				"delta": map[string]any{"thinking_delta": delta.text},
 			}},
 		}
 		data, _ := json.Marshal(chunk)
@@ -65,7 +82,7 @@ func (r *realtimeAgentSSEResponse) VisitChatCompletionsResponse(w http.ResponseW
 		flusher.Flush()
 	}
 
-	// Phase 2: thinkingCh closed means agent goroutine finished. Get result.
+	// Phase 2: deltaCh closed means agent goroutine finished. Get result.
 	ar := <-r.resultCh
 	if ar.err != nil {
 		errChunk := map[string]any{
@@ -130,8 +147,11 @@ func (r *realtimeAgentSSEResponse) VisitChatCompletionsResponse(w http.ResponseW
 		flusher.Flush()
 	}
 
-	// Phase 5: Emit content chunks
-	contentChunks := buildTextStreamChunks(r.completionID, r.created, r.modelID, result.Answer, r.rt.openAICompatStreamChunkChars)
+	// Phase 5: Emit content chunks. A streamed answer only needs the finish chunk.
+	contentChunks := []apigen.ChatCompletionChunk{buildStopChunk(r.completionID, r.created, r.modelID)}
+	if !result.AnswerStreamed {
+		contentChunks = buildTextStreamChunks(r.completionID, r.created, r.modelID, result.Answer, r.rt.openAICompatStreamChunkChars)
+	}
 	for _, chunk := range contentChunks {
 		payload, err := json.Marshal(chunk)
 		if err != nil {
@@ -10,7 +10,8 @@ import (
 
 func (rt *Router) openAICompatAuthMiddleware(f apigen.StrictHandlerFunc, operationID string) apigen.StrictHandlerFunc {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
-		if rt.openAICompatAPIKey == "" {
+		// Per-user API keys supersede the shared token once auth is enabled.
+		if rt.openAICompatAPIKey == "" || rt.authSvc != nil {
 			return f(ctx, w, r, request)
 		}
 		if !isOpenAICompatOperation(operationID) {
@@ -41,14 +42,20 @@ func isOpenAICompatOperation(operationID string) bool {
 }
 
 func isAuthorizedBearerHeader(headerValue, expectedToken string) bool {
-	headerValue = strings.TrimSpace(headerValue)
-	if headerValue == "" || expectedToken == "" {
+	if expectedToken == "" {
 		return false
 	}
+	token, ok := bearerToken(headerValue)
+	return ok && token == expectedToken
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" value.
+func bearerToken(headerValue string) (string, bool) {
+	headerValue = strings.TrimSpace(headerValue)
 	const bearerPrefix = "Bearer "
 	if !strings.HasPrefix(headerValue, bearerPrefix) {
-		return false
+		return "", false
 	}
 	token := strings.TrimSpace(strings.TrimPrefix(headerValue, bearerPrefix))
-	return token == expectedToken
+	return token, token != ""
 }
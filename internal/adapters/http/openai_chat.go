@@ -131,8 +131,15 @@ func (rt *Router) ChatCompletions(ctx context.Context, request apigen.ChatComple
 	}
 
 	ragQuestion := buildRAGQuestion(request.Body.Messages, lastUser, rt.openAICompatContextMessages)
+	if stream {
+		return rt.streamRAGCompletion(ctx, completionID, created, modelID, ragQuestion)
+	}
+	answerFn := rt.querySvc.Answer
+	if rt.ragCitationsEnabled {
+		answerFn = rt.querySvc.AnswerWithCitations
+	}
 	start := time.Now()
-	answer, err := rt.querySvc.Answer(ctx, ragQuestion, rt.ragTopK, domain.SearchFilter{})
+	answer, err := answerFn(ctx, ragQuestion, rt.ragTopK, domain.SearchFilter{})
 	if err != nil {
 		if mapErrorToHTTPStatus(err) == http.StatusServiceUnavailable {
 			return apigen.ChatCompletions503JSONResponse{Error: err.Error()}, nil
@@ -145,21 +152,34 @@ func (rt *Router) ChatCompletions(ctx context.Context, request apigen.ChatComple
 	if sources := toAPIDebugSources(answer.Sources); len(sources) > 0 {
 		debug.Sources = &sources
 	}
+	if answer.Sentences != nil {
+		citations := toAPICitations(answer.Citations)
+		sentences := toAPIAnswerSentences(answer.Sentences)
+		debug.Citations = &citations
+		debug.Sentences = &sentences
+	}
 
 	response := buildTextChatCompletionResponse(completionID, created, modelID, ragQuestion, answer.Text, debug)
+	rt.observeRAGCompletion(ctx, answer, response.Usage, modelID, start)
+	return apigen.ChatCompletions200JSONResponse(response), nil
+}
+
+// observeRAGCompletion records metrics and the retrieval log line for a
+// finished RAG chat completion.
+func (rt *Router) observeRAGCompletion(ctx context.Context, answer *domain.Answer, usage *apigen.Usage, modelID string, start time.Time) {
 	rt.httpMetrics.RecordRAGObservation("api", "chat_completions", len(answer.Sources), time.Since(start))
 	mode := string(answer.Retrieval.Mode)
 	if mode == "" {
 		mode = string(domain.RetrievalModeSemantic)
 	}
 	rt.httpMetrics.RecordRAGModeRequest("api", "chat_completions", mode)
-	if response.Usage != nil {
+	if usage != nil {
 		rt.httpMetrics.RecordTokenUsage(
 			"api",
 			"chat_completions",
 			modelID,
-			response.Usage.PromptTokens,
-			response.Usage.CompletionTokens,
+			usage.PromptTokens,
+			usage.CompletionTokens,
 		)
 	}
 	slog.Info("rag_retrieval",
@@ -172,8 +192,4 @@ func (rt *Router) ChatCompletions(ctx context.Context, request apigen.ChatComple
 		"retrieved_chunks", len(answer.Sources),
 		"duration_ms", float64(time.Since(start).Microseconds())/1000.0,
 	)
-	if stream {
-		return chatCompletionsSSEResponse{Chunks: buildTextStreamChunks(completionID, created, modelID, answer.Text, rt.openAICompatStreamChunkChars)}, nil
-	}
-	return apigen.ChatCompletions200JSONResponse(response), nil
 }
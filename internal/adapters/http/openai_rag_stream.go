@@ -0,0 +1,138 @@
+package httpadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	apigen "github.com/kirillkom/personal-ai-assistant/internal/adapters/http/openapi"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// ragStreamResult carries the outcome of a streamed RAG answer.
+type ragStreamResult struct {
+	answer *domain.Answer
+	err    error
+}
+
+// streamRAGCompletion starts answer generation in the background and returns
+// a response that forwards tokens as the generator produces them. It waits for
+// the first token so that retrieval and provider failures that happen before
+// any output still map to HTTP error statuses.
+func (rt *Router) streamRAGCompletion(
+	ctx context.Context,
+	completionID string,
+	created int64,
+	modelID string,
+	ragQuestion string,
+) (apigen.ChatCompletionsResponseObject, error) {
+	streamFn := rt.querySvc.StreamAnswer
+	if rt.ragCitationsEnabled {
+		streamFn = rt.querySvc.StreamAnswerWithCitations
+	}
+
+	start := time.Now()
+	deltaCh := make(chan string, 64)
+	resultCh := make(chan ragStreamResult, 1)
+	go func() {
+		defer close(deltaCh)
+		answer, err := streamFn(ctx, ragQuestion, rt.ragTopK, domain.SearchFilter{}, func(text string) {
+			select {
+			case deltaCh <- text:
+			case <-ctx.Done():
+			}
+		})
+		resultCh <- ragStreamResult{answer: answer, err: err}
+	}()
+
+	first, ok := <-deltaCh
+	if !ok {
+		res := <-resultCh
+		if res.err != nil {
+			if mapErrorToHTTPStatus(res.err) == http.StatusServiceUnavailable {
+				return apigen.ChatCompletions503JSONResponse{Error: res.err.Error()}, nil
+			}
+			return apigen.ChatCompletions500JSONResponse{Error: res.err.Error()}, nil
+		}
+		resultCh <- res
+	}
+
+	return &realtimeRAGSSEResponse{
+		rt:           rt,
+		ctx:          ctx,
+		first:        first,
+		hasFirst:     ok,
+		deltaCh:      deltaCh,
+		resultCh:     resultCh,
+		completionID: completionID,
+		created:      created,
+		modelID:      modelID,
+		ragQuestion:  ragQuestion,
+		start:        start,
+	}, nil
+}
+
+// realtimeRAGSSEResponse writes answer tokens as SSE chunks while the
+// generator is still running.
+type realtimeRAGSSEResponse struct {
+	rt  *Router
+	ctx context.Context
+
+	first    string
+	hasFirst bool
+	deltaCh  chan string
+	resultCh chan ragStreamResult
+
+	completionID string
+	created      int64
+	modelID      string
+	ragQuestion  string
+	start        time.Time
+}
+
+func (r *realtimeRAGSSEResponse) VisitChatCompletionsResponse(w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return fmt.Errorf("streaming is not supported by response writer")
+	}
+	writeSSEHeaders(w)
+
+	withRole := true
+	writeDelta := func(text string) error {
+		chunk := buildContentDeltaChunk(r.completionID, r.created, r.modelID, text, withRole)
+		withRole = false
+		return writeSSEData(w, flusher, chunk)
+	}
+
+	if r.hasFirst {
+		if err := writeDelta(r.first); err != nil {
+			return err
+		}
+	}
+	for text := range r.deltaCh {
+		if err := writeDelta(text); err != nil {
+			return err
+		}
+	}
+
+	res := <-r.resultCh
+	if res.err != nil {
+		_ = writeSSEData(w, flusher, buildErrorStreamChunk(res.err))
+		_ = writeSSEDone(w, flusher)
+		return nil
+	}
+	if withRole {
+		// The generator produced no deltas; send the answer in one piece.
+		if err := writeDelta(res.answer.Text); err != nil {
+			return err
+		}
+	}
+
+	r.rt.observeRAGCompletion(r.ctx, res.answer, estimateUsage(r.ragQuestion, res.answer.Text), r.modelID, r.start)
+
+	if err := writeSSEData(w, flusher, buildStopChunk(r.completionID, r.created, r.modelID)); err != nil {
+		return err
+	}
+	return writeSSEDone(w, flusher)
+}
@@ -9,6 +9,7 @@ import (
 	"unicode/utf8"
 
 	apigen "github.com/kirillkom/personal-ai-assistant/internal/adapters/http/openapi"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 )
 
 type toolStatusEntry struct {
@@ -20,6 +21,7 @@ type orchStepEntry struct {
 	Type            string  `json:"type"`
 	OrchestrationID string  `json:"orchestration_id"`
 	StepIndex       int     `json:"step_index"`
+	StepID          string  `json:"step_id,omitempty"`
 	AgentName       string  `json:"agent_name"`
 	Task            string  `json:"task"`
 	Status          string  `json:"status"`
@@ -27,6 +29,20 @@ type orchStepEntry struct {
 	DurationMS      float64 `json:"duration_ms,omitempty"`
 }
 
+func newOrchStepEntry(status domain.OrchestrationStatus) orchStepEntry {
+	return orchStepEntry{
+		Type:            "orchestration_step",
+		OrchestrationID: status.OrchestrationID,
+		StepIndex:       status.StepIndex,
+		StepID:          status.StepID,
+		AgentName:       status.AgentName,
+		Task:            status.Task,
+		Status:          status.Status,
+		Result:          status.Result,
+		DurationMS:      status.DurationMS,
+	}
+}
+
 type chatCompletionsSSEResponse struct {
 	Chunks []apigen.ChatCompletionChunk
 }
@@ -160,29 +176,40 @@ func buildTextStreamChunks(completionID string, created int64, modelID string, t
 	parts := splitByRunes(text, chunkChars)
 	chunks := make([]apigen.ChatCompletionChunk, 0, len(parts)+1)
 	for idx, part := range parts {
-		delta := apigen.ChatMessageDelta{}
-		if idx == 0 {
-			role := "assistant"
-			delta.Role = &role
-		}
-		if part != "" {
-			delta.Content = &part
-		}
-		chunks = append(chunks, apigen.ChatCompletionChunk{
-			Id:      completionID,
-			Object:  "chat.completion.chunk",
-			Created: created,
-			Model:   modelID,
-			Choices: []apigen.ChatCompletionChunkChoice{{
-				Index:        0,
-				Delta:        delta,
-				FinishReason: nil,
-			}},
-		})
+		chunks = append(chunks, buildContentDeltaChunk(completionID, created, modelID, part, idx == 0))
 	}
+	chunks = append(chunks, buildStopChunk(completionID, created, modelID))
+
+	return chunks
+}
+
+// buildContentDeltaChunk wraps one piece of answer text. The first chunk of a
+// completion carries the assistant role.
+func buildContentDeltaChunk(completionID string, created int64, modelID string, text string, withRole bool) apigen.ChatCompletionChunk {
+	delta := apigen.ChatMessageDelta{}
+	if withRole {
+		role := "assistant"
+		delta.Role = &role
+	}
+	if text != "" {
+		delta.Content = &text
+	}
+	return apigen.ChatCompletionChunk{
+		Id:      completionID,
+		Object:  "chat.completion.chunk",
+		Created: created,
+		Model:   modelID,
+		Choices: []apigen.ChatCompletionChunkChoice{{
+			Index:        0,
+			Delta:        delta,
+			FinishReason: nil,
+		}},
+	}
+}
 
+func buildStopChunk(completionID string, created int64, modelID string) apigen.ChatCompletionChunk {
 	finishReason := "stop"
-	chunks = append(chunks, apigen.ChatCompletionChunk{
+	return apigen.ChatCompletionChunk{
 		Id:      completionID,
 		Object:  "chat.completion.chunk",
 		Created: created,
@@ -192,9 +219,50 @@ func buildTextStreamChunks(completionID string, created int64, modelID string, t
 			Delta:        apigen.ChatMessageDelta{},
 			FinishReason: &finishReason,
 		}},
-	})
+	}
+}
 
-	return chunks
+// writeSSEHeaders starts an event stream response.
+func writeSSEHeaders(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+}
+
+// writeSSEData marshals v into a single "data:" event and flushes it.
+func writeSSEData(w io.Writer, flusher http.Flusher, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// writeSSEDone terminates an OpenAI-style event stream.
+func writeSSEDone(w io.Writer, flusher http.Flusher) error {
+	if _, err := io.WriteString(w, "data: [DONE]\n\n"); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// buildErrorStreamChunk reports a failure after the stream has started, when
+// an HTTP error status can no longer be sent.
+func buildErrorStreamChunk(err error) map[string]any {
+	return map[string]any{
+		"id":     "error",
+		"object": "chat.completion.chunk",
+		"choices": []map[string]any{{
+			"index": 0,
+			"delta": map[string]any{"content": "Error: " + err.Error()},
+		}},
+	}
 }
 
 func buildToolCallStreamChunks(completionID string, created int64, modelID string, toolCall apigen.ToolCall) []apigen.ChatCompletionChunk {
@@ -52,6 +52,19 @@ const (
 	ToolDefinitionTypeFunction ToolDefinitionType = "function"
 )
 
+// AnswerSentence defines model for AnswerSentence.
+type AnswerSentence struct {
+	End     int    `json:"end"`
+	Markers *[]int `json:"markers,omitempty"`
+
+	// Start Rune offset within the answer text.
+	Start int `json:"start"`
+
+	// Supported False when no marker resolves to a source backing the sentence.
+	Supported bool   `json:"supported"`
+	Text      string `json:"text"`
+}
+
 // ChatCompletionChoice defines model for ChatCompletionChoice.
 type ChatCompletionChoice struct {
 	FinishReason *string     `json:"finish_reason"`
@@ -127,16 +140,47 @@ type ChatRequestMetadata struct {
 	UserId         *string `json:"user_id,omitempty"`
 }
 
+// Citation defines model for Citation.
+type Citation struct {
+	ChunkIndex int    `json:"chunk_index"`
+	DocumentId string `json:"document_id"`
+	Filename   string `json:"filename"`
+
+	// Locator Page, sheet or slide of the source file the chunk was extracted from.
+	Locator *Locator `json:"locator,omitempty"`
+
+	// Marker Inline marker number; 1-based index into sources.
+	Marker int     `json:"marker"`
+	Path   *string `json:"path,omitempty"`
+
+	// Sentence Index of the answer sentence carrying the marker.
+	Sentence int `json:"sentence"`
+	SpanEnd  int `json:"span_end"`
+
+	// SpanStart Rune offset of the supporting passage within the chunk text.
+	SpanStart int     `json:"span_start"`
+	Title     *string `json:"title,omitempty"`
+}
+
 // DebugInfo defines model for DebugInfo.
 type DebugInfo struct {
-	AgentEnabled    *bool          `json:"agent_enabled,omitempty"`
-	AgentIterations *int           `json:"agent_iterations,omitempty"`
-	ConversationId  *string        `json:"conversation_id,omitempty"`
-	FallbackReason  *string        `json:"fallback_reason,omitempty"`
-	MemoryHits      *int           `json:"memory_hits,omitempty"`
-	Mode            *string        `json:"mode,omitempty"`
-	Sources         *[]DebugSource `json:"sources,omitempty"`
-	ToolsInvoked    *[]string      `json:"tools_invoked,omitempty"`
+	AgentEnabled    *bool       `json:"agent_enabled,omitempty"`
+	AgentIterations *int        `json:"agent_iterations,omitempty"`
+	Citations       *[]Citation `json:"citations,omitempty"`
+	ConversationId  *string     `json:"conversation_id,omitempty"`
+	FallbackReason  *string     `json:"fallback_reason,omitempty"`
+	MemoryHits      *int        `json:"memory_hits,omitempty"`
+	Mode            *string     `json:"mode,omitempty"`
+
+	// OrchestrationId Set when specialist agents answered through the orchestrator.
+	OrchestrationId    *string              `json:"orchestration_id,omitempty"`
+	OrchestrationSteps *[]OrchestrationStep `json:"orchestration_steps,omitempty"`
+
+	// RunId Trace of the agent run, see /v1/agent/runs/{id}.
+	RunId        *string           `json:"run_id,omitempty"`
+	Sentences    *[]AnswerSentence `json:"sentences,omitempty"`
+	Sources      *[]DebugSource    `json:"sources,omitempty"`
+	ToolsInvoked *[]string         `json:"tools_invoked,omitempty"`
 }
 
 // DebugSource defines model for DebugSource.
@@ -183,6 +227,13 @@ type HealthResponse struct {
 	Status string `json:"status"`
 }
 
+// Locator Page, sheet or slide of the source file the chunk was extracted from.
+type Locator struct {
+	Page  *int    `json:"page,omitempty"`
+	Sheet *string `json:"sheet,omitempty"`
+	Slide *int    `json:"slide,omitempty"`
+}
+
 // ModelListResponse defines model for ModelListResponse.
 type ModelListResponse struct {
 	Data   []ModelObject `json:"data"`
@@ -197,26 +248,52 @@ type ModelObject struct {
 	OwnedBy string `json:"owned_by"`
 }
 
+// OrchestrationStep defines model for OrchestrationStep.
+type OrchestrationStep struct {
+	Agent      string     `json:"agent"`
+	Attempts   *int       `json:"attempts,omitempty"`
+	DurationMs *float64   `json:"duration_ms,omitempty"`
+	Index      int        `json:"index"`
+	Result     *string    `json:"result,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+
+	// Status completed, failed or skipped.
+	Status string  `json:"status"`
+	StepId *string `json:"step_id,omitempty"`
+	Task   string  `json:"task"`
+}
+
 // RagAnswerResponse defines model for RagAnswerResponse.
 type RagAnswerResponse struct {
-	Sources []RetrievedChunk `json:"sources"`
-	Text    string           `json:"text"`
+	Citations *[]Citation       `json:"citations,omitempty"`
+	Sentences *[]AnswerSentence `json:"sentences,omitempty"`
+	Sources   []RetrievedChunk  `json:"sources"`
+	Text      string            `json:"text"`
 }
 
 // RagQueryRequest defines model for RagQueryRequest.
 type RagQueryRequest struct {
 	Category *string `json:"category,omitempty"`
-	Limit    *int    `json:"limit,omitempty"`
-	Question string  `json:"question"`
+
+	// Citations Ask for inline [n] markers and return structured citations.
+	Citations *bool  `json:"citations,omitempty"`
+	Limit     *int   `json:"limit,omitempty"`
+	Question  string `json:"question"`
 }
 
 // RetrievedChunk defines model for RetrievedChunk.
 type RetrievedChunk struct {
-	Category   string  `json:"category"`
-	DocumentId string  `json:"document_id"`
-	Filename   string  `json:"filename"`
-	Score      float64 `json:"score"`
-	Text       string  `json:"text"`
+	Category   string `json:"category"`
+	ChunkIndex *int   `json:"chunk_index,omitempty"`
+	DocumentId string `json:"document_id"`
+	Filename   string `json:"filename"`
+
+	// Locator Page, sheet or slide of the source file the chunk was extracted from.
+	Locator *Locator `json:"locator,omitempty"`
+	Path    *string  `json:"path,omitempty"`
+	Score   float64  `json:"score"`
+	Text    string   `json:"text"`
+	Title   *string  `json:"title,omitempty"`
 }
 
 // ToolCall defines model for ToolCall.
@@ -970,38 +1047,47 @@ func (sh *strictHandler) QueryRag(w http.ResponseWriter, r *http.Request) {
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xaS3MbuRH+KygkR4qU105qizdZ3jiqsmLHXp82LlZz0ORgjQHGQI9sWsX/ngLmPYPh",
-	"YyPJOeyNIoDG1w/0193UPU9MlhuNmhxf3nOXpJhB+HidAl2bLFdI0ujr1MgE/fe5NTlakhh2baSWLl1Z",
-	"BGe0/0IXSsFaIV+SLXDGaZcjX3JHVuot38+41AK/+Z3VitSEW7R+KUPnYBtu+avFDV/yvyxafIsK3MIj",
-	"u6227vczbvFLIS0Kvvytkt6K+tQgMOvfMSF/zVCzQn8eK5YEhcNHSZi5U0ANhFY22zcQwFrY+b8Ti0Ae",
-	"8D3fGJsBlXb4+ws+i5hFio65WkNmRqCKrlSqLu85fgMPiS95kgLNkwbgPAlqj/wzNKfgjbgWdn33rLHS",
-	"iWaeiiKBiuAMt78K+/ezhwy/iUAqoR3X7z1+KdDRWLcMvq3IfEbtDgb9eZHWhP84tjIkEHCaOSvQt/WR",
-	"g1HlyCJknaW1MQpBBxCY5WiBCou9oN4oA9RGmS6ydak1GaNWSRMOAl1iZe4NyZf8bY766ubCgwaSa4XM",
-	"b2fldraRqMSczwa+roSebsdfjVGv0EdQuHZkykFA1CHf+OuUmHC50Q4fPrs8TGIRuC62x65+5Tfd6I15",
-	"rFTEI6+0OIUHPsYZ4H9MWbctCQ18ZjShpnG4VidYtWHOrkGzNbJSHWas/1Qk/nGIZlMsfjVkGLWiNSos",
-	"oC4yr6PbOcKMezuh5TMOzklHoMNTM0Z1lGuFlE8OlFpNeLHZcN4bugaljr6eoMERk7+qWWDS7pOGeXRd",
-	"oriH6TMG/Q6tAx8oU1Z36JxfRi3K0NpAoYgvN6BcS2CdXOt9HhcWw9k+3xE62KKmFWofgyKe18stknxu",
-	"l2aKwk5RcwNKrSH53OHqcRrBzNjdKpU0xZVGxN3tTGHPyafBKh/CoVgaDUSykvrOfC4t0wgdB9rRSOne",
-	"NY4QINwau4vKFiYpsuCACZtKhZM5wyXmNDKOYq5uPhNwYvRGCtRJ/2JhCp/mImVAlZ5XQP0DQHhBMsMY",
-	"N6C1xp5vjynqkhmuym+jNQ9Q4bqpt8iVAREYJbcm8Y9Xb7lPdiB23Me59K8ploAdGQtbXOVAafy2Yn3Q",
-	"vK7IMphYI9i6cyJ1xotcnGn7GNM2Nu+acqBrY8eew3sIYtTwi3f0dA01FQcDlOW2mPx/FDoJ5ZRP+uPs",
-	"aLfhCcSNORFmg7srw7SiYjD+iaAondazE4NNCWWOt27VsdiNt74UeiMdTV9a89lJ2TTIe9uIHwZarAZU",
-	"0tFRFZoqLqCZ1ORtI3+Qqh6iy46Br0vJ8eavGsVqvTseGf0qtTkX0/E9bK+0+4oHnsK59PceyUq8Q1EO",
-	"P2IMiN/ouBZhV8u+E+j/XaDdTfbHBzOekpkMZzKpZeYz8LOY74JoGa0qBoibnVGsfbP8ULKe5szTXNOF",
-	"08vSjRKzxn3h8phBmpJ4PPercuexSOvl2OlXVhNwzbKN+E8nkVBFOpFTfV067f4f1sjLqbWKjQ/+iBKn",
-	"4o/cG5mldXrT05lrxnOwkCGhLelPiHABqHcd8b2hWo0tRnoxDT5O9dX1JODgnCy3Jsvp4BYyBOrAjgHQ",
-	"vsRZBMhA5FipfZgols1VfyjwIcfkYiOtI3b17ia0/RYSYhtjmdTbMgvNWP1MWUnXMwZasPdXr9kXnzTn",
-	"/wnTEUmBdt6hdd4h7OqGXdUdv5fOZ9z3X+XFl/Nn88tARjlqyCVf8ufzy/lzX7ACpcEwizTUHN/95y2G",
-	"bGLyqse7EXxZ1STfQ11bkk4499Pl5aAjhzxXMgkHF79XfV35Uo69o0HZE0zZN6G3m3SsxFo2V00FzN/I",
-	"O9ToHMutWZdToMXds0WSAi1aRwbUuXERFfsjNcfL2EBHL43YPZiW8SHxvh+K1fjn0Uw9MZXcV2SywDvU",
-	"dNHOd1upg5hGe4f2wvl4DWccKw+xr5JSNh7bhh8Z3DxS6I2c7TGy1nHMoisUMWPZhw+/VNf4qH7xgHbp",
-	"NxgRUC9BMFv7zN/97Onu/qihoNRY+R2Fv/xvT6n4jSa0PtW44HJWNlEBxfOnQ/ErZrmxYHdMYI5aoE52",
-	"zDfYhcVBOojFXi+gHEMtciM1Nbmizr0HksTH0O0305BDOSIrFMkcLC18DXdRN1CtKYY/nKp+wbeWGsqq",
-	"7GCpEM7FiehYQvnpwTzXGCTitHqNQZJgTigC5XVmJT/4Ef/5jg69ozLgGbSFifceuJ1OUmu0KVzPlcOX",
-	"tLjv9B37yfLiNVIdJi93N2GW1ik+f7vn0sOuZkdlwTpoaPqxPutYavh8Pj0isZ70DrLOr6svLl88ndv/",
-	"Zbz3Ci0GPn6NNKw7Q9nZAq3cGuYcbtKLb6Sj23LLI9p4PKyKqOrXmdkwuAMZflNjFfYfy9qDitVRB+AE",
-	"Y9W4KxdY2C5CKzDNUeV4BbaPVMEOBzhPXLuOp18Rq/uGCcKuEMj1POpPpvk/ZpqmyWWwBakdsfAvNyiq",
-	"rqEUX2pTkkJhFV/ylChfLhbKJKBS42j58+XPl3z/af/fAAAA//97BEp7zCYAAA==",
+	"H4sIAAAAAAAC/+xaS3MbNxL+KyjsHkeiHHu3UtyTbCdeVdlrr2WfvCoWOGhyEM0AY6BHFq3if9/CY94Y",
+	"cpRIig85xdEAjX586P66wTuaqqJUEiQauryjJs2gYO6f59J8A30JEkGmYP9SalWCRgHuO0hu/4O7EuiS",
+	"ComwBU33CS2Yvgbt1giEwsRXhb8wrdnO/r9BptEu5WBSLUoUStIl/VhJIGqzMYDkm8BMSIIZEOaUIwi3",
+	"eEqTiHhTlaXSCHws8leWGyDfMpBEKuK1JRqMym/AEFSEEaMqnQJZs/RayK070QRHdI5bK5UDk84auMWO",
+	"nQa1kFu63ydUw9dKaKvHF7+qNjVxHuxqetVIVuvfIEUr+FXG8JUqyhys9q8yJWKx2AgpTLbSwIw18Y7K",
+	"Ks/ZOge6RF1BMtQroUJyuJ2IHxjDtu6Uv2vY0CX926JFySJAZGE1exeWDg310ltRcyyr5PXYsNQZ3MfS",
+	"MaUGQoPPIpBLNbCAkI3SBUPvh3++iCJK8EiAE1ooDnn0SzDVXpVbZlWiS5pmDE/TRsHT1JmdHMGN4LQR",
+	"16pdn500Xprp5ikUcciR3SPsr936ffKQ8JsAklftuH0f4WsFBse2Fex2heoapDkI+vshrYH/GFsFIONs",
+	"njuD0u/qLQdRZVADKzqfelmoKEEzrDT0QL3JFcMWZbIq1iEJK5Wv0gYO/Tz5vgR5fnFilWYo1jkQu5z4",
+	"5WQjIOc2GfZjHYTO9+MnpfLXYBHkjh25cgCIGvJNvOZgwpRKGnj47PIwiYXDutoeO/q1XXQhN+qxUhGN",
+	"3NJqTh34HK8AfzBlvWuL0CBmSiLICFMIO0hYcEpeMUnWQLw5RGn7ryq1l4M3i2L4layAqBe1ysEzn6qw",
+	"NpqdQSio9RNomlBmjDDIpLtqSuUd41oh/sqxPF9NRLFZcL879Irl+dHb4yw44vLXdRWY9PukYx7dlqje",
+	"w/QZU/0GtGEWKFNeN2CM/RxYLYcNq3Kky40lizHGZ2MeFxbVUyDzOB3noEperw7QMa7SqgCJU5pvRA6T",
+	"kM1VylDpY05/G5Y17H18vS5kLiTUdNmXkH+RZydrZoATpz8RElXgziZOy0uG2YT/2z5jeLAVrTZd3l+v",
+	"JinTeldTdK9b/GBTsia4E19ndCBBi0DZ7bkl81mn05u4iB5oTVBg9LoMK50PRMc1fSwkPeh0cNAzp2N5",
+	"7N63ZWUETLa1B4G0uZHH+YZfIhC0A/cEtUoFtp/n1dmwI1pbZ1zmDctz27t1GOm4WEKh9G6VCZxihIrH",
+	"75TSaQYGdVeFPmQuAX1/aUpIBcuFQeJ8ZQKAgRPMtKq2mUNMK1F14Tt1pEEo5zvzfXfvJUIZ86qu4pZ8",
+	"0iyF5vJZE4iuZEIMAFncPFu4Py10Jc3iTvB9VPkav/NVHoweIvqGJDNbosP5pdsUE+co60rIG3UNvCd0",
+	"XNKO1qTuWeN0zxC2Su+isv9Qqjepmkf7ozqHk++pcKrkRvA6bzcHc1VZQhVpOAIRXDHsb2AIJygKiOEH",
+	"tPYV7H7+mCLJooCV/2u0u2JYmS7Jq8pcMe64a6lVCsbYpQnVwPjOpl0m8t7spitMabaF1XTRq9YH3Wuq",
+	"omAT35BtzX2QmtCq5Pf0fYzTd8pM68qBrY0fewHvaRArRr/YQE93a1M4GGjpl8Xk/1rJ1DVull6O653e",
+	"uisQd+YEzAZnB8e0omJq/BtYjtm0nR0MNs2aOj4kCttiJ75tWWA/vX9gW0iIycByG01MLniT7cMM1Ma7",
+	"Q2u+MUPgFjVLETjZaFWc0mRgQBkatwjPsifFoW6PnhgIjex5Z5vIt8LgtBPrTmBWdXDy3jfihxcn1j3b",
+	"mn40JE3/67S5mrLkfSN/kHofYj4ZU75uwseLv0ngq/XuONL7/X2zL2bjmIDEuWZUfYZ2qDXF0XgVKFFh",
+	"el6aLkAHGi0NxrV88bKg71u22mvcv3Nh5AI8Ib52uJt3LcoSeJw/IZSTwwJmrmcEK7QI3s1hV3IoY3xk",
+	"W0/CDgzPHpTW/wgk8SOgFnAD3D9GRCTe75knHD/h3/9WoHeT8+rDtKvr+uGooo+2c3NNNkoT4dv3L/Iq",
+	"tMm2FeFEA1Za9iZjtez4Q1cuCuHULYQUhaVIz2LJyFkllDzurGZl1E39iNzTSz/YZGWaBo6Y+3T+mkDg",
+	"7KlCf4LQIXONK5MGv06tWFiaGd34ITJQrGNu6VGx6eJV8/SajDfir2Zx1cBNI7v6tnTeH363RVZObVXs",
+	"PeP3GDFX/8i5kce9TlqYT3AtbDUrAMOLPuPcHcDyDx3xvVe+WrcYN45Z8Hlq0F8/TRx8uCu1Kko8uAQV",
+	"svzAioGifYlJRJGByLFRe8c0NmrkeHpZQnqyEdogOf9w4d4hLJcOOXrrc2FC6mtKfI1OXLL+eP6GfLVV",
+	"4/R/kjY3nn4AbWxAyPkFOa+fIKx0mlA7KvMHn50+Oz1zHK8EyUpBl/T56dnpc+oTk3PMInOtyXf7760n",
+	"6qoMw70LTpehdfnu2l/PC9y+n87OBk8ErCxzkbqNi9/CCM7flGP3aNAdOVcOStqHCyIM8br6GUzTKNO3",
+	"4gYkGENKrdb+WcrOqexj16INpG9TlImY2H/jM9RjAwy+VHz3YFbGX633fSiG96hHc/XEM+k+lJkF3IDE",
+	"k/bBuZU6HHfqG9AnxuLV7THEb3JTcTJ+R3aV2UTI7jjYVkfSBo54km4p8+XlL+EYi+oXD+iX/hwiotRL",
+	"ZplTiJk9+9nTnf1ZsgozpcV34Pbwfzyl4RcSQdtUY1zIiZ+1OC2eP50Wn6AolWZ6RziUIO38ced6qUrD",
+	"IB3EsNcDlCEgeamExCZX1Ln3QJL47IaCzdD0UI4oqhxFyTQuLLs7qecSrSuGv+TK+1RwLSTTu8hV6Rct",
+	"ty9eiI4llJ8eLHKNQyJBq78RlqZQuuGR0qQzUv2TL/Ff9+jQPfKAJ6wlJjZ6zOxkmmklVWV6oRzepMVd",
+	"p+/YT9KLN4A1TF7uLjjtk88vd1RYtcOI2RPWQUPTx3rS8dTw+lw9YmGddQ+Kzs+9Xpy9eLqw/0fZ6FWS",
+	"D2L8BnDIOx3tbBUNYXXjQzMZRTuYfeeXPKKPxzPgiKn2u51nsxsm3I98SND9z63aA8ZqsKPgRMWq9Q4h",
+	"0Gy7cK3AdI3y8yW2fSQGO5xgPTF3HQ8oI163DVP4xYgFcj2Q+6vS/MCVpmlyCdsyIQ363xYBD12DF++t",
+	"8UWh0jld0gyxXC4WdiyXZ8rg8uezn8/o/mr//wEAe6G+ReMwAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,109 @@
+package httpadapter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type orchestrationListResponse struct {
+	Orchestrations []domain.Orchestration `json:"orchestrations"`
+}
+
+// orchestrationDoneEntry ends an orchestration event stream. Status is still
+// "running" when the server stopped following it early; clients resubscribe.
+type orchestrationDoneEntry struct {
+	Type            string `json:"type"`
+	OrchestrationID string `json:"orchestration_id"`
+	Status          string `json:"status"`
+}
+
+// handleListOrchestrations lists the caller's recent orchestrations with
+// their plans and steps.
+// GET /v1/orchestrations?conversation_id=c1&limit=20
+func (rt *Router) handleListOrchestrations(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.orchestrationSvc != nil, "orchestration service") {
+		return
+	}
+	limit, ok := queryIntParam(w, r, "limit", 20, 1, 200)
+	if !ok {
+		return
+	}
+	orchs, err := rt.orchestrationSvc.List(r.Context(), requestUserID(r), r.URL.Query().Get("conversation_id"), limit)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orchestrationListResponse{Orchestrations: orchs})
+}
+
+// handleGetOrchestration returns one orchestration with its plan and the
+// steps finished so far.
+// GET /v1/orchestrations/{id}
+func (rt *Router) handleGetOrchestration(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.orchestrationSvc != nil, "orchestration service") {
+		return
+	}
+	orch, err := rt.orchestrationSvc.Get(r.Context(), requestUserID(r), r.PathValue("id"))
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, orch)
+}
+
+// handleOrchestrationEvents streams the step events of an orchestration as
+// SSE, replaying earlier ones first, and ends with an "orchestration_done"
+// event once it finishes.
+// GET /v1/orchestrations/{id}/events
+func (rt *Router) handleOrchestrationEvents(w http.ResponseWriter, r *http.Request) {
+	if !requireService(w, rt.orchestrationSvc != nil, "orchestration service") {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported by response writer"))
+		return
+	}
+	userID := requestUserID(r)
+	orchID := r.PathValue("id")
+	sub, err := rt.orchestrationSvc.Subscribe(r.Context(), userID, orchID)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	defer sub.Cancel()
+
+	writeSSEHeaders(w)
+	for _, event := range sub.Events {
+		if err := writeSSEData(w, flusher, newOrchStepEntry(event)); err != nil {
+			return
+		}
+	}
+	if sub.Updates != nil {
+	follow:
+		for {
+			select {
+			case event, open := <-sub.Updates:
+				if !open {
+					break follow
+				}
+				if err := writeSSEData(w, flusher, newOrchStepEntry(event)); err != nil {
+					return
+				}
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+
+	orch, err := rt.orchestrationSvc.Get(r.Context(), userID, orchID)
+	if err != nil {
+		_ = writeSSEData(w, flusher, buildErrorStreamChunk(err))
+		_ = writeSSEDone(w, flusher)
+		return
+	}
+	_ = writeSSEData(w, flusher, orchestrationDoneEntry{Type: "orchestration_done", OrchestrationID: orch.ID, Status: orch.Status})
+	_ = writeSSEDone(w, flusher)
+}
@@ -0,0 +1,106 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeOrchestrationService struct {
+	listUserID, listConversationID string
+	updates                        []domain.OrchestrationStatus
+	status                         string
+}
+
+func (f *fakeOrchestrationService) List(_ context.Context, userID, conversationID string, _ int) ([]domain.Orchestration, error) {
+	f.listUserID, f.listConversationID = userID, conversationID
+	return []domain.Orchestration{{ID: "o-1", UserID: userID, ConversationID: conversationID, Status: "completed"}}, nil
+}
+
+func (f *fakeOrchestrationService) Get(_ context.Context, userID, orchID string) (*domain.Orchestration, error) {
+	if orchID != "o-1" || userID != "alice" {
+		return nil, domain.WrapError(domain.ErrOrchestrationNotFound, "get orchestration", errors.New(orchID))
+	}
+	return &domain.Orchestration{ID: orchID, UserID: userID, Status: f.status}, nil
+}
+
+func (f *fakeOrchestrationService) Subscribe(ctx context.Context, userID, orchID string) (*domain.OrchestrationSubscription, error) {
+	if _, err := f.Get(ctx, userID, orchID); err != nil {
+		return nil, err
+	}
+	updates := make(chan domain.OrchestrationStatus, len(f.updates))
+	for _, event := range f.updates {
+		updates <- event
+	}
+	close(updates)
+	f.status = "completed"
+	return &domain.OrchestrationSubscription{
+		Events:  []domain.OrchestrationStatus{{OrchestrationID: orchID, StepID: "r", AgentName: "researcher", Status: "started"}},
+		Updates: updates,
+		Cancel:  func() {},
+	}, nil
+}
+
+func TestOrchestrationEndpointsScopeToCaller(t *testing.T) {
+	svc := &fakeOrchestrationService{status: "running"}
+	handler := newTestRouter(func(rt *Router) { rt.SetOrchestrationService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/orchestrations?conversation_id=c-1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var list orchestrationListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if svc.listUserID != "alice" || svc.listConversationID != "c-1" || len(list.Orchestrations) != 1 {
+		t.Fatalf("unexpected list call: user=%q conversation=%q %+v", svc.listUserID, svc.listConversationID, list)
+	}
+
+	if rec := doAs(handler, "alice-key", http.MethodGet, "/v1/orchestrations/o-1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("get status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	if rec := doAs(handler, "admin-key", http.MethodGet, "/v1/orchestrations/o-1", ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("another user's orchestration: status = %d, want 404", rec.Code)
+	}
+	if rec := doAs(newTestRouter(), "alice-key", http.MethodGet, "/v1/orchestrations", ""); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without orchestrator: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestOrchestrationEventsReplayAndFollow(t *testing.T) {
+	svc := &fakeOrchestrationService{
+		status: "running",
+		updates: []domain.OrchestrationStatus{
+			{OrchestrationID: "o-1", StepID: "r", AgentName: "researcher", Status: "completed", DurationMS: 12.5},
+		},
+	}
+	handler := newTestRouter(func(rt *Router) { rt.SetOrchestrationService(svc) })
+
+	rec := doAs(handler, "alice-key", http.MethodGet, "/v1/orchestrations/o-1/events", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var events []string
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if data, ok := strings.CutPrefix(line, "data: "); ok {
+			events = append(events, data)
+		}
+	}
+	if len(events) != 4 || events[3] != "[DONE]" {
+		t.Fatalf("unexpected events: %q", events)
+	}
+	var step orchStepEntry
+	if err := json.Unmarshal([]byte(events[1]), &step); err != nil || step.Status != "completed" || step.DurationMS != 12.5 {
+		t.Fatalf("unexpected live step event %q: %v", events[1], err)
+	}
+	var done orchestrationDoneEntry
+	if err := json.Unmarshal([]byte(events[2]), &done); err != nil || done.Type != "orchestration_done" || done.Status != "completed" {
+		t.Fatalf("unexpected final event %q: %v", events[2], err)
+	}
+}
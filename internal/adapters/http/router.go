@@ -13,7 +13,6 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"syscall"
 	"time"
 
@@ -39,19 +38,14 @@ type Router struct {
 	openAICompatStreamChunkChars int
 	toolTriggerKeywords          []string
 	ragTopK                      int
+	ragCitationsEnabled          bool
 	agentModeEnabled             bool
 	httpMetrics                  *metrics.HTTPServerMetrics
 	apiRateLimiter               *rate.Limiter
 	apiBackpressureMaxInFlight   int
 	apiBackpressureWaitTimeout   time.Duration
 
-	obsidianConfigPath             string
-	obsidianStateDir               string
-	obsidianVaultsRoot             string
 	obsidianDefaultIntervalMinutes int
-	obsidianSyncTimeout            time.Duration
-	obsidianSyncPoll               time.Duration
-	obsidianMu                     sync.Mutex
 
 	mcpHandler   http.Handler
 	httpToolDefs []paamcp.HTTPToolDef
@@ -64,6 +58,17 @@ type Router struct {
 	scheduleStore      ports.ScheduleStore
 	docRepo            ports.DocumentRepository
 	objectStorage      ports.ObjectStorage
+	docDeleter         ports.DocumentDeleter
+	docACL             ports.DocumentACLService
+	vaultSvc           ports.VaultSyncService
+	authSvc            ports.AuthService
+	conversationSvc    ports.ConversationService
+	memoryFactSvc      ports.MemoryFactService
+	evalSvc            ports.EvalService
+	bulkIngestSvc      ports.BulkIngestService
+	orchestrationSvc   ports.OrchestrationService
+	agentJobSvc        ports.AgentJobService
+	agentRunSvc        ports.AgentRunService
 }
 
 func NewRouter(
@@ -111,14 +116,6 @@ func NewRouter(
 	if obsidianDefaultInterval <= 0 {
 		obsidianDefaultInterval = 15
 	}
-	obsidianSyncTimeout := time.Duration(cfg.ObsidianSyncTimeoutSeconds) * time.Second
-	if obsidianSyncTimeout <= 0 {
-		obsidianSyncTimeout = 120 * time.Second
-	}
-	obsidianSyncPoll := time.Duration(cfg.ObsidianSyncPollSeconds) * time.Second
-	if obsidianSyncPoll <= 0 {
-		obsidianSyncPoll = 2 * time.Second
-	}
 
 	return &Router{
 		ingestor: ingestor,
@@ -133,21 +130,28 @@ func NewRouter(
 		openAICompatStreamChunkChars: streamChunkChars,
 		toolTriggerKeywords:          toolKeywords,
 		ragTopK:                      ragTopK,
+		ragCitationsEnabled:          cfg.RAGCitationsEnabled,
 		agentModeEnabled:             cfg.AgentModeEnabled,
 		httpMetrics:                  metrics.NewHTTPServerMetrics("api"),
 		apiRateLimiter:               apiRateLimiter,
 		apiBackpressureMaxInFlight:   cfg.APIBackpressureMaxInFlight,
 		apiBackpressureWaitTimeout:   apiBackpressureWait,
 
-		obsidianConfigPath:             cfg.ObsidianConfigPath,
-		obsidianStateDir:               cfg.ObsidianStateDir,
-		obsidianVaultsRoot:             cfg.ObsidianVaultsRoot,
 		obsidianDefaultIntervalMinutes: obsidianDefaultInterval,
-		obsidianSyncTimeout:            obsidianSyncTimeout,
-		obsidianSyncPoll:               obsidianSyncPoll,
 	}
 }
 
+// SetVaultSyncService sets the service behind the /v1/obsidian endpoints.
+func (rt *Router) SetVaultSyncService(s ports.VaultSyncService) {
+	rt.vaultSvc = s
+}
+
+// SetAuthService enables per-user API key authentication on /v1/* and /mcp
+// and the /v1/users, /v1/me and /v1/api-keys endpoints.
+func (rt *Router) SetAuthService(s ports.AuthService) {
+	rt.authSvc = s
+}
+
 // SetMCPHandler sets the MCP server handler to be mounted on /mcp.
 func (rt *Router) SetMCPHandler(h http.Handler) {
 	rt.mcpHandler = h
@@ -188,6 +192,51 @@ func (rt *Router) SetObjectStorage(s ports.ObjectStorage) {
 	rt.objectStorage = s
 }
 
+// SetDocumentDeleter sets the use case behind the DELETE /v1/documents endpoints.
+func (rt *Router) SetDocumentDeleter(d ports.DocumentDeleter) {
+	rt.docDeleter = d
+}
+
+// SetDocumentACLService sets the use case behind PUT /v1/documents/{id}/acl.
+func (rt *Router) SetDocumentACLService(s ports.DocumentACLService) {
+	rt.docACL = s
+}
+
+// SetConversationService sets the use case behind the /v1/conversations endpoints.
+func (rt *Router) SetConversationService(s ports.ConversationService) {
+	rt.conversationSvc = s
+}
+
+// SetMemoryFactService sets the use case behind the /v1/memories endpoints.
+func (rt *Router) SetMemoryFactService(s ports.MemoryFactService) {
+	rt.memoryFactSvc = s
+}
+
+// SetEvalService sets the use case behind the /v1/eval endpoints.
+func (rt *Router) SetEvalService(s ports.EvalService) {
+	rt.evalSvc = s
+}
+
+// SetBulkIngestService sets the use case behind the /v1/ingest endpoints.
+func (rt *Router) SetBulkIngestService(s ports.BulkIngestService) {
+	rt.bulkIngestSvc = s
+}
+
+// SetOrchestrationService sets the use case behind the /v1/orchestrations endpoints.
+func (rt *Router) SetOrchestrationService(s ports.OrchestrationService) {
+	rt.orchestrationSvc = s
+}
+
+// SetAgentJobService sets the use case behind the /v1/agent/jobs endpoints.
+func (rt *Router) SetAgentJobService(s ports.AgentJobService) {
+	rt.agentJobSvc = s
+}
+
+// SetAgentRunService sets the use case behind the /v1/agent/runs endpoints.
+func (rt *Router) SetAgentRunService(s ports.AgentRunService) {
+	rt.agentRunSvc = s
+}
+
 // SetHTTPToolDefs stores the list of HTTP tool definitions for the GET /v1/tools endpoint.
 func (rt *Router) SetHTTPToolDefs(defs []paamcp.HTTPToolDef) {
 	rt.httpToolDefs = defs
@@ -218,6 +267,7 @@ func (rt *Router) Handler() http.Handler {
 	mux.HandleFunc("POST /v1/obsidian/vaults", rt.handleObsidianUpsert)
 	mux.HandleFunc("DELETE /v1/obsidian/vaults/{id}", rt.handleObsidianRemove)
 	mux.HandleFunc("POST /v1/obsidian/vaults/{id}/sync", rt.handleObsidianSync)
+	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/sync-runs", rt.handleObsidianSyncRuns)
 	mux.HandleFunc("POST /v1/obsidian/vaults/{id}/notes", rt.handleObsidianCreateNote)
 	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/files", rt.handleObsidianListFiles)
 	mux.HandleFunc("GET /v1/obsidian/vaults/{id}/files/content", rt.handleObsidianFileContent)
@@ -234,8 +284,58 @@ func (rt *Router) Handler() http.Handler {
 	mux.HandleFunc("DELETE /v1/schedules/{id}", rt.handleDeleteSchedule)
 	mux.HandleFunc("PATCH /v1/schedules/{id}", rt.handleUpdateSchedule)
 
+	mux.HandleFunc("POST /v1/ingest/archives", rt.handleIngestArchive)
+	mux.HandleFunc("POST /v1/ingest/directories", rt.handleIngestDirectory)
+	mux.HandleFunc("GET /v1/ingest/jobs", rt.handleListIngestJobs)
+	mux.HandleFunc("GET /v1/ingest/jobs/{id}", rt.handleGetIngestJob)
+
 	mux.HandleFunc("GET /v1/documents", rt.handleListDocuments)
 	mux.HandleFunc("GET /v1/documents/{id}/content", rt.handleGetDocumentContent)
+	mux.HandleFunc("DELETE /v1/documents", rt.handleDeleteDocuments)
+	mux.HandleFunc("DELETE /v1/documents/{id}", rt.handleDeleteDocument)
+	mux.HandleFunc("PUT /v1/documents/{id}/acl", rt.handlePutDocumentACL)
+
+	mux.HandleFunc("GET /v1/conversations", rt.handleListConversations)
+	mux.HandleFunc("GET /v1/conversations/search", rt.handleSearchConversations)
+	mux.HandleFunc("GET /v1/conversations/{id}", rt.handleGetConversation)
+	mux.HandleFunc("PATCH /v1/conversations/{id}", rt.handleRenameConversation)
+	mux.HandleFunc("DELETE /v1/conversations/{id}", rt.handleDeleteConversation)
+	mux.HandleFunc("GET /v1/conversations/{id}/export", rt.handleExportConversation)
+
+	mux.HandleFunc("POST /v1/agent/jobs", rt.handleSubmitAgentJob)
+	mux.HandleFunc("GET /v1/agent/jobs", rt.handleListAgentJobs)
+	mux.HandleFunc("GET /v1/agent/jobs/{id}", rt.handleGetAgentJob)
+	mux.HandleFunc("GET /v1/agent/jobs/{id}/events", rt.handleAgentJobEvents)
+	mux.HandleFunc("POST /v1/agent/jobs/{id}/cancel", rt.handleCancelAgentJob)
+
+	mux.HandleFunc("GET /v1/agent/runs", rt.handleListAgentRuns)
+	mux.HandleFunc("GET /v1/agent/runs/{id}", rt.handleGetAgentRun)
+	mux.HandleFunc("POST /v1/agent/runs/{id}/replay", rt.handleReplayAgentRun)
+
+	mux.HandleFunc("GET /v1/orchestrations", rt.handleListOrchestrations)
+	mux.HandleFunc("GET /v1/orchestrations/{id}", rt.handleGetOrchestration)
+	mux.HandleFunc("GET /v1/orchestrations/{id}/events", rt.handleOrchestrationEvents)
+
+	mux.HandleFunc("GET /v1/memories", rt.handleListMemoryFacts)
+	mux.HandleFunc("POST /v1/memories", rt.handleRememberFact)
+	mux.HandleFunc("PATCH /v1/memories/{id}", rt.handleUpdateMemoryFact)
+	mux.HandleFunc("DELETE /v1/memories/{id}", rt.handleForgetMemoryFact)
+
+	mux.HandleFunc("GET /v1/eval/cases", rt.handleListEvalCases)
+	mux.HandleFunc("POST /v1/eval/cases", rt.handleSaveEvalCases)
+	mux.HandleFunc("DELETE /v1/eval/cases/{id}", rt.handleDeleteEvalCase)
+	mux.HandleFunc("GET /v1/eval/runs", rt.handleListEvalRuns)
+	mux.HandleFunc("POST /v1/eval/runs", rt.handleStartEvalRun)
+	mux.HandleFunc("GET /v1/eval/runs/{id}", rt.handleGetEvalRun)
+	mux.HandleFunc("GET /v1/eval/runs/{id}/diff", rt.handleDiffEvalRuns)
+
+	mux.HandleFunc("GET /v1/me", rt.handleGetMe)
+	mux.HandleFunc("GET /v1/users", rt.handleListUsers)
+	mux.HandleFunc("POST /v1/users", rt.handleCreateUser)
+	mux.HandleFunc("PUT /v1/users/{id}/groups", rt.handleSetUserGroups)
+	mux.HandleFunc("GET /v1/api-keys", rt.handleListAPIKeys)
+	mux.HandleFunc("POST /v1/api-keys", rt.handleCreateAPIKey)
+	mux.HandleFunc("DELETE /v1/api-keys/{id}", rt.handleRevokeAPIKey)
 
 	mux.HandleFunc("GET /v1/tools", rt.handleListTools)
 	mux.HandleFunc("GET /v1/settings/models", rt.handleGetRuntimeModels)
@@ -265,6 +365,7 @@ func (rt *Router) Handler() http.Handler {
 			writeError(w, http.StatusBadRequest, err)
 		},
 	})
+	handler = rt.authMiddleware(handler)
 	handler = backpressureMiddleware(handler, rt.apiBackpressureMaxInFlight, rt.apiBackpressureWaitTimeout)
 	handler = rateLimitMiddleware(handler, rt.apiRateLimiter)
 	handler = rt.httpMetrics.Middleware("api", handler)
@@ -325,6 +426,11 @@ func (rt *Router) GetDocumentById(ctx context.Context, request apigen.GetDocumen
 			Error: err.Error(),
 		}, nil
 	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.CanRead(*doc) {
+		return apigen.GetDocumentById404JSONResponse{
+			Error: domain.WrapError(domain.ErrDocumentNotFound, "get document by id", fmt.Errorf("id=%s", request.DocumentId)).Error(),
+		}, nil
+	}
 
 	return apigen.GetDocumentById200JSONResponse(toAPIDocument(doc)), nil
 }
@@ -346,8 +452,13 @@ func (rt *Router) QueryRag(ctx context.Context, request apigen.QueryRagRequestOb
 		filter.Categories = []string{*request.Body.Category}
 	}
 
+	answerFn := rt.querySvc.Answer
+	if request.Body.Citations != nil && *request.Body.Citations {
+		answerFn = rt.querySvc.AnswerWithCitations
+	}
+
 	start := time.Now()
-	answer, err := rt.querySvc.Answer(ctx, request.Body.Question, limit, filter)
+	answer, err := answerFn(ctx, request.Body.Question, limit, filter)
 	if err != nil {
 		switch status := mapErrorToHTTPStatus(err); status {
 		case http.StatusBadRequest:
@@ -384,10 +495,17 @@ func (rt *Router) QueryRag(ctx context.Context, request apigen.QueryRagRequestOb
 		"duration_ms", float64(time.Since(start).Microseconds())/1000.0,
 	)
 
-	return apigen.QueryRag200JSONResponse{
+	response := apigen.QueryRag200JSONResponse{
 		Text:    answer.Text,
 		Sources: toAPIRetrievedChunks(answer.Sources),
-	}, nil
+	}
+	if answer.Sentences != nil {
+		citations := toAPICitations(answer.Citations)
+		sentences := toAPIAnswerSentences(answer.Sentences)
+		response.Citations = &citations
+		response.Sentences = &sentences
+	}
+	return response, nil
 }
 
 func getMultipartFilePart(reader *multipart.Reader, formField string) (*multipart.Part, error) {
@@ -431,17 +549,70 @@ func toAPIDocument(doc *domain.Document) apigen.Document {
 func toAPIRetrievedChunks(chunks []domain.RetrievedChunk) []apigen.RetrievedChunk {
 	out := make([]apigen.RetrievedChunk, 0, len(chunks))
 	for _, chunk := range chunks {
+		chunkIndex := chunk.ChunkIndex
 		out = append(out, apigen.RetrievedChunk{
 			DocumentId: chunk.DocumentID,
 			Filename:   chunk.Filename,
 			Category:   chunk.Category,
+			Title:      nilIfEmpty(chunk.Title),
+			Path:       nilIfEmpty(chunk.Path),
+			ChunkIndex: &chunkIndex,
 			Text:       chunk.Text,
 			Score:      chunk.Score,
+			Locator:    toAPILocator(chunk.Locator),
+		})
+	}
+	return out
+}
+
+func toAPICitations(citations []domain.Citation) []apigen.Citation {
+	out := make([]apigen.Citation, 0, len(citations))
+	for _, c := range citations {
+		out = append(out, apigen.Citation{
+			Marker:     c.Marker,
+			Sentence:   c.Sentence,
+			DocumentId: c.DocumentID,
+			ChunkIndex: c.ChunkIndex,
+			Filename:   c.Filename,
+			Title:      nilIfEmpty(c.Title),
+			Path:       nilIfEmpty(c.Path),
+			Locator:    toAPILocator(c.Locator),
+			SpanStart:  c.SpanStart,
+			SpanEnd:    c.SpanEnd,
 		})
 	}
 	return out
 }
 
+func toAPILocator(l domain.Locator) *apigen.Locator {
+	if l.IsZero() {
+		return nil
+	}
+	return &apigen.Locator{
+		Page:  nilIfZeroInt(l.Page),
+		Sheet: nilIfEmpty(l.Sheet),
+		Slide: nilIfZeroInt(l.Slide),
+	}
+}
+
+func toAPIAnswerSentences(sentences []domain.AnswerSentence) []apigen.AnswerSentence {
+	out := make([]apigen.AnswerSentence, 0, len(sentences))
+	for _, s := range sentences {
+		sentence := apigen.AnswerSentence{
+			Text:      s.Text,
+			Start:     s.Start,
+			End:       s.End,
+			Supported: s.Supported,
+		}
+		if len(s.Markers) > 0 {
+			markers := append([]int(nil), s.Markers...)
+			sentence.Markers = &markers
+		}
+		out = append(out, sentence)
+	}
+	return out
+}
+
 func nilIfEmpty(v string) *string {
 	if v == "" {
 		return nil
@@ -456,6 +627,13 @@ func nilIfZero(v float64) *float64 {
 	return &v
 }
 
+func nilIfZeroInt(v int) *int {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
 func nilIfEmptySlice(v []string) *[]string {
 	if len(v) == 0 {
 		return nil
@@ -465,6 +643,16 @@ func nilIfEmptySlice(v []string) *[]string {
 	return &out
 }
 
+// requireService answers 503 when the service behind an endpoint is not
+// configured.
+func requireService(w http.ResponseWriter, configured bool, name string) bool {
+	if !configured {
+		writeError(w, http.StatusServiceUnavailable, errors.New(name+" not configured"))
+		return false
+	}
+	return true
+}
+
 func writeError(w http.ResponseWriter, status int, err error) {
 	msg := "internal server error"
 	if err != nil && err.Error() != "" {
@@ -524,16 +712,38 @@ func (rt *Router) handleGetGraph(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Enrich graph nodes with category from document repository
-	if graph != nil && rt.docRepo != nil {
-		for i := range graph.Nodes {
-			if graph.Nodes[i].Category == "" {
-				doc, err := rt.docRepo.GetByID(r.Context(), graph.Nodes[i].ID)
-				if err == nil && doc.Category != "" {
-					graph.Nodes[i].Category = doc.Category
+	// Enrich graph nodes with category from document repository and drop
+	// documents the caller may not read, with the edges touching them.
+	if graph != nil {
+		p, ok := domain.PrincipalFromContext(r.Context())
+		restricted := ok && !p.Admin
+		nodes := graph.Nodes[:0]
+		readable := make(map[string]struct{}, len(graph.Nodes))
+		for _, node := range graph.Nodes {
+			var doc *domain.Document
+			if rt.docRepo != nil && (restricted || node.Category == "") {
+				if found, err := rt.docRepo.GetByID(r.Context(), node.ID); err == nil {
+					doc = found
 				}
 			}
+			if restricted && (doc == nil || !canReadDocument(r, *doc)) {
+				continue
+			}
+			if node.Category == "" && doc != nil && doc.Category != "" {
+				node.Category = doc.Category
+			}
+			nodes = append(nodes, node)
+			readable[node.ID] = struct{}{}
+		}
+		edges := graph.Edges[:0]
+		for _, edge := range graph.Edges {
+			_, src := readable[edge.SourceID]
+			_, dst := readable[edge.TargetID]
+			if src && dst {
+				edges = append(edges, edge)
+			}
 		}
+		graph.Nodes, graph.Edges = nodes, edges
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -561,7 +771,7 @@ func (rt *Router) handlePostFeedback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	fb := &domain.AgentFeedback{
-		UserID:         r.Header.Get("X-User-ID"),
+		UserID:         requestUserID(r),
 		ConversationID: req.ConversationID,
 		MessageID:      req.MessageID,
 		Rating:         req.Rating,
@@ -613,7 +823,7 @@ func (rt *Router) handleCreateSchedule(w http.ResponseWriter, r *http.Request) {
 	}
 
 	task := &domain.ScheduledTask{
-		UserID:     r.Header.Get("X-User-ID"),
+		UserID:     requestUserID(r),
 		CronExpr:   req.CronExpr,
 		Prompt:     req.Prompt,
 		Condition:  req.Condition,
@@ -636,8 +846,7 @@ func (rt *Router) handleListSchedules(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	userID := r.Header.Get("X-User-ID")
-	tasks, err := rt.scheduleStore.ListByUser(r.Context(), userID)
+	tasks, err := rt.scheduleStore.ListByUser(r.Context(), requestUserID(r))
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
@@ -658,6 +867,13 @@ func (rt *Router) handleDeleteSchedule(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := domain.PrincipalFromContext(r.Context()); ok {
+		task, err := rt.scheduleStore.GetByID(r.Context(), id)
+		if err != nil || !canAccessOwned(r, task.UserID) {
+			writeError(w, http.StatusNotFound, fmt.Errorf("schedule not found: %s", id))
+			return
+		}
+	}
 	if err := rt.scheduleStore.Delete(r.Context(), id); err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
@@ -682,6 +898,10 @@ func (rt *Router) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, err)
 		return
 	}
+	if !canAccessOwned(r, task.UserID) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("schedule not found: %s", id))
+		return
+	}
 
 	var patch struct {
 		CronExpr   *string `json:"cron_expr"`
@@ -721,6 +941,9 @@ func (rt *Router) handleUpdateSchedule(w http.ResponseWriter, r *http.Request) {
 }
 
 func (rt *Router) handleGetEventsSummary(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) {
+		return
+	}
 	if rt.eventStore == nil {
 		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("event store not configured"))
 		return
@@ -741,6 +964,9 @@ func (rt *Router) handleGetEventsSummary(w http.ResponseWriter, r *http.Request)
 }
 
 func (rt *Router) handleGetFeedbackSummary(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) {
+		return
+	}
 	if rt.feedbackStore == nil {
 		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("feedback store not configured"))
 		return
@@ -769,6 +995,9 @@ func (rt *Router) handleGetFeedbackSummary(w http.ResponseWriter, r *http.Reques
 }
 
 func (rt *Router) handleGetImprovements(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) {
+		return
+	}
 	if rt.improvementStore == nil {
 		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("improvement store not configured"))
 		return
@@ -793,7 +1022,15 @@ func (rt *Router) handleListDocuments(w http.ResponseWriter, r *http.Request) {
 			limit = v
 		}
 	}
-	docs, err := rt.docRepo.ListRecent(r.Context(), limit)
+	var (
+		docs []domain.Document
+		err  error
+	)
+	if p, ok := domain.PrincipalFromContext(r.Context()); ok && !p.Admin {
+		docs, err = rt.docRepo.ListByFilter(r.Context(), domain.DocumentFilter{AccessTokens: p.ReadScope()}, limit)
+	} else {
+		docs, err = rt.docRepo.ListRecent(r.Context(), limit)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err)
 		return
@@ -820,6 +1057,10 @@ func (rt *Router) handleGetDocumentContent(w http.ResponseWriter, r *http.Reques
 		writeError(w, http.StatusNotFound, err)
 		return
 	}
+	if !canReadDocument(r, *doc) {
+		writeError(w, http.StatusNotFound, domain.WrapError(domain.ErrDocumentNotFound, "get document content", fmt.Errorf("id=%s", id)))
+		return
+	}
 	reader, err := rt.objectStorage.Open(r.Context(), doc.StoragePath)
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, fmt.Errorf("open file: %w", err))
@@ -839,7 +1080,93 @@ func (rt *Router) handleGetDocumentContent(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+func (rt *Router) handleDeleteDocument(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("id is required"))
+		return
+	}
+	if rt.docDeleter == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("document deleter not configured"))
+		return
+	}
+	if err := rt.docDeleter.Delete(r.Context(), id); err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type documentACLRequest struct {
+	Visibility domain.DocumentVisibility `json:"visibility"`
+	SharedWith []string                  `json:"shared_with"`
+}
+
+// handlePutDocumentACL replaces a document's visibility and share list.
+func (rt *Router) handlePutDocumentACL(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("id is required"))
+		return
+	}
+	if rt.docACL == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("document acl service not configured"))
+		return
+	}
+	var req documentACLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	doc, err := rt.docACL.SetACL(r.Context(), id, req.Visibility, req.SharedWith)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+// handleDeleteDocuments bulk-deletes documents selected by query parameters:
+// source_types, categories, statuses (comma-separated) and path_prefix.
+func (rt *Router) handleDeleteDocuments(w http.ResponseWriter, r *http.Request) {
+	if rt.docDeleter == nil {
+		writeError(w, http.StatusServiceUnavailable, fmt.Errorf("document deleter not configured"))
+		return
+	}
+	q := r.URL.Query()
+	filter := domain.DocumentFilter{
+		SourceTypes: splitQueryList(q.Get("source_types")),
+		Categories:  splitQueryList(q.Get("categories")),
+		PathPrefix:  strings.TrimSpace(q.Get("path_prefix")),
+	}
+	for _, st := range splitQueryList(q.Get("statuses")) {
+		filter.Statuses = append(filter.Statuses, domain.DocumentStatus(st))
+	}
+
+	result, err := rt.docDeleter.DeleteByFilter(r.Context(), filter)
+	if err != nil {
+		writeError(w, mapErrorToHTTPStatus(err), err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func splitQueryList(raw string) []string {
+	var out []string
+	for item := range strings.SplitSeq(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
 func (rt *Router) handlePatchImprovement(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) {
+		return
+	}
 	id := r.PathValue("id")
 	if id == "" {
 		writeError(w, http.StatusBadRequest, fmt.Errorf("id is required"))
@@ -38,6 +38,18 @@ func (f queryErrFake) Answer(context.Context, string, int, domain.SearchFilter)
 	return &domain.Answer{Text: "ok"}, nil
 }
 
+func (f queryErrFake) AnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
+func (f queryErrFake) StreamAnswer(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
+func (f queryErrFake) StreamAnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
 func (f queryErrFake) GenerateFromPrompt(context.Context, string) (string, error) { return "ok", nil }
 func (f queryErrFake) GenerateJSONFromPrompt(context.Context, string) (string, error) {
 	return `{"type":"final","answer":"ok"}`, nil
@@ -99,6 +111,30 @@ func TestQueryRagMapsDomainTemporaryTo503(t *testing.T) {
 	}
 }
 
+func TestChatCompletionsStreamMapsTemporaryTo503BeforeFirstToken(t *testing.T) {
+	handler := NewRouter(
+		config.Config{RAGTopK: 5},
+		nil,
+		queryErrFake{err: domain.WrapError(domain.ErrTemporary, "answer", errors.New("ollama unavailable"))},
+		docsErrFake{},
+		nil,
+		nil,
+	).Handler()
+
+	payload, _ := json.Marshal(map[string]any{
+		"messages": []map[string]any{{"role": "user", "content": "hello"}},
+		"stream":   true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", res.Code)
+	}
+}
+
 func TestUploadDocumentMapsDomainTemporaryTo503(t *testing.T) {
 	handler := NewRouter(
 		config.Config{RAGTopK: 5},
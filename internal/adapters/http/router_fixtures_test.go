@@ -0,0 +1,30 @@
+package httpadapter
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/config"
+)
+
+// newTestRouter builds a router authenticating with newAuthFake; options
+// plug in the services under test.
+func newTestRouter(opts ...func(*Router)) http.Handler {
+	rt := NewRouter(config.Config{RAGTopK: 5}, nil, nil, fakeDocumentRepo{}, nil, nil)
+	rt.SetAuthService(newAuthFake())
+	for _, opt := range opts {
+		opt(rt)
+	}
+	return rt.Handler()
+}
+
+// doAs sends a request authenticated with one of the API keys of
+// newAuthFake ("alice-key" or "admin-key").
+func doAs(handler http.Handler, apiKey, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewReader([]byte(body)))
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
@@ -29,6 +29,7 @@ func (f *fakeGraphStore) AddSimilarity(context.Context, string, string, float64)
 	return nil
 }
 func (f *fakeGraphStore) RemoveSimilarities(context.Context, string) error { return nil }
+func (f *fakeGraphStore) DeleteDocument(context.Context, string) error     { return nil }
 func (f *fakeGraphStore) GetRelated(context.Context, string, int, int) ([]domain.GraphRelation, error) {
 	return nil, nil
 }
@@ -136,6 +137,28 @@ func (f *fakeRuntimeModelConfig) SetRuntimeModelConfig(config ports.RuntimeModel
 	return nil
 }
 
+type fakeDocumentDeleter struct {
+	deleted []string
+	filter  domain.DocumentFilter
+	err     error
+}
+
+func (f *fakeDocumentDeleter) Delete(_ context.Context, id string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeDocumentDeleter) DeleteByFilter(_ context.Context, filter domain.DocumentFilter) (*domain.DocumentDeleteResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.filter = filter
+	return &domain.DocumentDeleteResult{Deleted: []string{"d1", "d2"}}, nil
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
@@ -257,6 +280,71 @@ func TestHandleGetGraph_Success(t *testing.T) {
 	}
 }
 
+type graphDocRepo struct {
+	fakeDocumentRepo
+	docs map[string]*domain.Document
+}
+
+func (f graphDocRepo) GetByID(_ context.Context, id string) (*domain.Document, error) {
+	doc, ok := f.docs[id]
+	if !ok {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return doc, nil
+}
+func (f graphDocRepo) GetBySourceID(context.Context, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f graphDocRepo) GetByContentHash(context.Context, string, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f graphDocRepo) UpdateSource(context.Context, *domain.Document) error { return nil }
+func (f graphDocRepo) ListRecent(context.Context, int) ([]domain.Document, error) {
+	return nil, nil
+}
+func (f graphDocRepo) ListByFilter(context.Context, domain.DocumentFilter, int) ([]domain.Document, error) {
+	return nil, nil
+}
+func (f graphDocRepo) Delete(context.Context, string) error { return nil }
+
+func TestHandleGetGraph_HidesUnreadableDocuments(t *testing.T) {
+	gs := &fakeGraphStore{
+		graph: &domain.Graph{
+			Nodes: []domain.GraphNode{
+				{ID: "mine", Title: "Mine"},
+				{ID: "secret", Title: "Secret"},
+				{ID: "public", Title: "Public"},
+			},
+			Edges: []domain.GraphRelation{
+				{SourceID: "mine", TargetID: "secret", Type: "wikilink"},
+				{SourceID: "mine", TargetID: "public", Type: "wikilink"},
+			},
+		},
+	}
+	rt := newRouterWithStores(gs, nil, nil)
+	rt.docRepo = graphDocRepo{docs: map[string]*domain.Document{
+		"mine":   {ID: "mine", OwnerID: "alice", Visibility: domain.VisibilityPrivate},
+		"secret": {ID: "secret", OwnerID: "bob", Visibility: domain.VisibilityPrivate},
+		"public": {ID: "public", Visibility: domain.VisibilityPublic},
+	}}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/graph", nil)
+	req = req.WithContext(domain.ContextWithPrincipal(req.Context(), domain.Principal{UserID: "alice"}))
+	rec := httptest.NewRecorder()
+	rt.handleGetGraph(rec, req)
+
+	var graph domain.Graph
+	if err := json.NewDecoder(rec.Body).Decode(&graph); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(graph.Nodes) != 2 || graph.Nodes[0].ID != "mine" || graph.Nodes[1].ID != "public" {
+		t.Fatalf("unexpected nodes: %+v", graph.Nodes)
+	}
+	if len(graph.Edges) != 1 || graph.Edges[0].TargetID != "public" {
+		t.Fatalf("unexpected edges: %+v", graph.Edges)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Feedback handler tests
 // ---------------------------------------------------------------------------
@@ -441,3 +529,78 @@ func TestHandleUpdateSchedule_Success(t *testing.T) {
 		t.Fatalf("store not updated: prompt=%s", ss.tasks[0].Prompt)
 	}
 }
+
+func TestHandleDeleteDocument_Success(t *testing.T) {
+	dd := &fakeDocumentDeleter{}
+	rt := &Router{docDeleter: dd}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/documents/d1", nil)
+	req.SetPathValue("id", "d1")
+	rec := httptest.NewRecorder()
+	rt.handleDeleteDocument(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if len(dd.deleted) != 1 || dd.deleted[0] != "d1" {
+		t.Fatalf("expected d1 deleted, got %v", dd.deleted)
+	}
+}
+
+func TestHandleDeleteDocument_NotFound(t *testing.T) {
+	dd := &fakeDocumentDeleter{
+		err: domain.WrapError(domain.ErrDocumentNotFound, "get document by id", errors.New("id=missing")),
+	}
+	rt := &Router{docDeleter: dd}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/documents/missing", nil)
+	req.SetPathValue("id", "missing")
+	rec := httptest.NewRecorder()
+	rt.handleDeleteDocument(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleDeleteDocuments_ParsesFilter(t *testing.T) {
+	dd := &fakeDocumentDeleter{}
+	rt := &Router{docDeleter: dd}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/documents?source_types=obsidian,web&statuses=failed&path_prefix=notes/", nil)
+	rec := httptest.NewRecorder()
+	rt.handleDeleteDocuments(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d; body: %s", rec.Code, rec.Body.String())
+	}
+	if len(dd.filter.SourceTypes) != 2 || dd.filter.SourceTypes[1] != "web" {
+		t.Fatalf("unexpected source types: %v", dd.filter.SourceTypes)
+	}
+	if len(dd.filter.Statuses) != 1 || dd.filter.Statuses[0] != domain.StatusFailed {
+		t.Fatalf("unexpected statuses: %v", dd.filter.Statuses)
+	}
+	if dd.filter.PathPrefix != "notes/" {
+		t.Fatalf("unexpected path prefix: %q", dd.filter.PathPrefix)
+	}
+
+	var result domain.DocumentDeleteResult
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(result.Deleted) != 2 {
+		t.Fatalf("expected 2 deleted ids, got %v", result.Deleted)
+	}
+}
+
+func TestHandleDeleteDocuments_NotConfigured(t *testing.T) {
+	rt := &Router{}
+
+	req := httptest.NewRequest(http.MethodDelete, "/v1/documents?categories=x", nil)
+	rec := httptest.NewRecorder()
+	rt.handleDeleteDocuments(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+}
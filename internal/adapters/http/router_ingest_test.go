@@ -39,8 +39,14 @@ func (f ingestSuccessFake) Upload(_ context.Context, filename, mimeType string,
 	}, nil
 }
 
-func (f ingestSuccessFake) IngestFromSource(context.Context, domain.SourceRequest) (*domain.Document, error) {
-	return nil, nil
+func (f ingestSuccessFake) IngestFromSource(ctx context.Context, req domain.SourceRequest) (*domain.Document, error) {
+	doc, err := f.Upload(ctx, req.Filename, req.MimeType, req.Body)
+	if err != nil {
+		return nil, err
+	}
+	doc.SourceType = req.SourceType
+	doc.Path = req.Path
+	return doc, nil
 }
 
 func newRouterForIngestTests() http.Handler {
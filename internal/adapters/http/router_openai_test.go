@@ -30,6 +30,9 @@ func (f fakeDocumentRepo) UpdateStatus(context.Context, string, domain.DocumentS
 func (f fakeDocumentRepo) SaveClassification(context.Context, string, domain.Classification) error {
 	return nil
 }
+func (f fakeDocumentRepo) UpdateACL(context.Context, string, domain.DocumentVisibility, []string) error {
+	return nil
+}
 
 type fakeEmbedder struct{}
 
@@ -70,6 +73,9 @@ func (f fakeVectorStore) SearchLexical(context.Context, string, int, domain.Sear
 func (f fakeVectorStore) UpdateChunksPayload(context.Context, string, string, map[string]any) error {
 	return nil
 }
+func (f fakeVectorStore) DeleteByDocumentID(context.Context, string) error {
+	return nil
+}
 
 type fakeAnswerGenerator struct{}
 
@@ -77,6 +83,22 @@ func (f fakeAnswerGenerator) GenerateAnswer(context.Context, string, []domain.Re
 	return "rag answer", nil
 }
 
+func (f fakeAnswerGenerator) GenerateCitedAnswer(context.Context, string, []domain.RetrievedChunk) (string, error) {
+	return "chunk content [1]. Unsupported claim [9].", nil
+}
+
+func (f fakeAnswerGenerator) StreamAnswer(_ context.Context, _ string, _ []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	onDelta("rag ")
+	onDelta("answer")
+	return "rag answer", nil
+}
+
+func (f fakeAnswerGenerator) StreamCitedAnswer(_ context.Context, _ string, _ []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	onDelta("chunk content [1]. ")
+	onDelta("Unsupported claim [9].")
+	return "chunk content [1]. Unsupported claim [9].", nil
+}
+
 func (f fakeAnswerGenerator) GenerateFromPrompt(context.Context, string) (string, error) {
 	return "post processed answer", nil
 }
@@ -90,9 +112,12 @@ func (f fakeAnswerGenerator) ChatWithTools(_ context.Context, _ []domain.ChatMes
 }
 
 type fakeAgentService struct {
-	result *domain.AgentRunResult
-	err    error
-	called bool
+	result  *domain.AgentRunResult
+	err     error
+	called  bool
+	lastReq domain.AgentChatRequest
+	// answerDeltas are sent through OnAnswerDelta before the result is returned.
+	answerDeltas []string
 }
 
 func (f *fakeAgentService) SetObsidianWriter(_ ports.ObsidianNoteWriter) {}
@@ -100,9 +125,15 @@ func (f *fakeAgentService) SetObsidianVaults(_ []ports.AgentVaultInfo)    {}
 
 func (f *fakeAgentService) Complete(_ context.Context, req domain.AgentChatRequest, _ domain.ToolStatusCallback) (*domain.AgentRunResult, error) {
 	f.called = true
+	f.lastReq = req
 	if f.err != nil {
 		return nil, f.err
 	}
+	if req.OnAnswerDelta != nil {
+		for _, delta := range f.answerDeltas {
+			req.OnAnswerDelta(delta)
+		}
+	}
 	if f.result != nil {
 		return f.result, nil
 	}
@@ -467,3 +498,219 @@ func TestChatCompletionsAgentModeFallsBackWithoutUserID(t *testing.T) {
 		t.Fatalf("did not expect agent call without metadata.user_id")
 	}
 }
+
+func TestQueryRagReturnsCitationsWhenRequested(t *testing.T) {
+	handler := newTestHandler(config.Config{RAGTopK: 5})
+
+	payload, _ := json.Marshal(map[string]any{"question": "what is in the chunk?", "citations": true})
+	req := httptest.NewRequest(http.MethodPost, "/v1/rag/query", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+
+	var resp apigen.RagAnswerResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Citations == nil || len(*resp.Citations) != 1 {
+		t.Fatalf("expected one resolved citation, got %+v", resp.Citations)
+	}
+	citation := (*resp.Citations)[0]
+	if citation.Marker != 1 || citation.DocumentId != "doc-1" || citation.SpanEnd != len("chunk") {
+		t.Fatalf("unexpected citation: %+v", citation)
+	}
+	if resp.Sentences == nil || len(*resp.Sentences) != 2 {
+		t.Fatalf("expected two sentences, got %+v", resp.Sentences)
+	}
+	if !(*resp.Sentences)[0].Supported || (*resp.Sentences)[1].Supported {
+		t.Fatalf("expected only the first sentence supported, got %+v", *resp.Sentences)
+	}
+}
+
+func TestQueryRagOmitsCitationsByDefault(t *testing.T) {
+	handler := newTestHandler(config.Config{RAGTopK: 5})
+
+	payload, _ := json.Marshal(map[string]any{"question": "what is in the chunk?"})
+	req := httptest.NewRequest(http.MethodPost, "/v1/rag/query", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	var resp apigen.RagAnswerResponse
+	if err := json.NewDecoder(res.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Text != "rag answer" || resp.Citations != nil {
+		t.Fatalf("expected plain answer without citations, got %+v", resp)
+	}
+}
+
+func TestChatCompletionsExposesCitationsInDebug(t *testing.T) {
+	handler := newTestHandler(config.Config{
+		OpenAICompatModelID: "paa-rag-v1",
+		RAGTopK:             5,
+		RAGCitationsEnabled: true,
+	})
+
+	payload, _ := json.Marshal(map[string]any{
+		"model":    "paa-rag-v1",
+		"messages": []map[string]any{{"role": "user", "content": "what is in the chunk?"}},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+
+	var chatResp apigen.ChatCompletionResponse
+	if err := json.NewDecoder(res.Body).Decode(&chatResp); err != nil {
+		t.Fatalf("decode chat response: %v", err)
+	}
+	if chatResp.Debug == nil || chatResp.Debug.Citations == nil || len(*chatResp.Debug.Citations) != 1 {
+		t.Fatalf("expected citations in debug, got %+v", chatResp.Debug)
+	}
+	if chatResp.Debug.Sentences == nil || len(*chatResp.Debug.Sentences) != 2 {
+		t.Fatalf("expected sentences in debug, got %+v", chatResp.Debug.Sentences)
+	}
+}
+
+// readStreamContent returns the content deltas of an SSE chat completion
+// stream in order, plus the number of chunks that carried the assistant role.
+func readStreamContent(t *testing.T, body string) ([]string, int) {
+	t.Helper()
+	var contents []string
+	roles := 0
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		var chunk apigen.ChatCompletionChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil || len(chunk.Choices) == 0 {
+			continue
+		}
+		delta := chunk.Choices[0].Delta
+		if delta.Role != nil {
+			roles++
+		}
+		if delta.Content != nil {
+			contents = append(contents, *delta.Content)
+		}
+	}
+	return contents, roles
+}
+
+func TestChatCompletionsStreamsRAGTokensAsGenerated(t *testing.T) {
+	handler := newTestHandler(config.Config{
+		OpenAICompatModelID:          "paa-rag-v1",
+		OpenAICompatStreamChunkChars: 1000,
+		RAGTopK:                      5,
+	})
+
+	payload, _ := json.Marshal(map[string]any{
+		"model":    "paa-rag-v1",
+		"messages": []map[string]any{{"role": "user", "content": "what is in the chunk?"}},
+		"stream":   true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", res.Code, res.Body.String())
+	}
+
+	body := res.Body.String()
+	contents, roles := readStreamContent(t, body)
+	if len(contents) != 2 || contents[0] != "rag " || contents[1] != "answer" {
+		t.Fatalf("expected generator deltas to be forwarded as is, got %q", contents)
+	}
+	if roles != 1 {
+		t.Fatalf("expected exactly one role chunk, got %d", roles)
+	}
+	if !strings.Contains(body, `"finish_reason":"stop"`) || !strings.HasSuffix(body, "data: [DONE]\n\n") {
+		t.Fatalf("expected finish chunk and DONE marker, got %s", body)
+	}
+}
+
+func TestChatCompletionsStreamsAgentAnswerDeltas(t *testing.T) {
+	agent := &fakeAgentService{
+		result: &domain.AgentRunResult{
+			ConversationID: "conv-agent-1",
+			Answer:         "agent final answer",
+			AnswerStreamed: true,
+		},
+		answerDeltas: []string{"agent ", "final ", "answer"},
+	}
+	handler := newTestHandlerWithAgent(config.Config{
+		OpenAICompatModelID:          "paa-rag-v1",
+		OpenAICompatStreamChunkChars: 1000,
+		AgentModeEnabled:             true,
+		RAGTopK:                      5,
+	}, agent)
+
+	payload, _ := json.Marshal(map[string]any{
+		"model":    "paa-rag-v1",
+		"messages": []map[string]any{{"role": "user", "content": "remember my tasks"}},
+		"metadata": map[string]any{"user_id": "u-1"},
+		"stream":   true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.Code)
+	}
+
+	contents, roles := readStreamContent(t, res.Body.String())
+	if strings.Join(contents, "|") != "agent |final |answer" {
+		t.Fatalf("expected streamed deltas without a repeated answer, got %q", contents)
+	}
+	if roles != 1 {
+		t.Fatalf("expected exactly one role chunk, got %d", roles)
+	}
+}
+
+func TestChatCompletionsStreamsUnstreamedAgentAnswerAtEnd(t *testing.T) {
+	agent := &fakeAgentService{
+		result: &domain.AgentRunResult{ConversationID: "conv-agent-1", Answer: "fallback answer"},
+	}
+	handler := newTestHandlerWithAgent(config.Config{
+		OpenAICompatModelID:          "paa-rag-v1",
+		OpenAICompatStreamChunkChars: 1000,
+		AgentModeEnabled:             true,
+		RAGTopK:                      5,
+	}, agent)
+
+	payload, _ := json.Marshal(map[string]any{
+		"model":    "paa-rag-v1",
+		"messages": []map[string]any{{"role": "user", "content": "remember my tasks"}},
+		"metadata": map[string]any{"user_id": "u-1"},
+		"stream":   true,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+
+	contents, _ := readStreamContent(t, res.Body.String())
+	if len(contents) != 1 || contents[0] != "fallback answer" {
+		t.Fatalf("expected final answer in one chunk, got %q", contents)
+	}
+}
+
+func TestToAPILocator(t *testing.T) {
+	if toAPILocator(domain.Locator{}) != nil {
+		t.Fatal("zero locator must be omitted")
+	}
+	got := toAPILocator(domain.Locator{Sheet: "Budget"})
+	if got == nil || got.Sheet == nil || *got.Sheet != "Budget" || got.Page != nil || got.Slide != nil {
+		t.Fatalf("unexpected locator: %+v", got)
+	}
+}
@@ -33,6 +33,9 @@ func (rt *Router) handleGetRuntimeModels(w http.ResponseWriter, _ *http.Request)
 }
 
 func (rt *Router) handlePutRuntimeModels(w http.ResponseWriter, r *http.Request) {
+	if !requireAdminRequest(w, r) {
+		return
+	}
 	if rt.runtimeModelConfig == nil {
 		writeError(w, http.StatusServiceUnavailable, errors.New("runtime model apply is not configured"))
 		return
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -14,11 +15,20 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/core/usecase"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/chunking"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor"
+	extcode "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/code"
 	extdocx "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/docx"
+	extemail "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/email"
+	extepub "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/epub"
+	exthtml "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/html"
+	extimage "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/image"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/metadata"
+	extodf "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/odf"
 	extpdf "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/pdf"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/plaintext"
+	extpptx "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/pptx"
+	extrtf "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/rtf"
 	extspreadsheet "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/spreadsheet"
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/fswatch"
 	graphpkg "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/graph"
 	graphneo4j "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/graph/neo4j"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/fallback"
@@ -26,8 +36,10 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/openaicompat"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/routing"
 	paamcp "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/mcp"
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/ocr"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/queue/nats"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/repository/postgres"
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/rerank"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/resilience"
 	sourceobsidian "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/source/obsidian"
 	sourceupload "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/source/upload"
@@ -36,6 +48,7 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/vector/qdrant"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/websearch/searxng"
 	"github.com/kirillkom/personal-ai-assistant/internal/observability/metrics"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mimetype"
 )
 
 type App struct {
@@ -46,8 +59,13 @@ type App struct {
 	IngestUC         ports.DocumentIngestor
 	ProcessUC        ports.DocumentProcessor
 	EnrichUC         ports.DocumentEnricher
+	DeleteUC         ports.DocumentDeleter
+	DocumentACLUC    ports.DocumentACLService
 	QueryUC          ports.DocumentQueryService
 	AgentUC          ports.AgentChatService
+	ConversationUC   ports.ConversationService
+	MemoryFactUC     ports.MemoryFactService
+	EvalUC           ports.EvalService
 	ToolRegistry     *paamcp.ToolRegistry
 	MCPClientMgr     *paamcp.ClientManager
 	WebSearcher      ports.WebSearcher
@@ -65,6 +83,18 @@ type App struct {
 	SchedulerUC   *usecase.SchedulerUseCase
 	Storage       ports.ObjectStorage
 
+	VaultSyncUC  *usecase.VaultSyncUseCase
+	BulkIngestUC *usecase.BulkIngestUseCase
+
+	AgentJobUC    *usecase.AgentJobUseCase
+	AgentJobQueue ports.AgentJobQueue
+	AgentRunUC    *usecase.AgentRunUseCase
+
+	// AuthUC is nil unless AUTH_ENABLED is set.
+	AuthUC *usecase.AuthUseCase
+	// OrchestratorUC is nil unless ORCHESTRATOR_ENABLED is set.
+	OrchestratorUC *usecase.OrchestratorUseCase
+
 	closeFn func()
 }
 
@@ -83,7 +113,24 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 	eventStore := postgres.NewEventRepository(db)
 	feedbackStore := postgres.NewFeedbackRepository(db)
 	improvementStore := postgres.NewImprovementRepository(db)
+	evalRepo := postgres.NewEvalRepository(db)
+	lexicalStatsRepo := postgres.NewLexicalStatsRepository(db)
 	scheduleStore := postgres.NewScheduleRepository(db)
+	vaultRepo := postgres.NewVaultRepository(db)
+	ingestJobRepo := postgres.NewIngestJobRepository(db)
+	importLegacyObsidianState(ctx, vaultRepo, cfg)
+
+	var authUC *usecase.AuthUseCase
+	if cfg.AuthEnabled {
+		authUC = usecase.NewAuthUseCase(postgres.NewUserRepository(db))
+		if cfg.AuthBootstrapAdminKey != "" {
+			admin, err := authUC.EnsureBootstrapAdmin(ctx, cfg.AuthBootstrapAdminKey)
+			if err != nil {
+				return nil, fmt.Errorf("ensure bootstrap admin: %w", err)
+			}
+			slog.Info("auth_bootstrap_admin_ready", "user_id", admin.ID)
+		}
+	}
 
 	storage, err := localfs.New(cfg.StoragePath)
 	if err != nil {
@@ -168,6 +215,7 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 
 	// Extra LLM providers (model-based routing via UI).
 	modelProviderMap := make(map[string]string)
+	providerNames := []string{llmProvider}
 	if extras := cfg.ParseExtraProviders(); len(extras) > 0 {
 		generators := map[string]ports.AnswerGenerator{llmProvider: generator}
 		classifiers := map[string]ports.DocumentClassifier{llmProvider: classifier}
@@ -190,6 +238,7 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 			generators[extra.Name] = eGen
 			classifiers[extra.Name] = eCls
 			modelProviderMap["paa-"+extra.Name] = extra.Name
+			providerNames = append(providerNames, extra.Name)
 		}
 
 		generator = routing.NewGenerator(generators, llmProvider, logger)
@@ -202,16 +251,37 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 	if rerankModel == "" {
 		rerankModel = llmModel
 	}
+	// Pointwise mode issues one LLM call per chunk; listwise (opt-in) scores
+	// all candidates in one prompt.
+	listwise := strings.EqualFold(strings.TrimSpace(cfg.RerankLLMMode), "listwise")
 	rerankProvider := strings.ToLower(strings.TrimSpace(cfg.RerankProvider))
 	switch rerankProvider {
 	case "openai-compat":
 		oacClient := openaicompat.New(cfg.RerankProviderURL, cfg.RerankProviderKey, rerankModel)
-		reranker = openaicompat.NewReranker(oacClient)
+		if listwise {
+			reranker = rerank.NewListwiseReranker(openaicompat.NewGenerator(oacClient))
+		} else {
+			reranker = openaicompat.NewReranker(oacClient)
+		}
 	case "ollama":
-		reranker = ollama.NewReranker(ollamaClient)
+		if listwise {
+			reranker = rerank.NewListwiseReranker(ollama.NewGenerator(ollamaClient))
+		} else {
+			reranker = ollama.NewReranker(ollamaClient)
+		}
+	case "cohere", "jina", "tei":
+		api := rerank.APICohere
+		if rerankProvider == "tei" {
+			api = rerank.APITEI
+		}
+		reranker = rerank.NewEndpointReranker(cfg.RerankProviderURL, cfg.RerankProviderKey, cfg.RerankModel, api, cfg.RerankScoreMode)
 	default: // "fallback"
 		reranker = usecase.NewFallbackReranker()
 	}
+	if _, ok := reranker.(*usecase.FallbackReranker); !ok {
+		reranker = rerank.NewDegrading(reranker, usecase.NewFallbackReranker(),
+			time.Duration(cfg.RerankTimeoutMS)*time.Millisecond, logger)
+	}
 
 	// Select embedding provider.
 	var embedder ports.Embedder
@@ -245,6 +315,7 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 
 	vectorDB := qdrant.NewMultiCollectionStore(cfg.QdrantURL, cfg.QdrantCollection, searchOrder, searchOrder, qdrant.Options{
 		ResilienceExecutor: resilienceExecutor,
+		LexicalStats:       lexicalStatsRepo,
 	})
 	memoryVector := qdrant.NewMemoryClientWithOptions(cfg.QdrantURL, cfg.QdrantMemoryCollection, qdrant.Options{
 		ResilienceExecutor: resilienceExecutor,
@@ -258,42 +329,93 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 			return nil, fmt.Errorf("ensure qdrant memory collection: %w", err)
 		}
 	}
-	chunkStrategy := strings.ToLower(strings.TrimSpace(cfg.ChunkStrategy))
-	var defaultChunker ports.Chunker
-	switch chunkStrategy {
-	case "", "fixed":
-		defaultChunker = chunking.NewSplitter(cfg.ChunkSize, cfg.ChunkOverlap)
-	case "markdown", "md":
-		defaultChunker = chunking.NewMarkdownSplitter(cfg.ChunkSize, cfg.ChunkOverlap)
-	default:
-		return nil, fmt.Errorf("unsupported CHUNK_STRATEGY=%q: use fixed or markdown", cfg.ChunkStrategy)
+	defaultChunkConfig := domain.ChunkConfig{
+		Strategy: cfg.ChunkStrategy,
+		Size:     cfg.ChunkSize,
+		Overlap:  cfg.ChunkOverlap,
+		Unit:     cfg.ChunkUnit,
+	}
+	defaultChunker, err := chunking.New(defaultChunkConfig, embedder)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CHUNK_STRATEGY/CHUNK_UNIT: %w", err)
 	}
 
 	chunkerRegistry := chunking.NewRegistry(defaultChunker)
-	if chunkConfigs := config.ParseChunkConfig(cfg.ChunkConfig); chunkConfigs != nil {
-		for sourceType, cc := range chunkConfigs {
-			size := cc.Size
-			if size <= 0 {
-				size = cfg.ChunkSize
-			}
-			overlap := cc.Overlap
-			if overlap < 0 {
-				overlap = cfg.ChunkOverlap
-			}
-			switch strings.ToLower(cc.Strategy) {
-			case "markdown", "md":
-				chunkerRegistry.Register(sourceType, chunking.NewMarkdownSplitter(size, overlap))
-			default:
-				chunkerRegistry.Register(sourceType, chunking.NewSplitter(size, overlap))
-			}
+	chunkConfigs := config.ParseChunkConfig(cfg.ChunkConfig)
+	for sourceType, cc := range chunkConfigs {
+		// Character defaults do not carry over to token-sized chunks.
+		if cc.Size <= 0 && !strings.EqualFold(cc.Unit, "tokens") {
+			cc.Size = cfg.ChunkSize
+		}
+		if cc.Overlap < 0 {
+			cc.Overlap = cfg.ChunkOverlap
+		}
+		chunker, err := chunking.New(cc, embedder)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CHUNK_CONFIG for %s: %w", sourceType, err)
+		}
+		chunkerRegistry.Register(sourceType, chunker)
+		chunkConfigs[sourceType] = cc
+	}
+	// Source files are chunked at declarations regardless of their source.
+	for _, lang := range mimetype.Languages {
+		chunker, err := chunking.NewCode(lang.Name, defaultChunkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CHUNK_UNIT for source code: %w", err)
 		}
+		chunkerRegistry.RegisterMimeType(lang.MimeType, chunker)
+	}
+	chunkingSnapshot := map[string]domain.ChunkConfig{"": defaultChunkConfig}
+	for sourceType, cc := range chunkConfigs {
+		chunkingSnapshot[sourceType] = cc
 	}
 	plaintextExtractor := plaintext.NewExtractor(storage)
 	extractorRegistry := extractor.NewRegistry(plaintextExtractor)
-	extractorRegistry.Register("application/pdf", extpdf.NewExtractor(storage))
+	var ocrEngine ports.OCREngine
+	if cfg.OCREnabled {
+		ocrEngine = ocr.NewTesseract(ocr.TesseractOptions{
+			Binary:    cfg.OCRTesseractPath,
+			PDFToPPM:  cfg.OCRPDFToPPMPath,
+			Languages: cfg.OCRLanguages,
+		})
+	}
+	var imageCaptioner ports.ImageCaptioner
+	if cfg.ImageCaptionModel != "" {
+		switch llmProvider {
+		case "openai-compat", "groq", "together", "openrouter", "cerebras", "huggingface":
+			imageCaptioner = openaicompat.NewCaptioner(openaicompat.New(llmURL, cfg.LLMProviderKey, llmModel,
+				openaicompat.Options{ExtraHeaders: providerHeaders(llmProvider)}), cfg.ImageCaptionModel)
+		default: // "ollama"
+			imageCaptioner = ollama.NewCaptioner(ollamaClient, cfg.ImageCaptionModel)
+		}
+	}
+	extractorRegistry.Register("application/pdf", extpdf.NewExtractorWithOptions(storage, extpdf.Options{
+		OCR:         ocrEngine,
+		MaxOCRPages: cfg.OCRMaxPDFPages,
+	}))
+	extractorRegistry.Register("image/*", extimage.NewExtractor(storage, ocrEngine, imageCaptioner))
 	extractorRegistry.Register("application/vnd.openxmlformats-officedocument.wordprocessingml.document", extdocx.NewExtractor(storage))
 	extractorRegistry.Register("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", extspreadsheet.NewExtractor(storage))
 	extractorRegistry.Register("text/csv", extspreadsheet.NewExtractor(storage))
+	htmlExtractor := exthtml.NewExtractor(storage)
+	extractorRegistry.Register(mimetype.HTML, htmlExtractor)
+	extractorRegistry.Register("application/xhtml+xml", htmlExtractor)
+	extractorRegistry.Register(mimetype.EPUB, extepub.NewExtractor(storage))
+	extractorRegistry.Register(mimetype.PPTX, extpptx.NewExtractor(storage))
+	odfExtractor := extodf.NewExtractor(storage)
+	for _, t := range []string{mimetype.ODT, mimetype.ODS, mimetype.ODP} {
+		extractorRegistry.Register(t, odfExtractor)
+	}
+	rtfExtractor := extrtf.NewExtractor(storage)
+	extractorRegistry.Register(mimetype.RTF, rtfExtractor)
+	extractorRegistry.Register("text/rtf", rtfExtractor)
+	emailExtractor := extemail.NewExtractor(storage)
+	extractorRegistry.Register(mimetype.EML, emailExtractor)
+	extractorRegistry.Register(mimetype.MBOX, emailExtractor)
+	codeExtractor := extcode.NewExtractor(storage)
+	for _, lang := range mimetype.Languages {
+		extractorRegistry.Register(lang.MimeType, codeExtractor)
+	}
 
 	fusionStrategy := domain.FusionStrategy(strings.ToLower(strings.TrimSpace(cfg.RAGFusionStrategy)))
 	if fusionStrategy != domain.FusionStrategyRRF {
@@ -318,7 +440,36 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 	ingestUC := usecase.NewIngestDocumentUseCase(repo, storage, queue, sourceAdapters)
 	metaExtractor := metadata.New()
 	processUC := usecase.NewProcessDocumentUseCase(repo, extractorRegistry, metaExtractor, chunkerRegistry, embedder, vectorDB, queue, graphStore)
+	chunkContextOpts := usecase.ChunkContextOptions{Headers: cfg.ChunkContextHeaders}
+	if cfg.ChunkContextLLM {
+		chunkContextOpts.Generator = generator
+	}
+	processUC.SetChunkContext(chunkContextOpts)
+	processUC.SetAttachmentIngestor(ingestUC)
 	enrichUC := usecase.NewEnrichDocumentUseCase(repo, extractorRegistry, classifier, vectorDB)
+	deleteUC := usecase.NewDeleteDocumentUseCase(repo, storage, vectorDB, graphStore)
+	processUC.SetAttachmentDeleter(deleteUC)
+	documentACLUC := usecase.NewDocumentACLUseCase(repo, vectorDB)
+	var vaultWatcher ports.DirectoryWatcher
+	if cfg.ObsidianWatchEnabled {
+		vaultWatcher = fswatch.NewDirectoryWatcher(fswatch.Options{
+			Debounce: time.Duration(cfg.ObsidianWatchDebounceMS) * time.Millisecond,
+			SkipDir:  usecase.IsIgnoredVaultDir,
+		})
+	}
+	vaultSyncUC := usecase.NewVaultSyncUseCase(vaultRepo, vaultRepo, ingestUC, repo, deleteUC, vaultWatcher, usecase.VaultSyncOptions{
+		VaultsRoot:             cfg.ObsidianVaultsRoot,
+		DefaultIntervalMinutes: cfg.ObsidianDefaultIntervalMinutes,
+		SyncTimeout:            time.Duration(cfg.ObsidianSyncTimeoutSeconds) * time.Second,
+		SyncPoll:               time.Duration(cfg.ObsidianSyncPollSeconds) * time.Second,
+	})
+	bulkIngestUC := usecase.NewBulkIngestUseCase(ingestJobRepo, repo, ingestUC, usecase.BulkIngestOptions{
+		ImportRoot:      cfg.IngestImportRoot,
+		MaxArchiveBytes: int64(cfg.IngestMaxArchiveMB) << 20,
+		MaxFileBytes:    int64(cfg.IngestMaxFileMB) << 20,
+		MaxFiles:        cfg.IngestMaxFiles,
+		Concurrency:     cfg.IngestJobConcurrency,
+	})
 	queryUC := usecase.NewQueryUseCase(embedder, vectorDB, generator, usecase.QueryOptions{
 		RetrievalMode:         domain.RetrievalMode(strings.ToLower(strings.TrimSpace(cfg.RAGRetrievalMode))),
 		HybridCandidates:      cfg.RAGHybridCandidates,
@@ -329,6 +480,13 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 		QueryExpansionEnabled: cfg.QueryExpansionEnabled,
 		QueryExpansionCount:   cfg.QueryExpansionCount,
 		GraphStore:            graphStore,
+		ChunkReader:           vectorDB,
+		ContextExpansion: domain.ContextExpansion{
+			Strategy:  domain.ContextExpansionStrategy(strings.ToLower(strings.TrimSpace(cfg.RAGContextExpansion))),
+			Neighbors: cfg.RAGContextNeighbors,
+		},
+		ContextExpansionBySource: config.ParseContextExpansionConfig(cfg.RAGContextExpansionConfig),
+		ContextTokenBudget:       cfg.RAGContextTokenBudget,
 	})
 	// Web search (optional).
 	var webSearcher ports.WebSearcher
@@ -371,7 +529,7 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 		memoryRepo,
 		memoryVector,
 		webSearcher,
-		nil, // obsidianWriter — set later via SetObsidianWriter after Router is created
+		vaultSyncUC,
 		toolRegistry,
 		domain.AgentLimits{
 			MaxIterations:       cfg.AgentMaxIterations,
@@ -388,6 +546,17 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 	)
 
 	agentUC.SetGraphStore(graphStore)
+	agentUC.SetEventCollector(usecase.NewEventCollector(eventStore))
+
+	agentRunRepo := postgres.NewAgentRunRepository(db)
+	if cfg.AgentTraceEnabled {
+		agentUC.SetRunStore(agentRunRepo)
+	}
+	agentRunUC := usecase.NewAgentRunUseCase(agentRunRepo, queryUC, usecase.AgentRunOptions{
+		MaxIterations: cfg.AgentMaxIterations,
+		Timeout:       time.Duration(cfg.AgentTimeoutSeconds) * time.Second,
+		Providers:     providerNames,
+	})
 
 	// Adaptive model routing.
 	if routingCfg := config.ParseModelRouting(cfg.ModelRouting); routingCfg != nil {
@@ -410,38 +579,69 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 		}
 	}
 
+	agentJobRepo := postgres.NewAgentJobRepository(db)
+	conversationUC := usecase.NewConversationUseCase(conversationRepo, memoryRepo, memoryVector)
+	conversationUC.SetRunStore(agentRunRepo)
+	conversationUC.SetOrchestrations(postgres.NewOrchestrationRepository(db))
+	conversationUC.SetAgentJobs(agentJobRepo)
+	memoryFactUC := usecase.NewMemoryFactUseCase(memoryRepo, generator)
+	agentUC.SetMemoryFacts(memoryFactUC)
+
+	agentJobUC := usecase.NewAgentJobUseCase(agentJobRepo, queue, agentUC, usecase.AgentJobOptions{
+		Timeout:       time.Duration(cfg.AgentJobTimeoutMinutes) * time.Minute,
+		MaxIterations: cfg.AgentJobMaxIterations,
+		MaxAttempts:   cfg.AgentJobMaxAttempts,
+	})
+	if authUC != nil {
+		agentJobUC.SetPrincipals(authUC)
+	}
+
 	// Scheduler (optional).
 	var schedulerUC *usecase.SchedulerUseCase
 	if cfg.SchedulerEnabled {
 		schedulerUC = usecase.NewSchedulerUseCase(scheduleStore, agentUC, generator)
+		schedulerUC.SetAgentJobs(agentJobUC)
+		if authUC != nil {
+			schedulerUC.SetPrincipals(authUC)
+		}
+		agentJobUC.OnFinished(schedulerUC.RecordJobRun)
 		slog.Info("scheduler_enabled", "interval_seconds", cfg.SchedulerCheckIntervalSeconds)
 	}
 
+	evalUC := usecase.NewEvalUseCase(evalRepo, queryUC)
+	evalUC.SetChunking(chunkingSnapshot)
+
 	// Self-improving agent (optional).
 	var selfImproveUC *usecase.SelfImproveUseCase
 	if cfg.SelfImproveEnabled {
 		selfImproveUC = usecase.NewSelfImproveUseCase(
 			eventStore, feedbackStore, improvementStore, generator, cfg.SelfImproveAutoApply,
 		)
+		selfImproveUC.SetEvalCases(evalUC)
 		slog.Info("self_improve_enabled", "interval_hours", cfg.SelfImproveIntervalHours, "auto_apply", cfg.SelfImproveAutoApply)
 	}
 
 	// Multi-agent orchestration.
+	var orchestrator *usecase.OrchestratorUseCase
 	if cfg.OrchestratorEnabled {
 		agentSpecs := config.ParseAgentSpecs(cfg.AgentSpecs)
 		agentRegistry := usecase.NewAgentRegistry(agentSpecs)
 		orchRepo := postgres.NewOrchestrationRepository(db)
-		orchestrator := usecase.NewOrchestratorUseCase(
+		orchestrator = usecase.NewOrchestratorUseCase(
 			agentUC,
 			agentRegistry,
 			memoryVector,
 			embedder,
 			generator,
 			orchRepo,
-			cfg.OrchestratorMaxSteps,
+			usecase.OrchestratorOptions{
+				MaxSteps:    cfg.OrchestratorMaxSteps,
+				MaxParallel: cfg.OrchestratorMaxParallel,
+				StepRetries: cfg.OrchestratorStepRetries,
+			},
 		)
 		agentUC.SetOrchestrator(orchestrator)
-		slog.Info("orchestrator_enabled", "agents", agentRegistry.Names(), "max_steps", cfg.OrchestratorMaxSteps)
+		slog.Info("orchestrator_enabled", "agents", agentRegistry.Names(), "max_steps", cfg.OrchestratorMaxSteps, "max_parallel", cfg.OrchestratorMaxParallel)
 	}
 
 	return &App{
@@ -453,8 +653,13 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 		IngestUC:        ingestUC,
 		ProcessUC:       processUC,
 		EnrichUC:        enrichUC,
+		DeleteUC:        deleteUC,
+		DocumentACLUC:   documentACLUC,
 		QueryUC:         queryUC,
 		AgentUC:         agentUC,
+		ConversationUC:  conversationUC,
+		MemoryFactUC:    memoryFactUC,
+		EvalUC:          evalUC,
 		ToolRegistry:    toolRegistry,
 		MCPClientMgr:    mcpClientMgr,
 		WebSearcher:     webSearcher,
@@ -471,7 +676,18 @@ func New(ctx context.Context, cfg config.Config) (*App, error) {
 		SchedulerUC:   schedulerUC,
 		Storage:       storage,
 
+		VaultSyncUC:  vaultSyncUC,
+		BulkIngestUC: bulkIngestUC,
+
+		AgentJobUC:    agentJobUC,
+		AgentJobQueue: queue,
+		AgentRunUC:    agentRunUC,
+
+		AuthUC:         authUC,
+		OrchestratorUC: orchestrator,
+
 		closeFn: func() {
+			bulkIngestUC.Close()
 			toolRegistry.Close()
 			queue.Close()
 			if cfg.GraphEnabled {
@@ -490,6 +706,38 @@ func (a *App) Close() {
 	}
 }
 
+// importLegacyObsidianState copies vaults and per-note sync state from the
+// JSON/TSV files used before vaults lived in Postgres. It only runs while no
+// vault is registered and renames the config afterwards so it runs once.
+func importLegacyObsidianState(ctx context.Context, store *postgres.VaultRepository, cfg config.Config) {
+	existing, err := store.ListVaults(ctx)
+	if err != nil || len(existing) > 0 {
+		return
+	}
+	vaults, states, err := sourceobsidian.LoadLegacyState(cfg.ObsidianConfigPath, cfg.ObsidianStateDir)
+	if err != nil {
+		slog.Warn("obsidian_legacy_import_failed", "error", err)
+		return
+	}
+	if len(vaults) == 0 {
+		return
+	}
+	for i := range vaults {
+		if err := store.UpsertVault(ctx, &vaults[i]); err != nil {
+			slog.Warn("obsidian_legacy_import_failed", "vault", vaults[i].ID, "error", err)
+			return
+		}
+	}
+	if err := store.UpsertFileStates(ctx, states); err != nil {
+		slog.Warn("obsidian_legacy_import_failed", "error", err)
+		return
+	}
+	if err := os.Rename(cfg.ObsidianConfigPath, cfg.ObsidianConfigPath+".imported"); err != nil {
+		slog.Warn("obsidian_legacy_config_rename_failed", "error", err)
+	}
+	slog.Info("obsidian_legacy_imported", "vaults", len(vaults), "notes", len(states))
+}
+
 // resolveProviderURL returns the default base URL for known providers when no explicit URL is given.
 func resolveProviderURL(provider, explicitURL string) string {
 	if explicitURL != "" {
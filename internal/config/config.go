@@ -29,10 +29,13 @@ type Config struct {
 	LLMProviderKey string
 	LLMModel       string // Model name for external LLM providers (if empty, uses OllamaGenModel)
 
-	RerankProvider    string // "fallback" (default), "ollama", "openai-compat"
+	RerankProvider    string // "fallback" (default), "ollama", "openai-compat", "cohere", "tei"
 	RerankProviderURL string
 	RerankProviderKey string
 	RerankModel       string // Model for reranking (if empty, uses LLMModel → OllamaGenModel)
+	RerankLLMMode     string // "pointwise" (default) or "listwise" for LLM providers
+	RerankTimeoutMS   int    // 0 disables the limit; on timeout or error the fallback reranker answers
+	RerankScoreMode   string // "logit" or "probability" for rerank endpoints; empty uses the API's default
 
 	EmbedProvider    string // "ollama" (default), "openai-compat"
 	EmbedProviderURL string
@@ -55,19 +58,47 @@ type Config struct {
 	ObsidianDefaultIntervalMinutes int
 	ObsidianSyncTimeoutSeconds     int
 	ObsidianSyncPollSeconds        int
+	ObsidianWatchEnabled           bool
+	ObsidianWatchDebounceMS        int
+	ObsidianSyncRunner             string
+
+	IngestImportRoot     string // directory imports are disabled while empty
+	IngestMaxArchiveMB   int
+	IngestMaxFileMB      int
+	IngestMaxFiles       int
+	IngestJobConcurrency int
 
 	ChunkSize           int
 	ChunkOverlap        int
 	ChunkStrategy       string
+	ChunkUnit           string // "chars" (default) or "tokens"
 	ChunkConfig         string // JSON: {"obsidian":{"strategy":"markdown","chunk_size":1200,"overlap":150}}
+	ChunkContextHeaders bool   // embed chunks with document title and heading path
+	ChunkContextLLM     bool   // add an LLM-written context line per chunk
+
+	OCREnabled        bool
+	OCRTesseractPath  string
+	OCRPDFToPPMPath   string
+	OCRLanguages      string // tesseract -l value, e.g. "rus+eng"
+	OCRMaxPDFPages    int
+	ImageCaptionModel string // vision model of LLM_PROVIDER; empty disables captioning
+
 	RAGTopK             int
 	RAGRetrievalMode    string
 	RAGHybridCandidates int
 	RAGFusionStrategy   string
 	RAGFusionRRFK       int
 	RAGRerankTopN       int
+	RAGCitationsEnabled bool
+
+	RAGContextExpansion       string // "none" (default), "neighbors", "section"
+	RAGContextNeighbors       int
+	RAGContextExpansionConfig string // JSON: {"obsidian":{"strategy":"section","max_chunks":6}}
+	RAGContextTokenBudget     int    // approximate tokens of expanded context per answer; 0 = unlimited
 
 	OpenAICompatAPIKey              string
+	AuthEnabled                     bool
+	AuthBootstrapAdminKey           string
 	OpenAICompatModelID             string
 	OpenAICompatContextMessages     int
 	OpenAICompatStreamChunkChars    int
@@ -83,11 +114,14 @@ type Config struct {
 	AgentMemoryTopK            int
 	AgentKnowledgeTopK         int
 	AgentIntentRouterEnabled   bool
+	AgentTraceEnabled          bool
 	ModelRouting string // JSON: {"simple":"llama3.1:8b","complex":"qwen3.5:9b","code":"qwen-coder:7b"}
 
-	AgentSpecs           string // JSON array of AgentSpec
-	OrchestratorEnabled  bool
-	OrchestratorMaxSteps int
+	AgentSpecs              string // JSON array of AgentSpec
+	OrchestratorEnabled     bool
+	OrchestratorMaxSteps    int
+	OrchestratorMaxParallel int
+	OrchestratorStepRetries int
 
 	SelfImproveEnabled       bool
 	SelfImproveIntervalHours int
@@ -99,6 +133,11 @@ type Config struct {
 	SchedulerEnabled              bool
 	SchedulerCheckIntervalSeconds int
 
+	AgentJobTimeoutMinutes int
+	AgentJobMaxIterations  int
+	AgentJobMaxAttempts    int
+	AgentJobConcurrency    int
+
 	LLMFallbackProvider string // fallback provider: "ollama", "openai-compat", "huggingface", etc.
 	LLMFallbackURL      string
 	LLMFallbackKey      string
@@ -169,6 +208,9 @@ func Load() Config {
 		RerankProviderURL: mustEnv("RERANKER_PROVIDER_URL", ""),
 		RerankProviderKey: mustEnv("RERANKER_PROVIDER_KEY", ""),
 		RerankModel:       mustEnv("RERANKER_MODEL", ""),
+		RerankLLMMode:     mustEnv("RERANKER_LLM_MODE", "pointwise"),
+		RerankTimeoutMS:   mustEnvInt("RERANKER_TIMEOUT_MS", 15000),
+		RerankScoreMode:   mustEnv("RERANKER_SCORE_MODE", ""),
 
 		EmbedProvider:    mustEnv("EMBED_PROVIDER", "ollama"),
 		EmbedProviderURL: mustEnv("EMBED_PROVIDER_URL", ""),
@@ -191,19 +233,47 @@ func Load() Config {
 		ObsidianDefaultIntervalMinutes: mustEnvInt("ASSISTANT_OBSIDIAN_DEFAULT_INTERVAL_MINUTES", 15),
 		ObsidianSyncTimeoutSeconds:     mustEnvInt("ASSISTANT_OBSIDIAN_SYNC_TIMEOUT_SECONDS", 120),
 		ObsidianSyncPollSeconds:        mustEnvInt("ASSISTANT_OBSIDIAN_SYNC_POLL_SECONDS", 2),
+		ObsidianWatchEnabled:           mustEnvBool("ASSISTANT_OBSIDIAN_WATCH_ENABLED", true),
+		ObsidianWatchDebounceMS:        mustEnvInt("ASSISTANT_OBSIDIAN_WATCH_DEBOUNCE_MS", 1500),
+		ObsidianSyncRunner:             mustEnv("ASSISTANT_OBSIDIAN_SYNC_RUNNER", "api"),
+
+		IngestImportRoot:     os.Getenv("INGEST_IMPORT_ROOT"),
+		IngestMaxArchiveMB:   mustEnvInt("INGEST_MAX_ARCHIVE_MB", 1024),
+		IngestMaxFileMB:      mustEnvInt("INGEST_MAX_FILE_MB", 100),
+		IngestMaxFiles:       mustEnvInt("INGEST_MAX_FILES", 10000),
+		IngestJobConcurrency: mustEnvInt("INGEST_JOB_CONCURRENCY", 2),
 
 		ChunkSize:           mustEnvInt("CHUNK_SIZE", 900),
 		ChunkOverlap:        mustEnvInt("CHUNK_OVERLAP", 150),
 		ChunkStrategy:       mustEnv("CHUNK_STRATEGY", "fixed"),
+		ChunkUnit:           mustEnv("CHUNK_UNIT", "chars"),
 		ChunkConfig:         os.Getenv("CHUNK_CONFIG"),
+		ChunkContextHeaders: mustEnvBool("CHUNK_CONTEXT_HEADERS", true),
+		ChunkContextLLM:     mustEnvBool("CHUNK_CONTEXT_LLM", false),
+
+		OCREnabled:        mustEnvBool("OCR_ENABLED", false),
+		OCRTesseractPath:  mustEnv("OCR_TESSERACT_PATH", "tesseract"),
+		OCRPDFToPPMPath:   mustEnv("OCR_PDFTOPPM_PATH", "pdftoppm"),
+		OCRLanguages:      mustEnv("OCR_LANGUAGES", "rus+eng"),
+		OCRMaxPDFPages:    mustEnvInt("OCR_MAX_PDF_PAGES", 50),
+		ImageCaptionModel: os.Getenv("IMAGE_CAPTION_MODEL"),
+
 		RAGTopK:             mustEnvInt("RAG_TOP_K", 5),
 		RAGRetrievalMode:    mustEnv("RAG_RETRIEVAL_MODE", "semantic"),
 		RAGHybridCandidates: mustEnvInt("RAG_HYBRID_CANDIDATES", 30),
 		RAGFusionStrategy:   mustEnv("RAG_FUSION_STRATEGY", "rrf"),
 		RAGFusionRRFK:       mustEnvInt("RAG_FUSION_RRF_K", 60),
 		RAGRerankTopN:       mustEnvInt("RAG_RERANK_TOP_N", 20),
+		RAGCitationsEnabled: mustEnvBool("RAG_CITATIONS_ENABLED", false),
+
+		RAGContextExpansion:       mustEnv("RAG_CONTEXT_EXPANSION", "none"),
+		RAGContextNeighbors:       mustEnvInt("RAG_CONTEXT_NEIGHBORS", 1),
+		RAGContextExpansionConfig: os.Getenv("RAG_CONTEXT_EXPANSION_CONFIG"),
+		RAGContextTokenBudget:     mustEnvInt("RAG_CONTEXT_TOKEN_BUDGET", 4000),
 
 		OpenAICompatAPIKey:              mustEnv("OPENAI_COMPAT_API_KEY", ""),
+		AuthEnabled:                     mustEnvBool("AUTH_ENABLED", false),
+		AuthBootstrapAdminKey:           mustEnv("AUTH_BOOTSTRAP_ADMIN_KEY", ""),
 		OpenAICompatModelID:             mustEnv("OPENAI_COMPAT_MODEL_ID", "paa-rag-v1"),
 		OpenAICompatContextMessages:     mustEnvInt("OPENAI_COMPAT_CONTEXT_MESSAGES", 5),
 		OpenAICompatStreamChunkChars:    mustEnvInt("OPENAI_COMPAT_STREAM_CHUNK_CHARS", 120),
@@ -218,11 +288,14 @@ func Load() Config {
 		AgentMemoryTopK:                 mustEnvInt("AGENT_MEMORY_TOP_K", 4),
 		AgentKnowledgeTopK:              mustEnvInt("AGENT_KNOWLEDGE_TOP_K", 5),
 		AgentIntentRouterEnabled:        mustEnvBool("AGENT_INTENT_ROUTER_ENABLED", true),
+		AgentTraceEnabled:               mustEnvBool("AGENT_TRACE_ENABLED", false),
 		ModelRouting:                    os.Getenv("MODEL_ROUTING"),
 
-		AgentSpecs:           os.Getenv("AGENT_SPECS"),
-		OrchestratorEnabled:  mustEnvBool("ORCHESTRATOR_ENABLED", false),
-		OrchestratorMaxSteps: mustEnvInt("ORCHESTRATOR_MAX_STEPS", 8),
+		AgentSpecs:              os.Getenv("AGENT_SPECS"),
+		OrchestratorEnabled:     mustEnvBool("ORCHESTRATOR_ENABLED", false),
+		OrchestratorMaxSteps:    mustEnvInt("ORCHESTRATOR_MAX_STEPS", 8),
+		OrchestratorMaxParallel: mustEnvInt("ORCHESTRATOR_MAX_PARALLEL", 3),
+		OrchestratorStepRetries: mustEnvInt("ORCHESTRATOR_STEP_RETRIES", 1),
 
 		SelfImproveEnabled:       mustEnvBool("SELF_IMPROVE_ENABLED", false),
 		SelfImproveIntervalHours: mustEnvInt("SELF_IMPROVE_INTERVAL_HOURS", 24),
@@ -234,6 +307,11 @@ func Load() Config {
 		SchedulerEnabled:              mustEnvBool("SCHEDULER_ENABLED", false),
 		SchedulerCheckIntervalSeconds: mustEnvInt("SCHEDULER_CHECK_INTERVAL_SECONDS", 60),
 
+		AgentJobTimeoutMinutes: mustEnvInt("AGENT_JOB_TIMEOUT_MINUTES", 30),
+		AgentJobMaxIterations:  mustEnvInt("AGENT_JOB_MAX_ITERATIONS", 30),
+		AgentJobMaxAttempts:    mustEnvInt("AGENT_JOB_MAX_ATTEMPTS", 3),
+		AgentJobConcurrency:    mustEnvInt("AGENT_JOB_CONCURRENCY", 2),
+
 		LLMFallbackProvider: mustEnv("LLM_FALLBACK_PROVIDER", ""),
 		LLMFallbackURL:      mustEnv("LLM_FALLBACK_URL", ""),
 		LLMFallbackKey:      mustEnv("LLM_FALLBACK_KEY", ""),
@@ -347,6 +425,19 @@ func ParseChunkConfig(raw string) map[string]domain.ChunkConfig {
 	return result
 }
 
+// ParseContextExpansionConfig parses the RAG_CONTEXT_EXPANSION_CONFIG JSON env
+// variable into a map of source type → ContextExpansion.
+func ParseContextExpansionConfig(raw string) map[string]domain.ContextExpansion {
+	if raw == "" {
+		return nil
+	}
+	var result map[string]domain.ContextExpansion
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil
+	}
+	return result
+}
+
 func mustEnv(key, fallback string) string {
 	v := os.Getenv(key)
 	if v == "" {
@@ -8,6 +8,8 @@ func TestLoadIncludesAdvancedRetrievalDefaults(t *testing.T) {
 	t.Setenv("RAG_FUSION_STRATEGY", "")
 	t.Setenv("RAG_FUSION_RRF_K", "")
 	t.Setenv("RAG_RERANK_TOP_N", "")
+	t.Setenv("RERANKER_LLM_MODE", "")
+	t.Setenv("RERANKER_TIMEOUT_MS", "")
 
 	cfg := Load()
 	if cfg.RAGRetrievalMode != "semantic" {
@@ -25,6 +27,9 @@ func TestLoadIncludesAdvancedRetrievalDefaults(t *testing.T) {
 	if cfg.RAGRerankTopN != 20 {
 		t.Fatalf("expected default rerank top n 20, got %d", cfg.RAGRerankTopN)
 	}
+	if cfg.RerankLLMMode != "pointwise" || cfg.RerankTimeoutMS != 15000 {
+		t.Fatalf("expected pointwise rerank with 15s timeout, got %q %d", cfg.RerankLLMMode, cfg.RerankTimeoutMS)
+	}
 }
 
 func TestLoadParsesAdvancedRetrievalOverrides(t *testing.T) {
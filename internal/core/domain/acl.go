@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DocumentVisibility controls who may read a document besides its owner.
+type DocumentVisibility string
+
+const (
+	// VisibilityPrivate documents are readable by their owner only.
+	VisibilityPrivate DocumentVisibility = "private"
+	// VisibilityShared documents are readable by the owner and the users and
+	// groups listed in Document.SharedWith.
+	VisibilityShared DocumentVisibility = "shared"
+	// VisibilityPublic documents are readable by everyone.
+	VisibilityPublic DocumentVisibility = "public"
+)
+
+// Valid reports whether v is a known visibility.
+func (v DocumentVisibility) Valid() bool {
+	switch v {
+	case VisibilityPrivate, VisibilityShared, VisibilityPublic:
+		return true
+	}
+	return false
+}
+
+// Access tokens are the unit of document ACLs. A document lists the tokens
+// that grant read access, a principal lists the tokens it holds, and a read is
+// allowed when the two sets intersect. The same strings are mirrored into
+// vector payloads so retrieval can filter on them.
+const (
+	AccessTokenPublic = "*"
+	// AccessTokenAdmin stands in for an empty reader list, e.g. an ownerless
+	// private document. No principal holds it, so only admins, who are not
+	// filtered, can read such documents; an empty acl would read as legacy
+	// public data in the vector store.
+	AccessTokenAdmin   = "admin"
+	accessPrefixUser   = "user:"
+	accessPrefixGroup  = "group:"
+	maxShareTargetSize = 200
+)
+
+// UserAccessToken is the access token held by user id.
+func UserAccessToken(id string) string { return accessPrefixUser + id }
+
+// GroupAccessToken is the access token held by members of group name.
+func GroupAccessToken(name string) string { return accessPrefixGroup + name }
+
+// NormalizeShareTargets validates SharedWith entries ("user:<id>" or
+// "group:<name>"), trimming whitespace and dropping duplicates.
+func NormalizeShareTargets(targets []string) ([]string, error) {
+	out := make([]string, 0, len(targets))
+	seen := make(map[string]struct{}, len(targets))
+	for _, raw := range targets {
+		target := strings.TrimSpace(raw)
+		name, ok := strings.CutPrefix(target, accessPrefixUser)
+		if !ok {
+			name, ok = strings.CutPrefix(target, accessPrefixGroup)
+		}
+		if !ok || strings.TrimSpace(name) == "" || len(target) > maxShareTargetSize {
+			return nil, fmt.Errorf("invalid share target %q: want user:<id> or group:<name>", raw)
+		}
+		if _, dup := seen[target]; dup {
+			continue
+		}
+		seen[target] = struct{}{}
+		out = append(out, target)
+	}
+	return out, nil
+}
+
+// EffectiveVisibility returns the document's visibility, defaulting rows
+// written before ACLs existed: ownerless documents are public, owned ones
+// private.
+func (d Document) EffectiveVisibility() DocumentVisibility {
+	if d.Visibility != "" {
+		return d.Visibility
+	}
+	if d.OwnerID == "" {
+		return VisibilityPublic
+	}
+	return VisibilityPrivate
+}
+
+// AccessTokens returns the tokens that grant read access to the document.
+func (d Document) AccessTokens() []string {
+	visibility := d.EffectiveVisibility()
+	if visibility == VisibilityPublic {
+		return []string{AccessTokenPublic}
+	}
+	tokens := make([]string, 0, 1+len(d.SharedWith))
+	if d.OwnerID != "" {
+		tokens = append(tokens, UserAccessToken(d.OwnerID))
+	}
+	if visibility == VisibilityShared {
+		tokens = append(tokens, d.SharedWith...)
+	}
+	if len(tokens) == 0 {
+		tokens = append(tokens, AccessTokenAdmin)
+	}
+	return tokens
+}
@@ -0,0 +1,98 @@
+package domain
+
+import "time"
+
+const (
+	AgentJobStatusQueued    = "queued"
+	AgentJobStatusRunning   = "running"
+	AgentJobStatusCompleted = "completed"
+	AgentJobStatusFailed    = "failed"
+	AgentJobStatusCanceled  = "canceled"
+)
+
+// What submitted an agent job.
+const (
+	AgentJobSourceAPI      = "api"
+	AgentJobSourceSchedule = "schedule"
+)
+
+// Types of AgentJobEvent.
+const (
+	AgentJobEventStatus = "status" // Status holds the new job status
+	AgentJobEventTool   = "tool"   // Tool and Status report a tool call
+	AgentJobEventAnswer = "answer" // Content holds the final answer
+)
+
+// AgentJobRequest submits an agent run to the worker.
+type AgentJobRequest struct {
+	UserID         string
+	ConversationID string
+	Prompt         string
+	Source         string
+	ScheduleID     string
+}
+
+// AgentJob is an agent run executed by a worker, outside any HTTP request
+// and its timeout. Progress is stored while the job runs so another worker
+// can resume it from its checkpoints after a restart.
+type AgentJob struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	ConversationID string `json:"conversation_id"`
+	Prompt         string `json:"prompt"`
+	Source         string `json:"source"`
+	ScheduleID     string `json:"schedule_id,omitempty"`
+	Status         string `json:"status"`
+	Answer         string `json:"answer,omitempty"`
+	Error          string `json:"error,omitempty"`
+	// Attempts counts the workers that started the job; every attempt after
+	// the first resumes from Checkpoints.
+	Attempts int `json:"attempts"`
+	// Turn is the conversation turn of the job's user message, stored by
+	// the first attempt so later attempts answer it without storing it again.
+	Turn            int        `json:"-"`
+	CancelRequested bool       `json:"cancel_requested,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty"`
+	HeartbeatAt     *time.Time `json:"-"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty"`
+	// Checkpoints hold the finished tool iterations of the run.
+	Checkpoints []AgentCheckpoint `json:"-"`
+}
+
+// Finished reports whether the job reached a final status.
+func (j AgentJob) Finished() bool {
+	switch j.Status {
+	case AgentJobStatusCompleted, AgentJobStatusFailed, AgentJobStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// AgentJobEvent is one progress record of a job. Seq grows with every event
+// and is the cursor for reading the events that follow.
+type AgentJobEvent struct {
+	Seq       int64     `json:"seq"`
+	Type      string    `json:"type"`
+	Tool      string    `json:"tool,omitempty"`
+	Status    string    `json:"status,omitempty"`
+	Content   string    `json:"content,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AgentCheckpoint is one finished tool iteration of an agent run: the calls
+// the model made and their results. Replaying checkpoints restores the
+// agent loop without executing the tools again.
+type AgentCheckpoint struct {
+	// Turn is the conversation turn the run answers.
+	Turn      int              `json:"turn"`
+	ToolCalls []ToolCall       `json:"tool_calls"`
+	Events    []AgentToolEvent `json:"events"`
+}
+
+// AgentCheckpointCallback is called after every agent iteration that
+// executed tools.
+type AgentCheckpointCallback func(checkpoint AgentCheckpoint)
+
+// UserTurnCallback is called once the user message of a run is stored.
+type UserTurnCallback func(turn int) error
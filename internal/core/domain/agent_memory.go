@@ -8,6 +8,7 @@ import (
 type Conversation struct {
 	UserID             string    `json:"user_id"`
 	ConversationID     string    `json:"conversation_id"`
+	Title              string    `json:"title"`
 	CurrentUserTurn    int       `json:"current_user_turn"`
 	LastSummaryEndTurn int       `json:"last_summary_end_turn"`
 	CreatedAt          time.Time `json:"created_at"`
@@ -83,6 +84,26 @@ type AgentChatRequest struct {
 	Messages         []AgentInputMessage   `json:"messages"`
 	OnOrchStep       OrchStepCallback      `json:"-"`
 	OnThinkingDelta  ThinkingDeltaCallback `json:"-"`
+	OnAnswerDelta    AnswerDeltaCallback   `json:"-"`
+	// Profile restricts the run to a specialist's tool allowlist, iteration
+	// budget and model tier. Nil runs the general assistant.
+	Profile *AgentSpec `json:"-"`
+	// TaskContext is appended to the system prompt: the task of an
+	// orchestrated step, related memory and the outputs it depends on.
+	TaskContext string `json:"-"`
+	// Timeout and MaxIterations override AgentLimits for runs outside an
+	// HTTP request, such as agent jobs.
+	Timeout       time.Duration `json:"-"`
+	MaxIterations int           `json:"-"`
+	// Resume replays the checkpoints of an interrupted run of the same turn
+	// before planning continues; OnCheckpoint receives new ones.
+	Resume       []AgentCheckpoint       `json:"-"`
+	OnCheckpoint AgentCheckpointCallback `json:"-"`
+	// Turn answers a user message an earlier attempt already stored under
+	// that turn. Without it the message is stored and its turn is passed to
+	// OnUserTurn, whose error stops the run.
+	Turn       int              `json:"-"`
+	OnUserTurn UserTurnCallback `json:"-"`
 }
 
 type AgentToolEvent struct {
@@ -101,6 +122,15 @@ type AgentRunResult struct {
 	ToolsInvoked   []string         `json:"tools_invoked,omitempty"`
 	FallbackReason string           `json:"fallback_reason,omitempty"`
 	ToolEvents     []AgentToolEvent `json:"tool_events,omitempty"`
+	// AnswerStreamed reports that the final answer was already delivered
+	// through AgentChatRequest.OnAnswerDelta while it was generated.
+	AnswerStreamed bool `json:"answer_streamed,omitempty"`
+	// OrchestrationID and OrchestrationSteps are set when specialists
+	// answered the request through the orchestrator.
+	OrchestrationID    string              `json:"orchestration_id,omitempty"`
+	OrchestrationSteps []OrchestrationStep `json:"orchestration_steps,omitempty"`
+	// RunID identifies the recorded trace of the run, when tracing is on.
+	RunID string `json:"run_id,omitempty"`
 }
 
 type AgentPlanStep struct {
@@ -131,6 +161,22 @@ func ThinkingCallbackFromContext(ctx context.Context) ThinkingDeltaCallback {
 	return cb
 }
 
+// AnswerDeltaCallback is called with incremental answer text as the LLM produces it.
+type AnswerDeltaCallback func(text string)
+
+type answerCtxKey struct{}
+
+// ContextWithAnswerCallback attaches an answer delta callback to a context.
+func ContextWithAnswerCallback(ctx context.Context, cb AnswerDeltaCallback) context.Context {
+	return context.WithValue(ctx, answerCtxKey{}, cb)
+}
+
+// AnswerCallbackFromContext extracts an answer delta callback from context.
+func AnswerCallbackFromContext(ctx context.Context) AnswerDeltaCallback {
+	cb, _ := ctx.Value(answerCtxKey{}).(AnswerDeltaCallback)
+	return cb
+}
+
 // WebSearchResult represents a single result from a web search engine.
 type WebSearchResult struct {
 	Title   string `json:"title"`
@@ -0,0 +1,71 @@
+package domain
+
+import "time"
+
+// Kinds of AgentRunStep.
+const (
+	AgentRunStepLLM      = "llm_call"  // one ChatWithTools request and its response
+	AgentRunStepTool     = "tool_call" // one tool call and the output the model saw
+	AgentRunStepFallback = "fallback"  // an answer produced outside the tool loop
+)
+
+// AgentRun is the trace of one agent turn: how it was routed, every model
+// call with its request and response, every tool call with its arguments
+// and result, and how it ended. Runs are recorded for debugging and can be
+// replayed against another model or system prompt.
+type AgentRun struct {
+	ID             string `json:"id"`
+	UserID         string `json:"user_id"`
+	ConversationID string `json:"conversation_id"`
+	// Input is the user message the run answered.
+	Input          string  `json:"input"`
+	Intent         string  `json:"intent"`
+	Tier           string  `json:"tier,omitempty"`
+	Model          string  `json:"model,omitempty"`
+	SystemPrompt   string  `json:"system_prompt"`
+	Answer         string  `json:"answer"`
+	FallbackReason string  `json:"fallback_reason,omitempty"`
+	Iterations     int     `json:"iterations"`
+	DurationMS     float64 `json:"duration_ms"`
+	// ReplayOf is the run this one replayed; replays never touch the
+	// conversation.
+	ReplayOf string `json:"replay_of,omitempty"`
+	// OrchestrationID is set when specialists answered the run; their own
+	// runs are linked from the steps of the orchestration.
+	OrchestrationID string    `json:"orchestration_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	// Tools and Steps are omitted when runs are listed.
+	Tools []ToolSchema   `json:"tools,omitempty"`
+	Steps []AgentRunStep `json:"steps,omitempty"`
+}
+
+// AgentRunStep is one recorded step of an agent run. Messages of an
+// llm_call step hold only the request messages that no earlier call sent,
+// so the full request of a call is the Messages of all calls up to it.
+type AgentRunStep struct {
+	Seq        int            `json:"seq"`
+	Kind       string         `json:"kind"`
+	Iteration  int            `json:"iteration"`
+	Messages   []ChatMessage  `json:"messages,omitempty"`
+	Content    string         `json:"content,omitempty"`
+	ToolCalls  []ToolCall     `json:"tool_calls,omitempty"`
+	Tool       string         `json:"tool,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+	Arguments  map[string]any `json:"arguments,omitempty"`
+	Output     string         `json:"output,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	// Cached marks a tool result served from the tool cache, Stubbed one
+	// taken from the replayed run instead of executing the tool.
+	Cached     bool    `json:"cached,omitempty"`
+	Stubbed    bool    `json:"stubbed,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMS float64 `json:"duration_ms"`
+}
+
+// AgentReplayOptions change a replayed run. Empty fields keep the values
+// of the recorded run.
+type AgentReplayOptions struct {
+	// Model is the LLM provider the replay routes its calls to.
+	Model        string
+	SystemPrompt string
+}
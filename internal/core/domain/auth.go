@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// User is an account that owns API keys and per-user data.
+type User struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Admin     bool      `json:"admin"`
+	Groups    []string  `json:"groups,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKey describes an issued key. The plaintext key is never stored; only its
+// hash and a short prefix used to recognise the key in listings.
+type APIKey struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID string `json:"user_id"`
+	KeyID  string `json:"key_id,omitempty"`
+	Admin  bool   `json:"admin"`
+	// Groups the user belongs to, for documents shared with "group:<name>".
+	Groups []string `json:"groups,omitempty"`
+}
+
+// CanAccess reports whether the principal may touch data owned by ownerID.
+// Admins can access everything.
+func (p Principal) CanAccess(ownerID string) bool {
+	return p.Admin || p.UserID == ownerID
+}
+
+// AccessTokens returns the document access tokens the principal holds.
+func (p Principal) AccessTokens() []string {
+	tokens := make([]string, 0, 2+len(p.Groups))
+	tokens = append(tokens, AccessTokenPublic, UserAccessToken(p.UserID))
+	for _, g := range p.Groups {
+		tokens = append(tokens, GroupAccessToken(g))
+	}
+	return tokens
+}
+
+// ReadScope returns the access tokens to restrict document reads with: nil
+// for admins (no restriction), otherwise AccessTokens.
+func (p Principal) ReadScope() []string {
+	if p.Admin {
+		return nil
+	}
+	return p.AccessTokens()
+}
+
+// CanRead reports whether the principal may read doc under its ACL.
+func (p Principal) CanRead(doc Document) bool {
+	if p.Admin {
+		return true
+	}
+	held := p.AccessTokens()
+	for _, token := range doc.AccessTokens() {
+		for _, h := range held {
+			if token == h {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type principalCtxKey struct{}
+
+// ContextWithPrincipal attaches the authenticated principal to a context.
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromContext returns the authenticated principal, if any.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(Principal)
+	return p, ok
+}
@@ -0,0 +1,16 @@
+package domain
+
+// ConversationTranscript is a conversation together with all of its messages
+// in chronological order.
+type ConversationTranscript struct {
+	Conversation
+	Messages []ConversationMessage `json:"messages"`
+}
+
+// ConversationExportFormat selects how a transcript is exported.
+type ConversationExportFormat string
+
+const (
+	ConversationExportMarkdown ConversationExportFormat = "markdown"
+	ConversationExportJSON     ConversationExportFormat = "json"
+)
@@ -22,23 +22,37 @@ type DocumentMetadata struct {
 }
 
 type Document struct {
-	ID          string         `json:"id"`
-	Filename    string         `json:"filename"`
-	MimeType    string         `json:"mime_type"`
-	StoragePath string         `json:"storage_path"`
-	Category    string         `json:"category,omitempty"`
-	Subcategory string         `json:"subcategory,omitempty"`
-	Tags        []string       `json:"tags,omitempty"`
-	Confidence  float64        `json:"confidence,omitempty"`
-	Summary     string         `json:"summary,omitempty"`
-	SourceType  string         `json:"source_type"`
-	Title       string         `json:"title"`
-	Headers     []string       `json:"headers,omitempty"`
-	Path        string         `json:"path"`
-	Status      DocumentStatus `json:"status"`
-	Error       string         `json:"error,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
+	ID          string             `json:"id"`
+	Filename    string             `json:"filename"`
+	MimeType    string             `json:"mime_type"`
+	StoragePath string             `json:"storage_path"`
+	Category    string             `json:"category,omitempty"`
+	Subcategory string             `json:"subcategory,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	Confidence  float64            `json:"confidence,omitempty"`
+	Summary     string             `json:"summary,omitempty"`
+	SourceType  string             `json:"source_type"`
+	Title       string             `json:"title"`
+	Headers     []string           `json:"headers,omitempty"`
+	Path        string             `json:"path"`
+	SourceID    string             `json:"source_id,omitempty"` // stable source identity, e.g. obsidian vault + path
+	ParentID    string             `json:"parent_id,omitempty"` // containing document, e.g. the email of an attachment
+	OwnerID     string             `json:"owner_id,omitempty"`  // uploading user; empty for shared sources such as vaults
+	Visibility  DocumentVisibility `json:"visibility,omitempty"`
+	SharedWith  []string           `json:"shared_with,omitempty"`  // ACL entries: "user:<id>" or "group:<name>"
+	ContentHash string             `json:"content_hash,omitempty"` // hex SHA-256 of the stored content
+	Status      DocumentStatus     `json:"status"`
+	Error       string             `json:"error,omitempty"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+}
+
+// Attachment is a file carried inside another document, such as an email
+// attachment. It is ingested as a child document of its container.
+type Attachment struct {
+	Filename string
+	MimeType string
+	Data     []byte
 }
 
 type Classification struct {
@@ -48,3 +62,32 @@ type Classification struct {
 	Confidence  float64  `json:"confidence"`
 	Summary     string   `json:"summary"`
 }
+
+// DocumentFilter selects documents for bulk operations. Empty fields match everything.
+type DocumentFilter struct {
+	SourceTypes []string         `json:"source_types,omitempty"`
+	Categories  []string         `json:"categories,omitempty"`
+	Statuses    []DocumentStatus `json:"statuses,omitempty"`
+	PathPrefix  string           `json:"path_prefix,omitempty"`
+	ParentIDs   []string         `json:"parent_ids,omitempty"`
+	// OwnerIDs scopes the selection to documents of these owners ("" matches
+	// shared documents). It is an access restriction, not a selection
+	// criterion, so IsEmpty ignores it.
+	OwnerIDs []string `json:"owner_ids,omitempty"`
+	// AccessTokens restricts the selection to documents readable with any of
+	// these tokens (see Document.AccessTokens). Like OwnerIDs, IsEmpty
+	// ignores it.
+	AccessTokens []string `json:"access_tokens,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no criteria set.
+func (f DocumentFilter) IsEmpty() bool {
+	return len(f.SourceTypes) == 0 && len(f.Categories) == 0 && len(f.Statuses) == 0 && f.PathPrefix == "" &&
+		len(f.ParentIDs) == 0
+}
+
+// DocumentDeleteResult reports the outcome of a bulk delete.
+type DocumentDeleteResult struct {
+	Deleted []string          `json:"deleted"`
+	Failed  map[string]string `json:"failed,omitempty"`
+}
@@ -10,6 +10,28 @@ var (
 	ErrInvalidInput     = errors.New("invalid input")
 	ErrUnauthorized     = errors.New("unauthorized")
 	ErrTemporary        = errors.New("temporary failure")
+	ErrVaultNotFound    = errors.New("vault not found")
+	ErrConflict         = errors.New("conflict")
+	ErrForbidden        = errors.New("forbidden")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrAPIKeyNotFound   = errors.New("api key not found")
+	// ErrConversationNotFound is also returned for another user's conversation.
+	ErrConversationNotFound = errors.New("conversation not found")
+	// ErrMemoryFactNotFound is also returned for another user's fact.
+	ErrMemoryFactNotFound = errors.New("memory fact not found")
+	ErrEvalCaseNotFound   = errors.New("eval case not found")
+	ErrEvalRunNotFound    = errors.New("eval run not found")
+	// ErrIngestJobNotFound is also returned for another user's job.
+	ErrIngestJobNotFound = errors.New("ingest job not found")
+	// ErrOrchestrationNotFound is also returned for another user's orchestration.
+	ErrOrchestrationNotFound = errors.New("orchestration not found")
+	// ErrAgentJobNotFound is also returned for another user's job.
+	ErrAgentJobNotFound = errors.New("agent job not found")
+	// ErrAgentJobNotOwned is returned to a worker whose job was taken over
+	// by another worker after its heartbeat went stale.
+	ErrAgentJobNotOwned = errors.New("agent job not owned")
+	// ErrAgentRunNotFound is also returned for another user's run.
+	ErrAgentRunNotFound = errors.New("agent run not found")
 )
 
 // WrapError preserves typed semantic errors with operation context.
@@ -0,0 +1,134 @@
+package domain
+
+import "time"
+
+// EvalCaseSource records where an eval case came from.
+type EvalCaseSource string
+
+const (
+	// EvalCaseSourceAPI cases were written through /v1/eval/cases.
+	EvalCaseSourceAPI EvalCaseSource = "api"
+	// EvalCaseSourceSelfImprove cases were proposed by the self-improve
+	// analysis (category eval_case).
+	EvalCaseSourceSelfImprove EvalCaseSource = "self_improve"
+)
+
+// EvalCase is a retrieval test question with the documents a good answer
+// must be grounded in. A retrieved document is relevant when its ID is in
+// ExpectedDocumentIDs or its filename is in ExpectedFilenames; filenames keep
+// cases valid across re-ingestion.
+type EvalCase struct {
+	ID                  string         `json:"id"`
+	Question            string         `json:"question"`
+	ExpectedDocumentIDs []string       `json:"expected_document_ids,omitempty"`
+	ExpectedFilenames   []string       `json:"expected_filenames,omitempty"`
+	GroundTruth         string         `json:"ground_truth,omitempty"`
+	Source              EvalCaseSource `json:"source"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// EvalMetrics are document-level retrieval metrics at cutoff k. For a single
+// case MRR holds the reciprocal rank of the first relevant document; for a
+// run every field is the mean over its scored cases.
+type EvalMetrics struct {
+	PrecisionAtK float64 `json:"precision_at_k"`
+	RecallAtK    float64 `json:"recall_at_k"`
+	MRR          float64 `json:"mrr"`
+	NDCG         float64 `json:"ndcg"`
+}
+
+// EvalCaseResult is the outcome of one case within a run.
+type EvalCaseResult struct {
+	CaseID   string `json:"case_id"`
+	Question string `json:"question"`
+	// Retrieved lists the distinct documents in rank order, by filename
+	// when known and by document ID otherwise.
+	Retrieved []string    `json:"retrieved"`
+	Relevant  int         `json:"relevant"`
+	Expected  int         `json:"expected"`
+	Metrics   EvalMetrics `json:"metrics"`
+	Answer    string      `json:"answer,omitempty"`
+	LatencyMs int64       `json:"latency_ms"`
+	Error     string      `json:"error,omitempty"`
+}
+
+const (
+	EvalRunStatusRunning = "running"
+	EvalRunStatusOK      = "ok"
+	EvalRunStatusError   = "error"
+)
+
+// EvalGate compares a run against a baseline run. It fails when any metric
+// dropped by more than MaxDrop.
+type EvalGate struct {
+	BaselineRunID string   `json:"baseline_run_id"`
+	MaxDrop       float64  `json:"max_drop"`
+	Passed        bool     `json:"passed"`
+	Failures      []string `json:"failures,omitempty"`
+}
+
+// EvalRun is one evaluation of every stored case under a single retrieval
+// configuration. Config holds the effective settings, so runs stay
+// comparable after the server defaults change.
+type EvalRun struct {
+	ID     string          `json:"id"`
+	Status string          `json:"status"`
+	Config RetrievalConfig `json:"config"`
+	// Chunking records the chunker settings the corpus was ingested with,
+	// keyed by source type ("" is the default), so runs over re-ingested
+	// corpora can be compared.
+	Chunking        map[string]ChunkConfig `json:"chunking,omitempty"`
+	K               int                    `json:"k"`
+	GenerateAnswers bool                   `json:"generate_answers"`
+	CaseCount       int                    `json:"case_count"`
+	Failed          int                    `json:"failed"`
+	Metrics         EvalMetrics            `json:"metrics"`
+	Results         []EvalCaseResult       `json:"results,omitempty"`
+	Gate            *EvalGate              `json:"gate,omitempty"`
+	Error           string                 `json:"error,omitempty"`
+	StartedAt       time.Time              `json:"started_at"`
+	FinishedAt      *time.Time             `json:"finished_at,omitempty"`
+}
+
+// EvalRunRequest starts one run per entry of Configs; an empty list runs the
+// server's current retrieval configuration. A non-empty BaselineRunID gates
+// every run against that run.
+type EvalRunRequest struct {
+	Configs         []RetrievalConfig `json:"configs,omitempty"`
+	K               int               `json:"k,omitempty"`
+	GenerateAnswers bool              `json:"generate_answers,omitempty"`
+	BaselineRunID   string            `json:"baseline_run_id,omitempty"`
+	MaxDrop         float64           `json:"max_drop,omitempty"`
+}
+
+// EvalChange classifies how a case moved between two runs.
+type EvalChange string
+
+const (
+	EvalChangeImproved  EvalChange = "improved"
+	EvalChangeRegressed EvalChange = "regressed"
+	EvalChangeAdded     EvalChange = "added"
+	EvalChangeRemoved   EvalChange = "removed"
+)
+
+// EvalCaseDiff is a case whose score differs between the compared runs.
+// Base or Head is nil when the case ran in only one of them.
+type EvalCaseDiff struct {
+	CaseID   string       `json:"case_id"`
+	Question string       `json:"question"`
+	Change   EvalChange   `json:"change"`
+	Base     *EvalMetrics `json:"base,omitempty"`
+	Head     *EvalMetrics `json:"head,omitempty"`
+}
+
+// EvalRunDiff compares run Head against run Base. Delta is Head minus Base;
+// Cases lists only the cases that changed.
+type EvalRunDiff struct {
+	BaseRunID string         `json:"base_run_id"`
+	HeadRunID string         `json:"head_run_id"`
+	Base      EvalMetrics    `json:"base"`
+	Head      EvalMetrics    `json:"head"`
+	Delta     EvalMetrics    `json:"delta"`
+	Cases     []EvalCaseDiff `json:"cases"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "time"
+
+// IngestJobKind names what a bulk ingest job expands into documents.
+type IngestJobKind string
+
+const (
+	IngestJobArchive   IngestJobKind = "archive"   // uploaded ZIP or TAR
+	IngestJobDirectory IngestJobKind = "directory" // server-side folder
+)
+
+const (
+	IngestJobStatusQueued   = "queued"
+	IngestJobStatusRunning  = "running"
+	IngestJobStatusDone     = "done"
+	IngestJobStatusPartial  = "partial" // finished with failed files
+	IngestJobStatusFailed   = "failed"  // could not be read at all, or lost to a restart
+	IngestJobStatusCanceled = "canceled"
+)
+
+const (
+	IngestFileIngested = "ingested"
+	IngestFileSkipped  = "skipped" // content already ingested
+	IngestFileFailed   = "failed"
+)
+
+// IngestJob is one bulk ingestion of an archive or directory. Counts are
+// updated while the job runs; Total is known once the source is listed.
+type IngestJob struct {
+	ID         string        `json:"id"`
+	Kind       IngestJobKind `json:"kind"`
+	Source     string        `json:"source"` // archive filename or directory path
+	OwnerID    string        `json:"owner_id,omitempty"`
+	Status     string        `json:"status"`
+	Total      int           `json:"total"`
+	Ingested   int           `json:"ingested"`
+	Skipped    int           `json:"skipped"`
+	Failed     int           `json:"failed"`
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	// HeartbeatAt is the last time the process running the job reported in.
+	HeartbeatAt *time.Time `json:"-"`
+	// Files is filled only when a single job is read.
+	Files []IngestJobFile `json:"files,omitempty"`
+}
+
+// Finished reports whether the job reached a final status.
+func (j IngestJob) Finished() bool {
+	switch j.Status {
+	case IngestJobStatusDone, IngestJobStatusPartial, IngestJobStatusFailed, IngestJobStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// IngestJobFile is the outcome of one file of a job. Path is relative to the
+// archive or directory root.
+type IngestJobFile struct {
+	Path       string `json:"path"`
+	Status     string `json:"status"`
+	DocumentID string `json:"document_id,omitempty"`
+	Hash       string `json:"hash,omitempty"`
+	Size       int64  `json:"size"`
+	Error      string `json:"error,omitempty"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// MemoryFactSource records who asked for a fact to be remembered.
+type MemoryFactSource string
+
+const (
+	// MemoryFactSourceUser facts were written through the /v1/memories API.
+	MemoryFactSourceUser MemoryFactSource = "user"
+	// MemoryFactSourceAgent facts were saved by the agent's memory tool.
+	MemoryFactSourceAgent MemoryFactSource = "agent"
+)
+
+// MemoryFact is an explicit piece of long-term memory about a user, such as
+// "my staging DB is db-stage-2". Unlike MemorySummary it is never generated
+// automatically and can be edited or forgotten.
+type MemoryFact struct {
+	ID        string           `json:"id"`
+	UserID    string           `json:"user_id"`
+	Content   string           `json:"content"`
+	Source    MemoryFactSource `json:"source"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// MemoryFactAction tells what writing a fact did to the user's memory.
+type MemoryFactAction string
+
+const (
+	// MemoryFactCreated means the fact was new and has been stored.
+	MemoryFactCreated MemoryFactAction = "created"
+	// MemoryFactDuplicate means an equivalent fact already existed and was
+	// kept as is.
+	MemoryFactDuplicate MemoryFactAction = "duplicate"
+	// MemoryFactReplaced means the fact contradicted or refined an existing
+	// one, which has been overwritten.
+	MemoryFactReplaced MemoryFactAction = "replaced"
+)
+
+// MemoryFactWrite is the outcome of remembering a fact.
+type MemoryFactWrite struct {
+	Fact   MemoryFact       `json:"fact"`
+	Action MemoryFactAction `json:"action"`
+	// Previous holds the overwritten content when Action is replaced.
+	Previous string `json:"previous,omitempty"`
+}
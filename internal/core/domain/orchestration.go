@@ -1,53 +1,115 @@
 package domain
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // AgentSpec defines a specialist agent's configuration.
 type AgentSpec struct {
-	Name          string   `json:"name"`
-	SystemPrompt  string   `json:"system_prompt"`
+	Name         string `json:"name"`
+	SystemPrompt string `json:"system_prompt"`
+	// Tools is the allowlist of tools the specialist may call; an empty list
+	// means no tools.
 	Tools         []string `json:"tools"`
 	MaxIterations int      `json:"max_iterations"`
+	// ModelTier selects the model from ModelRouting; empty keeps the tier
+	// classified from the request.
+	ModelTier ComplexityTier `json:"model_tier,omitempty"`
 }
 
-// OrchestrationPlanStep is a planned agent invocation.
+// AllowsTool reports whether the specialist may call the named tool.
+func (s AgentSpec) AllowsTool(name string) bool {
+	return slices.Contains(s.Tools, name)
+}
+
+// Failure policies of a plan step, applied once its retries are exhausted.
+const (
+	// OrchStepFailureSkip lets dependent steps run without the failed output.
+	OrchStepFailureSkip = "skip"
+	// OrchStepFailureAbort stops the orchestration.
+	OrchStepFailureAbort = "abort"
+)
+
+// StepConversationID returns the conversation in which a step of an
+// orchestration started from conversationID runs.
+func StepConversationID(conversationID, orchID, stepID string) string {
+	return StepConversationPrefix(conversationID) + orchID + "_" + stepID
+}
+
+// StepConversationPrefix starts the ids of every step conversation of the
+// orchestrations started from conversationID.
+func StepConversationPrefix(conversationID string) string {
+	return conversationID + "_orch_"
+}
+
+// OrchestrationPlanStep is a planned agent invocation. Steps form a
+// dependency graph: a step starts once every step in DependsOn has finished
+// and receives their outputs, so steps without dependencies run in parallel.
 type OrchestrationPlanStep struct {
-	Agent string `json:"agent"`
-	Task  string `json:"task"`
+	ID        string   `json:"id,omitempty"`
+	Agent     string   `json:"agent"`
+	Task      string   `json:"task"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	OnFailure string   `json:"on_failure,omitempty"`
+	// Retries overrides how often the step is retried before OnFailure
+	// applies; nil keeps the orchestrator default.
+	Retries *int `json:"retries,omitempty"`
+	// Synthesis marks the step whose output is the final answer.
+	Synthesis bool `json:"synthesis,omitempty"`
 }
 
-// OrchestrationStep is a completed agent execution.
+// OrchestrationStep is a finished agent execution: "completed", "failed" or
+// "skipped" when the orchestration was aborted before the step could run.
+// RunID is the recorded trace of the specialist run, when tracing is on.
 type OrchestrationStep struct {
 	Index      int       `json:"index"`
+	StepID     string    `json:"step_id,omitempty"`
 	Agent      string    `json:"agent"`
 	Task       string    `json:"task"`
 	Result     string    `json:"result"`
 	Status     string    `json:"status"`
+	Attempts   int       `json:"attempts,omitempty"`
+	RunID      string    `json:"run_id,omitempty"`
 	StartedAt  time.Time `json:"started_at"`
 	DurationMS float64   `json:"duration_ms"`
 }
 
 // Orchestration represents a full multi-agent execution.
 type Orchestration struct {
-	ID             string
-	UserID         string
-	ConversationID string
-	Request        string
-	Plan           []OrchestrationPlanStep
-	Steps          []OrchestrationStep
-	Status         string // "running", "completed", "failed"
-	CreatedAt      time.Time
-	CompletedAt    *time.Time
+	ID             string                  `json:"id"`
+	UserID         string                  `json:"user_id"`
+	ConversationID string                  `json:"conversation_id"`
+	Request        string                  `json:"request"`
+	Plan           []OrchestrationPlanStep `json:"plan"`
+	Steps          []OrchestrationStep     `json:"steps"`
+	Status         string                  `json:"status"` // "running", "completed", "failed"
+	CreatedAt      time.Time               `json:"created_at"`
+	CompletedAt    *time.Time              `json:"completed_at,omitempty"`
 }
 
 // OrchestrationStatus is sent via SSE during orchestration.
 type OrchestrationStatus struct {
-	OrchestrationID string `json:"orchestration_id"`
-	StepIndex       int    `json:"step_index"`
-	AgentName       string `json:"agent_name"`
-	Task            string `json:"task"`
-	Status          string `json:"status"`
-	Result          string `json:"result,omitempty"`
+	OrchestrationID string  `json:"orchestration_id"`
+	StepIndex       int     `json:"step_index"`
+	StepID          string  `json:"step_id,omitempty"`
+	AgentName       string  `json:"agent_name"`
+	Task            string  `json:"task"`
+	Status          string  `json:"status"`
+	Result          string  `json:"result,omitempty"`
+	DurationMS      float64 `json:"duration_ms,omitempty"`
+}
+
+// OrchestrationSubscription follows the step events of one orchestration.
+type OrchestrationSubscription struct {
+	// Events holds the events published before the subscription started.
+	Events []OrchestrationStatus
+	// Updates delivers the following events and is closed once the
+	// orchestration finishes; it is nil when it had already finished.
+	Updates <-chan OrchestrationStatus
+	// Cancel releases the subscription and must be called once the caller
+	// stops reading Updates.
+	Cancel func()
 }
 
 // OrchStepCallback is called when an orchestration step starts or completes.
@@ -14,21 +14,74 @@ const (
 	FusionStrategyRRF FusionStrategy = "rrf"
 )
 
+// RetrievalConfig selects how context is retrieved for a question. Zero
+// fields keep the server's configured value.
+type RetrievalConfig struct {
+	Mode             RetrievalMode  `json:"mode,omitempty"`
+	HybridCandidates int            `json:"hybrid_candidates,omitempty"`
+	FusionStrategy   FusionStrategy `json:"fusion_strategy,omitempty"`
+	FusionRRFK       int            `json:"fusion_rrf_k,omitempty"`
+	RerankTopN       int            `json:"rerank_top_n,omitempty"`
+	QueryExpansion   *bool          `json:"query_expansion,omitempty"`
+}
+
 type SearchFilter struct {
 	SourceTypes []string // filter by source_type (empty = all)
 	Categories  []string // filter by category (empty = all)
 	Tags        []string // any-match on tags (empty = all)
 	PathPrefix  string   // prefix match on path
 	DocumentIDs []string // filter by doc_id (empty = all)
+	// AccessTokens restricts results to documents readable with any of these
+	// tokens (see Document.AccessTokens); nil = unrestricted.
+	AccessTokens []string
+}
+
+// LexicalStats are the BM25 corpus statistics of one vector collection,
+// where every indexed chunk counts as a document. Used as a delta, negative
+// values remove documents.
+type LexicalStats struct {
+	Docs        int64            `json:"docs"`
+	TotalLength int64            `json:"total_length"`
+	DocFreq     map[string]int64 `json:"doc_freq,omitempty"`
+}
+
+type ContextExpansionStrategy string
+
+const (
+	ContextExpansionNone      ContextExpansionStrategy = "none"
+	ContextExpansionNeighbors ContextExpansionStrategy = "neighbors"
+	ContextExpansionSection   ContextExpansionStrategy = "section"
+)
+
+// ContextExpansion widens each retrieved hit into a window of stored chunks
+// before generation: adjacent chunks by chunk_index ("neighbors") or the
+// Markdown section the hit belongs to ("section").
+type ContextExpansion struct {
+	Strategy  ContextExpansionStrategy `json:"strategy"`
+	Neighbors int                      `json:"neighbors,omitempty"`  // chunks on each side of the hit
+	MaxChunks int                      `json:"max_chunks,omitempty"` // cap on a window's size
 }
 
 type RetrievedChunk struct {
 	DocumentID string  `json:"document_id"`
 	Filename   string  `json:"filename"`
 	Category   string  `json:"category"`
+	Title      string  `json:"title,omitempty"`
+	Path       string  `json:"path,omitempty"`
+	SourceType string  `json:"source_type,omitempty"`
 	ChunkIndex int     `json:"chunk_index"`
 	Text       string  `json:"text"`
 	Score      float64 `json:"score"`
+	// Locator is the page, sheet or slide the chunk was extracted from.
+	Locator Locator `json:"locator,omitzero"`
+	// Window is set when Text was expanded beyond the matched chunk and
+	// spans chunk indexes Start..End.
+	Window *ChunkWindow `json:"window,omitempty"`
+}
+
+type ChunkWindow struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 type RetrievalMeta struct {
@@ -36,10 +89,44 @@ type RetrievalMeta struct {
 	SemanticCandidates int           `json:"semantic_candidates"`
 	LexicalCandidates  int           `json:"lexical_candidates"`
 	RerankApplied      bool          `json:"rerank_applied"`
+	// ExpandedChunks counts neighbouring chunks added by context expansion.
+	ExpandedChunks int `json:"expanded_chunks,omitempty"`
 }
 
 type Answer struct {
 	Text      string           `json:"text"`
 	Sources   []RetrievedChunk `json:"sources"`
 	Retrieval RetrievalMeta    `json:"retrieval"`
+
+	// Citations and Sentences are filled only in citation mode.
+	Citations []Citation       `json:"citations,omitempty"`
+	Sentences []AnswerSentence `json:"sentences,omitempty"`
+}
+
+// Citation links an inline [n] marker in an answer sentence to the retrieved
+// chunk it points at. SpanStart/SpanEnd are rune offsets into the chunk text
+// of the passage that best supports the sentence.
+type Citation struct {
+	Marker     int    `json:"marker"`
+	Sentence   int    `json:"sentence"`
+	DocumentID string `json:"document_id"`
+	ChunkIndex int    `json:"chunk_index"`
+	Filename   string `json:"filename"`
+	Title      string `json:"title,omitempty"`
+	Path       string `json:"path,omitempty"`
+	// Locator is the page, sheet or slide of the cited chunk.
+	Locator   Locator `json:"locator,omitzero"`
+	SpanStart int     `json:"span_start"`
+	SpanEnd   int     `json:"span_end"`
+}
+
+// AnswerSentence is one sentence of a cited answer. Start/End are rune offsets
+// into Answer.Text. Supported is false when the sentence carries no marker
+// that resolves to a retrieved chunk backing its content.
+type AnswerSentence struct {
+	Text      string `json:"text"`
+	Start     int    `json:"start"`
+	End       int    `json:"end"`
+	Markers   []int  `json:"markers,omitempty"`
+	Supported bool   `json:"supported"`
 }
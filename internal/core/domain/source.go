@@ -21,6 +21,7 @@ type IngestResult struct {
 	Body       io.Reader
 	SourceType string
 	Path       string
+	SourceID   string // stable identity; re-ingesting the same SourceID updates the existing document
 	ExtraMeta  map[string]string
 }
 
@@ -29,4 +30,9 @@ type ChunkConfig struct {
 	Strategy string `json:"strategy"`
 	Size     int    `json:"chunk_size"`
 	Overlap  int    `json:"overlap"`
+	// Unit measures Size and Overlap: "chars" (default) or "tokens".
+	Unit string `json:"unit,omitempty"`
+	// BreakpointPercentile tunes the semantic strategy: a topic boundary is
+	// placed where sentence distance exceeds this percentile (default 90).
+	BreakpointPercentile float64 `json:"breakpoint_percentile,omitempty"`
 }
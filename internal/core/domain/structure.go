@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Locator points to the part of a source file a piece of text comes from.
+// Zero fields are unknown or not applicable.
+type Locator struct {
+	Page  int    `json:"page,omitempty"`  // 1-based PDF (or paginated DOCX) page
+	Sheet string `json:"sheet,omitempty"` // spreadsheet sheet name
+	Slide int    `json:"slide,omitempty"` // 1-based presentation slide
+}
+
+func (l Locator) IsZero() bool {
+	return l == Locator{}
+}
+
+// String renders the locator for prompts and citations, e.g. "page 12" or
+// "sheet Budget".
+func (l Locator) String() string {
+	var parts []string
+	if l.Sheet != "" {
+		parts = append(parts, "sheet "+l.Sheet)
+	}
+	if l.Page > 0 {
+		parts = append(parts, fmt.Sprintf("page %d", l.Page))
+	}
+	if l.Slide > 0 {
+		parts = append(parts, fmt.Sprintf("slide %d", l.Slide))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Section is a located part of an extracted document. Text is Markdown:
+// headings are "#" lines and tables are pipe tables.
+type Section struct {
+	Locator Locator
+	Text    string
+}
+
+// JoinSections returns the text of all sections as one document.
+func JoinSections(sections []Section) string {
+	texts := make([]string, 0, len(sections))
+	for _, s := range sections {
+		if t := strings.TrimSpace(s.Text); t != "" {
+			texts = append(texts, t)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// ChunkAnnotation is per-chunk data indexed alongside the chunk text.
+// Context is the prefix the chunk was embedded with (document title,
+// heading path, generated context); it is lexically indexed with the chunk
+// but stored apart from it, so the chunk text stays what is shown and cited.
+type ChunkAnnotation struct {
+	Context string
+	Locator Locator
+}
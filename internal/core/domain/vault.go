@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Vault is a registered Obsidian vault directory kept in sync with the index.
+type Vault struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Path            string     `json:"path"`
+	Enabled         bool       `json:"enabled"`
+	IntervalMinutes *int       `json:"interval_minutes,omitempty"`
+	LastSyncAt      *time.Time `json:"last_sync_at,omitempty"`
+	LastStatus      string     `json:"last_status,omitempty"`
+	LastError       string     `json:"last_error,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// VaultFileState remembers the last indexed content hash of a note.
+type VaultFileState struct {
+	VaultID    string `json:"vault_id"`
+	RelPath    string `json:"rel_path"`
+	Hash       string `json:"hash"`
+	DocumentID string `json:"document_id,omitempty"`
+}
+
+type VaultSyncTrigger string
+
+const (
+	VaultSyncTriggerManual   VaultSyncTrigger = "manual"
+	VaultSyncTriggerStartup  VaultSyncTrigger = "startup"
+	VaultSyncTriggerInterval VaultSyncTrigger = "interval"
+	VaultSyncTriggerWatch    VaultSyncTrigger = "watch"
+)
+
+const (
+	VaultSyncStatusRunning = "running"
+	VaultSyncStatusOK      = "ok"
+	VaultSyncStatusPartial = "partial"
+	VaultSyncStatusError   = "error"
+)
+
+// VaultSyncError describes a single note that could not be synced.
+type VaultSyncError struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+// VaultSyncRun is one execution of a vault sync with its per-run counts.
+type VaultSyncRun struct {
+	ID         string           `json:"id"`
+	VaultID    string           `json:"vault_id"`
+	Trigger    VaultSyncTrigger `json:"trigger"`
+	Status     string           `json:"status"`
+	Uploaded   int              `json:"uploaded"`
+	Skipped    int              `json:"skipped"`
+	Removed    int              `json:"removed"`
+	Failed     int              `json:"failed"`
+	Errors     []VaultSyncError `json:"errors,omitempty"`
+	StartedAt  time.Time        `json:"started_at"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+}
+
+var vaultSlugRe = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// VaultSlug derives the stable vault ID from its display name.
+func VaultSlug(name string) string {
+	trimmed := strings.TrimSpace(strings.ToLower(name))
+	trimmed = vaultSlugRe.ReplaceAllString(trimmed, "_")
+	if trimmed == "" {
+		return "vault"
+	}
+	return trimmed
+}
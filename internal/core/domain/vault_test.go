@@ -0,0 +1,27 @@
+package domain
+
+import "testing"
+
+func TestVaultSlug(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"My Vault", "my_vault"},
+		{"simple", "simple"},
+		{"UPPER", "upper"},
+		{"  spaces  ", "spaces"},
+		{"special!@#$chars", "special_chars"},
+		{"a.b-c_d", "a.b-c_d"},
+		{"(Work) Notes!", "_work_notes_"},
+		{"", "vault"},
+		{"   ", "vault"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := VaultSlug(tt.input); got != tt.want {
+				t.Errorf("VaultSlug(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
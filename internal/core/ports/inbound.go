@@ -13,9 +13,33 @@ type DocumentIngestor interface {
 	IngestFromSource(ctx context.Context, req domain.SourceRequest) (*domain.Document, error)
 }
 
+// AttachmentIngestor ingests files found inside a document as its child
+// documents. index is the attachment's position in its parent, so
+// re-processing the parent updates the same children.
+type AttachmentIngestor interface {
+	IngestAttachment(ctx context.Context, parent *domain.Document, index int, attachment domain.Attachment) (*domain.Document, error)
+}
+
+// BulkIngestService expands archives and server-side directories into
+// documents in background jobs.
+type BulkIngestService interface {
+	// StartArchive spools a ZIP or TAR (optionally gzip-compressed) archive
+	// and starts a job ingesting its files.
+	StartArchive(ctx context.Context, filename string, body io.Reader) (*domain.IngestJob, error)
+	// StartDirectory starts a job ingesting every file under path, which
+	// must lie inside the configured import root.
+	StartDirectory(ctx context.Context, path string) (*domain.IngestJob, error)
+	// GetJob returns a job with its files, optionally filtered by status.
+	GetJob(ctx context.Context, id, fileStatus string) (*domain.IngestJob, error)
+	ListJobs(ctx context.Context, limit int) ([]domain.IngestJob, error)
+}
+
 // DocumentQueryService is the inbound contract for RAG and prompt-based generation.
 type DocumentQueryService interface {
 	Answer(ctx context.Context, question string, limit int, filter domain.SearchFilter) (*domain.Answer, error)
+	AnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter) (*domain.Answer, error)
+	StreamAnswer(ctx context.Context, question string, limit int, filter domain.SearchFilter, onDelta domain.AnswerDeltaCallback) (*domain.Answer, error)
+	StreamAnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter, onDelta domain.AnswerDeltaCallback) (*domain.Answer, error)
 	GenerateFromPrompt(ctx context.Context, prompt string) (string, error)
 	GenerateJSONFromPrompt(ctx context.Context, prompt string) (string, error)
 	ChatWithTools(ctx context.Context, messages []domain.ChatMessage, tools []domain.ToolSchema) (*domain.ChatToolsResult, error)
@@ -36,6 +60,115 @@ type DocumentEnricher interface {
 	EnrichByID(ctx context.Context, documentID string) error
 }
 
+// DocumentDeleter is the inbound contract for removing documents from every store.
+type DocumentDeleter interface {
+	Delete(ctx context.Context, id string) error
+	DeleteByFilter(ctx context.Context, filter domain.DocumentFilter) (*domain.DocumentDeleteResult, error)
+}
+
+// DocumentACLService changes who may read a document.
+type DocumentACLService interface {
+	SetACL(ctx context.Context, id string, visibility domain.DocumentVisibility, sharedWith []string) (*domain.Document, error)
+}
+
+// ConversationService exposes a user's chat history.
+type ConversationService interface {
+	List(ctx context.Context, userID string, limit, offset int) ([]domain.Conversation, error)
+	Get(ctx context.Context, userID, conversationID string) (*domain.ConversationTranscript, error)
+	Search(ctx context.Context, userID, query string, limit int) ([]domain.ConversationMessage, error)
+	Rename(ctx context.Context, userID, conversationID, title string) (*domain.Conversation, error)
+	// Delete forgets the conversation: messages, memory summaries and their vectors.
+	Delete(ctx context.Context, userID, conversationID string) error
+	Export(ctx context.Context, userID, conversationID string, format domain.ConversationExportFormat) ([]byte, error)
+}
+
+// OrchestrationService exposes a user's multi-agent orchestrations and the
+// progress of running ones.
+type OrchestrationService interface {
+	List(ctx context.Context, userID, conversationID string, limit int) ([]domain.Orchestration, error)
+	Get(ctx context.Context, userID, orchID string) (*domain.Orchestration, error)
+	Subscribe(ctx context.Context, userID, orchID string) (*domain.OrchestrationSubscription, error)
+}
+
+// AgentJobService runs agent requests as background jobs that outlive the
+// HTTP request.
+type AgentJobService interface {
+	Submit(ctx context.Context, req domain.AgentJobRequest) (*domain.AgentJob, error)
+	List(ctx context.Context, userID string, limit int) ([]domain.AgentJob, error)
+	Get(ctx context.Context, userID, jobID string) (*domain.AgentJob, error)
+	// Events returns the job's progress events after afterSeq.
+	Events(ctx context.Context, userID, jobID string, afterSeq int64, limit int) ([]domain.AgentJobEvent, error)
+	Cancel(ctx context.Context, userID, jobID string) (*domain.AgentJob, error)
+	// HasUnfinishedScheduleJob reports whether a job of the scheduled task
+	// is still queued or running.
+	HasUnfinishedScheduleJob(ctx context.Context, scheduleID string) (bool, error)
+}
+
+// AgentRunService exposes recorded agent run traces and replays them.
+type AgentRunService interface {
+	List(ctx context.Context, userID, conversationID string, limit int) ([]domain.AgentRun, error)
+	Get(ctx context.Context, userID, runID string) (*domain.AgentRun, error)
+	// Replay runs the recorded request again with tool results taken from
+	// the trace, and records the replay as a new run.
+	Replay(ctx context.Context, userID, runID string, opts domain.AgentReplayOptions) (*domain.AgentRun, error)
+}
+
+// MemoryFactService manages the facts a user asked the assistant to remember.
+type MemoryFactService interface {
+	List(ctx context.Context, userID string) ([]domain.MemoryFact, error)
+	// Remember stores content unless an equivalent fact exists; a fact it
+	// contradicts is overwritten instead of kept alongside.
+	Remember(ctx context.Context, userID, content string, source domain.MemoryFactSource) (*domain.MemoryFactWrite, error)
+	Update(ctx context.Context, userID, id, content string) (*domain.MemoryFact, error)
+	Forget(ctx context.Context, userID, id string) error
+}
+
+// EvalService measures retrieval quality against stored eval cases.
+type EvalService interface {
+	ListCases(ctx context.Context) ([]domain.EvalCase, error)
+	// SaveCases creates or replaces cases by ID; cases without an ID get one.
+	SaveCases(ctx context.Context, cases []domain.EvalCase) ([]domain.EvalCase, error)
+	DeleteCase(ctx context.Context, id string) error
+	// Run evaluates every case once per requested retrieval config and
+	// returns the finished runs.
+	Run(ctx context.Context, req domain.EvalRunRequest) ([]domain.EvalRun, error)
+	ListRuns(ctx context.Context, limit int) ([]domain.EvalRun, error)
+	GetRun(ctx context.Context, id string) (*domain.EvalRun, error)
+	Diff(ctx context.Context, baseID, headID string) (*domain.EvalRunDiff, error)
+}
+
+// VaultSyncService manages Obsidian vaults and keeps their notes indexed.
+type VaultSyncService interface {
+	ListVaults(ctx context.Context) ([]domain.Vault, error)
+	GetVault(ctx context.Context, id string) (*domain.Vault, error)
+	UpsertVault(ctx context.Context, vault domain.Vault) (*domain.Vault, error)
+	RemoveVault(ctx context.Context, id string) error
+	SyncVault(ctx context.Context, id string, trigger domain.VaultSyncTrigger, waitReady bool) (*domain.VaultSyncRun, error)
+	SyncPaths(ctx context.Context, id string, relPaths []string) (*domain.VaultSyncRun, error)
+	ListSyncRuns(ctx context.Context, id string, limit int) ([]domain.VaultSyncRun, error)
+	CreateNote(ctx context.Context, vaultID, title, content, folder string) (string, error)
+}
+
+// AuthService authenticates API keys and manages users and their keys.
+type AuthService interface {
+	Authenticate(ctx context.Context, token string) (*domain.Principal, error)
+	CreateUser(ctx context.Context, name string, admin bool, groups []string) (*domain.User, error)
+	SetUserGroups(ctx context.Context, userID string, groups []string) (*domain.User, error)
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	// CreateAPIKey issues a key for userID and returns its plaintext value,
+	// which is shown only once.
+	CreateAPIKey(ctx context.Context, userID, name string) (*domain.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+}
+
+// PrincipalResolver loads the principal of a user for work done on their
+// behalf outside a request, such as agent jobs and scheduled tasks.
+type PrincipalResolver interface {
+	PrincipalFor(ctx context.Context, userID string) (*domain.Principal, error)
+}
+
 // AgentVaultInfo holds minimal vault metadata for the agent system prompt.
 type AgentVaultInfo struct {
 	ID   string
@@ -12,15 +12,24 @@ import (
 type DocumentRepository interface {
 	Create(ctx context.Context, doc *domain.Document) error
 	GetByID(ctx context.Context, id string) (*domain.Document, error)
+	GetBySourceID(ctx context.Context, sourceID string) (*domain.Document, error)
+	// GetByContentHash returns the newest document of ownerID with the given
+	// content hash.
+	GetByContentHash(ctx context.Context, hash, ownerID string) (*domain.Document, error)
+	UpdateSource(ctx context.Context, doc *domain.Document) error
 	UpdateStatus(ctx context.Context, id string, status domain.DocumentStatus, errMessage string) error
 	SaveClassification(ctx context.Context, id string, cls domain.Classification) error
+	UpdateACL(ctx context.Context, id string, visibility domain.DocumentVisibility, sharedWith []string) error
 	ListRecent(ctx context.Context, limit int) ([]domain.Document, error)
+	ListByFilter(ctx context.Context, filter domain.DocumentFilter, limit int) ([]domain.Document, error)
+	Delete(ctx context.Context, id string) error
 }
 
 // ObjectStorage stores source documents.
 type ObjectStorage interface {
 	Save(ctx context.Context, key string, data io.Reader) error
 	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
 }
 
 // MessageQueue publishes/consumes ingestion events.
@@ -31,16 +40,51 @@ type MessageQueue interface {
 	SubscribeDocumentEnrich(ctx context.Context, handler func(context.Context, string) error) error
 }
 
+// AgentJobQueue hands agent jobs to workers.
+type AgentJobQueue interface {
+	PublishAgentJob(ctx context.Context, jobID string) error
+	SubscribeAgentJob(ctx context.Context, handler func(context.Context, string) error) error
+}
+
 // TextExtractor extracts plain text from a stored document.
 type TextExtractor interface {
 	Extract(ctx context.Context, doc *domain.Document) (string, error)
 }
 
+// StructuredExtractor is a TextExtractor that keeps document structure: it
+// returns Markdown sections (headings, tables) located by page, sheet or
+// slide. Extract returns the same text joined.
+type StructuredExtractor interface {
+	TextExtractor
+	ExtractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error)
+}
+
+// AttachmentExtractor is a TextExtractor for documents that carry files of
+// their own, such as emails. Extract only lists the attachments by name;
+// ExtractAttachments returns them so they can be ingested as child
+// documents.
+type AttachmentExtractor interface {
+	TextExtractor
+	ExtractAttachments(ctx context.Context, doc *domain.Document) ([]domain.Attachment, error)
+}
+
 // ExtractorRegistry selects a TextExtractor based on MIME type.
 type ExtractorRegistry interface {
 	ForMimeType(mimeType string) TextExtractor
 }
 
+// OCREngine recognizes printed text in images and in rendered pages of PDFs
+// that have no text layer. page is 1-based.
+type OCREngine interface {
+	RecognizeImage(ctx context.Context, image []byte) (string, error)
+	RecognizePDFPage(ctx context.Context, pdf []byte, page int) (string, error)
+}
+
+// ImageCaptioner describes an image with a vision-capable model.
+type ImageCaptioner interface {
+	CaptionImage(ctx context.Context, image []byte, mimeType string) (string, error)
+}
+
 // DocumentClassifier classifies extracted text.
 type DocumentClassifier interface {
 	Classify(ctx context.Context, text string) (domain.Classification, error)
@@ -70,9 +114,18 @@ type Chunker interface {
 	Split(text string) []string
 }
 
-// ChunkerRegistry selects a Chunker based on source type.
+// ContextChunker is a Chunker that calls out to other services (e.g. an
+// embedder) and therefore needs the request context.
+type ContextChunker interface {
+	Chunker
+	SplitContext(ctx context.Context, text string) ([]string, error)
+}
+
+// ChunkerRegistry selects a Chunker based on source type. ForDocument also
+// considers the MIME type, so source files get a language-aware chunker.
 type ChunkerRegistry interface {
 	ForSource(sourceType string) Chunker
+	ForDocument(sourceType, mimeType string) Chunker
 }
 
 // VectorStore indexes chunks and performs semantic search.
@@ -81,13 +134,37 @@ type VectorStore interface {
 	Search(ctx context.Context, queryVector []float32, limit int, filter domain.SearchFilter) ([]domain.RetrievedChunk, error)
 	SearchLexical(ctx context.Context, queryText string, limit int, filter domain.SearchFilter) ([]domain.RetrievedChunk, error)
 	UpdateChunksPayload(ctx context.Context, docID string, sourceType string, payload map[string]any) error
+	DeleteByDocumentID(ctx context.Context, docID string) error
+}
+
+// AnnotatedVectorStore indexes chunks together with per-chunk annotations:
+// the contextual prefix they were embedded with and the page, sheet or slide
+// they come from. annotations may be nil.
+type AnnotatedVectorStore interface {
+	IndexAnnotatedChunks(ctx context.Context, doc *domain.Document, chunks []string, annotations []domain.ChunkAnnotation, vectors [][]float32) error
+}
+
+// ChunkReader loads stored chunks of one document by chunk_index range,
+// ordered by index. Used to expand retrieved hits into context windows.
+type ChunkReader interface {
+	ChunksByIndex(ctx context.Context, docID string, sourceType string, from, to int) ([]domain.RetrievedChunk, error)
 }
 
 // AnswerGenerator creates the final user-facing answer.
 type AnswerGenerator interface {
 	GenerateAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error)
+	// GenerateCitedAnswer answers like GenerateAnswer but marks each claim
+	// with inline [n] markers referring to the 1-based position in chunks.
+	GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error)
+	// StreamAnswer and StreamCitedAnswer produce the same answers as their
+	// Generate counterparts, calling onDelta with each piece of text as the
+	// model emits it. The returned string is the complete answer.
+	StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error)
+	StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error)
 	GenerateFromPrompt(ctx context.Context, prompt string) (string, error)
 	GenerateJSONFromPrompt(ctx context.Context, prompt string) (string, error)
+	// ChatWithTools streams content deltas of a text (non tool call) reply to
+	// the callback found via domain.AnswerCallbackFromContext, when present.
 	ChatWithTools(ctx context.Context, messages []domain.ChatMessage, tools []domain.ToolSchema) (*domain.ChatToolsResult, error)
 }
 
@@ -104,6 +181,21 @@ type ConversationStore interface {
 	ListRecentMessages(ctx context.Context, userID, conversationID string, limit int) ([]domain.ConversationMessage, error)
 	ListMessagesByTurnRange(ctx context.Context, userID, conversationID string, turnFrom, turnTo int) ([]domain.ConversationMessage, error)
 	UpdateLastSummaryEndTurn(ctx context.Context, userID, conversationID string, turn int) error
+	// ListConversations returns the user's conversations, most recently
+	// updated first, leaving out orchestration step conversations. An empty
+	// Title falls back to the first user message.
+	ListConversations(ctx context.Context, userID string, limit, offset int) ([]domain.Conversation, error)
+	GetConversation(ctx context.Context, userID, conversationID string) (*domain.Conversation, error)
+	ListMessages(ctx context.Context, userID, conversationID string) ([]domain.ConversationMessage, error)
+	// SearchMessages finds the user's messages containing query (case-insensitive), newest first.
+	// Messages of orchestration step conversations are left out.
+	SearchMessages(ctx context.Context, userID, query string, limit int) ([]domain.ConversationMessage, error)
+	RenameConversation(ctx context.Context, userID, conversationID, title string) error
+	// DeleteConversation removes the conversation and its messages.
+	DeleteConversation(ctx context.Context, userID, conversationID string) error
+	// ListConversationIDsByPrefix returns the ids of the user's
+	// conversations starting with prefix.
+	ListConversationIDsByPrefix(ctx context.Context, userID, prefix string) ([]string, error)
 }
 
 // TaskStore persists and retrieves user tasks.
@@ -119,12 +211,48 @@ type TaskStore interface {
 type MemoryStore interface {
 	CreateSummary(ctx context.Context, summary *domain.MemorySummary) error
 	GetLastSummaryEndTurn(ctx context.Context, userID, conversationID string) (int, error)
+	DeleteSummaries(ctx context.Context, userID, conversationID string) error
 }
 
 // MemoryVectorStore indexes and searches memory summaries semantically.
 type MemoryVectorStore interface {
 	IndexSummary(ctx context.Context, summary domain.MemorySummary, vector []float32) error
 	SearchSummaries(ctx context.Context, userID, conversationID string, queryVector []float32, limit int) ([]domain.MemoryHit, error)
+	DeleteSummaries(ctx context.Context, userID, conversationID string) error
+}
+
+// MemoryFactStore persists explicit user facts. Every call is scoped to
+// userID; another user's fact is reported as ErrMemoryFactNotFound.
+type MemoryFactStore interface {
+	CreateFact(ctx context.Context, fact *domain.MemoryFact) error
+	UpdateFact(ctx context.Context, fact *domain.MemoryFact) error
+	GetFact(ctx context.Context, userID, id string) (*domain.MemoryFact, error)
+	// ListFacts returns facts most recently updated first.
+	ListFacts(ctx context.Context, userID string, limit int) ([]domain.MemoryFact, error)
+	DeleteFact(ctx context.Context, userID, id string) error
+}
+
+// LexicalStatsStore keeps the BM25 corpus statistics of each vector
+// collection up to date as chunks are indexed and deleted.
+type LexicalStatsStore interface {
+	// AddLexicalStats applies delta to the collection's statistics.
+	AddLexicalStats(ctx context.Context, collection string, delta domain.LexicalStats) error
+	// LexicalStats returns the collection totals and the document frequency
+	// of terms; unknown terms are absent from DocFreq.
+	LexicalStats(ctx context.Context, collection string, terms []string) (*domain.LexicalStats, error)
+}
+
+// EvalStore persists retrieval eval cases and the history of eval runs.
+type EvalStore interface {
+	// UpsertCase creates the case or replaces the one with the same ID.
+	UpsertCase(ctx context.Context, c *domain.EvalCase) error
+	ListCases(ctx context.Context) ([]domain.EvalCase, error)
+	DeleteCase(ctx context.Context, id string) error
+	CreateRun(ctx context.Context, run *domain.EvalRun) error
+	FinishRun(ctx context.Context, run *domain.EvalRun) error
+	GetRun(ctx context.Context, id string) (*domain.EvalRun, error)
+	// ListRuns returns runs newest first, without per-case results.
+	ListRuns(ctx context.Context, limit int) ([]domain.EvalRun, error)
 }
 
 // WebSearcher performs web searches via an external search engine.
@@ -158,6 +286,7 @@ type GraphStore interface {
 	FindByID(ctx context.Context, id string) (*domain.GraphNode, error)
 	FindByTitle(ctx context.Context, title string) ([]domain.GraphNode, error)
 	GetGraph(ctx context.Context, filter domain.GraphFilter) (*domain.Graph, error)
+	DeleteDocument(ctx context.Context, docID string) error
 }
 
 // OrchestrationStore persists multi-agent orchestration history.
@@ -167,6 +296,64 @@ type OrchestrationStore interface {
 	Complete(ctx context.Context, orchID string, status string) error
 	GetByID(ctx context.Context, orchID string) (*domain.Orchestration, error)
 	ListByUser(ctx context.Context, userID string, limit int) ([]domain.Orchestration, error)
+	ListByConversation(ctx context.Context, userID, conversationID string, limit int) ([]domain.Orchestration, error)
+	DeleteByConversation(ctx context.Context, userID, conversationID string) error
+}
+
+// AgentRunStore persists agent run traces.
+type AgentRunStore interface {
+	SaveAgentRun(ctx context.Context, run *domain.AgentRun) error
+	// GetAgentRun returns the run with its tools and steps.
+	GetAgentRun(ctx context.Context, id string) (*domain.AgentRun, error)
+	// ListAgentRuns returns the user's runs newest first, without tools and
+	// steps. An empty conversationID lists runs of every conversation.
+	ListAgentRuns(ctx context.Context, userID, conversationID string, limit int) ([]domain.AgentRun, error)
+	// DeleteAgentRuns removes the runs of a conversation.
+	DeleteAgentRuns(ctx context.Context, userID, conversationID string) error
+}
+
+// AgentJobStore persists agent jobs with their progress events and
+// checkpoints.
+type AgentJobStore interface {
+	CreateAgentJob(ctx context.Context, job *domain.AgentJob) error
+	// GetAgentJob returns the job with its checkpoints.
+	GetAgentJob(ctx context.Context, id string) (*domain.AgentJob, error)
+	// ListAgentJobs returns the user's jobs newest first, without checkpoints.
+	ListAgentJobs(ctx context.Context, userID string, limit int) ([]domain.AgentJob, error)
+	// ClaimAgentJob marks a queued job, or a running one whose heartbeat is
+	// older than staleBefore, as running and counts the attempt. It reports
+	// false when another worker owns the job or it has finished.
+	ClaimAgentJob(ctx context.Context, id string, staleBefore time.Time) (*domain.AgentJob, bool, error)
+	// The writes of a running job below take the attempt the worker claimed
+	// and return ErrAgentJobNotOwned once another worker has claimed it.
+
+	// HeartbeatAgentJob refreshes a running job's heartbeat and reports
+	// whether its cancellation was requested.
+	HeartbeatAgentJob(ctx context.Context, id string, attempt int) (bool, error)
+	// FinishAgentJob stores the final status, answer and error of the
+	// job.Attempts attempt.
+	FinishAgentJob(ctx context.Context, job *domain.AgentJob) error
+	// SetAgentJobTurn stores the conversation turn of the job's user message.
+	SetAgentJobTurn(ctx context.Context, id string, attempt, turn int) error
+	AddAgentJobCheckpoint(ctx context.Context, id string, attempt int, checkpoint domain.AgentCheckpoint) error
+	// CancelAgentJob cancels a queued job right away and flags a running
+	// one for its worker; finished jobs are left unchanged.
+	CancelAgentJob(ctx context.Context, id string) error
+	AddAgentJobEvent(ctx context.Context, id string, event domain.AgentJobEvent) error
+	// ListAgentJobEvents returns the events after afterSeq in order.
+	ListAgentJobEvents(ctx context.Context, id string, afterSeq int64, limit int) ([]domain.AgentJobEvent, error)
+	// MarkAgentJobPublished records that the job was handed to the workers.
+	MarkAgentJobPublished(ctx context.Context, id string, at time.Time) error
+	// ListStaleAgentJobs returns the ids of queued jobs never published and
+	// of running jobs last heard of before staleBefore and not published
+	// again since.
+	ListStaleAgentJobs(ctx context.Context, staleBefore time.Time, limit int) ([]string, error)
+	// HasUnfinishedScheduleJob reports whether a job of the scheduled task
+	// is queued or running.
+	HasUnfinishedScheduleJob(ctx context.Context, scheduleID string) (bool, error)
+	// DeleteByConversation removes the user's jobs of a conversation with
+	// their events.
+	DeleteByConversation(ctx context.Context, userID, conversationID string) error
 }
 
 // EventStore records and queries agent execution events.
@@ -201,3 +388,65 @@ type ScheduleStore interface {
 	Delete(ctx context.Context, id string) error
 	RecordRun(ctx context.Context, id string, result string, status string) error
 }
+
+// VaultStore persists registered Obsidian vaults.
+type VaultStore interface {
+	ListVaults(ctx context.Context) ([]domain.Vault, error)
+	GetVault(ctx context.Context, id string) (*domain.Vault, error)
+	UpsertVault(ctx context.Context, vault *domain.Vault) error
+	DeleteVault(ctx context.Context, id string) error
+}
+
+// VaultSyncStateStore persists per-note sync state, sync history and the
+// cross-process lock that keeps two syncs of one vault from overlapping.
+type VaultSyncStateStore interface {
+	ListFileStates(ctx context.Context, vaultID string) ([]domain.VaultFileState, error)
+	UpsertFileStates(ctx context.Context, states []domain.VaultFileState) error
+	DeleteFileStates(ctx context.Context, vaultID string, relPaths []string) error
+	// TryLock acquires the sync lock for a vault without blocking. When ok is
+	// false the vault is being synced elsewhere and unlock is nil.
+	TryLock(ctx context.Context, vaultID string) (unlock func(), ok bool, err error)
+	CreateSyncRun(ctx context.Context, run *domain.VaultSyncRun) error
+	FinishSyncRun(ctx context.Context, run *domain.VaultSyncRun) error
+	ListSyncRuns(ctx context.Context, vaultID string, limit int) ([]domain.VaultSyncRun, error)
+}
+
+// IngestJobStore persists bulk ingest jobs and the outcome of their files.
+type IngestJobStore interface {
+	CreateIngestJob(ctx context.Context, job *domain.IngestJob) error
+	// UpdateIngestJob stores the status, counts and timestamps of a job.
+	UpdateIngestJob(ctx context.Context, job *domain.IngestJob) error
+	AddIngestJobFiles(ctx context.Context, jobID string, files []domain.IngestJobFile) error
+	GetIngestJob(ctx context.Context, id string) (*domain.IngestJob, error)
+	// ListIngestJobs returns jobs newest first; an empty ownerID lists all.
+	ListIngestJobs(ctx context.Context, ownerID string, limit int) ([]domain.IngestJob, error)
+	// ListIngestJobFiles returns files in processing order, optionally only
+	// those with the given status.
+	ListIngestJobFiles(ctx context.Context, jobID, status string, limit, offset int) ([]domain.IngestJobFile, error)
+	// HeartbeatIngestJob records that the process running a job is alive.
+	HeartbeatIngestJob(ctx context.Context, id string, at time.Time) error
+	// FailStaleIngestJobs marks queued or running jobs whose last heartbeat
+	// is older than staleBefore as failed with message and reports how many
+	// there were.
+	FailStaleIngestJobs(ctx context.Context, message string, staleBefore, finishedAt time.Time) (int, error)
+}
+
+// DirectoryWatcher reports batches of changed paths (relative to root) until
+// ctx is cancelled.
+type DirectoryWatcher interface {
+	Watch(ctx context.Context, root string, onChange func(ctx context.Context, relPaths []string) error) error
+}
+
+// UserStore persists users and their hashed API keys.
+type UserStore interface {
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+	ListUsers(ctx context.Context) ([]domain.User, error)
+	UpdateUserGroups(ctx context.Context, id string, groups []string) error
+	CreateAPIKey(ctx context.Context, key *domain.APIKey, keyHash string) error
+	// GetAPIKeyByHash returns the active (non-revoked) key with the given hash.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID string) error
+	TouchAPIKey(ctx context.Context, keyID string, usedAt time.Time) error
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +26,11 @@ const (
 	agentToolWebSearch       = "web_search"
 	agentToolObsidianWrite   = "obsidian_write"
 	agentToolTask            = "task_tool"
+	agentToolMemory          = "memory"
+
+	// agentMemoryFactsInPrompt caps how many user facts, most recently
+	// updated first, are injected into the system prompt.
+	agentMemoryFactsInPrompt = 50
 )
 
 type AgentChatUseCase struct {
@@ -44,6 +50,9 @@ type AgentChatUseCase struct {
 	modelRouting    *domain.ModelRouting
 	graphStore      ports.GraphStore
 	orchestrator    *OrchestratorUseCase
+	memoryFacts     ports.MemoryFactService
+	events          *EventCollector
+	runs            ports.AgentRunStore
 }
 
 func NewAgentChatUseCase(
@@ -122,6 +131,22 @@ func (uc *AgentChatUseCase) SetOrchestrator(o *OrchestratorUseCase) {
 	uc.orchestrator = o
 }
 
+// SetMemoryFacts enables the memory tool and injects the user's facts into
+// the system prompt.
+func (uc *AgentChatUseCase) SetMemoryFacts(s ports.MemoryFactService) {
+	uc.memoryFacts = s
+}
+
+// SetEventCollector records tool allowlist violations of specialist runs.
+func (uc *AgentChatUseCase) SetEventCollector(c *EventCollector) {
+	uc.events = c
+}
+
+// SetRunStore enables recording a trace of every run.
+func (uc *AgentChatUseCase) SetRunStore(s ports.AgentRunStore) {
+	uc.runs = s
+}
+
 func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRequest, onToolStatus domain.ToolStatusCallback) (*domain.AgentRunResult, error) {
 	requestStart := time.Now()
 	userID := strings.TrimSpace(req.UserID)
@@ -143,6 +168,11 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		return nil, fmt.Errorf("ensure conversation: %w", err)
 	}
 
+	var trace *runTrace
+	if uc.runs != nil {
+		trace = newRunTrace(userID, conversationID, lastUserMessage)
+	}
+
 	shortMemory, err := uc.conversations.ListRecentMessages(ctx, userID, conversationID, uc.limits.ShortMemoryMessages)
 	if err != nil {
 		return nil, fmt.Errorf("load short memory: %w", err)
@@ -157,24 +187,52 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		}
 	}
 
-	turn, err := uc.conversations.NextUserTurn(ctx, userID, conversationID)
-	if err != nil {
-		return nil, fmt.Errorf("next user turn: %w", err)
+	var memoryFacts []domain.MemoryFact
+	if uc.memoryFacts != nil {
+		memoryFacts, err = uc.memoryFacts.List(ctx, userID)
+		if err != nil {
+			slog.Warn("agent_memory_facts_unavailable", "user_id", userID, "error", err)
+			memoryFacts = nil
+		}
+		memoryFacts = memoryFacts[:min(len(memoryFacts), agentMemoryFactsInPrompt)]
 	}
 
-	if err := uc.conversations.AppendMessage(ctx, domain.ConversationMessage{
-		ID:             uuid.NewString(),
-		UserID:         userID,
-		ConversationID: conversationID,
-		Role:           "user",
-		Content:        lastUserMessage,
-		UserTurn:       turn,
-		CreatedAt:      time.Now().UTC(),
-	}); err != nil {
-		return nil, fmt.Errorf("append user message: %w", err)
+	// A resumed run answers the turn whose user message the interrupted
+	// attempt already stored.
+	turn := req.Turn
+	if turn == 0 && len(req.Resume) > 0 {
+		turn = req.Resume[0].Turn
 	}
+	resuming := turn > 0
+	if !resuming {
+		turn, err = uc.conversations.NextUserTurn(ctx, userID, conversationID)
+		if err != nil {
+			return nil, fmt.Errorf("next user turn: %w", err)
+		}
 
-	loopCtx, cancel := context.WithTimeout(ctx, uc.limits.Timeout)
+		if err := uc.conversations.AppendMessage(ctx, domain.ConversationMessage{
+			ID:             uuid.NewString(),
+			UserID:         userID,
+			ConversationID: conversationID,
+			Role:           "user",
+			Content:        lastUserMessage,
+			UserTurn:       turn,
+			CreatedAt:      time.Now().UTC(),
+		}); err != nil {
+			return nil, fmt.Errorf("append user message: %w", err)
+		}
+		if req.OnUserTurn != nil {
+			if err := req.OnUserTurn(turn); err != nil {
+				return nil, fmt.Errorf("store user turn: %w", err)
+			}
+		}
+	}
+
+	timeout := uc.limits.Timeout
+	if req.Timeout > 0 {
+		timeout = req.Timeout
+	}
+	loopCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// Attach thinking callback to context for streaming thinking tokens
@@ -182,11 +240,21 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		loopCtx = domain.ContextWithThinkingCallback(loopCtx, req.OnThinkingDelta)
 	}
 
-	thinkingLines := make([]string, 0, uc.limits.MaxIterations)
-	toolEvents := make([]domain.AgentToolEvent, 0, uc.limits.MaxIterations)
-	toolsInvoked := make([]string, 0, uc.limits.MaxIterations)
+	profile := req.Profile
+	maxIterations := uc.limits.MaxIterations
+	if req.MaxIterations > 0 {
+		maxIterations = req.MaxIterations
+	}
+	if profile != nil && profile.MaxIterations > 0 {
+		maxIterations = profile.MaxIterations
+	}
+
+	thinkingLines := make([]string, 0, maxIterations)
+	toolEvents := make([]domain.AgentToolEvent, 0, maxIterations)
+	toolsInvoked := make([]string, 0, maxIterations)
 	toolSet := make(map[string]struct{})
 	finalAnswer := ""
+	answerStreamed := false
 	fallbackReason := ""
 	iterations := 0
 
@@ -210,26 +278,57 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 	// Adaptive model routing based on complexity.
 	tier := domain.TierSimple
 	if uc.modelRouting != nil {
-		tier = classifyComplexityRules(lastUserMessage, intent)
-		if tier == TierUncertain {
-			tier = domain.TierComplex // safer default
+		if profile != nil && profile.ModelTier != "" {
+			tier = profile.ModelTier
+		} else {
+			tier = classifyComplexityRules(lastUserMessage, intent)
+			if tier == TierUncertain {
+				tier = domain.TierComplex // safer default
+			}
 		}
 		model := uc.modelRouting.ModelFor(tier)
 		ctx = routing.WithProvider(ctx, model)
 		slog.Info("adaptive_routing", "tier", tier, "model", model, "intent", intent)
 	}
+	trace.route(string(intent), string(tier), routing.ProviderFrom(ctx))
 
-	// Multi-agent orchestration for complex tasks.
-	if uc.orchestrator != nil && shouldOrchestrate(intent, tier, lastUserMessage) {
+	// Multi-agent orchestration for complex tasks. Specialist runs, resumed
+	// runs and fallbacks started by the orchestrator never orchestrate.
+	if uc.orchestrator != nil && profile == nil && !resuming && !orchestrationSkipped(ctx) && shouldOrchestrate(intent, tier, lastUserMessage) {
 		slog.Info("orchestrating_multi_agent", "intent", intent, "tier", tier)
-		return uc.orchestrator.Execute(ctx, req, onToolStatus, req.OnOrchStep)
+		// A single agent fallback answers the turn stored above instead of
+		// storing the user message again.
+		req.ConversationID = conversationID
+		req.Turn = turn
+		result, err := uc.orchestrator.Execute(ctx, req, onToolStatus, req.OnOrchStep)
+		if err != nil || result.OrchestrationID == "" {
+			return result, err
+		}
+		if err := uc.conversations.AppendMessage(ctx, domain.ConversationMessage{
+			ID:             uuid.NewString(),
+			UserID:         userID,
+			ConversationID: conversationID,
+			Role:           "assistant",
+			Content:        sanitizeUTF8(result.Answer),
+			UserTurn:       turn,
+			CreatedAt:      time.Now().UTC(),
+		}); err != nil {
+			return nil, fmt.Errorf("append assistant message: %w", err)
+		}
+		if run := trace.finish(result.Answer, "", result.Iterations); run != nil {
+			run.OrchestrationID = result.OrchestrationID
+			result.RunID = saveAgentRun(ctx, uc.runs, run)
+		}
+		return result, nil
 	}
 
-	if intent == IntentWeb && webSearchAvailable {
+	if intent == IntentWeb && webSearchAvailable && (profile == nil || profile.AllowsTool(agentToolWebSearch)) {
 		appendThinkingLine(loopCtx, &thinkingLines, "Searching the web directly")
+		directStart := time.Now()
 		answer, event, handled, directErr := uc.answerFromDirectWebSearch(loopCtx, lastUserMessage)
 		if directErr == nil && handled {
 			finalAnswer = answer
+			trace.toolCall(0, domain.ToolCall{}, event, toolCallMeta{elapsed: time.Since(directStart)})
 			appendThinkingLine(loopCtx, &thinkingLines, "✓ Tool web_search: ok")
 			toolEvents = append(toolEvents, event)
 			toolSet[event.Tool] = struct{}{}
@@ -237,8 +336,12 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		}
 	}
 
-	systemPrompt := buildSystemPrompt(ctx, intent, memoryHits, uc.toolRegistry, uc.obsidianVaults)
-	toolSchemas := toolSchemasFromRegistry(uc.toolRegistry, webSearchAvailable)
+	systemPrompt := buildSystemPrompt(ctx, intent, profile, memoryHits, memoryFacts, uc.toolRegistry, uc.obsidianVaults)
+	if taskContext := strings.TrimSpace(req.TaskContext); taskContext != "" {
+		systemPrompt += "\n" + taskContext + "\n"
+	}
+	toolSchemas := toolSchemasFromRegistry(uc.toolRegistry, webSearchAvailable, uc.memoryFacts != nil, profile)
+	trace.prompt(systemPrompt, toolSchemas)
 
 	// Build initial messages
 	chatMessages := []domain.ChatMessage{
@@ -246,6 +349,9 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 	}
 	// Add short memory as conversation history
 	for _, msg := range shortMemory {
+		if resuming && msg.UserTurn == turn {
+			continue
+		}
 		if content := strings.TrimSpace(msg.Content); content != "" {
 			chatMessages = append(chatMessages, domain.ChatMessage{Role: msg.Role, Content: content})
 		}
@@ -253,9 +359,31 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 	// Add current user message
 	chatMessages = append(chatMessages, domain.ChatMessage{Role: "user", Content: lastUserMessage})
 
+	// Replay the tool iterations of the interrupted attempt; they count
+	// against the iteration budget.
+	for _, checkpoint := range req.Resume {
+		chatMessages = append(chatMessages, domain.ChatMessage{Role: "assistant", ToolCalls: checkpoint.ToolCalls})
+		for idx, event := range checkpoint.Events {
+			if idx >= len(checkpoint.ToolCalls) {
+				break
+			}
+			toolEvents = append(toolEvents, event)
+			if _, seen := toolSet[event.Tool]; !seen && event.Tool != "" {
+				toolSet[event.Tool] = struct{}{}
+				toolsInvoked = append(toolsInvoked, event.Tool)
+			}
+			chatMessages = append(chatMessages, domain.ChatMessage{
+				Role:       "tool",
+				Content:    event.Output,
+				ToolCallID: checkpoint.ToolCalls[idx].ID,
+			})
+		}
+	}
+	iterations = len(req.Resume)
+
 	if finalAnswer == "" {
 		// Main loop — uses native function calling via ChatWithTools
-		for i := 1; i <= uc.limits.MaxIterations; i++ {
+		for i := iterations + 1; i <= maxIterations; i++ {
 			if loopCtx.Err() != nil {
 				fallbackReason = "timeout"
 				break
@@ -263,8 +391,20 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 			iterations = i
 
 			plannerCtx, plannerCancel := context.WithTimeout(loopCtx, uc.limits.PlannerTimeout)
+			// A text reply from the planner is the final answer, so its deltas
+			// go to the caller as they arrive. Providers stop forwarding text
+			// once the turn starts calling tools.
+			streamed := false
+			if req.OnAnswerDelta != nil {
+				plannerCtx = domain.ContextWithAnswerCallback(plannerCtx, func(text string) {
+					streamed = true
+					req.OnAnswerDelta(text)
+				})
+			}
+			callStart := time.Now()
 			chatResult, err := uc.querySvc.ChatWithTools(plannerCtx, chatMessages, toolSchemas)
 			plannerCancel()
+			trace.llmCall(i, chatMessages, chatResult, err, time.Since(callStart))
 			if err != nil {
 				if isAgentTimeoutError(err) {
 					fallbackReason = "timeout"
@@ -281,6 +421,7 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 			// If LLM returned a text response — final answer
 			if len(chatResult.ToolCalls) == 0 && chatResult.Content != "" {
 				finalAnswer = chatResult.Content
+				answerStreamed = streamed
 				break
 			}
 
@@ -293,6 +434,7 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 				})
 
 				var iterEvents []domain.AgentToolEvent
+				iterMeta := make([]toolCallMeta, len(chatResult.ToolCalls))
 
 				if len(chatResult.ToolCalls) > 1 {
 					// Parallel execution for multiple tool calls
@@ -302,12 +444,20 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 						wg.Add(1)
 						go func(idx int, call domain.ToolCall) {
 							defer wg.Done()
+							if denied, ok := uc.denyToolCall(loopCtx, userID, conversationID, profile, call.Function.Name); ok {
+								iterEvents[idx] = denied
+								if onToolStatus != nil {
+									onToolStatus(denied.Tool, denied.Status)
+								}
+								return
+							}
 							var ev domain.AgentToolEvent
 							ttl := cacheTTLForTool(call.Function.Name)
 							if ttl > 0 {
 								argsKey := argsToKey(call.Function.Arguments)
 								if cached, ok := uc.toolResultCache.get(call.Function.Name, argsKey); ok {
 									iterEvents[idx] = domain.AgentToolEvent{Tool: call.Function.Name, Status: "ok", Output: cached}
+									iterMeta[idx].cached = true
 									if onToolStatus != nil {
 										onToolStatus(call.Function.Name, "ok")
 									}
@@ -318,7 +468,9 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 								onToolStatus(call.Function.Name, "running")
 							}
 							toolCtx, toolCancel := context.WithTimeout(loopCtx, uc.limits.ToolTimeout)
+							toolStart := time.Now()
 							ev, execErr := uc.executeToolCall(toolCtx, userID, call, lastUserMessage)
+							iterMeta[idx].elapsed = time.Since(toolStart)
 							toolCancel()
 							if execErr != nil {
 								errorPayload, _ := json.Marshal(map[string]string{"error": execErr.Error()})
@@ -337,25 +489,34 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 					// Single tool call — sequential path
 					tc := chatResult.ToolCalls[0]
 					var event domain.AgentToolEvent
-					cached := false
+					resolved := false // denied or served from the cache
 					ttl := cacheTTLForTool(tc.Function.Name)
-					if ttl > 0 {
+					if denied, ok := uc.denyToolCall(loopCtx, userID, conversationID, profile, tc.Function.Name); ok {
+						event = denied
+						resolved = true
+						if onToolStatus != nil {
+							onToolStatus(denied.Tool, denied.Status)
+						}
+					} else if ttl > 0 {
 						argsKey := argsToKey(tc.Function.Arguments)
 						if cachedOutput, ok := uc.toolResultCache.get(tc.Function.Name, argsKey); ok {
 							event = domain.AgentToolEvent{Tool: tc.Function.Name, Status: "ok", Output: cachedOutput}
-							cached = true
+							resolved = true
+							iterMeta[0].cached = true
 							if onToolStatus != nil {
 								onToolStatus(tc.Function.Name, "ok")
 							}
 						}
 					}
-					if !cached {
+					if !resolved {
 						if onToolStatus != nil {
 							onToolStatus(tc.Function.Name, "running")
 						}
 						toolCtx, toolCancel := context.WithTimeout(loopCtx, uc.limits.ToolTimeout)
 						var execErr error
+						toolStart := time.Now()
 						event, execErr = uc.executeToolCall(toolCtx, userID, tc, lastUserMessage)
+						iterMeta[0].elapsed = time.Since(toolStart)
 						toolCancel()
 						if execErr != nil {
 							if isAgentTimeoutError(execErr) {
@@ -374,6 +535,7 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 				}
 
 				// Process collected events (thinking lines, FS hints, summarize, track tools, append messages)
+				iterStart := len(toolEvents)
 				for idx, event := range iterEvents {
 					tc := chatResult.ToolCalls[idx]
 					if event.Status == "error" {
@@ -396,6 +558,7 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 					}
 
 					event.Output = maybeSummarize(event.Output, 4096)
+					trace.toolCall(i, tc, event, iterMeta[idx])
 
 					toolEvents = append(toolEvents, event)
 					if event.Tool != "" {
@@ -412,6 +575,14 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 					})
 				}
 
+				if req.OnCheckpoint != nil {
+					req.OnCheckpoint(domain.AgentCheckpoint{
+						Turn:      turn,
+						ToolCalls: chatResult.ToolCalls,
+						Events:    slices.Clone(toolEvents[iterStart:]),
+					})
+				}
+
 				if intent == IntentWeb && finalAnswer == "" {
 					if answer, ok := uc.answerFromWebToolEvents(loopCtx, lastUserMessage, iterEvents); ok {
 						finalAnswer = answer
@@ -434,9 +605,11 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 	if fallbackReason == "" && finalAnswer == "" {
 		fallbackReason = "max_iterations"
 	}
-	if finalAnswer == "" && intent == IntentWeb {
+	if finalAnswer == "" && intent == IntentWeb && webSearchAvailable && uc.allowFallback(ctx, userID, conversationID, profile, agentToolWebSearch) {
 		appendThinkingLine(loopCtx, &thinkingLines, "Fallback: searching the web directly")
+		fallbackStart := time.Now()
 		fallbackAnswer, fallbackEvent, fallbackErr := uc.answerFromWebFallback(ctx, lastUserMessage)
+		trace.fallback(agentToolWebSearch, fallbackAnswer, fallbackErr, time.Since(fallbackStart))
 		if fallbackErr == nil && strings.TrimSpace(fallbackAnswer) != "" {
 			finalAnswer = fallbackAnswer
 			toolEvents = append(toolEvents, fallbackEvent)
@@ -446,9 +619,11 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 			}
 		}
 	}
-	if finalAnswer == "" && shouldFallbackToRAG(fallbackReason) {
+	if finalAnswer == "" && shouldFallbackToRAG(fallbackReason) && uc.allowFallback(ctx, userID, conversationID, profile, agentToolKnowledgeSearch) {
 		appendThinkingLine(loopCtx, &thinkingLines, "Fallback: searching knowledge base directly")
+		fallbackStart := time.Now()
 		fallbackAnswer, fallbackErr := uc.answerFromKnowledgeFallback(ctx, lastUserMessage)
+		trace.fallback(agentToolKnowledgeSearch, fallbackAnswer, fallbackErr, time.Since(fallbackStart))
 		if fallbackErr == nil && strings.TrimSpace(fallbackAnswer) != "" {
 			finalAnswer = fallbackAnswer
 		}
@@ -457,6 +632,8 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		finalAnswer = "I reached the current execution limits. Please refine the request and try again."
 	}
 
+	run := trace.finish(finalAnswer, fallbackReason, iterations)
+
 	thinkingContent := strings.Join(thinkingLines, "\n")
 	if thinkingContent != "" {
 		finalAnswer = fmt.Sprintf("<think>\n%s\n</think>\n\n%s", thinkingContent, finalAnswer)
@@ -509,6 +686,8 @@ func (uc *AgentChatUseCase) Complete(ctx context.Context, req domain.AgentChatRe
 		ToolsInvoked:   toolsInvoked,
 		FallbackReason: fallbackReason,
 		ToolEvents:     toolEvents,
+		AnswerStreamed: answerStreamed,
+		RunID:          saveAgentRun(ctx, uc.runs, run),
 	}, nil
 }
 
@@ -932,6 +1111,39 @@ func (uc *AgentChatUseCase) executeTaskTool(ctx context.Context, userID string,
 	}
 }
 
+// executeMemoryTool lets the agent remember, update, forget and list the
+// user's explicit facts.
+func (uc *AgentChatUseCase) executeMemoryTool(ctx context.Context, userID string, args map[string]any) (domain.AgentToolEvent, error) {
+	if uc.memoryFacts == nil {
+		return domain.AgentToolEvent{}, fmt.Errorf("memory tool is not configured")
+	}
+	var (
+		result any
+		err    error
+	)
+	action := strings.ToLower(strings.TrimSpace(stringFromArgs(args, "action", "")))
+	switch action {
+	case "remember":
+		result, err = uc.memoryFacts.Remember(ctx, userID, stringFromArgs(args, "content", ""), domain.MemoryFactSourceAgent)
+	case "update":
+		result, err = uc.memoryFacts.Update(ctx, userID, stringFromArgs(args, "id", ""), stringFromArgs(args, "content", ""))
+	case "forget":
+		id := stringFromArgs(args, "id", "")
+		if err = uc.memoryFacts.Forget(ctx, userID, id); err == nil {
+			result = map[string]string{"id": id, "status": "forgotten"}
+		}
+	case "list":
+		result, err = uc.memoryFacts.List(ctx, userID)
+	default:
+		return domain.AgentToolEvent{}, fmt.Errorf("unsupported memory action: %s", action)
+	}
+	if err != nil {
+		return domain.AgentToolEvent{}, fmt.Errorf("memory %s: %w", action, err)
+	}
+	payload, _ := json.Marshal(result)
+	return domain.AgentToolEvent{Tool: agentToolMemory, Status: "ok", Output: string(payload)}, nil
+}
+
 func (uc *AgentChatUseCase) maybePersistSummary(ctx context.Context, userID, conversationID string, currentTurn int, force bool) (bool, error) {
 	lastTurn, err := uc.memories.GetLastSummaryEndTurn(ctx, userID, conversationID)
 	if err != nil {
@@ -1080,14 +1292,21 @@ func parseTimeRFC3339(raw string) (time.Time, error) {
 }
 
 // toolSchemasFromRegistry converts MCPToolRegistry tools to domain.ToolSchema
-// for use with the ChatWithTools API.
-func toolSchemasFromRegistry(registry ports.MCPToolRegistry, webSearchAvailable bool) []domain.ToolSchema {
+// for use with the ChatWithTools API. A non-nil profile limits the schemas to
+// its tool allowlist.
+func toolSchemasFromRegistry(registry ports.MCPToolRegistry, webSearchAvailable, memoryAvailable bool, profile *domain.AgentSpec) []domain.ToolSchema {
 	if registry == nil {
 		return nil
 	}
 	var schemas []domain.ToolSchema
 	for _, t := range registry.ListTools() {
-		if t.Name == "web_search" && !webSearchAvailable {
+		if t.Name == agentToolWebSearch && !webSearchAvailable {
+			continue
+		}
+		if t.Name == agentToolMemory && !memoryAvailable {
+			continue
+		}
+		if profile != nil && !profile.AllowsTool(t.Name) {
 			continue
 		}
 		schemas = append(schemas, domain.ToolSchema{
@@ -1103,9 +1322,60 @@ func toolSchemasFromRegistry(registry ports.MCPToolRegistry, webSearchAvailable
 }
 
 // buildSystemPrompt builds the system prompt for the function-calling agent loop,
-// incorporating intent-specific guidance and long-term memory.
-func buildSystemPrompt(ctx context.Context, intent Intent, memoryHits []domain.MemoryHit, registry ports.MCPToolRegistry, vaults []ports.AgentVaultInfo) string {
+// incorporating intent-specific guidance and long-term memory. A specialist
+// profile replaces the assistant prompt with its own and only mentions the
+// tools it may call.
+func buildSystemPrompt(
+	ctx context.Context,
+	intent Intent,
+	profile *domain.AgentSpec,
+	memoryHits []domain.MemoryHit,
+	memoryFacts []domain.MemoryFact,
+	registry ports.MCPToolRegistry,
+	vaults []ports.AgentVaultInfo,
+) string {
 	var sb strings.Builder
+	if profile != nil {
+		writeSpecialistPrompt(&sb, profile)
+	} else {
+		writeAssistantPrompt(&sb, intent)
+	}
+
+	if len(vaults) > 0 && (profile == nil || profile.AllowsTool(agentToolObsidianWrite)) {
+		sb.WriteString("\nAvailable Obsidian vaults for obsidian_write tool:\n")
+		for _, v := range vaults {
+			fmt.Fprintf(&sb, "- %s (%s)\n", v.ID, v.Name)
+		}
+	}
+
+	if len(memoryFacts) > 0 {
+		sb.WriteString("\nFacts the user asked you to remember (trust these over older memory):\n")
+		for _, fact := range memoryFacts {
+			fmt.Fprintf(&sb, "- [%s] %s\n", fact.ID, strings.TrimSpace(fact.Content))
+		}
+	}
+
+	if len(memoryHits) > 0 {
+		sb.WriteString("\nRelevant long-term memory:\n")
+		for _, hit := range memoryHits {
+			fmt.Fprintf(&sb, "- [score=%.3f] %s\n", hit.Score, strings.TrimSpace(hit.Summary.Summary))
+		}
+	}
+
+	if registry != nil && (profile == nil || profile.AllowsTool("list_allowed_directories")) {
+		if fsCtx := probeFilesystemContext(ctx, registry); fsCtx != "" {
+			sb.WriteString("\n")
+			sb.WriteString(fsCtx)
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// writeAssistantPrompt writes the rules of the general-purpose assistant and
+// the guidance for intent.
+func writeAssistantPrompt(sb *strings.Builder, intent Intent) {
 	sb.WriteString(`You are a personal AI assistant. You have access to tools for searching knowledge, executing code, managing files, and more.
 
 RULES:
@@ -1118,35 +1388,54 @@ RULES:
 - MULTI-TOPIC: If the user asks about multiple unrelated topics in one message (e.g. "расскажи про Docker и Neovim"), call knowledge_search SEPARATELY for each topic with a focused query. Do NOT combine unrelated topics into one search query — this produces poor results for both topics.
 - When using web_search, always build specific, disambiguated queries. For example: if the conversation is about Rust programming language, search "Rust programming language news 2025", NOT just "Rust news". Add domain-specific keywords to avoid ambiguity (e.g. "Rust lang" vs "Rust game").
 - When user asks to save/write/create a note in Obsidian, use the obsidian_write tool. Pass vault id from the list of available vaults below.
+- When the user asks you to remember, correct or forget something about them, use the memory tool. To change or forget a known fact, pass its id from the list below.
 `)
 
 	sb.WriteString("\n")
 	sb.WriteString(systemPromptForIntent(intent))
 	sb.WriteString("\n")
+}
 
-	if len(vaults) > 0 {
-		sb.WriteString("\nAvailable Obsidian vaults for obsidian_write tool:\n")
-		for _, v := range vaults {
-			fmt.Fprintf(&sb, "- %s (%s)\n", v.ID, v.Name)
-		}
+// writeSpecialistPrompt writes the prompt of an orchestrated specialist:
+// its own instructions and rules that name only the tools it may call.
+func writeSpecialistPrompt(sb *strings.Builder, profile *domain.AgentSpec) {
+	sb.WriteString(strings.TrimSpace(profile.SystemPrompt))
+	sb.WriteString("\n\nRULES:\n- Always respond in Russian.\n")
+	if len(profile.Tools) == 0 {
+		sb.WriteString("- You have no tools. Answer from the task, its context and your knowledge.\n")
+		return
 	}
-
-	if len(memoryHits) > 0 {
-		sb.WriteString("\nRelevant long-term memory:\n")
-		for _, hit := range memoryHits {
-			fmt.Fprintf(&sb, "- [score=%.3f] %s\n", hit.Score, strings.TrimSpace(hit.Summary.Summary))
-		}
+	fmt.Fprintf(sb, "- You may only call these tools: %s.\n", strings.Join(profile.Tools, ", "))
+	sb.WriteString("- After using a tool, analyze its output and provide a complete answer.\n")
+	if profile.AllowsTool(agentToolKnowledgeSearch) {
+		sb.WriteString("- When answering from knowledge base results, cite the sources.\n")
+	}
+	if profile.AllowsTool(agentToolObsidianWrite) {
+		sb.WriteString("- To save a note in Obsidian, use the obsidian_write tool with a vault id from the list below.\n")
 	}
+}
 
-	if registry != nil {
-		if fsCtx := probeFilesystemContext(ctx, registry); fsCtx != "" {
-			sb.WriteString("\n")
-			sb.WriteString(fsCtx)
-			sb.WriteString("\n")
-		}
+// denyToolCall rejects a call to a tool outside the specialist's allowlist.
+// The model only sees allowed schemas, so a denied call means it invented or
+// remembered a tool name; the violation is logged and recorded as an event.
+// Direct fallbacks go through allowFallback and are recorded the same way.
+func (uc *AgentChatUseCase) denyToolCall(ctx context.Context, userID, conversationID string, profile *domain.AgentSpec, toolName string) (domain.AgentToolEvent, bool) {
+	if profile == nil || profile.AllowsTool(toolName) {
+		return domain.AgentToolEvent{}, false
 	}
+	slog.Warn("agent_tool_denied", "agent", profile.Name, "tool", toolName, "conversation_id", conversationID)
+	if uc.events != nil {
+		uc.events.RecordToolViolation(ctx, userID, conversationID, profile.Name, toolName)
+	}
+	payload, _ := json.Marshal(map[string]string{"error": fmt.Sprintf("tool %s is not allowed for agent %s", toolName, profile.Name)})
+	return domain.AgentToolEvent{Tool: toolName, Status: "error", Output: string(payload)}, true
+}
 
-	return sb.String()
+// allowFallback reports whether a direct tool fallback may run for the
+// specialist. A fallback outside its allowlist is recorded like a denied call.
+func (uc *AgentChatUseCase) allowFallback(ctx context.Context, userID, conversationID string, profile *domain.AgentSpec, toolName string) bool {
+	_, denied := uc.denyToolCall(ctx, userID, conversationID, profile, toolName)
+	return !denied
 }
 
 // executeToolCall dispatches a domain.ToolCall to the appropriate tool handler.
@@ -1222,6 +1511,9 @@ func (uc *AgentChatUseCase) executeToolCall(ctx context.Context, userID string,
 		action := stringFromArgs(args, "action", "")
 		return uc.executeTaskTool(ctx, userID, domain.AgentPlanStep{Input: args, Tool: toolName, Action: action})
 
+	case agentToolMemory:
+		return uc.executeMemoryTool(ctx, userID, args)
+
 	default:
 		// MCP tool
 		if uc.toolRegistry != nil && !uc.toolRegistry.IsBuiltIn(toolName) {
@@ -1236,7 +1528,8 @@ func (uc *AgentChatUseCase) executeToolCall(ctx context.Context, userID string,
 }
 
 func (uc *AgentChatUseCase) maybePersistToolMemory(ctx context.Context, userID, conversationID string, event domain.AgentToolEvent) {
-	if event.Status != "ok" || len(event.Output) < 200 {
+	// Memory tool output is already stored as facts.
+	if event.Status != "ok" || len(event.Output) < 200 || event.Tool == agentToolMemory {
 		return
 	}
 	summary := fmt.Sprintf("Tool %s result: %s", event.Tool, maybeSummarize(event.Output, 500))
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -44,6 +45,18 @@ func (f *fakeAgentQueryService) Answer(_ context.Context, _ string, _ int, _ dom
 	}, nil
 }
 
+func (f *fakeAgentQueryService) AnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
+func (f *fakeAgentQueryService) StreamAnswer(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
+func (f *fakeAgentQueryService) StreamAnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+
 func (f *fakeAgentQueryService) GenerateFromPrompt(_ context.Context, _ string) (string, error) {
 	if len(f.generateTextResponses) > 0 {
 		out := f.generateTextResponses[0]
@@ -151,6 +164,82 @@ func (f *fakeConversationStore) UpdateLastSummaryEndTurn(_ context.Context, _, _
 	return nil
 }
 
+func (f *fakeConversationStore) ListConversations(_ context.Context, userID string, _, _ int) ([]domain.Conversation, error) {
+	if f.conversation.UserID != userID {
+		return []domain.Conversation{}, nil
+	}
+	return []domain.Conversation{f.conversation}, nil
+}
+
+func (f *fakeConversationStore) GetConversation(_ context.Context, userID, conversationID string) (*domain.Conversation, error) {
+	if f.conversation.UserID != userID || f.conversation.ConversationID != conversationID {
+		return nil, domain.WrapError(domain.ErrConversationNotFound, "get conversation", errors.New("id="+conversationID))
+	}
+	conv := f.conversation
+	return &conv, nil
+}
+
+func (f *fakeConversationStore) ListMessages(_ context.Context, userID, conversationID string) ([]domain.ConversationMessage, error) {
+	out := make([]domain.ConversationMessage, 0)
+	for _, msg := range f.messages {
+		if msg.UserID == userID && msg.ConversationID == conversationID {
+			out = append(out, msg)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeConversationStore) SearchMessages(_ context.Context, userID, query string, limit int) ([]domain.ConversationMessage, error) {
+	out := make([]domain.ConversationMessage, 0)
+	for _, msg := range f.messages {
+		if msg.UserID == userID && strings.Contains(strings.ToLower(msg.Content), strings.ToLower(query)) && len(out) < limit {
+			out = append(out, msg)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeConversationStore) RenameConversation(ctx context.Context, userID, conversationID, title string) error {
+	if _, err := f.GetConversation(ctx, userID, conversationID); err != nil {
+		return err
+	}
+	f.conversation.Title = title
+	return nil
+}
+
+// DeleteConversation also deletes conversations known only by their
+// messages, such as the conversations of orchestration steps.
+func (f *fakeConversationStore) DeleteConversation(ctx context.Context, userID, conversationID string) error {
+	_, err := f.GetConversation(ctx, userID, conversationID)
+	found := err == nil
+	kept := make([]domain.ConversationMessage, 0, len(f.messages))
+	for _, msg := range f.messages {
+		if msg.UserID == userID && msg.ConversationID == conversationID {
+			found = true
+			continue
+		}
+		kept = append(kept, msg)
+	}
+	if !found {
+		return err
+	}
+	if err == nil {
+		f.conversation = domain.Conversation{}
+	}
+	f.messages = kept
+	return nil
+}
+
+func (f *fakeConversationStore) ListConversationIDsByPrefix(_ context.Context, userID, prefix string) ([]string, error) {
+	var ids []string
+	for _, msg := range f.messages {
+		if msg.UserID == userID && strings.HasPrefix(msg.ConversationID, prefix) && !slices.Contains(ids, msg.ConversationID) {
+			ids = append(ids, msg.ConversationID)
+		}
+	}
+	return ids, nil
+}
+
 type fakeTaskStore struct {
 	tasks map[string]domain.Task
 }
@@ -219,9 +308,22 @@ func (f *fakeMemoryStore) GetLastSummaryEndTurn(_ context.Context, _, _ string)
 	return f.lastTurn, nil
 }
 
+func (f *fakeMemoryStore) DeleteSummaries(_ context.Context, _, conversationID string) error {
+	kept := f.summaries[:0]
+	for _, summary := range f.summaries {
+		if summary.ConversationID != conversationID {
+			kept = append(kept, summary)
+		}
+	}
+	f.summaries = kept
+	return nil
+}
+
 type fakeMemoryVectorStore struct {
-	hits    []domain.MemoryHit
-	indexed []domain.MemorySummary
+	hits      []domain.MemoryHit
+	indexed   []domain.MemorySummary
+	deleted   []string
+	deleteErr error
 }
 
 func (f *fakeMemoryVectorStore) IndexSummary(_ context.Context, summary domain.MemorySummary, _ []float32) error {
@@ -233,6 +335,14 @@ func (f *fakeMemoryVectorStore) SearchSummaries(_ context.Context, _, _ string,
 	return append([]domain.MemoryHit(nil), f.hits...), nil
 }
 
+func (f *fakeMemoryVectorStore) DeleteSummaries(_ context.Context, _, conversationID string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	f.deleted = append(f.deleted, conversationID)
+	return nil
+}
+
 func TestAgentChatUseCaseFinalStep(t *testing.T) {
 	query := &fakeAgentQueryService{
 		chatToolsResponses: []domain.ChatToolsResult{
@@ -279,6 +389,88 @@ func TestAgentChatUseCaseFinalStep(t *testing.T) {
 	}
 }
 
+func TestAgentChatUseCaseStreamsFinalAnswerDeltas(t *testing.T) {
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(ctx context.Context, _ []domain.ChatMessage, _ []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			if cb := domain.AnswerCallbackFromContext(ctx); cb != nil {
+				cb("do")
+				cb("ne")
+			}
+			return &domain.ChatToolsResult{Content: "done"}, nil
+		},
+	}
+	uc := NewAgentChatUseCase(
+		query,
+		&fakeAgentEmbedder{},
+		&fakeConversationStore{},
+		&fakeTaskStore{},
+		&fakeMemoryStore{},
+		&fakeMemoryVectorStore{},
+		nil, // webSearcher
+		nil, // obsidianWriter
+		nil, // toolRegistry
+		domain.AgentLimits{},
+		nil, // agentMetrics
+	)
+
+	var deltas []string
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:        "u-1",
+		Messages:      []domain.AgentInputMessage{{Role: "user", Content: "hello"}},
+		OnAnswerDelta: func(text string) { deltas = append(deltas, text) },
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if strings.Join(deltas, "") != "done" {
+		t.Fatalf("expected planner deltas to be forwarded, got %q", deltas)
+	}
+	if !result.AnswerStreamed {
+		t.Fatal("expected AnswerStreamed to be set")
+	}
+}
+
+func TestAgentChatUseCaseForwardsDeltasBeforeModelReturns(t *testing.T) {
+	var received []string
+	var seenBeforeReturn []string
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(ctx context.Context, _ []domain.ChatMessage, _ []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			domain.AnswerCallbackFromContext(ctx)("do")
+			seenBeforeReturn = append([]string(nil), received...)
+			domain.AnswerCallbackFromContext(ctx)("ne")
+			return &domain.ChatToolsResult{Content: "done"}, nil
+		},
+	}
+	uc := NewAgentChatUseCase(
+		query,
+		&fakeAgentEmbedder{},
+		&fakeConversationStore{},
+		&fakeTaskStore{},
+		&fakeMemoryStore{},
+		&fakeMemoryVectorStore{},
+		nil, // webSearcher
+		nil, // obsidianWriter
+		nil, // toolRegistry
+		domain.AgentLimits{},
+		nil, // agentMetrics
+	)
+
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:        "u-1",
+		Messages:      []domain.AgentInputMessage{{Role: "user", Content: "hello"}},
+		OnAnswerDelta: func(text string) { received = append(received, text) },
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(seenBeforeReturn) != 1 || seenBeforeReturn[0] != "do" {
+		t.Fatalf("expected the first delta to reach the caller while the model was generating, got %q", seenBeforeReturn)
+	}
+	if !result.AnswerStreamed || len(received) != 2 {
+		t.Fatalf("unexpected result: streamed=%v deltas=%q", result.AnswerStreamed, received)
+	}
+}
+
 func TestAgentChatUseCaseToolThenFinal(t *testing.T) {
 	query := &fakeAgentQueryService{
 		chatToolsResponses: []domain.ChatToolsResult{
@@ -576,6 +768,7 @@ func (f *fakeGraphStore) AddSimilarity(context.Context, string, string, float64)
 	return nil
 }
 func (f *fakeGraphStore) RemoveSimilarities(context.Context, string) error { return nil }
+func (f *fakeGraphStore) DeleteDocument(context.Context, string) error     { return nil }
 func (f *fakeGraphStore) GetRelated(_ context.Context, _ string, _ int, _ int) ([]domain.GraphRelation, error) {
 	return f.relatedResult, nil
 }
@@ -1062,6 +1255,85 @@ func TestAgentChat_ToolResultCaching(t *testing.T) {
 	}
 }
 
+func TestAgentChat_ResumeReplaysCheckpoints(t *testing.T) {
+	var sent []domain.ChatMessage
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(_ context.Context, msgs []domain.ChatMessage, _ []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			sent = msgs
+			return &domain.ChatToolsResult{Content: "resumed answer"}, nil
+		},
+	}
+	conversations := &fakeConversationStore{}
+	uc := newTestAgentUC(query, func(uc *AgentChatUseCase) { uc.conversations = conversations })
+	var statuses []string
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "find info"}},
+		Resume: []domain.AgentCheckpoint{{
+			Turn:      1,
+			ToolCalls: []domain.ToolCall{{ID: "call-1", Function: domain.ToolCallFunc{Name: "knowledge_search", Arguments: map[string]any{"question": "q"}}}},
+			Events:    []domain.AgentToolEvent{{Tool: "knowledge_search", Status: "ok", Output: "stored result"}},
+		}},
+	}, func(tool, status string) { statuses = append(statuses, tool+":"+status) })
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(statuses) != 0 {
+		t.Fatalf("checkpointed tools must not run again, got %v", statuses)
+	}
+	if result.Answer != "resumed answer" || result.Iterations != 2 || len(result.ToolEvents) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	last := sent[len(sent)-1]
+	if last.Role != "tool" || last.ToolCallID != "call-1" || last.Content != "stored result" {
+		t.Fatalf("expected the stored tool result to be replayed, got %+v", last)
+	}
+	for _, msg := range conversations.messages {
+		if msg.Role == "user" {
+			t.Fatalf("resumed run must not store the user message again: %+v", msg)
+		}
+	}
+}
+
+func TestAgentChat_StoredTurnIsAnsweredWithoutStoringAgain(t *testing.T) {
+	conversations := &fakeConversationStore{}
+	uc := newTestAgentUC(&fakeAgentQueryService{}, func(uc *AgentChatUseCase) { uc.conversations = conversations })
+	req := domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "find info"}},
+	}
+
+	stored := 0
+	first := req
+	first.OnUserTurn = func(turn int) error {
+		stored = turn
+		return errors.New("job taken over")
+	}
+	if _, err := uc.Complete(context.Background(), first, nil); err == nil {
+		t.Fatal("expected the OnUserTurn error to stop the run")
+	}
+	retry := req
+	retry.Turn = stored
+	if _, err := uc.Complete(context.Background(), retry, nil); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+
+	var users, answers int
+	for _, msg := range conversations.messages {
+		switch {
+		case msg.Role == "user":
+			users++
+		case msg.Role == "assistant" && msg.UserTurn == stored:
+			answers++
+		}
+	}
+	if stored != 1 || users != 1 || answers != 1 {
+		t.Fatalf("turn = %d, user messages = %d, answers = %d; want one of each", stored, users, answers)
+	}
+}
+
 func TestAgentChat_WebIntentUsesDirectWebSearch(t *testing.T) {
 	ws := &fakeWebSearcher{results: []domain.WebSearchResult{
 		{Title: "HTTP", URL: "https://example.com/http", Snippet: "HTTP is a protocol"},
@@ -1373,7 +1645,7 @@ func TestExpandQueryWithGraph_WithResults(t *testing.T) {
 }
 
 func TestToolSchemasFromRegistry_Nil(t *testing.T) {
-	schemas := toolSchemasFromRegistry(nil, true)
+	schemas := toolSchemasFromRegistry(nil, true, true, nil)
 	if schemas != nil {
 		t.Fatalf("expected nil for nil registry, got %v", schemas)
 	}
@@ -1387,7 +1659,7 @@ func TestToolSchemasFromRegistry_FiltersWebSearch(t *testing.T) {
 		},
 	}
 	// web_search should be excluded when not available
-	schemas := toolSchemasFromRegistry(registry, false)
+	schemas := toolSchemasFromRegistry(registry, false, true, nil)
 	for _, s := range schemas {
 		if s.Function.Name == "web_search" {
 			t.Fatal("web_search should be filtered out when not available")
@@ -1395,7 +1667,7 @@ func TestToolSchemasFromRegistry_FiltersWebSearch(t *testing.T) {
 	}
 
 	// web_search should be included when available
-	schemas = toolSchemasFromRegistry(registry, true)
+	schemas = toolSchemasFromRegistry(registry, true, true, nil)
 	found := false
 	for _, s := range schemas {
 		if s.Function.Name == "web_search" {
@@ -1407,6 +1679,124 @@ func TestToolSchemasFromRegistry_FiltersWebSearch(t *testing.T) {
 	}
 }
 
+func TestAgentChat_ProfileRestrictsToolsAndIterations(t *testing.T) {
+	writer := &fakeObsidianWriter{}
+	events := &eventStoreFake{}
+	var offered []string
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(_ context.Context, _ []domain.ChatMessage, tools []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			offered = offered[:0]
+			for _, tool := range tools {
+				offered = append(offered, tool.Function.Name)
+			}
+			return &domain.ChatToolsResult{ToolCalls: []domain.ToolCall{{
+				ID:       "call-1",
+				Function: domain.ToolCallFunc{Name: "obsidian_write", Arguments: map[string]any{"title": "t", "content": "c"}},
+			}}}, nil
+		},
+	}
+	uc := newTestAgentUC(query, func(uc *AgentChatUseCase) {
+		uc.obsidianWriter = writer
+		uc.toolRegistry = &fakeMCPToolRegistry{tools: []ports.ToolDefinition{
+			{Name: "knowledge_search"}, {Name: "obsidian_write"}, {Name: "task_tool"},
+		}}
+		uc.SetEventCollector(NewEventCollector(events))
+	})
+
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:   "u-1",
+		Messages: []domain.AgentInputMessage{{Role: "user", Content: "review the answer"}},
+		Profile:  &domain.AgentSpec{Name: "critic", Tools: []string{"knowledge_search"}, MaxIterations: 2},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if len(offered) != 1 || offered[0] != "knowledge_search" {
+		t.Fatalf("expected only knowledge_search offered, got %v", offered)
+	}
+	if result.Iterations != 2 || result.FallbackReason != "max_iterations" {
+		t.Fatalf("expected the profile's budget of 2 iterations, got %d (%q)", result.Iterations, result.FallbackReason)
+	}
+	if writer.createdPath != "" {
+		t.Fatalf("critic must not write notes, got %q", writer.createdPath)
+	}
+	if len(result.ToolEvents) == 0 || result.ToolEvents[0].Status != "error" || !strings.Contains(result.ToolEvents[0].Output, "not allowed") {
+		t.Fatalf("expected a denied tool event, got %#v", result.ToolEvents)
+	}
+	if len(events.events) != 2 || events.events[0].EventType != "tool_violation" || events.events[0].Details["agent"] != "critic" {
+		t.Fatalf("expected two tool_violation events, got %#v", events.events)
+	}
+}
+
+func TestAgentChat_ProfileGatesFallbacks(t *testing.T) {
+	web := &fakeWebSearcher{results: []domain.WebSearchResult{{Title: "Go", URL: "https://go.dev", Snippet: "release"}}}
+	events := &eventStoreFake{}
+	query := &fakeAgentQueryService{chatToolsErr: errors.New("planner unavailable")}
+	uc := newTestAgentUC(query, func(uc *AgentChatUseCase) {
+		uc.webSearcher = web
+		uc.limits.IntentRouterEnabled = true
+		uc.SetEventCollector(NewEventCollector(events))
+	})
+
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:   "u-1",
+		Messages: []domain.AgentInputMessage{{Role: "user", Content: "search online for the latest go release"}},
+		Profile:  &domain.AgentSpec{Name: "coder", Tools: []string{"knowledge_search"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if web.calls != 0 {
+		t.Fatalf("coder must not fall back to web search, got %d searches", web.calls)
+	}
+	if !strings.HasSuffix(result.Answer, "knowledge answer") {
+		t.Fatalf("expected the allowed knowledge fallback to answer, got %q", result.Answer)
+	}
+	if len(events.events) != 1 || events.events[0].EventType != "tool_violation" || events.events[0].Details["tool"] != "web_search" {
+		t.Fatalf("expected one web_search tool_violation event, got %#v", events.events)
+	}
+
+	events.events = nil
+	result, err = uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:   "u-1",
+		Messages: []domain.AgentInputMessage{{Role: "user", Content: "draft a note"}},
+		Profile:  &domain.AgentSpec{Name: "writer", Tools: []string{"obsidian_write"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if strings.Contains(result.Answer, "knowledge answer") {
+		t.Fatalf("writer must not fall back to knowledge search, got %q", result.Answer)
+	}
+	if len(events.events) != 1 || events.events[0].Details["tool"] != "knowledge_search" {
+		t.Fatalf("expected one knowledge_search tool_violation event, got %#v", events.events)
+	}
+}
+
+func TestBuildSystemPrompt_UsesProfilePromptAndTools(t *testing.T) {
+	vaults := []ports.AgentVaultInfo{{ID: "main", Name: "Main"}}
+	critic := &domain.AgentSpec{Name: "critic", SystemPrompt: "You are a quality critic.", Tools: []string{"knowledge_search"}}
+
+	prompt := buildSystemPrompt(context.Background(), IntentGeneral, critic, nil, nil, nil, vaults)
+	if !strings.HasPrefix(prompt, "You are a quality critic.") {
+		t.Fatalf("expected the profile prompt first, got:\n%s", prompt)
+	}
+	for _, unwanted := range []string{"personal AI assistant", "web_search", "obsidian_write", "Obsidian vaults"} {
+		if strings.Contains(prompt, unwanted) {
+			t.Fatalf("critic prompt must not mention %q:\n%s", unwanted, prompt)
+		}
+	}
+	if !strings.Contains(prompt, "only call these tools: knowledge_search.") {
+		t.Fatalf("expected the allowed tools listed:\n%s", prompt)
+	}
+
+	writer := &domain.AgentSpec{Name: "writer", SystemPrompt: "You write notes.", Tools: []string{"obsidian_write"}}
+	prompt = buildSystemPrompt(context.Background(), IntentGeneral, writer, nil, nil, nil, vaults)
+	if !strings.Contains(prompt, "- main (Main)") {
+		t.Fatalf("expected vaults for a profile allowed to write notes:\n%s", prompt)
+	}
+}
+
 func TestStringFromArgs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	// agentJobHeartbeat is how often a running job reports to the store and
+	// checks whether it was canceled.
+	agentJobHeartbeat = 5 * time.Second
+	// agentJobStaleAfter is how long a running job may miss heartbeats, or a
+	// queued job wait for a worker, before it is handed out again.
+	agentJobStaleAfter = 2 * time.Minute
+	agentJobEventLimit = 500
+)
+
+// AgentJobOptions tunes agent job execution.
+type AgentJobOptions struct {
+	// Timeout replaces AgentLimits.Timeout for a job run.
+	Timeout time.Duration
+	// MaxIterations replaces AgentLimits.MaxIterations for a job run.
+	MaxIterations int
+	// MaxAttempts is how many workers may start a job before it fails.
+	MaxAttempts int
+}
+
+// AgentJobUseCase runs agent requests on workers. Every tool iteration is
+// checkpointed, so a job whose worker stopped is resumed by another one.
+type AgentJobUseCase struct {
+	store      ports.AgentJobStore
+	queue      ports.AgentJobQueue
+	agent      ports.AgentChatService
+	principals ports.PrincipalResolver
+	opts       AgentJobOptions
+	heartbeat  time.Duration
+	onFinished []func(context.Context, domain.AgentJob)
+}
+
+func NewAgentJobUseCase(
+	store ports.AgentJobStore,
+	queue ports.AgentJobQueue,
+	agent ports.AgentChatService,
+	opts AgentJobOptions,
+) *AgentJobUseCase {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Minute
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 30
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	return &AgentJobUseCase{
+		store:     store,
+		queue:     queue,
+		agent:     agent,
+		opts:      opts,
+		heartbeat: agentJobHeartbeat,
+	}
+}
+
+// SetPrincipals makes jobs run with the groups of their owner, so documents
+// shared with those groups are visible to the job.
+func (uc *AgentJobUseCase) SetPrincipals(principals ports.PrincipalResolver) {
+	uc.principals = principals
+}
+
+// OnFinished registers fn to run once a job reaches a final status.
+func (uc *AgentJobUseCase) OnFinished(fn func(context.Context, domain.AgentJob)) {
+	uc.onFinished = append(uc.onFinished, fn)
+}
+
+// Submit stores a queued job and hands it to the workers. A job whose
+// publish failed stays queued until RecoverStale publishes it again.
+func (uc *AgentJobUseCase) Submit(ctx context.Context, req domain.AgentJobRequest) (*domain.AgentJob, error) {
+	userID := strings.TrimSpace(req.UserID)
+	if userID == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "submit agent job", errors.New("user_id is required"))
+	}
+	prompt := strings.TrimSpace(req.Prompt)
+	if prompt == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "submit agent job", errors.New("prompt is required"))
+	}
+	source := req.Source
+	if source == "" {
+		source = domain.AgentJobSourceAPI
+	}
+	conversationID := strings.TrimSpace(req.ConversationID)
+	if conversationID == "" {
+		conversationID = uuid.NewString()
+	}
+
+	job := &domain.AgentJob{
+		ID:             uuid.NewString(),
+		UserID:         userID,
+		ConversationID: conversationID,
+		Prompt:         prompt,
+		Source:         source,
+		ScheduleID:     req.ScheduleID,
+		Status:         domain.AgentJobStatusQueued,
+		CreatedAt:      time.Now().UTC(),
+	}
+	if err := uc.store.CreateAgentJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("create agent job: %w", err)
+	}
+	uc.addEvent(ctx, job.ID, domain.AgentJobEvent{Type: domain.AgentJobEventStatus, Status: job.Status})
+	if err := uc.publish(ctx, job.ID); err != nil {
+		slog.Warn("agent_job_publish_failed", "job_id", job.ID, "error", err)
+	}
+	slog.Info("agent_job_submitted", "job_id", job.ID, "user_id", userID, "source", source)
+	return job, nil
+}
+
+func (uc *AgentJobUseCase) List(ctx context.Context, userID string, limit int) ([]domain.AgentJob, error) {
+	jobs, err := uc.store.ListAgentJobs(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent jobs: %w", err)
+	}
+	if jobs == nil {
+		jobs = []domain.AgentJob{}
+	}
+	return jobs, nil
+}
+
+// Get returns one of the user's jobs.
+func (uc *AgentJobUseCase) Get(ctx context.Context, userID, jobID string) (*domain.AgentJob, error) {
+	job, err := uc.store.GetAgentJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserID != userID {
+		return nil, domain.WrapError(domain.ErrAgentJobNotFound, "get agent job", fmt.Errorf("id=%s", jobID))
+	}
+	return job, nil
+}
+
+func (uc *AgentJobUseCase) Events(ctx context.Context, userID, jobID string, afterSeq int64, limit int) ([]domain.AgentJobEvent, error) {
+	if _, err := uc.Get(ctx, userID, jobID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > agentJobEventLimit {
+		limit = agentJobEventLimit
+	}
+	events, err := uc.store.ListAgentJobEvents(ctx, jobID, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent job events: %w", err)
+	}
+	if events == nil {
+		events = []domain.AgentJobEvent{}
+	}
+	return events, nil
+}
+
+// HasUnfinishedScheduleJob reports whether a job of the scheduled task is
+// still queued or running.
+func (uc *AgentJobUseCase) HasUnfinishedScheduleJob(ctx context.Context, scheduleID string) (bool, error) {
+	busy, err := uc.store.HasUnfinishedScheduleJob(ctx, scheduleID)
+	if err != nil {
+		return false, fmt.Errorf("check unfinished schedule jobs: %w", err)
+	}
+	return busy, nil
+}
+
+// Cancel stops a queued job right away; a running job is stopped by its
+// worker at the next heartbeat. Canceling a finished job changes nothing.
+func (uc *AgentJobUseCase) Cancel(ctx context.Context, userID, jobID string) (*domain.AgentJob, error) {
+	job, err := uc.Get(ctx, userID, jobID)
+	if err != nil || job.Finished() {
+		return job, err
+	}
+	if err := uc.store.CancelAgentJob(ctx, jobID); err != nil {
+		return nil, fmt.Errorf("cancel agent job: %w", err)
+	}
+	wasQueued := job.Status == domain.AgentJobStatusQueued
+	if job, err = uc.store.GetAgentJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+	if wasQueued && job.Status == domain.AgentJobStatusCanceled {
+		uc.finished(ctx, job)
+	}
+	return job, nil
+}
+
+// Run executes a job on this worker unless another worker owns it. When
+// ctx ends first the job is left running, to be resumed by another worker
+// once its heartbeat is stale. A run whose job was meanwhile claimed by
+// another worker stops without storing anything more.
+func (uc *AgentJobUseCase) Run(ctx context.Context, jobID string) error {
+	job, claimed, err := uc.store.ClaimAgentJob(ctx, jobID, time.Now().UTC().Add(-agentJobStaleAfter))
+	if err != nil {
+		return fmt.Errorf("claim agent job: %w", err)
+	}
+	if !claimed {
+		slog.Info("agent_job_not_claimed", "job_id", jobID)
+		return nil
+	}
+	switch {
+	case job.CancelRequested:
+		job.Status = domain.AgentJobStatusCanceled
+		return uc.finish(ctx, job)
+	case job.Attempts > uc.opts.MaxAttempts:
+		job.Status = domain.AgentJobStatusFailed
+		job.Error = fmt.Sprintf("gave up after %d attempts", uc.opts.MaxAttempts)
+		return uc.finish(ctx, job)
+	}
+
+	// Run as the job owner so retrieval only sees documents they can read.
+	ownerCtx, err := withOwnerPrincipal(ctx, uc.principals, job.UserID)
+	if err != nil {
+		if !domain.IsKind(err, domain.ErrUserNotFound) {
+			return fmt.Errorf("run agent job: %w", err)
+		}
+		job.Status = domain.AgentJobStatusFailed
+		job.Error = err.Error()
+		return uc.finish(ctx, job)
+	}
+
+	note := ""
+	if len(job.Checkpoints) > 0 {
+		note = fmt.Sprintf("resumed from %d checkpoints", len(job.Checkpoints))
+	}
+	uc.addEvent(ctx, job.ID, domain.AgentJobEvent{Type: domain.AgentJobEventStatus, Status: domain.AgentJobStatusRunning, Content: note})
+	slog.Info("agent_job_started", "job_id", job.ID, "attempt", job.Attempts, "checkpoints", len(job.Checkpoints))
+
+	runCtx, cancel := context.WithCancel(ownerCtx)
+	defer cancel()
+	var canceled, lost atomic.Bool
+	// disown stops the run once another worker has claimed the job.
+	disown := func(err error) bool {
+		if !domain.IsKind(err, domain.ErrAgentJobNotOwned) {
+			return false
+		}
+		lost.Store(true)
+		cancel()
+		return true
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(uc.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				requested, err := uc.store.HeartbeatAgentJob(ctx, job.ID, job.Attempts)
+				if disown(err) {
+					return
+				}
+				if err != nil {
+					slog.Warn("agent_job_heartbeat_failed", "job_id", job.ID, "error", err)
+					continue
+				}
+				if requested {
+					canceled.Store(true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	req := domain.AgentChatRequest{
+		UserID:         job.UserID,
+		ConversationID: job.ConversationID,
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: job.Prompt}},
+		Timeout:        uc.opts.Timeout,
+		MaxIterations:  uc.opts.MaxIterations,
+		Turn:           job.Turn,
+		Resume:         job.Checkpoints,
+		OnUserTurn: func(turn int) error {
+			err := uc.store.SetAgentJobTurn(context.WithoutCancel(ctx), job.ID, job.Attempts, turn)
+			disown(err)
+			return err
+		},
+		OnCheckpoint: func(checkpoint domain.AgentCheckpoint) {
+			err := uc.store.AddAgentJobCheckpoint(context.WithoutCancel(ctx), job.ID, job.Attempts, checkpoint)
+			if err != nil && !disown(err) {
+				slog.Warn("agent_job_checkpoint_failed", "job_id", job.ID, "error", err)
+			}
+		},
+	}
+	onToolStatus := func(tool, status string) {
+		uc.addEvent(ctx, job.ID, domain.AgentJobEvent{Type: domain.AgentJobEventTool, Tool: tool, Status: status})
+	}
+	result, err := uc.agent.Complete(runCtx, req, onToolStatus)
+
+	switch {
+	case lost.Load():
+		slog.Warn("agent_job_taken_over", "job_id", job.ID, "attempt", job.Attempts)
+		return nil
+	case canceled.Load():
+		job.Status = domain.AgentJobStatusCanceled
+	case ctx.Err() != nil:
+		slog.Info("agent_job_interrupted", "job_id", job.ID)
+		return ctx.Err()
+	case err != nil:
+		job.Status = domain.AgentJobStatusFailed
+		job.Error = err.Error()
+	default:
+		job.Status = domain.AgentJobStatusCompleted
+		job.Answer = result.Answer
+		uc.addEvent(ctx, job.ID, domain.AgentJobEvent{Type: domain.AgentJobEventAnswer, Content: result.Answer})
+	}
+	return uc.finish(context.WithoutCancel(ctx), job)
+}
+
+// RecoverStale hands out again the jobs whose worker stopped and the queued
+// jobs whose publish failed. Published queued jobs wait for a free worker and
+// are not published twice.
+func (uc *AgentJobUseCase) RecoverStale(ctx context.Context) {
+	ids, err := uc.store.ListStaleAgentJobs(ctx, time.Now().UTC().Add(-agentJobStaleAfter), 100)
+	if err != nil {
+		slog.Warn("agent_job_recover_failed", "error", err)
+		return
+	}
+	for _, id := range ids {
+		if err := uc.publish(ctx, id); err != nil {
+			slog.Warn("agent_job_publish_failed", "job_id", id, "error", err)
+		}
+	}
+	if len(ids) > 0 {
+		slog.Info("agent_jobs_recovered", "count", len(ids))
+	}
+}
+
+// publish hands a job to the workers and records that it did, so
+// RecoverStale only publishes it again once it is lost.
+func (uc *AgentJobUseCase) publish(ctx context.Context, jobID string) error {
+	if err := uc.queue.PublishAgentJob(ctx, jobID); err != nil {
+		return err
+	}
+	if err := uc.store.MarkAgentJobPublished(ctx, jobID, time.Now().UTC()); err != nil {
+		slog.Warn("agent_job_mark_published_failed", "job_id", jobID, "error", err)
+	}
+	return nil
+}
+
+func (uc *AgentJobUseCase) finish(ctx context.Context, job *domain.AgentJob) error {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	if err := uc.store.FinishAgentJob(ctx, job); err != nil {
+		if domain.IsKind(err, domain.ErrAgentJobNotOwned) {
+			slog.Warn("agent_job_taken_over", "job_id", job.ID, "attempt", job.Attempts)
+			return nil
+		}
+		return fmt.Errorf("finish agent job: %w", err)
+	}
+	uc.finished(ctx, job)
+	return nil
+}
+
+func (uc *AgentJobUseCase) finished(ctx context.Context, job *domain.AgentJob) {
+	uc.addEvent(ctx, job.ID, domain.AgentJobEvent{Type: domain.AgentJobEventStatus, Status: job.Status, Content: job.Error})
+	slog.Info("agent_job_finished", "job_id", job.ID, "status", job.Status, "attempts", job.Attempts)
+	for _, fn := range uc.onFinished {
+		fn(ctx, *job)
+	}
+}
+
+// addEvent records progress best-effort; a lost event must not fail the job.
+func (uc *AgentJobUseCase) addEvent(ctx context.Context, jobID string, event domain.AgentJobEvent) {
+	event.CreatedAt = time.Now().UTC()
+	if err := uc.store.AddAgentJobEvent(context.WithoutCancel(ctx), jobID, event); err != nil {
+		slog.Warn("agent_job_event_failed", "job_id", jobID, "type", event.Type, "error", err)
+	}
+}
@@ -0,0 +1,578 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+type agentJobStoreFake struct {
+	mu     sync.Mutex
+	jobs   map[string]*domain.AgentJob
+	events map[string][]domain.AgentJobEvent
+	seq    int64
+	// published holds the last publish time of each job.
+	published map[string]time.Time
+}
+
+func newAgentJobStoreFake() *agentJobStoreFake {
+	return &agentJobStoreFake{
+		jobs:      map[string]*domain.AgentJob{},
+		events:    map[string][]domain.AgentJobEvent{},
+		published: map[string]time.Time{},
+	}
+}
+
+func (f *agentJobStoreFake) CreateAgentJob(_ context.Context, job *domain.AgentJob) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *job
+	f.jobs[job.ID] = &stored
+	return nil
+}
+
+func (f *agentJobStoreFake) GetAgentJob(_ context.Context, id string) (*domain.AgentJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrAgentJobNotFound, "get agent job", errors.New(id))
+	}
+	copied := *job
+	copied.Checkpoints = slices.Clone(job.Checkpoints)
+	return &copied, nil
+}
+
+func (f *agentJobStoreFake) ListAgentJobs(_ context.Context, userID string, _ int) ([]domain.AgentJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var jobs []domain.AgentJob
+	for _, job := range f.jobs {
+		if job.UserID == userID {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *agentJobStoreFake) ClaimAgentJob(_ context.Context, id string, staleBefore time.Time) (*domain.AgentJob, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, false, nil
+	}
+	stale := job.Status == domain.AgentJobStatusRunning && job.HeartbeatAt != nil && job.HeartbeatAt.Before(staleBefore)
+	if job.Status != domain.AgentJobStatusQueued && !stale {
+		return nil, false, nil
+	}
+	now := time.Now().UTC()
+	job.Status = domain.AgentJobStatusRunning
+	job.Attempts++
+	job.HeartbeatAt = &now
+	copied := *job
+	copied.Checkpoints = slices.Clone(job.Checkpoints)
+	return &copied, true, nil
+}
+
+// owned returns the job when attempt still owns it.
+func (f *agentJobStoreFake) owned(id string, attempt int) (*domain.AgentJob, error) {
+	job := f.jobs[id]
+	if job.Attempts != attempt || job.Status != domain.AgentJobStatusRunning {
+		return nil, domain.WrapError(domain.ErrAgentJobNotOwned, "agent job", errors.New(id))
+	}
+	return job, nil
+}
+
+func (f *agentJobStoreFake) HeartbeatAgentJob(_ context.Context, id string, attempt int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, err := f.owned(id, attempt)
+	if err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	job.HeartbeatAt = &now
+	return job.CancelRequested, nil
+}
+
+func (f *agentJobStoreFake) FinishAgentJob(_ context.Context, job *domain.AgentJob) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored, err := f.owned(job.ID, job.Attempts)
+	if err != nil {
+		return err
+	}
+	stored.Status, stored.Answer, stored.Error, stored.FinishedAt = job.Status, job.Answer, job.Error, job.FinishedAt
+	return nil
+}
+
+func (f *agentJobStoreFake) SetAgentJobTurn(_ context.Context, id string, attempt, turn int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, err := f.owned(id, attempt)
+	if err != nil {
+		return err
+	}
+	job.Turn = turn
+	return nil
+}
+
+func (f *agentJobStoreFake) CancelAgentJob(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job := f.jobs[id]
+	job.CancelRequested = true
+	if job.Status == domain.AgentJobStatusQueued {
+		job.Status = domain.AgentJobStatusCanceled
+	}
+	return nil
+}
+
+func (f *agentJobStoreFake) AddAgentJobCheckpoint(_ context.Context, id string, attempt int, checkpoint domain.AgentCheckpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, err := f.owned(id, attempt)
+	if err != nil {
+		return err
+	}
+	job.Checkpoints = append(job.Checkpoints, checkpoint)
+	return nil
+}
+
+func (f *agentJobStoreFake) AddAgentJobEvent(_ context.Context, jobID string, event domain.AgentJobEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.seq++
+	event.Seq = f.seq
+	f.events[jobID] = append(f.events[jobID], event)
+	return nil
+}
+
+func (f *agentJobStoreFake) ListAgentJobEvents(_ context.Context, jobID string, afterSeq int64, _ int) ([]domain.AgentJobEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var events []domain.AgentJobEvent
+	for _, event := range f.events[jobID] {
+		if event.Seq > afterSeq {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (f *agentJobStoreFake) MarkAgentJobPublished(_ context.Context, id string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.published[id] = at
+	return nil
+}
+
+func (f *agentJobStoreFake) ListStaleAgentJobs(_ context.Context, staleBefore time.Time, _ int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for id, job := range f.jobs {
+		published, ok := f.published[id]
+		switch job.Status {
+		case domain.AgentJobStatusQueued:
+			if !ok {
+				ids = append(ids, id)
+			}
+		case domain.AgentJobStatusRunning:
+			if job.HeartbeatAt != nil && job.HeartbeatAt.Before(staleBefore) && (!ok || published.Before(*job.HeartbeatAt)) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (f *agentJobStoreFake) HasUnfinishedScheduleJob(_ context.Context, scheduleID string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, job := range f.jobs {
+		if job.ScheduleID == scheduleID && !job.Finished() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *agentJobStoreFake) DeleteByConversation(_ context.Context, userID, conversationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, job := range f.jobs {
+		if job.UserID == userID && job.ConversationID == conversationID {
+			delete(f.jobs, id)
+			delete(f.events, id)
+		}
+	}
+	return nil
+}
+
+func (f *agentJobStoreFake) eventTypes(jobID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var types []string
+	for _, event := range f.events[jobID] {
+		types = append(types, event.Type+":"+event.Status)
+	}
+	return types
+}
+
+type agentJobQueueFake struct {
+	published []string
+	err       error
+}
+
+func (f *agentJobQueueFake) PublishAgentJob(_ context.Context, jobID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.published = append(f.published, jobID)
+	return nil
+}
+
+func (f *agentJobQueueFake) SubscribeAgentJob(context.Context, func(context.Context, string) error) error {
+	return nil
+}
+
+// agentJobAgentFake stores the user turn, runs one tool iteration unless
+// skipCheckpoint is set, then answers or, when block is set, waits for its
+// context to end.
+type agentJobAgentFake struct {
+	block          bool
+	skipCheckpoint bool
+	req            domain.AgentChatRequest
+	principal      domain.Principal
+}
+
+func (f *agentJobAgentFake) Complete(ctx context.Context, req domain.AgentChatRequest, onToolStatus domain.ToolStatusCallback) (*domain.AgentRunResult, error) {
+	f.req = req
+	f.principal, _ = domain.PrincipalFromContext(ctx)
+	if req.Turn == 0 {
+		if err := req.OnUserTurn(1); err != nil {
+			return nil, err
+		}
+	}
+	if len(req.Resume) == 0 && !f.skipCheckpoint {
+		onToolStatus("knowledge_search", "done")
+		req.OnCheckpoint(domain.AgentCheckpoint{
+			Turn:      1,
+			ToolCalls: []domain.ToolCall{{ID: "call-1", Function: domain.ToolCallFunc{Name: "knowledge_search"}}},
+			Events:    []domain.AgentToolEvent{{Tool: "knowledge_search", Status: "ok"}},
+		})
+	}
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return &domain.AgentRunResult{Answer: "done"}, nil
+}
+
+func (f *agentJobAgentFake) SetObsidianWriter(ports.ObsidianNoteWriter) {}
+
+func (f *agentJobAgentFake) SetObsidianVaults([]ports.AgentVaultInfo) {}
+
+type principalResolverFake map[string][]string
+
+func (f principalResolverFake) PrincipalFor(_ context.Context, userID string) (*domain.Principal, error) {
+	groups, ok := f[userID]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrUserNotFound, "get user", errors.New(userID))
+	}
+	return &domain.Principal{UserID: userID, Groups: groups}, nil
+}
+
+func TestAgentJobSubmitQueuesAndScopesToOwner(t *testing.T) {
+	store, queue := newAgentJobStoreFake(), &agentJobQueueFake{}
+	uc := NewAgentJobUseCase(store, queue, &agentJobAgentFake{}, AgentJobOptions{})
+
+	job, err := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: " summarize the wiki "})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if job.Status != domain.AgentJobStatusQueued || job.Prompt != "summarize the wiki" || job.ConversationID == "" || job.Source != domain.AgentJobSourceAPI {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if !slices.Equal(queue.published, []string{job.ID}) {
+		t.Fatalf("published = %v, want [%s]", queue.published, job.ID)
+	}
+	if _, err := uc.Get(context.Background(), "bob", job.ID); !domain.IsKind(err, domain.ErrAgentJobNotFound) {
+		t.Fatalf("another user's job: err = %v, want ErrAgentJobNotFound", err)
+	}
+	if _, err := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice"}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("empty prompt: err = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestAgentJobRunCompletesAndCheckpoints(t *testing.T) {
+	store, agent := newAgentJobStoreFake(), &agentJobAgentFake{}
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, agent, AgentJobOptions{Timeout: time.Hour, MaxIterations: 40})
+	var finished []domain.AgentJob
+	uc.OnFinished(func(_ context.Context, job domain.AgentJob) { finished = append(finished, job) })
+
+	job, err := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "research"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if err := uc.Run(context.Background(), job.ID); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if agent.req.Timeout != time.Hour || agent.req.MaxIterations != 40 || agent.req.ConversationID != job.ConversationID {
+		t.Fatalf("unexpected agent request: %+v", agent.req)
+	}
+	got, _ := store.GetAgentJob(context.Background(), job.ID)
+	if got.Status != domain.AgentJobStatusCompleted || got.Answer != "done" || got.FinishedAt == nil || len(got.Checkpoints) != 1 {
+		t.Fatalf("unexpected job after run: %+v", got)
+	}
+	want := []string{"status:queued", "status:running", "tool:done", "answer:", "status:completed"}
+	if types := store.eventTypes(job.ID); !slices.Equal(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	if len(finished) != 1 || finished[0].Status != domain.AgentJobStatusCompleted {
+		t.Fatalf("finished hooks = %+v", finished)
+	}
+	if err := uc.Run(context.Background(), job.ID); err != nil || len(finished) != 1 {
+		t.Fatalf("second delivery must be ignored: err = %v, finished = %d", err, len(finished))
+	}
+}
+
+func TestAgentJobRecoverStaleRepublishesOnlyLostQueuedJobs(t *testing.T) {
+	store, queue := newAgentJobStoreFake(), &agentJobQueueFake{}
+	uc := NewAgentJobUseCase(store, queue, &agentJobAgentFake{}, AgentJobOptions{})
+
+	waiting, err := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "long research"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	queue.err = errors.New("nats unavailable")
+	lost, err := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "digest"})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	// Both jobs have been queued for longer than the stale window.
+	old := time.Now().UTC().Add(-time.Hour)
+	store.jobs[waiting.ID].CreatedAt, store.jobs[lost.ID].CreatedAt = old, old
+
+	queue.err = nil
+	queue.published = nil
+	uc.RecoverStale(context.Background())
+	uc.RecoverStale(context.Background())
+	if !slices.Equal(queue.published, []string{lost.ID}) {
+		t.Fatalf("published = %v, want only the job whose publish failed, once", queue.published)
+	}
+}
+
+func TestAgentJobResumesStaleRunFromCheckpoints(t *testing.T) {
+	store, agent := newAgentJobStoreFake(), &agentJobAgentFake{}
+	stale := time.Now().UTC().Add(-time.Hour)
+	checkpoint := domain.AgentCheckpoint{Turn: 3, ToolCalls: []domain.ToolCall{{ID: "call-1"}}}
+	store.jobs["j-1"] = &domain.AgentJob{
+		ID: "j-1", UserID: "alice", ConversationID: "c-1", Prompt: "research",
+		Status: domain.AgentJobStatusRunning, Attempts: 1, HeartbeatAt: &stale,
+		Checkpoints: []domain.AgentCheckpoint{checkpoint},
+	}
+	queue := &agentJobQueueFake{}
+	uc := NewAgentJobUseCase(store, queue, agent, AgentJobOptions{})
+
+	uc.RecoverStale(context.Background())
+	if !slices.Equal(queue.published, []string{"j-1"}) {
+		t.Fatalf("published = %v, want [j-1]", queue.published)
+	}
+	uc.RecoverStale(context.Background())
+	if len(queue.published) != 1 {
+		t.Fatalf("a stale job republished once must wait for a worker, published = %v", queue.published)
+	}
+	if err := uc.Run(context.Background(), "j-1"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(agent.req.Resume) != 1 || agent.req.Resume[0].Turn != 3 {
+		t.Fatalf("resume = %+v, want the stored checkpoint", agent.req.Resume)
+	}
+	got, _ := store.GetAgentJob(context.Background(), "j-1")
+	if got.Status != domain.AgentJobStatusCompleted || got.Attempts != 2 {
+		t.Fatalf("unexpected job after resume: %+v", got)
+	}
+}
+
+func TestAgentJobGivesUpAfterMaxAttempts(t *testing.T) {
+	store := newAgentJobStoreFake()
+	stale := time.Now().UTC().Add(-time.Hour)
+	store.jobs["j-1"] = &domain.AgentJob{ID: "j-1", UserID: "alice", Status: domain.AgentJobStatusRunning, Attempts: 2, HeartbeatAt: &stale}
+	agent := &agentJobAgentFake{}
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, agent, AgentJobOptions{MaxAttempts: 2})
+
+	if err := uc.Run(context.Background(), "j-1"); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	got, _ := store.GetAgentJob(context.Background(), "j-1")
+	if got.Status != domain.AgentJobStatusFailed || got.Error == "" || agent.req.UserID != "" {
+		t.Fatalf("expected failed job without a run, got %+v", got)
+	}
+}
+
+func TestAgentJobCancel(t *testing.T) {
+	store := newAgentJobStoreFake()
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, &agentJobAgentFake{block: true}, AgentJobOptions{})
+	uc.heartbeat = 5 * time.Millisecond
+	var finished []string
+	uc.OnFinished(func(_ context.Context, job domain.AgentJob) { finished = append(finished, job.Status) })
+
+	queued, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "a"})
+	got, err := uc.Cancel(context.Background(), "alice", queued.ID)
+	if err != nil || got.Status != domain.AgentJobStatusCanceled {
+		t.Fatalf("cancel queued: job = %+v, err = %v", got, err)
+	}
+
+	running, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "b"})
+	done := make(chan error, 1)
+	go func() { done <- uc.Run(context.Background(), running.ID) }()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if job, _ := store.GetAgentJob(context.Background(), running.ID); job.Status == domain.AgentJobStatusRunning {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := uc.Cancel(context.Background(), "alice", running.ID); err != nil {
+		t.Fatalf("cancel running: %v", err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("running job was not stopped by its heartbeat")
+	}
+	got, _ = store.GetAgentJob(context.Background(), running.ID)
+	if got.Status != domain.AgentJobStatusCanceled {
+		t.Fatalf("status = %s, want canceled", got.Status)
+	}
+	if !slices.Equal(finished, []string{domain.AgentJobStatusCanceled, domain.AgentJobStatusCanceled}) {
+		t.Fatalf("finished hooks = %v", finished)
+	}
+}
+
+func TestAgentJobInterruptedRunStaysResumable(t *testing.T) {
+	store := newAgentJobStoreFake()
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, &agentJobAgentFake{block: true}, AgentJobOptions{})
+	job, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := uc.Run(ctx, job.ID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	got, _ := store.GetAgentJob(context.Background(), job.ID)
+	if got.Status != domain.AgentJobStatusRunning || got.FinishedAt != nil || len(got.Checkpoints) != 1 {
+		t.Fatalf("interrupted job must stay running with its checkpoint: %+v", got)
+	}
+}
+
+func TestAgentJobRunsWithOwnerGroups(t *testing.T) {
+	store, agent := newAgentJobStoreFake(), &agentJobAgentFake{}
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, agent, AgentJobOptions{})
+	uc.SetPrincipals(principalResolverFake{"alice": {"eng"}})
+
+	job, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "a"})
+	if err := uc.Run(context.Background(), job.ID); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if agent.principal.UserID != "alice" || !slices.Equal(agent.principal.Groups, []string{"eng"}) {
+		t.Fatalf("principal = %+v, want alice in eng", agent.principal)
+	}
+
+	orphan, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "ghost", Prompt: "b"})
+	if err := uc.Run(context.Background(), orphan.ID); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	got, _ := store.GetAgentJob(context.Background(), orphan.ID)
+	if got.Status != domain.AgentJobStatusFailed || agent.req.UserID == "ghost" {
+		t.Fatalf("job of a removed user must fail without a run: %+v", got)
+	}
+}
+
+func TestAgentJobStopsAfterTakeover(t *testing.T) {
+	store := newAgentJobStoreFake()
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, &agentJobAgentFake{block: true}, AgentJobOptions{})
+	uc.heartbeat = 5 * time.Millisecond
+	var finished []string
+	uc.OnFinished(func(_ context.Context, job domain.AgentJob) { finished = append(finished, job.Status) })
+
+	job, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "a"})
+	done := make(chan error, 1)
+	go func() { done <- uc.Run(context.Background(), job.ID) }()
+	deadline := time.Now().Add(time.Second)
+	for {
+		if got, _ := store.GetAgentJob(context.Background(), job.ID); len(got.Checkpoints) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("job did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	// Another worker claims the job after this one's heartbeat went stale.
+	store.mu.Lock()
+	store.jobs[job.ID].Attempts++
+	store.mu.Unlock()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("run was not stopped after another worker claimed the job")
+	}
+	got, _ := store.GetAgentJob(context.Background(), job.ID)
+	if got.Status != domain.AgentJobStatusRunning || got.FinishedAt != nil || len(finished) != 0 {
+		t.Fatalf("stale run must leave the job to its new owner: %+v, finished = %v", got, finished)
+	}
+}
+
+func TestAgentJobRetryAnswersStoredTurn(t *testing.T) {
+	store := newAgentJobStoreFake()
+	agent := &agentJobAgentFake{block: true, skipCheckpoint: true}
+	uc := NewAgentJobUseCase(store, &agentJobQueueFake{}, agent, AgentJobOptions{})
+	job, _ := uc.Submit(context.Background(), domain.AgentJobRequest{UserID: "alice", Prompt: "a"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	if err := uc.Run(ctx, job.ID); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	got, _ := store.GetAgentJob(context.Background(), job.ID)
+	if got.Turn != 1 || len(got.Checkpoints) != 0 {
+		t.Fatalf("interrupted job must keep its turn: %+v", got)
+	}
+
+	stale := time.Now().UTC().Add(-time.Hour)
+	store.jobs[job.ID].HeartbeatAt = &stale
+	agent.block = false
+	if err := uc.Run(context.Background(), job.ID); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if agent.req.Turn != 1 {
+		t.Fatalf("retry turn = %d, want the stored turn 1", agent.req.Turn)
+	}
+}
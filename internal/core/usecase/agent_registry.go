@@ -8,24 +8,28 @@ var defaultAgentSpecs = []domain.AgentSpec{
 		SystemPrompt:  "You are a research specialist. Find facts, sources, and relevant context from the knowledge base and web. Be thorough and cite sources. Return structured findings.",
 		Tools:         []string{"knowledge_search", "web_search"},
 		MaxIterations: 5,
+		ModelTier:     domain.TierComplex,
 	},
 	{
 		Name:          "coder",
 		SystemPrompt:  "You are a code specialist. Generate, analyze, debug, and explain code. Provide working examples with clear explanations.",
 		Tools:         []string{"knowledge_search"},
 		MaxIterations: 5,
+		ModelTier:     domain.TierCode,
 	},
 	{
 		Name:          "writer",
 		SystemPrompt:  "You are a writing specialist. Synthesize information from previous research into a clear, well-structured response. Use headings, bullet points, and examples where appropriate.",
 		Tools:         []string{"knowledge_search"},
 		MaxIterations: 3,
+		ModelTier:     domain.TierComplex,
 	},
 	{
 		Name:          "critic",
 		SystemPrompt:  "You are a quality critic. Check the previous answer for factual errors, hallucinations, missing information, logical gaps, and unclear explanations. Be strict and specific about issues found. If the answer is good, say so explicitly.",
 		Tools:         []string{"knowledge_search"},
 		MaxIterations: 3,
+		ModelTier:     domain.TierComplex,
 	},
 }
 
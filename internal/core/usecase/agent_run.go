@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/routing"
+)
+
+// AgentRunOptions limit replays.
+type AgentRunOptions struct {
+	MaxIterations int
+	Timeout       time.Duration
+	// Providers are the configured LLM providers a replay may target. An
+	// empty list accepts any name.
+	Providers []string
+}
+
+// AgentRunUseCase serves recorded agent runs and replays them. A replay
+// sends the recorded request again, optionally to another model or with
+// another system prompt, and answers every tool call from the results
+// recorded in the trace instead of executing the tool.
+type AgentRunUseCase struct {
+	store ports.AgentRunStore
+	llm   ports.DocumentQueryService
+	opts  AgentRunOptions
+}
+
+func NewAgentRunUseCase(store ports.AgentRunStore, llm ports.DocumentQueryService, opts AgentRunOptions) *AgentRunUseCase {
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 6
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 90 * time.Second
+	}
+	return &AgentRunUseCase{store: store, llm: llm, opts: opts}
+}
+
+func (uc *AgentRunUseCase) List(ctx context.Context, userID, conversationID string, limit int) ([]domain.AgentRun, error) {
+	runs, err := uc.store.ListAgentRuns(ctx, userID, strings.TrimSpace(conversationID), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent runs: %w", err)
+	}
+	if runs == nil {
+		runs = []domain.AgentRun{}
+	}
+	return runs, nil
+}
+
+// Get returns one of the user's runs with its steps.
+func (uc *AgentRunUseCase) Get(ctx context.Context, userID, runID string) (*domain.AgentRun, error) {
+	run, err := uc.store.GetAgentRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if run.UserID != userID {
+		return nil, domain.WrapError(domain.ErrAgentRunNotFound, "get agent run", fmt.Errorf("id=%s", runID))
+	}
+	return run, nil
+}
+
+func (uc *AgentRunUseCase) Replay(ctx context.Context, userID, runID string, opts domain.AgentReplayOptions) (*domain.AgentRun, error) {
+	run, err := uc.Get(ctx, userID, runID)
+	if err != nil {
+		return nil, err
+	}
+	messages := replayRequest(run.Steps)
+	if len(messages) == 0 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "replay agent run", errors.New("run made no model calls"))
+	}
+	if prompt := strings.TrimSpace(opts.SystemPrompt); prompt != "" {
+		if messages[0].Role == "system" {
+			messages[0].Content = prompt
+		} else {
+			messages = slices.Insert(messages, 0, domain.ChatMessage{Role: "system", Content: prompt})
+		}
+	}
+	model := strings.TrimSpace(opts.Model)
+	if model != "" && len(uc.opts.Providers) > 0 && !slices.Contains(uc.opts.Providers, model) {
+		// The router would quietly use its default provider, and the replay
+		// would claim a model that never ran.
+		return nil, domain.WrapError(domain.ErrInvalidInput, "replay agent run", fmt.Errorf("unknown model %q", opts.Model))
+	}
+	if model == "" {
+		model = run.Model
+	}
+	if model != "" {
+		ctx = routing.WithProvider(ctx, model)
+	}
+
+	trace := newRunTrace(run.UserID, run.ConversationID, run.Input)
+	trace.run.ReplayOf = run.ID
+	trace.route(run.Intent, run.Tier, model)
+	systemPrompt := ""
+	if messages[0].Role == "system" {
+		systemPrompt = messages[0].Content
+	}
+	trace.prompt(systemPrompt, run.Tools)
+	stubs := newToolStubs(run.Steps)
+
+	loopCtx, cancel := context.WithTimeout(ctx, uc.opts.Timeout)
+	defer cancel()
+	answer, fallbackReason, iterations := "", "", 0
+	for i := 1; i <= uc.opts.MaxIterations; i++ {
+		iterations = i
+		callStart := time.Now()
+		result, err := uc.llm.ChatWithTools(loopCtx, messages, run.Tools)
+		trace.llmCall(i, messages, result, err, time.Since(callStart))
+		if err != nil {
+			fallbackReason = "planner_error"
+			if isAgentTimeoutError(err) {
+				fallbackReason = "timeout"
+			}
+			break
+		}
+		if len(result.ToolCalls) == 0 {
+			answer = result.Content
+			if answer == "" {
+				fallbackReason = "empty_response"
+			}
+			break
+		}
+		messages = append(messages, domain.ChatMessage{Role: "assistant", ToolCalls: result.ToolCalls})
+		for _, call := range result.ToolCalls {
+			event := stubs.take(call)
+			trace.toolCall(i, call, event, toolCallMeta{stubbed: true})
+			messages = append(messages, domain.ChatMessage{Role: "tool", Content: event.Output, ToolCallID: call.ID})
+		}
+	}
+	if answer == "" && fallbackReason == "" {
+		fallbackReason = "max_iterations"
+	}
+
+	replay := trace.finish(answer, fallbackReason, iterations)
+	if err := uc.store.SaveAgentRun(context.WithoutCancel(ctx), replay); err != nil {
+		return nil, fmt.Errorf("save agent run replay: %w", err)
+	}
+	slog.Info("agent_run_replayed", "run_id", run.ID, "replay_id", replay.ID, "model", model, "iterations", iterations)
+	return replay, nil
+}
+
+// replayRequest rebuilds the request of the first model call of a run.
+func replayRequest(steps []domain.AgentRunStep) []domain.ChatMessage {
+	for _, step := range steps {
+		if step.Kind == domain.AgentRunStepLLM {
+			return slices.Clone(step.Messages)
+		}
+	}
+	return nil
+}
+
+// toolStubs answers tool calls of a replay from the tool results recorded
+// in the replayed run. A call takes the first unused result of the same
+// tool with equal arguments, else the first unused result of the tool.
+type toolStubs struct {
+	steps []domain.AgentRunStep
+	used  []bool
+}
+
+func newToolStubs(steps []domain.AgentRunStep) *toolStubs {
+	var tools []domain.AgentRunStep
+	for _, step := range steps {
+		if step.Kind == domain.AgentRunStepTool {
+			tools = append(tools, step)
+		}
+	}
+	return &toolStubs{steps: tools, used: make([]bool, len(tools))}
+}
+
+func (s *toolStubs) take(call domain.ToolCall) domain.AgentToolEvent {
+	name := call.Function.Name
+	argsKey := argsToKey(call.Function.Arguments)
+	match := -1
+	for idx, step := range s.steps {
+		if s.used[idx] || step.Tool != name {
+			continue
+		}
+		if argsToKey(step.Arguments) == argsKey {
+			match = idx
+			break
+		}
+		if match < 0 {
+			match = idx
+		}
+	}
+	if match < 0 {
+		payload, _ := json.Marshal(map[string]string{"error": "no recorded result for tool " + name})
+		return domain.AgentToolEvent{Tool: name, Status: "missing", Output: string(payload)}
+	}
+	s.used[match] = true
+	step := s.steps[match]
+	return domain.AgentToolEvent{Tool: name, Status: step.Status, Output: step.Output}
+}
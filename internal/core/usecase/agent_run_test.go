@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/routing"
+)
+
+type agentRunStoreFake struct {
+	mu   sync.Mutex
+	runs map[string]domain.AgentRun
+}
+
+func newAgentRunStoreFake() *agentRunStoreFake {
+	return &agentRunStoreFake{runs: map[string]domain.AgentRun{}}
+}
+
+func (f *agentRunStoreFake) SaveAgentRun(_ context.Context, run *domain.AgentRun) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs[run.ID] = *run
+	return nil
+}
+
+func (f *agentRunStoreFake) GetAgentRun(_ context.Context, id string) (*domain.AgentRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	run, ok := f.runs[id]
+	if !ok {
+		return nil, domain.ErrAgentRunNotFound
+	}
+	return &run, nil
+}
+
+func (f *agentRunStoreFake) ListAgentRuns(_ context.Context, userID, _ string, _ int) ([]domain.AgentRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []domain.AgentRun
+	for _, run := range f.runs {
+		if run.UserID == userID {
+			out = append(out, run)
+		}
+	}
+	return out, nil
+}
+
+func (f *agentRunStoreFake) DeleteAgentRuns(_ context.Context, userID, conversationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, run := range f.runs {
+		if run.UserID == userID && run.ConversationID == conversationID {
+			delete(f.runs, id)
+		}
+	}
+	return nil
+}
+
+func newTracedAgentChat(query *fakeAgentQueryService, runs *agentRunStoreFake) *AgentChatUseCase {
+	uc := NewAgentChatUseCase(
+		query,
+		&fakeAgentEmbedder{},
+		&fakeConversationStore{},
+		&fakeTaskStore{},
+		&fakeMemoryStore{},
+		&fakeMemoryVectorStore{},
+		nil, // webSearcher
+		nil, // obsidianWriter
+		nil, // toolRegistry
+		domain.AgentLimits{},
+		nil, // agentMetrics
+	)
+	uc.SetRunStore(runs)
+	return uc
+}
+
+func TestAgentChat_RecordsRunTrace(t *testing.T) {
+	query := &fakeAgentQueryService{
+		chatToolsResponses: []domain.ChatToolsResult{
+			{ToolCalls: []domain.ToolCall{{ID: "call-1", Function: domain.ToolCallFunc{Name: "knowledge_search", Arguments: map[string]any{"question": "q"}}}}},
+			{Content: "knowledge merged"},
+		},
+	}
+	runs := newAgentRunStoreFake()
+	uc := newTracedAgentChat(query, runs)
+
+	result, err := uc.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:   "u-1",
+		Messages: []domain.AgentInputMessage{{Role: "user", Content: "find info"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.RunID == "" {
+		t.Fatal("expected run id in result")
+	}
+	run, err := runs.GetAgentRun(context.Background(), result.RunID)
+	if err != nil {
+		t.Fatalf("GetAgentRun() error = %v", err)
+	}
+	if run.Input != "find info" || run.Answer != "knowledge merged" || run.SystemPrompt == "" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	kinds := make([]string, 0, len(run.Steps))
+	for _, step := range run.Steps {
+		kinds = append(kinds, step.Kind)
+	}
+	want := []string{domain.AgentRunStepLLM, domain.AgentRunStepTool, domain.AgentRunStepLLM}
+	if len(kinds) != len(want) {
+		t.Fatalf("steps = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("steps = %v, want %v", kinds, want)
+		}
+	}
+	if tool := run.Steps[1]; tool.Tool != "knowledge_search" || tool.Arguments["question"] != "q" || tool.Output == "" {
+		t.Fatalf("unexpected tool step: %+v", tool)
+	}
+	// The second call records only the assistant tool call and the tool result.
+	if got := len(run.Steps[2].Messages); got != 2 {
+		t.Fatalf("second llm call recorded %d messages, want 2", got)
+	}
+}
+
+func TestAgentRunReplay_StubsToolsAndOverridesPrompt(t *testing.T) {
+	runs := newAgentRunStoreFake()
+	source := &domain.AgentRun{
+		ID:     "run-1",
+		UserID: "u-1",
+		Input:  "weather?",
+		Model:  "ollama",
+		Tools:  []domain.ToolSchema{{Type: "function", Function: domain.FunctionSchema{Name: "web_search"}}},
+		Steps: []domain.AgentRunStep{
+			{Seq: 1, Kind: domain.AgentRunStepLLM, Iteration: 1, Messages: []domain.ChatMessage{
+				{Role: "system", Content: "old prompt"},
+				{Role: "user", Content: "weather?"},
+			}},
+			{Seq: 2, Kind: domain.AgentRunStepTool, Iteration: 1, Tool: "web_search", Arguments: map[string]any{"query": "weather"}, Output: "sunny", Status: "ok"},
+		},
+	}
+	if err := runs.SaveAgentRun(context.Background(), source); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		firstRequest []domain.ChatMessage
+		toolOutputs  []string
+		provider     string
+	)
+	call := 0
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(ctx context.Context, msgs []domain.ChatMessage, _ []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			call++
+			provider = routing.ProviderFrom(ctx)
+			if call == 1 {
+				firstRequest = append([]domain.ChatMessage(nil), msgs...)
+				return &domain.ChatToolsResult{ToolCalls: []domain.ToolCall{
+					{ID: "c1", Function: domain.ToolCallFunc{Name: "web_search", Arguments: map[string]any{"query": "weather"}}},
+					{ID: "c2", Function: domain.ToolCallFunc{Name: "create_task", Arguments: map[string]any{"title": "x"}}},
+				}}, nil
+			}
+			for _, m := range msgs {
+				if m.Role == "tool" {
+					toolOutputs = append(toolOutputs, m.Content)
+				}
+			}
+			return &domain.ChatToolsResult{Content: "it is sunny"}, nil
+		},
+	}
+	uc := NewAgentRunUseCase(runs, query, AgentRunOptions{Providers: []string{"ollama", "openrouter"}})
+
+	replay, err := uc.Replay(context.Background(), "u-1", "run-1", domain.AgentReplayOptions{Model: "openrouter", SystemPrompt: "new prompt"})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+	if provider != "openrouter" {
+		t.Fatalf("provider = %q, want openrouter", provider)
+	}
+	if len(firstRequest) != 2 || firstRequest[0].Content != "new prompt" {
+		t.Fatalf("unexpected replay request: %+v", firstRequest)
+	}
+	if len(toolOutputs) != 2 || toolOutputs[0] != "sunny" {
+		t.Fatalf("unexpected stubbed outputs: %v", toolOutputs)
+	}
+	if replay.ReplayOf != "run-1" || replay.Answer != "it is sunny" || replay.Model != "openrouter" {
+		t.Fatalf("unexpected replay: %+v", replay)
+	}
+	var missing bool
+	for _, step := range replay.Steps {
+		if step.Kind != domain.AgentRunStepTool {
+			continue
+		}
+		if !step.Stubbed {
+			t.Fatalf("tool step not marked stubbed: %+v", step)
+		}
+		if step.Tool == "create_task" && step.Status == "missing" {
+			missing = true
+		}
+	}
+	if !missing {
+		t.Fatalf("expected unrecorded tool to be reported missing: %+v", replay.Steps)
+	}
+	if _, err := runs.GetAgentRun(context.Background(), replay.ID); err != nil {
+		t.Fatalf("replay not stored: %v", err)
+	}
+
+	call = 0
+	if _, err := uc.Replay(context.Background(), "u-1", "run-1", domain.AgentReplayOptions{Model: "gpt-nonexistent"}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("Replay() with an unknown model error = %v, want ErrInvalidInput", err)
+	}
+	if call != 0 {
+		t.Fatalf("an unknown model must not reach the LLM, got %d calls", call)
+	}
+}
+
+func TestAgentRunGet_HidesOtherUsersRuns(t *testing.T) {
+	runs := newAgentRunStoreFake()
+	_ = runs.SaveAgentRun(context.Background(), &domain.AgentRun{ID: "run-1", UserID: "u-1"})
+	uc := NewAgentRunUseCase(runs, &fakeAgentQueryService{}, AgentRunOptions{})
+
+	if _, err := uc.Get(context.Background(), "u-2", "run-1"); !errors.Is(err, domain.ErrAgentRunNotFound) {
+		t.Fatalf("Get() error = %v, want ErrAgentRunNotFound", err)
+	}
+	if _, err := uc.Replay(context.Background(), "u-2", "run-1", domain.AgentReplayOptions{}); !errors.Is(err, domain.ErrAgentRunNotFound) {
+		t.Fatalf("Replay() error = %v, want ErrAgentRunNotFound", err)
+	}
+}
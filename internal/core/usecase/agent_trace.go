@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// runTrace records the steps of one agent run. All methods accept a nil
+// trace and then record nothing, so runs are traced only when a run store
+// is configured.
+type runTrace struct {
+	run   domain.AgentRun
+	start time.Time
+	// sent counts the request messages already recorded by earlier calls.
+	sent int
+}
+
+// toolCallMeta is what a trace records about how a tool call was served.
+type toolCallMeta struct {
+	cached  bool
+	stubbed bool
+	elapsed time.Duration
+}
+
+func newRunTrace(userID, conversationID, input string) *runTrace {
+	now := time.Now().UTC()
+	return &runTrace{
+		run: domain.AgentRun{
+			ID:             uuid.NewString(),
+			UserID:         userID,
+			ConversationID: conversationID,
+			Input:          input,
+			CreatedAt:      now,
+		},
+		start: now,
+	}
+}
+
+func (t *runTrace) route(intent, tier, model string) {
+	if t == nil {
+		return
+	}
+	t.run.Intent, t.run.Tier, t.run.Model = intent, tier, model
+}
+
+func (t *runTrace) prompt(systemPrompt string, tools []domain.ToolSchema) {
+	if t == nil {
+		return
+	}
+	t.run.SystemPrompt = systemPrompt
+	t.run.Tools = tools
+}
+
+func (t *runTrace) llmCall(iteration int, messages []domain.ChatMessage, result *domain.ChatToolsResult, err error, elapsed time.Duration) {
+	if t == nil {
+		return
+	}
+	step := domain.AgentRunStep{
+		Kind:       domain.AgentRunStepLLM,
+		Iteration:  iteration,
+		Messages:   slices.Clone(messages[min(t.sent, len(messages)):]),
+		DurationMS: durationMS(elapsed),
+	}
+	t.sent = len(messages)
+	if err != nil {
+		step.Error = err.Error()
+	} else if result != nil {
+		step.Content = result.Content
+		step.ToolCalls = result.ToolCalls
+	}
+	t.add(step)
+}
+
+// toolCall records the output the model was given for call, after any
+// summarizing and error hints.
+func (t *runTrace) toolCall(iteration int, call domain.ToolCall, event domain.AgentToolEvent, meta toolCallMeta) {
+	if t == nil {
+		return
+	}
+	tool := call.Function.Name
+	if tool == "" {
+		tool = event.Tool
+	}
+	t.add(domain.AgentRunStep{
+		Kind:       domain.AgentRunStepTool,
+		Iteration:  iteration,
+		Tool:       tool,
+		ToolCallID: call.ID,
+		Arguments:  call.Function.Arguments,
+		Output:     event.Output,
+		Status:     event.Status,
+		Cached:     meta.cached,
+		Stubbed:    meta.stubbed,
+		DurationMS: durationMS(meta.elapsed),
+	})
+}
+
+// fallback records an answer produced by tool outside the tool loop.
+func (t *runTrace) fallback(tool, answer string, err error, elapsed time.Duration) {
+	if t == nil {
+		return
+	}
+	step := domain.AgentRunStep{
+		Kind:       domain.AgentRunStepFallback,
+		Tool:       tool,
+		Content:    answer,
+		Status:     "ok",
+		DurationMS: durationMS(elapsed),
+	}
+	if err != nil {
+		step.Status = "error"
+		step.Error = err.Error()
+	}
+	t.add(step)
+}
+
+func (t *runTrace) add(step domain.AgentRunStep) {
+	step.Seq = len(t.run.Steps) + 1
+	t.run.Steps = append(t.run.Steps, step)
+}
+
+// finish completes the run; the returned run is nil for a nil trace.
+func (t *runTrace) finish(answer, fallbackReason string, iterations int) *domain.AgentRun {
+	if t == nil {
+		return nil
+	}
+	t.run.Answer = answer
+	t.run.FallbackReason = fallbackReason
+	t.run.Iterations = iterations
+	t.run.DurationMS = durationMS(time.Since(t.start))
+	return &t.run
+}
+
+// saveAgentRun stores run best-effort, since a lost trace must not fail the
+// request, and returns its id once stored.
+func saveAgentRun(ctx context.Context, store ports.AgentRunStore, run *domain.AgentRun) string {
+	if store == nil || run == nil {
+		return ""
+	}
+	if err := store.SaveAgentRun(context.WithoutCancel(ctx), run); err != nil {
+		slog.Warn("agent_run_trace_failed", "run_id", run.ID, "error", err)
+		return ""
+	}
+	return run.ID
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	apiKeyPrefix = "paa_"
+	// apiKeyDisplayLen is how many leading characters of a key are kept in
+	// clear so users can tell their keys apart.
+	apiKeyDisplayLen = 12
+	// apiKeyTouchInterval throttles last_used_at writes for busy keys.
+	apiKeyTouchInterval = time.Minute
+)
+
+// AuthUseCase issues and verifies per-user API keys. Management calls check
+// the principal stored in ctx; calls without a principal are trusted internal
+// calls (e.g. bootstrap).
+type AuthUseCase struct {
+	store ports.UserStore
+	now   func() time.Time
+}
+
+func NewAuthUseCase(store ports.UserStore) *AuthUseCase {
+	return &AuthUseCase{store: store, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// Authenticate resolves a plaintext API key to the principal it belongs to.
+func (uc *AuthUseCase) Authenticate(ctx context.Context, token string) (*domain.Principal, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, domain.WrapError(domain.ErrUnauthorized, "authenticate", errors.New("api key is required"))
+	}
+	key, err := uc.store.GetAPIKeyByHash(ctx, hashAPIKey(token))
+	if err != nil {
+		if domain.IsKind(err, domain.ErrAPIKeyNotFound) {
+			return nil, domain.WrapError(domain.ErrUnauthorized, "authenticate", errors.New("invalid api key"))
+		}
+		return nil, err
+	}
+	user, err := uc.store.GetUser(ctx, key.UserID)
+	if err != nil {
+		if domain.IsKind(err, domain.ErrUserNotFound) {
+			return nil, domain.WrapError(domain.ErrUnauthorized, "authenticate", errors.New("api key owner no longer exists"))
+		}
+		return nil, err
+	}
+
+	now := uc.now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := uc.store.TouchAPIKey(ctx, key.ID, now); err != nil {
+			slog.Warn("api_key_touch_failed", "key_id", key.ID, "error", err)
+		}
+	}
+	return &domain.Principal{UserID: user.ID, KeyID: key.ID, Admin: user.Admin, Groups: user.Groups}, nil
+}
+
+// CreateUser registers a new user. Admin only.
+func (uc *AuthUseCase) CreateUser(ctx context.Context, name string, admin bool, groups []string) (*domain.User, error) {
+	if err := requireAdmin(ctx, "create user"); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "create user", errors.New("name is required"))
+	}
+	groups, err := normalizeGroups(groups)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "create user", err)
+	}
+	user := &domain.User{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Admin:     admin,
+		Groups:    groups,
+		CreatedAt: uc.now(),
+	}
+	if err := uc.store.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetUserGroups replaces the groups of a user, which decide the documents
+// shared with "group:<name>" the user can read. Admin only.
+func (uc *AuthUseCase) SetUserGroups(ctx context.Context, userID string, groups []string) (*domain.User, error) {
+	if err := requireAdmin(ctx, "set user groups"); err != nil {
+		return nil, err
+	}
+	groups, err := normalizeGroups(groups)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "set user groups", err)
+	}
+	if err := uc.store.UpdateUserGroups(ctx, userID, groups); err != nil {
+		return nil, err
+	}
+	return uc.store.GetUser(ctx, userID)
+}
+
+// ListUsers returns all users. Admin only.
+func (uc *AuthUseCase) ListUsers(ctx context.Context) ([]domain.User, error) {
+	if err := requireAdmin(ctx, "list users"); err != nil {
+		return nil, err
+	}
+	return uc.store.ListUsers(ctx)
+}
+
+// GetUser returns a user visible to the caller.
+func (uc *AuthUseCase) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	if err := requireAccess(ctx, "get user", id); err != nil {
+		return nil, err
+	}
+	return uc.store.GetUser(ctx, id)
+}
+
+// PrincipalFor returns the principal userID acts as, with their current
+// groups. It is an internal call and does not check the caller.
+func (uc *AuthUseCase) PrincipalFor(ctx context.Context, userID string) (*domain.Principal, error) {
+	user, err := uc.store.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Principal{UserID: user.ID, Admin: user.Admin, Groups: user.Groups}, nil
+}
+
+// CreateAPIKey issues a key for userID. Users may issue keys for themselves;
+// admins for anyone.
+func (uc *AuthUseCase) CreateAPIKey(ctx context.Context, userID, name string) (*domain.APIKey, string, error) {
+	if err := requireAccess(ctx, "create api key", userID); err != nil {
+		return nil, "", err
+	}
+	if _, err := uc.store.GetUser(ctx, userID); err != nil {
+		return nil, "", err
+	}
+
+	token, err := generateAPIKey()
+	if err != nil {
+		return nil, "", fmt.Errorf("generate api key: %w", err)
+	}
+	key, err := uc.storeAPIKey(ctx, userID, name, token)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, token, nil
+}
+
+// ListAPIKeys returns the keys of userID, including revoked ones.
+func (uc *AuthUseCase) ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	if err := requireAccess(ctx, "list api keys", userID); err != nil {
+		return nil, err
+	}
+	return uc.store.ListAPIKeys(ctx, userID)
+}
+
+// RevokeAPIKey disables a key of userID.
+func (uc *AuthUseCase) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	if err := requireAccess(ctx, "revoke api key", userID); err != nil {
+		return err
+	}
+	return uc.store.RevokeAPIKey(ctx, userID, keyID)
+}
+
+// EnsureBootstrapAdmin makes token a valid admin key, creating an "admin"
+// user for it on first start. It is idempotent across restarts.
+func (uc *AuthUseCase) EnsureBootstrapAdmin(ctx context.Context, token string) (*domain.User, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "bootstrap admin", errors.New("api key is required"))
+	}
+	key, err := uc.store.GetAPIKeyByHash(ctx, hashAPIKey(token))
+	if err == nil {
+		return uc.store.GetUser(ctx, key.UserID)
+	}
+	if !domain.IsKind(err, domain.ErrAPIKeyNotFound) {
+		return nil, err
+	}
+
+	user, err := uc.CreateUser(ctx, "admin", true, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.storeAPIKey(ctx, user.ID, "bootstrap", token); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *AuthUseCase) storeAPIKey(ctx context.Context, userID, name, token string) (*domain.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "default"
+	}
+	key := &domain.APIKey{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      name,
+		Prefix:    token[:min(len(token), apiKeyDisplayLen)],
+		CreatedAt: uc.now(),
+	}
+	if err := uc.store.CreateAPIKey(ctx, key, hashAPIKey(token)); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// requireAdmin rejects non-admin principals. Calls without a principal are
+// internal and allowed.
+func requireAdmin(ctx context.Context, op string) error {
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok || p.Admin {
+		return nil
+	}
+	return domain.WrapError(domain.ErrForbidden, op, errors.New("admin privileges required"))
+}
+
+// requireAccess rejects principals acting on another user's data.
+func requireAccess(ctx context.Context, op, userID string) error {
+	if strings.TrimSpace(userID) == "" {
+		return domain.WrapError(domain.ErrInvalidInput, op, errors.New("user id is required"))
+	}
+	p, ok := domain.PrincipalFromContext(ctx)
+	if !ok || p.CanAccess(userID) {
+		return nil
+	}
+	return domain.WrapError(domain.ErrForbidden, op, fmt.Errorf("user_id=%s", userID))
+}
+
+// withOwnerPrincipal returns ctx acting as userID, so work done for them
+// only sees what they can read. Without a resolver, as when auth is
+// disabled, the principal carries no groups.
+func withOwnerPrincipal(ctx context.Context, principals ports.PrincipalResolver, userID string) (context.Context, error) {
+	if principals == nil {
+		return domain.ContextWithPrincipal(ctx, domain.Principal{UserID: userID}), nil
+	}
+	p, err := principals.PrincipalFor(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve principal: %w", err)
+	}
+	return domain.ContextWithPrincipal(ctx, *p), nil
+}
+
+// normalizeGroups trims and de-duplicates group names. Names are used inside
+// "group:<name>" access tokens, so they may not contain a colon.
+func normalizeGroups(groups []string) ([]string, error) {
+	out := make([]string, 0, len(groups))
+	seen := make(map[string]struct{}, len(groups))
+	for _, g := range groups {
+		g = strings.TrimSpace(g)
+		if g == "" || strings.Contains(g, ":") {
+			return nil, fmt.Errorf("invalid group name %q", g)
+		}
+		if _, dup := seen[g]; dup {
+			continue
+		}
+		seen[g] = struct{}{}
+		out = append(out, g)
+	}
+	return out, nil
+}
+
+func generateAPIKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type userStoreFake struct {
+	users   map[string]domain.User
+	keys    map[string]domain.APIKey // by hash
+	touched []string
+}
+
+func newUserStoreFake() *userStoreFake {
+	return &userStoreFake{users: map[string]domain.User{}, keys: map[string]domain.APIKey{}}
+}
+
+func (f *userStoreFake) CreateUser(_ context.Context, user *domain.User) error {
+	f.users[user.ID] = *user
+	return nil
+}
+
+func (f *userStoreFake) GetUser(_ context.Context, id string) (*domain.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrUserNotFound, "get user", errors.New("id="+id))
+	}
+	return &user, nil
+}
+
+func (f *userStoreFake) ListUsers(context.Context) ([]domain.User, error) {
+	out := make([]domain.User, 0, len(f.users))
+	for _, user := range f.users {
+		out = append(out, user)
+	}
+	return out, nil
+}
+
+func (f *userStoreFake) UpdateUserGroups(_ context.Context, id string, groups []string) error {
+	user, ok := f.users[id]
+	if !ok {
+		return domain.WrapError(domain.ErrUserNotFound, "update user groups", errors.New("id="+id))
+	}
+	user.Groups = groups
+	f.users[id] = user
+	return nil
+}
+
+func (f *userStoreFake) CreateAPIKey(_ context.Context, key *domain.APIKey, keyHash string) error {
+	f.keys[keyHash] = *key
+	return nil
+}
+
+func (f *userStoreFake) GetAPIKeyByHash(_ context.Context, keyHash string) (*domain.APIKey, error) {
+	key, ok := f.keys[keyHash]
+	if !ok || key.RevokedAt != nil {
+		return nil, domain.WrapError(domain.ErrAPIKeyNotFound, "get api key by hash", errors.New("no active key"))
+	}
+	return &key, nil
+}
+
+func (f *userStoreFake) ListAPIKeys(_ context.Context, userID string) ([]domain.APIKey, error) {
+	var out []domain.APIKey
+	for _, key := range f.keys {
+		if key.UserID == userID {
+			out = append(out, key)
+		}
+	}
+	return out, nil
+}
+
+func (f *userStoreFake) RevokeAPIKey(_ context.Context, userID, keyID string) error {
+	for hash, key := range f.keys {
+		if key.ID == keyID && key.UserID == userID && key.RevokedAt == nil {
+			now := time.Now()
+			key.RevokedAt = &now
+			f.keys[hash] = key
+			return nil
+		}
+	}
+	return domain.WrapError(domain.ErrAPIKeyNotFound, "revoke api key", errors.New("id="+keyID))
+}
+
+func (f *userStoreFake) TouchAPIKey(_ context.Context, keyID string, _ time.Time) error {
+	f.touched = append(f.touched, keyID)
+	return nil
+}
+
+func TestAuthCreateAPIKeyAuthenticatesAndRevokes(t *testing.T) {
+	store := newUserStoreFake()
+	uc := NewAuthUseCase(store)
+	ctx := context.Background()
+
+	user, err := uc.CreateUser(ctx, "alice", false, nil)
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	key, token, err := uc.CreateAPIKey(ctx, user.ID, "laptop")
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(token, apiKeyPrefix) || !strings.HasPrefix(token, key.Prefix) {
+		t.Fatalf("unexpected token %q / prefix %q", token, key.Prefix)
+	}
+	if _, stored := store.keys[token]; stored {
+		t.Fatal("plaintext key must not be stored")
+	}
+
+	principal, err := uc.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if principal.UserID != user.ID || principal.KeyID != key.ID || principal.Admin {
+		t.Fatalf("unexpected principal %+v", principal)
+	}
+	if len(store.touched) != 1 {
+		t.Fatalf("expected last_used_at update, got %v", store.touched)
+	}
+
+	if err := uc.RevokeAPIKey(ctx, user.ID, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := uc.Authenticate(ctx, token); !domain.IsKind(err, domain.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized after revoke, got %v", err)
+	}
+}
+
+func TestAuthRejectsUnknownKey(t *testing.T) {
+	uc := NewAuthUseCase(newUserStoreFake())
+	if _, err := uc.Authenticate(context.Background(), "paa_unknown"); !domain.IsKind(err, domain.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAuthEnforcesPrincipalScope(t *testing.T) {
+	store := newUserStoreFake()
+	uc := NewAuthUseCase(store)
+	alice, _ := uc.CreateUser(context.Background(), "alice", false, nil)
+	bob, _ := uc.CreateUser(context.Background(), "bob", false, nil)
+
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: alice.ID})
+	if _, err := uc.CreateUser(ctx, "mallory", true, nil); !domain.IsKind(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for non-admin CreateUser, got %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, bob.ID, "steal"); !domain.IsKind(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for another user's key, got %v", err)
+	}
+	if _, _, err := uc.CreateAPIKey(ctx, alice.ID, "own"); err != nil {
+		t.Fatalf("CreateAPIKey(own) error = %v", err)
+	}
+
+	admin := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "root", Admin: true})
+	if _, err := uc.ListAPIKeys(admin, bob.ID); err != nil {
+		t.Fatalf("admin ListAPIKeys() error = %v", err)
+	}
+}
+
+func TestAuthEnsureBootstrapAdminIsIdempotent(t *testing.T) {
+	store := newUserStoreFake()
+	uc := NewAuthUseCase(store)
+
+	first, err := uc.EnsureBootstrapAdmin(context.Background(), "paa_bootstrap")
+	if err != nil {
+		t.Fatalf("EnsureBootstrapAdmin() error = %v", err)
+	}
+	if !first.Admin {
+		t.Fatal("bootstrap user must be admin")
+	}
+	second, err := uc.EnsureBootstrapAdmin(context.Background(), "paa_bootstrap")
+	if err != nil {
+		t.Fatalf("EnsureBootstrapAdmin() second call error = %v", err)
+	}
+	if second.ID != first.ID || len(store.users) != 1 {
+		t.Fatalf("expected the same admin user, got %s vs %s (%d users)", first.ID, second.ID, len(store.users))
+	}
+
+	principal, err := uc.Authenticate(context.Background(), "paa_bootstrap")
+	if err != nil || !principal.Admin {
+		t.Fatalf("bootstrap key must authenticate as admin, got %+v, %v", principal, err)
+	}
+}
+
+func TestAuthPrincipalCarriesUserGroups(t *testing.T) {
+	uc := NewAuthUseCase(newUserStoreFake())
+	ctx := context.Background()
+
+	if _, err := uc.CreateUser(ctx, "eve", false, []string{"bad:group"}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for a group with a colon, got %v", err)
+	}
+	user, err := uc.CreateUser(ctx, "alice", false, []string{" team ", "team"})
+	if err != nil {
+		t.Fatalf("CreateUser() error = %v", err)
+	}
+	if len(user.Groups) != 1 || user.Groups[0] != "team" {
+		t.Fatalf("expected groups [team], got %v", user.Groups)
+	}
+	if _, err := uc.SetUserGroups(ctx, user.ID, []string{"team", "finance"}); err != nil {
+		t.Fatalf("SetUserGroups() error = %v", err)
+	}
+	_, token, err := uc.CreateAPIKey(ctx, user.ID, "laptop")
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	principal, err := uc.Authenticate(ctx, token)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if len(principal.Groups) != 2 || principal.Groups[1] != "finance" {
+		t.Fatalf("expected principal groups [team finance], got %v", principal.Groups)
+	}
+
+	nonAdmin := domain.ContextWithPrincipal(ctx, *principal)
+	if _, err := uc.SetUserGroups(nonAdmin, user.ID, nil); !domain.IsKind(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for non-admin SetUserGroups, got %v", err)
+	}
+}
@@ -0,0 +1,511 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/archive"
+)
+
+const (
+	// ingestJobFlushEvery is how many files a job processes between progress
+	// writes to the job store.
+	ingestJobFlushEvery = 25
+	// ingestJobHeartbeat is how often a queued or running job reports that
+	// its process is alive.
+	ingestJobHeartbeat = 10 * time.Second
+	// ingestJobStaleAfter is how long a job may miss heartbeats before
+	// RecoverStale fails it.
+	ingestJobStaleAfter = 2 * time.Minute
+)
+
+// errStopWalk ends a walk early without being an error.
+var errStopWalk = errors.New("stop walk")
+
+// BulkIngestOptions tunes bulk ingestion.
+type BulkIngestOptions struct {
+	// ImportRoot confines directory imports; relative paths are resolved
+	// against it. Directory imports are disabled while it is empty.
+	ImportRoot      string
+	MaxArchiveBytes int64
+	MaxFileBytes    int64
+	MaxFiles        int
+	// Concurrency is how many jobs run at once; further jobs stay queued.
+	Concurrency int
+	// TempDir holds uploaded archives while their job runs.
+	TempDir string
+}
+
+// BulkIngestUseCase expands archives and server-side directories into
+// documents. Each call starts a job that runs in the background and records
+// per-file outcomes in the IngestJobStore; files whose content the owner
+// already ingested are skipped by hash.
+type BulkIngestUseCase struct {
+	jobs     ports.IngestJobStore
+	docs     ports.DocumentRepository
+	ingestor ports.DocumentIngestor
+	opts     BulkIngestOptions
+
+	heartbeat time.Duration
+	slots     chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBulkIngestUseCase(
+	jobs ports.IngestJobStore,
+	docs ports.DocumentRepository,
+	ingestor ports.DocumentIngestor,
+	opts BulkIngestOptions,
+) *BulkIngestUseCase {
+	if opts.MaxArchiveBytes <= 0 {
+		opts.MaxArchiveBytes = 1 << 30
+	}
+	if opts.MaxFileBytes <= 0 {
+		opts.MaxFileBytes = 100 << 20
+	}
+	if opts.MaxFiles <= 0 {
+		opts.MaxFiles = 10000
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 2
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BulkIngestUseCase{
+		jobs:     jobs,
+		docs:     docs,
+		ingestor: ingestor,
+		opts:      opts,
+		heartbeat: ingestJobHeartbeat,
+		slots:     make(chan struct{}, opts.Concurrency),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+}
+
+// RecoverStale fails the queued and running jobs whose process stopped
+// heartbeating. Jobs run inside the process that started them, so such a job
+// can never finish; jobs of live processes, this one or another replica, keep
+// beating and are left alone.
+func (uc *BulkIngestUseCase) RecoverStale(ctx context.Context) {
+	now := time.Now().UTC()
+	n, err := uc.jobs.FailStaleIngestJobs(ctx, "interrupted: the process running it stopped", now.Add(-ingestJobStaleAfter), now)
+	if err != nil {
+		slog.Warn("ingest_job_recover_failed", "error", err)
+		return
+	}
+	if n > 0 {
+		slog.Warn("ingest_jobs_interrupted", "count", n)
+	}
+}
+
+// Close cancels running jobs and waits until they have recorded their final
+// state.
+func (uc *BulkIngestUseCase) Close() {
+	uc.cancel()
+	uc.wg.Wait()
+}
+
+// StartArchive spools the archive to a temporary file and starts a job that
+// ingests its files with their archive paths.
+func (uc *BulkIngestUseCase) StartArchive(ctx context.Context, filename string, body io.Reader) (*domain.IngestJob, error) {
+	filename = strings.TrimSpace(filename)
+	if filename == "" {
+		filename = "archive"
+	}
+	tmp, err := os.CreateTemp(uc.opts.TempDir, "ingest-*.archive")
+	if err != nil {
+		return nil, fmt.Errorf("create archive spool file: %w", err)
+	}
+	src := archiveSource{path: tmp.Name()}
+	n, err := io.Copy(tmp, io.LimitReader(body, uc.opts.MaxArchiveBytes+1))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		src.cleanup()
+		return nil, fmt.Errorf("spool archive: %w", err)
+	}
+	if n > uc.opts.MaxArchiveBytes {
+		src.cleanup()
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start archive import",
+			fmt.Errorf("archive exceeds %d bytes", uc.opts.MaxArchiveBytes))
+	}
+	// Reject non-archives now rather than in a job that fails right away.
+	if err := archive.Walk(src.path, func(archive.Entry, io.Reader) error { return errStopWalk }); err != nil && !errors.Is(err, errStopWalk) {
+		src.cleanup()
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start archive import", err)
+	}
+
+	job, err := uc.start(ctx, domain.IngestJobArchive, filename, src)
+	if err != nil {
+		src.cleanup()
+		return nil, err
+	}
+	return job, nil
+}
+
+// StartDirectory starts a job that ingests every file under path with its
+// path relative to that directory.
+func (uc *BulkIngestUseCase) StartDirectory(ctx context.Context, dir string) (*domain.IngestJob, error) {
+	if uc.opts.ImportRoot == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start directory import", errors.New("directory import is not configured"))
+	}
+	resolved, err := uc.resolvePath(dir)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start directory import", err)
+	}
+	if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "start directory import", fmt.Errorf("directory not found: %s", resolved))
+	}
+	return uc.start(ctx, domain.IngestJobDirectory, resolved, directorySource{root: resolved})
+}
+
+// GetJob returns a job of the caller with its files, optionally only those
+// with fileStatus.
+func (uc *BulkIngestUseCase) GetJob(ctx context.Context, id, fileStatus string) (*domain.IngestJob, error) {
+	switch fileStatus {
+	case "", domain.IngestFileIngested, domain.IngestFileSkipped, domain.IngestFileFailed:
+	default:
+		return nil, domain.WrapError(domain.ErrInvalidInput, "get ingest job", fmt.Errorf("unknown file status %q", fileStatus))
+	}
+	job, err := uc.jobs.GetIngestJob(ctx, strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.CanAccess(job.OwnerID) {
+		return nil, domain.WrapError(domain.ErrIngestJobNotFound, "get ingest job", fmt.Errorf("id=%s", id))
+	}
+	files, err := uc.jobs.ListIngestJobFiles(ctx, job.ID, fileStatus, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	job.Files = files
+	return job, nil
+}
+
+// ListJobs returns the caller's jobs newest first; admins see every job.
+func (uc *BulkIngestUseCase) ListJobs(ctx context.Context, limit int) ([]domain.IngestJob, error) {
+	ownerID := ""
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.Admin {
+		ownerID = p.UserID
+	}
+	return uc.jobs.ListIngestJobs(ctx, ownerID, limit)
+}
+
+func (uc *BulkIngestUseCase) start(ctx context.Context, kind domain.IngestJobKind, source string, src ingestSource) (*domain.IngestJob, error) {
+	job := &domain.IngestJob{Kind: kind, Source: source, Status: domain.IngestJobStatusQueued}
+	p, hasPrincipal := domain.PrincipalFromContext(ctx)
+	if hasPrincipal {
+		job.OwnerID = p.UserID
+	}
+	if err := uc.jobs.CreateIngestJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("record ingest job: %w", err)
+	}
+	snapshot := *job
+
+	// The job outlives the request: it runs under the use case's context,
+	// carrying the caller so documents get the right owner.
+	jobCtx := uc.ctx
+	if hasPrincipal {
+		jobCtx = domain.ContextWithPrincipal(jobCtx, p)
+	}
+	uc.wg.Add(1)
+	go func() {
+		defer uc.wg.Done()
+		defer src.cleanup()
+		defer uc.keepAlive(job.ID)()
+		uc.run(jobCtx, job, src)
+	}()
+	return &snapshot, nil
+}
+
+// keepAlive heartbeats a job until the returned stop function is called.
+func (uc *BulkIngestUseCase) keepAlive(jobID string) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(uc.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := uc.jobs.HeartbeatIngestJob(uc.ctx, jobID, time.Now().UTC()); err != nil {
+					slog.Warn("ingest_job_heartbeat_failed", "job_id", jobID, "error", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (uc *BulkIngestUseCase) run(ctx context.Context, job *domain.IngestJob, src ingestSource) {
+	select {
+	case uc.slots <- struct{}{}:
+		defer func() { <-uc.slots }()
+	case <-ctx.Done():
+		uc.finish(job, nil, ctx.Err())
+		return
+	}
+
+	now := time.Now().UTC()
+	job.Status = domain.IngestJobStatusRunning
+	job.StartedAt = &now
+
+	total := 0
+	err := src.walk(func(rel string, _ int64, _ io.Reader) error {
+		if !skipImportPath(rel) {
+			total++
+		}
+		return nil
+	})
+	if err != nil {
+		uc.finish(job, nil, fmt.Errorf("list files: %w", err))
+		return
+	}
+	if total > uc.opts.MaxFiles {
+		uc.finish(job, nil, fmt.Errorf("%d files exceed the limit of %d", total, uc.opts.MaxFiles))
+		return
+	}
+	job.Total = total
+	uc.update(ctx, job, nil)
+
+	var (
+		pending []domain.IngestJobFile
+		seen    = make(map[string]string) // content hash -> document ID
+	)
+	err = src.walk(func(rel string, size int64, r io.Reader) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if skipImportPath(rel) {
+			return nil
+		}
+		file := uc.ingestFile(ctx, job.OwnerID, rel, size, r, seen)
+		switch file.Status {
+		case domain.IngestFileIngested:
+			job.Ingested++
+		case domain.IngestFileSkipped:
+			job.Skipped++
+		default:
+			job.Failed++
+		}
+		pending = append(pending, file)
+		if len(pending) >= ingestJobFlushEvery {
+			uc.update(ctx, job, pending)
+			pending = nil
+		}
+		return nil
+	})
+	uc.finish(job, pending, err)
+}
+
+// ingestFile ingests one file unless the owner already has a document with
+// the same content.
+func (uc *BulkIngestUseCase) ingestFile(ctx context.Context, ownerID, rel string, size int64, r io.Reader, seen map[string]string) domain.IngestJobFile {
+	file := domain.IngestJobFile{Path: rel, Size: size}
+	fail := func(err error) domain.IngestJobFile {
+		file.Status = domain.IngestFileFailed
+		file.Error = err.Error()
+		return file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, uc.opts.MaxFileBytes+1))
+	if err != nil {
+		return fail(fmt.Errorf("read file: %w", err))
+	}
+	if int64(len(data)) > uc.opts.MaxFileBytes {
+		return fail(fmt.Errorf("file exceeds %d bytes", uc.opts.MaxFileBytes))
+	}
+	file.Size = int64(len(data))
+	sum := sha256.Sum256(data)
+	file.Hash = hex.EncodeToString(sum[:])
+
+	if docID, ok := seen[file.Hash]; ok {
+		file.Status, file.DocumentID = domain.IngestFileSkipped, docID
+		return file
+	}
+	existing, err := uc.docs.GetByContentHash(ctx, file.Hash, ownerID)
+	switch {
+	case err == nil:
+		seen[file.Hash] = existing.ID
+		file.Status, file.DocumentID = domain.IngestFileSkipped, existing.ID
+		return file
+	case !domain.IsKind(err, domain.ErrDocumentNotFound):
+		return fail(fmt.Errorf("lookup content hash: %w", err))
+	}
+
+	doc, err := uc.ingestor.IngestFromSource(ctx, domain.SourceRequest{
+		SourceType: "upload",
+		Filename:   path.Base(rel),
+		Path:       rel,
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return fail(err)
+	}
+	seen[file.Hash] = doc.ID
+	file.Status, file.DocumentID = domain.IngestFileIngested, doc.ID
+	return file
+}
+
+// update stores job progress and pending file outcomes. Failures are only
+// logged: the job keeps going and the final update retries the counts.
+func (uc *BulkIngestUseCase) update(ctx context.Context, job *domain.IngestJob, files []domain.IngestJobFile) {
+	if len(files) > 0 {
+		if err := uc.jobs.AddIngestJobFiles(ctx, job.ID, files); err != nil {
+			slog.Warn("ingest_job_files_record_failed", "job_id", job.ID, "files", len(files), "error", err)
+		}
+	}
+	if err := uc.jobs.UpdateIngestJob(ctx, job); err != nil {
+		slog.Warn("ingest_job_update_failed", "job_id", job.ID, "error", err)
+	}
+}
+
+// finish records the final state of a job. It uses a fresh context so a job
+// cancelled by shutdown still leaves a finished record behind.
+func (uc *BulkIngestUseCase) finish(job *domain.IngestJob, pending []domain.IngestJobFile, err error) {
+	now := time.Now().UTC()
+	job.FinishedAt = &now
+	switch {
+	case errors.Is(err, context.Canceled):
+		job.Status = domain.IngestJobStatusCanceled
+		job.Error = "interrupted by shutdown"
+	case err != nil:
+		job.Status = domain.IngestJobStatusFailed
+		job.Error = err.Error()
+	case job.Failed > 0:
+		job.Status = domain.IngestJobStatusPartial
+	default:
+		job.Status = domain.IngestJobStatusDone
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	uc.update(ctx, job, pending)
+	slog.Info("ingest_job_done",
+		"job_id", job.ID,
+		"kind", job.Kind,
+		"status", job.Status,
+		"total", job.Total,
+		"ingested", job.Ingested,
+		"skipped", job.Skipped,
+		"failed", job.Failed,
+	)
+}
+
+func (uc *BulkIngestUseCase) resolvePath(dir string) (string, error) {
+	dir = strings.TrimSpace(dir)
+	if dir == "" {
+		return "", errors.New("path is required")
+	}
+	root := filepath.Clean(uc.opts.ImportRoot)
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	dir = filepath.Clean(dir)
+	if dir != root && !strings.HasPrefix(dir, root+string(os.PathSeparator)) {
+		return "", fmt.Errorf("path must be under %s", root)
+	}
+	return dir, nil
+}
+
+// skipImportPath reports whether a file is hidden, or lies in a hidden
+// directory or in metadata that archivers add (__MACOSX, Thumbs.db).
+func skipImportPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" || part == "Thumbs.db" || part == "desktop.ini" {
+			return true
+		}
+	}
+	return false
+}
+
+// ingestSource lists the files of a job. walk may be called more than once;
+// rel is slash-separated and relative to the source root.
+type ingestSource interface {
+	walk(fn func(rel string, size int64, r io.Reader) error) error
+	cleanup()
+}
+
+type archiveSource struct {
+	path string
+}
+
+func (s archiveSource) walk(fn func(string, int64, io.Reader) error) error {
+	return archive.Walk(s.path, func(e archive.Entry, r io.Reader) error {
+		return fn(e.Name, e.Size, r)
+	})
+}
+
+func (s archiveSource) cleanup() {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("ingest_archive_cleanup_failed", "path", s.path, "error", err)
+	}
+}
+
+type directorySource struct {
+	root string
+}
+
+// walk skips hidden directories and anything that is not a regular file,
+// so symlinks cannot lead outside the root.
+func (s directorySource) walk(fn func(string, int64, io.Reader) error) error {
+	return filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if p != s.root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			// An unreadable file fails on its own instead of ending the walk.
+			return fn(filepath.ToSlash(rel), info.Size(), errorReader{err})
+		}
+		defer func() { _ = f.Close() }()
+		return fn(filepath.ToSlash(rel), info.Size(), f)
+	})
+}
+
+func (directorySource) cleanup() {}
+
+type errorReader struct{ err error }
+
+func (r errorReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type ingestJobStoreFake struct {
+	mu    sync.Mutex
+	jobs  map[string]domain.IngestJob
+	files map[string][]domain.IngestJobFile
+}
+
+func newIngestJobStoreFake() *ingestJobStoreFake {
+	return &ingestJobStoreFake{jobs: map[string]domain.IngestJob{}, files: map[string][]domain.IngestJobFile{}}
+}
+
+func (f *ingestJobStoreFake) CreateIngestJob(_ context.Context, job *domain.IngestJob) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job.ID = fmt.Sprintf("job-%d", len(f.jobs)+1)
+	now := time.Now().UTC()
+	job.HeartbeatAt = &now
+	f.jobs[job.ID] = *job
+	return nil
+}
+
+func (f *ingestJobStoreFake) UpdateIngestJob(_ context.Context, job *domain.IngestJob) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := *job
+	stored.HeartbeatAt = f.jobs[job.ID].HeartbeatAt
+	f.jobs[job.ID] = stored
+	return nil
+}
+
+func (f *ingestJobStoreFake) HeartbeatIngestJob(_ context.Context, id string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job := f.jobs[id]
+	job.HeartbeatAt = &at
+	f.jobs[id] = job
+	return nil
+}
+
+func (f *ingestJobStoreFake) AddIngestJobFiles(_ context.Context, jobID string, files []domain.IngestJobFile) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.files[jobID] = append(f.files[jobID], files...)
+	return nil
+}
+
+func (f *ingestJobStoreFake) GetIngestJob(_ context.Context, id string) (*domain.IngestJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	job, ok := f.jobs[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrIngestJobNotFound, "get ingest job", errors.New(id))
+	}
+	return &job, nil
+}
+
+func (f *ingestJobStoreFake) ListIngestJobs(_ context.Context, ownerID string, _ int) ([]domain.IngestJob, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []domain.IngestJob
+	for _, job := range f.jobs {
+		if ownerID == "" || job.OwnerID == ownerID {
+			out = append(out, job)
+		}
+	}
+	return out, nil
+}
+
+func (f *ingestJobStoreFake) ListIngestJobFiles(_ context.Context, jobID, status string, _, _ int) ([]domain.IngestJobFile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []domain.IngestJobFile
+	for _, file := range f.files[jobID] {
+		if status == "" || file.Status == status {
+			out = append(out, file)
+		}
+	}
+	return out, nil
+}
+
+func (f *ingestJobStoreFake) FailStaleIngestJobs(_ context.Context, message string, staleBefore, finishedAt time.Time) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for id, job := range f.jobs {
+		beat := job.CreatedAt
+		if job.HeartbeatAt != nil {
+			beat = *job.HeartbeatAt
+		}
+		if !job.Finished() && beat.Before(staleBefore) {
+			job.Status, job.Error, job.FinishedAt = domain.IngestJobStatusFailed, message, &finishedAt
+			f.jobs[id] = job
+			n++
+		}
+	}
+	return n, nil
+}
+
+// hashRepoFake knows documents by content hash.
+type hashRepoFake struct {
+	ingestRepoFake
+	byHash map[string]string
+}
+
+func (f *hashRepoFake) GetByContentHash(_ context.Context, hash, _ string) (*domain.Document, error) {
+	if id, ok := f.byHash[hash]; ok {
+		return &domain.Document{ID: id}, nil
+	}
+	return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by content hash", errors.New(hash))
+}
+
+type bulkIngestorFake struct {
+	mu       sync.Mutex
+	requests []domain.SourceRequest
+	owners   []string
+}
+
+func (f *bulkIngestorFake) Upload(context.Context, string, string, io.Reader) (*domain.Document, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *bulkIngestorFake) IngestFromSource(ctx context.Context, req domain.SourceRequest) (*domain.Document, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	p, _ := domain.PrincipalFromContext(ctx)
+	f.owners = append(f.owners, p.UserID)
+	return &domain.Document{ID: fmt.Sprintf("doc-%d", len(f.requests))}, nil
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func zipArchive(t *testing.T, files map[string]string, order []string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(files[name]))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestBulkIngestArchive(t *testing.T) {
+	files := map[string]string{
+		"wiki/Team/setup.md":  "# Setup",
+		"wiki/copy.md":        "# Setup",
+		"wiki/known.md":       "already here",
+		"wiki/big.txt":        strings.Repeat("x", 64),
+		"wiki/.obsidian/a":    "hidden",
+		"__MACOSX/wiki/._a":   "junk",
+		"wiki/Team/notes.txt": "notes",
+	}
+	order := []string{"wiki/Team/setup.md", "wiki/copy.md", "wiki/known.md", "wiki/big.txt", "wiki/.obsidian/a", "__MACOSX/wiki/._a", "wiki/Team/notes.txt"}
+
+	store := newIngestJobStoreFake()
+	ingestor := &bulkIngestorFake{}
+	repo := &hashRepoFake{byHash: map[string]string{sha256Hex("already here"): "doc-old"}}
+	uc := NewBulkIngestUseCase(store, repo, ingestor, BulkIngestOptions{MaxFileBytes: 32, TempDir: t.TempDir()})
+
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "u-1"})
+	job, err := uc.StartArchive(ctx, "wiki.zip", bytes.NewReader(zipArchive(t, files, order)))
+	if err != nil {
+		t.Fatalf("StartArchive() error = %v", err)
+	}
+	if job.Status != domain.IngestJobStatusQueued || job.OwnerID != "u-1" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	uc.wg.Wait()
+
+	got, err := uc.GetJob(ctx, job.ID, "")
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != domain.IngestJobStatusPartial || got.Total != 5 || got.Ingested != 2 || got.Skipped != 2 || got.Failed != 1 {
+		t.Fatalf("unexpected job counts: %+v", got)
+	}
+	wantStatus := map[string]string{
+		"wiki/Team/setup.md":  domain.IngestFileIngested,
+		"wiki/copy.md":        domain.IngestFileSkipped,
+		"wiki/known.md":       domain.IngestFileSkipped,
+		"wiki/big.txt":        domain.IngestFileFailed,
+		"wiki/Team/notes.txt": domain.IngestFileIngested,
+	}
+	if len(got.Files) != len(wantStatus) {
+		t.Fatalf("unexpected files: %+v", got.Files)
+	}
+	for _, f := range got.Files {
+		if f.Status != wantStatus[f.Path] {
+			t.Errorf("%s: status %q, want %q", f.Path, f.Status, wantStatus[f.Path])
+		}
+	}
+	if got.Files[1].DocumentID != "doc-1" || got.Files[2].DocumentID != "doc-old" {
+		t.Errorf("skipped files must point at the existing document: %+v", got.Files)
+	}
+	if ingestor.requests[0].Path != "wiki/Team/setup.md" || ingestor.requests[0].Filename != "setup.md" {
+		t.Errorf("archive path not preserved: %+v", ingestor.requests[0])
+	}
+	if ingestor.owners[0] != "u-1" {
+		t.Errorf("documents must be ingested as the caller, got %q", ingestor.owners[0])
+	}
+
+	if _, err := uc.GetJob(domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "u-2"}), job.ID, ""); !domain.IsKind(err, domain.ErrIngestJobNotFound) {
+		t.Fatalf("another user's job must not be visible, got %v", err)
+	}
+	entries, _ := os.ReadDir(uc.opts.TempDir)
+	if len(entries) != 0 {
+		t.Fatalf("spooled archive must be removed, found %d files", len(entries))
+	}
+}
+
+func TestBulkIngestArchiveRejectsNonArchive(t *testing.T) {
+	uc := NewBulkIngestUseCase(newIngestJobStoreFake(), &hashRepoFake{}, &bulkIngestorFake{}, BulkIngestOptions{TempDir: t.TempDir()})
+	_, err := uc.StartArchive(context.Background(), "notes.txt", strings.NewReader("plain text"))
+	if !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestBulkIngestDirectory(t *testing.T) {
+	root := t.TempDir()
+	mustWrite := func(rel, content string) {
+		p := filepath.Join(root, "export", rel)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite("a.md", "A")
+	mustWrite("sub/b.md", "B")
+	mustWrite(".git/config", "x")
+
+	store := newIngestJobStoreFake()
+	ingestor := &bulkIngestorFake{}
+	uc := NewBulkIngestUseCase(store, &hashRepoFake{}, ingestor, BulkIngestOptions{ImportRoot: root})
+
+	if _, err := uc.StartDirectory(context.Background(), "../etc"); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected a path outside the root to be rejected, got %v", err)
+	}
+	job, err := uc.StartDirectory(context.Background(), "export")
+	if err != nil {
+		t.Fatalf("StartDirectory() error = %v", err)
+	}
+	uc.wg.Wait()
+
+	got, _ := store.GetIngestJob(context.Background(), job.ID)
+	if got.Status != domain.IngestJobStatusDone || got.Total != 2 || got.Ingested != 2 {
+		t.Fatalf("unexpected job: %+v", got)
+	}
+	if ingestor.requests[1].Path != "sub/b.md" {
+		t.Fatalf("unexpected relative path %q", ingestor.requests[1].Path)
+	}
+}
+
+func TestBulkIngestDirectoryDisabledWithoutRoot(t *testing.T) {
+	uc := NewBulkIngestUseCase(newIngestJobStoreFake(), &hashRepoFake{}, &bulkIngestorFake{}, BulkIngestOptions{})
+	if _, err := uc.StartDirectory(context.Background(), "/tmp"); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestBulkIngestRecoverStaleFailsOnlyJobsWithoutHeartbeat(t *testing.T) {
+	now := time.Now().UTC()
+	old, fresh := now.Add(-time.Hour), now.Add(-time.Second)
+	jobs := newIngestJobStoreFake()
+	jobs.jobs["job-1"] = domain.IngestJob{ID: "job-1", Status: domain.IngestJobStatusRunning, CreatedAt: old, HeartbeatAt: &old}
+	jobs.jobs["job-2"] = domain.IngestJob{ID: "job-2", Status: domain.IngestJobStatusQueued, CreatedAt: old}
+	jobs.jobs["job-3"] = domain.IngestJob{ID: "job-3", Status: domain.IngestJobStatusRunning, CreatedAt: old, HeartbeatAt: &fresh}
+	jobs.jobs["job-4"] = domain.IngestJob{ID: "job-4", Status: domain.IngestJobStatusDone, CreatedAt: old, HeartbeatAt: &old}
+	uc := NewBulkIngestUseCase(jobs, &hashRepoFake{}, &bulkIngestorFake{}, BulkIngestOptions{})
+	defer uc.Close()
+
+	uc.RecoverStale(context.Background())
+	for _, id := range []string{"job-1", "job-2"} {
+		if job := jobs.jobs[id]; job.Status != domain.IngestJobStatusFailed || job.Error == "" || job.FinishedAt == nil {
+			t.Fatalf("%s must be failed as interrupted, got %+v", id, job)
+		}
+	}
+	if jobs.jobs["job-3"].Status != domain.IngestJobStatusRunning {
+		t.Fatalf("a job another process still heartbeats must be left alone, got %+v", jobs.jobs["job-3"])
+	}
+	if jobs.jobs["job-4"].Status != domain.IngestJobStatusDone {
+		t.Fatalf("finished jobs must be left alone, got %+v", jobs.jobs["job-4"])
+	}
+}
@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// citationMinOverlap is the share of a sentence's content words that must
+// appear in a cited chunk for the citation to count as support.
+const citationMinOverlap = 0.25
+
+var citationMarkerRe = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+type textSpan struct {
+	start int
+	end   int
+	text  string
+}
+
+// attributeCitations splits a generated answer into sentences, resolves the
+// inline [n] markers of each sentence against chunks (1-based) and returns the
+// sentences with a support flag plus one citation per resolved marker.
+func attributeCitations(answer string, chunks []domain.RetrievedChunk) ([]domain.AnswerSentence, []domain.Citation) {
+	spans := splitSentences(answer)
+	sentences := make([]domain.AnswerSentence, 0, len(spans))
+	citations := make([]domain.Citation, 0)
+	chunkSpans := make(map[int][]textSpan)
+
+	for idx, span := range spans {
+		sentence := domain.AnswerSentence{Text: span.text, Start: span.start, End: span.end}
+		words := contentWords(citationMarkerRe.ReplaceAllString(span.text, " "))
+
+		for _, marker := range parseCitationMarkers(span.text) {
+			if marker < 1 || marker > len(chunks) {
+				continue
+			}
+			chunk := chunks[marker-1]
+			if _, ok := chunkSpans[marker]; !ok {
+				chunkSpans[marker] = splitSentences(chunk.Text)
+			}
+			best, overlap := bestSupportingSpan(words, chunkSpans[marker], chunk.Text)
+
+			sentence.Markers = append(sentence.Markers, marker)
+			if overlap >= citationMinOverlap {
+				sentence.Supported = true
+			}
+			citations = append(citations, domain.Citation{
+				Marker:     marker,
+				Sentence:   idx,
+				DocumentID: chunk.DocumentID,
+				ChunkIndex: chunk.ChunkIndex,
+				Filename:   chunk.Filename,
+				Title:      chunk.Title,
+				Path:       chunk.Path,
+				Locator:    chunk.Locator,
+				SpanStart:  best.start,
+				SpanEnd:    best.end,
+			})
+		}
+		sentences = append(sentences, sentence)
+	}
+	return sentences, citations
+}
+
+// parseCitationMarkers returns the distinct marker numbers in text in order of
+// appearance. Both "[1][2]" and "[1, 2]" forms are accepted.
+func parseCitationMarkers(text string) []int {
+	var out []int
+	seen := make(map[int]bool)
+	for _, match := range citationMarkerRe.FindAllStringSubmatch(text, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || seen[n] {
+				continue
+			}
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// bestSupportingSpan picks the chunk sentence sharing the most content words
+// with the answer sentence. It returns the whole chunk when nothing overlaps.
+func bestSupportingSpan(words map[string]struct{}, spans []textSpan, chunkText string) (textSpan, float64) {
+	whole := textSpan{start: 0, end: len([]rune(chunkText)), text: chunkText}
+	if len(words) == 0 {
+		// Nothing to compare (e.g. a bare "[1]" line); trust the marker.
+		return whole, 1
+	}
+
+	best, bestHits := whole, 0
+	for _, span := range spans {
+		hits := 0
+		for w := range contentWords(span.text) {
+			if _, ok := words[w]; ok {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = span, hits
+		}
+	}
+
+	// Overlap is measured against the whole chunk so that a claim stitched
+	// from several chunk sentences still counts as supported.
+	chunkWords := contentWords(chunkText)
+	total := 0
+	for w := range words {
+		if _, ok := chunkWords[w]; ok {
+			total++
+		}
+	}
+	return best, float64(total) / float64(len(words))
+}
+
+// splitSentences splits text on sentence terminators and newlines. Markers
+// that follow a terminator (". [1]") stay with the preceding sentence.
+// Offsets are in runes.
+func splitSentences(text string) []textSpan {
+	runes := []rune(text)
+	n := len(runes)
+	var out []textSpan
+
+	emit := func(start, end int) {
+		for start < end && unicode.IsSpace(runes[start]) {
+			start++
+		}
+		for end > start && unicode.IsSpace(runes[end-1]) {
+			end--
+		}
+		if start < end {
+			out = append(out, textSpan{start: start, end: end, text: string(runes[start:end])})
+		}
+	}
+
+	start := 0
+	for i := 0; i < n; i++ {
+		r := runes[i]
+		if r == '\n' {
+			emit(start, i)
+			start = i + 1
+			continue
+		}
+		if !isSentenceTerminator(r) {
+			continue
+		}
+		j := i + 1
+		for j < n && isSentenceTerminator(runes[j]) {
+			j++
+		}
+		for {
+			k := j
+			for k < n && runes[k] == ' ' {
+				k++
+			}
+			end := markerEnd(runes, k)
+			if end < 0 {
+				break
+			}
+			j = end
+		}
+		if j == n || unicode.IsSpace(runes[j]) {
+			emit(start, j)
+			start = j
+			i = j - 1
+		}
+	}
+	emit(start, n)
+	return out
+}
+
+// markerEnd returns the index after a "[digits]" marker starting at i, or -1.
+func markerEnd(runes []rune, i int) int {
+	if i >= len(runes) || runes[i] != '[' {
+		return -1
+	}
+	j := i + 1
+	for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == ',' || runes[j] == ' ') {
+		j++
+	}
+	if j == i+1 || j >= len(runes) || runes[j] != ']' {
+		return -1
+	}
+	return j + 1
+}
+
+func isSentenceTerminator(r rune) bool {
+	return r == '.' || r == '!' || r == '?' || r == '…'
+}
+
+// contentWords returns the lower-cased words of text that are at least three
+// runes long, which drops most stop words in both Russian and English.
+func contentWords(text string) map[string]struct{} {
+	words := make(map[string]struct{})
+	for _, field := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(field)) >= 3 {
+			words[field] = struct{}{}
+		}
+	}
+	return words
+}
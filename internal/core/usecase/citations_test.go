@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestSplitSentences(t *testing.T) {
+	text := "Docker uses containers [1]. Версия 3.5 вышла в 2024 году. [2]\nSecond line! Tail"
+	spans := splitSentences(text)
+
+	want := []string{
+		"Docker uses containers [1].",
+		"Версия 3.5 вышла в 2024 году. [2]",
+		"Second line!",
+		"Tail",
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d sentences, got %d: %+v", len(want), len(spans), spans)
+	}
+	runes := []rune(text)
+	for i, span := range spans {
+		if span.text != want[i] {
+			t.Errorf("sentence %d = %q, want %q", i, span.text, want[i])
+		}
+		if string(runes[span.start:span.end]) != span.text {
+			t.Errorf("sentence %d offsets [%d:%d] do not match text", i, span.start, span.end)
+		}
+	}
+}
+
+func TestParseCitationMarkers(t *testing.T) {
+	got := parseCitationMarkers("a [2][1] b [1, 3] c [x]")
+	if want := []int{2, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseCitationMarkers() = %v, want %v", got, want)
+	}
+}
+
+func TestAttributeCitations(t *testing.T) {
+	chunks := []domain.RetrievedChunk{
+		{
+			DocumentID: "doc-docker",
+			ChunkIndex: 2,
+			Filename:   "docker.md",
+			Title:      "Docker",
+			Path:       "notes/docker.md",
+			Text:       "Intro line. Docker runs applications inside isolated containers. Other text.",
+		},
+		{DocumentID: "doc-go", ChunkIndex: 0, Filename: "go.md", Text: "Go has goroutines."},
+	}
+	answer := "Docker runs applications in containers [1]. Goroutines are cheap [1]. Made up [7]. No marker here."
+
+	sentences, citations := attributeCitations(answer, chunks)
+	if len(sentences) != 4 {
+		t.Fatalf("expected 4 sentences, got %+v", sentences)
+	}
+
+	if !sentences[0].Supported || !reflect.DeepEqual(sentences[0].Markers, []int{1}) {
+		t.Fatalf("sentence 0 should be supported by [1], got %+v", sentences[0])
+	}
+	if sentences[1].Supported {
+		t.Fatalf("sentence 1 cites a chunk that does not back it, got %+v", sentences[1])
+	}
+	if sentences[2].Supported || len(sentences[2].Markers) != 0 {
+		t.Fatalf("out-of-range marker must be dropped, got %+v", sentences[2])
+	}
+	if sentences[3].Supported {
+		t.Fatalf("sentence without markers must be unsupported, got %+v", sentences[3])
+	}
+
+	if len(citations) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", citations)
+	}
+	first := citations[0]
+	if first.Sentence != 0 || first.DocumentID != "doc-docker" || first.ChunkIndex != 2 || first.Path != "notes/docker.md" || first.Title != "Docker" {
+		t.Fatalf("unexpected citation: %+v", first)
+	}
+	span := string([]rune(chunks[0].Text)[first.SpanStart:first.SpanEnd])
+	if span != "Docker runs applications inside isolated containers." {
+		t.Fatalf("unexpected supporting span %q", span)
+	}
+}
+
+func TestQueryUseCaseAnswerWithCitations(t *testing.T) {
+	generator := &queryGeneratorFake{cited: "Fact from the first chunk [1]."}
+	uc := NewQueryUseCase(&queryEmbedderFake{}, &queryVectorFake{}, generator, QueryOptions{})
+
+	answer, err := uc.AnswerWithCitations(context.Background(), "question", 3, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("AnswerWithCitations() error = %v", err)
+	}
+	if answer.Text != generator.cited {
+		t.Fatalf("unexpected text %q", answer.Text)
+	}
+	if len(answer.Sentences) != 1 || len(answer.Sentences[0].Markers) != 1 {
+		t.Fatalf("expected one cited sentence, got %+v", answer.Sentences)
+	}
+	if len(answer.Citations) != 1 || answer.Citations[0].DocumentID != answer.Sources[0].DocumentID {
+		t.Fatalf("expected citation to the first source, got %+v", answer.Citations)
+	}
+}
@@ -0,0 +1,252 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	conversationTitleMaxLen    = 200
+	conversationSearchMaxLimit = 100
+)
+
+// ConversationUseCase serves a user's chat history. Every call is scoped to
+// userID, so another user's conversation behaves as if it did not exist.
+type ConversationUseCase struct {
+	store          ports.ConversationStore
+	memories       ports.MemoryStore
+	memoryVector   ports.MemoryVectorStore
+	runs           ports.AgentRunStore
+	orchestrations ports.OrchestrationStore
+	jobs           ports.AgentJobStore
+}
+
+func NewConversationUseCase(
+	store ports.ConversationStore,
+	memories ports.MemoryStore,
+	memoryVector ports.MemoryVectorStore,
+) *ConversationUseCase {
+	return &ConversationUseCase{
+		store:        store,
+		memories:     memories,
+		memoryVector: memoryVector,
+	}
+}
+
+// SetRunStore makes Delete also remove the agent run traces of a
+// conversation.
+func (uc *ConversationUseCase) SetRunStore(runs ports.AgentRunStore) {
+	uc.runs = runs
+}
+
+// SetOrchestrations makes Delete also remove the orchestrations started
+// from a conversation.
+func (uc *ConversationUseCase) SetOrchestrations(store ports.OrchestrationStore) {
+	uc.orchestrations = store
+}
+
+// SetAgentJobs makes Delete also remove the background agent jobs of a
+// conversation.
+func (uc *ConversationUseCase) SetAgentJobs(jobs ports.AgentJobStore) {
+	uc.jobs = jobs
+}
+
+func (uc *ConversationUseCase) List(ctx context.Context, userID string, limit, offset int) ([]domain.Conversation, error) {
+	if err := requireUserID(userID, "list conversations"); err != nil {
+		return nil, err
+	}
+	return uc.store.ListConversations(ctx, userID, limit, offset)
+}
+
+func (uc *ConversationUseCase) Get(ctx context.Context, userID, conversationID string) (*domain.ConversationTranscript, error) {
+	if err := requireConversationRef(userID, conversationID, "get conversation"); err != nil {
+		return nil, err
+	}
+	conv, err := uc.store.GetConversation(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := uc.store.ListMessages(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ConversationTranscript{Conversation: *conv, Messages: messages}, nil
+}
+
+func (uc *ConversationUseCase) Search(ctx context.Context, userID, query string, limit int) ([]domain.ConversationMessage, error) {
+	if err := requireUserID(userID, "search conversations"); err != nil {
+		return nil, err
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "search conversations", errors.New("query is required"))
+	}
+	if limit <= 0 || limit > conversationSearchMaxLimit {
+		limit = conversationSearchMaxLimit
+	}
+	return uc.store.SearchMessages(ctx, userID, query, limit)
+}
+
+func (uc *ConversationUseCase) Rename(ctx context.Context, userID, conversationID, title string) (*domain.Conversation, error) {
+	if err := requireConversationRef(userID, conversationID, "rename conversation"); err != nil {
+		return nil, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "rename conversation", errors.New("title is required"))
+	}
+	if len([]rune(title)) > conversationTitleMaxLen {
+		title = string([]rune(title)[:conversationTitleMaxLen])
+	}
+	if err := uc.store.RenameConversation(ctx, userID, conversationID, title); err != nil {
+		return nil, err
+	}
+	return uc.store.GetConversation(ctx, userID, conversationID)
+}
+
+// Delete forgets a conversation together with the conversations its
+// orchestration steps ran in, their memories, agent runs and jobs and
+// orchestrations. The conversation itself goes last, so a partial failure
+// leaves it listed and the delete can be retried.
+func (uc *ConversationUseCase) Delete(ctx context.Context, userID, conversationID string) error {
+	if err := requireConversationRef(userID, conversationID, "delete conversation"); err != nil {
+		return err
+	}
+	if _, err := uc.store.GetConversation(ctx, userID, conversationID); err != nil {
+		return err
+	}
+
+	steps, err := uc.store.ListConversationIDsByPrefix(ctx, userID, domain.StepConversationPrefix(conversationID))
+	if err != nil {
+		return fmt.Errorf("list step conversations: %w", err)
+	}
+	for _, stepConversationID := range steps {
+		if err := uc.forget(ctx, userID, stepConversationID); err != nil {
+			return err
+		}
+		if err := uc.store.DeleteConversation(ctx, userID, stepConversationID); err != nil && !domain.IsKind(err, domain.ErrConversationNotFound) {
+			return err
+		}
+	}
+	if err := uc.forget(ctx, userID, conversationID); err != nil {
+		return err
+	}
+	if uc.orchestrations != nil {
+		if err := uc.orchestrations.DeleteByConversation(ctx, userID, conversationID); err != nil {
+			return fmt.Errorf("delete orchestrations: %w", err)
+		}
+	}
+	if err := uc.store.DeleteConversation(ctx, userID, conversationID); err != nil {
+		return err
+	}
+
+	slog.Info("conversation_deleted", "user_id", userID, "conversation_id", conversationID, "step_conversations", len(steps))
+	return nil
+}
+
+// forget removes what is derived from a conversation: memory vectors and
+// summaries, agent runs and agent jobs.
+func (uc *ConversationUseCase) forget(ctx context.Context, userID, conversationID string) error {
+	if uc.memoryVector != nil {
+		if err := uc.memoryVector.DeleteSummaries(ctx, userID, conversationID); err != nil {
+			return fmt.Errorf("delete memory vectors: %w", err)
+		}
+	}
+	if uc.memories != nil {
+		if err := uc.memories.DeleteSummaries(ctx, userID, conversationID); err != nil {
+			return fmt.Errorf("delete memory summaries: %w", err)
+		}
+	}
+	if uc.runs != nil {
+		if err := uc.runs.DeleteAgentRuns(ctx, userID, conversationID); err != nil {
+			return fmt.Errorf("delete agent runs: %w", err)
+		}
+	}
+	if uc.jobs != nil {
+		if err := uc.jobs.DeleteByConversation(ctx, userID, conversationID); err != nil {
+			return fmt.Errorf("delete agent jobs: %w", err)
+		}
+	}
+	return nil
+}
+
+// Export renders the full transcript as Markdown or JSON.
+func (uc *ConversationUseCase) Export(
+	ctx context.Context,
+	userID, conversationID string,
+	format domain.ConversationExportFormat,
+) ([]byte, error) {
+	if format == "" {
+		format = domain.ConversationExportMarkdown
+	}
+	if format != domain.ConversationExportMarkdown && format != domain.ConversationExportJSON {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "export conversation", fmt.Errorf("unknown format %q", format))
+	}
+	transcript, err := uc.Get(ctx, userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if format == domain.ConversationExportJSON {
+		out, err := json.MarshalIndent(transcript, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal conversation export: %w", err)
+		}
+		return out, nil
+	}
+	return []byte(renderConversationMarkdown(transcript)), nil
+}
+
+func renderConversationMarkdown(t *domain.ConversationTranscript) string {
+	var b strings.Builder
+	title := t.Title
+	if title == "" {
+		title = t.ConversationID
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "- Conversation: `%s`\n", t.ConversationID)
+	fmt.Fprintf(&b, "- Created: %s\n", t.CreatedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "- Updated: %s\n", t.UpdatedAt.UTC().Format(time.RFC3339))
+
+	for _, msg := range t.Messages {
+		switch msg.Role {
+		case "user":
+			fmt.Fprintf(&b, "\n## User (turn %d)\n\n", msg.UserTurn)
+		case "assistant":
+			b.WriteString("\n## Assistant\n\n")
+		case "tool":
+			fmt.Fprintf(&b, "\n### Tool `%s`\n\n", msg.ToolName)
+			fmt.Fprintf(&b, "```\n%s\n```\n", strings.TrimRight(msg.Content, "\n"))
+			continue
+		default:
+			fmt.Fprintf(&b, "\n## %s\n\n", msg.Role)
+		}
+		b.WriteString(strings.TrimRight(msg.Content, "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func requireUserID(userID, op string) error {
+	if strings.TrimSpace(userID) == "" {
+		return domain.WrapError(domain.ErrInvalidInput, op, errors.New("user id is required"))
+	}
+	return nil
+}
+
+func requireConversationRef(userID, conversationID, op string) error {
+	if err := requireUserID(userID, op); err != nil {
+		return err
+	}
+	if strings.TrimSpace(conversationID) == "" {
+		return domain.WrapError(domain.ErrInvalidInput, op, errors.New("conversation id is required"))
+	}
+	return nil
+}
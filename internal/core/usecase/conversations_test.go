@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func newConversationFixture() (*fakeConversationStore, *fakeMemoryStore, *fakeMemoryVectorStore) {
+	store := &fakeConversationStore{
+		conversation: domain.Conversation{UserID: "alice", ConversationID: "c1", Title: "Budget"},
+		messages: []domain.ConversationMessage{
+			{UserID: "alice", ConversationID: "c1", Role: "user", Content: "How much did we spend?", UserTurn: 1},
+			{UserID: "alice", ConversationID: "c1", Role: "tool", ToolName: "search_documents", Content: "{\"hits\":1}"},
+			{UserID: "alice", ConversationID: "c1", Role: "assistant", Content: "About 1200 EUR."},
+		},
+	}
+	memories := &fakeMemoryStore{summaries: []domain.MemorySummary{
+		{UserID: "alice", ConversationID: "c1", Summary: "spending"},
+		{UserID: "alice", ConversationID: "c2", Summary: "other"},
+	}}
+	return store, memories, &fakeMemoryVectorStore{}
+}
+
+func TestConversationDeleteForgetsMemories(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	uc := NewConversationUseCase(store, memories, vectors)
+
+	if err := uc.Delete(context.Background(), "alice", "c1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if len(vectors.deleted) != 1 || vectors.deleted[0] != "c1" {
+		t.Fatalf("expected memory vectors of c1 deleted, got %v", vectors.deleted)
+	}
+	if len(memories.summaries) != 1 || memories.summaries[0].ConversationID != "c2" {
+		t.Fatalf("expected only c2 summary to remain, got %+v", memories.summaries)
+	}
+	if store.conversation.ConversationID != "" || len(store.messages) != 0 {
+		t.Fatalf("expected conversation deleted, got %+v", store.conversation)
+	}
+}
+
+func TestConversationDeleteRemovesRunsAndOrchestrations(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	step := domain.StepConversationID("c1", "o1", "research")
+	store.messages = append(store.messages, domain.ConversationMessage{UserID: "alice", ConversationID: step, Role: "user", Content: "research"})
+	memories.summaries = append(memories.summaries, domain.MemorySummary{UserID: "alice", ConversationID: step})
+	runs := newAgentRunStoreFake()
+	for _, run := range []domain.AgentRun{
+		{ID: "r1", UserID: "alice", ConversationID: "c1"},
+		{ID: "r2", UserID: "alice", ConversationID: step},
+		{ID: "r3", UserID: "alice", ConversationID: "c2"},
+	} {
+		_ = runs.SaveAgentRun(context.Background(), &run)
+	}
+	orchs := &orchStoreFake{orchs: map[string]domain.Orchestration{
+		"o1": {ID: "o1", UserID: "alice", ConversationID: "c1"},
+		"o2": {ID: "o2", UserID: "alice", ConversationID: "c2"},
+	}}
+	uc := NewConversationUseCase(store, memories, vectors)
+	uc.SetRunStore(runs)
+	uc.SetOrchestrations(orchs)
+
+	if err := uc.Delete(context.Background(), "alice", "c1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := runs.runs["r3"]; len(runs.runs) != 1 || !ok {
+		t.Fatalf("expected only the run of c2 to remain, got %+v", runs.runs)
+	}
+	if _, ok := orchs.orchs["o2"]; len(orchs.orchs) != 1 || !ok {
+		t.Fatalf("expected only the orchestration of c2 to remain, got %+v", orchs.orchs)
+	}
+	if len(store.messages) != 0 || len(memories.summaries) != 1 || !slices.Contains(vectors.deleted, step) {
+		t.Fatalf("step conversation must be forgotten: messages=%+v summaries=%+v vectors=%v", store.messages, memories.summaries, vectors.deleted)
+	}
+}
+
+func TestConversationDeleteRemovesAgentJobsAndEvents(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	step := domain.StepConversationID("c1", "o1", "research")
+	store.messages = append(store.messages, domain.ConversationMessage{UserID: "alice", ConversationID: step, Role: "user", Content: "research"})
+	jobs := newAgentJobStoreFake()
+	for _, job := range []domain.AgentJob{
+		{ID: "j1", UserID: "alice", ConversationID: "c1"},
+		{ID: "j2", UserID: "alice", ConversationID: step},
+		{ID: "j3", UserID: "alice", ConversationID: "c2"},
+	} {
+		_ = jobs.CreateAgentJob(context.Background(), &job)
+		_ = jobs.AddAgentJobEvent(context.Background(), job.ID, domain.AgentJobEvent{Type: "status"})
+	}
+	uc := NewConversationUseCase(store, memories, vectors)
+	uc.SetAgentJobs(jobs)
+
+	if err := uc.Delete(context.Background(), "alice", "c1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok := jobs.jobs["j3"]; len(jobs.jobs) != 1 || !ok {
+		t.Fatalf("expected only the job of c2 to remain, got %+v", jobs.jobs)
+	}
+	if _, ok := jobs.events["j3"]; len(jobs.events) != 1 || !ok {
+		t.Fatalf("expected only the events of c2's job to remain, got %+v", jobs.events)
+	}
+}
+
+func TestConversationDeleteKeepsMessagesWhenVectorDeleteFails(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	vectors.deleteErr = errors.New("qdrant down")
+	uc := NewConversationUseCase(store, memories, vectors)
+
+	if err := uc.Delete(context.Background(), "alice", "c1"); err == nil {
+		t.Fatal("expected error when memory vectors cannot be deleted")
+	}
+	if store.conversation.ConversationID != "c1" || len(memories.summaries) != 2 {
+		t.Fatal("conversation and summaries must stay so the delete can be retried")
+	}
+}
+
+func TestConversationOfAnotherUserIsNotFound(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	uc := NewConversationUseCase(store, memories, vectors)
+
+	if _, err := uc.Get(context.Background(), "bob", "c1"); !domain.IsKind(err, domain.ErrConversationNotFound) {
+		t.Fatalf("Get() error = %v, want conversation not found", err)
+	}
+	if err := uc.Delete(context.Background(), "bob", "c1"); !domain.IsKind(err, domain.ErrConversationNotFound) {
+		t.Fatalf("Delete() error = %v, want conversation not found", err)
+	}
+	if len(vectors.deleted) != 0 || len(memories.summaries) != 2 {
+		t.Fatal("memories must be untouched when the conversation is not the caller's")
+	}
+}
+
+func TestConversationRenameValidatesTitle(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	uc := NewConversationUseCase(store, memories, vectors)
+
+	if _, err := uc.Rename(context.Background(), "alice", "c1", "   "); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("Rename() error = %v, want invalid input", err)
+	}
+	conv, err := uc.Rename(context.Background(), "alice", "c1", "  "+strings.Repeat("я", 250)+"  ")
+	if err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	if got := len([]rune(conv.Title)); got != conversationTitleMaxLen {
+		t.Fatalf("expected title truncated to %d runes, got %d", conversationTitleMaxLen, got)
+	}
+}
+
+func TestConversationSearchRequiresQuery(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	uc := NewConversationUseCase(store, memories, vectors)
+
+	if _, err := uc.Search(context.Background(), "alice", " ", 10); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("Search() error = %v, want invalid input", err)
+	}
+	hits, err := uc.Search(context.Background(), "alice", "SPEND", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(hits) != 1 || hits[0].Role != "user" {
+		t.Fatalf("unexpected search hits: %+v", hits)
+	}
+}
+
+func TestConversationExport(t *testing.T) {
+	store, memories, vectors := newConversationFixture()
+	uc := NewConversationUseCase(store, memories, vectors)
+	ctx := context.Background()
+
+	md, err := uc.Export(ctx, "alice", "c1", domain.ConversationExportMarkdown)
+	if err != nil {
+		t.Fatalf("Export(markdown) error = %v", err)
+	}
+	for _, want := range []string{"# Budget", "## User (turn 1)", "How much did we spend?", "### Tool `search_documents`", "## Assistant", "About 1200 EUR."} {
+		if !strings.Contains(string(md), want) {
+			t.Fatalf("markdown export missing %q:\n%s", want, md)
+		}
+	}
+
+	raw, err := uc.Export(ctx, "alice", "c1", domain.ConversationExportJSON)
+	if err != nil {
+		t.Fatalf("Export(json) error = %v", err)
+	}
+	var transcript domain.ConversationTranscript
+	if err := json.Unmarshal(raw, &transcript); err != nil {
+		t.Fatalf("unmarshal json export: %v", err)
+	}
+	if transcript.ConversationID != "c1" || len(transcript.Messages) != 3 {
+		t.Fatalf("unexpected json export: %+v", transcript)
+	}
+
+	if _, err := uc.Export(ctx, "alice", "c1", "pdf"); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("Export(pdf) error = %v, want invalid input", err)
+	}
+}
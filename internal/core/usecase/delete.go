@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+type DeleteDocumentUseCase struct {
+	repo       ports.DocumentRepository
+	storage    ports.ObjectStorage
+	vectorDB   ports.VectorStore
+	graphStore ports.GraphStore
+}
+
+func NewDeleteDocumentUseCase(
+	repo ports.DocumentRepository,
+	storage ports.ObjectStorage,
+	vectorDB ports.VectorStore,
+	graphStore ports.GraphStore,
+) *DeleteDocumentUseCase {
+	return &DeleteDocumentUseCase{
+		repo:       repo,
+		storage:    storage,
+		vectorDB:   vectorDB,
+		graphStore: graphStore,
+	}
+}
+
+// Delete purges a document and its attachments from vectors, graph, object
+// storage and finally the repository. The row goes last so a partial failure
+// can be retried by id.
+func (uc *DeleteDocumentUseCase) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return domain.WrapError(domain.ErrInvalidInput, "delete document", errors.New("document id is required"))
+	}
+
+	doc, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.CanAccess(doc.OwnerID) {
+		if p.CanRead(*doc) {
+			return domain.WrapError(domain.ErrForbidden, "delete document", fmt.Errorf("document %s is shared", doc.ID))
+		}
+		return domain.WrapError(domain.ErrDocumentNotFound, "delete document", fmt.Errorf("id=%s", doc.ID))
+	}
+
+	// Attachments ingested from the document go with it.
+	children, err := uc.repo.ListByFilter(ctx, domain.DocumentFilter{ParentIDs: []string{doc.ID}}, 0)
+	if err != nil {
+		return fmt.Errorf("list attached documents: %w", err)
+	}
+	for _, child := range children {
+		if err := uc.Delete(ctx, child.ID); err != nil {
+			return fmt.Errorf("delete attached document %s: %w", child.ID, err)
+		}
+	}
+
+	if err := uc.vectorDB.DeleteByDocumentID(ctx, doc.ID); err != nil {
+		return fmt.Errorf("delete document vectors: %w", err)
+	}
+
+	// Graph cleanup is best-effort, matching how the graph is populated.
+	if uc.graphStore != nil {
+		if err := uc.graphStore.DeleteDocument(ctx, doc.ID); err != nil {
+			slog.Warn("graph_delete_failed", "document_id", doc.ID, "error", err)
+		}
+	}
+
+	if doc.StoragePath != "" {
+		if err := uc.storage.Delete(ctx, doc.StoragePath); err != nil {
+			return fmt.Errorf("delete document blob: %w", err)
+		}
+	}
+
+	if err := uc.repo.Delete(ctx, doc.ID); err != nil {
+		return fmt.Errorf("delete document row: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFilter deletes every document matching filter. An empty filter is
+// rejected to avoid wiping the whole corpus by accident. Non-admin callers
+// only ever match their own documents.
+func (uc *DeleteDocumentUseCase) DeleteByFilter(ctx context.Context, filter domain.DocumentFilter) (*domain.DocumentDeleteResult, error) {
+	if filter.IsEmpty() {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "delete documents by filter", errors.New("at least one filter criterion is required"))
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.Admin {
+		filter.OwnerIDs = []string{p.UserID}
+	}
+
+	docs, err := uc.repo.ListByFilter(ctx, filter, 0)
+	if err != nil {
+		return nil, fmt.Errorf("list documents by filter: %w", err)
+	}
+
+	result := &domain.DocumentDeleteResult{Deleted: []string{}}
+	for _, doc := range docs {
+		if err := uc.Delete(ctx, doc.ID); err != nil {
+			if result.Failed == nil {
+				result.Failed = make(map[string]string)
+			}
+			result.Failed[doc.ID] = err.Error()
+			slog.Warn("document_delete_failed", "document_id", doc.ID, "error", err)
+			continue
+		}
+		result.Deleted = append(result.Deleted, doc.ID)
+	}
+	return result, nil
+}
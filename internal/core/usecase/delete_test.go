@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type deleteRepoFake struct {
+	docs     map[string]*domain.Document
+	listed   []domain.Document
+	deleted  []string
+	filterIn domain.DocumentFilter
+}
+
+func (f *deleteRepoFake) Create(context.Context, *domain.Document) error { return nil }
+func (f *deleteRepoFake) GetByID(_ context.Context, id string) (*domain.Document, error) {
+	doc, ok := f.docs[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by id", errors.New("id="+id))
+	}
+	copyDoc := *doc
+	return &copyDoc, nil
+}
+func (f *deleteRepoFake) GetBySourceID(context.Context, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *deleteRepoFake) GetByContentHash(context.Context, string, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *deleteRepoFake) UpdateSource(context.Context, *domain.Document) error { return nil }
+func (f *deleteRepoFake) UpdateStatus(context.Context, string, domain.DocumentStatus, string) error {
+	return nil
+}
+func (f *deleteRepoFake) SaveClassification(context.Context, string, domain.Classification) error {
+	return nil
+}
+func (f *deleteRepoFake) UpdateACL(_ context.Context, id string, visibility domain.DocumentVisibility, sharedWith []string) error {
+	doc, ok := f.docs[id]
+	if !ok {
+		return domain.WrapError(domain.ErrDocumentNotFound, "update document acl", errors.New("id="+id))
+	}
+	doc.Visibility = visibility
+	doc.SharedWith = sharedWith
+	return nil
+}
+func (f *deleteRepoFake) ListRecent(context.Context, int) ([]domain.Document, error) {
+	return nil, nil
+}
+func (f *deleteRepoFake) ListByFilter(_ context.Context, filter domain.DocumentFilter, _ int) ([]domain.Document, error) {
+	if len(filter.ParentIDs) > 0 {
+		var children []domain.Document
+		for _, doc := range f.docs {
+			if slices.Contains(filter.ParentIDs, doc.ParentID) {
+				children = append(children, *doc)
+			}
+		}
+		return children, nil
+	}
+	f.filterIn = filter
+	return f.listed, nil
+}
+func (f *deleteRepoFake) Delete(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	delete(f.docs, id)
+	return nil
+}
+
+type deleteStorageFake struct {
+	deletedKeys []string
+}
+
+func (f *deleteStorageFake) Save(context.Context, string, io.Reader) error { return nil }
+func (f *deleteStorageFake) Open(context.Context, string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+func (f *deleteStorageFake) Delete(_ context.Context, key string) error {
+	f.deletedKeys = append(f.deletedKeys, key)
+	return nil
+}
+
+type deleteVectorFake struct {
+	vectorFake
+	deletedDocIDs []string
+	deleteErrFor  map[string]error
+}
+
+func (f *deleteVectorFake) DeleteByDocumentID(_ context.Context, docID string) error {
+	if err := f.deleteErrFor[docID]; err != nil {
+		return err
+	}
+	f.deletedDocIDs = append(f.deletedDocIDs, docID)
+	return nil
+}
+
+type deleteGraphFake struct {
+	graphStoreFake
+	deleted []string
+	err     error
+}
+
+func (f *deleteGraphFake) DeleteDocument(_ context.Context, docID string) error {
+	f.deleted = append(f.deleted, docID)
+	return f.err
+}
+
+func TestDeletePurgesAllStores(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"doc-1": {ID: "doc-1", StoragePath: "doc-1_notes.md"},
+	}}
+	storage := &deleteStorageFake{}
+	vector := &deleteVectorFake{}
+	graph := &deleteGraphFake{err: errors.New("neo4j down")}
+
+	uc := NewDeleteDocumentUseCase(repo, storage, vector, graph)
+	if err := uc.Delete(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if len(vector.deletedDocIDs) != 1 || vector.deletedDocIDs[0] != "doc-1" {
+		t.Fatalf("expected vectors deleted for doc-1, got %v", vector.deletedDocIDs)
+	}
+	if len(graph.deleted) != 1 {
+		t.Fatalf("expected graph delete attempt, got %v", graph.deleted)
+	}
+	if len(storage.deletedKeys) != 1 || storage.deletedKeys[0] != "doc-1_notes.md" {
+		t.Fatalf("expected blob deleted, got %v", storage.deletedKeys)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != "doc-1" {
+		t.Fatalf("expected row deleted, got %v", repo.deleted)
+	}
+}
+
+func TestDeleteKeepsRowWhenVectorDeleteFails(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"doc-1": {ID: "doc-1", StoragePath: "doc-1_a.txt"},
+	}}
+	vector := &deleteVectorFake{deleteErrFor: map[string]error{"doc-1": errors.New("qdrant down")}}
+
+	uc := NewDeleteDocumentUseCase(repo, &deleteStorageFake{}, vector, nil)
+	if err := uc.Delete(context.Background(), "doc-1"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(repo.deleted) != 0 {
+		t.Fatalf("row must survive a failed vector delete, got %v", repo.deleted)
+	}
+}
+
+func TestDeleteReturnsNotFound(t *testing.T) {
+	uc := NewDeleteDocumentUseCase(&deleteRepoFake{docs: map[string]*domain.Document{}}, &deleteStorageFake{}, &deleteVectorFake{}, nil)
+	err := uc.Delete(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+}
+
+func TestDeleteByFilterRejectsEmptyFilter(t *testing.T) {
+	uc := NewDeleteDocumentUseCase(&deleteRepoFake{}, &deleteStorageFake{}, &deleteVectorFake{}, nil)
+	_, err := uc.DeleteByFilter(context.Background(), domain.DocumentFilter{})
+	if !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
+func TestDeleteByFilterReportsPartialFailures(t *testing.T) {
+	repo := &deleteRepoFake{
+		docs: map[string]*domain.Document{
+			"a": {ID: "a"},
+			"b": {ID: "b"},
+		},
+		listed: []domain.Document{{ID: "a"}, {ID: "b"}},
+	}
+	vector := &deleteVectorFake{deleteErrFor: map[string]error{"b": errors.New("boom")}}
+
+	uc := NewDeleteDocumentUseCase(repo, &deleteStorageFake{}, vector, nil)
+	filter := domain.DocumentFilter{SourceTypes: []string{"web"}}
+	result, err := uc.DeleteByFilter(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("DeleteByFilter() error = %v", err)
+	}
+	if len(repo.filterIn.SourceTypes) != 1 {
+		t.Fatalf("filter not forwarded: %+v", repo.filterIn)
+	}
+	if len(result.Deleted) != 1 || result.Deleted[0] != "a" {
+		t.Fatalf("expected [a] deleted, got %v", result.Deleted)
+	}
+	if _, ok := result.Failed["b"]; !ok {
+		t.Fatalf("expected b in failed, got %v", result.Failed)
+	}
+}
+
+func TestDeleteHidesOtherUsersDocuments(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"mine":   {ID: "mine", OwnerID: "alice"},
+		"theirs": {ID: "theirs", OwnerID: "bob"},
+		"shared": {ID: "shared"},
+	}}
+	uc := NewDeleteDocumentUseCase(repo, &deleteStorageFake{}, &deleteVectorFake{}, nil)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	if err := uc.Delete(ctx, "theirs"); !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound for another user's document, got %v", err)
+	}
+	if err := uc.Delete(ctx, "shared"); !domain.IsKind(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for a shared document, got %v", err)
+	}
+	if err := uc.Delete(ctx, "mine"); err != nil {
+		t.Fatalf("Delete(mine) error = %v", err)
+	}
+	if len(repo.deleted) != 1 || repo.deleted[0] != "mine" {
+		t.Fatalf("expected only mine deleted, got %v", repo.deleted)
+	}
+}
+
+func TestDeleteByFilterScopesNonAdminToOwnDocuments(t *testing.T) {
+	repo := &deleteRepoFake{}
+	uc := NewDeleteDocumentUseCase(repo, &deleteStorageFake{}, &deleteVectorFake{}, nil)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	if _, err := uc.DeleteByFilter(ctx, domain.DocumentFilter{SourceTypes: []string{"upload"}}); err != nil {
+		t.Fatalf("DeleteByFilter() error = %v", err)
+	}
+	if len(repo.filterIn.OwnerIDs) != 1 || repo.filterIn.OwnerIDs[0] != "alice" {
+		t.Fatalf("expected owner scope [alice], got %v", repo.filterIn.OwnerIDs)
+	}
+
+	admin := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "root", Admin: true})
+	if _, err := uc.DeleteByFilter(admin, domain.DocumentFilter{SourceTypes: []string{"upload"}}); err != nil {
+		t.Fatalf("DeleteByFilter() error = %v", err)
+	}
+	if repo.filterIn.OwnerIDs != nil {
+		t.Fatalf("admin filter must not be scoped, got %v", repo.filterIn.OwnerIDs)
+	}
+}
+
+func TestDeleteRemovesAttachedDocuments(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"mail-1": {ID: "mail-1", OwnerID: "alice"},
+		"att-1":  {ID: "att-1", OwnerID: "alice", ParentID: "mail-1"},
+		"att-2":  {ID: "att-2", OwnerID: "alice", ParentID: "att-1"},
+	}}
+	vector := &deleteVectorFake{}
+	uc := NewDeleteDocumentUseCase(repo, &deleteStorageFake{}, vector, nil)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	if err := uc.Delete(ctx, "mail-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	want := []string{"att-2", "att-1", "mail-1"}
+	if !slices.Equal(repo.deleted, want) {
+		t.Fatalf("deleted rows = %v, want %v", repo.deleted, want)
+	}
+	if !slices.Equal(vector.deletedDocIDs, want) {
+		t.Fatalf("deleted vectors = %v, want %v", vector.deletedDocIDs, want)
+	}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// DocumentACLUseCase changes who may read a document. Postgres is the source
+// of truth; the vector payload is rewritten so retrieval filters agree with it.
+type DocumentACLUseCase struct {
+	repo     ports.DocumentRepository
+	vectorDB ports.VectorStore
+}
+
+func NewDocumentACLUseCase(repo ports.DocumentRepository, vectorDB ports.VectorStore) *DocumentACLUseCase {
+	return &DocumentACLUseCase{repo: repo, vectorDB: vectorDB}
+}
+
+// SetACL replaces the visibility and share list of a document. Only its owner
+// or an admin may change it, so ownerless documents are admin-only.
+func (uc *DocumentACLUseCase) SetACL(
+	ctx context.Context,
+	id string,
+	visibility domain.DocumentVisibility,
+	sharedWith []string,
+) (*domain.Document, error) {
+	if id == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "set document acl", errors.New("document id is required"))
+	}
+	if !visibility.Valid() {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "set document acl", fmt.Errorf("unknown visibility %q", visibility))
+	}
+	shared, err := domain.NormalizeShareTargets(sharedWith)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "set document acl", err)
+	}
+	if visibility != domain.VisibilityShared && len(shared) > 0 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "set document acl", errors.New("shared_with requires visibility \"shared\""))
+	}
+
+	doc, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p, ok := domain.PrincipalFromContext(ctx); ok && !p.CanAccess(doc.OwnerID) {
+		if p.CanRead(*doc) {
+			return nil, domain.WrapError(domain.ErrForbidden, "set document acl", fmt.Errorf("document %s is not owned by caller", doc.ID))
+		}
+		return nil, domain.WrapError(domain.ErrDocumentNotFound, "set document acl", fmt.Errorf("id=%s", doc.ID))
+	}
+
+	if err := uc.apply(ctx, doc, visibility, shared); err != nil {
+		return nil, err
+	}
+
+	slog.Info("document_acl_updated", "document_id", doc.ID, "visibility", visibility, "shared_with", len(shared))
+	return doc, nil
+}
+
+// apply stores the ACL of doc and of the attachments ingested from it, which
+// always share their parent's access rules.
+func (uc *DocumentACLUseCase) apply(ctx context.Context, doc *domain.Document, visibility domain.DocumentVisibility, shared []string) error {
+	if err := uc.repo.UpdateACL(ctx, doc.ID, visibility, shared); err != nil {
+		return err
+	}
+	doc.Visibility = visibility
+	doc.SharedWith = shared
+
+	// A stale payload would keep exposing the document, so this is not
+	// best-effort like enrichment: the caller gets the error and can retry.
+	payload := map[string]any{
+		"owner_id":   doc.OwnerID,
+		"visibility": string(visibility),
+		"acl":        doc.AccessTokens(),
+	}
+	if err := uc.vectorDB.UpdateChunksPayload(ctx, doc.ID, doc.SourceType, payload); err != nil {
+		return fmt.Errorf("mirror document acl to vector store: %w", err)
+	}
+
+	children, err := uc.repo.ListByFilter(ctx, domain.DocumentFilter{ParentIDs: []string{doc.ID}}, 0)
+	if err != nil {
+		return fmt.Errorf("list attached documents: %w", err)
+	}
+	for i := range children {
+		if err := uc.apply(ctx, &children[i], visibility, shared); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type aclVectorFake struct {
+	vectorFake
+	payloads map[string]map[string]any
+	err      error
+}
+
+func (f *aclVectorFake) UpdateChunksPayload(_ context.Context, docID string, _ string, payload map[string]any) error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.payloads == nil {
+		f.payloads = make(map[string]map[string]any)
+	}
+	f.payloads[docID] = payload
+	return nil
+}
+
+func TestSetACLUpdatesRepositoryAndVectorPayload(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"doc-1": {ID: "doc-1", OwnerID: "alice", Visibility: domain.VisibilityPrivate},
+	}}
+	vector := &aclVectorFake{}
+	uc := NewDocumentACLUseCase(repo, vector)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	doc, err := uc.SetACL(ctx, "doc-1", domain.VisibilityShared, []string{" group:team ", "user:bob", "group:team"})
+	if err != nil {
+		t.Fatalf("SetACL() error = %v", err)
+	}
+	if len(doc.SharedWith) != 2 || repo.docs["doc-1"].Visibility != domain.VisibilityShared {
+		t.Fatalf("unexpected stored ACL: %+v", repo.docs["doc-1"])
+	}
+	acl, _ := vector.payloads["doc-1"]["acl"].([]string)
+	if len(acl) != 3 || acl[0] != "user:alice" || acl[1] != "group:team" || acl[2] != "user:bob" {
+		t.Fatalf("unexpected acl payload %v", vector.payloads["doc-1"])
+	}
+
+	bob := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "bob"})
+	if !(domain.Principal{UserID: "bob"}).CanRead(*repo.docs["doc-1"]) {
+		t.Fatal("bob must be able to read a document shared with him")
+	}
+	if _, err := uc.SetACL(bob, "doc-1", domain.VisibilityPublic, nil); !domain.IsKind(err, domain.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for a non-owner reader, got %v", err)
+	}
+	carol := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "carol"})
+	if _, err := uc.SetACL(carol, "doc-1", domain.VisibilityPublic, nil); !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound for a user without access, got %v", err)
+	}
+}
+
+func TestSetACLKeepsOwnerlessPrivateDocumentAdminOnly(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"doc-1": {ID: "doc-1"},
+	}}
+	vector := &aclVectorFake{}
+	uc := NewDocumentACLUseCase(repo, vector)
+	admin := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "root", Admin: true})
+
+	for _, visibility := range []domain.DocumentVisibility{domain.VisibilityPrivate, domain.VisibilityShared} {
+		doc, err := uc.SetACL(admin, "doc-1", visibility, nil)
+		if err != nil {
+			t.Fatalf("SetACL(%s) error = %v", visibility, err)
+		}
+		acl, _ := vector.payloads["doc-1"]["acl"].([]string)
+		if len(acl) != 1 || acl[0] != domain.AccessTokenAdmin {
+			t.Fatalf("%s: expected admin-only acl payload, got %v", visibility, acl)
+		}
+		if (domain.Principal{UserID: "alice", Groups: []string{"team"}}).CanRead(*doc) {
+			t.Fatalf("%s: a regular user must not read an ownerless %s document", visibility, visibility)
+		}
+	}
+}
+
+func TestSetACLRejectsInvalidInput(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{"doc-1": {ID: "doc-1"}}}
+	uc := NewDocumentACLUseCase(repo, &aclVectorFake{})
+
+	cases := []struct {
+		visibility domain.DocumentVisibility
+		sharedWith []string
+	}{
+		{visibility: "secret"},
+		{visibility: domain.VisibilityShared, sharedWith: []string{"bob"}},
+		{visibility: domain.VisibilityPrivate, sharedWith: []string{"user:bob"}},
+	}
+	for _, tc := range cases {
+		if _, err := uc.SetACL(context.Background(), "doc-1", tc.visibility, tc.sharedWith); !domain.IsKind(err, domain.ErrInvalidInput) {
+			t.Fatalf("SetACL(%q, %v): expected ErrInvalidInput, got %v", tc.visibility, tc.sharedWith, err)
+		}
+	}
+}
+
+func TestSetACLSurfacesVectorStoreFailure(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{"doc-1": {ID: "doc-1"}}}
+	uc := NewDocumentACLUseCase(repo, &aclVectorFake{err: errors.New("qdrant down")})
+
+	if _, err := uc.SetACL(context.Background(), "doc-1", domain.VisibilityPrivate, nil); err == nil {
+		t.Fatal("expected error when the vector payload cannot be updated")
+	}
+}
+
+func TestSetACLAppliesToAttachments(t *testing.T) {
+	repo := &deleteRepoFake{docs: map[string]*domain.Document{
+		"mail-1": {ID: "mail-1", OwnerID: "alice", Visibility: domain.VisibilityPublic},
+		"att-1":  {ID: "att-1", OwnerID: "alice", ParentID: "mail-1", Visibility: domain.VisibilityPublic},
+	}}
+	vector := &aclVectorFake{}
+	uc := NewDocumentACLUseCase(repo, vector)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	if _, err := uc.SetACL(ctx, "mail-1", domain.VisibilityPrivate, nil); err != nil {
+		t.Fatalf("SetACL() error = %v", err)
+	}
+	if got := repo.docs["att-1"].Visibility; got != domain.VisibilityPrivate {
+		t.Fatalf("attachment visibility = %q, want private", got)
+	}
+	if got := vector.payloads["att-1"]["visibility"]; got != string(domain.VisibilityPrivate) {
+		t.Fatalf("attachment payload visibility = %v, want private", got)
+	}
+}
@@ -24,6 +24,13 @@ func (f *enrichRepoFake) GetByID(context.Context, string) (*domain.Document, err
 	copyDoc := *f.doc
 	return &copyDoc, nil
 }
+func (f *enrichRepoFake) GetBySourceID(context.Context, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *enrichRepoFake) GetByContentHash(context.Context, string, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *enrichRepoFake) UpdateSource(context.Context, *domain.Document) error { return nil }
 func (f *enrichRepoFake) UpdateStatus(context.Context, string, domain.DocumentStatus, string) error {
 	return nil
 }
@@ -35,10 +42,17 @@ func (f *enrichRepoFake) SaveClassification(_ context.Context, id string, cls do
 	f.savedCls = cls
 	return nil
 }
+func (f *enrichRepoFake) UpdateACL(context.Context, string, domain.DocumentVisibility, []string) error {
+	return nil
+}
 
 func (f *enrichRepoFake) ListRecent(context.Context, int) ([]domain.Document, error) {
 	return nil, nil
 }
+func (f *enrichRepoFake) ListByFilter(context.Context, domain.DocumentFilter, int) ([]domain.Document, error) {
+	return nil, nil
+}
+func (f *enrichRepoFake) Delete(context.Context, string) error { return nil }
 
 type enrichVectorFake struct {
 	updatedDocID   string
@@ -63,6 +77,7 @@ func (f *enrichVectorFake) UpdateChunksPayload(_ context.Context, docID string,
 	f.updatedPayload = payload
 	return nil
 }
+func (f *enrichVectorFake) DeleteByDocumentID(context.Context, string) error { return nil }
 
 // classifierFake for enrichment tests (the LLM classifier used by enrich)
 type enrichClassifierFake struct {
@@ -0,0 +1,513 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	evalDefaultK = 5
+	evalMaxK     = 50
+	// evalMaxConfigs bounds one request; every config is a full pass over
+	// all cases.
+	evalMaxConfigs  = 10
+	evalMaxCases    = 1000
+	evalQuestionMax = 2000
+	// evalEpsilon absorbs float noise when comparing metrics.
+	evalEpsilon = 1e-9
+)
+
+// EvalUseCase measures retrieval quality: it runs every stored eval case
+// through QueryUseCase under one or more retrieval configs, scores the
+// retrieved documents and keeps each run for later comparison.
+type EvalUseCase struct {
+	store    ports.EvalStore
+	query    *QueryUseCase
+	chunking map[string]domain.ChunkConfig
+}
+
+func NewEvalUseCase(store ports.EvalStore, query *QueryUseCase) *EvalUseCase {
+	return &EvalUseCase{store: store, query: query}
+}
+
+// SetChunking sets the chunker settings recorded with every run.
+func (uc *EvalUseCase) SetChunking(chunking map[string]domain.ChunkConfig) {
+	uc.chunking = chunking
+}
+
+func (uc *EvalUseCase) ListCases(ctx context.Context) ([]domain.EvalCase, error) {
+	return uc.store.ListCases(ctx)
+}
+
+// SaveCases validates all cases before writing any of them, so a bad line in
+// a bulk upload leaves the store unchanged.
+func (uc *EvalUseCase) SaveCases(ctx context.Context, cases []domain.EvalCase) ([]domain.EvalCase, error) {
+	const op = "save eval cases"
+	if len(cases) == 0 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, errors.New("at least one case is required"))
+	}
+	if len(cases) > evalMaxCases {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("at most %d cases per request", evalMaxCases))
+	}
+
+	now := time.Now().UTC()
+	out := make([]domain.EvalCase, 0, len(cases))
+	for i, c := range cases {
+		c, err := normalizeEvalCase(c)
+		if err != nil {
+			return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("case %d: %w", i+1, err))
+		}
+		if c.ID == "" {
+			c.ID = uuid.NewString()
+		}
+		if c.Source == "" {
+			c.Source = domain.EvalCaseSourceAPI
+		}
+		c.CreatedAt, c.UpdatedAt = now, now
+		out = append(out, c)
+	}
+	for i := range out {
+		if err := uc.store.UpsertCase(ctx, &out[i]); err != nil {
+			return nil, err
+		}
+	}
+	slog.Info("eval_cases_saved", "count", len(out))
+	return out, nil
+}
+
+func (uc *EvalUseCase) DeleteCase(ctx context.Context, id string) error {
+	if strings.TrimSpace(id) == "" {
+		return domain.WrapError(domain.ErrInvalidInput, "delete eval case", errors.New("case id is required"))
+	}
+	return uc.store.DeleteCase(ctx, id)
+}
+
+func (uc *EvalUseCase) ListRuns(ctx context.Context, limit int) ([]domain.EvalRun, error) {
+	return uc.store.ListRuns(ctx, limit)
+}
+
+func (uc *EvalUseCase) GetRun(ctx context.Context, id string) (*domain.EvalRun, error) {
+	if strings.TrimSpace(id) == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "get eval run", errors.New("run id is required"))
+	}
+	return uc.store.GetRun(ctx, id)
+}
+
+// Run evaluates all stored cases once per requested config. Runs execute one
+// after another so their latencies are comparable.
+func (uc *EvalUseCase) Run(ctx context.Context, req domain.EvalRunRequest) ([]domain.EvalRun, error) {
+	const op = "run eval"
+	k := req.K
+	if k == 0 {
+		k = evalDefaultK
+	}
+	if k < 1 || k > evalMaxK {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("k must be between 1 and %d", evalMaxK))
+	}
+	if req.MaxDrop < 0 || req.MaxDrop > 1 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, errors.New("max_drop must be between 0 and 1"))
+	}
+	configs := req.Configs
+	if len(configs) == 0 {
+		configs = []domain.RetrievalConfig{{}}
+	}
+	if len(configs) > evalMaxConfigs {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("at most %d configs per request", evalMaxConfigs))
+	}
+	for i, cfg := range configs {
+		if err := validateRetrievalConfig(cfg); err != nil {
+			return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("config %d: %w", i+1, err))
+		}
+	}
+
+	var baseline *domain.EvalRun
+	if id := strings.TrimSpace(req.BaselineRunID); id != "" {
+		run, err := uc.store.GetRun(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if run.Status != domain.EvalRunStatusOK {
+			return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("baseline run %s has status %s", id, run.Status))
+		}
+		if run.K != k {
+			return nil, domain.WrapError(domain.ErrInvalidInput, op, fmt.Errorf("baseline run %s used k=%d, not %d", id, run.K, k))
+		}
+		baseline = run
+	}
+
+	cases, err := uc.store.ListCases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(cases) == 0 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, op, errors.New("no eval cases stored"))
+	}
+
+	runs := make([]domain.EvalRun, 0, len(configs))
+	for _, cfg := range configs {
+		run, err := uc.runConfig(ctx, cases, cfg, k, req.GenerateAnswers, baseline, req.MaxDrop)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, *run)
+	}
+	return runs, nil
+}
+
+func (uc *EvalUseCase) runConfig(
+	ctx context.Context,
+	cases []domain.EvalCase,
+	cfg domain.RetrievalConfig,
+	k int,
+	generate bool,
+	baseline *domain.EvalRun,
+	maxDrop float64,
+) (*domain.EvalRun, error) {
+	query := uc.query.WithRetrievalConfig(cfg)
+	run := &domain.EvalRun{
+		ID:              uuid.NewString(),
+		Status:          domain.EvalRunStatusRunning,
+		Config:          query.RetrievalConfig(),
+		Chunking:        uc.chunking,
+		K:               k,
+		GenerateAnswers: generate,
+		CaseCount:       len(cases),
+		StartedAt:       time.Now().UTC(),
+	}
+	if err := uc.store.CreateRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("record eval run: %w", err)
+	}
+
+	results := make([]domain.EvalCaseResult, 0, len(cases))
+	for _, c := range cases {
+		if err := ctx.Err(); err != nil {
+			run.Status = domain.EvalRunStatusError
+			run.Error = err.Error()
+			break
+		}
+		results = append(results, evalOneCase(ctx, query, c, k, generate))
+	}
+	run.Results = results
+	run.Metrics, run.Failed = summarizeEvalResults(results)
+	if run.Status == domain.EvalRunStatusRunning {
+		run.Status = domain.EvalRunStatusOK
+		if baseline != nil {
+			run.Gate = gateEvalRun(run, baseline, maxDrop)
+		}
+	}
+	uc.finishRun(run)
+
+	slog.Info("eval_run_done",
+		"run_id", run.ID,
+		"mode", run.Config.Mode,
+		"status", run.Status,
+		"cases", run.CaseCount,
+		"failed", run.Failed,
+		"recall_at_k", run.Metrics.RecallAtK,
+		"mrr", run.Metrics.MRR,
+	)
+	return run, nil
+}
+
+// finishRun stores the results of a run. It uses a fresh context so a
+// cancelled run still leaves a finished record behind.
+func (uc *EvalUseCase) finishRun(run *domain.EvalRun) {
+	now := time.Now().UTC()
+	run.FinishedAt = &now
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := uc.store.FinishRun(ctx, run); err != nil {
+		slog.Warn("eval_run_record_failed", "run_id", run.ID, "error", err)
+	}
+}
+
+// Diff compares run headID against run baseID case by case.
+func (uc *EvalUseCase) Diff(ctx context.Context, baseID, headID string) (*domain.EvalRunDiff, error) {
+	if strings.TrimSpace(baseID) == "" || strings.TrimSpace(headID) == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "diff eval runs", errors.New("base and head run ids are required"))
+	}
+	base, err := uc.store.GetRun(ctx, baseID)
+	if err != nil {
+		return nil, err
+	}
+	head, err := uc.store.GetRun(ctx, headID)
+	if err != nil {
+		return nil, err
+	}
+	return diffEvalRuns(base, head), nil
+}
+
+func evalOneCase(ctx context.Context, query *QueryUseCase, c domain.EvalCase, k int, generate bool) domain.EvalCaseResult {
+	res := domain.EvalCaseResult{CaseID: c.ID, Question: c.Question}
+	start := time.Now()
+	var (
+		sources []domain.RetrievedChunk
+		err     error
+	)
+	if generate {
+		var answer *domain.Answer
+		if answer, err = query.Answer(ctx, c.Question, k, domain.SearchFilter{}); err == nil {
+			sources, res.Answer = answer.Sources, answer.Text
+		}
+	} else {
+		sources, _, err = query.Retrieve(ctx, c.Question, k, domain.SearchFilter{})
+	}
+	res.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Retrieved, res.Relevant, res.Expected, res.Metrics = scoreEvalCase(c, sources, k)
+	return res
+}
+
+// scoreEvalCase ranks the distinct documents among sources and scores them
+// against the case's expectations with binary relevance. Recall counts the
+// expected IDs and filenames that were found; precision counts relevant
+// documents among the k slots.
+func scoreEvalCase(c domain.EvalCase, sources []domain.RetrievedChunk, k int) ([]string, int, int, domain.EvalMetrics) {
+	wantIDs := make(map[string]bool, len(c.ExpectedDocumentIDs))
+	for _, id := range c.ExpectedDocumentIDs {
+		wantIDs[id] = false
+	}
+	wantFiles := make(map[string]bool, len(c.ExpectedFilenames))
+	for _, name := range c.ExpectedFilenames {
+		wantFiles[name] = false
+	}
+	expected := len(wantIDs) + len(wantFiles)
+
+	var (
+		retrieved []string
+		seen      = make(map[string]bool)
+		relevant  int
+		rr, dcg   float64
+	)
+	for _, src := range sources {
+		key := src.DocumentID
+		if key == "" {
+			key = src.Filename
+		}
+		if seen[key] {
+			continue
+		}
+		if len(retrieved) == k {
+			break
+		}
+		seen[key] = true
+		label := src.Filename
+		if label == "" {
+			label = src.DocumentID
+		}
+		retrieved = append(retrieved, label)
+
+		_, idHit := wantIDs[src.DocumentID]
+		_, fileHit := wantFiles[src.Filename]
+		if !idHit && !fileHit {
+			continue
+		}
+		if idHit {
+			wantIDs[src.DocumentID] = true
+		}
+		if fileHit {
+			wantFiles[src.Filename] = true
+		}
+		rank := len(retrieved)
+		relevant++
+		if rr == 0 {
+			rr = 1 / float64(rank)
+		}
+		dcg += 1 / math.Log2(float64(rank)+1)
+	}
+
+	found := 0
+	for _, hit := range wantIDs {
+		if hit {
+			found++
+		}
+	}
+	for _, hit := range wantFiles {
+		if hit {
+			found++
+		}
+	}
+	var idcg float64
+	for rank := 1; rank <= min(expected, k); rank++ {
+		idcg += 1 / math.Log2(float64(rank)+1)
+	}
+
+	m := domain.EvalMetrics{PrecisionAtK: float64(relevant) / float64(k), MRR: rr}
+	if expected > 0 {
+		m.RecallAtK = float64(found) / float64(expected)
+	}
+	if idcg > 0 {
+		m.NDCG = math.Min(dcg/idcg, 1)
+	}
+	return retrieved, relevant, expected, m
+}
+
+// summarizeEvalResults averages the metrics of cases that ran without error.
+func summarizeEvalResults(results []domain.EvalCaseResult) (domain.EvalMetrics, int) {
+	var (
+		sum    domain.EvalMetrics
+		scored int
+		failed int
+	)
+	for _, res := range results {
+		if res.Error != "" {
+			failed++
+			continue
+		}
+		scored++
+		sum.PrecisionAtK += res.Metrics.PrecisionAtK
+		sum.RecallAtK += res.Metrics.RecallAtK
+		sum.MRR += res.Metrics.MRR
+		sum.NDCG += res.Metrics.NDCG
+	}
+	if scored == 0 {
+		return domain.EvalMetrics{}, failed
+	}
+	n := float64(scored)
+	return domain.EvalMetrics{
+		PrecisionAtK: sum.PrecisionAtK / n,
+		RecallAtK:    sum.RecallAtK / n,
+		MRR:          sum.MRR / n,
+		NDCG:         sum.NDCG / n,
+	}, failed
+}
+
+func gateEvalRun(run, baseline *domain.EvalRun, maxDrop float64) *domain.EvalGate {
+	gate := &domain.EvalGate{BaselineRunID: baseline.ID, MaxDrop: maxDrop}
+	check := func(name string, head, base float64) {
+		if base-head > maxDrop+evalEpsilon {
+			gate.Failures = append(gate.Failures, fmt.Sprintf("%s dropped from %.3f to %.3f", name, base, head))
+		}
+	}
+	check("precision_at_k", run.Metrics.PrecisionAtK, baseline.Metrics.PrecisionAtK)
+	check("recall_at_k", run.Metrics.RecallAtK, baseline.Metrics.RecallAtK)
+	check("mrr", run.Metrics.MRR, baseline.Metrics.MRR)
+	check("ndcg", run.Metrics.NDCG, baseline.Metrics.NDCG)
+	if run.Failed > baseline.Failed {
+		gate.Failures = append(gate.Failures, fmt.Sprintf("%d cases failed, baseline had %d", run.Failed, baseline.Failed))
+	}
+	gate.Passed = len(gate.Failures) == 0
+	return gate
+}
+
+// diffEvalRuns pairs results by case ID. A case counts as improved or
+// regressed by its nDCG, falling back to recall when nDCG is unchanged.
+func diffEvalRuns(base, head *domain.EvalRun) *domain.EvalRunDiff {
+	diff := &domain.EvalRunDiff{
+		BaseRunID: base.ID,
+		HeadRunID: head.ID,
+		Base:      base.Metrics,
+		Head:      head.Metrics,
+		Delta: domain.EvalMetrics{
+			PrecisionAtK: head.Metrics.PrecisionAtK - base.Metrics.PrecisionAtK,
+			RecallAtK:    head.Metrics.RecallAtK - base.Metrics.RecallAtK,
+			MRR:          head.Metrics.MRR - base.Metrics.MRR,
+			NDCG:         head.Metrics.NDCG - base.Metrics.NDCG,
+		},
+		Cases: make([]domain.EvalCaseDiff, 0),
+	}
+
+	baseByID := make(map[string]domain.EvalCaseResult, len(base.Results))
+	for _, res := range base.Results {
+		baseByID[res.CaseID] = res
+	}
+	inHead := make(map[string]bool, len(head.Results))
+	for _, res := range head.Results {
+		inHead[res.CaseID] = true
+		headMetrics := res.Metrics
+		prev, ok := baseByID[res.CaseID]
+		if !ok {
+			diff.Cases = append(diff.Cases, domain.EvalCaseDiff{
+				CaseID: res.CaseID, Question: res.Question, Change: domain.EvalChangeAdded, Head: &headMetrics,
+			})
+			continue
+		}
+		delta := headMetrics.NDCG - prev.Metrics.NDCG
+		if math.Abs(delta) < evalEpsilon {
+			delta = headMetrics.RecallAtK - prev.Metrics.RecallAtK
+		}
+		if math.Abs(delta) < evalEpsilon {
+			continue
+		}
+		change := domain.EvalChangeImproved
+		if delta < 0 {
+			change = domain.EvalChangeRegressed
+		}
+		baseMetrics := prev.Metrics
+		diff.Cases = append(diff.Cases, domain.EvalCaseDiff{
+			CaseID: res.CaseID, Question: res.Question, Change: change, Base: &baseMetrics, Head: &headMetrics,
+		})
+	}
+	for _, res := range base.Results {
+		if inHead[res.CaseID] {
+			continue
+		}
+		baseMetrics := res.Metrics
+		diff.Cases = append(diff.Cases, domain.EvalCaseDiff{
+			CaseID: res.CaseID, Question: res.Question, Change: domain.EvalChangeRemoved, Base: &baseMetrics,
+		})
+	}
+	return diff
+}
+
+func normalizeEvalCase(c domain.EvalCase) (domain.EvalCase, error) {
+	c.ID = strings.TrimSpace(c.ID)
+	c.Question = strings.TrimSpace(c.Question)
+	c.GroundTruth = strings.TrimSpace(c.GroundTruth)
+	if c.Question == "" {
+		return c, errors.New("question is required")
+	}
+	if len([]rune(c.Question)) > evalQuestionMax {
+		return c, fmt.Errorf("question is longer than %d characters", evalQuestionMax)
+	}
+	c.ExpectedDocumentIDs = uniqueNonEmpty(c.ExpectedDocumentIDs)
+	c.ExpectedFilenames = uniqueNonEmpty(c.ExpectedFilenames)
+	if len(c.ExpectedDocumentIDs) == 0 && len(c.ExpectedFilenames) == 0 {
+		return c, errors.New("expected_document_ids or expected_filenames is required")
+	}
+	return c, nil
+}
+
+func validateRetrievalConfig(cfg domain.RetrievalConfig) error {
+	switch cfg.Mode {
+	case "", domain.RetrievalModeSemantic, domain.RetrievalModeHybrid, domain.RetrievalModeHybridRerank:
+	default:
+		return fmt.Errorf("unknown retrieval mode %q", cfg.Mode)
+	}
+	switch cfg.FusionStrategy {
+	case "", domain.FusionStrategyRRF:
+	default:
+		return fmt.Errorf("unknown fusion strategy %q", cfg.FusionStrategy)
+	}
+	if cfg.HybridCandidates < 0 || cfg.FusionRRFK < 0 || cfg.RerankTopN < 0 {
+		return errors.New("numeric settings must not be negative")
+	}
+	return nil
+}
+
+func uniqueNonEmpty(values []string) []string {
+	out := make([]string, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
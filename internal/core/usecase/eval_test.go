@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeEvalStore struct {
+	cases []domain.EvalCase
+	runs  map[string]*domain.EvalRun
+}
+
+func (f *fakeEvalStore) UpsertCase(_ context.Context, c *domain.EvalCase) error {
+	for i := range f.cases {
+		if f.cases[i].ID == c.ID {
+			f.cases[i] = *c
+			return nil
+		}
+	}
+	f.cases = append(f.cases, *c)
+	return nil
+}
+
+func (f *fakeEvalStore) ListCases(context.Context) ([]domain.EvalCase, error) {
+	return append([]domain.EvalCase(nil), f.cases...), nil
+}
+
+func (f *fakeEvalStore) DeleteCase(_ context.Context, id string) error {
+	for i := range f.cases {
+		if f.cases[i].ID == id {
+			f.cases = append(f.cases[:i], f.cases[i+1:]...)
+			return nil
+		}
+	}
+	return domain.WrapError(domain.ErrEvalCaseNotFound, "delete eval case", errors.New("id="+id))
+}
+
+func (f *fakeEvalStore) CreateRun(_ context.Context, run *domain.EvalRun) error {
+	if f.runs == nil {
+		f.runs = make(map[string]*domain.EvalRun)
+	}
+	stored := *run
+	f.runs[run.ID] = &stored
+	return nil
+}
+
+func (f *fakeEvalStore) FinishRun(_ context.Context, run *domain.EvalRun) error {
+	stored := *run
+	f.runs[run.ID] = &stored
+	return nil
+}
+
+func (f *fakeEvalStore) GetRun(_ context.Context, id string) (*domain.EvalRun, error) {
+	run, ok := f.runs[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrEvalRunNotFound, "get eval run", errors.New("id="+id))
+	}
+	return run, nil
+}
+
+func (f *fakeEvalStore) ListRuns(context.Context, int) ([]domain.EvalRun, error) {
+	out := make([]domain.EvalRun, 0, len(f.runs))
+	for _, run := range f.runs {
+		out = append(out, *run)
+	}
+	return out, nil
+}
+
+// newEvalFixture serves vpn.md only from semantic search and notes.md only
+// from lexical search, so semantic and hybrid runs score differently.
+func newEvalFixture() (*EvalUseCase, *fakeEvalStore, *queryVectorFake) {
+	vector := &queryVectorFake{
+		semanticResponse: []domain.RetrievedChunk{{DocumentID: "doc-vpn", Filename: "vpn.md", Text: "vpn"}},
+		lexicalResponse:  []domain.RetrievedChunk{{DocumentID: "doc-notes", Filename: "notes.md", Text: "notes"}},
+	}
+	query := NewQueryUseCase(&queryEmbedderFake{}, vector, &queryGeneratorFake{}, QueryOptions{
+		RetrievalMode: domain.RetrievalModeSemantic,
+	})
+	store := &fakeEvalStore{cases: []domain.EvalCase{
+		{ID: "EX001", Question: "Where are the meeting notes?", ExpectedFilenames: []string{"notes.md"}},
+	}}
+	return NewEvalUseCase(store, query), store, vector
+}
+
+func TestScoreEvalCase(t *testing.T) {
+	c := domain.EvalCase{ExpectedDocumentIDs: []string{"d2"}, ExpectedFilenames: []string{"c.md"}}
+	sources := []domain.RetrievedChunk{
+		{DocumentID: "d1", Filename: "a.md"},
+		{DocumentID: "d2", Filename: "b.md"},
+		{DocumentID: "d2", Filename: "b.md", ChunkIndex: 1},
+		{DocumentID: "d4", Filename: "d.md"},
+		{DocumentID: "d3", Filename: "c.md"},
+	}
+
+	retrieved, relevant, expected, m := scoreEvalCase(c, sources, 4)
+	if len(retrieved) != 4 || retrieved[1] != "b.md" || retrieved[3] != "c.md" {
+		t.Fatalf("expected distinct documents in rank order, got %v", retrieved)
+	}
+	if relevant != 2 || expected != 2 {
+		t.Fatalf("relevant=%d expected=%d", relevant, expected)
+	}
+	wantNDCG := (1/math.Log2(3) + 1/math.Log2(5)) / (1 + 1/math.Log2(3))
+	if m.PrecisionAtK != 0.5 || m.RecallAtK != 1 || m.MRR != 0.5 || math.Abs(m.NDCG-wantNDCG) > 1e-9 {
+		t.Fatalf("unexpected metrics: %+v (want ndcg %.4f)", m, wantNDCG)
+	}
+
+	_, _, _, m = scoreEvalCase(c, nil, 4)
+	if m != (domain.EvalMetrics{}) {
+		t.Fatalf("empty retrieval must score zero, got %+v", m)
+	}
+}
+
+func TestEvalRunScoresEachConfigAndGates(t *testing.T) {
+	uc, store, vector := newEvalFixture()
+	ctx := context.Background()
+
+	runs, err := uc.Run(ctx, domain.EvalRunRequest{Configs: []domain.RetrievalConfig{
+		{Mode: domain.RetrievalModeSemantic},
+		{Mode: domain.RetrievalModeHybrid},
+	}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(runs) != 2 || vector.searchLexCalls != 1 {
+		t.Fatalf("expected two runs and one lexical search, got %d runs, %d lexical", len(runs), vector.searchLexCalls)
+	}
+	semantic, hybrid := runs[0], runs[1]
+	if semantic.Config.Mode != domain.RetrievalModeSemantic || semantic.Metrics.RecallAtK != 0 {
+		t.Fatalf("unexpected semantic run: %+v", semantic)
+	}
+	if hybrid.Config.Mode != domain.RetrievalModeHybrid || hybrid.Metrics.RecallAtK != 1 || hybrid.K != evalDefaultK {
+		t.Fatalf("unexpected hybrid run: %+v", hybrid)
+	}
+	if stored := store.runs[hybrid.ID]; stored.Status != domain.EvalRunStatusOK || stored.FinishedAt == nil || len(stored.Results) != 1 {
+		t.Fatalf("run not finished in store: %+v", stored)
+	}
+
+	gated, err := uc.Run(ctx, domain.EvalRunRequest{BaselineRunID: hybrid.ID, MaxDrop: 0.05})
+	if err != nil {
+		t.Fatalf("gated Run() error = %v", err)
+	}
+	gate := gated[0].Gate
+	if gate == nil || gate.Passed || len(gate.Failures) == 0 {
+		t.Fatalf("expected failing gate against the hybrid baseline, got %+v", gate)
+	}
+
+	diff, err := uc.Diff(ctx, semantic.ID, hybrid.ID)
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+	if diff.Delta.RecallAtK != 1 || len(diff.Cases) != 1 || diff.Cases[0].Change != domain.EvalChangeImproved {
+		t.Fatalf("unexpected diff: %+v", diff)
+	}
+}
+
+func TestEvalRunValidation(t *testing.T) {
+	uc, store, _ := newEvalFixture()
+	ctx := context.Background()
+
+	if _, err := uc.Run(ctx, domain.EvalRunRequest{Configs: []domain.RetrievalConfig{{Mode: "bm25"}}}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("unknown mode: error = %v", err)
+	}
+	if _, err := uc.Run(ctx, domain.EvalRunRequest{K: evalMaxK + 1}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("large k: error = %v", err)
+	}
+	if _, err := uc.Run(ctx, domain.EvalRunRequest{BaselineRunID: "missing"}); !domain.IsKind(err, domain.ErrEvalRunNotFound) {
+		t.Fatalf("missing baseline: error = %v", err)
+	}
+	store.cases = nil
+	if _, err := uc.Run(ctx, domain.EvalRunRequest{}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("no cases: error = %v", err)
+	}
+	if len(store.runs) != 0 {
+		t.Fatalf("invalid requests must not record runs, got %d", len(store.runs))
+	}
+}
+
+func TestSaveEvalCasesIsAllOrNothing(t *testing.T) {
+	uc, store, _ := newEvalFixture()
+
+	_, err := uc.SaveCases(context.Background(), []domain.EvalCase{
+		{Question: "Valid", ExpectedFilenames: []string{"a.md", " a.md ", ""}},
+		{Question: "No expectations"},
+	})
+	if !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("SaveCases() error = %v, want invalid input", err)
+	}
+	if len(store.cases) != 1 {
+		t.Fatalf("a rejected batch must not store any case, got %d", len(store.cases))
+	}
+
+	saved, err := uc.SaveCases(context.Background(), []domain.EvalCase{
+		{Question: "Valid", ExpectedFilenames: []string{"a.md", " a.md ", ""}},
+	})
+	if err != nil {
+		t.Fatalf("SaveCases() error = %v", err)
+	}
+	if saved[0].ID == "" || saved[0].Source != domain.EvalCaseSourceAPI || len(saved[0].ExpectedFilenames) != 1 {
+		t.Fatalf("unexpected saved case: %+v", saved[0])
+	}
+}
@@ -37,6 +37,10 @@ func (c *EventCollector) RecordCriticRejection(ctx context.Context, userID, conv
 	c.record(ctx, userID, convID, "critic_rejection", map[string]any{"feedback": feedback})
 }
 
+func (c *EventCollector) RecordToolViolation(ctx context.Context, userID, convID, agent, toolName string) {
+	c.record(ctx, userID, convID, "tool_violation", map[string]any{"agent": agent, "tool": toolName})
+}
+
 func (c *EventCollector) record(ctx context.Context, userID, convID, eventType string, details map[string]any) {
 	if c.store == nil {
 		return
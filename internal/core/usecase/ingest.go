@@ -1,10 +1,15 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"log/slog"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -62,26 +67,93 @@ func (uc *IngestDocumentUseCase) IngestFromSource(
 		return nil, fmt.Errorf("source adapter %q: %w", req.SourceType, err)
 	}
 
-	id := uuid.NewString()
-	storageKey := fmt.Sprintf("%s_%s", id, sanitizeFilename(result.Filename))
-	now := time.Now().UTC()
+	if result.SourceID != "" {
+		existing, err := uc.repo.GetBySourceID(ctx, result.SourceID)
+		switch {
+		case err == nil:
+			return uc.reingest(ctx, existing, result)
+		case !domain.IsKind(err, domain.ErrDocumentNotFound):
+			return nil, fmt.Errorf("lookup document by source id: %w", err)
+		}
+	}
 
-	if err := uc.storage.Save(ctx, storageKey, result.Body); err != nil {
-		return nil, fmt.Errorf("save to object storage: %w", err)
+	doc := &domain.Document{
+		Filename:   result.Filename,
+		MimeType:   result.MimeType,
+		SourceType: result.SourceType,
+		Path:       result.Path,
+		SourceID:   result.SourceID,
+	}
+	// Documents with a stable source identity (e.g. vault notes) are shared;
+	// everything else belongs to the caller.
+	if p, ok := domain.PrincipalFromContext(ctx); ok && result.SourceID == "" {
+		doc.OwnerID = p.UserID
+	}
+	doc.Visibility = doc.EffectiveVisibility()
+	return uc.create(ctx, doc, result.Body)
+}
+
+// IngestAttachment ingests a file found inside parent as a child document
+// that inherits the parent's owner and access rules. The child's SourceID is
+// derived from the parent and index, so re-processing the parent replaces
+// its attachments in place.
+func (uc *IngestDocumentUseCase) IngestAttachment(
+	ctx context.Context,
+	parent *domain.Document,
+	index int,
+	attachment domain.Attachment,
+) (*domain.Document, error) {
+	result := &domain.IngestResult{
+		Filename:   attachment.Filename,
+		MimeType:   attachment.MimeType,
+		Body:       bytes.NewReader(attachment.Data),
+		SourceType: parent.SourceType,
+		Path:       parent.Path + "/" + attachment.Filename,
+		SourceID:   attachmentSourcePrefix(parent.ID) + strconv.Itoa(index),
+	}
+	existing, err := uc.repo.GetBySourceID(ctx, result.SourceID)
+	switch {
+	case err == nil:
+		return uc.reingest(ctx, existing, result)
+	case !domain.IsKind(err, domain.ErrDocumentNotFound):
+		return nil, fmt.Errorf("lookup attachment by source id: %w", err)
 	}
 
 	doc := &domain.Document{
-		ID:          id,
-		Filename:    result.Filename,
-		MimeType:    result.MimeType,
-		StoragePath: storageKey,
-		SourceType:  result.SourceType,
-		Path:        result.Path,
-		Status:      domain.StatusUploaded,
-		Tags:        []string{},
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		Filename:   result.Filename,
+		MimeType:   result.MimeType,
+		SourceType: result.SourceType,
+		Path:       result.Path,
+		SourceID:   result.SourceID,
+		ParentID:   parent.ID,
+		OwnerID:    parent.OwnerID,
+		Visibility: parent.EffectiveVisibility(),
+		SharedWith: parent.SharedWith,
 	}
+	return uc.create(ctx, doc, result.Body)
+}
+
+// attachmentSourcePrefix starts the SourceID of every attachment of
+// parentID; the attachment's index follows it.
+func attachmentSourcePrefix(parentID string) string {
+	return "attachment:" + parentID + "/"
+}
+
+// create stores body as the content of a new document, records doc and
+// queues it for processing.
+func (uc *IngestDocumentUseCase) create(ctx context.Context, doc *domain.Document, body io.Reader) (*domain.Document, error) {
+	doc.ID = uuid.NewString()
+	doc.StoragePath = fmt.Sprintf("%s_%s", doc.ID, sanitizeFilename(doc.Filename))
+	hash, err := uc.save(ctx, doc.StoragePath, body)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	doc.ContentHash = hash
+	doc.Status = domain.StatusUploaded
+	doc.Tags = []string{}
+	doc.CreatedAt = now
+	doc.UpdatedAt = now
 
 	if err := uc.repo.Create(ctx, doc); err != nil {
 		return nil, fmt.Errorf("create document metadata: %w", err)
@@ -94,6 +166,54 @@ func (uc *IngestDocumentUseCase) IngestFromSource(
 	return doc, nil
 }
 
+// reingest replaces the stored content of an existing document in place, keeping
+// its ID so the processing pipeline overwrites chunks instead of duplicating them.
+func (uc *IngestDocumentUseCase) reingest(
+	ctx context.Context,
+	existing *domain.Document,
+	result *domain.IngestResult,
+) (*domain.Document, error) {
+	storageKey := fmt.Sprintf("%s_%s", existing.ID, sanitizeFilename(result.Filename))
+	hash, err := uc.save(ctx, storageKey, result.Body)
+	if err != nil {
+		return nil, err
+	}
+	if existing.StoragePath != "" && existing.StoragePath != storageKey {
+		if err := uc.storage.Delete(ctx, existing.StoragePath); err != nil {
+			slog.Warn("reingest_delete_old_blob_failed", "document_id", existing.ID, "error", err)
+		}
+	}
+
+	doc := *existing
+	doc.Filename = result.Filename
+	doc.MimeType = result.MimeType
+	doc.StoragePath = storageKey
+	doc.SourceType = result.SourceType
+	doc.Path = result.Path
+	doc.ContentHash = hash
+	doc.Status = domain.StatusUploaded
+	doc.Error = ""
+	doc.UpdatedAt = time.Now().UTC()
+
+	if err := uc.repo.UpdateSource(ctx, &doc); err != nil {
+		return nil, fmt.Errorf("update document metadata: %w", err)
+	}
+
+	if err := uc.queue.PublishDocumentIngested(ctx, doc.ID); err != nil {
+		return nil, fmt.Errorf("publish ingestion event: %w", err)
+	}
+	return &doc, nil
+}
+
+// save stores body under key and returns the hex SHA-256 of the content.
+func (uc *IngestDocumentUseCase) save(ctx context.Context, key string, body io.Reader) (string, error) {
+	h := sha256.New()
+	if err := uc.storage.Save(ctx, key, io.TeeReader(body, h)); err != nil {
+		return "", fmt.Errorf("save to object storage: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
 func sanitizeFilename(name string) string {
 	base := filepath.Base(name)
 	base = strings.ReplaceAll(base, " ", "_")
@@ -14,8 +14,10 @@ import (
 )
 
 type ingestRepoFake struct {
-	created *domain.Document
-	err     error
+	created  *domain.Document
+	existing *domain.Document
+	updated  *domain.Document
+	err      error
 }
 
 func (f *ingestRepoFake) Create(_ context.Context, doc *domain.Document) error {
@@ -30,21 +32,44 @@ func (f *ingestRepoFake) Create(_ context.Context, doc *domain.Document) error {
 func (f *ingestRepoFake) GetByID(context.Context, string) (*domain.Document, error) {
 	return nil, errors.New("not implemented")
 }
+func (f *ingestRepoFake) GetBySourceID(_ context.Context, sourceID string) (*domain.Document, error) {
+	if f.existing != nil && f.existing.SourceID == sourceID {
+		copyDoc := *f.existing
+		return &copyDoc, nil
+	}
+	return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by source id", errors.New(sourceID))
+}
+func (f *ingestRepoFake) GetByContentHash(_ context.Context, hash, _ string) (*domain.Document, error) {
+	return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by content hash", errors.New(hash))
+}
+func (f *ingestRepoFake) UpdateSource(_ context.Context, doc *domain.Document) error {
+	copyDoc := *doc
+	f.updated = &copyDoc
+	return nil
+}
 func (f *ingestRepoFake) UpdateStatus(context.Context, string, domain.DocumentStatus, string) error {
 	return errors.New("not implemented")
 }
 func (f *ingestRepoFake) SaveClassification(context.Context, string, domain.Classification) error {
 	return errors.New("not implemented")
 }
+func (f *ingestRepoFake) UpdateACL(context.Context, string, domain.DocumentVisibility, []string) error {
+	return nil
+}
 
 func (f *ingestRepoFake) ListRecent(context.Context, int) ([]domain.Document, error) {
 	return nil, nil
 }
+func (f *ingestRepoFake) ListByFilter(context.Context, domain.DocumentFilter, int) ([]domain.Document, error) {
+	return nil, nil
+}
+func (f *ingestRepoFake) Delete(context.Context, string) error { return nil }
 
 type ingestStorageFake struct {
-	savedKey  string
-	savedBody string
-	err       error
+	savedKey   string
+	savedBody  string
+	deletedKey string
+	err        error
 }
 
 func (f *ingestStorageFake) Save(_ context.Context, key string, data io.Reader) error {
@@ -64,6 +89,11 @@ func (f *ingestStorageFake) Open(context.Context, string) (io.ReadCloser, error)
 	return io.NopCloser(strings.NewReader("")), nil
 }
 
+func (f *ingestStorageFake) Delete(_ context.Context, key string) error {
+	f.deletedKey = key
+	return nil
+}
+
 type ingestQueueFake struct {
 	documentID string
 	err        error
@@ -131,6 +161,27 @@ func TestIngestUploadSuccess(t *testing.T) {
 	if storage.savedBody != "hello" {
 		t.Fatalf("expected saved body hello, got %s", storage.savedBody)
 	}
+	// sha256("hello")
+	if want := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"; repo.created.ContentHash != want {
+		t.Fatalf("expected content hash %s, got %s", want, repo.created.ContentHash)
+	}
+}
+
+func TestIngestUploadRecordsCallerAsOwner(t *testing.T) {
+	repo := &ingestRepoFake{}
+	adapters := map[string]ports.SourceAdapter{
+		"upload": sourceupload.New(),
+	}
+	uc := NewIngestDocumentUseCase(repo, &ingestStorageFake{}, &ingestQueueFake{}, adapters)
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	doc, err := uc.Upload(ctx, "report.txt", "text/plain", bytes.NewBufferString("hello"))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if doc.OwnerID != "alice" || doc.Visibility != domain.VisibilityPrivate {
+		t.Fatalf("expected private document owned by alice, got owner=%q visibility=%q", doc.OwnerID, doc.Visibility)
+	}
 }
 
 func TestIngestUploadQueueError(t *testing.T) {
@@ -199,3 +250,96 @@ func TestIngestFromSourceUnknownAdapter(t *testing.T) {
 		t.Fatal("expected error for unknown source type")
 	}
 }
+
+func TestIngestFromSourceReusesDocumentForKnownSourceID(t *testing.T) {
+	repo := &ingestRepoFake{existing: &domain.Document{
+		ID:          "doc-1",
+		Filename:    "old.md",
+		StoragePath: "doc-1_old.md",
+		SourceID:    "obsidian:main:notes/plan.md",
+		Status:      domain.StatusReady,
+	}}
+	storage := &ingestStorageFake{}
+	queue := &ingestQueueFake{}
+	adapter := &sourceAdapterFake{
+		result: &domain.IngestResult{
+			Filename:   "plan.md",
+			MimeType:   "text/markdown",
+			Body:       bytes.NewBufferString("# Plan v2"),
+			SourceType: "obsidian",
+			Path:       "notes/plan.md",
+			SourceID:   "obsidian:main:notes/plan.md",
+		},
+	}
+	uc := NewIngestDocumentUseCase(repo, storage, queue, map[string]ports.SourceAdapter{"obsidian": adapter})
+
+	doc, err := uc.IngestFromSource(context.Background(), domain.SourceRequest{SourceType: "obsidian"})
+	if err != nil {
+		t.Fatalf("IngestFromSource() error = %v", err)
+	}
+	if doc.ID != "doc-1" {
+		t.Fatalf("expected existing id doc-1, got %s", doc.ID)
+	}
+	if repo.created != nil {
+		t.Fatalf("expected no new document, got %+v", repo.created)
+	}
+	if repo.updated == nil || repo.updated.Status != domain.StatusUploaded {
+		t.Fatalf("expected source update with status uploaded, got %+v", repo.updated)
+	}
+	if storage.savedKey != "doc-1_plan.md" {
+		t.Errorf("saved key = %q, want %q", storage.savedKey, "doc-1_plan.md")
+	}
+	if storage.deletedKey != "doc-1_old.md" {
+		t.Errorf("expected old blob removed, got %q", storage.deletedKey)
+	}
+	if queue.documentID != "doc-1" {
+		t.Errorf("queued doc id = %q, want doc-1", queue.documentID)
+	}
+}
+
+func TestIngestAttachmentInheritsParentACL(t *testing.T) {
+	repo := &ingestRepoFake{}
+	storage := &ingestStorageFake{}
+	queue := &ingestQueueFake{}
+	uc := NewIngestDocumentUseCase(repo, storage, queue, nil)
+	parent := &domain.Document{
+		ID:         "mail-1",
+		SourceType: "upload",
+		Path:       "inbox/report.eml",
+		OwnerID:    "alice",
+		Visibility: domain.VisibilityShared,
+		SharedWith: []string{"group:team"},
+	}
+
+	doc, err := uc.IngestAttachment(context.Background(), parent, 1, domain.Attachment{
+		Filename: "q3.csv",
+		MimeType: "text/csv",
+		Data:     []byte("revenue,100"),
+	})
+	if err != nil {
+		t.Fatalf("IngestAttachment() error = %v", err)
+	}
+	created := repo.created
+	if created == nil || created.ID != doc.ID {
+		t.Fatalf("expected child document created, got %+v", created)
+	}
+	if created.ParentID != "mail-1" || created.SourceID != "attachment:mail-1/1" || created.Path != "inbox/report.eml/q3.csv" {
+		t.Fatalf("unexpected child identity: %+v", created)
+	}
+	if created.OwnerID != "alice" || created.Visibility != domain.VisibilityShared || len(created.SharedWith) != 1 {
+		t.Fatalf("child must inherit the parent's ACL: %+v", created)
+	}
+	if storage.savedBody != "revenue,100" || queue.documentID != doc.ID {
+		t.Fatalf("unexpected save/queue: body=%q queued=%q", storage.savedBody, queue.documentID)
+	}
+
+	// Re-processing the parent updates the same child.
+	repo.existing = created
+	again, err := uc.IngestAttachment(context.Background(), parent, 1, domain.Attachment{Filename: "q3.csv", Data: []byte("revenue,200")})
+	if err != nil {
+		t.Fatalf("IngestAttachment() error = %v", err)
+	}
+	if again.ID != doc.ID || repo.updated == nil {
+		t.Fatalf("expected child %s reingested in place, got %+v", doc.ID, again)
+	}
+}
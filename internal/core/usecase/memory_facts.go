@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	memoryFactMaxLen = 1000
+	// memoryFactMaxPerUser bounds how many facts a user keeps; they are all
+	// candidates for the system prompt and for contradiction checks.
+	memoryFactMaxPerUser = 200
+	// memoryFactJudgeCandidates is how many recent facts the LLM compares a
+	// new fact against.
+	memoryFactJudgeCandidates = 50
+)
+
+// MemoryFactUseCase stores explicit user facts. Writes are deduplicated and
+// checked for contradictions against the user's existing facts, so "my
+// staging DB is X" followed by "staging DB is now Y" leaves one fact.
+type MemoryFactUseCase struct {
+	store     ports.MemoryFactStore
+	generator ports.AnswerGenerator
+}
+
+// NewMemoryFactUseCase creates the use case. generator may be nil, in which
+// case only exact duplicates are detected.
+func NewMemoryFactUseCase(store ports.MemoryFactStore, generator ports.AnswerGenerator) *MemoryFactUseCase {
+	return &MemoryFactUseCase{store: store, generator: generator}
+}
+
+func (uc *MemoryFactUseCase) List(ctx context.Context, userID string) ([]domain.MemoryFact, error) {
+	if err := requireUserID(userID, "list memory facts"); err != nil {
+		return nil, err
+	}
+	return uc.store.ListFacts(ctx, userID, memoryFactMaxPerUser)
+}
+
+func (uc *MemoryFactUseCase) Remember(
+	ctx context.Context,
+	userID, content string,
+	source domain.MemoryFactSource,
+) (*domain.MemoryFactWrite, error) {
+	const op = "remember memory fact"
+	if err := requireUserID(userID, op); err != nil {
+		return nil, err
+	}
+	content, err := normalizeFactContent(content, op)
+	if err != nil {
+		return nil, err
+	}
+	if source == "" {
+		source = domain.MemoryFactSourceUser
+	}
+
+	existing, err := uc.store.ListFacts(ctx, userID, memoryFactMaxPerUser)
+	if err != nil {
+		return nil, err
+	}
+
+	action, target := domain.MemoryFactCreated, (*domain.MemoryFact)(nil)
+	key := factDedupKey(content)
+	for i := range existing {
+		if factDedupKey(existing[i].Content) == key {
+			action, target = domain.MemoryFactDuplicate, &existing[i]
+			break
+		}
+	}
+	if target == nil {
+		action, target = uc.judgeFact(ctx, content, existing)
+	}
+
+	now := time.Now().UTC()
+	switch action {
+	case domain.MemoryFactDuplicate:
+		slog.Info("memory_fact_written", "user_id", userID, "fact_id", target.ID, "action", action)
+		return &domain.MemoryFactWrite{Fact: *target, Action: action}, nil
+
+	case domain.MemoryFactReplaced:
+		previous := target.Content
+		target.Content = content
+		target.Source = source
+		target.UpdatedAt = now
+		if err := uc.store.UpdateFact(ctx, target); err != nil {
+			return nil, err
+		}
+		slog.Info("memory_fact_written", "user_id", userID, "fact_id", target.ID, "action", action)
+		return &domain.MemoryFactWrite{Fact: *target, Action: action, Previous: previous}, nil
+	}
+
+	if len(existing) >= memoryFactMaxPerUser {
+		return nil, domain.WrapError(domain.ErrConflict, op,
+			fmt.Errorf("memory is full (%d facts), forget some first", memoryFactMaxPerUser))
+	}
+	fact := &domain.MemoryFact{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Content:   content,
+		Source:    source,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.store.CreateFact(ctx, fact); err != nil {
+		return nil, err
+	}
+	slog.Info("memory_fact_written", "user_id", userID, "fact_id", fact.ID, "action", domain.MemoryFactCreated)
+	return &domain.MemoryFactWrite{Fact: *fact, Action: domain.MemoryFactCreated}, nil
+}
+
+func (uc *MemoryFactUseCase) Update(ctx context.Context, userID, id, content string) (*domain.MemoryFact, error) {
+	const op = "update memory fact"
+	if err := requireMemoryFactRef(userID, id, op); err != nil {
+		return nil, err
+	}
+	content, err := normalizeFactContent(content, op)
+	if err != nil {
+		return nil, err
+	}
+	fact, err := uc.store.GetFact(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	fact.Content = content
+	fact.UpdatedAt = time.Now().UTC()
+	if err := uc.store.UpdateFact(ctx, fact); err != nil {
+		return nil, err
+	}
+	return fact, nil
+}
+
+func (uc *MemoryFactUseCase) Forget(ctx context.Context, userID, id string) error {
+	if err := requireMemoryFactRef(userID, id, "forget memory fact"); err != nil {
+		return err
+	}
+	if err := uc.store.DeleteFact(ctx, userID, id); err != nil {
+		return err
+	}
+	slog.Info("memory_fact_forgotten", "user_id", userID, "fact_id", id)
+	return nil
+}
+
+type factJudgement struct {
+	Action string `json:"action"`
+	ID     string `json:"id"`
+}
+
+// judgeFact asks the LLM whether content repeats or contradicts one of the
+// user's recent facts. Any failure degrades to storing content as new.
+func (uc *MemoryFactUseCase) judgeFact(ctx context.Context, content string, existing []domain.MemoryFact) (domain.MemoryFactAction, *domain.MemoryFact) {
+	if uc.generator == nil || len(existing) == 0 {
+		return domain.MemoryFactCreated, nil
+	}
+	candidates := existing[:min(len(existing), memoryFactJudgeCandidates)]
+
+	var sb strings.Builder
+	sb.WriteString(`You maintain a list of facts about a user. Decide how a NEW fact relates to the EXISTING ones.
+- "duplicate": an existing fact already says the same thing.
+- "replace": the new fact contradicts or updates an existing fact about the same subject (e.g. a changed address, host or preference).
+- "new": otherwise.
+
+Return only JSON: {"action":"duplicate|replace|new","id":"<existing fact id or empty>"}
+
+EXISTING:
+`)
+	for _, fact := range candidates {
+		fmt.Fprintf(&sb, "- %s: %s\n", fact.ID, fact.Content)
+	}
+	fmt.Fprintf(&sb, "\nNEW: %s\n", content)
+
+	raw, err := uc.generator.GenerateJSONFromPrompt(ctx, sb.String())
+	if err != nil {
+		slog.Warn("memory_fact_judge_failed", "error", err)
+		return domain.MemoryFactCreated, nil
+	}
+	var verdict factJudgement
+	if err := json.Unmarshal([]byte(raw), &verdict); err != nil {
+		start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+		if start < 0 || end <= start || json.Unmarshal([]byte(raw[start:end+1]), &verdict) != nil {
+			slog.Warn("memory_fact_judge_unparsable", "raw", raw)
+			return domain.MemoryFactCreated, nil
+		}
+	}
+
+	var action domain.MemoryFactAction
+	switch strings.ToLower(strings.TrimSpace(verdict.Action)) {
+	case "duplicate":
+		action = domain.MemoryFactDuplicate
+	case "replace":
+		action = domain.MemoryFactReplaced
+	default:
+		return domain.MemoryFactCreated, nil
+	}
+	id := strings.TrimSpace(verdict.ID)
+	for i := range candidates {
+		if candidates[i].ID == id {
+			return action, &candidates[i]
+		}
+	}
+	return domain.MemoryFactCreated, nil
+}
+
+func normalizeFactContent(content, op string) (string, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", domain.WrapError(domain.ErrInvalidInput, op, errors.New("content is required"))
+	}
+	if len([]rune(content)) > memoryFactMaxLen {
+		return "", domain.WrapError(domain.ErrInvalidInput, op,
+			fmt.Errorf("content is longer than %d characters", memoryFactMaxLen))
+	}
+	return content, nil
+}
+
+// factDedupKey folds case, whitespace and punctuation so trivially different
+// spellings of the same fact compare equal.
+func factDedupKey(content string) string {
+	fields := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+func requireMemoryFactRef(userID, id, op string) error {
+	if err := requireUserID(userID, op); err != nil {
+		return err
+	}
+	if strings.TrimSpace(id) == "" {
+		return domain.WrapError(domain.ErrInvalidInput, op, errors.New("fact id is required"))
+	}
+	return nil
+}
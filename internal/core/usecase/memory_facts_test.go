@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeMemoryFactStore struct {
+	facts []domain.MemoryFact
+}
+
+func (f *fakeMemoryFactStore) CreateFact(_ context.Context, fact *domain.MemoryFact) error {
+	f.facts = append([]domain.MemoryFact{*fact}, f.facts...)
+	return nil
+}
+
+func (f *fakeMemoryFactStore) UpdateFact(_ context.Context, fact *domain.MemoryFact) error {
+	for i := range f.facts {
+		if f.facts[i].ID == fact.ID && f.facts[i].UserID == fact.UserID {
+			f.facts[i] = *fact
+			return nil
+		}
+	}
+	return domain.WrapError(domain.ErrMemoryFactNotFound, "update memory fact", errors.New("id="+fact.ID))
+}
+
+func (f *fakeMemoryFactStore) GetFact(_ context.Context, userID, id string) (*domain.MemoryFact, error) {
+	for _, fact := range f.facts {
+		if fact.ID == id && fact.UserID == userID {
+			return &fact, nil
+		}
+	}
+	return nil, domain.WrapError(domain.ErrMemoryFactNotFound, "get memory fact", errors.New("id="+id))
+}
+
+func (f *fakeMemoryFactStore) ListFacts(_ context.Context, userID string, limit int) ([]domain.MemoryFact, error) {
+	out := make([]domain.MemoryFact, 0)
+	for _, fact := range f.facts {
+		if fact.UserID == userID && len(out) < limit {
+			out = append(out, fact)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeMemoryFactStore) DeleteFact(_ context.Context, userID, id string) error {
+	for i, fact := range f.facts {
+		if fact.ID == id && fact.UserID == userID {
+			f.facts = append(f.facts[:i], f.facts[i+1:]...)
+			return nil
+		}
+	}
+	return domain.WrapError(domain.ErrMemoryFactNotFound, "delete memory fact", errors.New("id="+id))
+}
+
+// factJudgeFake answers the contradiction check with a fixed verdict.
+type factJudgeFake struct {
+	queryGeneratorFake
+	verdict string
+	err     error
+	prompts []string
+}
+
+func (f *factJudgeFake) GenerateJSONFromPrompt(_ context.Context, prompt string) (string, error) {
+	f.prompts = append(f.prompts, prompt)
+	return f.verdict, f.err
+}
+
+func newFactFixture() *fakeMemoryFactStore {
+	return &fakeMemoryFactStore{facts: []domain.MemoryFact{
+		{ID: "f-db", UserID: "alice", Content: "My staging DB is db-stage-1", Source: domain.MemoryFactSourceUser},
+		{ID: "f-bob", UserID: "bob", Content: "Bob prefers tea"},
+	}}
+}
+
+func TestRememberSkipsExactDuplicateWithoutLLM(t *testing.T) {
+	store := newFactFixture()
+	judge := &factJudgeFake{verdict: `{"action":"new"}`}
+	uc := NewMemoryFactUseCase(store, judge)
+
+	res, err := uc.Remember(context.Background(), "alice", "  my staging db is db-stage-1. ", "")
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	if res.Action != domain.MemoryFactDuplicate || res.Fact.ID != "f-db" {
+		t.Fatalf("expected duplicate of f-db, got %+v", res)
+	}
+	if len(judge.prompts) != 0 {
+		t.Fatal("exact duplicates must not reach the LLM")
+	}
+	if len(store.facts) != 2 {
+		t.Fatalf("expected no new fact, got %d", len(store.facts))
+	}
+}
+
+func TestRememberReplacesContradictedFact(t *testing.T) {
+	store := newFactFixture()
+	judge := &factJudgeFake{verdict: "Sure: {\"action\":\"replace\",\"id\":\"f-db\"}"}
+	uc := NewMemoryFactUseCase(store, judge)
+
+	res, err := uc.Remember(context.Background(), "alice", "Staging DB moved to db-stage-2", domain.MemoryFactSourceAgent)
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	if res.Action != domain.MemoryFactReplaced || res.Previous != "My staging DB is db-stage-1" {
+		t.Fatalf("expected replacement of the old fact, got %+v", res)
+	}
+	fact, _ := store.GetFact(context.Background(), "alice", "f-db")
+	if fact.Content != "Staging DB moved to db-stage-2" || fact.Source != domain.MemoryFactSourceAgent {
+		t.Fatalf("fact not overwritten: %+v", fact)
+	}
+	if len(judge.prompts) != 1 || strings.Contains(judge.prompts[0], "Bob prefers tea") {
+		t.Fatal("judge must only see the caller's facts")
+	}
+}
+
+func TestRememberIgnoresVerdictForUnknownFact(t *testing.T) {
+	store := newFactFixture()
+	uc := NewMemoryFactUseCase(store, &factJudgeFake{verdict: `{"action":"replace","id":"f-bob"}`})
+
+	res, err := uc.Remember(context.Background(), "alice", "I like coffee", "")
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	if res.Action != domain.MemoryFactCreated {
+		t.Fatalf("expected a new fact, got %+v", res)
+	}
+	if bob, _ := store.GetFact(context.Background(), "bob", "f-bob"); bob.Content != "Bob prefers tea" {
+		t.Fatal("another user's fact must never be replaced")
+	}
+}
+
+func TestRememberStoresNewFactWhenJudgeFails(t *testing.T) {
+	store := newFactFixture()
+	uc := NewMemoryFactUseCase(store, &factJudgeFake{err: errors.New("llm down")})
+
+	res, err := uc.Remember(context.Background(), "alice", "I live in Berlin", "")
+	if err != nil {
+		t.Fatalf("Remember() error = %v", err)
+	}
+	if res.Action != domain.MemoryFactCreated || res.Fact.Source != domain.MemoryFactSourceUser {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMemoryFactValidation(t *testing.T) {
+	uc := NewMemoryFactUseCase(newFactFixture(), nil)
+	ctx := context.Background()
+
+	if _, err := uc.Remember(ctx, "alice", "   ", ""); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("empty content: error = %v", err)
+	}
+	if _, err := uc.Remember(ctx, "alice", strings.Repeat("x", memoryFactMaxLen+1), ""); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("long content: error = %v", err)
+	}
+	if _, err := uc.Update(ctx, "alice", "f-bob", "hijack"); !domain.IsKind(err, domain.ErrMemoryFactNotFound) {
+		t.Fatalf("foreign update: error = %v", err)
+	}
+	if err := uc.Forget(ctx, "alice", "f-bob"); !domain.IsKind(err, domain.ErrMemoryFactNotFound) {
+		t.Fatalf("foreign forget: error = %v", err)
+	}
+}
+
+func TestAgentMemoryToolAndPrompt(t *testing.T) {
+	store := newFactFixture()
+	facts := NewMemoryFactUseCase(store, nil)
+	uc := newTestAgentUC(&fakeAgentQueryService{}, func(u *AgentChatUseCase) { u.SetMemoryFacts(facts) })
+
+	tc := domain.ToolCall{Function: domain.ToolCallFunc{Name: "memory", Arguments: map[string]any{
+		"action": "remember", "content": "Deploys happen on Thursdays",
+	}}}
+	ev, err := uc.executeToolCall(context.Background(), "alice", tc, "")
+	if err != nil {
+		t.Fatalf("executeToolCall error: %v", err)
+	}
+	if ev.Status != "ok" || !strings.Contains(ev.Output, `"action":"created"`) {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	list, _ := facts.List(context.Background(), "alice")
+	prompt := buildSystemPrompt(context.Background(), IntentGeneral, nil, nil, list, nil, nil)
+	for _, want := range []string{"[f-db] My staging DB is db-stage-1", "Deploys happen on Thursdays"} {
+		if !strings.Contains(prompt, want) {
+			t.Fatalf("system prompt missing %q", want)
+		}
+	}
+
+	tc.Function.Arguments = map[string]any{"action": "forget", "id": "f-db"}
+	if _, err := uc.executeToolCall(context.Background(), "alice", tc, ""); err != nil {
+		t.Fatalf("forget error: %v", err)
+	}
+	if _, err := store.GetFact(context.Background(), "alice", "f-db"); err == nil {
+		t.Fatal("expected fact to be forgotten")
+	}
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// orchSubscriberBuffer is how many step events a subscriber may lag behind
+// before it is dropped; a dropped subscriber reconnects and replays.
+const orchSubscriberBuffer = 64
+
+// orchestrationHub fans out the step events of running orchestrations to
+// subscribers. It keeps every event of a running orchestration so late
+// subscribers start from the beginning.
+type orchestrationHub struct {
+	mu   sync.Mutex
+	runs map[string]*hubRun
+}
+
+type hubRun struct {
+	events []domain.OrchestrationStatus
+	subs   map[chan domain.OrchestrationStatus]struct{}
+}
+
+func newOrchestrationHub() *orchestrationHub {
+	return &orchestrationHub{runs: make(map[string]*hubRun)}
+}
+
+func (h *orchestrationHub) start(orchID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs[orchID] = &hubRun{subs: make(map[chan domain.OrchestrationStatus]struct{})}
+}
+
+func (h *orchestrationHub) publish(status domain.OrchestrationStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[status.OrchestrationID]
+	if !ok {
+		return
+	}
+	run.events = append(run.events, status)
+	for ch := range run.subs {
+		select {
+		case ch <- status:
+		default:
+			slog.Warn("orchestration_subscriber_dropped", "id", status.OrchestrationID)
+			delete(run.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// finish closes every subscription of the orchestration.
+func (h *orchestrationHub) finish(orchID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[orchID]
+	if !ok {
+		return
+	}
+	for ch := range run.subs {
+		delete(run.subs, ch)
+		close(ch)
+	}
+	delete(h.runs, orchID)
+}
+
+// subscribe returns false when the orchestration is not running in this
+// process.
+func (h *orchestrationHub) subscribe(orchID string) (*domain.OrchestrationSubscription, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	run, ok := h.runs[orchID]
+	if !ok {
+		return nil, false
+	}
+	ch := make(chan domain.OrchestrationStatus, orchSubscriberBuffer)
+	run.subs[ch] = struct{}{}
+	return &domain.OrchestrationSubscription{
+		Events:  slices.Clone(run.events),
+		Updates: ch,
+		Cancel: func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if _, ok := run.subs[ch]; ok {
+				delete(run.subs, ch)
+				close(ch)
+			}
+		},
+	}, true
+}
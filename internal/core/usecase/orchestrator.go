@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,6 +16,29 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 )
 
+const (
+	// orchSynthesisAgent writes the final answer when the planner did not
+	// mark a synthesis step.
+	orchSynthesisAgent = "writer"
+	// orchInputBudget caps, in runes, the dependency outputs passed to one
+	// step; each dependency gets an equal share but at least orchMinInput.
+	orchInputBudget = 12000
+	orchMinInput    = 2000
+	// orchMaxStepRetries caps the retries a plan step may ask for.
+	orchMaxStepRetries = 3
+)
+
+// OrchestratorOptions tunes plan execution.
+type OrchestratorOptions struct {
+	// MaxSteps caps the plan size, the synthesis step included.
+	MaxSteps int
+	// MaxParallel is how many independent steps run at once.
+	MaxParallel int
+	// StepRetries is how often a failed step is retried before its
+	// failure policy applies, unless the step sets its own retries.
+	StepRetries int
+}
+
 type OrchestratorUseCase struct {
 	agentChat    ports.AgentChatService
 	registry     *AgentRegistry
@@ -21,7 +46,8 @@ type OrchestratorUseCase struct {
 	embedder     ports.Embedder
 	generator    ports.AnswerGenerator
 	orchStore    ports.OrchestrationStore
-	maxSteps     int
+	opts         OrchestratorOptions
+	hub          *orchestrationHub
 }
 
 func NewOrchestratorUseCase(
@@ -31,10 +57,16 @@ func NewOrchestratorUseCase(
 	embedder ports.Embedder,
 	generator ports.AnswerGenerator,
 	orchStore ports.OrchestrationStore,
-	maxSteps int,
+	opts OrchestratorOptions,
 ) *OrchestratorUseCase {
-	if maxSteps <= 0 {
-		maxSteps = 8
+	if opts.MaxSteps <= 0 {
+		opts.MaxSteps = 8
+	}
+	if opts.MaxParallel <= 0 {
+		opts.MaxParallel = 3
+	}
+	if opts.StepRetries < 0 {
+		opts.StepRetries = 0
 	}
 	return &OrchestratorUseCase{
 		agentChat:    agentChat,
@@ -43,10 +75,28 @@ func NewOrchestratorUseCase(
 		embedder:     embedder,
 		generator:    generator,
 		orchStore:    orchStore,
-		maxSteps:     maxSteps,
+		opts:         opts,
+		hub:          newOrchestrationHub(),
 	}
 }
 
+type skipOrchestrationKey struct{}
+
+// withoutOrchestration marks a context whose agent run must not be handed
+// back to the orchestrator, so falling back to a single agent cannot loop.
+func withoutOrchestration(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipOrchestrationKey{}, true)
+}
+
+func orchestrationSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipOrchestrationKey{}).(bool)
+	return skip
+}
+
+// Execute plans the request as a dependency graph of specialist steps, runs
+// independent steps concurrently and answers with the synthesis step's
+// output. Without a usable plan or synthesis it falls back to a single
+// agent run.
 func (uc *OrchestratorUseCase) Execute(
 	ctx context.Context,
 	req domain.AgentChatRequest,
@@ -58,9 +108,12 @@ func (uc *OrchestratorUseCase) Execute(
 	lastMessage := orchLastUserMsg(req.Messages)
 
 	plan, err := uc.planSteps(ctx, lastMessage)
+	if err == nil {
+		plan, err = uc.preparePlan(plan)
+	}
 	if err != nil {
 		slog.Warn("orchestrator_plan_failed", "error", err)
-		return uc.agentChat.Complete(ctx, req, onToolStatus)
+		return uc.agentChat.Complete(withoutOrchestration(ctx), req, onToolStatus)
 	}
 
 	orch := &domain.Orchestration{
@@ -72,136 +125,151 @@ func (uc *OrchestratorUseCase) Execute(
 		Status:         "running",
 		CreatedAt:      now,
 	}
+	uc.hub.start(orchID)
 	if uc.orchStore != nil {
 		_ = uc.orchStore.Create(ctx, orch)
 	}
 
-	var lastResult string
-	stepIndex := 0
-
-	for stepIndex < len(plan) && stepIndex < uc.maxSteps {
-		step := plan[stepIndex]
-		spec, ok := uc.registry.Get(step.Agent)
-		if !ok {
-			slog.Warn("orchestrator_unknown_agent", "agent", step.Agent)
-			stepIndex++
-			continue
-		}
-
-		if onToolStatus != nil {
-			onToolStatus(fmt.Sprintf("orchestrator:%s", step.Agent), "started")
-		}
-
-		if onOrchStep != nil {
-			onOrchStep(domain.OrchestrationStatus{
-				OrchestrationID: orchID,
-				StepIndex:       stepIndex,
-				AgentName:       step.Agent,
-				Task:            step.Task,
-				Status:          "started",
-			})
-		}
-
-		startTime := time.Now()
-
-		memoryContext := uc.gatherMemoryContext(ctx, req.UserID, req.ConversationID, step.Task)
-
-		agentPrompt := fmt.Sprintf("%s\n\nTask: %s", spec.SystemPrompt, step.Task)
-		if memoryContext != "" {
-			agentPrompt += fmt.Sprintf("\n\nContext from previous steps:\n%s", memoryContext)
-		}
-		if lastResult != "" {
-			agentPrompt += fmt.Sprintf("\n\nPrevious agent result:\n%s", orchTruncate(lastResult, 2000))
-		}
-
-		agentReq := domain.AgentChatRequest{
-			UserID:         req.UserID,
-			ConversationID: req.ConversationID + "_orch_" + orchID,
-			Messages: []domain.AgentInputMessage{
-				{Role: "system", Content: agentPrompt},
-				{Role: "user", Content: lastMessage},
-			},
-		}
+	run := &orchRun{
+		uc:           uc,
+		req:          req,
+		orchID:       orchID,
+		userMessage:  lastMessage,
+		plan:         plan,
+		slots:        make(chan struct{}, uc.opts.MaxParallel),
+		results:      make(map[string]domain.OrchestrationStep, len(plan)),
+		onToolStatus: onToolStatus,
+		onOrchStep:   onOrchStep,
+	}
+	run.execute(ctx)
 
-		result, err := uc.agentChat.Complete(ctx, agentReq, nil)
+	final := run.results[plan[len(plan)-1].ID]
+	status := "completed"
+	if final.Status != "completed" {
+		status = "failed"
+	}
+	if uc.orchStore != nil {
+		_ = uc.orchStore.Complete(context.WithoutCancel(ctx), orchID, status)
+	}
+	uc.hub.finish(orchID)
+	slog.Info("orchestration_completed", "id", orchID, "steps", len(plan), "status", status)
 
-		duration := float64(time.Since(startTime).Microseconds()) / 1000.0
-		orchStep := domain.OrchestrationStep{
-			Index:      stepIndex,
-			Agent:      step.Agent,
-			Task:       step.Task,
-			Status:     "completed",
-			StartedAt:  startTime,
-			DurationMS: duration,
+	if status != "completed" {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
-
-		if err != nil {
-			orchStep.Status = "failed"
-			orchStep.Result = err.Error()
-			slog.Warn("orchestrator_step_failed", "agent", step.Agent, "error", err)
-		} else {
-			orchStep.Result = result.Answer
-			lastResult = result.Answer
-			uc.saveToMemory(ctx, req.UserID, req.ConversationID, step.Agent, result.Answer)
+		slog.Warn("orchestrator_synthesis_failed", "id", orchID, "status", final.Status)
+		return uc.agentChat.Complete(withoutOrchestration(ctx), req, onToolStatus)
+	}
+	uc.saveToMemory(ctx, req.UserID, req.ConversationID, final.Agent, final.Result)
+	steps := make([]domain.OrchestrationStep, 0, len(plan))
+	toolsInvoked := make([]string, 0, len(run.toolEvents))
+	for _, step := range plan {
+		steps = append(steps, run.results[step.ID])
+	}
+	for _, event := range run.toolEvents {
+		if !slices.Contains(toolsInvoked, event.Tool) {
+			toolsInvoked = append(toolsInvoked, event.Tool)
 		}
+	}
+	return &domain.AgentRunResult{
+		ConversationID:     req.ConversationID,
+		Answer:             final.Result,
+		Iterations:         run.iterations,
+		ToolsInvoked:       toolsInvoked,
+		ToolEvents:         run.toolEvents,
+		OrchestrationID:    orchID,
+		OrchestrationSteps: steps,
+	}, nil
+}
 
-		if uc.orchStore != nil {
-			_ = uc.orchStore.AddStep(ctx, orchID, orchStep)
-		}
+// List returns the user's most recent orchestrations, only those of one
+// conversation when conversationID is set.
+func (uc *OrchestratorUseCase) List(ctx context.Context, userID, conversationID string, limit int) ([]domain.Orchestration, error) {
+	if uc.orchStore == nil {
+		return []domain.Orchestration{}, nil
+	}
+	var (
+		orchs []domain.Orchestration
+		err   error
+	)
+	if conversationID != "" {
+		orchs, err = uc.orchStore.ListByConversation(ctx, userID, conversationID, limit)
+	} else {
+		orchs, err = uc.orchStore.ListByUser(ctx, userID, limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list orchestrations: %w", err)
+	}
+	if orchs == nil {
+		orchs = []domain.Orchestration{}
+	}
+	return orchs, nil
+}
 
-		if onOrchStep != nil {
-			onOrchStep(domain.OrchestrationStatus{
-				OrchestrationID: orchID,
-				StepIndex:       stepIndex,
-				AgentName:       step.Agent,
-				Task:            step.Task,
-				Status:          orchStep.Status,
-				Result:          orchStep.Result,
-			})
-		}
+// Get returns one of the user's orchestrations with its plan and the steps
+// finished so far.
+func (uc *OrchestratorUseCase) Get(ctx context.Context, userID, orchID string) (*domain.Orchestration, error) {
+	if uc.orchStore == nil {
+		return nil, domain.WrapError(domain.ErrOrchestrationNotFound, "get orchestration", fmt.Errorf("id=%s", orchID))
+	}
+	orch, err := uc.orchStore.GetByID(ctx, orchID)
+	if err != nil {
+		return nil, err
+	}
+	if orch.UserID != userID {
+		return nil, domain.WrapError(domain.ErrOrchestrationNotFound, "get orchestration", fmt.Errorf("id=%s", orchID))
+	}
+	return orch, nil
+}
 
-		if onToolStatus != nil {
-			status := "completed"
-			if orchStep.Status == "failed" {
-				status = "failed"
-			}
-			onToolStatus(fmt.Sprintf("orchestrator:%s", step.Agent), status)
+// Subscribe follows one of the user's orchestrations. A running
+// orchestration replays the events published so far and streams the rest;
+// a finished one, or one running in another process, only replays its
+// stored steps.
+func (uc *OrchestratorUseCase) Subscribe(ctx context.Context, userID, orchID string) (*domain.OrchestrationSubscription, error) {
+	orch, err := uc.Get(ctx, userID, orchID)
+	if err != nil {
+		return nil, err
+	}
+	if orch.Status == "running" {
+		if sub, ok := uc.hub.subscribe(orchID); ok {
+			return sub, nil
 		}
-
-		if step.Agent == "critic" && orchStep.Status == "completed" {
-			if orchContainsCriticIssues(orchStep.Result) && stepIndex+2 < uc.maxSteps {
-				plan = append(plan,
-					domain.OrchestrationPlanStep{Agent: "writer", Task: "Fix issues found by critic: " + orchTruncate(orchStep.Result, 500)},
-					domain.OrchestrationPlanStep{Agent: "critic", Task: "Re-check the fixed answer"},
-				)
-			}
+		// It may have finished in between; reload the stored steps.
+		if orch, err = uc.Get(ctx, userID, orchID); err != nil {
+			return nil, err
 		}
-
-		stepIndex++
 	}
-
-	if uc.orchStore != nil {
-		_ = uc.orchStore.Complete(ctx, orchID, "completed")
+	events := make([]domain.OrchestrationStatus, 0, len(orch.Steps))
+	for _, step := range orch.Steps {
+		events = append(events, domain.OrchestrationStatus{
+			OrchestrationID: orch.ID,
+			StepIndex:       step.Index,
+			StepID:          step.StepID,
+			AgentName:       step.Agent,
+			Task:            step.Task,
+			Status:          step.Status,
+			Result:          step.Result,
+			DurationMS:      step.DurationMS,
+		})
 	}
-
-	slog.Info("orchestration_completed", "id", orchID, "steps", stepIndex)
-
-	return &domain.AgentRunResult{
-		Answer:     lastResult,
-		ToolEvents: nil,
-	}, nil
+	return &domain.OrchestrationSubscription{Events: events, Cancel: func() {}}, nil
 }
 
 func (uc *OrchestratorUseCase) planSteps(ctx context.Context, userMessage string) ([]domain.OrchestrationPlanStep, error) {
 	agentNames := uc.registry.Names()
-	prompt := fmt.Sprintf(`You are an orchestrator. Given the user request, decide which specialist agents to call and in what order.
+	prompt := fmt.Sprintf(`You are an orchestrator. Given the user request, decide which specialist agents to call and how their work depends on each other.
 
 Available specialists: %s
 
-Return ONLY a JSON object: {"steps": [{"agent": "<name>", "task": "<specific task for this agent>"}]}
+Return ONLY a JSON object:
+{"steps": [{"id": "<short id>", "agent": "<name>", "task": "<specific task for this agent>", "depends_on": ["<ids of steps whose output this task needs>"], "on_failure": "skip|abort", "retries": <0-%d, optional>}]}
+
+Steps without dependencies run in parallel, so split independent research into separate steps. Use "abort" only when the remaining work is pointless without the step. Set "retries" only for steps that are worth retrying more or less often than usual. The last step must set "synthesis": true and depend on every step whose output belongs in the answer; it writes the final answer to the user. Use at most %d steps.
 Do not include any explanation, only the JSON.
 
-User request: %s`, strings.Join(agentNames, ", "), userMessage)
+User request: %s`, strings.Join(agentNames, ", "), orchMaxStepRetries, uc.opts.MaxSteps, userMessage)
 
 	respText, err := uc.generator.GenerateJSONFromPrompt(ctx, prompt)
 	if err != nil {
@@ -230,22 +298,315 @@ User request: %s`, strings.Join(agentNames, ", "), userMessage)
 	return plan.Steps, nil
 }
 
-func (uc *OrchestratorUseCase) gatherMemoryContext(ctx context.Context, userID, conversationID, task string) string {
-	vec, err := uc.embedder.EmbedQuery(ctx, task)
+// preparePlan validates the planner's dependency graph and returns it in
+// execution order with the synthesis step last. Steps without an id get
+// "s<n>". Every step nothing else depends on feeds the synthesis step; a plan
+// without one gets one run by the writer.
+func (uc *OrchestratorUseCase) preparePlan(steps []domain.OrchestrationPlanStep) ([]domain.OrchestrationPlanStep, error) {
+	steps = slices.Clone(steps)
+	ids := make(map[string]bool, len(steps))
+	synthesis := -1
+	for i := range steps {
+		step := &steps[i]
+		step.ID = strings.TrimSpace(step.ID)
+		if step.ID == "" {
+			step.ID = fmt.Sprintf("s%d", i+1)
+		}
+		if ids[step.ID] {
+			return nil, fmt.Errorf("duplicate step id %q", step.ID)
+		}
+		ids[step.ID] = true
+		if _, ok := uc.registry.Get(step.Agent); !ok {
+			return nil, fmt.Errorf("step %s: unknown agent %q", step.ID, step.Agent)
+		}
+		switch step.OnFailure {
+		case "":
+			step.OnFailure = domain.OrchStepFailureSkip
+		case domain.OrchStepFailureSkip, domain.OrchStepFailureAbort:
+		default:
+			return nil, fmt.Errorf("step %s: unknown failure policy %q", step.ID, step.OnFailure)
+		}
+		if step.Retries != nil && (*step.Retries < 0 || *step.Retries > orchMaxStepRetries) {
+			return nil, fmt.Errorf("step %s: retries must be between 0 and %d", step.ID, orchMaxStepRetries)
+		}
+		if step.Synthesis {
+			if synthesis >= 0 {
+				return nil, fmt.Errorf("plan has more than one synthesis step")
+			}
+			synthesis = i
+		}
+	}
+
+	consumed := make(map[string]bool)
+	for _, step := range steps {
+		for _, dep := range step.DependsOn {
+			if !ids[dep] || dep == step.ID {
+				return nil, fmt.Errorf("step %s: invalid dependency %q", step.ID, dep)
+			}
+			consumed[dep] = true
+		}
+	}
+
+	// Outputs nothing depends on belong in the answer, so the synthesis
+	// step consumes them whether the planner wrote it or not.
+	var unconsumed []string
+	for _, step := range steps {
+		if !consumed[step.ID] && !step.Synthesis {
+			unconsumed = append(unconsumed, step.ID)
+		}
+	}
+	if synthesis >= 0 {
+		final := &steps[synthesis]
+		if consumed[final.ID] {
+			return nil, fmt.Errorf("synthesis step %s cannot be a dependency", final.ID)
+		}
+		final.DependsOn = append(slices.Clone(final.DependsOn), unconsumed...)
+	} else {
+		if _, ok := uc.registry.Get(orchSynthesisAgent); !ok {
+			return nil, fmt.Errorf("plan has no synthesis step and no %s agent", orchSynthesisAgent)
+		}
+		id := "synthesis"
+		for n := 2; ids[id]; n++ {
+			id = fmt.Sprintf("synthesis%d", n)
+		}
+		steps = append(steps, domain.OrchestrationPlanStep{
+			ID:        id,
+			Agent:     orchSynthesisAgent,
+			Task:      "Write the final answer to the user's request from the outputs of the previous steps.",
+			DependsOn: unconsumed,
+			OnFailure: domain.OrchStepFailureSkip,
+			Synthesis: true,
+		})
+	}
+	if len(steps) > uc.opts.MaxSteps {
+		return nil, fmt.Errorf("plan has %d steps, limit is %d", len(steps), uc.opts.MaxSteps)
+	}
+
+	// Kahn's algorithm: order steps so dependencies come first and reject
+	// cycles. The synthesis step is kept last.
+	ordered := make([]domain.OrchestrationPlanStep, 0, len(steps))
+	placed := make(map[string]bool, len(steps))
+	for len(ordered) < len(steps) {
+		progressed := false
+		for _, step := range steps {
+			if placed[step.ID] || (step.Synthesis && len(ordered) < len(steps)-1) {
+				continue
+			}
+			ready := true
+			for _, dep := range step.DependsOn {
+				if !placed[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, step)
+				placed[step.ID] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, fmt.Errorf("plan dependencies contain a cycle")
+		}
+	}
+	return ordered, nil
+}
+
+// orchRun executes one plan. Every step waits in its own goroutine until its
+// dependencies have finished and a slot is free.
+type orchRun struct {
+	uc           *OrchestratorUseCase
+	req          domain.AgentChatRequest
+	orchID       string
+	userMessage  string
+	plan         []domain.OrchestrationPlanStep
+	slots        chan struct{}
+	abort        context.CancelFunc
+	onToolStatus domain.ToolStatusCallback
+	onOrchStep   domain.OrchStepCallback
+
+	mu         sync.Mutex // guards the fields below and serializes callbacks
+	results    map[string]domain.OrchestrationStep
+	toolEvents []domain.AgentToolEvent
+	iterations int
+}
+
+func (r *orchRun) execute(ctx context.Context) {
+	runCtx, abort := context.WithCancel(ctx)
+	defer abort()
+	r.abort = abort
+
+	done := make(map[string]chan struct{}, len(r.plan))
+	for _, step := range r.plan {
+		done[step.ID] = make(chan struct{})
+	}
+	var wg sync.WaitGroup
+	for index, step := range r.plan {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[step.ID])
+			for _, dep := range step.DependsOn {
+				select {
+				case <-done[dep]:
+				case <-runCtx.Done():
+				}
+			}
+			r.runStep(runCtx, index, step)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *orchRun) runStep(ctx context.Context, index int, step domain.OrchestrationPlanStep) {
+	skipped := domain.OrchestrationStep{
+		Index:     index,
+		StepID:    step.ID,
+		Agent:     step.Agent,
+		Task:      step.Task,
+		Status:    "skipped",
+		Result:    "orchestration aborted",
+		StartedAt: time.Now(),
+	}
+	if ctx.Err() != nil {
+		r.finish(ctx, skipped)
+		return
+	}
+	select {
+	case r.slots <- struct{}{}:
+		defer func() { <-r.slots }()
+	case <-ctx.Done():
+		r.finish(ctx, skipped)
+		return
+	}
+
+	r.notify(step, index, "started", "", 0)
+	startTime := time.Now()
+
+	spec, _ := r.uc.registry.Get(step.Agent)
+	agentReq := domain.AgentChatRequest{
+		UserID:         r.req.UserID,
+		ConversationID: domain.StepConversationID(r.req.ConversationID, r.orchID, step.ID),
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: r.userMessage}},
+		Profile:        &spec,
+		TaskContext:    r.stepContext(step),
+	}
+
+	retries := r.uc.opts.StepRetries
+	if step.Retries != nil {
+		retries = *step.Retries
+	}
+	var result *domain.AgentRunResult
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		result, err = r.uc.agentChat.Complete(ctx, agentReq, nil)
+		if err == nil || attempts > retries || ctx.Err() != nil {
+			break
+		}
+		slog.Warn("orchestrator_step_retry", "agent", step.Agent, "step_id", step.ID, "attempt", attempts, "error", err)
+	}
+
+	orchStep := domain.OrchestrationStep{
+		Index:      index,
+		StepID:     step.ID,
+		Agent:      step.Agent,
+		Task:       step.Task,
+		Status:     "completed",
+		Attempts:   attempts,
+		StartedAt:  startTime,
+		DurationMS: float64(time.Since(startTime).Microseconds()) / 1000.0,
+	}
 	if err != nil {
-		return ""
+		orchStep.Status = "failed"
+		orchStep.Result = err.Error()
+		slog.Warn("orchestrator_step_failed", "agent", step.Agent, "step_id", step.ID, "policy", step.OnFailure, "error", err)
+		if step.OnFailure == domain.OrchStepFailureAbort {
+			r.abort()
+		}
+	} else {
+		orchStep.Result = result.Answer
+		orchStep.RunID = result.RunID
+		r.mu.Lock()
+		r.toolEvents = append(r.toolEvents, result.ToolEvents...)
+		r.iterations += result.Iterations
+		r.mu.Unlock()
+	}
+	r.finish(ctx, orchStep)
+}
+
+// stepContext builds the task context of a step: the task and the outputs
+// of the steps it depends on, nothing else. The specialist's own prompt
+// comes from its profile.
+func (r *orchRun) stepContext(step domain.OrchestrationPlanStep) string {
+	prompt := "Task: " + step.Task
+	if len(step.DependsOn) == 0 {
+		return prompt
+	}
+
+	limit := max(orchInputBudget/len(step.DependsOn), orchMinInput)
+	var sb strings.Builder
+	r.mu.Lock()
+	for _, dep := range step.DependsOn {
+		res := r.results[dep]
+		switch res.Status {
+		case "completed":
+			fmt.Fprintf(&sb, "\n\n### Step %s (%s)\n%s", dep, res.Agent, orchTruncate(res.Result, limit))
+			if res.Agent == "critic" && orchContainsCriticIssues(res.Result) {
+				sb.WriteString("\n(The critic found issues above; fix them in your output.)")
+			}
+		default:
+			fmt.Fprintf(&sb, "\n\n### Step %s (%s)\nNo output: the step %s.", dep, res.Agent, res.Status)
+		}
+	}
+	r.mu.Unlock()
+	return prompt + "\n\nOutputs of the steps this task depends on:" + sb.String()
+}
+
+// finish records a step and reports it to the store and the callbacks.
+func (r *orchRun) finish(ctx context.Context, step domain.OrchestrationStep) {
+	r.mu.Lock()
+	r.results[step.StepID] = step
+	r.mu.Unlock()
+
+	if r.uc.orchStore != nil {
+		_ = r.uc.orchStore.AddStep(context.WithoutCancel(ctx), r.orchID, step)
+	}
+	r.notify(r.plan[step.Index], step.Index, step.Status, step.Result, step.DurationMS)
+}
+
+// notify reports a step event to the callbacks and to subscribers of the
+// orchestration.
+func (r *orchRun) notify(step domain.OrchestrationPlanStep, index int, status, result string, durationMS float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.onToolStatus != nil {
+		toolStatus := status
+		if toolStatus == "skipped" {
+			toolStatus = "failed"
+		}
+		r.onToolStatus(fmt.Sprintf("orchestrator:%s", step.Agent), toolStatus)
 	}
-	hits, err := uc.memoryVector.SearchSummaries(ctx, userID, conversationID, vec, 3)
-	if err != nil || len(hits) == 0 {
-		return ""
+	event := domain.OrchestrationStatus{
+		OrchestrationID: r.orchID,
+		StepIndex:       index,
+		StepID:          step.ID,
+		AgentName:       step.Agent,
+		Task:            step.Task,
+		Status:          status,
+		Result:          result,
+		DurationMS:      durationMS,
 	}
-	var parts []string
-	for _, h := range hits {
-		parts = append(parts, h.Summary.Summary)
+	r.uc.hub.publish(event)
+	if r.onOrchStep != nil {
+		r.onOrchStep(event)
 	}
-	return strings.Join(parts, "\n\n---\n\n")
 }
 
+// saveToMemory indexes the final synthesis in the memory of the
+// conversation the orchestration was started from; step outputs stay in
+// their step conversations.
 func (uc *OrchestratorUseCase) saveToMemory(ctx context.Context, userID, conversationID, agentName, result string) {
 	summary := domain.MemorySummary{
 		ID:             uuid.NewString(),
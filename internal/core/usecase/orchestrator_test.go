@@ -1,9 +1,17 @@
 package usecase
 
 import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 )
 
 func TestOrchContainsCriticIssues(t *testing.T) {
@@ -46,3 +54,563 @@ func TestOrchLastUserMsg(t *testing.T) {
 		t.Errorf("orchLastUserMsg = %q, want %q", got, "second question")
 	}
 }
+
+type orchPlannerFake struct {
+	*queryGeneratorFake
+	plan string
+}
+
+func (f *orchPlannerFake) GenerateJSONFromPrompt(context.Context, string) (string, error) {
+	return f.plan, nil
+}
+
+// orchAgentFake answers each specialist run with a hook keyed by the task
+// line of its task context.
+type orchAgentFake struct {
+	mu        sync.Mutex
+	prompts   map[string]string
+	fallbacks int
+	hook      func(ctx context.Context, task string) (string, error)
+}
+
+func (f *orchAgentFake) Complete(ctx context.Context, req domain.AgentChatRequest, _ domain.ToolStatusCallback) (*domain.AgentRunResult, error) {
+	if req.Profile == nil {
+		f.mu.Lock()
+		f.fallbacks++
+		f.mu.Unlock()
+		if !orchestrationSkipped(ctx) {
+			return nil, errors.New("fallback run may orchestrate again")
+		}
+		return &domain.AgentRunResult{Answer: "single agent answer"}, nil
+	}
+	task, _, _ := strings.Cut(strings.TrimPrefix(req.TaskContext, "Task: "), "\n")
+	f.mu.Lock()
+	f.prompts[task] = req.TaskContext
+	f.mu.Unlock()
+	answer, err := f.hook(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AgentRunResult{Answer: answer}, nil
+}
+
+func (f *orchAgentFake) SetObsidianWriter(ports.ObsidianNoteWriter) {}
+
+func (f *orchAgentFake) SetObsidianVaults([]ports.AgentVaultInfo) {}
+
+// orchMemoryFake records the memory writes of an orchestration.
+type orchMemoryFake struct {
+	mu sync.Mutex
+	fakeMemoryVectorStore
+}
+
+func (f *orchMemoryFake) IndexSummary(ctx context.Context, summary domain.MemorySummary, vector []float32) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.fakeMemoryVectorStore.IndexSummary(ctx, summary, vector)
+}
+
+func newTestOrchestrator(agent *orchAgentFake, plan string, opts OrchestratorOptions) *OrchestratorUseCase {
+	agent.prompts = make(map[string]string)
+	return NewOrchestratorUseCase(
+		agent,
+		NewAgentRegistry(nil),
+		&orchMemoryFake{},
+		&fakeAgentEmbedder{},
+		&orchPlannerFake{queryGeneratorFake: &queryGeneratorFake{}, plan: plan},
+		nil,
+		opts,
+	)
+}
+
+func TestPreparePlan(t *testing.T) {
+	uc := newTestOrchestrator(&orchAgentFake{}, "", OrchestratorOptions{MaxSteps: 5})
+
+	plan, err := uc.preparePlan([]domain.OrchestrationPlanStep{
+		{ID: "review", Agent: "critic", Task: "check", DependsOn: []string{"s2", "s3"}},
+		{Agent: "researcher", Task: "a"},
+		{Agent: "researcher", Task: "b"},
+	})
+	if err != nil {
+		t.Fatalf("preparePlan() error = %v", err)
+	}
+	var order []string
+	for _, step := range plan {
+		order = append(order, step.ID)
+	}
+	if strings.Join(order, ",") != "s2,s3,review,synthesis" {
+		t.Fatalf("unexpected order %v", order)
+	}
+	final := plan[len(plan)-1]
+	if !final.Synthesis || final.Agent != "writer" || strings.Join(final.DependsOn, ",") != "review" {
+		t.Fatalf("expected a writer synthesis over the sink step, got %+v", final)
+	}
+	if plan[0].OnFailure != domain.OrchStepFailureSkip {
+		t.Fatalf("expected skip as the default failure policy, got %q", plan[0].OnFailure)
+	}
+
+	plan, err = uc.preparePlan([]domain.OrchestrationPlanStep{
+		{ID: "a", Agent: "researcher", Task: "a"},
+		{ID: "b", Agent: "researcher", Task: "b"},
+		{ID: "final", Agent: "writer", Task: "write", DependsOn: []string{"a"}, Synthesis: true},
+	})
+	if err != nil {
+		t.Fatalf("preparePlan() error = %v", err)
+	}
+	if final := plan[len(plan)-1]; final.ID != "final" || strings.Join(final.DependsOn, ",") != "a,b" {
+		t.Fatalf("expected the planner's synthesis to consume every sink step, got %+v", final)
+	}
+
+	negative, tooMany := -1, orchMaxStepRetries+1
+	invalid := map[string][]domain.OrchestrationPlanStep{
+		"cycle": {
+			{ID: "a", Agent: "researcher", DependsOn: []string{"b"}},
+			{ID: "b", Agent: "researcher", DependsOn: []string{"a"}},
+		},
+		"unknown dependency": {{ID: "a", Agent: "researcher", DependsOn: []string{"x"}}},
+		"unknown agent":      {{ID: "a", Agent: "poet"}},
+		"unknown policy":     {{ID: "a", Agent: "researcher", OnFailure: "retry-forever"}},
+		"negative retries":   {{ID: "a", Agent: "researcher", Retries: &negative}},
+		"too many retries":   {{ID: "a", Agent: "researcher", Retries: &tooMany}},
+		"depends on synthesis": {
+			{ID: "a", Agent: "writer", Synthesis: true},
+			{ID: "b", Agent: "critic", DependsOn: []string{"a"}},
+		},
+		"too many steps": {
+			{Agent: "researcher"}, {Agent: "researcher"}, {Agent: "researcher"},
+			{Agent: "researcher"}, {Agent: "researcher"},
+		},
+	}
+	for name, steps := range invalid {
+		if _, err := uc.preparePlan(steps); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestOrchestratorRunsIndependentStepsConcurrently(t *testing.T) {
+	started := make(chan struct{}, 2)
+	agent := &orchAgentFake{hook: func(ctx context.Context, task string) (string, error) {
+		if task == "synthesize" {
+			return "final answer", nil
+		}
+		// Both research steps must be in flight at the same time.
+		started <- struct{}{}
+		deadline := time.After(2 * time.Second)
+		for len(started) < 2 {
+			select {
+			case <-deadline:
+				return "", errors.New("research steps ran sequentially")
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return "finding for " + task, nil
+	}}
+	plan := `{"steps":[
+		{"id":"go","agent":"researcher","task":"research go"},
+		{"id":"rust","agent":"researcher","task":"research rust"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["go","rust"],"synthesis":true}
+	]}`
+	uc := newTestOrchestrator(agent, plan, OrchestratorOptions{MaxParallel: 2})
+
+	var statuses []domain.OrchestrationStatus
+	result, err := uc.Execute(context.Background(), domain.AgentChatRequest{
+		UserID:   "u-1",
+		Messages: []domain.AgentInputMessage{{Role: "user", Content: "compare go and rust"}},
+	}, nil, func(status domain.OrchestrationStatus) { statuses = append(statuses, status) })
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Answer != "final answer" {
+		t.Fatalf("expected the synthesis output, got %q", result.Answer)
+	}
+	synthesis := agent.prompts["synthesize"]
+	if !strings.Contains(synthesis, "finding for research go") || !strings.Contains(synthesis, "finding for research rust") {
+		t.Fatalf("synthesis must receive both research outputs, got:\n%s", synthesis)
+	}
+	if strings.Contains(agent.prompts["research go"], "Outputs of the steps") {
+		t.Fatal("independent steps must not receive other outputs")
+	}
+	if len(statuses) != 6 || statuses[5].StepID != "final" || statuses[5].Status != "completed" {
+		t.Fatalf("unexpected step statuses: %+v", statuses)
+	}
+	if result.OrchestrationID != statuses[0].OrchestrationID || len(result.OrchestrationSteps) != 3 ||
+		result.OrchestrationSteps[2].StepID != "final" || result.OrchestrationSteps[2].Status != "completed" {
+		t.Fatalf("result must carry the orchestration and its steps in plan order, got %q %+v", result.OrchestrationID, result.OrchestrationSteps)
+	}
+}
+
+func TestOrchestratorFailurePolicies(t *testing.T) {
+	var attempts atomic.Int32
+	agent := &orchAgentFake{hook: func(_ context.Context, task string) (string, error) {
+		switch task {
+		case "flaky":
+			if attempts.Add(1) == 1 {
+				return "", errors.New("model timeout")
+			}
+			return "flaky result", nil
+		case "broken":
+			return "", errors.New("tool crashed")
+		}
+		return "answer for " + task, nil
+	}}
+
+	skipPlan := `{"steps":[
+		{"id":"a","agent":"researcher","task":"flaky"},
+		{"id":"b","agent":"researcher","task":"broken"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["a","b"],"synthesis":true}
+	]}`
+	uc := newTestOrchestrator(agent, skipPlan, OrchestratorOptions{StepRetries: 1})
+	req := domain.AgentChatRequest{UserID: "u-1", Messages: []domain.AgentInputMessage{{Role: "user", Content: "q"}}}
+	result, err := uc.Execute(context.Background(), req, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Answer != "answer for synthesize" || attempts.Load() != 2 {
+		t.Fatalf("expected a retried step and a synthesis, got %q after %d attempts", result.Answer, attempts.Load())
+	}
+	if prompt := agent.prompts["synthesize"]; !strings.Contains(prompt, "flaky result") || !strings.Contains(prompt, "No output: the step failed") {
+		t.Fatalf("synthesis must see the retried output and the skipped failure, got:\n%s", prompt)
+	}
+
+	attempts.Store(0)
+	retryPlan := `{"steps":[
+		{"id":"a","agent":"researcher","task":"flaky","retries":1},
+		{"id":"b","agent":"researcher","task":"broken","retries":2},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["a","b"],"synthesis":true}
+	]}`
+	uc = newTestOrchestrator(agent, retryPlan, OrchestratorOptions{StepRetries: 0})
+	result, err = uc.Execute(context.Background(), req, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if attempts.Load() != 2 || !strings.Contains(agent.prompts["synthesize"], "flaky result") {
+		t.Fatalf("expected the step's own retries to override the default, got %d attempts", attempts.Load())
+	}
+	if broken := result.OrchestrationSteps[1]; broken.StepID != "b" || broken.Attempts != 3 {
+		t.Fatalf("expected 3 attempts for a step with 2 retries, got %+v", broken)
+	}
+
+	abortPlan := `{"steps":[
+		{"id":"b","agent":"researcher","task":"broken","on_failure":"abort"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["b"],"synthesis":true}
+	]}`
+	uc = newTestOrchestrator(agent, abortPlan, OrchestratorOptions{})
+	result, err = uc.Execute(context.Background(), req, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Answer != "single agent answer" || agent.fallbacks != 1 {
+		t.Fatalf("an aborted plan must fall back to a single agent, got %q", result.Answer)
+	}
+	if _, ran := agent.prompts["synthesize"]; ran {
+		t.Fatal("synthesis must not run after an abort")
+	}
+}
+
+// orchStoreFake keeps orchestrations in memory.
+type orchStoreFake struct {
+	mu    sync.Mutex
+	orchs map[string]domain.Orchestration
+}
+
+func (f *orchStoreFake) Create(_ context.Context, orch *domain.Orchestration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.orchs[orch.ID] = *orch
+	return nil
+}
+
+func (f *orchStoreFake) AddStep(_ context.Context, orchID string, step domain.OrchestrationStep) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orch := f.orchs[orchID]
+	orch.Steps = append(slices.Clone(orch.Steps), step)
+	f.orchs[orchID] = orch
+	return nil
+}
+
+func (f *orchStoreFake) Complete(_ context.Context, orchID string, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orch := f.orchs[orchID]
+	orch.Status = status
+	f.orchs[orchID] = orch
+	return nil
+}
+
+func (f *orchStoreFake) GetByID(_ context.Context, orchID string) (*domain.Orchestration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orch, ok := f.orchs[orchID]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrOrchestrationNotFound, "get orchestration", errors.New(orchID))
+	}
+	return &orch, nil
+}
+
+func (f *orchStoreFake) ListByUser(context.Context, string, int) ([]domain.Orchestration, error) {
+	return nil, nil
+}
+
+func (f *orchStoreFake) ListByConversation(context.Context, string, string, int) ([]domain.Orchestration, error) {
+	return nil, nil
+}
+
+func (f *orchStoreFake) DeleteByConversation(_ context.Context, userID, conversationID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, orch := range f.orchs {
+		if orch.UserID == userID && orch.ConversationID == conversationID {
+			delete(f.orchs, id)
+		}
+	}
+	return nil
+}
+
+func TestOrchestratorSubscribe(t *testing.T) {
+	running := make(chan struct{})
+	release := make(chan struct{})
+	agent := &orchAgentFake{hook: func(_ context.Context, task string) (string, error) {
+		if task == "research" {
+			close(running)
+			<-release
+		}
+		return "answer for " + task, nil
+	}}
+	plan := `{"steps":[
+		{"id":"r","agent":"researcher","task":"research"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["r"],"synthesis":true}
+	]}`
+	uc := newTestOrchestrator(agent, plan, OrchestratorOptions{})
+	store := &orchStoreFake{orchs: make(map[string]domain.Orchestration)}
+	uc.orchStore = store
+
+	orchIDs := make(chan string, 8)
+	done := make(chan error, 1)
+	go func() {
+		_, err := uc.Execute(context.Background(), domain.AgentChatRequest{
+			UserID:   "u-1",
+			Messages: []domain.AgentInputMessage{{Role: "user", Content: "q"}},
+		}, nil, func(status domain.OrchestrationStatus) { orchIDs <- status.OrchestrationID })
+		done <- err
+	}()
+	<-running
+	orchID := <-orchIDs
+
+	sub, err := uc.Subscribe(context.Background(), "u-1", orchID)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer sub.Cancel()
+	if len(sub.Events) != 1 || sub.Events[0].Status != "started" || sub.Updates == nil {
+		t.Fatalf("a running orchestration must replay its events and stream the rest, got %+v", sub.Events)
+	}
+	close(release)
+	var updates []domain.OrchestrationStatus
+	for event := range sub.Updates {
+		updates = append(updates, event)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(updates) != 3 || updates[2].StepID != "final" || updates[2].Status != "completed" {
+		t.Fatalf("unexpected live events: %+v", updates)
+	}
+
+	sub, err = uc.Subscribe(context.Background(), "u-1", orchID)
+	if err != nil {
+		t.Fatalf("Subscribe() after finish error = %v", err)
+	}
+	if sub.Updates != nil || len(sub.Events) != 2 || sub.Events[1].StepID != "final" {
+		t.Fatalf("a finished orchestration must only replay its stored steps, got %+v", sub.Events)
+	}
+	if _, err := uc.Subscribe(context.Background(), "u-2", orchID); !domain.IsKind(err, domain.ErrOrchestrationNotFound) {
+		t.Fatalf("another user's orchestration must not be found, got %v", err)
+	}
+}
+
+func TestOrchestratorRunsSpecialistsWithTheirPromptAndTask(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		systems []string
+	)
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(_ context.Context, msgs []domain.ChatMessage, _ []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			systems = append(systems, msgs[0].Content)
+			if strings.Contains(msgs[0].Content, "Task: research go") {
+				return &domain.ChatToolsResult{Content: "go is simple"}, nil
+			}
+			return &domain.ChatToolsResult{Content: "final answer"}, nil
+		},
+	}
+	conversations := &fakeConversationStore{}
+	agent := newTestAgentUC(query, func(uc *AgentChatUseCase) { uc.conversations = conversations })
+	registry := NewAgentRegistry(nil)
+	plan := `{"steps":[
+		{"id":"go","agent":"researcher","task":"research go"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["go"],"synthesis":true}
+	]}`
+	uc := NewOrchestratorUseCase(agent, registry, &orchMemoryFake{}, &fakeAgentEmbedder{},
+		&orchPlannerFake{queryGeneratorFake: &queryGeneratorFake{}, plan: plan}, nil, OrchestratorOptions{MaxParallel: 1})
+
+	result, err := uc.Execute(context.Background(), domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "explain go"}},
+	}, nil, nil)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Answer != "final answer" || len(systems) != 2 {
+		t.Fatalf("unexpected run: answer=%q prompts=%d", result.Answer, len(systems))
+	}
+	researcher, _ := registry.Get("researcher")
+	writer, _ := registry.Get("writer")
+	if !strings.HasPrefix(systems[0], researcher.SystemPrompt) || !strings.Contains(systems[0], "Task: research go") {
+		t.Fatalf("researcher prompt must carry its profile and task:\n%s", systems[0])
+	}
+	if !strings.HasPrefix(systems[1], writer.SystemPrompt) || !strings.Contains(systems[1], "go is simple") {
+		t.Fatalf("writer prompt must carry its profile and the research output:\n%s", systems[1])
+	}
+
+	var ids []string
+	for _, msg := range conversations.messages {
+		if !slices.Contains(ids, msg.ConversationID) {
+			ids = append(ids, msg.ConversationID)
+		}
+	}
+	prefix := "c-1_orch_" + result.OrchestrationID + "_"
+	if len(ids) != 2 || ids[0] != prefix+"go" || ids[1] != prefix+"final" {
+		t.Fatalf("each step needs its own conversation, got %v", ids)
+	}
+}
+
+func TestOrchestratorKeepsStepsOutOfParentMemory(t *testing.T) {
+	agent := &orchAgentFake{prompts: map[string]string{}, hook: func(_ context.Context, task string) (string, error) {
+		return "output of " + task, nil
+	}}
+	memory := &orchMemoryFake{fakeMemoryVectorStore: fakeMemoryVectorStore{hits: []domain.MemoryHit{
+		{Summary: domain.MemorySummary{Summary: "earlier talk about budgets"}},
+	}}}
+	plan := `{"steps":[
+		{"id":"a","agent":"researcher","task":"research"},
+		{"id":"b","agent":"coder","task":"prototype"},
+		{"id":"c","agent":"critic","task":"review","depends_on":["b"]},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["a","c"],"synthesis":true}
+	]}`
+	uc := NewOrchestratorUseCase(agent, NewAgentRegistry(nil), memory, &fakeAgentEmbedder{},
+		&orchPlannerFake{queryGeneratorFake: &queryGeneratorFake{}, plan: plan}, nil, OrchestratorOptions{MaxParallel: 1})
+
+	if _, err := uc.Execute(context.Background(), domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "build it"}},
+	}, nil, nil); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for task, prompt := range agent.prompts {
+		if strings.Contains(prompt, "earlier talk about budgets") {
+			t.Fatalf("step %q must not read the parent conversation's memory:\n%s", task, prompt)
+		}
+	}
+	if final := agent.prompts["synthesize"]; !strings.Contains(final, "output of review") || strings.Contains(final, "output of prototype") {
+		t.Fatalf("synthesis must see only the steps it depends on:\n%s", final)
+	}
+	if len(memory.indexed) != 1 || memory.indexed[0].ConversationID != "c-1" || !strings.Contains(memory.indexed[0].Summary, "output of synthesize") {
+		t.Fatalf("only the final synthesis belongs in the parent's memory, got %+v", memory.indexed)
+	}
+}
+
+func TestAgentChatTracesOrchestratedRequest(t *testing.T) {
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(context.Context, []domain.ChatMessage, []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			return &domain.ChatToolsResult{Content: "step answer"}, nil
+		},
+	}
+	runs := newAgentRunStoreFake()
+	agent := newTestAgentUC(query, func(uc *AgentChatUseCase) {
+		uc.limits.IntentRouterEnabled = true
+		uc.modelRouting = &domain.ModelRouting{Simple: "small", Complex: "large", Code: "coder"}
+	})
+	agent.SetRunStore(runs)
+	plan := `{"steps":[
+		{"id":"go","agent":"researcher","task":"research the backlog"},
+		{"id":"final","agent":"writer","task":"synthesize","depends_on":["go"],"synthesis":true}
+	]}`
+	agent.SetOrchestrator(NewOrchestratorUseCase(agent, NewAgentRegistry(nil), &orchMemoryFake{}, &fakeAgentEmbedder{},
+		&orchPlannerFake{queryGeneratorFake: &queryGeneratorFake{}, plan: plan}, nil, OrchestratorOptions{MaxParallel: 1}))
+
+	result, err := agent.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "Deep research the task backlog and analyze its priorities"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.OrchestrationID == "" || result.RunID == "" {
+		t.Fatalf("expected an orchestrated and traced run, got %+v", result)
+	}
+	run, err := runs.GetAgentRun(context.Background(), result.RunID)
+	if err != nil {
+		t.Fatalf("GetAgentRun() error = %v", err)
+	}
+	if run.OrchestrationID != result.OrchestrationID || run.ConversationID != "c-1" || run.Answer != result.Answer {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if len(result.OrchestrationSteps) != 2 {
+		t.Fatalf("steps = %+v, want 2", result.OrchestrationSteps)
+	}
+	for _, step := range result.OrchestrationSteps {
+		stepRun, err := runs.GetAgentRun(context.Background(), step.RunID)
+		if err != nil {
+			t.Fatalf("step %s is not linked to its run: %v", step.StepID, err)
+		}
+		if stepRun.ConversationID != domain.StepConversationID("c-1", result.OrchestrationID, step.StepID) {
+			t.Fatalf("step run conversation = %q", stepRun.ConversationID)
+		}
+	}
+}
+
+func TestAgentChatOrchestratorFallbackStoresUserMessageOnce(t *testing.T) {
+	query := &fakeAgentQueryService{
+		chatToolsHook: func(context.Context, []domain.ChatMessage, []domain.ToolSchema) (*domain.ChatToolsResult, error) {
+			return &domain.ChatToolsResult{Content: "single agent answer"}, nil
+		},
+	}
+	conversations := &fakeConversationStore{}
+	agent := newTestAgentUC(query, func(uc *AgentChatUseCase) {
+		uc.conversations = conversations
+		uc.limits.IntentRouterEnabled = true
+		uc.modelRouting = &domain.ModelRouting{Simple: "small", Complex: "large", Code: "coder"}
+	})
+	agent.SetOrchestrator(NewOrchestratorUseCase(agent, NewAgentRegistry(nil), &orchMemoryFake{}, &fakeAgentEmbedder{},
+		&orchPlannerFake{queryGeneratorFake: &queryGeneratorFake{}, plan: "not a plan"}, nil, OrchestratorOptions{MaxParallel: 1}))
+
+	result, err := agent.Complete(context.Background(), domain.AgentChatRequest{
+		UserID:         "u-1",
+		ConversationID: "c-1",
+		Messages:       []domain.AgentInputMessage{{Role: "user", Content: "Deep research the task backlog and analyze its priorities"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if result.OrchestrationID != "" || result.Answer != "single agent answer" {
+		t.Fatalf("expected a single agent fallback, got %+v", result)
+	}
+	users := 0
+	for _, msg := range conversations.messages {
+		if msg.Role == "user" {
+			users++
+		}
+		if msg.UserTurn != 1 {
+			t.Fatalf("message %q stored under turn %d, want 1", msg.Content, msg.UserTurn)
+		}
+	}
+	if users != 1 || len(conversations.messages) != 2 {
+		t.Fatalf("expected one user and one assistant message, got %+v", conversations.messages)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
@@ -19,6 +20,9 @@ type ProcessDocumentUseCase struct {
 	vectorDB      ports.VectorStore
 	queue         ports.MessageQueue
 	graphStore    ports.GraphStore
+	chunkContext  ChunkContextOptions
+	attachments   ports.AttachmentIngestor
+	deleter       ports.DocumentDeleter
 }
 
 func NewProcessDocumentUseCase(
@@ -69,29 +73,33 @@ func (uc *ProcessDocumentUseCase) processPipeline(ctx context.Context, documentI
 		return nil, err
 	}
 
-	text, err := uc.extractText(ctx, doc)
+	sections, err := uc.extractSections(ctx, doc)
 	if err != nil {
 		return nil, err
 	}
+	text := domain.JoinSections(sections)
 
 	meta, err := uc.extractMetadata(ctx, doc, text)
 	if err != nil {
 		return nil, err
 	}
 
-	chunks, err := uc.chunk(ctx, text, meta.SourceType)
+	chunks, locators, err := uc.chunk(ctx, sections, meta.SourceType, doc.MimeType)
 	if err != nil {
 		return nil, err
 	}
 
-	vectors, err := uc.embed(ctx, chunks)
+	// Chunks are embedded with their contextual prefix; the bare chunk text
+	// is what gets stored, shown and cited.
+	contexts := uc.chunkContexts(ctx, meta.Title, text, chunks)
+	vectors, err := uc.embed(ctx, contextualizeChunks(chunks, contexts))
 	if err != nil {
 		return nil, err
 	}
 
 	uc.applyMetadata(doc, meta)
 
-	if err := uc.index(ctx, doc, chunks, vectors); err != nil {
+	if err := uc.index(ctx, doc, chunks, chunkAnnotations(contexts, locators), vectors); err != nil {
 		return nil, err
 	}
 
@@ -100,6 +108,11 @@ func (uc *ProcessDocumentUseCase) processPipeline(ctx context.Context, documentI
 		uc.indexGraph(ctx, doc, text, vectors)
 	}
 
+	// Ingest attached files as child documents (best-effort).
+	if uc.attachments != nil {
+		uc.ingestAttachments(ctx, doc)
+	}
+
 	// Publish enrichment event (best-effort — don't fail the pipeline).
 	if pubErr := uc.queue.PublishDocumentEnrich(ctx, doc.ID); pubErr != nil {
 		slog.Warn("publish_enrich_failed", "document_id", doc.ID, "error", pubErr)
@@ -116,15 +129,27 @@ func (uc *ProcessDocumentUseCase) loadDocument(ctx context.Context, documentID s
 	return doc, nil
 }
 
-func (uc *ProcessDocumentUseCase) extractText(ctx context.Context, doc *domain.Document) (string, error) {
-	text, err := uc.extractors.ForMimeType(doc.MimeType).Extract(ctx, doc)
-	if err != nil {
-		return "", fmt.Errorf("extract text: %w", err)
+// extractSections extracts the document as located sections. Extractors
+// without structure yield one section without a locator.
+func (uc *ProcessDocumentUseCase) extractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error) {
+	extractor := uc.extractors.ForMimeType(doc.MimeType)
+	var sections []domain.Section
+	if se, ok := extractor.(ports.StructuredExtractor); ok {
+		var err error
+		if sections, err = se.ExtractSections(ctx, doc); err != nil {
+			return nil, fmt.Errorf("extract text: %w", err)
+		}
+	} else {
+		text, err := extractor.Extract(ctx, doc)
+		if err != nil {
+			return nil, fmt.Errorf("extract text: %w", err)
+		}
+		sections = []domain.Section{{Text: text}}
 	}
-	if text == "" {
-		return "", domain.WrapError(domain.ErrInvalidInput, "extract text", errors.New("empty extracted text"))
+	if domain.JoinSections(sections) == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "extract text", errors.New("empty extracted text"))
 	}
-	return text, nil
+	return sections, nil
 }
 
 func (uc *ProcessDocumentUseCase) extractMetadata(ctx context.Context, doc *domain.Document, text string) (domain.DocumentMetadata, error) {
@@ -135,13 +160,36 @@ func (uc *ProcessDocumentUseCase) extractMetadata(ctx context.Context, doc *doma
 	return meta, nil
 }
 
-func (uc *ProcessDocumentUseCase) chunk(_ context.Context, text string, sourceType string) ([]string, error) {
-	chunker := uc.chunkers.ForSource(sourceType)
-	chunks := chunker.Split(text)
+// chunk splits every section on its own, so a chunk never spans two pages
+// or sheets, and returns each chunk's locator.
+func (uc *ProcessDocumentUseCase) chunk(ctx context.Context, sections []domain.Section, sourceType, mimeType string) ([]string, []domain.Locator, error) {
+	chunker := uc.chunkers.ForDocument(sourceType, mimeType)
+	var (
+		chunks   []string
+		locators []domain.Locator
+	)
+	for _, section := range sections {
+		if strings.TrimSpace(section.Text) == "" {
+			continue
+		}
+		var parts []string
+		if cc, ok := chunker.(ports.ContextChunker); ok {
+			var err error
+			if parts, err = cc.SplitContext(ctx, section.Text); err != nil {
+				return nil, nil, fmt.Errorf("chunk document: %w", err)
+			}
+		} else {
+			parts = chunker.Split(section.Text)
+		}
+		for _, part := range parts {
+			chunks = append(chunks, part)
+			locators = append(locators, section.Locator)
+		}
+	}
 	if len(chunks) == 0 {
-		return nil, domain.WrapError(domain.ErrInvalidInput, "chunk document", errors.New("chunking produced zero chunks"))
+		return nil, nil, domain.WrapError(domain.ErrInvalidInput, "chunk document", errors.New("chunking produced zero chunks"))
 	}
-	return chunks, nil
+	return chunks, locators, nil
 }
 
 func (uc *ProcessDocumentUseCase) embed(ctx context.Context, chunks []string) ([][]float32, error) {
@@ -159,8 +207,19 @@ func (uc *ProcessDocumentUseCase) embed(ctx context.Context, chunks []string) ([
 	return vectors, nil
 }
 
-func (uc *ProcessDocumentUseCase) index(ctx context.Context, doc *domain.Document, chunks []string, vectors [][]float32) error {
-	if err := uc.vectorDB.IndexChunks(ctx, doc, chunks, vectors); err != nil {
+func (uc *ProcessDocumentUseCase) index(ctx context.Context, doc *domain.Document, chunks []string, annotations []domain.ChunkAnnotation, vectors [][]float32) error {
+	// Drop chunks from a previous run first: re-ingested documents keep their ID,
+	// and a shorter new version would otherwise leave stale tail chunks behind.
+	if err := uc.vectorDB.DeleteByDocumentID(ctx, doc.ID); err != nil {
+		return fmt.Errorf("delete previous chunks: %w", err)
+	}
+	var err error
+	if av, ok := uc.vectorDB.(ports.AnnotatedVectorStore); ok && annotations != nil {
+		err = av.IndexAnnotatedChunks(ctx, doc, chunks, annotations, vectors)
+	} else {
+		err = uc.vectorDB.IndexChunks(ctx, doc, chunks, vectors)
+	}
+	if err != nil {
 		return fmt.Errorf("index chunks in vector db: %w", err)
 	}
 	return nil
@@ -186,6 +245,9 @@ func (uc *ProcessDocumentUseCase) indexGraph(ctx context.Context, doc *domain.Do
 		slog.Warn("graph_upsert_failed", "document_id", doc.ID, "error", err)
 		return
 	}
+	if doc.ParentID != "" {
+		_ = uc.graphStore.AddLink(ctx, doc.ID, doc.ParentID, "attachment_of")
+	}
 
 	// Extract and resolve wikilinks.
 	for _, target := range extractWikilinks(text) {
@@ -228,5 +290,8 @@ func (uc *ProcessDocumentUseCase) applyMetadata(doc *domain.Document, meta domai
 	doc.Title = meta.Title
 	doc.Summary = meta.Summary
 	doc.Headers = meta.Headers
-	doc.Path = meta.Path
+	// Keep the source path (e.g. vault-relative note path) when the adapter set one.
+	if doc.Path == "" {
+		doc.Path = meta.Path
+	}
 }
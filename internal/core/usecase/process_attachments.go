@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// maxAttachmentDepth bounds how deeply attachments of attachments, such as
+// forwarded emails, are ingested.
+const maxAttachmentDepth = 3
+
+// SetAttachmentIngestor enables ingesting the attachments of documents whose
+// extractor exposes them (see ports.AttachmentExtractor) as child documents.
+func (uc *ProcessDocumentUseCase) SetAttachmentIngestor(ingestor ports.AttachmentIngestor) {
+	uc.attachments = ingestor
+}
+
+// SetAttachmentDeleter makes re-processing a document delete the child
+// documents of attachments it no longer has.
+func (uc *ProcessDocumentUseCase) SetAttachmentDeleter(deleter ports.DocumentDeleter) {
+	uc.deleter = deleter
+}
+
+// ingestAttachments hands every attachment of doc to the ingestor. Each
+// child is queued and processed like any other document, so attachments
+// that carry files of their own are followed up to maxAttachmentDepth.
+func (uc *ProcessDocumentUseCase) ingestAttachments(ctx context.Context, doc *domain.Document) {
+	extractor, ok := uc.extractors.ForMimeType(doc.MimeType).(ports.AttachmentExtractor)
+	if !ok {
+		return
+	}
+	if depth := uc.attachmentDepth(ctx, doc); depth >= maxAttachmentDepth {
+		slog.Warn("attachments_too_deep", "document_id", doc.ID, "depth", depth)
+		return
+	}
+	attachments, err := extractor.ExtractAttachments(ctx, doc)
+	if err != nil {
+		slog.Warn("extract_attachments_failed", "document_id", doc.ID, "error", err)
+		return
+	}
+	for i, attachment := range attachments {
+		if _, err := uc.attachments.IngestAttachment(ctx, doc, i, attachment); err != nil {
+			slog.Warn("attachment_ingest_failed", "document_id", doc.ID, "attachment", attachment.Filename, "error", err)
+		}
+	}
+	uc.pruneAttachments(ctx, doc, len(attachments))
+}
+
+// pruneAttachments deletes, with their chunks, the children of doc whose
+// attachment index is count or above, left over from an earlier version of
+// doc that had more attachments.
+func (uc *ProcessDocumentUseCase) pruneAttachments(ctx context.Context, doc *domain.Document, count int) {
+	if uc.deleter == nil {
+		return
+	}
+	children, err := uc.repo.ListByFilter(ctx, domain.DocumentFilter{ParentIDs: []string{doc.ID}}, 0)
+	if err != nil {
+		slog.Warn("list_attachments_failed", "document_id", doc.ID, "error", err)
+		return
+	}
+	for _, child := range children {
+		suffix, ok := strings.CutPrefix(child.SourceID, attachmentSourcePrefix(doc.ID))
+		if !ok {
+			continue
+		}
+		index, err := strconv.Atoi(suffix)
+		if err != nil || index < count {
+			continue
+		}
+		if err := uc.deleter.Delete(ctx, child.ID); err != nil {
+			slog.Warn("stale_attachment_delete_failed", "document_id", doc.ID, "attachment_id", child.ID, "error", err)
+			continue
+		}
+		slog.Info("stale_attachment_deleted", "document_id", doc.ID, "attachment_id", child.ID, "index", index)
+	}
+}
+
+// attachmentDepth counts the ancestors of doc, stopping at maxAttachmentDepth.
+func (uc *ProcessDocumentUseCase) attachmentDepth(ctx context.Context, doc *domain.Document) int {
+	depth := 0
+	for parentID := doc.ParentID; parentID != "" && depth < maxAttachmentDepth; depth++ {
+		parent, err := uc.repo.GetByID(ctx, parentID)
+		if err != nil {
+			return depth + 1
+		}
+		parentID = parent.ParentID
+	}
+	return depth
+}
@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	// chunkContextDocRunes caps the document excerpt sent with each chunk to
+	// the context generator.
+	chunkContextDocRunes = 12000
+	// chunkContextMaxRunes caps one generated context line.
+	chunkContextMaxRunes = 300
+)
+
+var (
+	headingLineRe = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.+?)\s*#*\s*$`)
+	codeFenceRe   = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+// ChunkContextOptions controls the contextual prefix embedded with each
+// chunk. Headers prepends the document title and the Markdown heading path;
+// a non-nil Generator adds a one-line LLM-written context per chunk.
+type ChunkContextOptions struct {
+	Headers   bool
+	Generator ports.AnswerGenerator
+}
+
+// SetChunkContext enables contextual chunk prefixes.
+func (uc *ProcessDocumentUseCase) SetChunkContext(opts ChunkContextOptions) {
+	uc.chunkContext = opts
+}
+
+// chunkContexts builds the contextual prefix of every chunk, or returns nil
+// when prefixes are disabled.
+func (uc *ProcessDocumentUseCase) chunkContexts(ctx context.Context, title, text string, chunks []string) []string {
+	opts := uc.chunkContext
+	if !opts.Headers && opts.Generator == nil {
+		return nil
+	}
+	var breadcrumbs []string
+	if opts.Headers {
+		breadcrumbs = headingBreadcrumbs(chunks)
+	}
+	title = strings.TrimSpace(title)
+	excerpt := truncateRunes(text, chunkContextDocRunes)
+
+	contexts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		var lines []string
+		if opts.Headers {
+			if title != "" {
+				lines = append(lines, "Document: "+title)
+			}
+			if breadcrumbs[i] != "" {
+				lines = append(lines, "Section: "+breadcrumbs[i])
+			}
+		}
+		if opts.Generator != nil {
+			line, err := situateChunk(ctx, opts.Generator, excerpt, chunk)
+			if err != nil {
+				slog.Warn("chunk_context_generation_failed", "chunk_index", i, "error", err)
+			} else if line != "" {
+				lines = append(lines, "Context: "+line)
+			}
+		}
+		contexts[i] = strings.Join(lines, "\n")
+	}
+	return contexts
+}
+
+// headingBreadcrumbs returns, for each chunk, the path of Markdown headings
+// ("A > B > C") its text falls under. Headings inside fenced code blocks are
+// ignored; headings a chunk starts with are part of its own path.
+func headingBreadcrumbs(chunks []string) []string {
+	var stack [6]string
+	path := func() string {
+		parts := make([]string, 0, len(stack))
+		for _, h := range stack {
+			if h != "" {
+				parts = append(parts, h)
+			}
+		}
+		return strings.Join(parts, " > ")
+	}
+
+	out := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		inFence, inBody := false, false
+		for _, line := range strings.Split(chunk, "\n") {
+			if codeFenceRe.MatchString(line) {
+				inFence = !inFence
+			}
+			m := headingLineRe.FindStringSubmatch(line)
+			if inFence || m == nil {
+				if !inBody && strings.TrimSpace(line) != "" {
+					out[i], inBody = path(), true
+				}
+				continue
+			}
+			level := len(m[1]) - 1
+			stack[level] = m[2]
+			for j := level + 1; j < len(stack); j++ {
+				stack[j] = ""
+			}
+		}
+		if !inBody {
+			out[i] = path()
+		}
+	}
+	return out
+}
+
+// situateChunk asks the generator for one sentence placing chunk within the
+// document.
+func situateChunk(ctx context.Context, generator ports.AnswerGenerator, document, chunk string) (string, error) {
+	prompt := fmt.Sprintf(`<document>
+%s
+</document>
+
+Here is a chunk from this document:
+<chunk>
+%s
+</chunk>
+
+Write one short sentence that situates this chunk within the document (what it is about and which part of the document it belongs to), to improve search retrieval of the chunk. Answer only with the sentence, in the language of the document.`, document, chunk)
+
+	resp, err := generator.GenerateFromPrompt(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("generate chunk context: %w", err)
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(resp), "\n")
+	return truncateRunes(strings.TrimSpace(line), chunkContextMaxRunes), nil
+}
+
+// contextualizeChunks prepends each non-empty prefix to its chunk.
+func contextualizeChunks(chunks, contexts []string) []string {
+	if contexts == nil {
+		return chunks
+	}
+	out := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		if contexts[i] == "" {
+			out[i] = chunk
+			continue
+		}
+		out[i] = contexts[i] + "\n\n" + chunk
+	}
+	return out
+}
+
+// chunkAnnotations pairs chunk contexts with locators, or returns nil when
+// neither carries anything.
+func chunkAnnotations(contexts []string, locators []domain.Locator) []domain.ChunkAnnotation {
+	located := false
+	for _, l := range locators {
+		if !l.IsZero() {
+			located = true
+			break
+		}
+	}
+	if contexts == nil && !located {
+		return nil
+	}
+	out := make([]domain.ChunkAnnotation, len(locators))
+	for i := range out {
+		out[i].Locator = locators[i]
+		if contexts != nil {
+			out[i].Context = contexts[i]
+		}
+	}
+	return out
+}
+
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) > maxLen {
+		return string(runes[:maxLen])
+	}
+	return s
+}
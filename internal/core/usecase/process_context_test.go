@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+func TestHeadingBreadcrumbs(t *testing.T) {
+	chunks := []string{
+		"# Guide\n\nIntro text.",
+		"## Timeouts\n\nSet it to 30.",
+		"More about timeouts.\n\n```sh\n# not a heading\n```",
+		"### Retries\n\nThree attempts.\n\n## Logging\n\nUse JSON.",
+		"# Appendix",
+	}
+	got := headingBreadcrumbs(chunks)
+	want := []string{
+		"Guide",
+		"Guide > Timeouts",
+		"Guide > Timeouts",
+		"Guide > Timeouts > Retries",
+		"Appendix",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("chunk %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+type contextEmbedderFake struct {
+	texts []string
+}
+
+func (f *contextEmbedderFake) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	f.texts = texts
+	out := make([][]float32, len(texts))
+	for i := range texts {
+		out[i] = []float32{float32(i)}
+	}
+	return out, nil
+}
+
+func (f *contextEmbedderFake) EmbedQuery(context.Context, string) ([]float32, error) { return nil, nil }
+
+type contextualVectorFake struct {
+	vectorFake
+	chunks   []string
+	contexts []string
+	locators []domain.Locator
+}
+
+func (f *contextualVectorFake) IndexAnnotatedChunks(_ context.Context, _ *domain.Document, chunks []string, annotations []domain.ChunkAnnotation, _ [][]float32) error {
+	f.chunks = chunks
+	f.contexts, f.locators = nil, nil
+	for _, a := range annotations {
+		f.contexts = append(f.contexts, a.Context)
+		f.locators = append(f.locators, a.Locator)
+	}
+	return nil
+}
+
+type contextGeneratorFake struct {
+	queryGeneratorFake
+	err error
+}
+
+func (f *contextGeneratorFake) GenerateFromPrompt(context.Context, string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return "Explains the request timeout setting.\nExtra line.", nil
+}
+
+func TestProcessByIDEmbedsContextualChunks(t *testing.T) {
+	embedder := &contextEmbedderFake{}
+	vector := &contextualVectorFake{}
+	uc := NewProcessDocumentUseCase(
+		&processRepoFake{doc: &domain.Document{ID: "doc-1", Filename: "proxy.md"}},
+		&extractorRegistryFake{text: "# Proxy\n\n## Timeouts\n\nSet it to 30."},
+		&metadataExtractorFake{meta: domain.DocumentMetadata{SourceType: "upload", Title: "Proxy setup"}},
+		&chunkerRegistryFake{chunks: []string{"## Timeouts\n\nSet it to 30."}},
+		embedder,
+		vector,
+		&queueFake{},
+		nil,
+	)
+	uc.SetChunkContext(ChunkContextOptions{Headers: true, Generator: &contextGeneratorFake{}})
+
+	if err := uc.ProcessByID(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	wantContext := "Document: Proxy setup\nSection: Timeouts\nContext: Explains the request timeout setting."
+	if len(vector.contexts) != 1 || vector.contexts[0] != wantContext {
+		t.Fatalf("unexpected contexts: %q", vector.contexts)
+	}
+	if vector.chunks[0] != "## Timeouts\n\nSet it to 30." {
+		t.Fatalf("stored chunk must stay bare, got %q", vector.chunks[0])
+	}
+	if embedder.texts[0] != wantContext+"\n\n"+vector.chunks[0] {
+		t.Fatalf("unexpected embedded text: %q", embedder.texts[0])
+	}
+}
+
+func TestChunkContextsSkipsFailedGeneration(t *testing.T) {
+	uc := &ProcessDocumentUseCase{}
+	uc.SetChunkContext(ChunkContextOptions{Headers: true, Generator: &contextGeneratorFake{err: errors.New("llm down")}})
+
+	contexts := uc.chunkContexts(context.Background(), "Notes", "text", []string{"plain chunk"})
+	if len(contexts) != 1 || contexts[0] != "Document: Notes" {
+		t.Fatalf("unexpected contexts: %q", contexts)
+	}
+	if strings.Contains(contexts[0], "Context:") {
+		t.Fatalf("failed generation must not add a context line")
+	}
+}
+
+func TestChunkContextsDisabled(t *testing.T) {
+	uc := &ProcessDocumentUseCase{}
+	if contexts := uc.chunkContexts(context.Background(), "Notes", "text", []string{"a"}); contexts != nil {
+		t.Fatalf("expected no contexts, got %q", contexts)
+	}
+}
+
+type sectionExtractorFake struct {
+	extractorFake
+	sections []domain.Section
+}
+
+func (f *sectionExtractorFake) ExtractSections(context.Context, *domain.Document) ([]domain.Section, error) {
+	return f.sections, nil
+}
+
+type sectionExtractorRegistryFake struct {
+	extractor *sectionExtractorFake
+}
+
+func (f *sectionExtractorRegistryFake) ForMimeType(string) ports.TextExtractor { return f.extractor }
+
+type lineChunker struct{}
+
+func (lineChunker) Split(text string) []string { return strings.Split(text, "\n") }
+
+type lineChunkerRegistry struct{}
+
+func (lineChunkerRegistry) ForSource(string) ports.Chunker           { return lineChunker{} }
+func (lineChunkerRegistry) ForDocument(string, string) ports.Chunker { return lineChunker{} }
+
+func TestProcessByIDChunksSectionsWithLocators(t *testing.T) {
+	vector := &contextualVectorFake{}
+	uc := NewProcessDocumentUseCase(
+		&processRepoFake{doc: &domain.Document{ID: "doc-1", Filename: "report.pdf"}},
+		&sectionExtractorRegistryFake{extractor: &sectionExtractorFake{sections: []domain.Section{
+			{Locator: domain.Locator{Page: 1}, Text: "Intro\nScope"},
+			{Locator: domain.Locator{Page: 2}, Text: "   "},
+			{Locator: domain.Locator{Page: 3}, Text: "Results"},
+		}}},
+		&metadataExtractorFake{meta: domain.DocumentMetadata{SourceType: "upload"}},
+		lineChunkerRegistry{},
+		&contextEmbedderFake{},
+		vector,
+		&queueFake{},
+		nil,
+	)
+
+	if err := uc.ProcessByID(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	wantChunks := []string{"Intro", "Scope", "Results"}
+	wantPages := []int{1, 1, 3}
+	if len(vector.chunks) != len(wantChunks) || len(vector.locators) != len(wantChunks) {
+		t.Fatalf("unexpected chunks %q / locators %+v", vector.chunks, vector.locators)
+	}
+	for i := range wantChunks {
+		if vector.chunks[i] != wantChunks[i] || vector.locators[i].Page != wantPages[i] {
+			t.Fatalf("chunk %d: got %q on %+v", i, vector.chunks[i], vector.locators[i])
+		}
+	}
+	if vector.contexts[0] != "" {
+		t.Fatalf("contexts are disabled, got %q", vector.contexts[0])
+	}
+}
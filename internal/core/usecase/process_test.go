@@ -3,6 +3,8 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
@@ -23,6 +25,7 @@ type processRepoFake struct {
 	statusCalls      []statusCall
 	classification   domain.Classification
 	classificationID string
+	children         []domain.Document
 }
 
 func (f *processRepoFake) Create(context.Context, *domain.Document) error { return nil }
@@ -34,6 +37,13 @@ func (f *processRepoFake) GetByID(context.Context, string) (*domain.Document, er
 	copyDoc := *f.doc
 	return &copyDoc, nil
 }
+func (f *processRepoFake) GetBySourceID(context.Context, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *processRepoFake) GetByContentHash(context.Context, string, string) (*domain.Document, error) {
+	return nil, domain.ErrDocumentNotFound
+}
+func (f *processRepoFake) UpdateSource(context.Context, *domain.Document) error { return nil }
 
 func (f *processRepoFake) UpdateStatus(_ context.Context, _ string, status domain.DocumentStatus, errMessage string) error {
 	f.statusCalls = append(f.statusCalls, statusCall{status: status, errMsg: errMessage})
@@ -54,10 +64,17 @@ func (f *processRepoFake) SaveClassification(_ context.Context, id string, cls d
 	f.classification = cls
 	return nil
 }
+func (f *processRepoFake) UpdateACL(context.Context, string, domain.DocumentVisibility, []string) error {
+	return nil
+}
 
 func (f *processRepoFake) ListRecent(context.Context, int) ([]domain.Document, error) {
 	return nil, nil
 }
+func (f *processRepoFake) ListByFilter(context.Context, domain.DocumentFilter, int) ([]domain.Document, error) {
+	return f.children, nil
+}
+func (f *processRepoFake) Delete(context.Context, string) error { return nil }
 
 type extractorFake struct {
 	text string
@@ -120,6 +137,7 @@ func (f *graphStoreFake) AddSimilarity(context.Context, string, string, float64)
 	return nil
 }
 func (f *graphStoreFake) RemoveSimilarities(context.Context, string) error { return nil }
+func (f *graphStoreFake) DeleteDocument(context.Context, string) error     { return nil }
 func (f *graphStoreFake) GetRelated(context.Context, string, int, int) ([]domain.GraphRelation, error) {
 	return nil, nil
 }
@@ -147,6 +165,10 @@ func (f *chunkerRegistryFake) ForSource(string) ports.Chunker {
 	return &chunkerFake{chunks: f.chunks}
 }
 
+func (f *chunkerRegistryFake) ForDocument(string, string) ports.Chunker {
+	return &chunkerFake{chunks: f.chunks}
+}
+
 type embedderFake struct {
 	vectors [][]float32
 	err     error
@@ -162,10 +184,12 @@ func (f *embedderFake) Embed(context.Context, []string) ([][]float32, error) {
 func (f *embedderFake) EmbedQuery(context.Context, string) ([]float32, error) { return nil, nil }
 
 type vectorFake struct {
-	err error
+	err   error
+	calls []string
 }
 
 func (f *vectorFake) IndexChunks(context.Context, *domain.Document, []string, [][]float32) error {
+	f.calls = append(f.calls, "index")
 	return f.err
 }
 
@@ -178,6 +202,10 @@ func (f *vectorFake) SearchLexical(context.Context, string, int, domain.SearchFi
 }
 
 func (f *vectorFake) UpdateChunksPayload(context.Context, string, string, map[string]any) error { return nil }
+func (f *vectorFake) DeleteByDocumentID(context.Context, string) error {
+	f.calls = append(f.calls, "delete")
+	return nil
+}
 
 func TestProcessByIDSuccess(t *testing.T) {
 	repo := &processRepoFake{doc: &domain.Document{ID: "doc-1", Filename: "test.md"}}
@@ -207,6 +235,28 @@ func TestProcessByIDSuccess(t *testing.T) {
 	}
 }
 
+func TestProcessByIDReplacesPreviousChunks(t *testing.T) {
+	repo := &processRepoFake{doc: &domain.Document{ID: "doc-1", Filename: "test.md"}}
+	vector := &vectorFake{}
+	uc := NewProcessDocumentUseCase(
+		repo,
+		&extractorRegistryFake{text: "Some text"},
+		&metadataExtractorFake{meta: domain.DocumentMetadata{SourceType: "markdown"}},
+		&chunkerRegistryFake{chunks: []string{"a"}},
+		&embedderFake{vectors: [][]float32{{1}}},
+		vector,
+		&queueFake{},
+		nil,
+	)
+
+	if err := uc.ProcessByID(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	if len(vector.calls) != 2 || vector.calls[0] != "delete" || vector.calls[1] != "index" {
+		t.Fatalf("expected delete before index, got %v", vector.calls)
+	}
+}
+
 func TestProcessByIDMarksFailedOnExtractError(t *testing.T) {
 	repo := &processRepoFake{doc: &domain.Document{ID: "doc-1"}}
 	uc := NewProcessDocumentUseCase(
@@ -253,3 +303,117 @@ func TestProcessByIDMarksFailedOnVectorMismatch(t *testing.T) {
 		t.Fatalf("expected final failed status, got %+v", repo.statusCalls)
 	}
 }
+
+type attachmentExtractorFake struct {
+	extractorFake
+	attachments []domain.Attachment
+}
+
+func (f *attachmentExtractorFake) ExtractAttachments(context.Context, *domain.Document) ([]domain.Attachment, error) {
+	return f.attachments, nil
+}
+
+type attachmentRegistryFake struct {
+	ext ports.TextExtractor
+}
+
+func (f *attachmentRegistryFake) ForMimeType(string) ports.TextExtractor { return f.ext }
+
+type attachmentIngestorFake struct {
+	ingested []string
+}
+
+func (f *attachmentIngestorFake) IngestAttachment(_ context.Context, parent *domain.Document, index int, a domain.Attachment) (*domain.Document, error) {
+	f.ingested = append(f.ingested, fmt.Sprintf("%s/%d/%s", parent.ID, index, a.Filename))
+	return &domain.Document{ID: a.Filename, ParentID: parent.ID}, nil
+}
+
+func TestProcessByIDIngestsAttachments(t *testing.T) {
+	newUC := func(doc *domain.Document, ingestor *attachmentIngestorFake) *ProcessDocumentUseCase {
+		uc := NewProcessDocumentUseCase(
+			&processRepoFake{doc: doc},
+			&attachmentRegistryFake{ext: &attachmentExtractorFake{
+				extractorFake: extractorFake{text: "Subject: Report\n\nAttachment: q3.csv"},
+				attachments:   []domain.Attachment{{Filename: "q3.csv"}, {Filename: "fwd.eml"}},
+			}},
+			&metadataExtractorFake{meta: domain.DocumentMetadata{SourceType: "upload"}},
+			&chunkerRegistryFake{chunks: []string{"a"}},
+			&embedderFake{vectors: [][]float32{{1}}},
+			&vectorFake{},
+			&queueFake{},
+			nil,
+		)
+		uc.SetAttachmentIngestor(ingestor)
+		return uc
+	}
+
+	ingestor := &attachmentIngestorFake{}
+	if err := newUC(&domain.Document{ID: "mail-1"}, ingestor).ProcessByID(context.Background(), "mail-1"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	want := []string{"mail-1/0/q3.csv", "mail-1/1/fwd.eml"}
+	if !slices.Equal(ingestor.ingested, want) {
+		t.Fatalf("ingested = %v, want %v", ingestor.ingested, want)
+	}
+
+	// The fake repository resolves every parent to a document with a parent
+	// again, so the chain is deeper than maxAttachmentDepth.
+	deep := &attachmentIngestorFake{}
+	if err := newUC(&domain.Document{ID: "att-9", ParentID: "att-9"}, deep).ProcessByID(context.Background(), "att-9"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	if len(deep.ingested) != 0 {
+		t.Fatalf("attachments beyond maxAttachmentDepth must not be ingested: %v", deep.ingested)
+	}
+}
+
+type documentDeleterFake struct {
+	deleted []string
+}
+
+func (f *documentDeleterFake) Delete(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *documentDeleterFake) DeleteByFilter(context.Context, domain.DocumentFilter) (*domain.DocumentDeleteResult, error) {
+	return &domain.DocumentDeleteResult{}, nil
+}
+
+func TestProcessByIDDeletesAttachmentsTheDocumentNoLongerHas(t *testing.T) {
+	repo := &processRepoFake{
+		doc: &domain.Document{ID: "mail-1"},
+		children: []domain.Document{
+			{ID: "att-0", ParentID: "mail-1", SourceID: "attachment:mail-1/0"},
+			{ID: "att-1", ParentID: "mail-1", SourceID: "attachment:mail-1/1"},
+			{ID: "att-2", ParentID: "mail-1", SourceID: "attachment:mail-1/2"},
+		},
+	}
+	uc := NewProcessDocumentUseCase(
+		repo,
+		&attachmentRegistryFake{ext: &attachmentExtractorFake{
+			extractorFake: extractorFake{text: "Subject: Report"},
+			attachments:   []domain.Attachment{{Filename: "q3.csv"}},
+		}},
+		&metadataExtractorFake{meta: domain.DocumentMetadata{SourceType: "upload"}},
+		&chunkerRegistryFake{chunks: []string{"a"}},
+		&embedderFake{vectors: [][]float32{{1}}},
+		&vectorFake{},
+		&queueFake{},
+		nil,
+	)
+	ingestor := &attachmentIngestorFake{}
+	deleter := &documentDeleterFake{}
+	uc.SetAttachmentIngestor(ingestor)
+	uc.SetAttachmentDeleter(deleter)
+
+	if err := uc.ProcessByID(context.Background(), "mail-1"); err != nil {
+		t.Fatalf("ProcessByID() error = %v", err)
+	}
+	if !slices.Equal(ingestor.ingested, []string{"mail-1/0/q3.csv"}) {
+		t.Fatalf("ingested = %v", ingestor.ingested)
+	}
+	if want := []string{"att-1", "att-2"}; !slices.Equal(deleter.deleted, want) {
+		t.Fatalf("deleted = %v, want %v", deleter.deleted, want)
+	}
+}
@@ -23,6 +23,16 @@ type QueryOptions struct {
 	QueryExpansionEnabled bool
 	QueryExpansionCount   int
 	GraphStore            ports.GraphStore
+
+	// ChunkReader enables context expansion; ContextExpansionBySource
+	// overrides ContextExpansion per source type.
+	ChunkReader              ports.ChunkReader
+	ContextExpansion         domain.ContextExpansion
+	ContextExpansionBySource map[string]domain.ContextExpansion
+	// ContextTokenBudget caps the approximate tokens of retrieved context
+	// passed to the generator when context expansion is enabled; 0 =
+	// unlimited.
+	ContextTokenBudget int
 }
 
 type QueryUseCase struct {
@@ -39,6 +49,11 @@ type QueryUseCase struct {
 
 	queryExpansionEnabled bool
 	queryExpansionCount   int
+
+	chunkReader              ports.ChunkReader
+	contextExpansion         domain.ContextExpansion
+	contextExpansionBySource map[string]domain.ContextExpansion
+	contextTokenBudget       int
 }
 
 func NewQueryUseCase(
@@ -85,6 +100,11 @@ func NewQueryUseCase(
 		rerankTopN:            rerankTopN,
 		queryExpansionEnabled: options.QueryExpansionEnabled,
 		queryExpansionCount:   expansionCount,
+
+		chunkReader:              options.ChunkReader,
+		contextExpansion:         options.ContextExpansion,
+		contextExpansionBySource: options.ContextExpansionBySource,
+		contextTokenBudget:       options.ContextTokenBudget,
 	}
 }
 
@@ -94,13 +114,60 @@ func (uc *QueryUseCase) Answer(
 	limit int,
 	filter domain.SearchFilter,
 ) (*domain.Answer, error) {
+	return uc.answer(ctx, question, limit, filter, false, nil)
+}
+
+// AnswerWithCitations asks the generator for inline [n] markers and resolves
+// them against the retrieved chunks into structured citations.
+func (uc *QueryUseCase) AnswerWithCitations(
+	ctx context.Context,
+	question string,
+	limit int,
+	filter domain.SearchFilter,
+) (*domain.Answer, error) {
+	return uc.answer(ctx, question, limit, filter, true, nil)
+}
+
+// StreamAnswer behaves like Answer but passes answer text to onDelta as the
+// generator produces it.
+func (uc *QueryUseCase) StreamAnswer(
+	ctx context.Context,
+	question string,
+	limit int,
+	filter domain.SearchFilter,
+	onDelta domain.AnswerDeltaCallback,
+) (*domain.Answer, error) {
+	return uc.answer(ctx, question, limit, filter, false, onDelta)
+}
+
+// StreamAnswerWithCitations streams the marked-up answer; citations are
+// resolved once the full text is known.
+func (uc *QueryUseCase) StreamAnswerWithCitations(
+	ctx context.Context,
+	question string,
+	limit int,
+	filter domain.SearchFilter,
+	onDelta domain.AnswerDeltaCallback,
+) (*domain.Answer, error) {
+	return uc.answer(ctx, question, limit, filter, true, onDelta)
+}
+
+// Retrieve returns the context chunks Answer would ground its answer in,
+// without generating one. Hits are expanded into context windows when
+// configured.
+func (uc *QueryUseCase) Retrieve(
+	ctx context.Context,
+	question string,
+	limit int,
+	filter domain.SearchFilter,
+) ([]domain.RetrievedChunk, domain.RetrievalMeta, error) {
 	if limit <= 0 {
 		limit = 5
 	}
 
 	chunks, meta, err := uc.retrieveChunks(ctx, question, limit, filter)
 	if err != nil {
-		return nil, err
+		return nil, domain.RetrievalMeta{}, err
 	}
 
 	// Graph retrieval boost — re-embed question to search graph-related docs.
@@ -111,26 +178,115 @@ func (uc *QueryUseCase) Answer(
 		}
 	}
 
+	chunks, meta.ExpandedChunks = uc.expandContext(ctx, chunks)
+	return chunks, meta, nil
+}
+
+// RetrievalConfig reports the effective retrieval settings.
+func (uc *QueryUseCase) RetrievalConfig() domain.RetrievalConfig {
+	expansion := uc.queryExpansionEnabled
+	return domain.RetrievalConfig{
+		Mode:             uc.retrievalMode,
+		HybridCandidates: uc.hybridCandidates,
+		FusionStrategy:   uc.fusionStrategy,
+		FusionRRFK:       uc.fusionRRFK,
+		RerankTopN:       uc.rerankTopN,
+		QueryExpansion:   &expansion,
+	}
+}
+
+// WithRetrievalConfig returns a copy of the use case that retrieves with cfg
+// applied over the current settings. The receiver is not modified.
+func (uc *QueryUseCase) WithRetrievalConfig(cfg domain.RetrievalConfig) *QueryUseCase {
+	clone := *uc
+	if cfg.Mode != "" {
+		clone.retrievalMode = normalizeRetrievalMode(cfg.Mode)
+	}
+	if cfg.HybridCandidates > 0 {
+		clone.hybridCandidates = cfg.HybridCandidates
+	}
+	if cfg.FusionStrategy != "" {
+		clone.fusionStrategy = normalizeFusionStrategy(cfg.FusionStrategy)
+	}
+	if cfg.FusionRRFK > 0 {
+		clone.fusionRRFK = cfg.FusionRRFK
+	}
+	if cfg.RerankTopN > 0 {
+		clone.rerankTopN = cfg.RerankTopN
+	}
+	if cfg.QueryExpansion != nil {
+		clone.queryExpansionEnabled = *cfg.QueryExpansion
+	}
+	return &clone
+}
+
+// answer retrieves context and generates the answer. A non-nil onDelta
+// switches generation to streaming mode.
+func (uc *QueryUseCase) answer(
+	ctx context.Context,
+	question string,
+	limit int,
+	filter domain.SearchFilter,
+	cited bool,
+	onDelta domain.AnswerDeltaCallback,
+) (*domain.Answer, error) {
+	chunks, meta, err := uc.Retrieve(ctx, question, limit, filter)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(chunks) == 0 {
+		emptyText := "В базе знаний пока нет проиндексированных документов. Загрузите документы через API или синхронизируйте Obsidian vault, затем повторите запрос."
+		if onDelta != nil {
+			onDelta(emptyText)
+		}
 		return &domain.Answer{
-			Text:      "В базе знаний пока нет проиндексированных документов. Загрузите документы через API или синхронизируйте Obsidian vault, затем повторите запрос.",
+			Text:      emptyText,
 			Sources:   chunks,
 			Retrieval: meta,
 		}, nil
 	}
 
-	answerText, err := uc.generator.GenerateAnswer(ctx, question, chunks)
-	if err != nil {
-		return nil, fmt.Errorf("generate answer: %w", err)
+	if !cited {
+		answerText, err := uc.generateAnswer(ctx, question, chunks, onDelta)
+		if err != nil {
+			return nil, fmt.Errorf("generate answer: %w", err)
+		}
+		return &domain.Answer{
+			Text:      answerText,
+			Sources:   chunks,
+			Retrieval: meta,
+		}, nil
 	}
 
+	answerText, err := uc.generateCitedAnswer(ctx, question, chunks, onDelta)
+	if err != nil {
+		return nil, fmt.Errorf("generate cited answer: %w", err)
+	}
+	sentences, citations := attributeCitations(answerText, chunks)
 	return &domain.Answer{
 		Text:      answerText,
 		Sources:   chunks,
 		Retrieval: meta,
+		Citations: citations,
+		Sentences: sentences,
 	}, nil
 }
 
+func (uc *QueryUseCase) generateAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	if onDelta != nil {
+		return uc.generator.StreamAnswer(ctx, question, chunks, onDelta)
+	}
+	return uc.generator.GenerateAnswer(ctx, question, chunks)
+}
+
+func (uc *QueryUseCase) generateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	if onDelta != nil {
+		return uc.generator.StreamCitedAnswer(ctx, question, chunks, onDelta)
+	}
+	return uc.generator.GenerateCitedAnswer(ctx, question, chunks)
+}
+
 func (uc *QueryUseCase) GenerateFromPrompt(ctx context.Context, prompt string) (string, error) {
 	answerText, err := uc.generator.GenerateFromPrompt(ctx, prompt)
 	if err != nil {
@@ -157,6 +313,8 @@ func (uc *QueryUseCase) retrieveChunks(
 	limit int,
 	filter domain.SearchFilter,
 ) ([]domain.RetrievedChunk, domain.RetrievalMeta, error) {
+	filter = scopeFilterToCaller(ctx, filter)
+
 	// Query expansion: generate alternative queries and merge results via RRF.
 	queries := []string{question}
 	if uc.queryExpansionEnabled {
@@ -408,7 +566,7 @@ func (uc *QueryUseCase) boostWithGraph(ctx context.Context, chunks []domain.Retr
 
 	// Fetch chunks from graph-related documents and merge with reduced score.
 	// Preserve all original filter fields so graph results respect the same constraints.
-	graphFilter := filter
+	graphFilter := scopeFilterToCaller(ctx, filter)
 	graphFilter.DocumentIDs = relatedIDs
 	graphChunks, err := uc.vectorDB.Search(ctx, queryVector, min(limit, len(relatedIDs)*2), graphFilter)
 	if err != nil {
@@ -427,6 +585,16 @@ func (uc *QueryUseCase) boostWithGraph(ctx context.Context, chunks []domain.Retr
 	return trimCandidates(merged, limit)
 }
 
+// scopeFilterToCaller restricts retrieval to documents the principal in ctx
+// may read. Calls without a principal (auth disabled, internal jobs) keep the
+// filter as given.
+func scopeFilterToCaller(ctx context.Context, filter domain.SearchFilter) domain.SearchFilter {
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		filter.AccessTokens = p.ReadScope()
+	}
+	return filter
+}
+
 func normalizeRetrievalMode(mode domain.RetrievalMode) domain.RetrievalMode {
 	switch strings.ToLower(string(mode)) {
 	case string(domain.RetrievalModeHybrid):
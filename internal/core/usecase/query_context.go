@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/tokenizer"
+)
+
+const (
+	defaultContextNeighbors = 1
+	defaultContextMaxChunks = 8
+	// minChunkOverlap is the shortest shared text treated as chunker overlap
+	// when joining adjacent chunks; shorter matches are coincidental.
+	minChunkOverlap = 16
+	maxChunkOverlap = 2000
+)
+
+// sectionHeadingRe matches the Markdown heading a MarkdownSplitter section
+// (and therefore its first chunk) starts with.
+var sectionHeadingRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+
+// contextWindow is a span of one document's chunks assembled around one or
+// more hits.
+type contextWindow struct {
+	hit        domain.RetrievedChunk // best-ranked hit inside the window
+	rank       int
+	start, end int
+}
+
+// expandContext widens each hit into its configured context window, merges
+// overlapping windows of the same document and fits the result into the
+// token budget. It returns the chunks and the number of chunks added. Without
+// expansion the hits pass through unchanged and unbudgeted.
+func (uc *QueryUseCase) expandContext(ctx context.Context, hits []domain.RetrievedChunk) ([]domain.RetrievedChunk, int) {
+	if len(hits) == 0 || !uc.contextExpansionEnabled() {
+		return hits, 0
+	}
+
+	texts := make(map[string]map[int]string) // doc_id → chunk_index → text
+	remember := func(c domain.RetrievedChunk) {
+		if texts[c.DocumentID] == nil {
+			texts[c.DocumentID] = make(map[int]string)
+		}
+		if _, ok := texts[c.DocumentID][c.ChunkIndex]; !ok {
+			texts[c.DocumentID][c.ChunkIndex] = c.Text
+		}
+	}
+
+	windows := make([]contextWindow, 0, len(hits))
+	for rank, hit := range hits {
+		remember(hit)
+		w := contextWindow{hit: hit, rank: rank, start: hit.ChunkIndex, end: hit.ChunkIndex}
+		exp := uc.contextExpansionFor(hit.SourceType)
+		if hit.DocumentID == "" || exp.Strategy == domain.ContextExpansionNone {
+			windows = append(windows, w)
+			continue
+		}
+
+		from, to := expansionRange(hit.ChunkIndex, exp)
+		stored, err := uc.chunkReader.ChunksByIndex(ctx, hit.DocumentID, hit.SourceType, from, to)
+		if err != nil {
+			slog.Warn("context_expansion_failed", "document_id", hit.DocumentID, "chunk_index", hit.ChunkIndex, "error", err)
+			windows = append(windows, w)
+			continue
+		}
+		for _, c := range stored {
+			remember(c)
+		}
+		w.start, w.end = expandWindow(texts[hit.DocumentID], hit.ChunkIndex, exp)
+		windows = append(windows, w)
+	}
+
+	merged := mergeContextWindows(windows)
+	out := make([]domain.RetrievedChunk, 0, len(merged))
+	windowParts := make(map[string]int, len(merged)) // chunk key → chunks joined
+	for _, w := range merged {
+		chunk := w.hit
+		if w.start != w.end {
+			parts := make([]string, 0, w.end-w.start+1)
+			for i := w.start; i <= w.end; i++ {
+				if text, ok := texts[chunk.DocumentID][i]; ok {
+					parts = append(parts, text)
+				}
+			}
+			chunk.Text = joinChunkTexts(parts)
+			chunk.Window = &domain.ChunkWindow{Start: w.start, End: w.end}
+			windowParts[retrievalChunkKey(chunk)] = len(parts)
+		}
+		out = append(out, chunk)
+	}
+
+	// Only windows that survived the budget count as added context.
+	out = applyContextTokenBudget(out, hits, uc.contextTokenBudget)
+	added := 0
+	for _, chunk := range out {
+		if chunk.Window != nil {
+			added += windowParts[retrievalChunkKey(chunk)] - 1
+		}
+	}
+	return out, added
+}
+
+// contextExpansionEnabled reports whether any source widens hits into
+// context windows.
+func (uc *QueryUseCase) contextExpansionEnabled() bool {
+	if uc.chunkReader == nil {
+		return false
+	}
+	if uc.contextExpansionFor("").Strategy != domain.ContextExpansionNone {
+		return true
+	}
+	for sourceType := range uc.contextExpansionBySource {
+		if uc.contextExpansionFor(sourceType).Strategy != domain.ContextExpansionNone {
+			return true
+		}
+	}
+	return false
+}
+
+func (uc *QueryUseCase) contextExpansionFor(sourceType string) domain.ContextExpansion {
+	exp := uc.contextExpansion
+	if byType, ok := uc.contextExpansionBySource[sourceType]; ok {
+		exp = byType
+	}
+	switch exp.Strategy {
+	case domain.ContextExpansionNeighbors, domain.ContextExpansionSection:
+	default:
+		exp.Strategy = domain.ContextExpansionNone
+	}
+	if exp.Neighbors <= 0 {
+		exp.Neighbors = defaultContextNeighbors
+	}
+	if exp.MaxChunks <= 0 {
+		exp.MaxChunks = defaultContextMaxChunks
+	}
+	return exp
+}
+
+// expansionRange is the chunk_index range to load for a hit.
+func expansionRange(idx int, exp domain.ContextExpansion) (int, int) {
+	if exp.Strategy == domain.ContextExpansionSection {
+		return max(idx-exp.MaxChunks+1, 0), idx + exp.MaxChunks - 1
+	}
+	return max(idx-exp.Neighbors, 0), idx + exp.Neighbors
+}
+
+// expandWindow picks the window around idx from the loaded chunk texts.
+// Windows never cross a missing chunk and are capped at MaxChunks.
+func expandWindow(texts map[int]string, idx int, exp domain.ContextExpansion) (int, int) {
+	start, end := idx, idx
+	if exp.Strategy == domain.ContextExpansionSection {
+		// Walk back to the chunk that opens the section, then forward until
+		// the next section starts.
+		for end-start+1 < exp.MaxChunks && !sectionHeadingRe.MatchString(texts[start]) {
+			if _, ok := texts[start-1]; !ok {
+				break
+			}
+			start--
+		}
+		for end-start+1 < exp.MaxChunks {
+			next, ok := texts[end+1]
+			if !ok || sectionHeadingRe.MatchString(next) {
+				break
+			}
+			end++
+		}
+		return start, end
+	}
+
+	for i := 1; i <= exp.Neighbors && end-start+1 < exp.MaxChunks; i++ {
+		if _, ok := texts[start-1]; ok {
+			start--
+		}
+		if _, ok := texts[end+1]; ok && end-start+1 < exp.MaxChunks {
+			end++
+		}
+	}
+	return start, end
+}
+
+// mergeContextWindows joins overlapping or adjacent windows of the same
+// document, keeping the best-ranked hit, and orders the result by rank.
+func mergeContextWindows(windows []contextWindow) []contextWindow {
+	sorted := append([]contextWindow(nil), windows...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].hit.DocumentID != sorted[j].hit.DocumentID {
+			return sorted[i].hit.DocumentID < sorted[j].hit.DocumentID
+		}
+		return sorted[i].start < sorted[j].start
+	})
+
+	merged := make([]contextWindow, 0, len(sorted))
+	for _, w := range sorted {
+		n := len(merged)
+		if n > 0 && w.hit.DocumentID != "" && merged[n-1].hit.DocumentID == w.hit.DocumentID && w.start <= merged[n-1].end+1 {
+			last := &merged[n-1]
+			last.end = max(last.end, w.end)
+			if w.rank < last.rank {
+				last.hit, last.rank = w.hit, w.rank
+			}
+			continue
+		}
+		merged = append(merged, w)
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].rank < merged[j].rank })
+	return merged
+}
+
+// joinChunkTexts concatenates consecutive chunks, dropping the text a chunk
+// repeats from its predecessor because of chunker overlap.
+func joinChunkTexts(parts []string) string {
+	var sb strings.Builder
+	for i, part := range parts {
+		if i == 0 {
+			sb.WriteString(part)
+			continue
+		}
+		if k := chunkOverlap(parts[i-1], part); k > 0 {
+			sb.WriteString(part[k:])
+			continue
+		}
+		sb.WriteString("\n")
+		sb.WriteString(part)
+	}
+	return sb.String()
+}
+
+// chunkOverlap returns the byte length of the longest prefix of next that
+// prev ends with, or 0 when it is shorter than minChunkOverlap runes.
+func chunkOverlap(prev, next string) int {
+	limit := min(len(prev), len(next), maxChunkOverlap)
+	for k := limit; k > 0; k-- {
+		if k < len(next) && !utf8.RuneStart(next[k]) {
+			continue
+		}
+		if strings.HasSuffix(prev, next[:k]) {
+			if utf8.RuneCountInString(next[:k]) < minChunkOverlap {
+				return 0
+			}
+			return k
+		}
+	}
+	return 0
+}
+
+// applyContextTokenBudget keeps chunks in order while they fit in budget
+// tokens. An expanded window that does not fit falls back to its matched
+// chunk from hits; the first chunk is always kept. budget <= 0 disables it.
+func applyContextTokenBudget(chunks, hits []domain.RetrievedChunk, budget int) []domain.RetrievedChunk {
+	if budget <= 0 {
+		return chunks
+	}
+	original := make(map[string]domain.RetrievedChunk, len(hits))
+	for _, h := range hits {
+		original[retrievalChunkKey(h)] = h
+	}
+
+	out := make([]domain.RetrievedChunk, 0, len(chunks))
+	used := 0
+	for _, c := range chunks {
+		cost := tokenizer.CountTokens(c.Text)
+		if used+cost > budget && c.Window != nil {
+			if h, ok := original[retrievalChunkKey(c)]; ok {
+				c, cost = h, tokenizer.CountTokens(h.Text)
+			}
+		}
+		if used+cost > budget && len(out) > 0 {
+			continue
+		}
+		out = append(out, c)
+		used += cost
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type chunkReaderFake struct {
+	docs  map[string][]string // doc_id → chunk texts by index
+	calls int
+}
+
+func (f *chunkReaderFake) ChunksByIndex(_ context.Context, docID string, _ string, from, to int) ([]domain.RetrievedChunk, error) {
+	f.calls++
+	var out []domain.RetrievedChunk
+	for i, text := range f.docs[docID] {
+		if i >= from && i <= to {
+			out = append(out, domain.RetrievedChunk{DocumentID: docID, ChunkIndex: i, Text: text})
+		}
+	}
+	return out, nil
+}
+
+func newContextQueryUseCase(reader *chunkReaderFake, exp domain.ContextExpansion, bySource map[string]domain.ContextExpansion, budget int, hits []domain.RetrievedChunk) *QueryUseCase {
+	return NewQueryUseCase(&queryEmbedderFake{}, &queryVectorFake{semanticResponse: hits}, &queryGeneratorFake{}, QueryOptions{
+		ChunkReader:              reader,
+		ContextExpansion:         exp,
+		ContextExpansionBySource: bySource,
+		ContextTokenBudget:       budget,
+	})
+}
+
+func TestRetrieveExpandsNeighborsAndMergesWindows(t *testing.T) {
+	reader := &chunkReaderFake{docs: map[string][]string{
+		"doc-1": {"c0", "c1", "c2", "c3", "c4", "c5"},
+	}}
+	hits := []domain.RetrievedChunk{
+		{DocumentID: "doc-1", ChunkIndex: 3, Text: "c3", Score: 0.9},
+		{DocumentID: "doc-1", ChunkIndex: 1, Text: "c1", Score: 0.8},
+	}
+	uc := newContextQueryUseCase(reader, domain.ContextExpansion{Strategy: domain.ContextExpansionNeighbors, Neighbors: 1}, nil, 0, hits)
+
+	chunks, meta, err := uc.Retrieve(context.Background(), "q", 5, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("overlapping windows must merge into one, got %d: %+v", len(chunks), chunks)
+	}
+	got := chunks[0]
+	if got.ChunkIndex != 3 || got.Window == nil || got.Window.Start != 0 || got.Window.End != 4 {
+		t.Fatalf("unexpected window: index=%d window=%+v", got.ChunkIndex, got.Window)
+	}
+	if got.Text != "c0\nc1\nc2\nc3\nc4" {
+		t.Fatalf("unexpected text %q", got.Text)
+	}
+	if meta.ExpandedChunks != 4 {
+		t.Fatalf("expected 4 expanded chunks, got %d", meta.ExpandedChunks)
+	}
+}
+
+func TestRetrieveExpandsMarkdownSectionPerSourceType(t *testing.T) {
+	reader := &chunkReaderFake{docs: map[string][]string{
+		"note": {"# Intro\nhello", "## Setup\nstep one", "step two", "step three", "## Usage\nrun it"},
+		"file": {"a", "b", "c"},
+	}}
+	hits := []domain.RetrievedChunk{
+		{DocumentID: "note", SourceType: "obsidian", ChunkIndex: 2, Text: "step two", Score: 0.9},
+		{DocumentID: "file", SourceType: "upload", ChunkIndex: 1, Text: "b", Score: 0.8},
+	}
+	bySource := map[string]domain.ContextExpansion{"obsidian": {Strategy: domain.ContextExpansionSection}}
+	uc := newContextQueryUseCase(reader, domain.ContextExpansion{Strategy: domain.ContextExpansionNone}, bySource, 0, hits)
+
+	chunks, _, err := uc.Retrieve(context.Background(), "q", 5, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].Text != "## Setup\nstep one\nstep two\nstep three" {
+		t.Fatalf("unexpected section text %q", chunks[0].Text)
+	}
+	if chunks[1].Text != "b" || chunks[1].Window != nil || reader.calls != 1 {
+		t.Fatalf("upload source must not be expanded: %+v calls=%d", chunks[1], reader.calls)
+	}
+}
+
+func TestRetrieveContextTokenBudgetShrinksWindows(t *testing.T) {
+	long := strings.Repeat("word ", 80) // ~100 tokens
+	reader := &chunkReaderFake{docs: map[string][]string{
+		"doc-1": {long, "hit one", long},
+		"doc-2": {"hit two"},
+	}}
+	hits := []domain.RetrievedChunk{
+		{DocumentID: "doc-1", ChunkIndex: 1, Text: "hit one", Score: 0.9},
+		{DocumentID: "doc-2", ChunkIndex: 0, Text: "hit two", Score: 0.8},
+	}
+	uc := newContextQueryUseCase(reader, domain.ContextExpansion{Strategy: domain.ContextExpansionNeighbors}, nil, 50, hits)
+
+	chunks, _, err := uc.Retrieve(context.Background(), "q", 5, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(chunks) != 2 || chunks[0].Text != "hit one" || chunks[0].Window != nil || chunks[1].Text != "hit two" {
+		t.Fatalf("over-budget window must fall back to the hit: %+v", chunks)
+	}
+}
+
+func TestRetrieveContextTokenBudgetCountsOnlyKeptWindows(t *testing.T) {
+	long := strings.Repeat("word ", 80) // ~100 tokens
+	reader := &chunkReaderFake{docs: map[string][]string{
+		"doc-1": {"a", "hit one", "b"},
+		"doc-2": {long, "hit two", long},
+	}}
+	hits := []domain.RetrievedChunk{
+		{DocumentID: "doc-1", ChunkIndex: 1, Text: "hit one", Score: 0.9},
+		{DocumentID: "doc-2", ChunkIndex: 1, Text: "hit two", Score: 0.8},
+	}
+	uc := newContextQueryUseCase(reader, domain.ContextExpansion{Strategy: domain.ContextExpansionNeighbors}, nil, 50, hits)
+
+	chunks, meta, err := uc.Retrieve(context.Background(), "q", 5, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(chunks) != 2 || chunks[0].Window == nil || chunks[1].Window != nil {
+		t.Fatalf("expected only the first window to fit: %+v", chunks)
+	}
+	if meta.ExpandedChunks != 2 {
+		t.Fatalf("expected 2 expanded chunks from the kept window, got %d", meta.ExpandedChunks)
+	}
+}
+
+func TestRetrieveWithoutExpansionIgnoresTokenBudget(t *testing.T) {
+	long := strings.Repeat("word ", 80) // ~100 tokens
+	hits := []domain.RetrievedChunk{
+		{DocumentID: "doc-1", ChunkIndex: 0, Text: long, Score: 0.9},
+		{DocumentID: "doc-2", ChunkIndex: 0, Text: long, Score: 0.8},
+	}
+	uc := newContextQueryUseCase(&chunkReaderFake{}, domain.ContextExpansion{}, nil, 50, hits)
+
+	chunks, _, err := uc.Retrieve(context.Background(), "q", 5, domain.SearchFilter{})
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected hits to pass through without expansion, got %d", len(chunks))
+	}
+}
+
+func TestJoinChunkTextsDropsChunkerOverlap(t *testing.T) {
+	prev := "Первый абзац заканчивается общей частью текста"
+	next := "общей частью текста и продолжается дальше"
+	if got := joinChunkTexts([]string{prev, next}); got != "Первый абзац заканчивается общей частью текста и продолжается дальше" {
+		t.Fatalf("unexpected join %q", got)
+	}
+}
@@ -34,6 +34,7 @@ type queryVectorFake struct {
 	semanticResponse []domain.RetrievedChunk
 	lexicalResponse  []domain.RetrievedChunk
 	lastFilter       domain.SearchFilter
+	lastLexFilter    domain.SearchFilter
 }
 
 func (f *queryVectorFake) IndexChunks(context.Context, *domain.Document, []string, [][]float32) error {
@@ -53,9 +54,10 @@ func (f *queryVectorFake) Search(_ context.Context, _ []float32, limit int, filt
 	return []domain.RetrievedChunk{{DocumentID: "doc-1", ChunkIndex: 0, Text: "chunk", Score: 0.9}}, nil
 }
 
-func (f *queryVectorFake) SearchLexical(_ context.Context, _ string, limit int, _ domain.SearchFilter) ([]domain.RetrievedChunk, error) {
+func (f *queryVectorFake) SearchLexical(_ context.Context, _ string, limit int, filter domain.SearchFilter) ([]domain.RetrievedChunk, error) {
 	f.searchLexCalls++
 	f.lexicalLimit = limit
+	f.lastLexFilter = filter
 	if f.lexicalErr != nil {
 		return nil, f.lexicalErr
 	}
@@ -68,9 +70,13 @@ func (f *queryVectorFake) SearchLexical(_ context.Context, _ string, limit int,
 func (f *queryVectorFake) UpdateChunksPayload(context.Context, string, string, map[string]any) error {
 	return nil
 }
+func (f *queryVectorFake) DeleteByDocumentID(context.Context, string) error {
+	return nil
+}
 
 type queryGeneratorFake struct {
-	err error
+	err   error
+	cited string
 }
 
 func (f *queryGeneratorFake) GenerateAnswer(context.Context, string, []domain.RetrievedChunk) (string, error) {
@@ -79,6 +85,29 @@ func (f *queryGeneratorFake) GenerateAnswer(context.Context, string, []domain.Re
 	}
 	return "answer", nil
 }
+func (f *queryGeneratorFake) GenerateCitedAnswer(context.Context, string, []domain.RetrievedChunk) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	return f.cited, nil
+}
+func (f *queryGeneratorFake) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	out, err := f.GenerateAnswer(ctx, question, chunks)
+	if err != nil {
+		return "", err
+	}
+	onDelta(out[:3])
+	onDelta(out[3:])
+	return out, nil
+}
+func (f *queryGeneratorFake) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	out, err := f.GenerateCitedAnswer(ctx, question, chunks)
+	if err != nil {
+		return "", err
+	}
+	onDelta(out)
+	return out, nil
+}
 func (f *queryGeneratorFake) GenerateFromPrompt(_ context.Context, prompt string) (string, error) {
 	return prompt, nil
 }
@@ -112,6 +141,43 @@ func TestQueryUseCaseAnswerDefaultLimit(t *testing.T) {
 	}
 }
 
+func TestQueryUseCaseStreamAnswerForwardsDeltas(t *testing.T) {
+	uc := NewQueryUseCase(&queryEmbedderFake{}, &queryVectorFake{}, &queryGeneratorFake{}, QueryOptions{})
+
+	var deltas []string
+	answer, err := uc.StreamAnswer(context.Background(), "q", 3, domain.SearchFilter{}, func(text string) {
+		deltas = append(deltas, text)
+	})
+	if err != nil {
+		t.Fatalf("StreamAnswer() error = %v", err)
+	}
+	if len(deltas) != 2 || deltas[0]+deltas[1] != "answer" {
+		t.Fatalf("expected generator deltas, got %q", deltas)
+	}
+	if answer.Text != "answer" || len(answer.Sources) == 0 {
+		t.Fatalf("unexpected answer %+v", answer)
+	}
+}
+
+func TestQueryUseCaseStreamAnswerWithCitationsResolvesMarkers(t *testing.T) {
+	generator := &queryGeneratorFake{cited: "Fact from the first chunk [1]."}
+	uc := NewQueryUseCase(&queryEmbedderFake{}, &queryVectorFake{}, generator, QueryOptions{})
+
+	var streamed strings.Builder
+	answer, err := uc.StreamAnswerWithCitations(context.Background(), "q", 3, domain.SearchFilter{}, func(text string) {
+		streamed.WriteString(text)
+	})
+	if err != nil {
+		t.Fatalf("StreamAnswerWithCitations() error = %v", err)
+	}
+	if streamed.String() != generator.cited {
+		t.Fatalf("expected cited text to be streamed, got %q", streamed.String())
+	}
+	if len(answer.Citations) != 1 || len(answer.Sentences) != 1 {
+		t.Fatalf("expected citations resolved after streaming, got %+v", answer)
+	}
+}
+
 func TestQueryUseCaseAnswerEmbedError(t *testing.T) {
 	uc := NewQueryUseCase(&queryEmbedderFake{err: errors.New("embed fail")}, &queryVectorFake{}, &queryGeneratorFake{}, QueryOptions{})
 	_, err := uc.Answer(context.Background(), "q", 3, domain.SearchFilter{})
@@ -152,6 +218,35 @@ func TestQueryUseCaseHybridUsesBothBranchesAndCandidateLimit(t *testing.T) {
 	}
 }
 
+func TestQueryUseCaseScopesRetrievalToCaller(t *testing.T) {
+	vector := &queryVectorFake{}
+	uc := NewQueryUseCase(&queryEmbedderFake{}, vector, &queryGeneratorFake{}, QueryOptions{
+		RetrievalMode: domain.RetrievalModeHybrid,
+	})
+
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice", Groups: []string{"team"}})
+	if _, err := uc.Answer(ctx, "q", 5, domain.SearchFilter{}); err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	want := []string{"*", "user:alice", "group:team"}
+	for name, got := range map[string][]string{
+		"semantic": vector.lastFilter.AccessTokens,
+		"lexical":  vector.lastLexFilter.AccessTokens,
+	} {
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%s search: expected access tokens %v, got %v", name, want, got)
+		}
+	}
+
+	admin := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "root", Admin: true})
+	if _, err := uc.Answer(admin, "q", 5, domain.SearchFilter{}); err != nil {
+		t.Fatalf("Answer() error = %v", err)
+	}
+	if vector.lastFilter.AccessTokens != nil || vector.lastLexFilter.AccessTokens != nil {
+		t.Fatalf("admin retrieval must be unrestricted, got %v / %v", vector.lastFilter.AccessTokens, vector.lastLexFilter.AccessTokens)
+	}
+}
+
 func TestQueryUseCaseHybridRerankAppliesRerank(t *testing.T) {
 	vector := &queryVectorFake{
 		semanticResponse: []domain.RetrievedChunk{
@@ -206,6 +301,7 @@ func (f *graphStoreWithRelated) AddSimilarity(context.Context, string, string, f
 	return nil
 }
 func (f *graphStoreWithRelated) RemoveSimilarities(context.Context, string) error { return nil }
+func (f *graphStoreWithRelated) DeleteDocument(context.Context, string) error     { return nil }
 func (f *graphStoreWithRelated) GetRelated(_ context.Context, docID string, _ int, _ int) ([]domain.GraphRelation, error) {
 	if f.relatedErr != nil {
 		return nil, f.relatedErr
@@ -455,6 +551,12 @@ func TestBoostWithGraph_PreservesFilterFields(t *testing.T) {
 	if len(vector.lastFilter.SourceTypes) == 0 || vector.lastFilter.SourceTypes[0] != "obsidian" {
 		t.Fatalf("expected filter to preserve SourceTypes, got %v", vector.lastFilter.SourceTypes)
 	}
+
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+	uc.boostWithGraph(ctx, original, 5, filter, []float32{0.1, 0.2})
+	if len(vector.lastFilter.AccessTokens) != 2 || vector.lastFilter.AccessTokens[1] != "user:alice" {
+		t.Fatalf("expected graph search scoped to alice, got %v", vector.lastFilter.AccessTokens)
+	}
 }
 
 func TestBoostWithGraph_EmptyChunks(t *testing.T) {
@@ -24,10 +24,12 @@ const (
 
 // SchedulerUseCase handles cron-based task execution with conditional logic and webhooks.
 type SchedulerUseCase struct {
-	store     ports.ScheduleStore
-	agentChat ports.AgentChatService
-	generator ports.AnswerGenerator
-	parser    cron.Parser
+	store      ports.ScheduleStore
+	agentChat  ports.AgentChatService
+	generator  ports.AnswerGenerator
+	jobs       ports.AgentJobService
+	principals ports.PrincipalResolver
+	parser     cron.Parser
 }
 
 // NewSchedulerUseCase constructs a SchedulerUseCase with a standard cron parser.
@@ -46,6 +48,18 @@ func NewSchedulerUseCase(
 	}
 }
 
+// SetAgentJobs makes due tasks run as agent jobs on the worker instead of
+// inside the scheduler. Results are then recorded by RecordJobRun.
+func (s *SchedulerUseCase) SetAgentJobs(jobs ports.AgentJobService) {
+	s.jobs = jobs
+}
+
+// SetPrincipals makes tasks run inside the scheduler with the groups of
+// their owner.
+func (s *SchedulerUseCase) SetPrincipals(principals ports.PrincipalResolver) {
+	s.principals = principals
+}
+
 // Tick checks all enabled scheduled tasks and fires those that are due.
 // It should be called periodically (e.g., every minute).
 func (s *SchedulerUseCase) Tick(ctx context.Context) {
@@ -90,6 +104,20 @@ func (s *SchedulerUseCase) isDue(task domain.ScheduledTask, now time.Time) bool
 
 // executeTask optionally checks a condition, runs the agent prompt, records the result, and sends a webhook.
 func (s *SchedulerUseCase) executeTask(ctx context.Context, task domain.ScheduledTask) {
+	// A job that outlasts the cron interval keeps the task due; wait for it
+	// instead of queueing a second run alongside.
+	if s.jobs != nil {
+		busy, err := s.jobs.HasUnfinishedScheduleJob(ctx, task.ID)
+		if err != nil {
+			log.Printf("[scheduler] unfinished job check error for task %s: %v", task.ID, err)
+			return
+		}
+		if busy {
+			log.Printf("[scheduler] previous agent job still unfinished, skipping task %s", task.ID)
+			return
+		}
+	}
+
 	// Conditional guard: skip execution when condition evaluates to false.
 	if task.Condition != "" {
 		condCtx, cancel := context.WithTimeout(ctx, schedulerConditionTimeout)
@@ -111,6 +139,11 @@ func (s *SchedulerUseCase) executeTask(ctx context.Context, task domain.Schedule
 		}
 	}
 
+	if s.jobs != nil {
+		s.submitJob(ctx, task)
+		return
+	}
+
 	// Execute the prompt via agent.
 	req := domain.AgentChatRequest{
 		UserID: task.UserID,
@@ -119,7 +152,16 @@ func (s *SchedulerUseCase) executeTask(ctx context.Context, task domain.Schedule
 		},
 	}
 
-	result, err := s.agentChat.Complete(ctx, req, nil)
+	// Run as the task owner so retrieval only sees documents they can read.
+	runCtx := ctx
+	var err error
+	if task.UserID != "" {
+		runCtx, err = withOwnerPrincipal(ctx, s.principals, task.UserID)
+	}
+	var result *domain.AgentRunResult
+	if err == nil {
+		result, err = s.agentChat.Complete(runCtx, req, nil)
+	}
 	var runResult string
 	var runStatus string
 	if err != nil {
@@ -142,6 +184,60 @@ func (s *SchedulerUseCase) executeTask(ctx context.Context, task domain.Schedule
 	}
 }
 
+// submitJob queues the task prompt as an agent job and records the run as
+// running. executeTask skips the task until the job finishes, when
+// RecordJobRun records its result.
+func (s *SchedulerUseCase) submitJob(ctx context.Context, task domain.ScheduledTask) {
+	job, err := s.jobs.Submit(ctx, domain.AgentJobRequest{
+		UserID:     task.UserID,
+		Prompt:     task.Prompt,
+		Source:     domain.AgentJobSourceSchedule,
+		ScheduleID: task.ID,
+	})
+	if err != nil {
+		log.Printf("[scheduler] submit agent job error for task %s: %v", task.ID, err)
+		if recordErr := s.store.RecordRun(ctx, task.ID, fmt.Sprintf("error: %v", err), "error"); recordErr != nil {
+			log.Printf("[scheduler] RecordRun error for task %s: %v", task.ID, recordErr)
+		}
+		return
+	}
+	if recordErr := s.store.RecordRun(ctx, task.ID, fmt.Sprintf("agent job %s queued", job.ID), "running"); recordErr != nil {
+		log.Printf("[scheduler] RecordRun error for task %s: %v", task.ID, recordErr)
+	}
+}
+
+// RecordJobRun records the result of an agent job submitted for a scheduled
+// task and sends the task webhook. Jobs of other sources are ignored.
+func (s *SchedulerUseCase) RecordJobRun(ctx context.Context, job domain.AgentJob) {
+	if job.ScheduleID == "" {
+		return
+	}
+	var runResult, runStatus string
+	switch job.Status {
+	case domain.AgentJobStatusCompleted:
+		runResult = truncateScheduleResult(job.Answer, scheduleMaxResultLen)
+		runStatus = "success"
+	case domain.AgentJobStatusCanceled:
+		runResult = fmt.Sprintf("agent job %s canceled", job.ID)
+		runStatus = "canceled"
+	default:
+		runResult = fmt.Sprintf("error: %s", job.Error)
+		runStatus = "error"
+	}
+	if recordErr := s.store.RecordRun(ctx, job.ScheduleID, runResult, runStatus); recordErr != nil {
+		log.Printf("[scheduler] RecordRun error for task %s: %v", job.ScheduleID, recordErr)
+	}
+
+	task, err := s.store.GetByID(ctx, job.ScheduleID)
+	if err != nil {
+		log.Printf("[scheduler] GetByID error for task %s: %v", job.ScheduleID, err)
+		return
+	}
+	if task.WebhookURL != "" {
+		s.sendWebhook(*task, runResult)
+	}
+}
+
 // evaluateCondition asks the LLM to evaluate the condition and returns true for a "yes" answer.
 func (s *SchedulerUseCase) evaluateCondition(ctx context.Context, condition string) (bool, error) {
 	prompt := fmt.Sprintf(
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -51,6 +53,60 @@ func TestCronShouldNotRun(t *testing.T) {
 	}
 }
 
+// scheduleStoreFake records the runs of scheduled tasks.
+type scheduleStoreFake struct {
+	mu   sync.Mutex
+	runs []string
+}
+
+func (f *scheduleStoreFake) Create(context.Context, *domain.ScheduledTask) error { return nil }
+func (f *scheduleStoreFake) ListByUser(context.Context, string) ([]domain.ScheduledTask, error) {
+	return nil, nil
+}
+func (f *scheduleStoreFake) ListEnabled(context.Context) ([]domain.ScheduledTask, error) {
+	return nil, nil
+}
+func (f *scheduleStoreFake) GetByID(_ context.Context, id string) (*domain.ScheduledTask, error) {
+	return &domain.ScheduledTask{ID: id}, nil
+}
+func (f *scheduleStoreFake) Update(context.Context, *domain.ScheduledTask) error { return nil }
+func (f *scheduleStoreFake) Delete(context.Context, string) error                { return nil }
+func (f *scheduleStoreFake) RecordRun(_ context.Context, _ string, _ string, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.runs = append(f.runs, status)
+	return nil
+}
+
+// TestSchedulerSkipsTaskWhileJobUnfinished verifies that a job outlasting the
+// cron interval is not joined by a second one.
+func TestSchedulerSkipsTaskWhileJobUnfinished(t *testing.T) {
+	jobStore := newAgentJobStoreFake()
+	jobs := NewAgentJobUseCase(jobStore, &agentJobQueueFake{}, &agentJobAgentFake{}, AgentJobOptions{})
+	schedules := &scheduleStoreFake{}
+	s := newTestScheduler()
+	s.store = schedules
+	s.SetAgentJobs(jobs)
+
+	task := domain.ScheduledTask{ID: "task-1", UserID: "alice", Prompt: "daily digest", CronExpr: "* * * * *"}
+	s.executeTask(context.Background(), task)
+	s.executeTask(context.Background(), task)
+	if len(jobStore.jobs) != 1 {
+		t.Fatalf("expected one job while the first is unfinished, got %d", len(jobStore.jobs))
+	}
+
+	for _, job := range jobStore.jobs {
+		job.Status = domain.AgentJobStatusCompleted
+	}
+	s.executeTask(context.Background(), task)
+	if len(jobStore.jobs) != 2 {
+		t.Fatalf("expected a new job once the first finished, got %d", len(jobStore.jobs))
+	}
+	if len(schedules.runs) != 2 {
+		t.Fatalf("skipped ticks must not record runs, got %v", schedules.runs)
+	}
+}
+
 // TestTruncateScheduleResult verifies that the helper truncates long strings correctly.
 func TestTruncateScheduleResult(t *testing.T) {
 	tests := []struct {
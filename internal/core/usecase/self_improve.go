@@ -18,6 +18,7 @@ type SelfImproveUseCase struct {
 	improvements ports.ImprovementStore
 	generator    ports.AnswerGenerator
 	autoApply    bool
+	evalCases    ports.EvalService
 }
 
 func NewSelfImproveUseCase(
@@ -33,6 +34,12 @@ func NewSelfImproveUseCase(
 	}
 }
 
+// SetEvalCases makes applied eval_case improvements land in the eval case
+// store instead of only being marked applied.
+func (uc *SelfImproveUseCase) SetEvalCases(s ports.EvalService) {
+	uc.evalCases = s
+}
+
 func (uc *SelfImproveUseCase) Analyze(ctx context.Context, since time.Time) error {
 	eventCounts, err := uc.events.CountByType(ctx, since)
 	if err != nil {
@@ -81,6 +88,10 @@ func (uc *SelfImproveUseCase) Analyze(ctx context.Context, since time.Time) erro
 			continue
 		}
 		if uc.autoApply && domain.AutoApplyCategories[improvements[i].Category] {
+			if err := uc.apply(ctx, improvements[i]); err != nil {
+				slog.Warn("self_improve_apply_failed", "category", improvements[i].Category, "error", err)
+				continue
+			}
 			slog.Info("self_improve_auto_apply", "category", improvements[i].Category, "description", improvements[i].Description)
 			_ = uc.improvements.MarkApplied(ctx, improvements[i].ID)
 		}
@@ -90,6 +101,41 @@ func (uc *SelfImproveUseCase) Analyze(ctx context.Context, since time.Time) erro
 	return nil
 }
 
+// apply carries out the parts of an improvement that have a real target.
+// Categories without one are only marked applied.
+func (uc *SelfImproveUseCase) apply(ctx context.Context, imp domain.AgentImprovement) error {
+	if imp.Category != domain.ImproveCategoryEvalCase || uc.evalCases == nil {
+		return nil
+	}
+	c, err := evalCaseFromAction(imp.Action)
+	if err != nil {
+		return err
+	}
+	_, err = uc.evalCases.SaveCases(ctx, []domain.EvalCase{c})
+	return err
+}
+
+// evalCaseFromAction reads an eval_case action, which uses the same field
+// names as /v1/eval/cases. The proposed case always gets a fresh ID so the
+// LLM cannot overwrite existing cases.
+func evalCaseFromAction(action map[string]any) (domain.EvalCase, error) {
+	raw, err := json.Marshal(action)
+	if err != nil {
+		return domain.EvalCase{}, fmt.Errorf("marshal eval_case action: %w", err)
+	}
+	var c domain.EvalCase
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return domain.EvalCase{}, fmt.Errorf("decode eval_case action: %w", err)
+	}
+	return domain.EvalCase{
+		Question:            c.Question,
+		ExpectedDocumentIDs: c.ExpectedDocumentIDs,
+		ExpectedFilenames:   c.ExpectedFilenames,
+		GroundTruth:         c.GroundTruth,
+		Source:              domain.EvalCaseSourceSelfImprove,
+	}, nil
+}
+
 func buildSelfImprovePrompt(eventCounts, ratingCounts map[string]int, comments []string) string {
 	var sb strings.Builder
 	sb.WriteString("Analyze these agent performance metrics and suggest specific improvements.\n\nError summary:\n")
@@ -110,6 +156,7 @@ func buildSelfImprovePrompt(eventCounts, ratingCounts map[string]int, comments [
 		}
 	}
 	sb.WriteString("\nReturn ONLY a JSON array: [{\"category\":\"system_prompt|intent_keywords|model_routing|reindex_document|eval_case|add_document\",\"description\":\"...\",\"action\":{}}]\n")
+	sb.WriteString("For eval_case the action is {\"question\":\"...\",\"expected_filenames\":[\"...\"],\"ground_truth\":\"...\"}.\n")
 	return sb.String()
 }
 
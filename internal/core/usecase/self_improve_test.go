@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"testing"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
@@ -54,3 +55,37 @@ func TestParseSelfImprovements_WithWrapper(t *testing.T) {
 		t.Fatalf("expected 1, got %d", len(imps))
 	}
 }
+
+func TestApplyEvalCaseImprovementStoresCase(t *testing.T) {
+	evals, store, _ := newEvalFixture()
+	uc := NewSelfImproveUseCase(nil, nil, nil, nil, true)
+	uc.SetEvalCases(evals)
+
+	err := uc.apply(context.Background(), domain.AgentImprovement{
+		Category: domain.ImproveCategoryEvalCase,
+		Action: map[string]any{
+			"id":                 "EX001",
+			"question":           "How do I rotate the API key?",
+			"expected_filenames": []any{"keys.md"},
+			"ground_truth":       "Use the admin panel.",
+		},
+	})
+	if err != nil {
+		t.Fatalf("apply() error = %v", err)
+	}
+	if len(store.cases) != 2 {
+		t.Fatalf("expected a new case next to EX001, got %+v", store.cases)
+	}
+	added := store.cases[1]
+	if added.ID == "EX001" || added.Source != domain.EvalCaseSourceSelfImprove || added.ExpectedFilenames[0] != "keys.md" {
+		t.Fatalf("unexpected case: %+v", added)
+	}
+
+	err = uc.apply(context.Background(), domain.AgentImprovement{
+		Category: domain.ImproveCategoryEvalCase,
+		Action:   map[string]any{"question": "No expectations"},
+	})
+	if !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("apply() error = %v, want invalid input", err)
+	}
+}
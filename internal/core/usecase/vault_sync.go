@@ -0,0 +1,843 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// VaultSyncOptions tunes vault synchronisation.
+type VaultSyncOptions struct {
+	// VaultsRoot confines vault paths; relative paths are resolved against it
+	// and new sub-directories are auto-registered as vaults.
+	VaultsRoot             string
+	DefaultIntervalMinutes int
+	// SyncTimeout bounds how long a sync waits for one note to become ready.
+	SyncTimeout time.Duration
+	SyncPoll    time.Duration
+	// RefreshInterval is how often Run re-reads the vault list.
+	RefreshInterval time.Duration
+}
+
+// VaultSyncUseCase owns Obsidian vault registration and keeps vault notes in
+// sync with the document index. State lives behind VaultStore and
+// VaultSyncStateStore so any API replica or the worker can run syncs.
+type VaultSyncUseCase struct {
+	vaults   ports.VaultStore
+	state    ports.VaultSyncStateStore
+	ingestor ports.DocumentIngestor
+	docs     ports.DocumentReader
+	deleter  ports.DocumentDeleter
+	watcher  ports.DirectoryWatcher
+	opts     VaultSyncOptions
+
+	refresh chan struct{}
+}
+
+// NewVaultSyncUseCase wires a VaultSyncUseCase. watcher may be nil, in which
+// case Run only performs periodic full syncs.
+func NewVaultSyncUseCase(
+	vaults ports.VaultStore,
+	state ports.VaultSyncStateStore,
+	ingestor ports.DocumentIngestor,
+	docs ports.DocumentReader,
+	deleter ports.DocumentDeleter,
+	watcher ports.DirectoryWatcher,
+	opts VaultSyncOptions,
+) *VaultSyncUseCase {
+	if opts.DefaultIntervalMinutes <= 0 {
+		opts.DefaultIntervalMinutes = 15
+	}
+	if opts.SyncTimeout <= 0 {
+		opts.SyncTimeout = 120 * time.Second
+	}
+	if opts.SyncPoll <= 0 {
+		opts.SyncPoll = 2 * time.Second
+	}
+	if opts.RefreshInterval <= 0 {
+		opts.RefreshInterval = time.Minute
+	}
+	return &VaultSyncUseCase{
+		vaults:   vaults,
+		state:    state,
+		ingestor: ingestor,
+		docs:     docs,
+		deleter:  deleter,
+		watcher:  watcher,
+		opts:     opts,
+		refresh:  make(chan struct{}, 1),
+	}
+}
+
+// DefaultIntervalMinutes is the full-sync interval for vaults without their own.
+func (uc *VaultSyncUseCase) DefaultIntervalMinutes() int {
+	return uc.opts.DefaultIntervalMinutes
+}
+
+// ListVaults returns registered vaults, registering any new directory found
+// under the vaults root first.
+func (uc *VaultSyncUseCase) ListVaults(ctx context.Context) ([]domain.Vault, error) {
+	vaults, err := uc.vaults.ListVaults(ctx)
+	if err != nil {
+		return nil, err
+	}
+	discovered := uc.discoverVaults(ctx, vaults)
+	if len(discovered) == 0 {
+		return vaults, nil
+	}
+	return uc.vaults.ListVaults(ctx)
+}
+
+// GetVault looks a vault up by ID, falling back to its display name.
+func (uc *VaultSyncUseCase) GetVault(ctx context.Context, id string) (*domain.Vault, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "get vault", errors.New("vault id is required"))
+	}
+	vault, err := uc.vaults.GetVault(ctx, id)
+	if err == nil || !domain.IsKind(err, domain.ErrVaultNotFound) {
+		return vault, err
+	}
+	if slug := domain.VaultSlug(id); slug != id {
+		if vault, slugErr := uc.vaults.GetVault(ctx, slug); slugErr == nil {
+			return vault, nil
+		}
+	}
+	return nil, err
+}
+
+// UpsertVault registers a vault or updates the one with the same name.
+func (uc *VaultSyncUseCase) UpsertVault(ctx context.Context, input domain.Vault) (*domain.Vault, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Path = strings.TrimSpace(input.Path)
+	if input.Name == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "upsert vault", errors.New("name is required"))
+	}
+	if input.Path == "" {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "upsert vault", errors.New("path is required"))
+	}
+	if input.IntervalMinutes != nil && *input.IntervalMinutes < 1 {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "upsert vault", errors.New("interval_minutes must be >= 1"))
+	}
+	resolved, err := uc.resolvePath(input.Path)
+	if err != nil {
+		return nil, domain.WrapError(domain.ErrInvalidInput, "upsert vault", err)
+	}
+
+	vault := domain.Vault{
+		ID:              domain.VaultSlug(input.Name),
+		Name:            input.Name,
+		Path:            resolved,
+		Enabled:         input.Enabled,
+		IntervalMinutes: input.IntervalMinutes,
+	}
+	existing, err := uc.vaults.GetVault(ctx, vault.ID)
+	switch {
+	case err == nil:
+		vault.CreatedAt = existing.CreatedAt
+		if vault.IntervalMinutes == nil {
+			vault.IntervalMinutes = existing.IntervalMinutes
+		}
+	case !domain.IsKind(err, domain.ErrVaultNotFound):
+		return nil, err
+	}
+
+	if err := uc.vaults.UpsertVault(ctx, &vault); err != nil {
+		return nil, err
+	}
+	uc.notifyChanged()
+	return &vault, nil
+}
+
+// RemoveVault unregisters a vault. Its indexed notes are left in place.
+func (uc *VaultSyncUseCase) RemoveVault(ctx context.Context, id string) error {
+	vault, err := uc.GetVault(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.vaults.DeleteVault(ctx, vault.ID); err != nil {
+		return err
+	}
+	uc.notifyChanged()
+	return nil
+}
+
+// ListSyncRuns returns the most recent sync runs of a vault, newest first.
+func (uc *VaultSyncUseCase) ListSyncRuns(ctx context.Context, id string, limit int) ([]domain.VaultSyncRun, error) {
+	vault, err := uc.GetVault(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.state.ListSyncRuns(ctx, vault.ID, limit)
+}
+
+// SyncVault walks the whole vault, ingests new or changed notes and removes
+// the documents of notes that disappeared. Every call is recorded as a sync
+// run. It fails with ErrConflict while another sync holds the vault.
+func (uc *VaultSyncUseCase) SyncVault(ctx context.Context, id string, trigger domain.VaultSyncTrigger, waitReady bool) (*domain.VaultSyncRun, error) {
+	vault, err := uc.GetVault(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := uc.lock(ctx, vault.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	run := &domain.VaultSyncRun{VaultID: vault.ID, Trigger: trigger}
+	if err := uc.state.CreateSyncRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("record sync run: %w", err)
+	}
+	uc.syncAll(ctx, vault, waitReady, run)
+	uc.finishRun(run)
+	return run, nil
+}
+
+// SyncPaths applies changes for the given vault-relative paths only, without
+// walking the whole vault. Paths that no longer exist remove the note (or
+// every note under a removed folder). Runs that changed nothing are not
+// recorded to keep the history readable while a note is being edited.
+func (uc *VaultSyncUseCase) SyncPaths(ctx context.Context, id string, relPaths []string) (*domain.VaultSyncRun, error) {
+	return uc.syncChangedPaths(ctx, id, relPaths, domain.VaultSyncTriggerWatch)
+}
+
+func (uc *VaultSyncUseCase) syncChangedPaths(ctx context.Context, id string, relPaths []string, trigger domain.VaultSyncTrigger) (*domain.VaultSyncRun, error) {
+	vault, err := uc.GetVault(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := uc.lock(ctx, vault.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	run := &domain.VaultSyncRun{
+		VaultID:   vault.ID,
+		Trigger:   trigger,
+		Status:    domain.VaultSyncStatusRunning,
+		StartedAt: time.Now().UTC(),
+	}
+	if err := uc.syncPaths(ctx, vault, relPaths, run); err != nil {
+		return nil, err
+	}
+	if run.Uploaded+run.Removed+run.Failed > 0 {
+		if err := uc.state.CreateSyncRun(ctx, run); err != nil {
+			slog.Warn("vault_sync_run_record_failed", "vault", vault.ID, "error", err)
+		} else {
+			uc.finishRun(run)
+		}
+	} else {
+		run.Status = domain.VaultSyncStatusOK
+	}
+	return run, nil
+}
+
+// CreateNote writes a markdown note into a vault and indexes it.
+func (uc *VaultSyncUseCase) CreateNote(ctx context.Context, vaultID, title, content, folder string) (string, error) {
+	vault, err := uc.GetVault(ctx, vaultID)
+	if err != nil {
+		return "", err
+	}
+	root, err := uc.resolvePath(vault.Path)
+	if err != nil {
+		return "", fmt.Errorf("resolve vault path: %w", err)
+	}
+
+	targetDir := root
+	if folder != "" {
+		targetDir = filepath.Join(root, filepath.Clean(folder))
+		if targetDir != root && !strings.HasPrefix(targetDir, root+string(os.PathSeparator)) {
+			return "", domain.WrapError(domain.ErrInvalidInput, "create note", errors.New("folder escapes vault root"))
+		}
+	}
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return "", fmt.Errorf("create folder: %w", err)
+	}
+
+	sanitized := sanitizeNoteFilename(title)
+	if sanitized == "" {
+		sanitized = "note"
+	}
+	notePath := filepath.Join(targetDir, sanitized+".md")
+	// Avoid overwriting existing files.
+	if _, err := os.Stat(notePath); err == nil {
+		notePath = filepath.Join(targetDir, fmt.Sprintf("%s_%d.md", sanitized, time.Now().UnixMilli()))
+	}
+	if err := os.WriteFile(notePath, []byte(content), 0o644); err != nil {
+		return "", fmt.Errorf("write note: %w", err)
+	}
+
+	rel, _ := filepath.Rel(root, notePath)
+	// Going through SyncPaths records the note's hash, so the watcher event for
+	// the same write is a no-op. If the vault is busy the running sync or the
+	// watcher picks the note up instead.
+	if _, err := uc.syncChangedPaths(ctx, vault.ID, []string{rel}, domain.VaultSyncTriggerManual); err != nil {
+		slog.Warn("obsidian_note_ingest_failed", "path", notePath, "error", err)
+	}
+	return rel, nil
+}
+
+// Run keeps enabled vaults in sync until ctx is cancelled: a full sync when a
+// vault is first seen, filesystem watching when a DirectoryWatcher is
+// configured, and a periodic full sync as reconciliation for missed events.
+// The vault list is re-read every RefreshInterval and whenever vaults are
+// changed through this use case.
+func (uc *VaultSyncUseCase) Run(ctx context.Context) {
+	type runner struct {
+		path     string
+		interval time.Duration
+		cancel   context.CancelFunc
+	}
+	running := make(map[string]runner)
+	var wg sync.WaitGroup
+	defer func() {
+		for _, r := range running {
+			r.cancel()
+		}
+		wg.Wait()
+	}()
+
+	reconcile := func() {
+		vaults, err := uc.ListVaults(ctx)
+		if err != nil {
+			slog.Error("vault_sync_list_failed", "error", err)
+			return
+		}
+		wanted := make(map[string]bool, len(vaults))
+		for _, vault := range vaults {
+			if !vault.Enabled {
+				continue
+			}
+			wanted[vault.ID] = true
+			interval := uc.interval(vault)
+			if r, ok := running[vault.ID]; ok {
+				if r.path == vault.Path && r.interval == interval {
+					continue
+				}
+				r.cancel()
+			}
+			vaultCtx, cancel := context.WithCancel(ctx)
+			running[vault.ID] = runner{path: vault.Path, interval: interval, cancel: cancel}
+			wg.Add(1)
+			go func(vault domain.Vault) {
+				defer wg.Done()
+				uc.runVault(vaultCtx, vault, interval)
+			}(vault)
+		}
+		for id, r := range running {
+			if !wanted[id] {
+				r.cancel()
+				delete(running, id)
+			}
+		}
+	}
+
+	reconcile()
+	ticker := time.NewTicker(uc.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reconcile()
+		case <-uc.refresh:
+			reconcile()
+		}
+	}
+}
+
+func (uc *VaultSyncUseCase) runVault(ctx context.Context, vault domain.Vault, interval time.Duration) {
+	uc.logRun(uc.SyncVault(ctx, vault.ID, domain.VaultSyncTriggerStartup, false))
+
+	if uc.watcher != nil {
+		root, err := uc.resolvePath(vault.Path)
+		if err != nil {
+			slog.Warn("vault_watch_skipped", "vault", vault.ID, "error", err)
+		} else {
+			go func() {
+				slog.Info("vault_watch_started", "vault", vault.ID, "path", root)
+				err := uc.watcher.Watch(ctx, root, func(batchCtx context.Context, relPaths []string) error {
+					run, err := uc.SyncPaths(batchCtx, vault.ID, relPaths)
+					if err != nil {
+						return err
+					}
+					if run.Uploaded+run.Removed+run.Failed > 0 {
+						uc.logRun(run, nil)
+					}
+					return nil
+				})
+				if err != nil {
+					slog.Error("vault_watch_failed", "vault", vault.ID, "error", err)
+				}
+			}()
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.logRun(uc.SyncVault(ctx, vault.ID, domain.VaultSyncTriggerInterval, false))
+		}
+	}
+}
+
+func (uc *VaultSyncUseCase) logRun(run *domain.VaultSyncRun, err error) {
+	if err != nil {
+		if domain.IsKind(err, domain.ErrConflict) {
+			slog.Info("vault_sync_skipped_locked", "error", err)
+			return
+		}
+		if !errors.Is(err, context.Canceled) {
+			slog.Error("vault_sync_failed", "error", err)
+		}
+		return
+	}
+	slog.Info("vault_sync_done",
+		"vault", run.VaultID,
+		"trigger", run.Trigger,
+		"status", run.Status,
+		"uploaded", run.Uploaded,
+		"skipped", run.Skipped,
+		"removed", run.Removed,
+		"failed", run.Failed,
+	)
+}
+
+func (uc *VaultSyncUseCase) notifyChanged() {
+	select {
+	case uc.refresh <- struct{}{}:
+	default:
+	}
+}
+
+func (uc *VaultSyncUseCase) interval(vault domain.Vault) time.Duration {
+	minutes := uc.opts.DefaultIntervalMinutes
+	if vault.IntervalMinutes != nil && *vault.IntervalMinutes > 0 {
+		minutes = *vault.IntervalMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func (uc *VaultSyncUseCase) lock(ctx context.Context, vaultID string) (func(), error) {
+	unlock, ok, err := uc.state.TryLock(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.WrapError(domain.ErrConflict, "sync vault "+vaultID, errors.New("vault is locked by another sync"))
+	}
+	return unlock, nil
+}
+
+// finishRun stores the final counts of a run. It uses a fresh context so a
+// cancelled sync still leaves a finished record behind.
+func (uc *VaultSyncUseCase) finishRun(run *domain.VaultSyncRun) {
+	if run.Status == "" || run.Status == domain.VaultSyncStatusRunning {
+		run.Status = domain.VaultSyncStatusOK
+		if run.Failed > 0 {
+			run.Status = domain.VaultSyncStatusPartial
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := uc.state.FinishSyncRun(ctx, run); err != nil {
+		slog.Warn("vault_sync_run_record_failed", "vault", run.VaultID, "run_id", run.ID, "error", err)
+	}
+}
+
+func failRun(run *domain.VaultSyncRun, msg string) {
+	run.Status = domain.VaultSyncStatusError
+	run.Failed++
+	run.Errors = append(run.Errors, domain.VaultSyncError{Error: msg})
+}
+
+func (uc *VaultSyncUseCase) syncAll(ctx context.Context, vault *domain.Vault, waitReady bool, run *domain.VaultSyncRun) {
+	root, err := uc.resolvePath(vault.Path)
+	if err != nil {
+		failRun(run, err.Error())
+		return
+	}
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		failRun(run, fmt.Sprintf("vault path not found: %s", root))
+		return
+	}
+
+	state, err := uc.loadState(ctx, vault.ID)
+	if err != nil {
+		failRun(run, err.Error())
+		return
+	}
+	files, err := listVaultNotes(root)
+	if err != nil {
+		failRun(run, err.Error())
+		return
+	}
+
+	var changed []domain.VaultFileState
+	seen := make(map[string]bool, len(files))
+	for _, filePath := range files {
+		rel, err := filepath.Rel(root, filePath)
+		if err != nil {
+			run.Failed++
+			run.Errors = append(run.Errors, domain.VaultSyncError{File: filePath, Error: err.Error()})
+			continue
+		}
+		seen[rel] = true
+		if row, ok := uc.syncNote(ctx, vault.ID, root, rel, state, waitReady, run); ok {
+			changed = append(changed, row)
+		}
+	}
+
+	// Notes deleted or renamed since the last sync: purge their documents so
+	// they stop showing up in search. Rows are kept until the purge succeeds.
+	var removed []string
+	for rel, prev := range state {
+		if seen[rel] {
+			continue
+		}
+		if uc.removeNote(ctx, rel, prev, run) {
+			removed = append(removed, rel)
+		}
+	}
+
+	if err := uc.saveState(ctx, vault.ID, changed, removed); err != nil {
+		failRun(run, err.Error())
+	}
+}
+
+func (uc *VaultSyncUseCase) syncPaths(ctx context.Context, vault *domain.Vault, relPaths []string, run *domain.VaultSyncRun) error {
+	root, err := uc.resolvePath(vault.Path)
+	if err != nil {
+		return err
+	}
+	state, err := uc.loadState(ctx, vault.ID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		changed []domain.VaultFileState
+		removed []string
+	)
+	for _, rel := range relPaths {
+		rel = filepath.Clean(rel)
+		info, statErr := os.Stat(filepath.Join(root, rel))
+		switch {
+		case statErr == nil && info.IsDir():
+			files, err := listVaultNotes(filepath.Join(root, rel))
+			if err != nil {
+				run.Failed++
+				run.Errors = append(run.Errors, domain.VaultSyncError{File: rel, Error: err.Error()})
+				continue
+			}
+			for _, filePath := range files {
+				fileRel, err := filepath.Rel(root, filePath)
+				if err != nil {
+					continue
+				}
+				if row, ok := uc.syncNote(ctx, vault.ID, root, fileRel, state, false, run); ok {
+					state[fileRel] = row
+					changed = append(changed, row)
+				}
+			}
+		case statErr == nil:
+			if !isVaultNote(rel) {
+				continue
+			}
+			if row, ok := uc.syncNote(ctx, vault.ID, root, rel, state, false, run); ok {
+				state[rel] = row
+				changed = append(changed, row)
+			}
+		case os.IsNotExist(statErr):
+			prefix := rel + string(os.PathSeparator)
+			for stateRel, prev := range state {
+				if stateRel != rel && !strings.HasPrefix(stateRel, prefix) {
+					continue
+				}
+				if uc.removeNote(ctx, stateRel, prev, run) {
+					delete(state, stateRel)
+					removed = append(removed, stateRel)
+				}
+			}
+		default:
+			run.Failed++
+			run.Errors = append(run.Errors, domain.VaultSyncError{File: rel, Error: statErr.Error()})
+		}
+	}
+
+	return uc.saveState(ctx, vault.ID, changed, removed)
+}
+
+// syncNote ingests a single note if its content changed since the last sync.
+// It returns the new state row and whether it differs from the stored one.
+func (uc *VaultSyncUseCase) syncNote(
+	ctx context.Context,
+	vaultID, root, rel string,
+	state map[string]domain.VaultFileState,
+	waitReady bool,
+	run *domain.VaultSyncRun,
+) (domain.VaultFileState, bool) {
+	prev, hasPrev := state[rel]
+
+	filePath := filepath.Join(root, rel)
+	hash, err := hashVaultFile(filePath)
+	if err != nil {
+		run.Failed++
+		run.Errors = append(run.Errors, domain.VaultSyncError{File: rel, Error: err.Error()})
+		return prev, false
+	}
+	if hasPrev && prev.Hash == hash {
+		run.Skipped++
+		return prev, false
+	}
+
+	docID, err := uc.ingestNote(ctx, vaultID, rel, filePath, waitReady)
+	if err != nil {
+		run.Failed++
+		run.Errors = append(run.Errors, domain.VaultSyncError{File: rel, Error: err.Error()})
+		return prev, false
+	}
+	run.Uploaded++
+
+	// Notes synced before stable identity existed got a fresh document per
+	// upload; drop the superseded one now that the note has a permanent ID.
+	if hasPrev && prev.DocumentID != "" && prev.DocumentID != docID {
+		if err := uc.deleteDocument(ctx, prev.DocumentID); err != nil {
+			slog.Warn("obsidian_remove_superseded_failed", "document_id", prev.DocumentID, "error", err)
+		}
+	}
+	return domain.VaultFileState{VaultID: vaultID, RelPath: rel, Hash: hash, DocumentID: docID}, true
+}
+
+// removeNote purges the document of a note that no longer exists and reports
+// whether its state row can be dropped.
+func (uc *VaultSyncUseCase) removeNote(ctx context.Context, rel string, prev domain.VaultFileState, run *domain.VaultSyncRun) bool {
+	if err := uc.deleteDocument(ctx, prev.DocumentID); err != nil {
+		run.Failed++
+		run.Errors = append(run.Errors, domain.VaultSyncError{File: rel, Error: err.Error()})
+		return false
+	}
+	run.Removed++
+	return true
+}
+
+func (uc *VaultSyncUseCase) ingestNote(ctx context.Context, vaultID, relPath, path string, waitReady bool) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	doc, err := uc.ingestor.IngestFromSource(ctx, domain.SourceRequest{
+		SourceType: "obsidian",
+		Filename:   filepath.Base(path),
+		MimeType:   "text/markdown",
+		Body:       file,
+		VaultID:    vaultID,
+		Path:       filepath.ToSlash(relPath),
+	})
+	if err != nil {
+		return "", err
+	}
+	if !waitReady {
+		return doc.ID, nil
+	}
+	if err := uc.waitDocumentReady(ctx, doc.ID); err != nil {
+		return doc.ID, err
+	}
+	return doc.ID, nil
+}
+
+func (uc *VaultSyncUseCase) waitDocumentReady(ctx context.Context, documentID string) error {
+	deadline := time.Now().Add(uc.opts.SyncTimeout)
+	for {
+		doc, err := uc.docs.GetByID(ctx, documentID)
+		if err != nil {
+			return err
+		}
+		switch doc.Status {
+		case domain.StatusReady:
+			return nil
+		case domain.StatusFailed:
+			return fmt.Errorf("document processing failed: %s", doc.Error)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout waiting for document %s", documentID)
+		}
+		select {
+		case <-time.After(uc.opts.SyncPoll):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (uc *VaultSyncUseCase) deleteDocument(ctx context.Context, documentID string) error {
+	if documentID == "" {
+		return nil
+	}
+	if uc.deleter == nil {
+		return errors.New("document deleter not configured")
+	}
+	if err := uc.deleter.Delete(ctx, documentID); err != nil && !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (uc *VaultSyncUseCase) loadState(ctx context.Context, vaultID string) (map[string]domain.VaultFileState, error) {
+	rows, err := uc.state.ListFileStates(ctx, vaultID)
+	if err != nil {
+		return nil, err
+	}
+	state := make(map[string]domain.VaultFileState, len(rows))
+	for _, row := range rows {
+		state[row.RelPath] = row
+	}
+	return state, nil
+}
+
+func (uc *VaultSyncUseCase) saveState(ctx context.Context, vaultID string, changed []domain.VaultFileState, removed []string) error {
+	if err := uc.state.UpsertFileStates(ctx, changed); err != nil {
+		return err
+	}
+	return uc.state.DeleteFileStates(ctx, vaultID, removed)
+}
+
+// discoverVaults registers directories under the vaults root that are not
+// known yet and returns the newly added vaults.
+func (uc *VaultSyncUseCase) discoverVaults(ctx context.Context, known []domain.Vault) []domain.Vault {
+	if uc.opts.VaultsRoot == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(uc.opts.VaultsRoot)
+	if err != nil {
+		slog.Warn("auto_discover_vaults_failed", "error", err)
+		return nil
+	}
+
+	knownPaths := make(map[string]bool, len(known))
+	knownIDs := make(map[string]bool, len(known))
+	for _, v := range known {
+		knownPaths[filepath.Clean(v.Path)] = true
+		knownIDs[v.ID] = true
+	}
+
+	var added []domain.Vault
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		fullPath := filepath.Join(uc.opts.VaultsRoot, entry.Name())
+		slug := domain.VaultSlug(entry.Name())
+		if knownPaths[filepath.Clean(fullPath)] || knownIDs[slug] {
+			continue
+		}
+		vault := domain.Vault{ID: slug, Name: entry.Name(), Path: fullPath, Enabled: true}
+		if err := uc.vaults.UpsertVault(ctx, &vault); err != nil {
+			slog.Warn("auto_discover_vault_save_failed", "name", entry.Name(), "error", err)
+			continue
+		}
+		knownIDs[slug] = true
+		added = append(added, vault)
+		slog.Info("auto_discovered_vault", "name", entry.Name(), "path", fullPath)
+	}
+	return added
+}
+
+func (uc *VaultSyncUseCase) resolvePath(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("path is required")
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(uc.opts.VaultsRoot, path)
+	}
+	path = filepath.Clean(path)
+	if uc.opts.VaultsRoot != "" {
+		root := filepath.Clean(uc.opts.VaultsRoot)
+		if path != root && !strings.HasPrefix(path, root+string(os.PathSeparator)) {
+			return "", fmt.Errorf("path must be under %s", root)
+		}
+	}
+	return path, nil
+}
+
+func sanitizeNoteFilename(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' || r == '*' || r == '?' || r == '"' || r == '<' || r == '>' || r == '|' {
+			return '_'
+		}
+		return r
+	}, name)
+	if len(name) > 200 {
+		name = name[:200]
+	}
+	return name
+}
+
+func listVaultNotes(root string) ([]string, error) {
+	paths := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if IsIgnoredVaultDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isVaultNote(d.Name()) {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// IsIgnoredVaultDir reports whether a vault sub-directory holds Obsidian or
+// VCS internals rather than notes.
+func IsIgnoredVaultDir(name string) bool {
+	return name == ".obsidian" || name == ".trash" || name == ".git"
+}
+
+func isVaultNote(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".md")
+}
+
+func hashVaultFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type vaultStoreFake struct {
+	vaults   map[string]domain.Vault
+	states   map[string]domain.VaultFileState
+	runs     []domain.VaultSyncRun
+	finished []domain.VaultSyncRun
+	locked   map[string]bool
+}
+
+func newVaultStoreFake() *vaultStoreFake {
+	return &vaultStoreFake{
+		vaults: map[string]domain.Vault{},
+		states: map[string]domain.VaultFileState{},
+		locked: map[string]bool{},
+	}
+}
+
+func (f *vaultStoreFake) ListVaults(context.Context) ([]domain.Vault, error) {
+	out := make([]domain.Vault, 0, len(f.vaults))
+	for _, v := range f.vaults {
+		out = append(out, v)
+	}
+	return out, nil
+}
+func (f *vaultStoreFake) GetVault(_ context.Context, id string) (*domain.Vault, error) {
+	v, ok := f.vaults[id]
+	if !ok {
+		return nil, domain.WrapError(domain.ErrVaultNotFound, "get vault", io.EOF)
+	}
+	return &v, nil
+}
+func (f *vaultStoreFake) UpsertVault(_ context.Context, v *domain.Vault) error {
+	f.vaults[v.ID] = *v
+	return nil
+}
+func (f *vaultStoreFake) DeleteVault(_ context.Context, id string) error {
+	if _, ok := f.vaults[id]; !ok {
+		return domain.WrapError(domain.ErrVaultNotFound, "delete vault", io.EOF)
+	}
+	delete(f.vaults, id)
+	return nil
+}
+func (f *vaultStoreFake) ListFileStates(_ context.Context, vaultID string) ([]domain.VaultFileState, error) {
+	var out []domain.VaultFileState
+	for _, st := range f.states {
+		if st.VaultID == vaultID {
+			out = append(out, st)
+		}
+	}
+	return out, nil
+}
+func (f *vaultStoreFake) UpsertFileStates(_ context.Context, states []domain.VaultFileState) error {
+	for _, st := range states {
+		f.states[st.VaultID+"/"+st.RelPath] = st
+	}
+	return nil
+}
+func (f *vaultStoreFake) DeleteFileStates(_ context.Context, vaultID string, relPaths []string) error {
+	for _, rel := range relPaths {
+		delete(f.states, vaultID+"/"+rel)
+	}
+	return nil
+}
+func (f *vaultStoreFake) TryLock(_ context.Context, vaultID string) (func(), bool, error) {
+	if f.locked[vaultID] {
+		return nil, false, nil
+	}
+	f.locked[vaultID] = true
+	return func() { delete(f.locked, vaultID) }, true, nil
+}
+func (f *vaultStoreFake) CreateSyncRun(_ context.Context, run *domain.VaultSyncRun) error {
+	run.ID = "run-" + string(rune('a'+len(f.runs)))
+	f.runs = append(f.runs, *run)
+	return nil
+}
+func (f *vaultStoreFake) FinishSyncRun(_ context.Context, run *domain.VaultSyncRun) error {
+	f.finished = append(f.finished, *run)
+	return nil
+}
+func (f *vaultStoreFake) ListSyncRuns(context.Context, string, int) ([]domain.VaultSyncRun, error) {
+	return f.finished, nil
+}
+
+type vaultIngestFake struct {
+	ingested []string
+}
+
+func (f *vaultIngestFake) Upload(context.Context, string, string, io.Reader) (*domain.Document, error) {
+	return nil, io.EOF
+}
+func (f *vaultIngestFake) IngestFromSource(_ context.Context, req domain.SourceRequest) (*domain.Document, error) {
+	f.ingested = append(f.ingested, req.Path)
+	return &domain.Document{ID: "doc:" + req.Path, Status: domain.StatusUploaded}, nil
+}
+
+type vaultDeleterFake struct {
+	deleted []string
+}
+
+func (f *vaultDeleterFake) Delete(_ context.Context, id string) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+func (f *vaultDeleterFake) DeleteByFilter(context.Context, domain.DocumentFilter) (*domain.DocumentDeleteResult, error) {
+	return nil, nil
+}
+
+func newVaultSyncFixture(t *testing.T) (*VaultSyncUseCase, *vaultStoreFake, *vaultIngestFake, *vaultDeleterFake, string) {
+	t.Helper()
+	root := t.TempDir()
+	store := newVaultStoreFake()
+	ingest := &vaultIngestFake{}
+	deleter := &vaultDeleterFake{}
+	uc := NewVaultSyncUseCase(store, store, ingest, nil, deleter, nil, VaultSyncOptions{VaultsRoot: root})
+	return uc, store, ingest, deleter, root
+}
+
+func writeVaultNote(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncVaultRemovesDeletedNotesAndRecordsRun(t *testing.T) {
+	uc, store, ingest, deleter, root := newVaultSyncFixture(t)
+	vaultDir := filepath.Join(root, "main")
+	writeVaultNote(t, filepath.Join(vaultDir, "kept.md"), "# Kept")
+	writeVaultNote(t, filepath.Join(vaultDir, ".obsidian", "workspace.md"), "ignored")
+	store.vaults["main"] = domain.Vault{ID: "main", Name: "Main", Path: vaultDir, Enabled: true}
+	store.states["main/gone.md"] = domain.VaultFileState{VaultID: "main", RelPath: "gone.md", Hash: "abc", DocumentID: "doc-gone"}
+
+	run, err := uc.SyncVault(context.Background(), "main", domain.VaultSyncTriggerManual, false)
+	if err != nil {
+		t.Fatalf("SyncVault() error = %v", err)
+	}
+	if run.Uploaded != 1 || run.Removed != 1 || run.Failed != 0 || run.Status != domain.VaultSyncStatusOK {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if len(ingest.ingested) != 1 || ingest.ingested[0] != "kept.md" {
+		t.Fatalf("expected only kept.md ingested, got %v", ingest.ingested)
+	}
+	if len(deleter.deleted) != 1 || deleter.deleted[0] != "doc-gone" {
+		t.Fatalf("expected doc-gone deleted, got %v", deleter.deleted)
+	}
+	if _, ok := store.states["main/gone.md"]; ok {
+		t.Fatal("expected gone.md dropped from state")
+	}
+	if st, ok := store.states["main/kept.md"]; !ok || st.DocumentID != "doc:kept.md" {
+		t.Fatalf("expected kept.md in state, got %+v", store.states)
+	}
+	if len(store.runs) != 1 || len(store.finished) != 1 || store.finished[0].Trigger != domain.VaultSyncTriggerManual {
+		t.Fatalf("expected one recorded run, got %+v / %+v", store.runs, store.finished)
+	}
+	if len(store.locked) != 0 {
+		t.Fatal("expected lock released")
+	}
+
+	// A second pass sees unchanged hashes and skips the note.
+	run, err = uc.SyncVault(context.Background(), "main", domain.VaultSyncTriggerInterval, false)
+	if err != nil {
+		t.Fatalf("SyncVault() error = %v", err)
+	}
+	if run.Skipped != 1 || run.Uploaded != 0 {
+		t.Fatalf("expected note skipped, got %+v", run)
+	}
+}
+
+func TestSyncVaultRecordsMissingPathAsError(t *testing.T) {
+	uc, store, _, _, root := newVaultSyncFixture(t)
+	store.vaults["gone"] = domain.Vault{ID: "gone", Name: "Gone", Path: filepath.Join(root, "gone"), Enabled: true}
+
+	run, err := uc.SyncVault(context.Background(), "gone", domain.VaultSyncTriggerManual, false)
+	if err != nil {
+		t.Fatalf("SyncVault() error = %v", err)
+	}
+	if run.Status != domain.VaultSyncStatusError || len(run.Errors) != 1 || !strings.Contains(run.Errors[0].Error, "vault path not found") {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if len(store.finished) != 1 || store.finished[0].Status != domain.VaultSyncStatusError {
+		t.Fatalf("expected error run recorded, got %+v", store.finished)
+	}
+}
+
+func TestSyncVaultConflictsWhileLocked(t *testing.T) {
+	uc, store, _, _, root := newVaultSyncFixture(t)
+	store.vaults["main"] = domain.Vault{ID: "main", Name: "Main", Path: root, Enabled: true}
+	store.locked["main"] = true
+
+	_, err := uc.SyncVault(context.Background(), "main", domain.VaultSyncTriggerManual, false)
+	if !domain.IsKind(err, domain.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if len(store.runs) != 0 {
+		t.Fatalf("locked sync must not record a run, got %+v", store.runs)
+	}
+}
+
+func TestSyncPathsSyncsChangedAndRemovesMissing(t *testing.T) {
+	uc, store, ingest, deleter, root := newVaultSyncFixture(t)
+	vaultDir := filepath.Join(root, "watch")
+	writeVaultNote(t, filepath.Join(vaultDir, "notes", "fresh.md"), "# Fresh")
+	store.vaults["watch"] = domain.Vault{ID: "watch", Name: "Watch", Path: vaultDir, Enabled: true}
+	store.states["watch/old/a.md"] = domain.VaultFileState{VaultID: "watch", RelPath: "old/a.md", Hash: "a", DocumentID: "doc-a"}
+	store.states["watch/untouched.md"] = domain.VaultFileState{VaultID: "watch", RelPath: "untouched.md", Hash: "u", DocumentID: "doc-u"}
+
+	run, err := uc.SyncPaths(context.Background(), "watch", []string{"notes/fresh.md", "old", "notes/image.png"})
+	if err != nil {
+		t.Fatalf("SyncPaths() error = %v", err)
+	}
+	if run.Uploaded != 1 || run.Removed != 1 {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if len(ingest.ingested) != 1 || len(deleter.deleted) != 1 || deleter.deleted[0] != "doc-a" {
+		t.Fatalf("unexpected ingest/delete: %v / %v", ingest.ingested, deleter.deleted)
+	}
+	if _, ok := store.states["watch/untouched.md"]; !ok {
+		t.Fatal("expected untouched.md kept in state")
+	}
+	if _, ok := store.states["watch/old/a.md"]; ok {
+		t.Fatal("expected old/a.md dropped from state")
+	}
+	if len(store.finished) != 1 || store.finished[0].Trigger != domain.VaultSyncTriggerWatch {
+		t.Fatalf("expected a recorded watch run, got %+v", store.finished)
+	}
+
+	// Re-delivering the same unchanged path is not recorded in history.
+	if _, err := uc.SyncPaths(context.Background(), "watch", []string{"notes/fresh.md"}); err != nil {
+		t.Fatalf("SyncPaths() error = %v", err)
+	}
+	if len(store.finished) != 1 {
+		t.Fatalf("no-op watch run must not be recorded, got %d runs", len(store.finished))
+	}
+}
+
+func TestUpsertVaultValidatesAndKeepsInterval(t *testing.T) {
+	uc, store, _, _, root := newVaultSyncFixture(t)
+
+	if _, err := uc.UpsertVault(context.Background(), domain.Vault{Name: "X", Path: "/outside"}); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for path outside root, got %v", err)
+	}
+
+	interval := 5
+	if _, err := uc.UpsertVault(context.Background(), domain.Vault{Name: "My Vault", Path: "my", Enabled: true, IntervalMinutes: &interval}); err != nil {
+		t.Fatalf("UpsertVault() error = %v", err)
+	}
+	vault, err := uc.UpsertVault(context.Background(), domain.Vault{Name: "My Vault", Path: "my", Enabled: false})
+	if err != nil {
+		t.Fatalf("UpsertVault() error = %v", err)
+	}
+	if vault.ID != "my_vault" || vault.Path != filepath.Join(root, "my") || vault.Enabled {
+		t.Fatalf("unexpected vault: %+v", vault)
+	}
+	if vault.IntervalMinutes == nil || *vault.IntervalMinutes != 5 {
+		t.Fatalf("expected interval preserved, got %v", vault.IntervalMinutes)
+	}
+	if len(store.vaults) != 1 {
+		t.Fatalf("expected a single vault, got %d", len(store.vaults))
+	}
+
+	// Lookup by display name falls back to the slug.
+	if _, err := uc.GetVault(context.Background(), "My Vault"); err != nil {
+		t.Fatalf("GetVault(name) error = %v", err)
+	}
+}
+
+func TestListVaultsDiscoversNewDirectories(t *testing.T) {
+	uc, store, _, _, root := newVaultSyncFixture(t)
+	for _, dir := range []string{"Work Notes", ".hidden"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vaults, err := uc.ListVaults(context.Background())
+	if err != nil {
+		t.Fatalf("ListVaults() error = %v", err)
+	}
+	if len(vaults) != 1 || vaults[0].ID != "work_notes" || !vaults[0].Enabled {
+		t.Fatalf("unexpected vaults: %+v", vaults)
+	}
+	if _, ok := store.vaults["work_notes"]; !ok {
+		t.Fatal("expected discovered vault persisted")
+	}
+}
+
+func TestCreateNoteWritesAndIndexes(t *testing.T) {
+	uc, store, ingest, _, root := newVaultSyncFixture(t)
+	vaultDir := filepath.Join(root, "notes")
+	store.vaults["notes"] = domain.Vault{ID: "notes", Name: "Notes", Path: vaultDir, Enabled: true}
+
+	rel, err := uc.CreateNote(context.Background(), "notes", "Plan: Q3", "# Plan", "projects")
+	if err != nil {
+		t.Fatalf("CreateNote() error = %v", err)
+	}
+	if rel != filepath.Join("projects", "Plan_ Q3.md") {
+		t.Fatalf("unexpected path %q", rel)
+	}
+	if len(ingest.ingested) != 1 {
+		t.Fatalf("expected note indexed, got %v", ingest.ingested)
+	}
+	if _, ok := store.states["notes/"+rel]; !ok {
+		t.Fatal("expected note hash recorded so the watcher skips it")
+	}
+
+	if _, err := uc.CreateNote(context.Background(), "notes", "Escape", "x", "../.."); !domain.IsKind(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput for escaping folder, got %v", err)
+	}
+}
+
+func TestSanitizeNoteFilename(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"simple", "simple"},
+		{"Hello World", "Hello World"},
+		{"file/name", "file_name"},
+		{"a\\b", "a_b"},
+		{"co:lon", "co_lon"},
+		{"star*", "star_"},
+		{"q?mark", "q_mark"},
+		{`"quoted"`, `_quoted_`},
+		{"a<b>c", "a_b_c"},
+		{"pipe|line", "pipe_line"},
+		{"  spaces  ", "spaces"},
+		{"", ""},
+		{strings.Repeat("a", 250), strings.Repeat("a", 200)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := sanitizeNoteFilename(tt.input); got != tt.want {
+				t.Errorf("sanitizeNoteFilename(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
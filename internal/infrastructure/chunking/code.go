@@ -0,0 +1,108 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+)
+
+// declarationPatterns match lines that start a top-level declaration, keyed
+// by mimetype.Language name. Languages without a pattern are split by the
+// inner splitter alone.
+var declarationPatterns = map[string]*regexp.Regexp{
+	"go":         regexp.MustCompile(`^(func|type|var|const)\b`),
+	"python":     regexp.MustCompile(`^\s{0,4}(async\s+def|def|class)\s`),
+	"javascript": regexp.MustCompile(`^\s{0,2}(export\s+)?(default\s+)?(async\s+)?(function\b|class\b|const\s|let\s|var\s)`),
+	"typescript": regexp.MustCompile(`^\s{0,2}(export\s+)?(default\s+)?(declare\s+)?(abstract\s+)?(async\s+)?(function\b|class\b|interface\s|type\s|enum\s|namespace\s|const\s|let\s)`),
+	"java":       jvmDeclaration,
+	"kotlin":     jvmDeclaration,
+	"scala":      jvmDeclaration,
+	"csharp":     jvmDeclaration,
+	"swift":      jvmDeclaration,
+	"rust":       regexp.MustCompile(`^\s{0,4}(pub(\([^)]*\))?\s+)?((async|unsafe|const|extern)\s+)*(fn|struct|enum|trait|impl|mod|type|static|macro_rules!)[\s<{!]`),
+	"c":          cDeclaration,
+	"cpp":        cDeclaration,
+	"ruby":       regexp.MustCompile(`^\s{0,2}(def|class|module)\s`),
+	"php":        regexp.MustCompile(`^\s{0,4}((public|private|protected|static|abstract|final)\s+)*(function|class|interface|trait|enum)\s`),
+	"shell":      regexp.MustCompile(`^(function\s+[\w-]+|[\w-]+\s*\(\))`),
+	"sql":        regexp.MustCompile(`(?i)^(create|alter|drop|insert|update|delete|select|with|grant|comment\s+on)\b`),
+	"protobuf":   regexp.MustCompile(`^\s{0,2}(message|service|enum|rpc|extend)\s`),
+	"yaml":       regexp.MustCompile(`^[A-Za-z_"'][^:#]*:(\s|$)`),
+	"toml":       regexp.MustCompile(`^\[`),
+	"dockerfile": regexp.MustCompile(`(?i)^FROM\s`),
+	"makefile":   regexp.MustCompile(`^[\w.$(){}/%-]+\s*:([^=]|$)`),
+}
+
+var (
+	jvmDeclaration = regexp.MustCompile(`^\s{0,4}(((public|private|protected|internal|static|final|abstract|override|open|sealed|data|suspend|async|virtual|partial|inline|readonly|fileprivate)\s+)*(class|interface|enum|record|struct|object|trait|fun|func|def|extension)\s|((public|private|protected|internal|static|final|abstract|override|virtual|async)\s+)+[\w<>\[\],.?]+\s+\w+\s*\()`)
+	cDeclaration   = regexp.MustCompile(`^((struct|class|enum|union|namespace|typedef|template)\b|[A-Za-z_][\w\s*&:<>,]*[\s*&]\**~?[A-Za-z_][\w:~]*\s*\([^;]*$)`)
+)
+
+// leadingCommentPrefixes mark comment, doc-comment, decorator and attribute
+// lines that belong to the declaration below them.
+var leadingCommentPrefixes = []string{"//", "#", "/*", "*", "@", "--", "[", ";"}
+
+// CodeSplitter splits source code at top-level declarations (functions,
+// types, classes, ...) recognized per language, keeping the comments and
+// decorators above a declaration with it. Declarations are packed into
+// chunks by the inner RecursiveSplitter; one that exceeds the size limit is
+// split at blank lines, then lines.
+type CodeSplitter struct {
+	boundary *regexp.Regexp
+	inner    *RecursiveSplitter
+}
+
+func NewCodeSplitter(language string, inner *RecursiveSplitter) *CodeSplitter {
+	return &CodeSplitter{boundary: declarationPatterns[language], inner: inner}
+}
+
+func (s *CodeSplitter) Split(text string) []string {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if s.boundary == nil {
+		return s.inner.Split(text)
+	}
+	var pieces []string
+	for _, block := range s.blocks(text) {
+		pieces = append(pieces, s.inner.pieces(block, levelParagraph)...)
+	}
+	return s.inner.merge(pieces)
+}
+
+// blocks cuts text before every declaration line and its leading comments.
+// Concatenating the blocks reproduces text.
+func (s *CodeSplitter) blocks(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	var (
+		out   []string
+		start int
+	)
+	for i := 1; i < len(lines); i++ {
+		if !s.boundary.MatchString(strings.TrimRight(lines[i], "\r\n")) {
+			continue
+		}
+		cut := i
+		for cut > start+1 && isLeadingComment(lines[cut-1]) {
+			cut--
+		}
+		if cut <= start {
+			continue
+		}
+		out = append(out, strings.Join(lines[start:cut], ""))
+		start = cut
+	}
+	return append(out, strings.Join(lines[start:], ""))
+}
+
+func isLeadingComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return false
+	}
+	for _, p := range leadingCommentPrefixes {
+		if strings.HasPrefix(trimmed, p) {
+			return true
+		}
+	}
+	return false
+}
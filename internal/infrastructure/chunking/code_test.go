@@ -0,0 +1,102 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+)
+
+const goSource = `package store
+
+import "context"
+
+// Get loads a record by key.
+func Get(ctx context.Context, key string) (string, error) {
+	return lookup(ctx, key)
+}
+
+// Put stores a record.
+// It overwrites existing keys.
+func Put(ctx context.Context, key, value string) error {
+	return save(ctx, key, value)
+}
+
+type Record struct {
+	Key   string
+	Value string
+}
+`
+
+func TestCodeSplitter_SplitsAtDeclarations(t *testing.T) {
+	s := NewCodeSplitter("go", NewRecursiveSplitter(160, 0, RuneLength))
+	chunks := s.Split(goSource)
+
+	if len(chunks) != 3 {
+		t.Fatalf("expected 3 chunks, got %d: %q", len(chunks), chunks)
+	}
+	if !strings.HasPrefix(chunks[1], "// Put stores a record.\n// It overwrites existing keys.\nfunc Put(") {
+		t.Fatalf("doc comment must stay with its function, got %q", chunks[1])
+	}
+	if !strings.HasPrefix(chunks[2], "type Record struct") {
+		t.Fatalf("unexpected last chunk %q", chunks[2])
+	}
+	for _, c := range chunks {
+		if strings.Contains(c, "func Get") && strings.Contains(c, "func Put") {
+			t.Fatalf("functions must not share a chunk at this size: %q", c)
+		}
+	}
+}
+
+func TestCodeSplitter_PacksSmallDeclarations(t *testing.T) {
+	s := NewCodeSplitter("go", NewRecursiveSplitter(1000, 0, RuneLength))
+	if chunks := s.Split(goSource); len(chunks) != 1 {
+		t.Fatalf("expected the whole file in one chunk, got %d", len(chunks))
+	}
+}
+
+func TestCodeSplitter_PythonMethodsAndDecorators(t *testing.T) {
+	src := "class Cache:\n    \"\"\"In-memory cache.\"\"\"\n\n    @property\n    def size(self):\n        return len(self.items)\n\n    def clear(self):\n        self.items = {}\n"
+	s := NewCodeSplitter("python", NewRecursiveSplitter(100, 0, RuneLength))
+	chunks := s.Split(src)
+
+	var found bool
+	for _, c := range chunks {
+		if strings.HasPrefix(c, "@property\n    def size(self):") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("decorator must stay with its method, got %q", chunks)
+	}
+}
+
+func TestCodeSplitter_OversizedDeclarationIsSplit(t *testing.T) {
+	body := strings.Repeat("\tx++\n", 100)
+	src := "func big() {\n" + body + "}\n"
+	s := NewCodeSplitter("go", NewRecursiveSplitter(100, 0, RuneLength))
+	for _, c := range s.Split(src) {
+		if RuneLength(c) > 100 {
+			t.Fatalf("chunk exceeds size: %d runes", RuneLength(c))
+		}
+	}
+}
+
+func TestCodeSplitter_UnknownLanguageFallsBack(t *testing.T) {
+	s := NewCodeSplitter("cobol", NewRecursiveSplitter(100, 0, RuneLength))
+	if chunks := s.Split("IDENTIFICATION DIVISION."); len(chunks) != 1 {
+		t.Fatalf("expected inner splitter result, got %q", chunks)
+	}
+}
+
+func TestRegistry_ForDocument_PrefersMimeType(t *testing.T) {
+	fallback := NewSplitter(900, 100)
+	code := NewCodeSplitter("go", NewRecursiveSplitter(900, 0, RuneLength))
+	reg := NewRegistry(fallback)
+	reg.RegisterMimeType("text/x-go", code)
+
+	if got := reg.ForDocument("upload", "text/x-go; charset=utf-8"); got != code {
+		t.Fatalf("expected code splitter, got %T", got)
+	}
+	if got := reg.ForDocument("upload", "text/plain"); got != fallback {
+		t.Fatalf("expected fallback, got %T", got)
+	}
+}
@@ -0,0 +1,78 @@
+package chunking
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// defaultTokenChunkSize suits embedding models with a 512-token window.
+const defaultTokenChunkSize = 256
+
+// New builds the chunker for cfg. The embedder is used by the semantic
+// strategy only.
+//
+// Strategies: fixed (rune windows), markdown (headings, then fixed),
+// recursive (paragraph/sentence boundaries), markdown-structured (headings,
+// code blocks and tables kept whole, recursive prose) and semantic (topic
+// shifts between sentence embeddings). The last three honour cfg.Unit.
+func New(cfg domain.ChunkConfig, embedder ports.Embedder) (ports.Chunker, error) {
+	length, tokens, err := unitLength(cfg.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := strings.ToLower(strings.TrimSpace(cfg.Strategy))
+	if tokens && cfg.Size <= 0 {
+		cfg.Size = defaultTokenChunkSize
+	}
+	switch strategy {
+	case "", "fixed", "markdown", "md":
+		if tokens {
+			return nil, fmt.Errorf("chunk strategy %q does not support unit tokens", cfg.Strategy)
+		}
+		if strategy == "markdown" || strategy == "md" {
+			return NewMarkdownSplitter(cfg.Size, cfg.Overlap), nil
+		}
+		return NewSplitter(cfg.Size, cfg.Overlap), nil
+	case "recursive":
+		return NewRecursiveSplitter(cfg.Size, cfg.Overlap, length), nil
+	case "markdown-structured":
+		return NewStructuredMarkdownSplitter(NewRecursiveSplitter(cfg.Size, cfg.Overlap, length)), nil
+	case "semantic":
+		if embedder == nil {
+			return nil, fmt.Errorf("chunk strategy semantic requires an embedder")
+		}
+		return NewSemanticSplitter(embedder, NewRecursiveSplitter(cfg.Size, cfg.Overlap, length), cfg.BreakpointPercentile), nil
+	default:
+		return nil, fmt.Errorf("unsupported chunk strategy %q: use fixed, markdown, recursive, markdown-structured or semantic", cfg.Strategy)
+	}
+}
+
+// NewCode builds the language-aware chunker for source files in language
+// (a mimetype.Language name), sized by cfg. cfg.Strategy is ignored.
+func NewCode(language string, cfg domain.ChunkConfig) (ports.Chunker, error) {
+	length, tokens, err := unitLength(cfg.Unit)
+	if err != nil {
+		return nil, err
+	}
+	if tokens && cfg.Size <= 0 {
+		cfg.Size = defaultTokenChunkSize
+	}
+	return NewCodeSplitter(language, NewRecursiveSplitter(cfg.Size, cfg.Overlap, length)), nil
+}
+
+// unitLength returns the LengthFunc for a chunk unit and whether it counts
+// tokens.
+func unitLength(unit string) (LengthFunc, bool, error) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "", "chars", "runes":
+		return RuneLength, false, nil
+	case "tokens":
+		return TokenLength, true, nil
+	default:
+		return nil, false, fmt.Errorf("unsupported chunk unit %q: use chars or tokens", unit)
+	}
+}
@@ -0,0 +1,45 @@
+package chunking
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestNew_SelectsStrategy(t *testing.T) {
+	cases := []struct {
+		cfg  domain.ChunkConfig
+		want any
+	}{
+		{domain.ChunkConfig{}, &Splitter{}},
+		{domain.ChunkConfig{Strategy: "markdown"}, &MarkdownSplitter{}},
+		{domain.ChunkConfig{Strategy: "recursive", Unit: "tokens"}, &RecursiveSplitter{}},
+		{domain.ChunkConfig{Strategy: "markdown-structured"}, &StructuredMarkdownSplitter{}},
+		{domain.ChunkConfig{Strategy: "semantic"}, &SemanticSplitter{}},
+	}
+	for _, tc := range cases {
+		got, err := New(tc.cfg, topicEmbedder{})
+		if err != nil {
+			t.Fatalf("New(%+v) error = %v", tc.cfg, err)
+		}
+		if gotType, wantType := fmt.Sprintf("%T", got), fmt.Sprintf("%T", tc.want); gotType != wantType {
+			t.Fatalf("New(%+v) = %s, want %s", tc.cfg, gotType, wantType)
+		}
+	}
+}
+
+func TestNew_RejectsInvalidConfig(t *testing.T) {
+	for _, cfg := range []domain.ChunkConfig{
+		{Strategy: "sliding"},
+		{Strategy: "recursive", Unit: "words"},
+		{Strategy: "fixed", Unit: "tokens"},
+	} {
+		if _, err := New(cfg, topicEmbedder{}); err == nil {
+			t.Fatalf("New(%+v) expected error", cfg)
+		}
+	}
+	if _, err := New(domain.ChunkConfig{Strategy: "semantic"}, nil); err == nil {
+		t.Fatal("semantic without embedder expected error")
+	}
+}
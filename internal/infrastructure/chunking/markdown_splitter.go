@@ -62,3 +62,136 @@ func splitMarkdownSections(text string) []string {
 	}
 	return sections
 }
+
+// StructuredMarkdownSplitter splits by headings like MarkdownSplitter but
+// keeps fenced code blocks and tables whole when they fit and cuts prose on
+// paragraph and sentence boundaries. Oversized code blocks and tables are
+// split by lines, repeating the fence or the table header in every part.
+type StructuredMarkdownSplitter struct {
+	inner *RecursiveSplitter
+}
+
+func NewStructuredMarkdownSplitter(inner *RecursiveSplitter) *StructuredMarkdownSplitter {
+	return &StructuredMarkdownSplitter{inner: inner}
+}
+
+func (s *StructuredMarkdownSplitter) Split(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	var out []string
+	for _, section := range splitMarkdownSections(text) {
+		var units []string
+		for _, block := range markdownBlocks(section) {
+			units = append(units, s.blockUnits(block)...)
+		}
+		out = append(out, s.inner.merge(units)...)
+	}
+	return out
+}
+
+type markdownBlockKind int
+
+const (
+	markdownProse markdownBlockKind = iota
+	markdownCode
+	markdownTable
+)
+
+type markdownBlock struct {
+	kind  markdownBlockKind
+	lines []string
+}
+
+// markdownBlocks groups section lines into prose, fenced code and table
+// blocks.
+func markdownBlocks(section string) []markdownBlock {
+	var (
+		blocks []markdownBlock
+		cur    *markdownBlock
+		fence  string
+	)
+	start := func(kind markdownBlockKind) {
+		blocks = append(blocks, markdownBlock{kind: kind})
+		cur = &blocks[len(blocks)-1]
+	}
+	for _, line := range strings.Split(section, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			cur.lines = append(cur.lines, line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				cur = nil
+			}
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+			start(markdownCode)
+		case strings.HasPrefix(trimmed, "|"):
+			if cur == nil || cur.kind != markdownTable {
+				start(markdownTable)
+			}
+		default:
+			if cur == nil || cur.kind != markdownProse {
+				start(markdownProse)
+			}
+		}
+		cur.lines = append(cur.lines, line)
+	}
+	return blocks
+}
+
+func (s *StructuredMarkdownSplitter) blockUnits(block markdownBlock) []string {
+	text := strings.Join(block.lines, "\n") + "\n\n"
+	if block.kind == markdownProse {
+		if strings.TrimSpace(text) == "" {
+			return nil
+		}
+		return s.inner.pieces(text, levelParagraph)
+	}
+	if s.inner.length(text) <= s.inner.size {
+		return []string{text}
+	}
+
+	var prefix, body, suffix []string
+	switch block.kind {
+	case markdownCode:
+		prefix, body = block.lines[:1], block.lines[1:]
+		if n := len(body); n > 0 && strings.HasPrefix(strings.TrimSpace(body[n-1]), strings.TrimSpace(prefix[0])[:3]) {
+			body, suffix = body[:n-1], body[n-1:]
+		}
+	case markdownTable:
+		// Header row plus the |---| delimiter row.
+		n := min(2, len(block.lines))
+		prefix, body = block.lines[:n], block.lines[n:]
+	}
+	return s.splitBlockLines(prefix, body, suffix)
+}
+
+// splitBlockLines groups body lines into parts that fit the size limit,
+// each wrapped in prefix and suffix lines.
+func (s *StructuredMarkdownSplitter) splitBlockLines(prefix, body, suffix []string) []string {
+	wrap := func(lines []string) string {
+		all := make([]string, 0, len(prefix)+len(lines)+len(suffix))
+		all = append(append(append(all, prefix...), lines...), suffix...)
+		return strings.Join(all, "\n") + "\n\n"
+	}
+	var (
+		out []string
+		cur []string
+	)
+	for _, line := range body {
+		if len(cur) > 0 && s.inner.length(wrap(append(cur, line))) > s.inner.size {
+			out = append(out, wrap(cur))
+			cur = nil
+		}
+		cur = append(cur, line)
+	}
+	if len(cur) > 0 || len(out) == 0 {
+		out = append(out, wrap(cur))
+	}
+	return out
+}
@@ -0,0 +1,207 @@
+package chunking
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/tokenizer"
+)
+
+// LengthFunc measures text for chunk sizing.
+type LengthFunc func(string) int
+
+// RuneLength sizes chunks in characters.
+func RuneLength(s string) int { return utf8.RuneCountInString(s) }
+
+// TokenLength sizes chunks in approximate embedding-model tokens.
+func TokenLength(s string) int { return tokenizer.CountTokens(s) }
+
+// Separator levels tried in order: paragraphs, lines, sentences, clauses,
+// words. Text that still does not fit is cut by runes.
+const (
+	levelParagraph = iota
+	levelLine
+	levelSentence
+	levelClause
+	levelWord
+	levelRune
+)
+
+var (
+	sentenceEndRe = regexp.MustCompile(`[.!?…]+["'»”)\]]*\s+`)
+	clauseEndRe   = regexp.MustCompile(`[;:,]\s+`)
+)
+
+// RecursiveSplitter splits text on the coarsest boundary (paragraph, line,
+// sentence, clause, word) that yields pieces within the size limit, then
+// packs adjacent pieces into chunks of up to size, repeating trailing pieces
+// of up to overlap as the start of the next chunk. Size and overlap are
+// measured with the splitter's LengthFunc.
+type RecursiveSplitter struct {
+	size    int
+	overlap int
+	length  LengthFunc
+}
+
+func NewRecursiveSplitter(size, overlap int, length LengthFunc) *RecursiveSplitter {
+	if size <= 0 {
+		size = 900
+	}
+	if overlap < 0 {
+		overlap = 0
+	}
+	if overlap >= size {
+		overlap = size / 4
+	}
+	if length == nil {
+		length = RuneLength
+	}
+	return &RecursiveSplitter{size: size, overlap: overlap, length: length}
+}
+
+func (s *RecursiveSplitter) Split(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	return s.merge(s.pieces(text, levelParagraph))
+}
+
+// pieces breaks text into consecutive segments that each fit the size
+// limit, splitting only as finely as needed. Segments keep their trailing
+// separators, so concatenating them reproduces text.
+func (s *RecursiveSplitter) pieces(text string, level int) []string {
+	if s.length(text) <= s.size {
+		return []string{text}
+	}
+	if level >= levelRune {
+		return s.cutRunes(text)
+	}
+	segs := splitAtLevel(text, level)
+	if len(segs) <= 1 {
+		return s.pieces(text, level+1)
+	}
+	out := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		out = append(out, s.pieces(seg, level+1)...)
+	}
+	return out
+}
+
+// units breaks text down to sentences regardless of size; sentences that
+// are still too long are split further.
+func (s *RecursiveSplitter) units(text string) []string {
+	segs := []string{text}
+	for level := levelParagraph; level <= levelSentence; level++ {
+		next := make([]string, 0, len(segs))
+		for _, seg := range segs {
+			next = append(next, splitAtLevel(seg, level)...)
+		}
+		segs = next
+	}
+	out := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		if strings.TrimSpace(seg) == "" {
+			continue
+		}
+		out = append(out, s.pieces(seg, levelClause)...)
+	}
+	return out
+}
+
+// cutRunes hard-splits text into runs of size runes. Every token spans at
+// least one rune, so the runs also fit a token-measured size.
+func (s *RecursiveSplitter) cutRunes(text string) []string {
+	runes := []rune(text)
+	out := make([]string, 0, len(runes)/s.size+1)
+	for start := 0; start < len(runes); start += s.size {
+		end := min(start+s.size, len(runes))
+		out = append(out, string(runes[start:end]))
+	}
+	return out
+}
+
+// merge packs pieces into chunks of up to size, carrying trailing pieces of
+// up to overlap into the next chunk.
+func (s *RecursiveSplitter) merge(pieces []string) []string {
+	var (
+		out     []string
+		cur     []string
+		lengths []int
+		curLen  int
+	)
+	emit := func() {
+		if chunk := strings.TrimSpace(strings.Join(cur, "")); chunk != "" {
+			out = append(out, chunk)
+		}
+	}
+	for _, p := range pieces {
+		n := s.length(p)
+		if curLen+n > s.size && len(cur) > 0 {
+			emit()
+			// Keep the longest tail within overlap that still leaves room
+			// for p.
+			keep, kept := len(cur), 0
+			for keep > 0 && kept+lengths[keep-1] <= s.overlap && kept+lengths[keep-1]+n <= s.size {
+				keep--
+				kept += lengths[keep]
+			}
+			cur, lengths, curLen = append([]string(nil), cur[keep:]...), append([]int(nil), lengths[keep:]...), kept
+		}
+		cur = append(cur, p)
+		lengths = append(lengths, n)
+		curLen += n
+	}
+	if len(cur) > 0 {
+		emit()
+	}
+	return out
+}
+
+// splitAtLevel splits text after each separator of the given level,
+// keeping the separator with the preceding segment.
+func splitAtLevel(text string, level int) []string {
+	switch level {
+	case levelParagraph:
+		return splitAfter(text, "\n\n")
+	case levelLine:
+		return splitAfter(text, "\n")
+	case levelSentence:
+		return splitAfterMatches(text, sentenceEndRe)
+	case levelClause:
+		return splitAfterMatches(text, clauseEndRe)
+	case levelWord:
+		return splitAfter(text, " ")
+	default:
+		return []string{text}
+	}
+}
+
+func splitAfter(text, sep string) []string {
+	parts := strings.SplitAfter(text, sep)
+	out := parts[:0]
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func splitAfterMatches(text string, re *regexp.Regexp) []string {
+	matches := re.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return []string{text}
+	}
+	out := make([]string, 0, len(matches)+1)
+	start := 0
+	for _, m := range matches {
+		out = append(out, text[start:m[1]])
+		start = m[1]
+	}
+	if start < len(text) {
+		out = append(out, text[start:])
+	}
+	return out
+}
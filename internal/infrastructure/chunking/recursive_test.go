@@ -0,0 +1,108 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRecursiveSplitter_CutsAtSentenceBoundaries(t *testing.T) {
+	s := NewRecursiveSplitter(60, 0, RuneLength)
+	text := "The VPN client must be installed first. Then import the profile from the portal. Finally, connect with your corporate account."
+
+	chunks := s.Split(text)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %q", chunks)
+	}
+	for _, c := range chunks {
+		if utf8.RuneCountInString(c) > 60 {
+			t.Fatalf("chunk exceeds size: %q", c)
+		}
+		if !strings.HasSuffix(c, ".") {
+			t.Fatalf("chunk does not end at a sentence boundary: %q", c)
+		}
+	}
+}
+
+func TestRecursiveSplitter_DoesNotCutWords(t *testing.T) {
+	s := NewRecursiveSplitter(20, 0, RuneLength)
+	text := "configuration management without any punctuation at all in this long line"
+
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(text) {
+		words[w] = true
+	}
+	for _, c := range s.Split(text) {
+		for _, w := range strings.Fields(c) {
+			if !words[w] {
+				t.Fatalf("word cut in chunk %q", c)
+			}
+		}
+	}
+}
+
+func TestRecursiveSplitter_CarriesOverlap(t *testing.T) {
+	s := NewRecursiveSplitter(50, 25, RuneLength)
+	text := "First sentence here. Second sentence here. Third sentence here. Fourth one."
+
+	chunks := s.Split(text)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %q", chunks)
+	}
+	if !strings.HasPrefix(chunks[1], "Second sentence here.") {
+		t.Fatalf("expected the last sentence of chunk 0 to open chunk 1, got %q", chunks)
+	}
+}
+
+func TestRecursiveSplitter_TokenLength(t *testing.T) {
+	s := NewRecursiveSplitter(8, 0, TokenLength)
+	text := "Alpha beta gamma. Delta epsilon zeta. Eta theta iota. Kappa lambda mu."
+
+	chunks := s.Split(text)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %q", chunks)
+	}
+	for _, c := range chunks {
+		if n := TokenLength(c); n > 8 {
+			t.Fatalf("chunk has %d tokens: %q", n, c)
+		}
+	}
+}
+
+func TestStructuredMarkdownSplitter_KeepsCodeBlockWhole(t *testing.T) {
+	s := NewStructuredMarkdownSplitter(NewRecursiveSplitter(120, 0, RuneLength))
+	code := "```go\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n```"
+	text := "# Setup\n\nSome prose before the example that is long enough to need its own chunk.\n\n" + code + "\n\nMore prose after it."
+
+	found := false
+	for _, c := range s.Split(text) {
+		if strings.Contains(c, "```go") {
+			if !strings.Contains(c, code) {
+				t.Fatalf("code block split: %q", c)
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("code block missing from chunks")
+	}
+}
+
+func TestStructuredMarkdownSplitter_RepeatsTableHeader(t *testing.T) {
+	s := NewStructuredMarkdownSplitter(NewRecursiveSplitter(80, 0, RuneLength))
+	var sb strings.Builder
+	sb.WriteString("| host | port |\n|------|------|\n")
+	for i := 0; i < 10; i++ {
+		sb.WriteString("| server.example.org | 8443 |\n")
+	}
+
+	chunks := s.Split(sb.String())
+	if len(chunks) < 2 {
+		t.Fatalf("expected the table to be split, got %q", chunks)
+	}
+	for _, c := range chunks {
+		if !strings.HasPrefix(c, "| host | port |\n|------|------|") {
+			t.Fatalf("table part without header: %q", c)
+		}
+	}
+}
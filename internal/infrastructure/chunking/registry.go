@@ -1,16 +1,23 @@
 package chunking
 
-import "github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+import (
+	"strings"
 
-// Registry selects a Chunker based on source type, falling back to a default.
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// Registry selects a Chunker based on source type or MIME type, falling back
+// to a default.
 type Registry struct {
 	chunkers map[string]ports.Chunker
+	byMime   map[string]ports.Chunker
 	fallback ports.Chunker
 }
 
 func NewRegistry(fallback ports.Chunker) *Registry {
 	return &Registry{
 		chunkers: make(map[string]ports.Chunker),
+		byMime:   make(map[string]ports.Chunker),
 		fallback: fallback,
 	}
 }
@@ -25,3 +32,19 @@ func (r *Registry) ForSource(sourceType string) ports.Chunker {
 	}
 	return r.fallback
 }
+
+// RegisterMimeType maps a MIME type to chunker. MIME registrations take
+// precedence over source types in ForDocument.
+func (r *Registry) RegisterMimeType(mimeType string, chunker ports.Chunker) {
+	r.byMime[strings.ToLower(mimeType)] = chunker
+}
+
+// ForDocument returns the chunker for a document's MIME type when one is
+// registered, otherwise the chunker for its source type.
+func (r *Registry) ForDocument(sourceType, mimeType string) ports.Chunker {
+	base := strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	if c, ok := r.byMime[base]; ok {
+		return c
+	}
+	return r.ForSource(sourceType)
+}
@@ -0,0 +1,130 @@
+package chunking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	defaultBreakpointPercentile = 90
+	semanticEmbedBatch          = 64
+)
+
+// SemanticSplitter embeds every sentence and cuts where the cosine distance
+// between neighbouring sentences is in the top percentile, i.e. where the
+// topic shifts. Groups are then packed by the inner RecursiveSplitter, so
+// no chunk exceeds its size. If embedding fails the inner splitter's
+// chunks are returned.
+type SemanticSplitter struct {
+	embedder   ports.Embedder
+	inner      *RecursiveSplitter
+	percentile float64
+}
+
+func NewSemanticSplitter(embedder ports.Embedder, inner *RecursiveSplitter, breakpointPercentile float64) *SemanticSplitter {
+	if breakpointPercentile <= 0 || breakpointPercentile >= 100 {
+		breakpointPercentile = defaultBreakpointPercentile
+	}
+	return &SemanticSplitter{embedder: embedder, inner: inner, percentile: breakpointPercentile}
+}
+
+func (s *SemanticSplitter) Split(text string) []string {
+	chunks, err := s.SplitContext(context.Background(), text)
+	if err != nil {
+		return s.inner.Split(text)
+	}
+	return chunks
+}
+
+func (s *SemanticSplitter) SplitContext(ctx context.Context, text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+	units := s.inner.units(text)
+	if len(units) < 3 {
+		return s.inner.Split(text), nil
+	}
+
+	vectors, err := s.embedUnits(ctx, units)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		slog.Warn("semantic_chunking_fallback", "units", len(units), "error", err)
+		return s.inner.Split(text), nil
+	}
+
+	distances := make([]float64, len(units)-1)
+	for i := range distances {
+		distances[i] = 1 - cosineSimilarity(vectors[i], vectors[i+1])
+	}
+	threshold := percentile(distances, s.percentile)
+
+	// A group shorter than a quarter of the size limit is too small to be a
+	// useful chunk and is folded into the next group.
+	minGroup := s.inner.size / 4
+	var (
+		out      []string
+		group    []string
+		groupLen int
+	)
+	for i, unit := range units {
+		group = append(group, unit)
+		groupLen += s.inner.length(unit)
+		last := i == len(units)-1
+		if last || (distances[i] > threshold && groupLen >= minGroup) {
+			out = append(out, s.inner.merge(group)...)
+			group, groupLen = nil, 0
+		}
+	}
+	return out, nil
+}
+
+func (s *SemanticSplitter) embedUnits(ctx context.Context, units []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(units))
+	for start := 0; start < len(units); start += semanticEmbedBatch {
+		end := min(start+semanticEmbedBatch, len(units))
+		batch, err := s.embedder.Embed(ctx, units[start:end])
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, batch...)
+	}
+	if len(vectors) != len(units) {
+		return nil, fmt.Errorf("embedder returned %d vectors for %d sentences", len(vectors), len(units))
+	}
+	return vectors, nil
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// percentile returns the p-th percentile of values by linear interpolation.
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	pos := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
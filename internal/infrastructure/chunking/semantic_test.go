@@ -0,0 +1,68 @@
+package chunking
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// topicEmbedder maps each sentence to a unit vector of its topic keyword.
+type topicEmbedder struct {
+	err error
+}
+
+func (e topicEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		switch {
+		case strings.Contains(text, "VPN"):
+			out[i] = []float32{1, 0}
+		default:
+			out[i] = []float32{0, 1}
+		}
+	}
+	return out, nil
+}
+
+func (e topicEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+const semanticText = "The VPN needs a client. The VPN profile is on the portal. Connect the VPN at login. " +
+	"Vacation requests go to HR. Approval takes two days. Unused days expire in March."
+
+func TestSemanticSplitter_CutsAtTopicShift(t *testing.T) {
+	s := NewSemanticSplitter(topicEmbedder{}, NewRecursiveSplitter(200, 0, RuneLength), 50)
+
+	chunks, err := s.SplitContext(context.Background(), semanticText)
+	if err != nil {
+		t.Fatalf("SplitContext() error = %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("expected two topic chunks, got %q", chunks)
+	}
+	if !strings.HasSuffix(chunks[0], "Connect the VPN at login.") || !strings.HasPrefix(chunks[1], "Vacation requests") {
+		t.Fatalf("unexpected cut: %q", chunks)
+	}
+}
+
+func TestSemanticSplitter_FallsBackOnEmbedError(t *testing.T) {
+	inner := NewRecursiveSplitter(200, 0, RuneLength)
+	s := NewSemanticSplitter(topicEmbedder{err: errors.New("embedder down")}, inner, 50)
+
+	chunks, err := s.SplitContext(context.Background(), semanticText)
+	if err != nil {
+		t.Fatalf("SplitContext() error = %v", err)
+	}
+	if want := inner.Split(semanticText); len(chunks) != len(want) || chunks[0] != want[0] {
+		t.Fatalf("expected recursive fallback %q, got %q", want, chunks)
+	}
+}
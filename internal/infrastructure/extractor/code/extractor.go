@@ -0,0 +1,49 @@
+// Package code extracts source files. The text is kept verbatim apart from
+// line endings, so indentation survives for language-aware chunking.
+package code
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open source file: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read source file: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent returns the source text with the BOM removed, line endings
+// normalized to "\n" and surrounding blank lines trimmed.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(raw) || bytes.IndexByte(raw, 0) >= 0 {
+		return "", fmt.Errorf("source file is not UTF-8 text: %s", doc.Filename)
+	}
+	text := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.Trim(text, "\n"), nil
+}
@@ -0,0 +1,26 @@
+package code
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestCodeExtractor_KeepsIndentation(t *testing.T) {
+	raw := []byte("\xef\xbb\xbf\r\n\tfunc main() {\r\n\t\trun()\r\n\t}\r\n\r\n")
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "main.go"}, raw)
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	if text != "\tfunc main() {\n\t\trun()\n\t}" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestCodeExtractor_RejectsBinary(t *testing.T) {
+	_, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "a.out"}, []byte("ELF\x00\x01"))
+	if err == nil {
+		t.Fatal("expected error for binary content")
+	}
+}
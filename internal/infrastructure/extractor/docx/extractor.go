@@ -7,10 +7,12 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mdtable"
 )
 
 type Extractor struct {
@@ -22,56 +24,108 @@ func NewExtractor(storage ports.ObjectStorage) *Extractor {
 }
 
 func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	sections, err := e.ExtractSections(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+// ExtractSections returns the document as Markdown: headings from heading
+// styles, lists as "-" items and tables as pipe tables. When the file
+// records page breaks (explicit or last rendered by Word) there is one
+// section per page, located by page number; otherwise a single section.
+func (e *Extractor) ExtractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error) {
 	reader, err := e.storage.Open(ctx, doc.StoragePath)
 	if err != nil {
-		return "", fmt.Errorf("open docx document: %w", err)
+		return nil, fmt.Errorf("open docx document: %w", err)
 	}
 	defer func() { _ = reader.Close() }()
 
 	raw, err := io.ReadAll(reader)
 	if err != nil {
-		return "", fmt.Errorf("read docx document: %w", err)
+		return nil, fmt.Errorf("read docx document: %w", err)
+	}
+	return sectionsFromDocx(doc, raw)
+}
+
+// ExtractContent extracts text from raw DOCX content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	sections, err := sectionsFromDocx(doc, raw)
+	if err != nil {
+		return "", err
 	}
+	return domain.JoinSections(sections), nil
+}
 
+func sectionsFromDocx(doc *domain.Document, raw []byte) ([]domain.Section, error) {
 	if len(raw) == 0 {
-		return "", fmt.Errorf("empty docx file: %s", doc.Filename)
+		return nil, fmt.Errorf("empty docx file: %s", doc.Filename)
 	}
 
 	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
 	if err != nil {
-		return "", fmt.Errorf("open docx as zip: %w", err)
+		return nil, fmt.Errorf("open docx as zip: %w", err)
 	}
 
 	for _, f := range zr.File {
 		if f.Name == "word/document.xml" {
 			rc, err := f.Open()
 			if err != nil {
-				return "", fmt.Errorf("open word/document.xml: %w", err)
+				return nil, fmt.Errorf("open word/document.xml: %w", err)
 			}
 			defer func() { _ = rc.Close() }()
 
 			xmlData, err := io.ReadAll(rc)
 			if err != nil {
-				return "", fmt.Errorf("read word/document.xml: %w", err)
+				return nil, fmt.Errorf("read word/document.xml: %w", err)
 			}
 
-			text := extractTextFromDocxXML(xmlData)
-			if text == "" {
-				return "", fmt.Errorf("no text content in docx: %s", doc.Filename)
+			sections := extractDocxSections(xmlData)
+			if domain.JoinSections(sections) == "" {
+				return nil, fmt.Errorf("no text content in docx: %s", doc.Filename)
 			}
-			return text, nil
+			return sections, nil
 		}
 	}
 
-	return "", fmt.Errorf("word/document.xml not found in docx: %s", doc.Filename)
+	return nil, fmt.Errorf("word/document.xml not found in docx: %s", doc.Filename)
 }
 
 const wordMLNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
 
 func extractTextFromDocxXML(data []byte) string {
+	return domain.JoinSections(extractDocxSections(data))
+}
+
+// docxParser accumulates Markdown blocks per page while walking
+// word/document.xml.
+type docxParser struct {
+	blocks    []docxBlock
+	page      int
+	paginated bool
+	// breakPending defers a page break until content follows it, so an
+	// explicit break and Word's rendered break at the same spot count once.
+	breakPending bool
+
+	para    strings.Builder
+	heading int
+	list    bool
+
+	tableDepth int
+	rows       [][]string
+	row        []string
+	cell       []string
+}
+
+type docxBlock struct {
+	page int
+	text string
+}
+
+func extractDocxSections(data []byte) []domain.Section {
+	p := &docxParser{page: 1}
 	decoder := xml.NewDecoder(bytes.NewReader(data))
-	var paragraphs []string
-	var currentParagraph strings.Builder
 	inText := false
 
 	for {
@@ -82,36 +136,166 @@ func extractTextFromDocxXML(data []byte) string {
 
 		switch t := tok.(type) {
 		case xml.StartElement:
-			if t.Name.Local == "t" && t.Name.Space == wordMLNamespace {
-				inText = true
+			if t.Name.Space != wordMLNamespace {
+				continue
 			}
-			if t.Name.Local == "p" && t.Name.Space == wordMLNamespace {
-				if currentParagraph.Len() > 0 {
-					paragraphs = append(paragraphs, strings.TrimSpace(currentParagraph.String()))
-					currentParagraph.Reset()
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "p":
+				p.para.Reset()
+				p.heading, p.list = 0, false
+			case "pStyle":
+				p.heading = headingLevel(wordAttr(t, "val"))
+			case "outlineLvl":
+				if n, err := strconv.Atoi(wordAttr(t, "val")); err == nil && n < 6 && p.heading == 0 {
+					p.heading = n + 1
+				}
+			case "numPr":
+				p.list = true
+			case "tab":
+				p.para.WriteString("\t")
+			case "br":
+				if wordAttr(t, "type") == "page" {
+					p.pageBreak()
+				} else {
+					p.para.WriteString("\n")
+				}
+			case "lastRenderedPageBreak":
+				p.pageBreak()
+			case "tbl":
+				p.tableDepth++
+				if p.tableDepth == 1 {
+					p.rows = nil
+				}
+			case "tr":
+				if p.tableDepth == 1 {
+					p.row = nil
+				}
+			case "tc":
+				if p.tableDepth == 1 {
+					p.cell = nil
 				}
 			}
 		case xml.EndElement:
-			if t.Name.Local == "t" && t.Name.Space == wordMLNamespace {
+			if t.Name.Space != wordMLNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
 				inText = false
+			case "p":
+				p.endParagraph()
+			case "tc":
+				if p.tableDepth == 1 {
+					p.row = append(p.row, strings.Join(p.cell, " "))
+				}
+			case "tr":
+				if p.tableDepth == 1 {
+					p.rows = append(p.rows, p.row)
+				}
+			case "tbl":
+				p.tableDepth--
+				if p.tableDepth == 0 {
+					p.add(mdtable.Render(p.rows))
+				}
 			}
 		case xml.CharData:
 			if inText {
-				currentParagraph.Write(t)
+				p.para.Write(t)
 			}
 		}
 	}
+	return p.sections()
+}
+
+// pageBreak starts a new page at the next content. Breaks inside tables are
+// ignored so a table stays in one section.
+func (p *docxParser) pageBreak() {
+	if p.tableDepth > 0 {
+		return
+	}
+	p.paginated = true
+	if text := strings.TrimSpace(p.para.String()); text != "" {
+		// A break in the middle of a paragraph splits it across pages.
+		p.add(text)
+		p.para.Reset()
+	}
+	p.breakPending = true
+}
+
+func (p *docxParser) endParagraph() {
+	text := strings.TrimSpace(p.para.String())
+	p.para.Reset()
+	if text == "" {
+		return
+	}
+	if p.tableDepth > 0 {
+		p.cell = append(p.cell, text)
+		return
+	}
+	switch {
+	case p.heading > 0:
+		text = strings.Repeat("#", p.heading) + " " + strings.Join(strings.Fields(text), " ")
+	case p.list:
+		text = "- " + text
+	}
+	p.add(text)
+}
+
+func (p *docxParser) add(text string) {
+	if text == "" {
+		return
+	}
+	if p.breakPending {
+		if len(p.blocks) > 0 {
+			p.page++
+		}
+		p.breakPending = false
+	}
+	p.blocks = append(p.blocks, docxBlock{page: p.page, text: text})
+}
 
-	if currentParagraph.Len() > 0 {
-		paragraphs = append(paragraphs, strings.TrimSpace(currentParagraph.String()))
+func (p *docxParser) sections() []domain.Section {
+	var out []domain.Section
+	for i := 0; i < len(p.blocks); {
+		j := i
+		var texts []string
+		for j < len(p.blocks) && p.blocks[j].page == p.blocks[i].page {
+			texts = append(texts, p.blocks[j].text)
+			j++
+		}
+		section := domain.Section{Text: strings.Join(texts, "\n\n")}
+		if p.paginated {
+			section.Locator.Page = p.blocks[i].page
+		}
+		out = append(out, section)
+		i = j
 	}
+	return out
+}
 
-	var nonEmpty []string
-	for _, p := range paragraphs {
-		if p != "" {
-			nonEmpty = append(nonEmpty, p)
+// headingLevel maps Word's built-in heading styles ("Heading1", "Title")
+// to a Markdown heading level, or 0.
+func headingLevel(style string) int {
+	lower := strings.ToLower(style)
+	switch {
+	case lower == "title":
+		return 1
+	case strings.HasPrefix(lower, "heading"):
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(lower, "heading")))
+		if err == nil && n >= 1 {
+			return min(n, 6)
 		}
 	}
+	return 0
+}
 
-	return strings.Join(nonEmpty, "\n")
+func wordAttr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
 }
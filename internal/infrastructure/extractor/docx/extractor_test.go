@@ -24,6 +24,7 @@ func (f *storageFake) Open(_ context.Context, _ string) (io.ReadCloser, error) {
 	}
 	return io.NopCloser(strings.NewReader(string(f.data))), nil
 }
+func (f *storageFake) Delete(context.Context, string) error { return nil }
 
 func TestExtractTextFromDocxXML(t *testing.T) {
 	xml := `<?xml version="1.0" encoding="UTF-8"?>
@@ -117,3 +118,53 @@ func TestExtractDocx_StorageError(t *testing.T) {
 		t.Errorf("expected wrapped storage error, got: %v", err)
 	}
 }
+
+func TestExtractSections_HeadingsTablesAndPages(t *testing.T) {
+	documentXML := `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Budget</w:t></w:r></w:p>
+<w:p><w:r><w:t>Totals by quarter.</w:t></w:r></w:p>
+<w:tbl>
+<w:tr><w:tc><w:p><w:r><w:t>Quarter</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Amount</w:t></w:r></w:p></w:tc></w:tr>
+<w:tr><w:tc><w:p><w:r><w:t>Q1</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>10|20</w:t></w:r></w:p></w:tc></w:tr>
+</w:tbl>
+<w:p><w:r><w:br w:type="page"/></w:r></w:p>
+<w:p><w:r><w:lastRenderedPageBreak/><w:t>Next page.</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>Item</w:t></w:r></w:p>
+</w:body>
+</w:document>`
+
+	ext := NewExtractor(&storageFake{data: makeDocxZip(documentXML)})
+	sections, err := ext.ExtractSections(context.Background(), &domain.Document{StoragePath: "b.docx", Filename: "b.docx"})
+	if err != nil {
+		t.Fatalf("ExtractSections() error = %v", err)
+	}
+	want := []domain.Section{
+		{Locator: domain.Locator{Page: 1}, Text: "# Budget\n\nTotals by quarter.\n\n| Quarter | Amount |\n| --- | --- |\n| Q1 | 10\\|20 |"},
+		{Locator: domain.Locator{Page: 2}, Text: "Next page.\n\n- Item"},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("expected %d sections, got %+v", len(want), sections)
+	}
+	for i := range want {
+		if sections[i] != want[i] {
+			t.Fatalf("section %d: got %+v, want %+v", i, sections[i], want[i])
+		}
+	}
+}
+
+func TestExtractSections_UnpaginatedDocument(t *testing.T) {
+	documentXML := `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:r><w:t>Only text.</w:t></w:r></w:p>
+</w:body></w:document>`
+
+	ext := NewExtractor(&storageFake{data: makeDocxZip(documentXML)})
+	sections, err := ext.ExtractSections(context.Background(), &domain.Document{StoragePath: "a.docx", Filename: "a.docx"})
+	if err != nil {
+		t.Fatalf("ExtractSections() error = %v", err)
+	}
+	if len(sections) != 1 || !sections[0].Locator.IsZero() || sections[0].Text != "Only text." {
+		t.Fatalf("unexpected sections: %+v", sections)
+	}
+}
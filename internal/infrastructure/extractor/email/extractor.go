@@ -0,0 +1,319 @@
+// Package email extracts text from RFC 822 messages (.eml) and mbox
+// archives: headers, the message body and the names of attachments. The
+// attachments themselves are returned by ExtractAttachments and ingested as
+// child documents.
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	exthtml "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/html"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mimetype"
+)
+
+var headerDecoder = &mime.WordDecoder{CharsetReader: charset.NewReaderLabel}
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	raw, err := e.read(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts every message of raw .eml or mbox content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return "", fmt.Errorf("empty email file: %s", doc.Filename)
+	}
+	messages := splitMessages(raw)
+
+	var parts []string
+	for i, msg := range messages {
+		text, err := renderMessage(msg)
+		if err != nil {
+			if len(messages) == 1 {
+				return "", fmt.Errorf("parse email %s: %w", doc.Filename, err)
+			}
+			slog.Warn("mbox_message_parse_failed", "filename", doc.Filename, "message", i+1, "error", err)
+			continue
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("no text content in email: %s", doc.Filename)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
+
+// ExtractAttachments returns the attachments of every message in order.
+// Attached messages are returned whole, so they are ingested as emails of
+// their own.
+func (e *Extractor) ExtractAttachments(ctx context.Context, doc *domain.Document) ([]domain.Attachment, error) {
+	raw, err := e.read(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+	var attachments []domain.Attachment
+	for _, msg := range splitMessages(raw) {
+		parsed, err := mail.ReadMessage(bytes.NewReader(msg))
+		if err != nil {
+			continue
+		}
+		var r render
+		walkPart(&r, parsed.Header, parsed.Body)
+		attachments = append(attachments, r.attachments...)
+	}
+	return attachments, nil
+}
+
+func (e *Extractor) read(ctx context.Context, doc *domain.Document) ([]byte, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("open email: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read email: %w", err)
+	}
+	return raw, nil
+}
+
+// splitMessages returns the messages of an mbox archive, or raw itself when
+// it is a single message.
+func splitMessages(raw []byte) [][]byte {
+	if bytes.HasPrefix(raw, []byte("From ")) {
+		return splitMbox(raw)
+	}
+	return [][]byte{raw}
+}
+
+// splitMbox splits an mbox archive on its "From " separator lines and
+// undoes the ">From " quoting of body lines.
+func splitMbox(raw []byte) [][]byte {
+	var (
+		messages [][]byte
+		current  bytes.Buffer
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	scanner.Buffer(make([]byte, 64*1024), len(raw)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if bytes.HasPrefix(line, []byte("From ")) {
+			if current.Len() > 0 {
+				messages = append(messages, bytes.Clone(current.Bytes()))
+				current.Reset()
+			}
+			continue
+		}
+		if bytes.HasPrefix(bytes.TrimLeft(line, ">"), []byte("From ")) && line[0] == '>' {
+			line = line[1:]
+		}
+		current.Write(line)
+		current.WriteByte('\n')
+	}
+	if current.Len() > 0 {
+		messages = append(messages, current.Bytes())
+	}
+	return messages
+}
+
+// renderMessage renders the main headers, the body and the attachment
+// names of one message.
+func renderMessage(raw []byte) (string, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, name := range []string{"Subject", "From", "To", "Cc", "Date"} {
+		if v := msg.Header.Get(name); v != "" {
+			if decoded, err := headerDecoder.DecodeHeader(v); err == nil {
+				v = decoded
+			}
+			lines = append(lines, name+": "+strings.TrimSpace(v))
+		}
+	}
+	text := strings.Join(lines, "\n")
+
+	var r render
+	walkPart(&r, msg.Header, msg.Body)
+	if body := strings.TrimSpace(r.body.String()); body != "" {
+		text += "\n\n" + body
+	}
+	if len(r.attachments) > 0 {
+		names := make([]string, len(r.attachments))
+		for i, a := range r.attachments {
+			names[i] = "Attachment: " + a.Filename
+		}
+		text += "\n\n" + strings.Join(names, "\n")
+	}
+	return strings.TrimSpace(text), nil
+}
+
+type render struct {
+	body        strings.Builder
+	attachments []domain.Attachment
+}
+
+// header is satisfied by both mail.Header and textproto.MIMEHeader.
+type header interface {
+	Get(key string) string
+}
+
+// walkPart renders a MIME entity: text parts go to the body, multipart
+// entities are walked (preferring text/plain within alternatives), and
+// everything else is an attachment.
+func walkPart(r *render, h header, body io.Reader) {
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+	disposition, dparams, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+	filename := dparams["filename"]
+	if filename == "" {
+		filename = params["name"]
+	}
+	if decoded, err := headerDecoder.DecodeHeader(filename); err == nil {
+		filename = decoded
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		walkMultipart(r, mediaType, params["boundary"], body)
+		return
+	}
+
+	data, err := io.ReadAll(decodeTransfer(h.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		slog.Warn("email_part_read_failed", "content_type", mediaType, "error", err)
+		return
+	}
+
+	isText := mediaType == "text/plain" || mediaType == "text/html"
+	if isText && disposition != "attachment" {
+		if text := partText(mediaType, params["charset"], data); text != "" {
+			if r.body.Len() > 0 {
+				r.body.WriteString("\n\n")
+			}
+			r.body.WriteString(text)
+		}
+		return
+	}
+	if filename == "" {
+		filename = "attachment"
+		if mediaType == mimetype.EML {
+			filename = "message.eml"
+		}
+	}
+	// Declared attachment types are often generic; detect the real one the
+	// way uploads do.
+	r.attachments = append(r.attachments, domain.Attachment{
+		Filename: filename,
+		MimeType: mimetype.Detect(filename, mediaType, data[:min(len(data), mimetype.SniffLen)]),
+		Data:     data,
+	})
+}
+
+func walkMultipart(r *render, mediaType, boundary string, body io.Reader) {
+	if boundary == "" {
+		return
+	}
+	mr := multipart.NewReader(body, boundary)
+	if mediaType != "multipart/alternative" {
+		for {
+			part, err := mr.NextPart()
+			if err != nil {
+				return
+			}
+			walkPart(r, part.Header, part)
+		}
+	}
+
+	// Alternatives carry the same content; use the plain text one when
+	// present, since it needs no markup stripping.
+	var plain, other *render
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			break
+		}
+		var alt render
+		walkPart(&alt, part.Header, part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch {
+		case mediaType == "text/plain" && plain == nil:
+			plain = &alt
+		case other == nil && alt.body.Len() > 0:
+			other = &alt
+		}
+	}
+	chosen := plain
+	if chosen == nil || chosen.body.Len() == 0 {
+		chosen = other
+	}
+	if chosen == nil {
+		return
+	}
+	if r.body.Len() > 0 && chosen.body.Len() > 0 {
+		r.body.WriteString("\n\n")
+	}
+	r.body.WriteString(chosen.body.String())
+	r.attachments = append(r.attachments, chosen.attachments...)
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// partText decodes a text part to UTF-8 and strips HTML markup.
+func partText(mediaType, charsetLabel string, data []byte) string {
+	if mediaType == "text/html" {
+		contentType := "text/html"
+		if charsetLabel != "" {
+			contentType += "; charset=" + charsetLabel
+		}
+		return exthtml.Text(data, contentType)
+	}
+	if charsetLabel != "" && !strings.EqualFold(charsetLabel, "utf-8") && !strings.EqualFold(charsetLabel, "us-ascii") {
+		if r, err := charset.NewReaderLabel(charsetLabel, bytes.NewReader(data)); err == nil {
+			if decoded, err := io.ReadAll(r); err == nil {
+				data = decoded
+			}
+		}
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(data), "\r\n", "\n"))
+}
@@ -0,0 +1,119 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mimetype"
+)
+
+type storageFake struct {
+	data []byte
+}
+
+func (f *storageFake) Save(context.Context, string, io.Reader) error { return nil }
+func (f *storageFake) Open(context.Context, string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+func (f *storageFake) Delete(context.Context, string) error { return nil }
+
+const multipartMessage = "From: =?UTF-8?B?0JjQstCw0L0=?= <ivan@example.org>\r\n" +
+	"To: team@example.org\r\n" +
+	"Subject: Quarterly report\r\n" +
+	"Date: Mon, 1 Jul 2024 10:00:00 +0000\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Numbers are attached =E2=80=94 see below.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Numbers are attached</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/csv; name=\"q3.csv\"\r\n" +
+	"Content-Disposition: attachment; filename=\"q3.csv\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"cmV2ZW51ZSwx\r\nMDA=\r\n" +
+	"--outer--\r\n"
+
+func TestEmailExtractor_MultipartListsAttachments(t *testing.T) {
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "report.eml"}, []byte(multipartMessage))
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	want := "Subject: Quarterly report\n" +
+		"From: Иван <ivan@example.org>\n" +
+		"To: team@example.org\n" +
+		"Date: Mon, 1 Jul 2024 10:00:00 +0000\n\n" +
+		"Numbers are attached — see below.\n\n" +
+		"Attachment: q3.csv"
+	if text != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", text, want)
+	}
+}
+
+func TestEmailExtractor_ExtractAttachments(t *testing.T) {
+	mbox := "From ivan@example.org Mon Jul  1 10:00:00 2024\n" + strings.ReplaceAll(multipartMessage, "\r\n", "\n") +
+		"From bob@example.org Mon Jul  1 11:00:00 2024\n" +
+		"Subject: Fwd\nContent-Type: multipart/mixed; boundary=b\n\n--b\n" +
+		"Content-Type: message/rfc822\n\nSubject: Inner\n\nHi\n--b--\n"
+	ext := NewExtractor(&storageFake{data: []byte(mbox)})
+
+	attachments, err := ext.ExtractAttachments(context.Background(), &domain.Document{Filename: "inbox.mbox"})
+	if err != nil {
+		t.Fatalf("ExtractAttachments() error = %v", err)
+	}
+	if len(attachments) != 2 {
+		t.Fatalf("expected 2 attachments, got %+v", attachments)
+	}
+	if a := attachments[0]; a.Filename != "q3.csv" || a.MimeType != "text/csv" || string(a.Data) != "revenue,100" {
+		t.Fatalf("unexpected csv attachment: %+v", a)
+	}
+	// Forwarded messages stay whole so they are ingested as emails.
+	if a := attachments[1]; a.Filename != "message.eml" || a.MimeType != mimetype.EML || !strings.Contains(string(a.Data), "Subject: Inner") {
+		t.Fatalf("unexpected message attachment: %+v", a)
+	}
+}
+
+func TestEmailExtractor_HTMLOnlyAndCharset(t *testing.T) {
+	msg := "Subject: =?koi8-r?B?8NLJ18XU?=\n" +
+		"Content-Type: text/html; charset=utf-8\n\n" +
+		"<html><body><p>Hello <b>there</b></p></body></html>\n"
+
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "a.eml"}, []byte(msg))
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	if text != "Subject: Привет\n\nHello there" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestEmailExtractor_Mbox(t *testing.T) {
+	mbox := "From alice@example.org Mon Jul  1 10:00:00 2024\n" +
+		"Subject: First\n\nHello\n>From the start.\n\n" +
+		"From bob@example.org Mon Jul  1 11:00:00 2024\n" +
+		"Subject: Second\n\nBye\n"
+
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "inbox.mbox"}, []byte(mbox))
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	want := "Subject: First\n\nHello\nFrom the start.\n\nSubject: Second\n\nBye"
+	if text != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+}
@@ -0,0 +1,140 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	exthtml "github.com/kirillkom/personal-ai-assistant/internal/infrastructure/extractor/html"
+)
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open epub document: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read epub document: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts the book title and the text of every chapter in
+// reading (spine) order.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return "", fmt.Errorf("empty epub file: %s", doc.Filename)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return "", fmt.Errorf("open epub as zip: %w", err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := readXML(files, "META-INF/container.xml", &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", fmt.Errorf("no rootfile in epub: %s", doc.Filename)
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg struct {
+		Title    string `xml:"metadata>title"`
+		Manifest []struct {
+			ID        string `xml:"id,attr"`
+			Href      string `xml:"href,attr"`
+			MediaType string `xml:"media-type,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err := readXML(files, opfPath, &pkg); err != nil {
+		return "", err
+	}
+	hrefs := make(map[string]string, len(pkg.Manifest))
+	for _, item := range pkg.Manifest {
+		if strings.Contains(item.MediaType, "html") {
+			hrefs[item.ID] = item.Href
+		}
+	}
+
+	var chapters []string
+	base := path.Dir(opfPath)
+	for _, ref := range pkg.Spine {
+		href, ok := hrefs[ref.IDRef]
+		if !ok {
+			continue
+		}
+		href, _, _ = strings.Cut(href, "#")
+		data, err := readFile(files, path.Join(base, href))
+		if err != nil {
+			return "", err
+		}
+		if text := exthtml.Text(data, "application/xhtml+xml"); text != "" {
+			chapters = append(chapters, text)
+		}
+	}
+	if len(chapters) == 0 {
+		return "", fmt.Errorf("no text content in epub: %s", doc.Filename)
+	}
+	if title := strings.TrimSpace(pkg.Title); title != "" {
+		chapters = append([]string{title}, chapters...)
+	}
+	return strings.Join(chapters, "\n\n"), nil
+}
+
+func readXML(files map[string]*zip.File, name string, v any) error {
+	data, err := readFile(files, name)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s: %w", name, err)
+	}
+	return nil
+}
+
+func readFile(files map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := files[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in epub", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", name, err)
+	}
+	defer func() { _ = rc.Close() }()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
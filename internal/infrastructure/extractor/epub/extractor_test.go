@@ -0,0 +1,65 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func buildEPUB(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestEPUBExtractor_SpineOrder(t *testing.T) {
+	raw := buildEPUB(t, map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<container xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`,
+		"OEBPS/content.opf": `<package xmlns="http://www.idpf.org/2007/opf" xmlns:dc="http://purl.org/dc/elements/1.1/">
+<metadata><dc:title>Field Guide</dc:title></metadata>
+<manifest>
+<item id="c2" href="text/ch2.xhtml" media-type="application/xhtml+xml"/>
+<item id="c1" href="text/ch1.xhtml" media-type="application/xhtml+xml"/>
+<item id="css" href="style.css" media-type="text/css"/>
+</manifest>
+<spine><itemref idref="c1"/><itemref idref="c2"/></spine></package>`,
+		"OEBPS/text/ch1.xhtml": `<html><body><h1>Chapter one</h1><p>Birds.</p></body></html>`,
+		"OEBPS/text/ch2.xhtml": `<html><body><h1>Chapter two</h1><p>Trees.</p></body></html>`,
+	})
+
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "guide.epub"}, raw)
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	want := "Field Guide\n\nChapter one\nBirds.\n\nChapter two\nTrees."
+	if text != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+}
+
+func TestEPUBExtractor_MissingContainer(t *testing.T) {
+	raw := buildEPUB(t, map[string]string{"mimetype": "application/epub+zip"})
+	_, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "bad.epub"}, raw)
+	if err == nil || !strings.Contains(err.Error(), "container.xml") {
+		t.Fatalf("expected missing container error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package html
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/htmltext"
+)
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open html document: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read html document: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts the title and visible text of raw HTML content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	text := Text(raw, doc.MimeType)
+	if text == "" {
+		return "", fmt.Errorf("no text content in html: %s", doc.Filename)
+	}
+	return text, nil
+}
+
+// Text decodes raw HTML in its declared or detected charset and returns
+// the title followed by the body text.
+func Text(raw []byte, contentType string) string {
+	enc, _, _ := charset.DetermineEncoding(raw, contentType)
+	if decoded, err := enc.NewDecoder().Bytes(raw); err == nil {
+		raw = decoded
+	}
+	title, body := htmltext.Extract(string(raw))
+	title, body = strings.TrimSpace(title), strings.TrimSpace(body)
+	if title == "" || strings.HasPrefix(body, title) {
+		return body
+	}
+	if body == "" {
+		return title
+	}
+	return title + "\n\n" + body
+}
@@ -0,0 +1,55 @@
+package html
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type storageFake struct {
+	data []byte
+}
+
+func (f *storageFake) Save(context.Context, string, io.Reader) error { return nil }
+func (f *storageFake) Open(context.Context, string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(string(f.data))), nil
+}
+func (f *storageFake) Delete(context.Context, string) error { return nil }
+
+func TestHTMLExtractor_Extract(t *testing.T) {
+	page := `<html><head><title>Runbook</title><style>p{}</style></head>
+<body><h1>Deploy</h1><p>Run <b>make</b> release.</p><script>alert(1)</script></body></html>`
+	ext := NewExtractor(&storageFake{data: []byte(page)})
+
+	text, err := ext.Extract(context.Background(), &domain.Document{Filename: "runbook.html", MimeType: "text/html"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if text != "Runbook\n\nDeploy\nRun make release." {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestHTMLExtractor_DecodesCharset(t *testing.T) {
+	// "Привет" in windows-1251.
+	page := append([]byte(`<html><head><meta charset="windows-1251"></head><body><p>`), 0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2)
+	page = append(page, []byte(`</p></body></html>`)...)
+
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "old.htm"}, page)
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	if text != "Привет" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestHTMLExtractor_Empty(t *testing.T) {
+	_, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "blank.html"}, []byte("<html><body><script>x</script></body></html>"))
+	if err == nil {
+		t.Fatal("expected error for page without text")
+	}
+}
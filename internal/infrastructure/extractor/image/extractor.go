@@ -0,0 +1,85 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// Extractor turns an image into text: the OCR result and, when a captioner
+// is configured, a description from a vision model. Either may be nil.
+type Extractor struct {
+	storage   ports.ObjectStorage
+	ocr       ports.OCREngine
+	captioner ports.ImageCaptioner
+}
+
+func NewExtractor(storage ports.ObjectStorage, ocr ports.OCREngine, captioner ports.ImageCaptioner) *Extractor {
+	return &Extractor{storage: storage, ocr: ocr, captioner: captioner}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open image: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read image: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts text from raw image content.
+func (e *Extractor) ExtractContent(ctx context.Context, doc *domain.Document, raw []byte) (string, error) {
+	if e.ocr == nil && e.captioner == nil {
+		return "", fmt.Errorf("image %s: neither OCR nor image captioning is configured", doc.Filename)
+	}
+	if len(raw) == 0 {
+		return "", fmt.Errorf("empty image file: %s", doc.Filename)
+	}
+
+	// OCR and captioning complement each other, so a failure of one is
+	// logged and the other is still used.
+	var (
+		caption, text string
+		errs          []error
+		err           error
+	)
+	if e.captioner != nil {
+		mimeType := strings.TrimSpace(strings.SplitN(doc.MimeType, ";", 2)[0])
+		if caption, err = e.captioner.CaptionImage(ctx, raw, mimeType); err != nil {
+			slog.Warn("image_caption_failed", "filename", doc.Filename, "error", err)
+			errs = append(errs, err)
+		}
+	}
+	if e.ocr != nil {
+		if text, err = e.ocr.RecognizeImage(ctx, raw); err != nil {
+			slog.Warn("image_ocr_failed", "filename", doc.Filename, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	var parts []string
+	if caption = strings.TrimSpace(caption); caption != "" {
+		parts = append(parts, "Image description: "+caption)
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		parts = append(parts, text)
+	}
+	if len(parts) == 0 {
+		if len(errs) > 0 {
+			return "", fmt.Errorf("extract image %s: %w", doc.Filename, errors.Join(errs...))
+		}
+		return "", fmt.Errorf("no text content in image: %s", doc.Filename)
+	}
+	return strings.Join(parts, "\n\n"), nil
+}
@@ -0,0 +1,82 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type storageFake struct {
+	data []byte
+}
+
+func (f *storageFake) Save(context.Context, string, io.Reader) error { return nil }
+func (f *storageFake) Open(context.Context, string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(string(f.data))), nil
+}
+func (f *storageFake) Delete(context.Context, string) error { return nil }
+
+type ocrFake struct {
+	text string
+	err  error
+}
+
+func (f *ocrFake) RecognizeImage(context.Context, []byte) (string, error) { return f.text, f.err }
+func (f *ocrFake) RecognizePDFPage(context.Context, []byte, int) (string, error) {
+	return "", errors.New("not a pdf")
+}
+
+type captionerFake struct {
+	caption  string
+	err      error
+	mimeType string
+}
+
+func (f *captionerFake) CaptionImage(_ context.Context, _ []byte, mimeType string) (string, error) {
+	f.mimeType = mimeType
+	return f.caption, f.err
+}
+
+var screenshot = &domain.Document{StoragePath: "shot.png", Filename: "shot.png", MimeType: "image/png"}
+
+func TestExtract_CombinesCaptionAndOCR(t *testing.T) {
+	captioner := &captionerFake{caption: "Settings dialog of the VPN client."}
+	ext := NewExtractor(&storageFake{data: []byte("png")}, &ocrFake{text: "Server: vpn.example.org"}, captioner)
+
+	text, err := ext.Extract(context.Background(), screenshot)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	want := "Image description: Settings dialog of the VPN client.\n\nServer: vpn.example.org"
+	if text != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+	if captioner.mimeType != "image/png" {
+		t.Fatalf("unexpected mime type %q", captioner.mimeType)
+	}
+}
+
+func TestExtract_OCRFailureKeepsCaption(t *testing.T) {
+	ext := NewExtractor(&storageFake{data: []byte("png")}, &ocrFake{err: errors.New("tesseract missing")}, &captionerFake{caption: "A chart."})
+
+	text, err := ext.Extract(context.Background(), screenshot)
+	if err != nil || text != "Image description: A chart." {
+		t.Fatalf("got %q, %v", text, err)
+	}
+}
+
+func TestExtract_Errors(t *testing.T) {
+	if _, err := NewExtractor(&storageFake{data: []byte("png")}, nil, nil).Extract(context.Background(), screenshot); err == nil {
+		t.Fatal("expected error without OCR and captioner")
+	}
+	if _, err := NewExtractor(&storageFake{data: []byte("png")}, &ocrFake{}, nil).Extract(context.Background(), screenshot); err == nil || !strings.Contains(err.Error(), "no text content") {
+		t.Fatalf("expected no text content error, got %v", err)
+	}
+	if _, err := NewExtractor(&storageFake{}, &ocrFake{text: "x"}, nil).Extract(context.Background(), screenshot); err == nil {
+		t.Fatal("expected error for empty image")
+	}
+}
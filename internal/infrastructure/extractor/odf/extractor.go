@@ -0,0 +1,213 @@
+// Package odf extracts text from OpenDocument text documents, spreadsheets
+// and presentations (ODT, ODS, ODP).
+package odf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const (
+	officeNamespace = "urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+	textNamespace   = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+	tableNamespace  = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+	drawNamespace   = "urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"
+
+	// maxRepeatedCells caps how often a repeated non-empty cell is expanded.
+	maxRepeatedCells = 64
+)
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open opendocument file: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read opendocument file: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts text from raw ODT, ODS or ODP content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	if len(raw) == 0 {
+		return "", fmt.Errorf("empty opendocument file: %s", doc.Filename)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return "", fmt.Errorf("open opendocument as zip: %w", err)
+	}
+	for _, f := range zr.File {
+		if f.Name != "content.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("open content.xml: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return "", fmt.Errorf("read content.xml: %w", err)
+		}
+		text := extractTextFromContentXML(data)
+		if text == "" {
+			return "", fmt.Errorf("no text content in opendocument file: %s", doc.Filename)
+		}
+		return text, nil
+	}
+	return "", fmt.Errorf("content.xml not found in opendocument file: %s", doc.Filename)
+}
+
+// extractTextFromContentXML renders content.xml as lines: headings as
+// Markdown headings, paragraphs as they are, table rows as cells joined by
+// " | ", spreadsheet tables under "Sheet: <name>" and presentation pages
+// under "Slide N".
+func extractTextFromContentXML(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var (
+		lines       []string
+		labels      = make(map[int]bool)
+		para        strings.Builder
+		headLevel   int
+		paraDepth   int
+		spreadsheet bool
+		slides      int
+
+		inCell     bool
+		cell       strings.Builder
+		cellRepeat int
+		row        []string
+	)
+	attr := func(el xml.StartElement, space, local string) string {
+		for _, a := range el.Attr {
+			if a.Name.Space == space && a.Name.Local == local {
+				return a.Value
+			}
+		}
+		return ""
+	}
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Space == officeNamespace && t.Name.Local == "spreadsheet":
+				spreadsheet = true
+			case t.Name.Space == drawNamespace && t.Name.Local == "page":
+				slides++
+				labels[len(lines)] = true
+				lines = append(lines, fmt.Sprintf("Slide %d", slides))
+			case t.Name.Space == tableNamespace && t.Name.Local == "table":
+				if spreadsheet {
+					labels[len(lines)] = true
+					lines = append(lines, "Sheet: "+attr(t, tableNamespace, "name"))
+				}
+			case t.Name.Space == tableNamespace && t.Name.Local == "table-row":
+				row = row[:0]
+			case t.Name.Space == tableNamespace && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = true
+				cell.Reset()
+				cellRepeat = 1
+				if n, err := strconv.Atoi(attr(t, tableNamespace, "number-columns-repeated")); err == nil && n > 1 {
+					cellRepeat = n
+				}
+			case t.Name.Space == textNamespace && (t.Name.Local == "p" || t.Name.Local == "h"):
+				if paraDepth == 0 {
+					para.Reset()
+					headLevel = 0
+					if t.Name.Local == "h" {
+						headLevel = 1
+						if n, err := strconv.Atoi(attr(t, textNamespace, "outline-level")); err == nil && n > 0 {
+							headLevel = min(n, 6)
+						}
+					}
+				}
+				paraDepth++
+			case t.Name.Space == textNamespace && t.Name.Local == "s":
+				n, err := strconv.Atoi(attr(t, textNamespace, "c"))
+				if err != nil || n < 1 {
+					n = 1
+				}
+				para.WriteString(strings.Repeat(" ", n))
+			case t.Name.Space == textNamespace && (t.Name.Local == "tab" || t.Name.Local == "line-break"):
+				para.WriteString(" ")
+			}
+		case xml.EndElement:
+			switch {
+			case t.Name.Space == textNamespace && (t.Name.Local == "p" || t.Name.Local == "h"):
+				paraDepth--
+				if paraDepth > 0 {
+					continue
+				}
+				p := strings.TrimSpace(para.String())
+				if p == "" {
+					continue
+				}
+				switch {
+				case inCell:
+					if cell.Len() > 0 {
+						cell.WriteString(" ")
+					}
+					cell.WriteString(p)
+				case headLevel > 0:
+					lines = append(lines, strings.Repeat("#", headLevel)+" "+p)
+				default:
+					lines = append(lines, p)
+				}
+			case t.Name.Space == tableNamespace && (t.Name.Local == "table-cell" || t.Name.Local == "covered-table-cell"):
+				inCell = false
+				for i := 0; i < min(cellRepeat, maxRepeatedCells); i++ {
+					row = append(row, cell.String())
+				}
+			case t.Name.Space == tableNamespace && t.Name.Local == "table-row":
+				end := len(row)
+				for end > 0 && row[end-1] == "" {
+					end--
+				}
+				if end > 0 {
+					lines = append(lines, strings.Join(row[:end], " | "))
+				}
+			}
+		case xml.CharData:
+			if paraDepth > 0 {
+				para.Write(t)
+			}
+		}
+	}
+
+	// Drop sheet and slide labels that ended up without content.
+	var out []string
+	for i, line := range lines {
+		if labels[i] && (i == len(lines)-1 || labels[i+1]) {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,63 @@
+package odf
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+const contentHeader = `<office:document-content
+ xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+ xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+ xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+ xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0">`
+
+func TestExtractTextFromContentXML_Text(t *testing.T) {
+	content := contentHeader + `<office:body><office:text>
+<text:h text:outline-level="2">Setup</text:h>
+<text:p>Install<text:s text:c="2"/>the <text:span>agent</text:span>.</text:p>
+<table:table table:name="Table1"><table:table-row>
+<table:table-cell><text:p>Port</text:p></table:table-cell><table:table-cell><text:p>8080</text:p></table:table-cell>
+</table:table-row></table:table>
+</office:text></office:body></office:document-content>`
+
+	got := extractTextFromContentXML([]byte(content))
+	want := "## Setup\nInstall  the agent.\nPort | 8080"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestExtractTextFromContentXML_Spreadsheet(t *testing.T) {
+	content := contentHeader + `<office:body><office:spreadsheet>
+<table:table table:name="Budget">
+<table:table-row><table:table-cell><text:p>Item</text:p></table:table-cell><table:table-cell><text:p>Cost</text:p></table:table-cell><table:table-cell table:number-columns-repeated="16382"/></table:table-row>
+<table:table-row><table:table-cell table:number-columns-repeated="2"><text:p>x</text:p></table:table-cell></table:table-row>
+<table:table-row table:number-rows-repeated="1000"><table:table-cell table:number-columns-repeated="16384"/></table:table-row>
+</table:table>
+<table:table table:name="Empty"/>
+</office:spreadsheet></office:body></office:document-content>`
+
+	got := extractTextFromContentXML([]byte(content))
+	want := "Sheet: Budget\nItem | Cost\nx | x"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestODFExtractor_MissingContent(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if _, err := zw.Create("mimetype"); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "x.odt"}, buf.Bytes()); err == nil {
+		t.Fatal("expected error without content.xml")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"strings"
 
 	lpdf "github.com/ledongthuc/pdf"
@@ -13,54 +14,112 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 )
 
+// defaultMaxOCRPages bounds OCR work for one scanned document.
+const defaultMaxOCRPages = 50
+
+// Options configures OCR of pages without a text layer. A nil OCR keeps
+// text-layer extraction only.
+type Options struct {
+	OCR         ports.OCREngine
+	MaxOCRPages int
+}
+
 type Extractor struct {
-	storage ports.ObjectStorage
+	storage     ports.ObjectStorage
+	ocr         ports.OCREngine
+	maxOCRPages int
 }
 
 func NewExtractor(storage ports.ObjectStorage) *Extractor {
-	return &Extractor{storage: storage}
+	return NewExtractorWithOptions(storage, Options{})
+}
+
+func NewExtractorWithOptions(storage ports.ObjectStorage, opts Options) *Extractor {
+	maxPages := opts.MaxOCRPages
+	if maxPages <= 0 {
+		maxPages = defaultMaxOCRPages
+	}
+	return &Extractor{storage: storage, ocr: opts.OCR, maxOCRPages: maxPages}
 }
 
 func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	sections, err := e.ExtractSections(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+// ExtractSections returns one section per page that has text, located by
+// its page number.
+func (e *Extractor) ExtractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error) {
 	reader, err := e.storage.Open(ctx, doc.StoragePath)
 	if err != nil {
-		return "", fmt.Errorf("open pdf document: %w", err)
+		return nil, fmt.Errorf("open pdf document: %w", err)
 	}
 	defer func() { _ = reader.Close() }()
 
 	raw, err := io.ReadAll(reader)
 	if err != nil {
-		return "", fmt.Errorf("read pdf document: %w", err)
+		return nil, fmt.Errorf("read pdf document: %w", err)
 	}
+	return e.pages(ctx, doc, raw)
+}
 
+// ExtractContent extracts text from raw PDF content.
+func (e *Extractor) ExtractContent(ctx context.Context, doc *domain.Document, raw []byte) (string, error) {
+	sections, err := e.pages(ctx, doc, raw)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+func (e *Extractor) pages(ctx context.Context, doc *domain.Document, raw []byte) ([]domain.Section, error) {
 	if len(raw) == 0 {
-		return "", fmt.Errorf("empty pdf file: %s", doc.Filename)
+		return nil, fmt.Errorf("empty pdf file: %s", doc.Filename)
 	}
 
 	r, err := lpdf.NewReader(bytes.NewReader(raw), int64(len(raw)))
 	if err != nil {
-		return "", fmt.Errorf("parse pdf: %w", err)
+		return nil, fmt.Errorf("parse pdf: %w", err)
 	}
 
-	var pages []string
+	var pages []domain.Section
+	ocrPages := 0
 	for i := 1; i <= r.NumPage(); i++ {
 		page := r.Page(i)
 		if page.V.IsNull() {
 			continue
 		}
-		text, err := page.GetPlainText(nil)
-		if err != nil {
-			continue
+		var t string
+		if text, err := page.GetPlainText(nil); err == nil {
+			t = strings.TrimSpace(text)
+		}
+		// A page without a text layer is most likely a scan.
+		if t == "" && e.ocr != nil && ocrPages < e.maxOCRPages {
+			ocrPages++
+			text, err := e.ocr.RecognizePDFPage(ctx, raw, i)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				slog.Warn("pdf_ocr_page_failed", "filename", doc.Filename, "page", i, "error", err)
+				continue
+			}
+			t = strings.TrimSpace(text)
 		}
-		t := strings.TrimSpace(text)
 		if t != "" {
-			pages = append(pages, t)
+			pages = append(pages, domain.Section{Locator: domain.Locator{Page: i}, Text: t})
 		}
 	}
 
 	if len(pages) == 0 {
-		return "", fmt.Errorf("no text content in pdf: %s (might be scanned/image-only)", doc.Filename)
+		if e.ocr != nil {
+			return nil, fmt.Errorf("no text content in pdf: %s (OCR found no text)", doc.Filename)
+		}
+		return nil, fmt.Errorf("no text content in pdf: %s (might be scanned/image-only)", doc.Filename)
 	}
 
-	return strings.Join(pages, "\n\n"), nil
+	return pages, nil
 }
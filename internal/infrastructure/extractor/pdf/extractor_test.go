@@ -23,6 +23,7 @@ func (f *storageFake) Open(_ context.Context, _ string) (io.ReadCloser, error) {
 	}
 	return io.NopCloser(strings.NewReader(string(f.data))), nil
 }
+func (f *storageFake) Delete(context.Context, string) error { return nil }
 
 func TestPDFExtractor_EmptyFile(t *testing.T) {
 	storage := &storageFake{data: []byte{}}
@@ -62,10 +63,14 @@ var minimalPDFNoText = []byte(
 
 // validPDFWithText is a hand-crafted minimal valid PDF containing the text "Hello World".
 // It has proper cross-reference table and a text stream on page 1.
-var validPDFWithText = buildValidPDF()
+var validPDFWithText = buildPDF("BT /F1 12 Tf 100 700 Td (Hello World) Tj ET")
 
-func buildValidPDF() []byte {
-	// Minimal PDF 1.4 with one page containing "Hello World" text.
+// scannedPDF has a valid page whose content stream draws no text, like a
+// page holding only a scanned image.
+var scannedPDF = buildPDF("")
+
+func buildPDF(stream string) []byte {
+	// Minimal PDF 1.4 with one page drawing the given content stream.
 	var b strings.Builder
 	offsets := make([]int, 6) // objects 1-5, index 0 unused
 
@@ -84,7 +89,6 @@ func buildValidPDF() []byte {
 	b.WriteString("3 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>\nendobj\n")
 
 	// Object 4: Content stream
-	stream := "BT /F1 12 Tf 100 700 Td (Hello World) Tj ET"
 	offsets[4] = b.Len()
 	fmt.Fprintf(&b, "4 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(stream), stream)
 
@@ -121,6 +125,18 @@ func TestExtractPDF_ValidWithText(t *testing.T) {
 	}
 }
 
+func TestExtractPDF_SectionsCarryPageNumbers(t *testing.T) {
+	ext := NewExtractor(&storageFake{data: validPDFWithText})
+
+	sections, err := ext.ExtractSections(context.Background(), &domain.Document{StoragePath: "hello.pdf", Filename: "hello.pdf"})
+	if err != nil {
+		t.Fatalf("ExtractSections() error = %v", err)
+	}
+	if len(sections) != 1 || sections[0].Locator.Page != 1 || !strings.Contains(sections[0].Text, "Hello World") {
+		t.Fatalf("unexpected sections: %+v", sections)
+	}
+}
+
 func TestExtractPDF_EmptyFile(t *testing.T) {
 	storage := &storageFake{data: minimalPDFNoText}
 	ext := NewExtractor(storage)
@@ -149,3 +165,51 @@ func TestExtractPDF_StorageError(t *testing.T) {
 		t.Errorf("expected wrapped storage error, got: %v", err)
 	}
 }
+
+type ocrFake struct {
+	text  string
+	err   error
+	pages []int
+}
+
+func (f *ocrFake) RecognizeImage(context.Context, []byte) (string, error) { return f.text, f.err }
+
+func (f *ocrFake) RecognizePDFPage(_ context.Context, _ []byte, page int) (string, error) {
+	f.pages = append(f.pages, page)
+	return f.text, f.err
+}
+
+func TestExtractPDF_OCRForPageWithoutText(t *testing.T) {
+	ocr := &ocrFake{text: "Scanned invoice 42"}
+	ext := NewExtractorWithOptions(&storageFake{data: scannedPDF}, Options{OCR: ocr})
+
+	text, err := ext.Extract(context.Background(), &domain.Document{StoragePath: "scan.pdf", Filename: "scan.pdf"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if text != "Scanned invoice 42" || len(ocr.pages) != 1 || ocr.pages[0] != 1 {
+		t.Fatalf("unexpected OCR result %q for pages %v", text, ocr.pages)
+	}
+}
+
+func TestExtractPDF_OCRSkippedForTextLayer(t *testing.T) {
+	ocr := &ocrFake{text: "should not be used"}
+	ext := NewExtractorWithOptions(&storageFake{data: validPDFWithText}, Options{OCR: ocr})
+
+	text, err := ext.Extract(context.Background(), &domain.Document{StoragePath: "hello.pdf", Filename: "hello.pdf"})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if !strings.Contains(text, "Hello World") || len(ocr.pages) != 0 {
+		t.Fatalf("expected text layer without OCR, got %q (ocr pages %v)", text, ocr.pages)
+	}
+}
+
+func TestExtractPDF_OCRFailure(t *testing.T) {
+	ext := NewExtractorWithOptions(&storageFake{data: scannedPDF}, Options{OCR: &ocrFake{err: errors.New("tesseract missing")}})
+
+	_, err := ext.Extract(context.Background(), &domain.Document{StoragePath: "scan.pdf", Filename: "scan.pdf"})
+	if err == nil || !strings.Contains(err.Error(), "no text content") {
+		t.Fatalf("expected no text content error, got %v", err)
+	}
+}
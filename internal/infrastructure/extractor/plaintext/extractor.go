@@ -30,7 +30,11 @@ func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string,
 	if err != nil {
 		return "", fmt.Errorf("read source document: %w", err)
 	}
+	return e.ExtractContent(ctx, doc, raw)
+}
 
+// ExtractContent extracts text from raw file content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
 	if !utf8.Valid(raw) {
 		return "", fmt.Errorf("unsupported binary format for MVP: %s", doc.Filename)
 	}
@@ -25,6 +25,7 @@ func (f *fakeStorage) Open(_ context.Context, _ string) (io.ReadCloser, error) {
 	}
 	return io.NopCloser(strings.NewReader(f.content)), nil
 }
+func (f *fakeStorage) Delete(context.Context, string) error { return nil }
 
 func TestExtract_ValidUTF8(t *testing.T) {
 	e := NewExtractor(&fakeStorage{content: "  hello world  "})
@@ -0,0 +1,153 @@
+package pptx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+const drawingMLNamespace = "http://schemas.openxmlformats.org/drawingml/2006/main"
+
+var slideNameRe = regexp.MustCompile(`^ppt/slides/slide(\d+)\.xml$`)
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	sections, err := e.ExtractSections(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+// ExtractSections returns one section per slide with text, located by slide
+// number.
+func (e *Extractor) ExtractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return nil, fmt.Errorf("open pptx document: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("read pptx document: %w", err)
+	}
+	return slideSections(doc, raw)
+}
+
+// ExtractContent extracts the text of every slide, in slide order, each
+// under a "Slide N" line.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	sections, err := slideSections(doc, raw)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+func slideSections(doc *domain.Document, raw []byte) ([]domain.Section, error) {
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("empty pptx file: %s", doc.Filename)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("open pptx as zip: %w", err)
+	}
+
+	type slide struct {
+		num  int
+		file *zip.File
+	}
+	var slides []slide
+	for _, f := range zr.File {
+		if m := slideNameRe.FindStringSubmatch(f.Name); m != nil {
+			num, _ := strconv.Atoi(m[1])
+			slides = append(slides, slide{num: num, file: f})
+		}
+	}
+	if len(slides) == 0 {
+		return nil, fmt.Errorf("no slides found in pptx: %s", doc.Filename)
+	}
+	// slide10.xml must follow slide9.xml, not slide1.xml.
+	sort.Slice(slides, func(i, j int) bool { return slides[i].num < slides[j].num })
+
+	var sections []domain.Section
+	for _, s := range slides {
+		rc, err := s.file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", s.file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", s.file.Name, err)
+		}
+		if text := extractTextFromSlideXML(data); text != "" {
+			sections = append(sections, domain.Section{
+				Locator: domain.Locator{Slide: s.num},
+				Text:    fmt.Sprintf("Slide %d\n%s", s.num, text),
+			})
+		}
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no text content in pptx: %s", doc.Filename)
+	}
+	return sections, nil
+}
+
+// extractTextFromSlideXML returns the slide's DrawingML paragraphs, one per
+// line.
+func extractTextFromSlideXML(data []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var paragraphs []string
+	var current strings.Builder
+	inText := false
+
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == drawingMLNamespace && t.Name.Local == "t" {
+				inText = true
+			}
+		case xml.EndElement:
+			if t.Name.Space != drawingMLNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if p := strings.TrimSpace(current.String()); p != "" {
+					paragraphs = append(paragraphs, p)
+				}
+				current.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+	return strings.Join(paragraphs, "\n")
+}
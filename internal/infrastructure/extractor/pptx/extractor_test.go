@@ -0,0 +1,71 @@
+package pptx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func slideXML(paragraphs ...string) string {
+	var body string
+	for _, p := range paragraphs {
+		body += `<a:p><a:r><a:t>` + p + `</a:t></a:r></a:p>`
+	}
+	return `<p:sld xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">
+<p:cSld><p:spTree><p:sp><p:txBody>` + body + `</p:txBody></p:sp></p:spTree></p:cSld></p:sld>`
+}
+
+func buildPPTX(t *testing.T, slides map[int]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for num, content := range slides {
+		w, err := zw.Create(fmt.Sprintf("ppt/slides/slide%d.xml", num))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPPTXExtractor_SlidesInNumericOrder(t *testing.T) {
+	raw := buildPPTX(t, map[int]string{
+		10: slideXML("Summary"),
+		2:  slideXML("Agenda", "Budget"),
+		1:  slideXML("Q3 review"),
+	})
+
+	text, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "q3.pptx"}, raw)
+	if err != nil {
+		t.Fatalf("ExtractContent() error = %v", err)
+	}
+	want := "Slide 1\nQ3 review\n\nSlide 2\nAgenda\nBudget\n\nSlide 10\nSummary"
+	if text != want {
+		t.Fatalf("got %q, want %q", text, want)
+	}
+
+	sections, err := slideSections(&domain.Document{Filename: "q3.pptx"}, raw)
+	if err != nil {
+		t.Fatalf("slideSections() error = %v", err)
+	}
+	if len(sections) != 3 || sections[2].Locator.Slide != 10 {
+		t.Fatalf("expected slide locators, got %+v", sections)
+	}
+}
+
+func TestPPTXExtractor_NoSlides(t *testing.T) {
+	raw := buildPPTX(t, nil)
+	if _, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "empty.pptx"}, raw); err == nil {
+		t.Fatal("expected error for pptx without slides")
+	}
+}
@@ -1,8 +1,10 @@
 package extractor
 
 import (
+	"context"
 	"strings"
 
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 )
 
@@ -18,14 +20,26 @@ func NewRegistry(fallback ports.TextExtractor) *Registry {
 	}
 }
 
+// Register maps a MIME type to ext. A "type/*" pattern such as "image/*"
+// matches every subtype without an exact registration.
 func (r *Registry) Register(mimeType string, ext ports.TextExtractor) {
 	r.extractors[mimeType] = ext
 }
 
 func (r *Registry) ForMimeType(mimeType string) ports.TextExtractor {
-	base := strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0])
+	base := strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
 	if ext, ok := r.extractors[base]; ok {
 		return ext
 	}
+	if major, _, ok := strings.Cut(base, "/"); ok {
+		if ext, ok := r.extractors[major+"/*"]; ok {
+			return ext
+		}
+	}
 	return r.fallback
 }
+
+// Extract extracts text from doc with the extractor for its MIME type.
+func (r *Registry) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	return r.ForMimeType(doc.MimeType).Extract(ctx, doc)
+}
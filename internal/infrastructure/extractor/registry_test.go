@@ -55,4 +55,22 @@ func TestRegistry_ForMimeType_WithCharset(t *testing.T) {
 	}
 }
 
+func TestRegistry_ForMimeType_Wildcard(t *testing.T) {
+	reg := NewRegistry(&fakeExtractor{text: "fallback"})
+	reg.Register("image/*", &fakeExtractor{text: "image-text"})
+	reg.Register("image/svg+xml", &fakeExtractor{text: "svg-text"})
+
+	for mimeType, want := range map[string]string{
+		"image/png":     "image-text",
+		"IMAGE/JPEG":    "image-text",
+		"image/svg+xml": "svg-text",
+		"text/plain":    "fallback",
+	} {
+		text, _ := reg.ForMimeType(mimeType).Extract(context.Background(), &domain.Document{})
+		if text != want {
+			t.Errorf("%s: expected %q, got %q", mimeType, want, text)
+		}
+	}
+}
+
 var _ ports.ExtractorRegistry = (*Registry)(nil)
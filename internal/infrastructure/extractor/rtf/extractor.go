@@ -0,0 +1,240 @@
+package rtf
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+var blankLinesRe = regexp.MustCompile(`\n{3,}`)
+
+// skippedDestinations hold formatting tables, metadata and embedded
+// objects rather than document text.
+var skippedDestinations = map[string]bool{
+	"fonttbl": true, "colortbl": true, "stylesheet": true, "info": true,
+	"pict": true, "object": true, "header": true, "headerl": true,
+	"headerr": true, "headerf": true, "footer": true, "footerl": true,
+	"footerr": true, "footerf": true, "listtable": true, "listoverridetable": true,
+	"revtbl": true, "rsidtbl": true, "generator": true, "filetbl": true,
+	"latentstyles": true, "themedata": true, "colorschememapping": true,
+	"datastore": true, "xmlnstbl": true, "mmathPr": true, "fldinst": true,
+}
+
+type Extractor struct {
+	storage ports.ObjectStorage
+}
+
+func NewExtractor(storage ports.ObjectStorage) *Extractor {
+	return &Extractor{storage: storage}
+}
+
+func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	reader, err := e.storage.Open(ctx, doc.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("open rtf document: %w", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	raw, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("read rtf document: %w", err)
+	}
+	return e.ExtractContent(ctx, doc, raw)
+}
+
+// ExtractContent extracts the plain text of raw RTF content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	if !strings.HasPrefix(strings.TrimLeft(string(raw[:min(len(raw), 16)]), " \t\r\n"), `{\rtf`) {
+		return "", fmt.Errorf("not an rtf document: %s", doc.Filename)
+	}
+	text := extractText(raw)
+	if text == "" {
+		return "", fmt.Errorf("no text content in rtf: %s", doc.Filename)
+	}
+	return text, nil
+}
+
+type group struct {
+	skip bool
+	uc   int // fallback characters to skip after \uN
+}
+
+// extractText is a minimal RTF reader: it keeps text, paragraph and table
+// breaks, decodes \'hh escapes in the document code page and \uN Unicode
+// escapes, and skips non-text destinations.
+func extractText(raw []byte) string {
+	var (
+		out       strings.Builder
+		pending   []byte // \'hh bytes awaiting decoding
+		codepage  = 1252
+		stack     []group
+		cur       = group{uc: 1}
+		skipChars int // fallback characters left to skip after \uN
+	)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		out.WriteString(decodeCodepage(pending, codepage))
+		pending = pending[:0]
+	}
+	emit := func(s string) {
+		if cur.skip {
+			return
+		}
+		flush()
+		out.WriteString(s)
+	}
+
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch c {
+		case '{':
+			flush()
+			stack = append(stack, cur)
+			skipChars = 0
+		case '}':
+			flush()
+			if len(stack) > 0 {
+				cur = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			skipChars = 0
+		case '\r', '\n':
+		case '\\':
+			if i+1 >= len(raw) {
+				break
+			}
+			next := raw[i+1]
+			switch {
+			case next == '\'' && i+3 < len(raw):
+				i += 3
+				if skipChars > 0 {
+					skipChars--
+					continue
+				}
+				if b, err := strconv.ParseUint(string(raw[i-1:i+1]), 16, 8); err == nil && !cur.skip {
+					pending = append(pending, byte(b))
+				}
+			case isLetter(next):
+				j := i + 1
+				for j < len(raw) && isLetter(raw[j]) {
+					j++
+				}
+				word := string(raw[i+1 : j])
+				k := j
+				if k < len(raw) && (raw[k] == '-' || isDigit(raw[k])) {
+					k++
+					for k < len(raw) && isDigit(raw[k]) {
+						k++
+					}
+				}
+				param, hasParam := 0, k > j
+				if hasParam {
+					param, _ = strconv.Atoi(string(raw[j:k]))
+				}
+				if k < len(raw) && raw[k] == ' ' {
+					k++ // the delimiting space belongs to the control word
+				}
+				i = k - 1
+
+				switch word {
+				case "par", "line", "row", "sect", "page":
+					emit("\n")
+				case "tab":
+					emit("\t")
+				case "cell":
+					emit(" | ")
+				case "emdash":
+					emit("—")
+				case "endash":
+					emit("–")
+				case "bullet":
+					emit("•")
+				case "lquote", "rquote":
+					emit("'")
+				case "ldblquote", "rdblquote":
+					emit("\"")
+				case "ansicpg":
+					codepage = param
+				case "uc":
+					cur.uc = param
+				case "u":
+					if param < 0 {
+						param += 65536
+					}
+					emit(string(rune(param)))
+					skipChars = cur.uc
+				default:
+					if skippedDestinations[word] {
+						cur.skip = true
+					}
+				}
+			default:
+				i++
+				switch next {
+				case '*':
+					cur.skip = true
+				case '\\', '{', '}':
+					emit(string(next))
+				case '~':
+					emit(" ")
+				case '_':
+					emit("-")
+				case '\r', '\n':
+					emit("\n")
+				}
+			}
+		default:
+			if skipChars > 0 {
+				skipChars--
+				continue
+			}
+			emit(string(c))
+		}
+	}
+	flush()
+
+	lines := strings.Split(out.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t|")
+	}
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// decodeCodepage decodes b from a Windows code page, or as UTF-8 for 65001.
+func decodeCodepage(b []byte, codepage int) string {
+	label := "windows-" + strconv.Itoa(codepage)
+	switch codepage {
+	case 65001:
+		return string(b)
+	case 936:
+		label = "gbk"
+	case 932:
+		label = "shift_jis"
+	case 949:
+		label = "euc-kr"
+	case 950:
+		label = "big5"
+	}
+	enc, _ := charset.Lookup(label)
+	if enc == nil {
+		enc, _ = charset.Lookup("windows-1252")
+	}
+	decoded, err := enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return string(b)
+	}
+	return string(decoded)
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
@@ -0,0 +1,53 @@
+package rtf
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestExtractText(t *testing.T) {
+	tests := []struct {
+		name string
+		rtf  string
+		want string
+	}{
+		{
+			name: "paragraphs and skipped destinations",
+			rtf: `{\rtf1\ansi\deff0{\fonttbl{\f0 Times New Roman;}}{\colortbl;\red0\green0\blue0;}` +
+				`{\*\generator Riched20;}\pard\b Release notes\b0\par` + "\n" +
+				`Fixed the \{proxy\} timeout.\par}`,
+			want: "Release notes\nFixed the {proxy} timeout.",
+		},
+		{
+			name: "code page escapes",
+			rtf:  `{\rtf1\ansi\ansicpg1251 \'cf\'f0\'e8\'e2\'e5\'f2\par}`,
+			want: "Привет",
+		},
+		{
+			name: "unicode escapes skip fallback",
+			rtf:  `{\rtf1\ansi\uc1\u1052?\u1080?\u1088? ok}`,
+			want: "Мир ok",
+		},
+		{
+			name: "table cells",
+			rtf:  `{\rtf1\ansi\trowd A\cell B\cell\row C\cell D\cell\row}`,
+			want: "A | B\nC | D",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractText([]byte(tt.rtf)); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRTFExtractor_RejectsNonRTF(t *testing.T) {
+	_, err := NewExtractor(nil).ExtractContent(context.Background(), &domain.Document{Filename: "x.rtf"}, []byte("plain text"))
+	if err == nil {
+		t.Fatal("expected error for non-rtf content")
+	}
+}
@@ -6,14 +6,20 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/xuri/excelize/v2"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mdtable"
 )
 
+// rowsPerSection bounds one section of a sheet. Every section repeats the
+// header row so a chunk of a long sheet still says what its columns mean.
+const rowsPerSection = 50
+
 type Extractor struct {
 	storage ports.ObjectStorage
 }
@@ -23,19 +29,41 @@ func NewExtractor(storage ports.ObjectStorage) *Extractor {
 }
 
 func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string, error) {
+	sections, err := e.ExtractSections(ctx, doc)
+	if err != nil {
+		return "", err
+	}
+	return domain.JoinSections(sections), nil
+}
+
+// ExtractSections returns every sheet as Markdown tables located by sheet
+// name. A "Row" column carries the spreadsheet row numbers.
+func (e *Extractor) ExtractSections(ctx context.Context, doc *domain.Document) ([]domain.Section, error) {
 	reader, err := e.storage.Open(ctx, doc.StoragePath)
 	if err != nil {
-		return "", fmt.Errorf("open spreadsheet: %w", err)
+		return nil, fmt.Errorf("open spreadsheet: %w", err)
 	}
 	defer func() { _ = reader.Close() }()
 
 	raw, err := io.ReadAll(reader)
 	if err != nil {
-		return "", fmt.Errorf("read spreadsheet: %w", err)
+		return nil, fmt.Errorf("read spreadsheet: %w", err)
+	}
+	return sectionsFromSpreadsheet(doc, raw)
+}
+
+// ExtractContent extracts text from raw CSV or XLSX content.
+func (e *Extractor) ExtractContent(_ context.Context, doc *domain.Document, raw []byte) (string, error) {
+	sections, err := sectionsFromSpreadsheet(doc, raw)
+	if err != nil {
+		return "", err
 	}
+	return domain.JoinSections(sections), nil
+}
 
+func sectionsFromSpreadsheet(doc *domain.Document, raw []byte) ([]domain.Section, error) {
 	if len(raw) == 0 {
-		return "", fmt.Errorf("empty spreadsheet file: %s", doc.Filename)
+		return nil, fmt.Errorf("empty spreadsheet file: %s", doc.Filename)
 	}
 
 	mime := strings.SplitN(doc.MimeType, ";", 2)[0]
@@ -47,56 +75,88 @@ func (e *Extractor) Extract(ctx context.Context, doc *domain.Document) (string,
 	}
 }
 
-func extractCSV(data []byte, filename string) (string, error) {
+func extractCSV(data []byte, filename string) ([]domain.Section, error) {
 	r := csv.NewReader(bytes.NewReader(data))
 	r.LazyQuotes = true
 	r.FieldsPerRecord = -1
 
 	records, err := r.ReadAll()
 	if err != nil {
-		return "", fmt.Errorf("parse csv: %w", err)
+		return nil, fmt.Errorf("parse csv: %w", err)
 	}
 
-	if len(records) == 0 {
-		return "", fmt.Errorf("no data in csv: %s", filename)
-	}
-
-	var lines []string
-	for _, row := range records {
-		lines = append(lines, strings.Join(row, "\t"))
+	sections := sheetSections("", records)
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no data in csv: %s", filename)
 	}
-
-	return strings.Join(lines, "\n"), nil
+	return sections, nil
 }
 
-func extractXLSX(data []byte, filename string) (string, error) {
+func extractXLSX(data []byte, filename string) ([]domain.Section, error) {
 	f, err := excelize.OpenReader(bytes.NewReader(data))
 	if err != nil {
-		return "", fmt.Errorf("open xlsx: %w", err)
+		return nil, fmt.Errorf("open xlsx: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
-	var sections []string
+	var sections []domain.Section
 	for _, sheet := range f.GetSheetList() {
 		rows, err := f.GetRows(sheet)
 		if err != nil {
 			continue
 		}
-		if len(rows) == 0 {
+		sections = append(sections, sheetSections(sheet, rows)...)
+	}
+
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no data in xlsx: %s", filename)
+	}
+	return sections, nil
+}
+
+// sheetSections renders rows (row i is spreadsheet row i+1) as Markdown
+// tables of at most rowsPerSection data rows. The first non-blank row is
+// the header; blank rows are dropped. A named sheet gets a heading.
+func sheetSections(sheet string, rows [][]string) []domain.Section {
+	var (
+		header []string
+		data   [][]string
+	)
+	for i, row := range rows {
+		if blankRow(row) {
 			continue
 		}
-
-		var lines []string
-		lines = append(lines, fmt.Sprintf("Sheet: %s", sheet))
-		for _, row := range rows {
-			lines = append(lines, strings.Join(row, "\t"))
+		if header == nil {
+			header = append([]string{"Row"}, row...)
+			continue
 		}
-		sections = append(sections, strings.Join(lines, "\n"))
+		data = append(data, append([]string{strconv.Itoa(i + 1)}, row...))
+	}
+	if header == nil {
+		return nil
 	}
 
-	if len(sections) == 0 {
-		return "", fmt.Errorf("no data in xlsx: %s", filename)
+	heading := ""
+	if sheet != "" {
+		heading = "## Sheet: " + sheet + "\n\n"
+	}
+	var sections []domain.Section
+	for start := 0; start == 0 || start < len(data); start += rowsPerSection {
+		end := min(start+rowsPerSection, len(data))
+		table := append([][]string{header}, data[start:end]...)
+		sections = append(sections, domain.Section{
+			Locator: domain.Locator{Sheet: sheet},
+			Text:    heading + mdtable.Render(table),
+		})
 	}
+	return sections
+}
 
-	return strings.Join(sections, "\n\n"), nil
+func blankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
 }
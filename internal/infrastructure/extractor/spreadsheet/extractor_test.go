@@ -24,6 +24,7 @@ func (f *storageFake) Open(_ context.Context, _ string) (io.ReadCloser, error) {
 	}
 	return io.NopCloser(strings.NewReader(string(f.data))), nil
 }
+func (f *storageFake) Delete(context.Context, string) error { return nil }
 
 func TestCSVExtract(t *testing.T) {
 	csv := "Name,Age,City\nAlice,30,Moscow\nBob,25,London\n"
@@ -205,3 +206,39 @@ func TestExtractSpreadsheet_StorageError(t *testing.T) {
 		t.Errorf("expected wrapped storage error, got: %v", err)
 	}
 }
+
+func TestExtractSections_SheetTablesWithRowNumbers(t *testing.T) {
+	rows := [][]string{{"Item", "Qty"}, {}}
+	for i := 0; i < rowsPerSection+1; i++ {
+		rows = append(rows, []string{"Widget", "5"})
+	}
+	data := makeXLSX(t, map[string][][]string{"Orders": rows})
+	ext := NewExtractor(&storageFake{data: data})
+
+	doc := &domain.Document{
+		StoragePath: "orders.xlsx",
+		Filename:    "orders.xlsx",
+		MimeType:    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+	sections, err := ext.ExtractSections(context.Background(), doc)
+	if err != nil {
+		t.Fatalf("ExtractSections() error = %v", err)
+	}
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(sections))
+	}
+	for _, s := range sections {
+		if s.Locator.Sheet != "Orders" {
+			t.Fatalf("unexpected locator: %+v", s.Locator)
+		}
+		if !strings.HasPrefix(s.Text, "## Sheet: Orders\n\n| Row | Item | Qty |\n| --- | --- | --- |\n") {
+			t.Fatalf("section must repeat the header, got %q", s.Text)
+		}
+	}
+	if !strings.Contains(sections[0].Text, "| 3 | Widget | 5 |") {
+		t.Errorf("expected spreadsheet row numbers skipping the blank row, got %q", sections[0].Text)
+	}
+	if !strings.HasSuffix(sections[1].Text, "| 53 | Widget | 5 |") {
+		t.Errorf("expected the last row in the second section, got %q", sections[1].Text)
+	}
+}
@@ -0,0 +1,287 @@
+package fswatch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// BatchHandler receives the relative paths changed during one debounce window.
+// Returning an error keeps the paths pending so they are retried with backoff;
+// ErrConflict means the target is busy and does not use up an attempt.
+type BatchHandler func(ctx context.Context, relPaths []string) error
+
+// Options configures a Watcher.
+type Options struct {
+	// Debounce is the quiet period after the last event before a batch is flushed.
+	Debounce time.Duration
+	// SkipDir reports whether a directory (by base name) should not be watched.
+	SkipDir func(name string) bool
+	// MaxAttempts is how often a path is handed to the handler before a
+	// path that keeps failing is dropped with a warning. Batches rejected
+	// with ErrConflict are retried without counting as an attempt.
+	MaxAttempts int
+	// MaxBackoff caps the retry delay of a failed path, which starts at
+	// Debounce and doubles with every failed attempt.
+	MaxBackoff time.Duration
+}
+
+// retryState tracks the failed attempts of a pending path.
+type retryState struct {
+	attempts int
+	next     time.Time
+}
+
+// Watcher recursively watches a directory tree and delivers debounced batches
+// of changed paths relative to the root. fsnotify is not recursive, so new
+// subdirectories are added as they appear.
+type Watcher struct {
+	root    string
+	handler BatchHandler
+	opts    Options
+
+	mu      sync.Mutex
+	pending map[string]struct{}
+	retries map[string]retryState
+}
+
+func New(root string, handler BatchHandler, opts Options) *Watcher {
+	if opts.Debounce <= 0 {
+		opts.Debounce = 1500 * time.Millisecond
+	}
+	if opts.SkipDir == nil {
+		opts.SkipDir = func(string) bool { return false }
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 5
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Minute
+	}
+	return &Watcher{
+		root:    filepath.Clean(root),
+		handler: handler,
+		opts:    opts,
+		pending: make(map[string]struct{}),
+		retries: make(map[string]retryState),
+	}
+}
+
+// Run blocks until ctx is cancelled or the underlying watcher fails.
+func (w *Watcher) Run(ctx context.Context) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create fsnotify watcher: %w", err)
+	}
+	defer func() { _ = fw.Close() }()
+
+	if err := w.addTree(fw, w.root); err != nil {
+		return fmt.Errorf("watch %s: %w", w.root, err)
+	}
+
+	timer := time.NewTimer(w.opts.Debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-fw.Events:
+			if !ok {
+				return nil
+			}
+			if w.handleEvent(fw, ev) {
+				timer.Reset(w.opts.Debounce)
+			}
+		case err, ok := <-fw.Errors:
+			if !ok {
+				return nil
+			}
+			slog.Warn("fswatch_error", "root", w.root, "error", err)
+		case <-timer.C:
+			if wait := w.flush(ctx); wait > 0 {
+				timer.Reset(wait)
+			}
+		}
+	}
+}
+
+// handleEvent records the event path and reports whether anything became pending.
+func (w *Watcher) handleEvent(fw *fsnotify.Watcher, ev fsnotify.Event) bool {
+	if ev.Op == fsnotify.Chmod {
+		return false
+	}
+	rel, err := filepath.Rel(w.root, ev.Name)
+	if err != nil || rel == "." || w.skipped(rel) {
+		return false
+	}
+
+	if ev.Has(fsnotify.Create) {
+		if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+			if err := w.addTree(fw, ev.Name); err != nil {
+				slog.Warn("fswatch_add_dir_failed", "path", ev.Name, "error", err)
+			}
+			// Files may have landed before the watch was registered.
+			w.markTree(ev.Name)
+			return true
+		}
+	}
+
+	w.mark(rel)
+	return true
+}
+
+func (w *Watcher) addTree(fw *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != w.root && w.opts.SkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+		return fw.Add(path)
+	})
+}
+
+func (w *Watcher) markTree(dir string) {
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if w.opts.SkipDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel, err := filepath.Rel(w.root, path); err == nil {
+			w.mark(rel)
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) skipped(rel string) bool {
+	for dir := filepath.Dir(rel); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if w.opts.SkipDir(filepath.Base(dir)) {
+			return true
+		}
+	}
+	return w.opts.SkipDir(filepath.Base(rel))
+}
+
+// mark makes rel pending. A new change gives a failing path a fresh start.
+func (w *Watcher) mark(rel string) {
+	w.mu.Lock()
+	w.pending[rel] = struct{}{}
+	delete(w.retries, rel)
+	w.mu.Unlock()
+}
+
+// flush hands the pending paths that are due to the handler. The paths of a
+// failed batch are retried with exponential backoff and dropped after
+// MaxAttempts; a busy target keeps them pending without limit. flush returns how long until pending paths are due again, or
+// zero when nothing is pending.
+func (w *Watcher) flush(ctx context.Context) time.Duration {
+	now := time.Now()
+	w.mu.Lock()
+	paths := make([]string, 0, len(w.pending))
+	for p := range w.pending {
+		if r, ok := w.retries[p]; ok && now.Before(r.next) {
+			continue
+		}
+		paths = append(paths, p)
+		delete(w.pending, p)
+	}
+	w.mu.Unlock()
+
+	if len(paths) > 0 {
+		sort.Strings(paths)
+		err := w.handler(ctx, paths)
+		w.mu.Lock()
+		switch {
+		case domain.IsKind(err, domain.ErrConflict):
+			slog.Info("fswatch_batch_busy", "root", w.root, "paths", len(paths), "error", err)
+			w.retry(paths, time.Now(), false)
+		case err != nil:
+			slog.Warn("fswatch_batch_failed", "root", w.root, "paths", len(paths), "error", err)
+			w.retry(paths, time.Now(), true)
+		default:
+			for _, p := range paths {
+				delete(w.retries, p)
+			}
+		}
+		w.mu.Unlock()
+	}
+	return w.nextDue()
+}
+
+// retry requeues the paths of a failed batch, or drops those out of
+// attempts. A batch that was not counted waits as long as its last attempt.
+// The caller holds w.mu.
+func (w *Watcher) retry(paths []string, now time.Time, counted bool) {
+	for _, p := range paths {
+		r := w.retries[p]
+		if counted {
+			r.attempts++
+		}
+		if r.attempts >= w.opts.MaxAttempts {
+			delete(w.retries, p)
+			slog.Warn("fswatch_path_dropped", "root", w.root, "path", p, "attempts", r.attempts)
+			continue
+		}
+		backoff := w.opts.Debounce
+		for i := 1; i < r.attempts && backoff < w.opts.MaxBackoff; i++ {
+			backoff *= 2
+		}
+		r.next = now.Add(min(backoff, w.opts.MaxBackoff))
+		w.retries[p] = r
+		w.pending[p] = struct{}{}
+	}
+}
+
+// nextDue returns the wait until the earliest pending path is due; paths
+// marked while the handler ran wait one debounce period.
+func (w *Watcher) nextDue() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		return 0
+	}
+	wait := w.opts.MaxBackoff
+	for p := range w.pending {
+		due := w.opts.Debounce
+		if r, ok := w.retries[p]; ok {
+			due = time.Until(r.next)
+		}
+		wait = min(wait, due)
+	}
+	return max(wait, time.Millisecond)
+}
+
+// DirectoryWatcher implements ports.DirectoryWatcher with one Watcher per call.
+type DirectoryWatcher struct {
+	opts Options
+}
+
+func NewDirectoryWatcher(opts Options) *DirectoryWatcher {
+	return &DirectoryWatcher{opts: opts}
+}
+
+func (d *DirectoryWatcher) Watch(ctx context.Context, root string, onChange func(ctx context.Context, relPaths []string) error) error {
+	return New(root, onChange, d.opts).Run(ctx)
+}
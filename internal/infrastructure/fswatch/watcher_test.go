@@ -0,0 +1,115 @@
+package fswatch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestWatcherDeliversDebouncedBatch(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	batches := make(chan []string, 4)
+	w := New(root, func(_ context.Context, paths []string) error {
+		batches <- paths
+		return nil
+	}, Options{
+		Debounce: 50 * time.Millisecond,
+		SkipDir:  func(name string) bool { return name == ".git" },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx) }()
+
+	// Give the watcher time to register before producing events.
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "note.md"), []byte("# hi"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	deadline := time.After(3 * time.Second)
+	for !seen[filepath.Join("sub", "note.md")] {
+		select {
+		case batch := <-batches:
+			for _, p := range batch {
+				seen[p] = true
+			}
+		case <-deadline:
+			t.Fatalf("timed out waiting for sub/note.md, saw %v", seen)
+		}
+	}
+	for p := range seen {
+		if filepath.Dir(p) == ".git" {
+			t.Fatalf("skipped dir leaked into batch: %v", seen)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+}
+
+func TestWatcherBacksOffAndDropsFailingPaths(t *testing.T) {
+	var calls []time.Time
+	w := New(t.TempDir(), func(context.Context, []string) error {
+		calls = append(calls, time.Now())
+		return errors.New("index down")
+	}, Options{Debounce: 10 * time.Millisecond, MaxAttempts: 3, MaxBackoff: time.Second})
+	w.mark("note.md")
+
+	for wait := w.flush(context.Background()); wait > 0; wait = w.flush(context.Background()) {
+		time.Sleep(wait)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 attempts before the path is dropped, got %d", len(calls))
+	}
+	if gap1, gap2 := calls[1].Sub(calls[0]), calls[2].Sub(calls[1]); gap1 < 10*time.Millisecond || gap2 < 20*time.Millisecond {
+		t.Fatalf("retries must back off exponentially, gaps %v and %v", gap1, gap2)
+	}
+	if len(w.pending) != 0 || len(w.retries) != 0 {
+		t.Fatalf("dropped path must be forgotten: pending=%v retries=%v", w.pending, w.retries)
+	}
+}
+
+func TestWatcherRetriesBusyBatchesWithoutUsingAttempts(t *testing.T) {
+	calls := 0
+	w := New(t.TempDir(), func(context.Context, []string) error {
+		calls++
+		switch {
+		case calls <= 4:
+			return domain.WrapError(domain.ErrConflict, "sync vault v-1", errors.New("vault is locked by another sync"))
+		case calls == 5:
+			return errors.New("index down")
+		}
+		return nil
+	}, Options{Debounce: time.Millisecond, MaxAttempts: 2, MaxBackoff: 10 * time.Millisecond})
+	w.mark("note.md")
+
+	for wait := w.flush(context.Background()); wait > 0; wait = w.flush(context.Background()) {
+		time.Sleep(wait)
+	}
+	if calls != 6 {
+		t.Fatalf("expected the batch to outlast the lock and one failure, got %d calls", calls)
+	}
+	if len(w.pending) != 0 || len(w.retries) != 0 {
+		t.Fatalf("delivered path must be forgotten: pending=%v retries=%v", w.pending, w.retries)
+	}
+}
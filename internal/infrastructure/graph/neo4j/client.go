@@ -135,6 +135,24 @@ DELETE r`
 	return nil
 }
 
+// DeleteDocument removes a Document node together with all its LINKS_TO and SIMILAR relationships.
+func (c *Client) DeleteDocument(ctx context.Context, docID string) error {
+	session := c.driver.NewSession(ctx, neo4jdriver.SessionConfig{})
+	defer func() { _ = session.Close(ctx) }()
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4jdriver.ManagedTransaction) (any, error) {
+		query := `
+MATCH (d:Document {id: $id})
+DETACH DELETE d`
+		_, err := tx.Run(ctx, query, map[string]any{"id": docID})
+		return nil, err
+	})
+	if err != nil {
+		return fmt.Errorf("neo4j: delete document %q: %w", docID, err)
+	}
+	return nil
+}
+
 // GetRelated returns documents reachable from docID within maxDepth hops.
 func (c *Client) GetRelated(ctx context.Context, docID string, maxDepth int, limit int) ([]domain.GraphRelation, error) {
 	if maxDepth < 1 {
@@ -11,10 +11,11 @@ type NoopStore struct{}
 
 func NewNoopStore() *NoopStore { return &NoopStore{} }
 
-func (n *NoopStore) UpsertDocument(context.Context, domain.GraphNode) error             { return nil }
-func (n *NoopStore) AddLink(context.Context, string, string, string) error              { return nil }
-func (n *NoopStore) AddSimilarity(context.Context, string, string, float64) error       { return nil }
-func (n *NoopStore) RemoveSimilarities(context.Context, string) error                   { return nil }
+func (n *NoopStore) UpsertDocument(context.Context, domain.GraphNode) error       { return nil }
+func (n *NoopStore) AddLink(context.Context, string, string, string) error        { return nil }
+func (n *NoopStore) AddSimilarity(context.Context, string, string, float64) error { return nil }
+func (n *NoopStore) RemoveSimilarities(context.Context, string) error             { return nil }
+func (n *NoopStore) DeleteDocument(context.Context, string) error                 { return nil }
 func (n *NoopStore) GetRelated(context.Context, string, int, int) ([]domain.GraphRelation, error) {
 	return nil, nil
 }
@@ -0,0 +1,6 @@
+// Package caption holds what the vision captioners of all LLM providers
+// share, so captions read the same whichever provider produced them.
+package caption
+
+// Prompt asks a vision model for an image description suited to indexing.
+const Prompt = "Describe this image for a search index in 2-4 sentences: what it shows, any visible text, and what a diagram or chart means. Answer in the language of the text in the image, otherwise in English."
@@ -29,6 +29,37 @@ func (g *Generator) GenerateAnswer(ctx context.Context, question string, chunks
 	return ans, err
 }
 
+func (g *Generator) GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
+	ans, err := g.primary.GenerateCitedAnswer(ctx, question, chunks)
+	if err != nil && openaicompat.IsRetryable(err) {
+		g.logger.Warn("primary LLM failed, falling back", "op", "GenerateCitedAnswer", "error", err)
+		return g.fallback.GenerateCitedAnswer(ctx, question, chunks)
+	}
+	return ans, err
+}
+
+// StreamAnswer falls back only while nothing has been streamed yet; once the
+// primary has emitted text, switching providers would garble the answer.
+func (g *Generator) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	started, tracked := trackDeltas(onDelta)
+	ans, err := g.primary.StreamAnswer(ctx, question, chunks, tracked)
+	if err != nil && !*started && openaicompat.IsRetryable(err) {
+		g.logger.Warn("primary LLM failed, falling back", "op", "StreamAnswer", "error", err)
+		return g.fallback.StreamAnswer(ctx, question, chunks, onDelta)
+	}
+	return ans, err
+}
+
+func (g *Generator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	started, tracked := trackDeltas(onDelta)
+	ans, err := g.primary.StreamCitedAnswer(ctx, question, chunks, tracked)
+	if err != nil && !*started && openaicompat.IsRetryable(err) {
+		g.logger.Warn("primary LLM failed, falling back", "op", "StreamCitedAnswer", "error", err)
+		return g.fallback.StreamCitedAnswer(ctx, question, chunks, onDelta)
+	}
+	return ans, err
+}
+
 func (g *Generator) GenerateFromPrompt(ctx context.Context, prompt string) (string, error) {
 	ans, err := g.primary.GenerateFromPrompt(ctx, prompt)
 	if err != nil && openaicompat.IsRetryable(err) {
@@ -48,14 +79,33 @@ func (g *Generator) GenerateJSONFromPrompt(ctx context.Context, prompt string) (
 }
 
 func (g *Generator) ChatWithTools(ctx context.Context, messages []domain.ChatMessage, tools []domain.ToolSchema) (*domain.ChatToolsResult, error) {
-	res, err := g.primary.ChatWithTools(ctx, messages, tools)
-	if err != nil && openaicompat.IsRetryable(err) {
+	primaryCtx := ctx
+	started := new(bool)
+	if onAnswer := domain.AnswerCallbackFromContext(ctx); onAnswer != nil {
+		var tracked domain.AnswerDeltaCallback
+		started, tracked = trackDeltas(onAnswer)
+		primaryCtx = domain.ContextWithAnswerCallback(ctx, tracked)
+	}
+	res, err := g.primary.ChatWithTools(primaryCtx, messages, tools)
+	if err != nil && !*started && openaicompat.IsRetryable(err) {
 		g.logger.Warn("primary LLM failed, falling back", "op", "ChatWithTools", "error", err)
 		return g.fallback.ChatWithTools(ctx, messages, tools)
 	}
 	return res, err
 }
 
+// trackDeltas wraps onDelta so the caller can tell whether any text was
+// forwarded. A nil onDelta is still tracked.
+func trackDeltas(onDelta domain.AnswerDeltaCallback) (*bool, domain.AnswerDeltaCallback) {
+	started := new(bool)
+	return started, func(text string) {
+		*started = true
+		if onDelta != nil {
+			onDelta(text)
+		}
+	}
+}
+
 // Classifier wraps ports.DocumentClassifier with automatic fallback on retryable errors.
 type Classifier struct {
 	primary  ports.DocumentClassifier
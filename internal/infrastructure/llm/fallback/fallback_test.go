@@ -15,11 +15,29 @@ import (
 type mockGenerator struct {
 	answer string
 	err    error
+	// partial is streamed before err is returned.
+	partial string
 }
 
 func (m *mockGenerator) GenerateAnswer(_ context.Context, _ string, _ []domain.RetrievedChunk) (string, error) {
 	return m.answer, m.err
 }
+func (m *mockGenerator) GenerateCitedAnswer(_ context.Context, _ string, _ []domain.RetrievedChunk) (string, error) {
+	return m.answer, m.err
+}
+func (m *mockGenerator) StreamAnswer(_ context.Context, _ string, _ []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	if m.partial != "" {
+		onDelta(m.partial)
+	}
+	if m.err != nil {
+		return m.partial, m.err
+	}
+	onDelta(m.answer)
+	return m.answer, nil
+}
+func (m *mockGenerator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return m.StreamAnswer(ctx, question, chunks, onDelta)
+}
 func (m *mockGenerator) GenerateFromPrompt(_ context.Context, _ string) (string, error) {
 	return m.answer, m.err
 }
@@ -105,6 +123,35 @@ func TestGenerator_FallbackOnServerError(t *testing.T) {
 	}
 }
 
+func TestGenerator_StreamFallsBackBeforeFirstDelta(t *testing.T) {
+	retryableErr := &openaicompat.ProviderError{StatusCode: 503, Body: "unavailable", Operation: "chat_stream"}
+	g := NewGenerator(&mockGenerator{err: retryableErr}, &mockGenerator{answer: "fallback answer"}, slog.Default())
+
+	var deltas []string
+	ans, err := g.StreamAnswer(context.Background(), "q", nil, func(text string) { deltas = append(deltas, text) })
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if ans != "fallback answer" || len(deltas) != 1 || deltas[0] != "fallback answer" {
+		t.Fatalf("expected fallback stream, got %q (deltas %q)", ans, deltas)
+	}
+}
+
+func TestGenerator_StreamNoFallbackAfterDelta(t *testing.T) {
+	retryableErr := &openaicompat.ProviderError{StatusCode: 503, Body: "unavailable", Operation: "chat_stream"}
+	fb := &mockGenerator{answer: "fallback answer"}
+	g := NewGenerator(&mockGenerator{err: retryableErr, partial: "half"}, fb, slog.Default())
+
+	var deltas []string
+	_, err := g.StreamAnswer(context.Background(), "q", nil, func(text string) { deltas = append(deltas, text) })
+	if !errors.Is(err, retryableErr) {
+		t.Fatalf("expected primary error once streaming started, got %v", err)
+	}
+	if len(deltas) != 1 || deltas[0] != "half" {
+		t.Fatalf("expected only the partial primary output, got %q", deltas)
+	}
+}
+
 func TestClassifier_FallbackOnRetryable(t *testing.T) {
 	retryableErr := &openaicompat.ProviderError{StatusCode: 500, Body: "internal", Operation: "chat"}
 	primary := &mockClassifier{err: retryableErr}
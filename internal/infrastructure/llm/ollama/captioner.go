@@ -0,0 +1,36 @@
+package ollama
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/caption"
+)
+
+// Captioner implements ports.ImageCaptioner with a multimodal Ollama model
+// such as llava or qwen2.5vl.
+type Captioner struct {
+	client *Client
+	model  string
+}
+
+// NewCaptioner uses model for captions, or the current generation model
+// when empty.
+func NewCaptioner(client *Client, model string) *Captioner {
+	return &Captioner{client: client, model: model}
+}
+
+func (c *Captioner) CaptionImage(ctx context.Context, image []byte, _ string) (string, error) {
+	model := c.model
+	if model == "" {
+		model, _, _, _ = c.client.runtimeSnapshot()
+	}
+	reqBody := map[string]any{
+		"model":  model,
+		"prompt": caption.Prompt,
+		"images": []string{base64.StdEncoding.EncodeToString(image)},
+		"stream": false,
+		"think":  false,
+	}
+	return c.client.generate(ctx, reqBody)
+}
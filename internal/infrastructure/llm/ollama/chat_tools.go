@@ -45,16 +45,17 @@ func (c *Client) chatWithTools(ctx context.Context, messages []domain.ChatMessag
 		})
 	}
 
-	// Check if we should stream thinking tokens
+	// Check if we should stream thinking and answer tokens
 	onThinking := domain.ThinkingCallbackFromContext(ctx)
+	onAnswer := domain.AnswerCallbackFromContext(ctx)
 	useStreaming := thinkEnabled && onThinking != nil
 
 	if useStreaming {
-		result, err := c.chatWithToolsStreaming(ctx, model, ollamaMessages, ollamaTools, onThinking)
+		result, err := c.chatWithToolsStreaming(ctx, model, ollamaMessages, ollamaTools, onThinking, onAnswer)
 		if err != nil {
 			// Fallback to content-streaming with <think> tag detection
 			slog.Warn("chat_think_api_fallback", "error", err)
-			result, err = c.chatWithToolsContentStreaming(ctx, model, ollamaMessages, ollamaTools, onThinking)
+			result, err = c.chatWithToolsContentStreaming(ctx, model, ollamaMessages, ollamaTools, onThinking, onAnswer)
 			if err != nil {
 				slog.Warn("chat_content_stream_fallback", "error", err)
 				return c.chatWithToolsSync(ctx, model, ollamaMessages, ollamaTools)
@@ -63,6 +64,13 @@ func (c *Client) chatWithTools(ctx context.Context, messages []domain.ChatMessag
 		}
 		return result, nil
 	}
+	if onAnswer != nil {
+		result, err := c.chatWithToolsContentStreaming(ctx, model, ollamaMessages, ollamaTools, nil, onAnswer)
+		if err == nil {
+			return result, nil
+		}
+		slog.Warn("chat_content_stream_fallback", "error", err)
+	}
 	return c.chatWithToolsSync(ctx, model, ollamaMessages, ollamaTools)
 }
 
@@ -130,7 +138,9 @@ func (c *Client) chatWithToolsSync(ctx context.Context, model string, messages,
 
 // chatWithToolsContentStreaming streams content tokens and detects <think> tags inline.
 // This works with models that don't support the native think API but output <think> tags in content.
-func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string, messages, tools []map[string]any, onThinking domain.ThinkingDeltaCallback) (*domain.ChatToolsResult, error) {
+// Either callback may be nil; text outside <think> tags goes to onAnswer until
+// the model starts calling tools.
+func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string, messages, tools []map[string]any, onThinking domain.ThinkingDeltaCallback, onAnswer domain.AnswerDeltaCallback) (*domain.ChatToolsResult, error) {
 	reqBody := map[string]any{
 		"model":    model,
 		"messages": messages,
@@ -144,6 +154,19 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 	var contentBuf strings.Builder
 	var toolCalls []domain.ToolCall
 
+	writeThinking := func(text string) {
+		thinkingBuf.WriteString(text)
+		if onThinking != nil {
+			onThinking(text)
+		}
+	}
+	writeContent := func(text string) {
+		contentBuf.WriteString(text)
+		if onAnswer != nil && len(toolCalls) == 0 {
+			onAnswer(text)
+		}
+	}
+
 	// State machine for detecting <think> tags in content stream.
 	const (
 		stateInit     = 0
@@ -185,7 +208,7 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 						break
 					}
 					// Not a prefix of <think> — flush as content
-					contentBuf.WriteString(accumulated)
+					writeContent(accumulated)
 					tagBuf.Reset()
 					state = stateContent
 					break
@@ -196,12 +219,11 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 					remainder := accumulated[7:]
 					tagBuf.Reset()
 					if remainder != "" {
-						thinkingBuf.WriteString(remainder)
-						onThinking(remainder)
+						writeThinking(remainder)
 					}
 				} else {
 					// No <think> tag — flush as content
-					contentBuf.WriteString(accumulated)
+					writeContent(accumulated)
 					tagBuf.Reset()
 					state = stateContent
 				}
@@ -212,20 +234,18 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 					before := text[:idx]
 					after := text[idx+8:]
 					if before != "" {
-						thinkingBuf.WriteString(before)
-						onThinking(before)
+						writeThinking(before)
 					}
 					state = stateContent
 					if after != "" {
-						contentBuf.WriteString(after)
+						writeContent(after)
 					}
 				} else {
-					thinkingBuf.WriteString(text)
-					onThinking(text)
+					writeThinking(text)
 				}
 
 			case stateContent:
-				contentBuf.WriteString(text)
+				writeContent(text)
 			}
 		}
 
@@ -246,7 +266,7 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 
 	// Flush any remaining tag buffer (e.g. stream ended without <think>)
 	if tagBuf.Len() > 0 {
-		contentBuf.WriteString(tagBuf.String())
+		writeContent(tagBuf.String())
 	}
 
 	content := strings.TrimSpace(contentBuf.String())
@@ -262,7 +282,9 @@ func (c *Client) chatWithToolsContentStreaming(ctx context.Context, model string
 }
 
 // chatWithToolsStreaming uses Ollama streaming API to send thinking tokens in real-time.
-func (c *Client) chatWithToolsStreaming(ctx context.Context, model string, messages, tools []map[string]any, onThinking domain.ThinkingDeltaCallback) (*domain.ChatToolsResult, error) {
+// Content tokens are forwarded to onAnswer when it is set, until the model
+// starts calling tools.
+func (c *Client) chatWithToolsStreaming(ctx context.Context, model string, messages, tools []map[string]any, onThinking domain.ThinkingDeltaCallback, onAnswer domain.AnswerDeltaCallback) (*domain.ChatToolsResult, error) {
 	reqBody := map[string]any{
 		"model":    model,
 		"messages": messages,
@@ -303,9 +325,12 @@ func (c *Client) chatWithToolsStreaming(ctx context.Context, model string, messa
 			onThinking(chunk.Message.Thinking)
 		}
 
-		// Accumulate content
+		// Accumulate content and stream it as answer tokens
 		if chunk.Message.Content != "" {
 			contentBuf.WriteString(chunk.Message.Content)
+			if onAnswer != nil && len(toolCalls) == 0 {
+				onAnswer(chunk.Message.Content)
+			}
 		}
 
 		// Collect tool calls from final chunk
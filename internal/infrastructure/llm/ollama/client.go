@@ -171,6 +171,18 @@ func (g *Generator) GenerateAnswer(ctx context.Context, question string, chunks
 	return g.client.generateText(ctx, buildAnswerPrompt(question, chunks))
 }
 
+func (g *Generator) GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
+	return g.client.generateText(ctx, buildCitedAnswerPrompt(question, chunks))
+}
+
+func (g *Generator) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return g.client.streamText(ctx, buildAnswerPrompt(question, chunks), onDelta)
+}
+
+func (g *Generator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return g.client.streamText(ctx, buildCitedAnswerPrompt(question, chunks), onDelta)
+}
+
 func (g *Generator) GenerateFromPrompt(ctx context.Context, prompt string) (string, error) {
 	return g.client.generateText(ctx, prompt)
 }
@@ -207,6 +219,44 @@ func (c *Client) generateText(ctx context.Context, prompt string) (string, error
 	return c.generate(ctx, reqBody)
 }
 
+// streamText runs /api/generate in streaming mode and forwards every
+// response fragment to onDelta as soon as it is decoded.
+func (c *Client) streamText(ctx context.Context, prompt string, onDelta domain.AnswerDeltaCallback) (string, error) {
+	genModel, _, _, _ := c.runtimeSnapshot()
+	reqBody := map[string]any{
+		"model":  genModel,
+		"prompt": prompt,
+		"stream": true,
+		"think":  false,
+	}
+
+	var buf strings.Builder
+	err := c.postStreamJSON(ctx, "/api/generate", reqBody, "generate-stream", func(raw json.RawMessage) error {
+		var chunk struct {
+			Response string `json:"response"`
+			Error    string `json:"error"`
+		}
+		if err := json.Unmarshal(raw, &chunk); err != nil {
+			return nil // skip malformed chunks
+		}
+		if chunk.Error != "" {
+			return fmt.Errorf("ollama generate stream: %s", chunk.Error)
+		}
+		if chunk.Response == "" {
+			return nil
+		}
+		buf.WriteString(chunk.Response)
+		if onDelta != nil {
+			onDelta(chunk.Response)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", wrapTemporaryIfNeeded("ollama generate-stream", err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
 func (c *Client) generate(ctx context.Context, reqBody map[string]any) (string, error) {
 	var response struct {
 		Response string `json:"response"`
@@ -227,7 +227,7 @@ func TestContentStreaming_DetectsThinkTags(t *testing.T) {
 	}
 
 	result, err := client.chatWithToolsContentStreaming(
-		context.Background(), "gen", nil, nil, onThinking,
+		context.Background(), "gen", nil, nil, onThinking, nil,
 	)
 	if err != nil {
 		t.Fatalf("chatWithToolsContentStreaming() error = %v", err)
@@ -274,7 +274,7 @@ func TestContentStreaming_NoThinkTags(t *testing.T) {
 	}
 
 	result, err := client.chatWithToolsContentStreaming(
-		context.Background(), "gen", nil, nil, onThinking,
+		context.Background(), "gen", nil, nil, onThinking, nil,
 	)
 	if err != nil {
 		t.Fatalf("error = %v", err)
@@ -303,7 +303,7 @@ func TestContentStreaming_ToolCalls(t *testing.T) {
 
 	client := NewWithOptions(server.URL, "gen", "embed", Options{ThinkEnabled: true})
 	result, err := client.chatWithToolsContentStreaming(
-		context.Background(), "gen", nil, nil, func(string) {},
+		context.Background(), "gen", nil, nil, func(string) {}, nil,
 	)
 	if err != nil {
 		t.Fatalf("error = %v", err)
@@ -324,7 +324,7 @@ func TestContentStreaming_ServerError(t *testing.T) {
 
 	client := NewWithOptions(server.URL, "gen", "embed", Options{ThinkEnabled: true})
 	_, err := client.chatWithToolsContentStreaming(
-		context.Background(), "gen", nil, nil, func(string) {},
+		context.Background(), "gen", nil, nil, func(string) {}, nil,
 	)
 	if err == nil {
 		t.Fatal("expected error on server 500")
@@ -356,7 +356,7 @@ func TestContentStreaming_ThinkTagSplitAcrossChunks(t *testing.T) {
 	result, err := client.chatWithToolsContentStreaming(
 		context.Background(), "gen", nil, nil, func(text string) {
 			thinkingTokens = append(thinkingTokens, text)
-		},
+		}, nil,
 	)
 	if err != nil {
 		t.Fatalf("error = %v", err)
@@ -371,6 +371,127 @@ func TestContentStreaming_ThinkTagSplitAcrossChunks(t *testing.T) {
 	}
 }
 
+func TestContentStreaming_ForwardsAnswerOutsideThinkTags(t *testing.T) {
+	chunks := []string{
+		`{"message":{"content":"<think>"},"done":false}`,
+		`{"message":{"content":"plan"},"done":false}`,
+		`{"message":{"content":"</think>Hello "},"done":false}`,
+		`{"message":{"content":"world"},"done":false}`,
+		`{"message":{"content":""},"done":true}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, c := range chunks {
+			_, _ = w.Write([]byte(c + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewWithOptions(server.URL, "gen", "embed", Options{})
+
+	var answerTokens []string
+	result, err := client.chatWithToolsContentStreaming(
+		context.Background(), "gen", nil, nil, nil, func(text string) {
+			answerTokens = append(answerTokens, text)
+		},
+	)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if got := strings.Join(answerTokens, ""); got != "Hello world" {
+		t.Fatalf("expected streamed answer %q, got %q (tokens %v)", "Hello world", got, answerTokens)
+	}
+	if !strings.Contains(result.Content, "<think>plan</think>") {
+		t.Fatalf("expected thinking to stay in content, got %q", result.Content)
+	}
+}
+
+func TestChatWithToolsStreamsAnswerWhenCallbackInContext(t *testing.T) {
+	var streamed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		streamed, _ = payload["stream"].(bool)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"message":{"content":"Hi "},"done":false}` + "\n"))
+		_, _ = w.Write([]byte(`{"message":{"content":"there"},"done":true}` + "\n"))
+	}))
+	defer server.Close()
+
+	gen := NewGenerator(NewWithOptions(server.URL, "gen", "embed", Options{}))
+	var answerTokens []string
+	ctx := domain.ContextWithAnswerCallback(context.Background(), func(text string) {
+		answerTokens = append(answerTokens, text)
+	})
+
+	result, err := gen.ChatWithTools(ctx, []domain.ChatMessage{{Role: "user", Content: "hi"}}, nil)
+	if err != nil {
+		t.Fatalf("ChatWithTools() error = %v", err)
+	}
+	if !streamed {
+		t.Fatal("expected streaming request")
+	}
+	if len(answerTokens) != 2 || result.Content != "Hi there" {
+		t.Fatalf("unexpected stream: tokens=%v content=%q", answerTokens, result.Content)
+	}
+}
+
+func TestStreamAnswerForwardsGenerateDeltas(t *testing.T) {
+	var capturedStream bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			http.NotFound(w, r)
+			return
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		capturedStream, _ = payload["stream"].(bool)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		for _, c := range []string{
+			`{"response":"First","done":false}`,
+			`{"response":" part.","done":false}`,
+			`{"response":"","done":true}`,
+		} {
+			_, _ = w.Write([]byte(c + "\n"))
+		}
+	}))
+	defer server.Close()
+
+	gen := NewGenerator(NewWithOptions(server.URL, "gen", "embed", Options{}))
+	var deltas []string
+	out, err := gen.StreamAnswer(context.Background(), "q", []domain.RetrievedChunk{{Text: "ctx"}}, func(text string) {
+		deltas = append(deltas, text)
+	})
+	if err != nil {
+		t.Fatalf("StreamAnswer() error = %v", err)
+	}
+	if !capturedStream {
+		t.Fatal("expected stream=true in request")
+	}
+	if out != "First part." {
+		t.Fatalf("unexpected answer %q", out)
+	}
+	if len(deltas) != 2 || deltas[0] != "First" || deltas[1] != " part." {
+		t.Fatalf("unexpected deltas %v", deltas)
+	}
+}
+
+func TestStreamAnswerReportsStreamError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"model not loaded"}` + "\n"))
+	}))
+	defer server.Close()
+
+	gen := NewGenerator(NewWithOptions(server.URL, "gen", "embed", Options{}))
+	if _, err := gen.StreamAnswer(context.Background(), "q", nil, nil); err == nil {
+		t.Fatal("expected error from stream error chunk")
+	}
+}
+
 func TestGenerateJSONFromPromptUsesPlannerModelAndJSONFormat(t *testing.T) {
 	var capturedModel string
 	var capturedFormat string
@@ -469,3 +590,29 @@ func TestSetRuntimeModelConfigAppliesUpdatedModels(t *testing.T) {
 		t.Fatalf("expected updated embedding model, got %v", embedModels)
 	}
 }
+
+func TestCaptionerSendsBase64Image(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/generate" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		_, _ = w.Write([]byte(`{"response":"A network diagram."}`))
+	}))
+	defer server.Close()
+
+	captioner := NewCaptioner(New(server.URL, "gen", "embed"), "llava")
+	caption, err := captioner.CaptionImage(context.Background(), []byte("png"), "image/png")
+	if err != nil {
+		t.Fatalf("CaptionImage() error = %v", err)
+	}
+	if caption != "A network diagram." {
+		t.Fatalf("unexpected caption %q", caption)
+	}
+	images, _ := payload["images"].([]any)
+	if payload["model"] != "llava" || len(images) != 1 || images[0] != "cG5n" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
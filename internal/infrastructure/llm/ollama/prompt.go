@@ -24,25 +24,52 @@ Document:
 }
 
 func buildAnswerPrompt(question string, chunks []domain.RetrievedChunk) string {
+	return fmt.Sprintf(`Answer user question only from context below.
+If context is insufficient, say it directly.
+
+Question:
+%s
+
+Context:
+%s
+`, question, buildContextBlock(chunks))
+}
+
+func buildCitedAnswerPrompt(question string, chunks []domain.RetrievedChunk) string {
+	return fmt.Sprintf(`Answer user question only from context below.
+If context is insufficient, say it directly.
+After every sentence that states a fact, add the number of the context block it comes from in square brackets, e.g. [1] or [2][3].
+Use only numbers of the blocks listed below. Do not add a list of sources at the end.
+
+Question:
+%s
+
+Context:
+%s
+`, question, buildContextBlock(chunks))
+}
+
+func buildContextBlock(chunks []domain.RetrievedChunk) string {
 	var contextBuilder strings.Builder
 	for idx, chunk := range chunks {
 		fmt.Fprintf(&contextBuilder,
-			"[%d] file=%s category=%s score=%.3f\n%s\n\n",
+			"[%d] file=%s%s category=%s score=%.3f\n%s\n\n",
 			idx+1,
 			chunk.Filename,
+			locationLabel(chunk.Locator),
 			chunk.Category,
 			chunk.Score,
 			chunk.Text,
 		)
 	}
+	return contextBuilder.String()
+}
 
-	return fmt.Sprintf(`Answer user question only from context below.
-If context is insufficient, say it directly.
-
-Question:
-%s
-
-Context:
-%s
-`, question, contextBuilder.String())
+// locationLabel renders a chunk's page, sheet or slide for the context
+// block header, so answers can cite it.
+func locationLabel(l domain.Locator) string {
+	if l.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf(" location=%q", l.String())
 }
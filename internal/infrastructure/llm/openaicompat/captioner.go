@@ -0,0 +1,60 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/llm/caption"
+)
+
+// Captioner implements ports.ImageCaptioner with a vision-capable chat model.
+type Captioner struct {
+	client *Client
+	model  string
+}
+
+// NewCaptioner uses model for captions, or the client's model when empty.
+func NewCaptioner(client *Client, model string) *Captioner {
+	if model == "" {
+		model = client.model
+	}
+	return &Captioner{client: client, model: model}
+}
+
+type visionContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *visionImageURL `json:"image_url,omitempty"`
+}
+
+type visionImageURL struct {
+	URL string `json:"url"`
+}
+
+func (c *Captioner) CaptionImage(ctx context.Context, image []byte, mimeType string) (string, error) {
+	if mimeType == "" {
+		mimeType = "image/png"
+	}
+	dataURL := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(image)
+	reqBody := map[string]any{
+		"model": c.model,
+		"messages": []map[string]any{{
+			"role": "user",
+			"content": []visionContentPart{
+				{Type: "text", Text: caption.Prompt},
+				{Type: "image_url", ImageURL: &visionImageURL{URL: dataURL}},
+			},
+		}},
+	}
+
+	var resp chatResponse
+	if err := c.client.postJSON(ctx, "/v1/chat/completions", reqBody, &resp, "caption"); err != nil {
+		return "", err
+	}
+	if len(resp.Choices) == 0 {
+		return "", fmt.Errorf("openaicompat caption: empty choices")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,40 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptioner_SendsImageAsDataURL(t *testing.T) {
+	var body struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Content []visionContentPart `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":" A login form. "}}]}`))
+	}))
+	defer server.Close()
+
+	captioner := NewCaptioner(New(server.URL, "", "text-model"), "vision-model")
+	caption, err := captioner.CaptionImage(context.Background(), []byte("png"), "image/png")
+	if err != nil {
+		t.Fatalf("CaptionImage error: %v", err)
+	}
+	if caption != "A login form." {
+		t.Fatalf("unexpected caption %q", caption)
+	}
+	if body.Model != "vision-model" || len(body.Messages) != 1 || len(body.Messages[0].Content) != 2 {
+		t.Fatalf("unexpected request: %+v", body)
+	}
+	image := body.Messages[0].Content[1]
+	if image.Type != "image_url" || !strings.HasPrefix(image.ImageURL.URL, "data:image/png;base64,cG5n") {
+		t.Fatalf("unexpected image part: %+v", image)
+	}
+}
@@ -47,6 +47,11 @@ func (c *Client) chatWithTools(ctx context.Context, messages []domain.ChatMessag
 		reqBody["tools"] = wireTools
 	}
 
+	if onAnswer := domain.AnswerCallbackFromContext(ctx); onAnswer != nil {
+		reqBody["stream"] = true
+		return c.chatWithToolsStream(ctx, reqBody, onAnswer)
+	}
+
 	var response struct {
 		Choices []struct {
 			Message struct {
@@ -84,3 +89,50 @@ func (c *Client) chatWithTools(ctx context.Context, messages []domain.ChatMessag
 	}
 	return result, nil
 }
+
+// chatWithToolsStream reads a streamed tool-calling completion. Content deltas
+// go to onAnswer as they arrive until the first tool call fragment, which
+// marks the text as a preamble; fragments are assembled per index.
+func (c *Client) chatWithToolsStream(ctx context.Context, reqBody map[string]any, onAnswer domain.AnswerDeltaCallback) (*domain.ChatToolsResult, error) {
+	var content strings.Builder
+	calls := make(map[int]*streamedToolCall)
+
+	err := c.postStream(ctx, "/v1/chat/completions", reqBody, "chat_tools_stream", func(chunk streamChunk) {
+		if len(chunk.Choices) == 0 {
+			return
+		}
+		delta := chunk.Choices[0].Delta
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			if len(calls) == 0 {
+				onAnswer(delta.Content)
+			}
+		}
+		for _, tc := range delta.ToolCalls {
+			call, ok := calls[tc.Index]
+			if !ok {
+				call = &streamedToolCall{}
+				calls[tc.Index] = call
+			}
+			if tc.ID != "" {
+				call.id = tc.ID
+			}
+			if tc.Function.Name != "" {
+				call.name = tc.Function.Name
+			}
+			call.arguments.WriteString(tc.Function.Arguments)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("openaicompat chat_tools: %w", err)
+	}
+
+	toolCalls, err := collectToolCalls(calls)
+	if err != nil {
+		return nil, fmt.Errorf("openaicompat chat_tools: %w", err)
+	}
+	return &domain.ChatToolsResult{
+		Content:   strings.TrimSpace(content.String()),
+		ToolCalls: toolCalls,
+	}, nil
+}
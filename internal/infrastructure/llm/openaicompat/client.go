@@ -88,14 +88,29 @@ func (c *Client) embedTexts(ctx context.Context, model string, texts []string) (
 }
 
 func (c *Client) postJSON(ctx context.Context, path string, payload any, out any, operation string) error {
+	resp, err := c.post(ctx, path, payload, operation)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", operation, err)
+	}
+	return nil
+}
+
+// post sends payload as JSON and returns the response when the status is
+// successful. The caller owns the response body.
+func (c *Client) post(ctx context.Context, path string, payload any, operation string) (*http.Response, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshal %s request: %w", operation, err)
+		return nil, fmt.Errorf("marshal %s request: %w", operation, err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create %s request: %w", operation, err)
+		return nil, fmt.Errorf("create %s request: %w", operation, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	if c.apiKey != "" {
@@ -107,22 +122,19 @@ func (c *Client) postJSON(ctx context.Context, path string, payload any, out any
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("openaicompat %s request: %w", operation, err)
+		return nil, fmt.Errorf("openaicompat %s request: %w", operation, err)
 	}
-	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
 		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-		return &ProviderError{
+		return nil, &ProviderError{
 			StatusCode: resp.StatusCode,
 			Body:       strings.TrimSpace(string(respBody)),
 			Operation:  operation,
 		}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-		return fmt.Errorf("decode %s response: %w", operation, err)
-	}
-	return nil
+	return resp, nil
 }
 
 // --- request / response types ---
@@ -139,7 +151,8 @@ type responseFormat struct {
 type chatRequest struct {
 	Model          string          `json:"model"`
 	Messages       []chatMessage   `json:"messages"`
-	ResponseFormat *responseFormat  `json:"response_format,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Stream         bool            `json:"stream,omitempty"`
 }
 
 type chatResponse struct {
@@ -20,11 +20,35 @@ func NewGenerator(client *Client) *Generator {
 func (g *Generator) GenerateAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
 	prompt := buildAnswerPrompt(question, chunks)
 	return g.client.chatCompletion(ctx, []chatMessage{
-		{Role: "system", Content: "Answer user question only from context below. If context is insufficient, say it directly."},
+		{Role: "system", Content: answerSystemPrompt},
 		{Role: "user", Content: prompt},
 	}, false)
 }
 
+func (g *Generator) GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
+	prompt := buildAnswerPrompt(question, chunks)
+	return g.client.chatCompletion(ctx, []chatMessage{
+		{Role: "system", Content: citedAnswerSystemPrompt},
+		{Role: "user", Content: prompt},
+	}, false)
+}
+
+func (g *Generator) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	prompt := buildAnswerPrompt(question, chunks)
+	return g.client.chatCompletionStream(ctx, []chatMessage{
+		{Role: "system", Content: answerSystemPrompt},
+		{Role: "user", Content: prompt},
+	}, onDelta)
+}
+
+func (g *Generator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	prompt := buildAnswerPrompt(question, chunks)
+	return g.client.chatCompletionStream(ctx, []chatMessage{
+		{Role: "system", Content: citedAnswerSystemPrompt},
+		{Role: "user", Content: prompt},
+	}, onDelta)
+}
+
 func (g *Generator) GenerateFromPrompt(ctx context.Context, prompt string) (string, error) {
 	return g.client.chatCompletion(ctx, []chatMessage{
 		{Role: "user", Content: prompt},
@@ -37,14 +61,24 @@ func (g *Generator) GenerateJSONFromPrompt(ctx context.Context, prompt string) (
 	}, true)
 }
 
+const answerSystemPrompt = "Answer user question only from context below. If context is insufficient, say it directly."
+
+const citedAnswerSystemPrompt = answerSystemPrompt + `
+After every sentence that states a fact, add the number of the context block it comes from in square brackets, e.g. [1] or [2][3].
+Use only numbers of the blocks listed in the context. Do not add a list of sources at the end.`
+
 func buildAnswerPrompt(question string, chunks []domain.RetrievedChunk) string {
 	var b strings.Builder
 	b.WriteString("Question:\n")
 	b.WriteString(question)
 	b.WriteString("\n\nContext:\n")
 	for idx, chunk := range chunks {
-		fmt.Fprintf(&b, "[%d] file=%s category=%s score=%.3f\n%s\n\n",
-			idx+1, chunk.Filename, chunk.Category, chunk.Score, chunk.Text)
+		location := ""
+		if !chunk.Locator.IsZero() {
+			location = fmt.Sprintf(" location=%q", chunk.Locator.String())
+		}
+		fmt.Fprintf(&b, "[%d] file=%s%s category=%s score=%.3f\n%s\n\n",
+			idx+1, chunk.Filename, location, chunk.Category, chunk.Score, chunk.Text)
 	}
 	return b.String()
 }
@@ -106,7 +106,7 @@ func TestGenerator_GenerateJSONFromPrompt(t *testing.T) {
 func TestBuildAnswerPrompt(t *testing.T) {
 	chunks := []domain.RetrievedChunk{
 		{Text: "chunk 1", Filename: "file1.txt", Category: "cat1", Score: 0.9},
-		{Text: "chunk 2", Filename: "file2.txt", Category: "cat2", Score: 0.8},
+		{Text: "chunk 2", Filename: "file2.pdf", Category: "cat2", Score: 0.8, Locator: domain.Locator{Page: 12}},
 	}
 	prompt := buildAnswerPrompt("test question", chunks)
 	if !strings.Contains(prompt, "test question") {
@@ -118,4 +118,42 @@ func TestBuildAnswerPrompt(t *testing.T) {
 	if !strings.Contains(prompt, "file1.txt") {
 		t.Fatal("prompt should contain filenames")
 	}
+	if !strings.Contains(prompt, `file=file2.pdf location="page 12"`) || strings.Contains(prompt, `file1.txt location`) {
+		t.Fatalf("prompt should locate chunks that have a page: %s", prompt)
+	}
+}
+
+func TestGenerator_GenerateCitedAnswerAsksForMarkers(t *testing.T) {
+	var system string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body chatRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Messages) > 0 {
+			system = body.Messages[0].Content
+		}
+		_ = json.NewEncoder(w).Encode(chatResponse{
+			Choices: []struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+			}{{Message: struct {
+				Content string `json:"content"`
+			}{Content: "AI is artificial intelligence [1]."}}},
+		})
+	}))
+	defer server.Close()
+
+	gen := NewGenerator(New(server.URL, "", "model"))
+	answer, err := gen.GenerateCitedAnswer(context.Background(), "What is AI?", []domain.RetrievedChunk{
+		{Text: "AI is artificial intelligence", Filename: "doc.txt", Score: 0.9},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCitedAnswer error: %v", err)
+	}
+	if !strings.Contains(answer, "[1]") {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+	if !strings.Contains(system, "square brackets") {
+		t.Fatalf("system prompt should ask for citation markers, got %q", system)
+	}
 }
@@ -0,0 +1,128 @@
+package openaicompat
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// maxStreamLineBytes bounds a single SSE line; tool call arguments can be large.
+const maxStreamLineBytes = 1 << 20
+
+// streamChunk is one "data:" event of a streamed chat completion.
+type streamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content   string `json:"content"`
+			ToolCalls []struct {
+				Index    int    `json:"index"`
+				ID       string `json:"id"`
+				Function struct {
+					Name      string `json:"name"`
+					Arguments string `json:"arguments"`
+				} `json:"function"`
+			} `json:"tool_calls"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+// chatCompletionStream sends a streaming request to /v1/chat/completions and
+// forwards content deltas to onDelta. It returns the full trimmed content.
+func (c *Client) chatCompletionStream(ctx context.Context, messages []chatMessage, onDelta domain.AnswerDeltaCallback) (string, error) {
+	reqBody := chatRequest{
+		Model:    c.model,
+		Messages: messages,
+		Stream:   true,
+	}
+
+	var content strings.Builder
+	err := c.postStream(ctx, "/v1/chat/completions", reqBody, "chat_stream", func(chunk streamChunk) {
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			content.WriteString(choice.Delta.Content)
+			if onDelta != nil {
+				onDelta(choice.Delta.Content)
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(content.String()), nil
+}
+
+// postStream posts payload and decodes the server-sent events of the response
+// until "[DONE]" or the end of the body.
+func (c *Client) postStream(ctx context.Context, path string, payload any, operation string, onChunk func(streamChunk)) error {
+	resp, err := c.post(ctx, path, payload, operation)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamLineBytes)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("data:")) {
+			continue
+		}
+		data := bytes.TrimSpace(line[len("data:"):])
+		if bytes.Equal(data, []byte("[DONE]")) {
+			return nil
+		}
+		var chunk streamChunk
+		if err := json.Unmarshal(data, &chunk); err != nil {
+			continue // skip malformed events
+		}
+		onChunk(chunk)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read %s stream: %w", operation, err)
+	}
+	return nil
+}
+
+// streamedToolCall accumulates the fragments of one tool call across events.
+type streamedToolCall struct {
+	id        string
+	name      string
+	arguments strings.Builder
+}
+
+// collectToolCalls turns accumulated fragments into domain tool calls ordered
+// by their stream index.
+func collectToolCalls(calls map[int]*streamedToolCall) ([]domain.ToolCall, error) {
+	indexes := make([]int, 0, len(calls))
+	for idx := range calls {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	out := make([]domain.ToolCall, 0, len(indexes))
+	for _, idx := range indexes {
+		call := calls[idx]
+		args := map[string]any{}
+		if raw := strings.TrimSpace(call.arguments.String()); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &args); err != nil {
+				return nil, fmt.Errorf("decode arguments of tool call %q: %w", call.name, err)
+			}
+		}
+		out = append(out, domain.ToolCall{
+			ID: call.id,
+			Function: domain.ToolCallFunc{
+				Name:      call.name,
+				Arguments: args,
+			},
+		})
+	}
+	return out, nil
+}
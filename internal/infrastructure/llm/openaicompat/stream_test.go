@@ -0,0 +1,136 @@
+package openaicompat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func newSSEServer(t *testing.T, events []string, capture *map[string]any) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if capture != nil {
+			_ = json.NewDecoder(r.Body).Decode(capture)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+		_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestGenerator_StreamAnswerForwardsDeltas(t *testing.T) {
+	var body map[string]any
+	server := newSSEServer(t, []string{
+		`{"choices":[{"delta":{"role":"assistant"}}]}`,
+		`{"choices":[{"delta":{"content":"AI is "}}]}`,
+		`{"choices":[{"delta":{"content":"artificial intelligence."}}]}`,
+		`{"choices":[{"delta":{},"finish_reason":"stop"}]}`,
+	}, &body)
+	defer server.Close()
+
+	gen := NewGenerator(New(server.URL, "", "model"))
+	var deltas []string
+	answer, err := gen.StreamAnswer(context.Background(), "What is AI?", []domain.RetrievedChunk{{Text: "AI"}}, func(text string) {
+		deltas = append(deltas, text)
+	})
+	if err != nil {
+		t.Fatalf("StreamAnswer error: %v", err)
+	}
+	if stream, _ := body["stream"].(bool); !stream {
+		t.Fatalf("expected stream=true in request, got %v", body["stream"])
+	}
+	if answer != "AI is artificial intelligence." {
+		t.Fatalf("unexpected answer %q", answer)
+	}
+	if len(deltas) != 2 || deltas[0] != "AI is " {
+		t.Fatalf("unexpected deltas %q", deltas)
+	}
+}
+
+func TestGenerator_StreamAnswerHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	gen := NewGenerator(New(server.URL, "", "model"))
+	_, err := gen.StreamAnswer(context.Background(), "q", nil, func(string) {})
+	if !IsRetryable(err) {
+		t.Fatalf("expected retryable provider error, got %v", err)
+	}
+}
+
+func TestChatWithTools_StreamsContentWhenCallbackInContext(t *testing.T) {
+	server := newSSEServer(t, []string{
+		`{"choices":[{"delta":{"content":"Hello"}}]}`,
+		`{"choices":[{"delta":{"content":" there"}}]}`,
+	}, nil)
+	defer server.Close()
+
+	var deltas []string
+	ctx := domain.ContextWithAnswerCallback(context.Background(), func(text string) {
+		deltas = append(deltas, text)
+	})
+	result, err := NewGenerator(New(server.URL, "", "model")).ChatWithTools(ctx, []domain.ChatMessage{{Role: "user", Content: "hi"}}, nil)
+	if err != nil {
+		t.Fatalf("ChatWithTools error: %v", err)
+	}
+	if result.Content != "Hello there" || len(deltas) != 2 {
+		t.Fatalf("unexpected result %q, deltas %q", result.Content, deltas)
+	}
+}
+
+func TestChatWithTools_StreamAssemblesToolCallFragments(t *testing.T) {
+	server := newSSEServer(t, []string{
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call-1","function":{"name":"web_search","arguments":""}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"query\":"}}]}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"golang\"}"}}]}}]}`,
+	}, nil)
+	defer server.Close()
+
+	ctx := domain.ContextWithAnswerCallback(context.Background(), func(string) {
+		t.Fatal("tool call fragments must not be streamed as answer text")
+	})
+	result, err := NewGenerator(New(server.URL, "", "model")).ChatWithTools(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("ChatWithTools error: %v", err)
+	}
+	if len(result.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(result.ToolCalls))
+	}
+	call := result.ToolCalls[0]
+	if call.ID != "call-1" || call.Function.Name != "web_search" || call.Function.Arguments["query"] != "golang" {
+		t.Fatalf("unexpected tool call %+v", call)
+	}
+}
+
+func TestChatWithTools_StopsStreamingContentAtToolCall(t *testing.T) {
+	server := newSSEServer(t, []string{
+		`{"choices":[{"delta":{"content":"Let me check."}}]}`,
+		`{"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call-1","function":{"name":"web_search","arguments":"{}"}}]}}]}`,
+		`{"choices":[{"delta":{"content":" Searching now."}}]}`,
+	}, nil)
+	defer server.Close()
+
+	var deltas []string
+	ctx := domain.ContextWithAnswerCallback(context.Background(), func(text string) {
+		deltas = append(deltas, text)
+	})
+	result, err := NewGenerator(New(server.URL, "", "model")).ChatWithTools(ctx, nil, nil)
+	if err != nil {
+		t.Fatalf("ChatWithTools error: %v", err)
+	}
+	if len(result.ToolCalls) != 1 {
+		t.Fatalf("expected 1 tool call, got %d", len(result.ToolCalls))
+	}
+	if len(deltas) != 1 || deltas[0] != "Let me check." {
+		t.Fatalf("expected only text before the tool call to stream, got %q", deltas)
+	}
+}
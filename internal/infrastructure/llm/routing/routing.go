@@ -38,6 +38,18 @@ func (g *Generator) GenerateAnswer(ctx context.Context, question string, chunks
 	return g.resolve(ctx).GenerateAnswer(ctx, question, chunks)
 }
 
+func (g *Generator) GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
+	return g.resolve(ctx).GenerateCitedAnswer(ctx, question, chunks)
+}
+
+func (g *Generator) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return g.resolve(ctx).StreamAnswer(ctx, question, chunks, onDelta)
+}
+
+func (g *Generator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return g.resolve(ctx).StreamCitedAnswer(ctx, question, chunks, onDelta)
+}
+
 func (g *Generator) GenerateFromPrompt(ctx context.Context, prompt string) (string, error) {
 	return g.resolve(ctx).GenerateFromPrompt(ctx, prompt)
 }
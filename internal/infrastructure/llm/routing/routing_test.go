@@ -24,6 +24,19 @@ func (s *stubGenerator) GenerateAnswer(_ context.Context, _ string, _ []domain.R
 	return "answer-from-" + s.name, nil
 }
 
+func (s *stubGenerator) GenerateCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk) (string, error) {
+	return s.GenerateAnswer(ctx, question, chunks)
+}
+func (s *stubGenerator) StreamAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	out, err := s.GenerateAnswer(ctx, question, chunks)
+	if err == nil {
+		onDelta(out)
+	}
+	return out, err
+}
+func (s *stubGenerator) StreamCitedAnswer(ctx context.Context, question string, chunks []domain.RetrievedChunk, onDelta domain.AnswerDeltaCallback) (string, error) {
+	return s.StreamAnswer(ctx, question, chunks, onDelta)
+}
 func (s *stubGenerator) GenerateFromPrompt(_ context.Context, _ string) (string, error) {
 	if s.err != nil {
 		return "", s.err
@@ -15,6 +15,7 @@ var builtinTools = map[string]bool{
 	"web_search":       true,
 	"obsidian_write":   true,
 	"task_tool":        true,
+	"memory":           true,
 }
 
 // ToolRegistry implements ports.MCPToolRegistry, combining built-in agent tools
@@ -142,6 +143,20 @@ func defaultBuiltinDefs() []ports.ToolDefinition {
 			},
 			Source: "builtin",
 		},
+		{
+			Name:        "memory",
+			Description: "Remember, update, forget or list facts about the user (e.g. \"my staging DB is db-stage-2\")",
+			InputSchema: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"action":  map[string]any{"type": "string", "enum": []string{"remember", "update", "forget", "list"}},
+					"content": map[string]any{"type": "string", "description": "the fact, for remember and update"},
+					"id":      map[string]any{"type": "string", "description": "fact id, for update and forget"},
+				},
+				"required": []string{"action"},
+			},
+			Source: "builtin",
+		},
 	}
 }
 
@@ -46,9 +46,10 @@ func NewMCPHandler(deps ServerDeps) http.Handler {
 func registerKnowledgeSearch(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("knowledge_search",
-			mcpgo.WithDescription("Search the knowledge base (Obsidian vaults and uploaded documents) for relevant information."),
+			mcpgo.WithDescription("Search the knowledge base (Obsidian vaults and uploaded documents the caller can read) for relevant information."),
 			mcpgo.WithString("question", mcpgo.Required(), mcpgo.Description("The search query")),
 			mcpgo.WithNumber("limit", mcpgo.Description("Maximum number of results (default 5)")),
+			mcpgo.WithBoolean("citations", mcpgo.Description("Mark claims with [n] source markers and return structured citations")),
 		),
 		func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
 			question := req.GetString("question", "")
@@ -60,15 +61,24 @@ func registerKnowledgeSearch(s *mcpserver.MCPServer, deps ServerDeps) {
 				limit = deps.KnowledgeTopK
 			}
 
-			answer, err := deps.QuerySvc.Answer(ctx, question, limit, domain.SearchFilter{})
+			answerFn := deps.QuerySvc.Answer
+			if req.GetBool("citations", false) {
+				answerFn = deps.QuerySvc.AnswerWithCitations
+			}
+			answer, err := answerFn(ctx, question, limit, domain.SearchFilter{})
 			if err != nil {
 				return mcpgo.NewToolResultErrorFromErr("knowledge search failed", err), nil
 			}
 
-			payload, _ := json.Marshal(map[string]any{
+			result := map[string]any{
 				"answer":  answer.Text,
 				"sources": answer.Sources,
-			})
+			}
+			if answer.Sentences != nil {
+				result["citations"] = answer.Citations
+				result["sentences"] = answer.Sentences
+			}
+			payload, _ := json.Marshal(result)
 			return mcpgo.NewToolResultText(string(payload)), nil
 		},
 	)
@@ -147,7 +157,7 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_create",
 			mcpgo.WithDescription("Create a new task for the user."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithString("title", mcpgo.Required(), mcpgo.Description("Task title")),
 			mcpgo.WithString("details", mcpgo.Description("Task details")),
 			mcpgo.WithString("due_at", mcpgo.Description("Due date in RFC3339 format")),
@@ -158,7 +168,7 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_list",
 			mcpgo.WithDescription("List all tasks for a user."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithBoolean("include_deleted", mcpgo.Description("Include soft-deleted tasks")),
 		),
 		makeTaskHandler(deps, "list"),
@@ -167,7 +177,7 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_get",
 			mcpgo.WithDescription("Get a specific task by ID."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithString("id", mcpgo.Required(), mcpgo.Description("Task ID")),
 		),
 		makeTaskHandler(deps, "get"),
@@ -176,7 +186,7 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_update",
 			mcpgo.WithDescription("Update an existing task."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithString("id", mcpgo.Required(), mcpgo.Description("Task ID")),
 			mcpgo.WithString("title", mcpgo.Description("New title")),
 			mcpgo.WithString("details", mcpgo.Description("New details")),
@@ -188,7 +198,7 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_delete",
 			mcpgo.WithDescription("Soft-delete a task."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithString("id", mcpgo.Required(), mcpgo.Description("Task ID")),
 		),
 		makeTaskHandler(deps, "delete"),
@@ -197,16 +207,29 @@ func registerTaskTool(s *mcpserver.MCPServer, deps ServerDeps) {
 	s.AddTool(
 		mcpgo.NewTool("task_complete",
 			mcpgo.WithDescription("Mark a task as completed."),
-			mcpgo.WithString("user_id", mcpgo.Required(), mcpgo.Description("User ID")),
+			mcpgo.WithString("user_id", mcpgo.Description(userIDParamDescription)),
 			mcpgo.WithString("id", mcpgo.Required(), mcpgo.Description("Task ID")),
 		),
 		makeTaskHandler(deps, "complete"),
 	)
 }
 
+// userIDParamDescription documents the user_id tool parameter, which only
+// matters when the server runs without API key authentication.
+const userIDParamDescription = "User ID (required when the server runs without authentication; ignored otherwise)"
+
+// callerUserID returns the authenticated principal's user id, falling back to
+// the user_id argument when the request is unauthenticated.
+func callerUserID(ctx context.Context, req mcpgo.CallToolRequest) string {
+	if p, ok := domain.PrincipalFromContext(ctx); ok {
+		return p.UserID
+	}
+	return strings.TrimSpace(req.GetString("user_id", ""))
+}
+
 func makeTaskHandler(deps ServerDeps, action string) mcpserver.ToolHandlerFunc {
 	return func(ctx context.Context, req mcpgo.CallToolRequest) (*mcpgo.CallToolResult, error) {
-		userID := req.GetString("user_id", "")
+		userID := callerUserID(ctx, req)
 		if userID == "" {
 			return mcpgo.NewToolResultError("user_id is required"), nil
 		}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 )
@@ -23,6 +25,15 @@ func (f *fakeQuerySvc) Answer(_ context.Context, _ string, _ int, _ domain.Searc
 	}
 	return &domain.Answer{Text: f.answerText}, nil
 }
+func (f *fakeQuerySvc) AnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+func (f *fakeQuerySvc) StreamAnswer(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
+func (f *fakeQuerySvc) StreamAnswerWithCitations(ctx context.Context, question string, limit int, filter domain.SearchFilter, _ domain.AnswerDeltaCallback) (*domain.Answer, error) {
+	return f.Answer(ctx, question, limit, filter)
+}
 func (f *fakeQuerySvc) GenerateFromPrompt(context.Context, string) (string, error) {
 	return "", nil
 }
@@ -153,6 +164,28 @@ func TestGenerateID(t *testing.T) {
 	}
 }
 
+func TestTaskHandlerUsesPrincipalOverUserIDArgument(t *testing.T) {
+	store := &fakeTaskStore{}
+	handler := makeTaskHandler(ServerDeps{Tasks: store}, "create")
+
+	req := mcpgo.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"user_id": "mallory", "title": "buy milk"}
+	ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{UserID: "alice"})
+
+	result, err := handler(ctx, req)
+	if err != nil || result.IsError {
+		t.Fatalf("handler error: %v, %+v", err, result)
+	}
+	for _, task := range store.tasks {
+		if task.UserID != "alice" {
+			t.Fatalf("expected task owned by alice, got %q", task.UserID)
+		}
+	}
+	if len(store.tasks) != 1 {
+		t.Fatalf("expected one task, got %d", len(store.tasks))
+	}
+}
+
 // Verify ServerDeps implements expected interfaces
 var _ ports.DocumentQueryService = (*fakeQuerySvc)(nil)
 var _ ports.WebSearcher = (*fakeWebSearcher)(nil)
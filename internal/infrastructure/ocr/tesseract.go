@@ -0,0 +1,98 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultTesseractBinary = "tesseract"
+	defaultPDFToPPMBinary  = "pdftoppm"
+	defaultLanguages       = "rus+eng"
+	defaultDPI             = 300
+)
+
+// TesseractOptions configures the local binaries. Empty fields use the
+// defaults: tesseract and pdftoppm from PATH, rus+eng, 300 DPI.
+type TesseractOptions struct {
+	Binary    string
+	PDFToPPM  string
+	Languages string
+	DPI       int
+}
+
+// Tesseract implements ports.OCREngine with a local tesseract binary. PDF
+// pages are rendered to PNG with poppler's pdftoppm first.
+type Tesseract struct {
+	binary    string
+	pdftoppm  string
+	languages string
+	dpi       int
+}
+
+func NewTesseract(opts TesseractOptions) *Tesseract {
+	t := &Tesseract{
+		binary:    opts.Binary,
+		pdftoppm:  opts.PDFToPPM,
+		languages: opts.Languages,
+		dpi:       opts.DPI,
+	}
+	if t.binary == "" {
+		t.binary = defaultTesseractBinary
+	}
+	if t.pdftoppm == "" {
+		t.pdftoppm = defaultPDFToPPMBinary
+	}
+	if t.languages == "" {
+		t.languages = defaultLanguages
+	}
+	if t.dpi <= 0 {
+		t.dpi = defaultDPI
+	}
+	return t
+}
+
+func (t *Tesseract) RecognizeImage(ctx context.Context, image []byte) (string, error) {
+	out, err := run(ctx, image, t.binary, "stdin", "stdout", "-l", t.languages)
+	if err != nil {
+		return "", fmt.Errorf("tesseract: %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (t *Tesseract) RecognizePDFPage(ctx context.Context, pdf []byte, page int) (string, error) {
+	p := strconv.Itoa(page)
+	png, err := run(ctx, pdf, t.pdftoppm, "-f", p, "-l", p, "-r", strconv.Itoa(t.dpi), "-png", "-singlefile", "-")
+	if err != nil {
+		return "", fmt.Errorf("render pdf page %d: %w", page, err)
+	}
+	if len(png) == 0 {
+		return "", fmt.Errorf("render pdf page %d: empty image", page)
+	}
+	return t.RecognizeImage(ctx, png)
+}
+
+// run executes name with stdin as input and returns its stdout. The error
+// carries the tail of stderr.
+func run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 512 {
+			msg = msg[len(msg)-512:]
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,61 @@
+package ocr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeBinary writes a shell script that prints its arguments and echoes
+// stdin, standing in for tesseract or pdftoppm.
+func fakeBinary(t *testing.T, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755); err != nil {
+		t.Fatalf("write fake binary: %v", err)
+	}
+	return path
+}
+
+func TestTesseract_RecognizeImage(t *testing.T) {
+	bin := fakeBinary(t, "tesseract", `echo "$@"; cat`)
+	engine := NewTesseract(TesseractOptions{Binary: bin, Languages: "eng"})
+
+	text, err := engine.RecognizeImage(context.Background(), []byte("PNGDATA"))
+	if err != nil {
+		t.Fatalf("RecognizeImage() error = %v", err)
+	}
+	if text != "stdin stdout -l eng\nPNGDATA" {
+		t.Fatalf("unexpected output: %q", text)
+	}
+}
+
+func TestTesseract_RecognizePDFPageRendersPage(t *testing.T) {
+	pdftoppm := fakeBinary(t, "pdftoppm", `echo "page $2"`)
+	tesseract := fakeBinary(t, "tesseract", `cat`)
+	engine := NewTesseract(TesseractOptions{Binary: tesseract, PDFToPPM: pdftoppm})
+
+	text, err := engine.RecognizePDFPage(context.Background(), []byte("%PDF"), 3)
+	if err != nil {
+		t.Fatalf("RecognizePDFPage() error = %v", err)
+	}
+	if text != "page 3" {
+		t.Fatalf("unexpected output: %q", text)
+	}
+}
+
+func TestTesseract_ErrorIncludesStderr(t *testing.T) {
+	bin := fakeBinary(t, "tesseract", `echo "Error opening data file rus.traineddata" >&2; exit 1`)
+	engine := NewTesseract(TesseractOptions{Binary: bin})
+
+	_, err := engine.RecognizeImage(context.Background(), []byte("x"))
+	if err == nil || !strings.Contains(err.Error(), "rus.traineddata") {
+		t.Fatalf("expected stderr in error, got %v", err)
+	}
+}
@@ -12,10 +12,11 @@ import (
 )
 
 type Queue struct {
-	conn          *nats.Conn
-	subject       string
-	enrichSubject string
-	executor      *resilience.Executor
+	conn            *nats.Conn
+	subject         string
+	enrichSubject   string
+	agentJobSubject string
+	executor        *resilience.Executor
 }
 
 func New(url, subject string) (*Queue, error) {
@@ -66,10 +67,11 @@ func NewWithOptions(url, subject string, options Options) (*Queue, error) {
 		return nil, fmt.Errorf("connect nats: %w", err)
 	}
 	return &Queue{
-		conn:          conn,
-		subject:       subject,
-		enrichSubject: subject + ".enrich",
-		executor:      options.ResilienceExecutor,
+		conn:            conn,
+		subject:         subject,
+		enrichSubject:   subject + ".enrich",
+		agentJobSubject: subject + ".agent_jobs",
+		executor:        options.ResilienceExecutor,
 	}, nil
 }
 
@@ -178,3 +180,53 @@ func (q *Queue) SubscribeDocumentIngested(ctx context.Context, handler func(cont
 	}
 	return nil
 }
+
+func (q *Queue) PublishAgentJob(ctx context.Context, jobID string) error {
+	call := func(_ context.Context) error {
+		if err := q.conn.Publish(q.agentJobSubject, []byte(jobID)); err != nil {
+			return fmt.Errorf("nats publish agent job: %w", err)
+		}
+		return nil
+	}
+
+	var err error
+	if q.executor != nil {
+		err = q.executor.Execute(ctx, "nats.publish_agent_job", call, classifyNATSError)
+	} else {
+		err = call(ctx)
+	}
+	if err != nil {
+		return wrapTemporaryIfNeeded(err)
+	}
+	return nil
+}
+
+func (q *Queue) SubscribeAgentJob(ctx context.Context, handler func(context.Context, string) error) error {
+	sub, err := q.conn.QueueSubscribe(q.agentJobSubject, "agent-workers", func(msg *nats.Msg) {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return
+		}
+
+		handlerCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		if err := handler(handlerCtx, string(msg.Data)); err != nil {
+			log.Printf("agent job handler error for job=%s: %v", string(msg.Data), err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("nats subscribe agent jobs: %w", err)
+	}
+
+	if err := q.conn.Flush(); err != nil {
+		return fmt.Errorf("nats flush agent jobs: %w", err)
+	}
+
+	<-ctx.Done()
+	if err := sub.Drain(); err != nil {
+		return fmt.Errorf("nats drain agent job subscription: %w", err)
+	}
+	if err := q.conn.FlushTimeout(5 * time.Second); err != nil {
+		return fmt.Errorf("nats flush after agent job drain: %w", err)
+	}
+	return nil
+}
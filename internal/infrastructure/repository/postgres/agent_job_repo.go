@@ -0,0 +1,349 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// AgentJobRepository implements ports.AgentJobStore.
+type AgentJobRepository struct {
+	db *sql.DB
+}
+
+func NewAgentJobRepository(db *sql.DB) *AgentJobRepository {
+	return &AgentJobRepository{db: db}
+}
+
+const agentJobColumns = `id, user_id, conversation_id, prompt, source, schedule_id, status, answer, error_message,
+	attempts, user_turn, cancel_requested, created_at, started_at, heartbeat_at, finished_at`
+
+func (r *AgentJobRepository) CreateAgentJob(ctx context.Context, job *domain.AgentJob) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now().UTC()
+	}
+	if job.Status == "" {
+		job.Status = domain.AgentJobStatusQueued
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO agent_jobs (id, user_id, conversation_id, prompt, source, schedule_id, status, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`, job.ID, job.UserID, job.ConversationID, job.Prompt, job.Source, job.ScheduleID, job.Status, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert agent job: %w", err)
+	}
+	return nil
+}
+
+// GetAgentJob returns the job together with its checkpoints.
+func (r *AgentJobRepository) GetAgentJob(ctx context.Context, id string) (*domain.AgentJob, error) {
+	job, err := scanAgentJobWithCheckpoints(r.db.QueryRowContext(ctx, `
+SELECT `+agentJobColumns+`, checkpoints
+FROM agent_jobs
+WHERE id = $1
+`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrAgentJobNotFound, "get agent job", fmt.Errorf("id=%s", id))
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *AgentJobRepository) ListAgentJobs(ctx context.Context, userID string, limit int) ([]domain.AgentJob, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+agentJobColumns+`
+FROM agent_jobs
+WHERE user_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := make([]domain.AgentJob, 0)
+	for rows.Next() {
+		job, err := scanAgentJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agent job rows: %w", err)
+	}
+	return jobs, nil
+}
+
+// ClaimAgentJob moves a queued job, or a running job whose heartbeat is
+// older than staleBefore, to running and counts the attempt. It reports
+// false when the job is finished or owned by a live worker.
+func (r *AgentJobRepository) ClaimAgentJob(ctx context.Context, id string, staleBefore time.Time) (*domain.AgentJob, bool, error) {
+	job, err := scanAgentJobWithCheckpoints(r.db.QueryRowContext(ctx, `
+UPDATE agent_jobs
+SET status = 'running', attempts = attempts + 1, started_at = COALESCE(started_at, $2), heartbeat_at = $2
+WHERE id = $1 AND (status = 'queued' OR (status = 'running' AND heartbeat_at < $3))
+RETURNING `+agentJobColumns+`, checkpoints
+`, id, time.Now().UTC(), staleBefore))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return job, true, nil
+}
+
+// HeartbeatAgentJob reports whether cancellation was requested.
+func (r *AgentJobRepository) HeartbeatAgentJob(ctx context.Context, id string, attempt int) (bool, error) {
+	var cancelRequested bool
+	err := r.db.QueryRowContext(ctx, `
+UPDATE agent_jobs SET heartbeat_at = $3
+WHERE id = $1 AND attempts = $2 AND status = 'running'
+RETURNING cancel_requested
+`, id, attempt, time.Now().UTC()).Scan(&cancelRequested)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, notOwned("heartbeat agent job", id, attempt)
+		}
+		return false, fmt.Errorf("heartbeat agent job: %w", err)
+	}
+	return cancelRequested, nil
+}
+
+func (r *AgentJobRepository) FinishAgentJob(ctx context.Context, job *domain.AgentJob) error {
+	return r.execOwned(ctx, "finish agent job", job.ID, job.Attempts, `
+UPDATE agent_jobs
+SET status = $3, answer = $4, error_message = $5, finished_at = $6
+WHERE id = $1 AND attempts = $2 AND status = 'running'
+`, job.Status, job.Answer, job.Error, nullTime(job.FinishedAt))
+}
+
+func (r *AgentJobRepository) SetAgentJobTurn(ctx context.Context, id string, attempt, turn int) error {
+	return r.execOwned(ctx, "set agent job turn", id, attempt, `
+UPDATE agent_jobs SET user_turn = $3
+WHERE id = $1 AND attempts = $2 AND status = 'running'
+`, turn)
+}
+
+// CancelAgentJob cancels a queued job at once and flags a running one for
+// its worker. Finished jobs are left unchanged.
+func (r *AgentJobRepository) CancelAgentJob(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE agent_jobs
+SET cancel_requested = TRUE,
+	status = CASE WHEN status = 'queued' THEN 'canceled' ELSE status END,
+	finished_at = CASE WHEN status = 'queued' THEN $2 ELSE finished_at END
+WHERE id = $1 AND status IN ('queued', 'running')
+`, id, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("cancel agent job: %w", err)
+	}
+	if _, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("rows affected for cancel agent job: %w", err)
+	}
+	return nil
+}
+
+func (r *AgentJobRepository) AddAgentJobCheckpoint(ctx context.Context, id string, attempt int, checkpoint domain.AgentCheckpoint) error {
+	raw, err := json.Marshal([]domain.AgentCheckpoint{checkpoint})
+	if err != nil {
+		return fmt.Errorf("marshal agent checkpoint: %w", err)
+	}
+	return r.execOwned(ctx, "add agent job checkpoint", id, attempt, `
+UPDATE agent_jobs SET checkpoints = checkpoints || $3::jsonb
+WHERE id = $1 AND attempts = $2 AND status = 'running'
+`, raw)
+}
+
+// execOwned runs an update of a running job whose first two parameters are
+// the job id and the attempt that claimed it.
+func (r *AgentJobRepository) execOwned(ctx context.Context, op, id string, attempt int, query string, args ...any) error {
+	result, err := r.db.ExecContext(ctx, query, append([]any{id, attempt}, args...)...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for %s: %w", op, err)
+	}
+	if rows == 0 {
+		return notOwned(op, id, attempt)
+	}
+	return nil
+}
+
+func notOwned(op, id string, attempt int) error {
+	return domain.WrapError(domain.ErrAgentJobNotOwned, op, fmt.Errorf("id=%s attempt=%d", id, attempt))
+}
+
+func (r *AgentJobRepository) AddAgentJobEvent(ctx context.Context, jobID string, event domain.AgentJobEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	if _, err := r.db.ExecContext(ctx, `
+INSERT INTO agent_job_events (job_id, type, tool, status, content, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, jobID, event.Type, event.Tool, event.Status, event.Content, event.CreatedAt); err != nil {
+		return fmt.Errorf("insert agent job event: %w", err)
+	}
+	return nil
+}
+
+// ListAgentJobEvents returns the events with a sequence number above
+// afterSeq, oldest first.
+func (r *AgentJobRepository) ListAgentJobEvents(ctx context.Context, jobID string, afterSeq int64, limit int) ([]domain.AgentJobEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT seq, type, tool, status, content, created_at
+FROM agent_job_events
+WHERE job_id = $1 AND seq > $2
+ORDER BY seq
+LIMIT $3
+`, jobID, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent job events: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	events := make([]domain.AgentJobEvent, 0)
+	for rows.Next() {
+		var e domain.AgentJobEvent
+		if err := rows.Scan(&e.Seq, &e.Type, &e.Tool, &e.Status, &e.Content, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan agent job event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agent job event rows: %w", err)
+	}
+	return events, nil
+}
+
+// HasUnfinishedScheduleJob reports whether a job of the scheduled task is
+// queued or running.
+func (r *AgentJobRepository) HasUnfinishedScheduleJob(ctx context.Context, scheduleID string) (bool, error) {
+	var busy bool
+	err := r.db.QueryRowContext(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM agent_jobs WHERE schedule_id = $1 AND status IN ('queued', 'running')
+)
+`, scheduleID).Scan(&busy)
+	if err != nil {
+		return false, fmt.Errorf("check unfinished schedule jobs: %w", err)
+	}
+	return busy, nil
+}
+
+func (r *AgentJobRepository) MarkAgentJobPublished(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE agent_jobs SET published_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("mark agent job published: %w", err)
+	}
+	return nil
+}
+
+// ListStaleAgentJobs returns queued jobs whose publish failed and running
+// jobs whose last heartbeat is older than staleBefore, unless they were
+// published again after that heartbeat. A queued job that was published is
+// waiting for a free worker and is left alone.
+func (r *AgentJobRepository) ListStaleAgentJobs(ctx context.Context, staleBefore time.Time, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id
+FROM agent_jobs
+WHERE (status = 'queued' AND published_at IS NULL)
+	OR (status = 'running' AND heartbeat_at < $1 AND (published_at IS NULL OR published_at < heartbeat_at))
+ORDER BY created_at
+LIMIT $2
+`, staleBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list stale agent jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan stale agent job: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate stale agent job rows: %w", err)
+	}
+	return ids, nil
+}
+
+// DeleteByConversation removes the user's jobs of a conversation; their
+// events go with them through the foreign key.
+func (r *AgentJobRepository) DeleteByConversation(ctx context.Context, userID, conversationID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM agent_jobs WHERE user_id = $1 AND conversation_id = $2`, userID, conversationID); err != nil {
+		return fmt.Errorf("delete agent jobs: %w", err)
+	}
+	return nil
+}
+
+func scanAgentJob(row rowScanner) (*domain.AgentJob, error) {
+	return scanAgentJobRow(row, false)
+}
+
+func scanAgentJobWithCheckpoints(row rowScanner) (*domain.AgentJob, error) {
+	return scanAgentJobRow(row, true)
+}
+
+func scanAgentJobRow(row rowScanner, withCheckpoints bool) (*domain.AgentJob, error) {
+	var (
+		job         domain.AgentJob
+		startedAt   sql.NullTime
+		heartbeatAt sql.NullTime
+		finishedAt  sql.NullTime
+		checkpoints []byte
+	)
+	dest := []any{
+		&job.ID, &job.UserID, &job.ConversationID, &job.Prompt, &job.Source, &job.ScheduleID,
+		&job.Status, &job.Answer, &job.Error, &job.Attempts, &job.Turn, &job.CancelRequested,
+		&job.CreatedAt, &startedAt, &heartbeatAt, &finishedAt,
+	}
+	if withCheckpoints {
+		dest = append(dest, &checkpoints)
+	}
+	if err := row.Scan(dest...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan agent job: %w", err)
+	}
+	if len(checkpoints) > 0 {
+		if err := json.Unmarshal(checkpoints, &job.Checkpoints); err != nil {
+			return nil, fmt.Errorf("decode agent job checkpoints: %w", err)
+		}
+	}
+	if startedAt.Valid {
+		t := startedAt.Time
+		job.StartedAt = &t
+	}
+	if heartbeatAt.Valid {
+		t := heartbeatAt.Time
+		job.HeartbeatAt = &t
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		job.FinishedAt = &t
+	}
+	return &job, nil
+}
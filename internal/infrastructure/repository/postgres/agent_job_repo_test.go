@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+var agentJobRowColumns = []string{
+	"id", "user_id", "conversation_id", "prompt", "source", "schedule_id", "status", "answer", "error_message",
+	"attempts", "user_turn", "cancel_requested", "created_at", "started_at", "heartbeat_at", "finished_at", "checkpoints",
+}
+
+func TestAgentJobRepositoryGetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewAgentJobRepository(db)
+	mock.ExpectQuery("FROM agent_jobs").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(agentJobRowColumns))
+
+	_, err = repo.GetAgentJob(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrAgentJobNotFound) {
+		t.Fatalf("expected ErrAgentJobNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAgentJobRepositoryClaimDecodesCheckpoints(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	staleBefore := created.Add(time.Hour)
+	repo := NewAgentJobRepository(db)
+	mock.ExpectQuery(`UPDATE agent_jobs\s+SET status = 'running', attempts = attempts \+ 1`).
+		WithArgs("job-1", sqlmock.AnyArg(), staleBefore).
+		WillReturnRows(sqlmock.NewRows(agentJobRowColumns).AddRow(
+			"job-1", "alice", "c-1", "research", "api", "", "running", "", "",
+			2, 4, false, created, created, staleBefore, nil,
+			[]byte(`[{"turn":1,"tool_calls":[{"id":"call-1","function":{"name":"knowledge_search","arguments":null}}],"events":[{"tool":"knowledge_search","status":"ok","output":"x"}]}]`),
+		))
+
+	job, claimed, err := repo.ClaimAgentJob(context.Background(), "job-1", staleBefore)
+	if err != nil || !claimed {
+		t.Fatalf("ClaimAgentJob() = %v, %v", claimed, err)
+	}
+	if job.Attempts != 2 || job.Turn != 4 || job.HeartbeatAt == nil || job.FinishedAt != nil {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if len(job.Checkpoints) != 1 || job.Checkpoints[0].ToolCalls[0].ID != "call-1" || job.Checkpoints[0].Events[0].Output != "x" {
+		t.Fatalf("unexpected checkpoints: %+v", job.Checkpoints)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAgentJobRepositoryClaimOwnedJob(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewAgentJobRepository(db)
+	mock.ExpectQuery("UPDATE agent_jobs").
+		WillReturnRows(sqlmock.NewRows(agentJobRowColumns))
+
+	job, claimed, err := repo.ClaimAgentJob(context.Background(), "job-1", time.Now())
+	if err != nil || claimed || job != nil {
+		t.Fatalf("expected job owned by another worker to be skipped, got %+v, %v, %v", job, claimed, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAgentJobRepositoryHeartbeatReportsCancel(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewAgentJobRepository(db)
+	mock.ExpectQuery(`UPDATE agent_jobs SET heartbeat_at = \$3\s+WHERE id = \$1 AND attempts = \$2 AND status = 'running'\s+RETURNING cancel_requested`).
+		WithArgs("job-1", 2, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"cancel_requested"}).AddRow(true))
+	mock.ExpectQuery("UPDATE agent_jobs SET heartbeat_at").
+		WithArgs("job-1", 1, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"cancel_requested"}))
+
+	requested, err := repo.HeartbeatAgentJob(context.Background(), "job-1", 2)
+	if err != nil || !requested {
+		t.Fatalf("HeartbeatAgentJob() = %v, %v; want cancel requested", requested, err)
+	}
+	if _, err := repo.HeartbeatAgentJob(context.Background(), "job-1", 1); !domain.IsKind(err, domain.ErrAgentJobNotOwned) {
+		t.Fatalf("stale attempt: err = %v, want ErrAgentJobNotOwned", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAgentJobRepositoryCheckpointFencedByAttempt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewAgentJobRepository(db)
+	mock.ExpectExec(`UPDATE agent_jobs SET checkpoints = checkpoints \|\| \$3::jsonb\s+WHERE id = \$1 AND attempts = \$2`).
+		WithArgs("job-1", 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.AddAgentJobCheckpoint(context.Background(), "job-1", 1, domain.AgentCheckpoint{Turn: 1})
+	if !domain.IsKind(err, domain.ErrAgentJobNotOwned) {
+		t.Fatalf("AddAgentJobCheckpoint() error = %v, want ErrAgentJobNotOwned", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
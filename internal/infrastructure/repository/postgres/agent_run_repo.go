@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// AgentRunRepository implements ports.AgentRunStore. Tools and steps of a
+// run are stored as JSONB next to it.
+type AgentRunRepository struct {
+	db *sql.DB
+}
+
+func NewAgentRunRepository(db *sql.DB) *AgentRunRepository {
+	return &AgentRunRepository{db: db}
+}
+
+const agentRunColumns = `id, user_id, conversation_id, input, intent, tier, model, system_prompt, answer,
+	fallback_reason, iterations, duration_ms, replay_of, orchestration_id, created_at`
+
+func (r *AgentRunRepository) SaveAgentRun(ctx context.Context, run *domain.AgentRun) error {
+	tools := run.Tools
+	if tools == nil {
+		tools = []domain.ToolSchema{}
+	}
+	toolsJSON, err := json.Marshal(tools)
+	if err != nil {
+		return fmt.Errorf("marshal agent run tools: %w", err)
+	}
+	steps := run.Steps
+	if steps == nil {
+		steps = []domain.AgentRunStep{}
+	}
+	stepsJSON, err := json.Marshal(steps)
+	if err != nil {
+		return fmt.Errorf("marshal agent run steps: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+INSERT INTO agent_runs (`+agentRunColumns+`, tools, steps)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+`,
+		run.ID, run.UserID, run.ConversationID, run.Input, run.Intent, run.Tier, run.Model, run.SystemPrompt, run.Answer,
+		run.FallbackReason, run.Iterations, run.DurationMS, run.ReplayOf, run.OrchestrationID, run.CreatedAt, toolsJSON, stepsJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("insert agent run: %w", err)
+	}
+	return nil
+}
+
+func (r *AgentRunRepository) GetAgentRun(ctx context.Context, id string) (*domain.AgentRun, error) {
+	var (
+		run       domain.AgentRun
+		toolsJSON []byte
+		stepsJSON []byte
+	)
+	err := r.db.QueryRowContext(ctx, `
+SELECT `+agentRunColumns+`, tools, steps
+FROM agent_runs
+WHERE id = $1
+`, id).Scan(append(agentRunDest(&run), &toolsJSON, &stepsJSON)...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrAgentRunNotFound, "get agent run", fmt.Errorf("id=%s", id))
+		}
+		return nil, fmt.Errorf("get agent run: %w", err)
+	}
+	if err := json.Unmarshal(toolsJSON, &run.Tools); err != nil {
+		return nil, fmt.Errorf("decode agent run tools: %w", err)
+	}
+	if err := json.Unmarshal(stepsJSON, &run.Steps); err != nil {
+		return nil, fmt.Errorf("decode agent run steps: %w", err)
+	}
+	return &run, nil
+}
+
+func (r *AgentRunRepository) ListAgentRuns(ctx context.Context, userID, conversationID string, limit int) ([]domain.AgentRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+agentRunColumns+`
+FROM agent_runs
+WHERE user_id = $1 AND ($2 = '' OR conversation_id = $2)
+ORDER BY created_at DESC
+LIMIT $3
+`, userID, conversationID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list agent runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]domain.AgentRun, 0)
+	for rows.Next() {
+		var run domain.AgentRun
+		if err := rows.Scan(agentRunDest(&run)...); err != nil {
+			return nil, fmt.Errorf("scan agent run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate agent run rows: %w", err)
+	}
+	return runs, nil
+}
+
+func (r *AgentRunRepository) DeleteAgentRuns(ctx context.Context, userID, conversationID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM agent_runs WHERE user_id = $1 AND conversation_id = $2`, userID, conversationID); err != nil {
+		return fmt.Errorf("delete agent runs: %w", err)
+	}
+	return nil
+}
+
+// agentRunDest lists the scan targets of agentRunColumns.
+func agentRunDest(run *domain.AgentRun) []any {
+	return []any{
+		&run.ID, &run.UserID, &run.ConversationID, &run.Input, &run.Intent, &run.Tier, &run.Model, &run.SystemPrompt, &run.Answer,
+		&run.FallbackReason, &run.Iterations, &run.DurationMS, &run.ReplayOf, &run.OrchestrationID, &run.CreatedAt,
+	}
+}
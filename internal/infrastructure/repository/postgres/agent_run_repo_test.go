@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+var agentRunRowColumns = []string{
+	"id", "user_id", "conversation_id", "input", "intent", "tier", "model", "system_prompt", "answer",
+	"fallback_reason", "iterations", "duration_ms", "replay_of", "orchestration_id", "created_at",
+}
+
+func TestAgentRunRepositoryGetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewAgentRunRepository(db)
+	mock.ExpectQuery("FROM agent_runs").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(append(agentRunRowColumns, "tools", "steps")))
+
+	_, err = repo.GetAgentRun(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrAgentRunNotFound) {
+		t.Fatalf("expected ErrAgentRunNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestAgentRunRepositoryGetDecodesSteps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	created := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := NewAgentRunRepository(db)
+	mock.ExpectQuery("FROM agent_runs").
+		WithArgs("r-1").
+		WillReturnRows(sqlmock.NewRows(append(agentRunRowColumns, "tools", "steps")).AddRow(
+			"r-1", "alice", "c-1", "weather?", "general", "", "ollama", "prompt", "sunny",
+			"", 2, 12.5, "", "", created,
+			[]byte(`[{"type":"function","function":{"name":"web_search"}}]`),
+			[]byte(`[{"seq":1,"kind":"llm_call","iteration":1},{"seq":2,"kind":"tool_call","iteration":1,"tool":"web_search","output":"sunny","cached":true}]`),
+		))
+
+	run, err := repo.GetAgentRun(context.Background(), "r-1")
+	if err != nil {
+		t.Fatalf("GetAgentRun() error = %v", err)
+	}
+	if len(run.Tools) != 1 || len(run.Steps) != 2 || !run.Steps[1].Cached || run.Steps[1].Tool != "web_search" {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -181,6 +181,197 @@ WHERE user_id = $1 AND conversation_id = $2
 	return nil
 }
 
+// conversationTitleFallback derives a title from the first user message when
+// the conversation was never renamed.
+const conversationTitleFallback = `COALESCE(NULLIF(c.title, ''), (
+	SELECT LEFT(m.content, 80)
+	FROM conversation_messages m
+	WHERE m.user_id = c.user_id AND m.conversation_id = c.conversation_id AND m.role = 'user'
+	ORDER BY m.created_at ASC
+	LIMIT 1
+), '')`
+
+// ListConversations and SearchMessages leave out the conversations that
+// orchestration steps run in (see domain.StepConversationPrefix); they are
+// reached through their parent conversation.
+func (r *ConversationRepository) ListConversations(ctx context.Context, userID string, limit, offset int) ([]domain.Conversation, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT c.user_id, c.conversation_id, `+conversationTitleFallback+`,
+	c.current_user_turn, c.last_summary_end_turn, c.created_at, c.updated_at
+FROM conversations c
+WHERE c.user_id = $1 AND position('_orch_' in c.conversation_id) = 0
+ORDER BY c.updated_at DESC
+LIMIT $2 OFFSET $3
+`, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list conversations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]domain.Conversation, 0)
+	for rows.Next() {
+		conv, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *conv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate conversations: %w", err)
+	}
+	return out, nil
+}
+
+func (r *ConversationRepository) GetConversation(ctx context.Context, userID, conversationID string) (*domain.Conversation, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT c.user_id, c.conversation_id, `+conversationTitleFallback+`,
+	c.current_user_turn, c.last_summary_end_turn, c.created_at, c.updated_at
+FROM conversations c
+WHERE c.user_id = $1 AND c.conversation_id = $2
+`, userID, conversationID)
+	conv, err := scanConversation(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrConversationNotFound, "get conversation", fmt.Errorf("id=%s", conversationID))
+		}
+		return nil, err
+	}
+	return conv, nil
+}
+
+func (r *ConversationRepository) ListMessages(ctx context.Context, userID, conversationID string) ([]domain.ConversationMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, user_id, conversation_id, role, content, COALESCE(tool_name, ''), user_turn, created_at
+FROM conversation_messages
+WHERE user_id = $1 AND conversation_id = $2
+ORDER BY created_at ASC
+`, userID, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("list messages: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanConversationMessages(rows)
+}
+
+func (r *ConversationRepository) SearchMessages(ctx context.Context, userID, query string, limit int) ([]domain.ConversationMessage, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, user_id, conversation_id, role, content, COALESCE(tool_name, ''), user_turn, created_at
+FROM conversation_messages
+WHERE user_id = $1 AND role IN ('user', 'assistant') AND strpos(lower(content), lower($2)) > 0
+	AND position('_orch_' in conversation_id) = 0
+ORDER BY created_at DESC
+LIMIT $3
+`, userID, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search messages: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	return scanConversationMessages(rows)
+}
+
+func (r *ConversationRepository) RenameConversation(ctx context.Context, userID, conversationID, title string) error {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE conversations
+SET title = $3, updated_at = $4
+WHERE user_id = $1 AND conversation_id = $2
+`, userID, conversationID, sanitizeUTF8pg(title), time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("rename conversation: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for rename conversation: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrConversationNotFound, "rename conversation", fmt.Errorf("id=%s", conversationID))
+	}
+	return nil
+}
+
+func (r *ConversationRepository) DeleteConversation(ctx context.Context, userID, conversationID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin delete conversation tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	msgResult, err := tx.ExecContext(ctx, `DELETE FROM conversation_messages WHERE user_id = $1 AND conversation_id = $2`, userID, conversationID)
+	if err != nil {
+		return fmt.Errorf("delete conversation messages: %w", err)
+	}
+	convResult, err := tx.ExecContext(ctx, `DELETE FROM conversations WHERE user_id = $1 AND conversation_id = $2`, userID, conversationID)
+	if err != nil {
+		return fmt.Errorf("delete conversation: %w", err)
+	}
+	msgRows, err := msgResult.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for delete conversation messages: %w", err)
+	}
+	convRows, err := convResult.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for delete conversation: %w", err)
+	}
+	if msgRows == 0 && convRows == 0 {
+		return domain.WrapError(domain.ErrConversationNotFound, "delete conversation", fmt.Errorf("id=%s", conversationID))
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete conversation tx: %w", err)
+	}
+	return nil
+}
+
+func scanConversation(row rowScanner) (*domain.Conversation, error) {
+	var conv domain.Conversation
+	if err := row.Scan(
+		&conv.UserID,
+		&conv.ConversationID,
+		&conv.Title,
+		&conv.CurrentUserTurn,
+		&conv.LastSummaryEndTurn,
+		&conv.CreatedAt,
+		&conv.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan conversation: %w", err)
+	}
+	return &conv, nil
+}
+
+func scanConversationMessages(rows *sql.Rows) ([]domain.ConversationMessage, error) {
+	out := make([]domain.ConversationMessage, 0)
+	for rows.Next() {
+		var msg domain.ConversationMessage
+		if err := rows.Scan(
+			&msg.ID,
+			&msg.UserID,
+			&msg.ConversationID,
+			&msg.Role,
+			&msg.Content,
+			&msg.ToolName,
+			&msg.UserTurn,
+			&msg.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan message: %w", err)
+		}
+		out = append(out, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate messages: %w", err)
+	}
+	return out, nil
+}
+
 // sanitizeUTF8pg strips invalid UTF-8 byte sequences so the string can be
 // safely inserted into PostgreSQL without "invalid byte sequence for encoding
 // UTF8" errors (e.g. truncated multi-byte Cyrillic characters from web search).
@@ -208,3 +399,29 @@ func nullableString(v string) any {
 	}
 	return v
 }
+
+func (r *ConversationRepository) ListConversationIDsByPrefix(ctx context.Context, userID, prefix string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT conversation_id
+FROM conversations
+WHERE user_id = $1 AND starts_with(conversation_id, $2)
+ORDER BY conversation_id
+`, userID, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list conversation ids: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan conversation id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate conversation id rows: %w", err)
+	}
+	return ids, nil
+}
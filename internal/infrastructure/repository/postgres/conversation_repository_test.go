@@ -194,3 +194,132 @@ func TestNullableString(t *testing.T) {
 		t.Fatal("expected 'hello'")
 	}
 }
+
+func TestListConversationsFallsBackToFirstUserMessageTitle(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`COALESCE\(NULLIF\(c.title, ''\)`).
+		WithArgs("u-1", 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "conversation_id", "title", "current_user_turn", "last_summary_end_turn", "created_at", "updated_at"}).
+			AddRow("u-1", "c-1", "What is on my calendar?", 3, 0, now, now))
+
+	convs, err := repo.ListConversations(context.Background(), "u-1", 0, -1)
+	if err != nil {
+		t.Fatalf("ListConversations error: %v", err)
+	}
+	if len(convs) != 1 || convs[0].Title != "What is on my calendar?" {
+		t.Fatalf("unexpected conversations: %+v", convs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestDeleteConversationRemovesMessagesInTx(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM conversation_messages").
+		WithArgs("u-1", "c-1").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec("DELETE FROM conversations").
+		WithArgs("u-1", "c-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.DeleteConversation(context.Background(), "u-1", "c-1"); err != nil {
+		t.Fatalf("DeleteConversation error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestDeleteConversationReturnsNotFound(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM conversation_messages").
+		WithArgs("u-2", "c-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM conversations").
+		WithArgs("u-2", "c-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.DeleteConversation(context.Background(), "u-2", "c-1")
+	if !domain.IsKind(err, domain.ErrConversationNotFound) {
+		t.Fatalf("expected ErrConversationNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSearchMessagesIsCaseInsensitive(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`strpos\(lower\(content\), lower\(\$2\)\) > 0`).
+		WithArgs("u-1", "Invoice", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "conversation_id", "role", "content", "tool_name", "user_turn", "created_at"}).
+			AddRow("m-1", "u-1", "c-1", "user", "where is the invoice?", "", 1, time.Now().UTC()))
+
+	msgs, err := repo.SearchMessages(context.Background(), "u-1", "Invoice", 0)
+	if err != nil {
+		t.Fatalf("SearchMessages error: %v", err)
+	}
+	if len(msgs) != 1 || msgs[0].ConversationID != "c-1" {
+		t.Fatalf("unexpected messages: %+v", msgs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListAndSearchSkipStepConversations(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE c.user_id = \$1 AND position\('_orch_' in c.conversation_id\) = 0`).
+		WithArgs("u-1", 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "conversation_id", "title", "current_user_turn", "last_summary_end_turn", "created_at", "updated_at"}))
+	mock.ExpectQuery(`AND position\('_orch_' in conversation_id\) = 0`).
+		WithArgs("u-1", "invoice", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "conversation_id", "role", "content", "tool_name", "user_turn", "created_at"}))
+
+	if _, err := repo.ListConversations(context.Background(), "u-1", 0, 0); err != nil {
+		t.Fatalf("ListConversations error: %v", err)
+	}
+	if _, err := repo.SearchMessages(context.Background(), "u-1", "invoice", 0); err != nil {
+		t.Fatalf("SearchMessages error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListConversationIDsByPrefixMatchesLiterally(t *testing.T) {
+	repo, mock, done := newConvRepoWithMock(t)
+	defer done()
+
+	// starts_with keeps the underscores of the prefix from acting as LIKE wildcards.
+	mock.ExpectQuery(`WHERE user_id = \$1 AND starts_with\(conversation_id, \$2\)`).
+		WithArgs("u-1", "c-1_orch_").
+		WillReturnRows(sqlmock.NewRows([]string{"conversation_id"}).AddRow("c-1_orch_o-1_research"))
+
+	ids, err := repo.ListConversationIDsByPrefix(context.Background(), "u-1", "c-1_orch_")
+	if err != nil {
+		t.Fatalf("ListConversationIDsByPrefix error: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "c-1_orch_o-1_research" {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -124,10 +125,24 @@ CREATE TABLE IF NOT EXISTS memory_summaries (
 CREATE INDEX IF NOT EXISTS idx_memory_summaries_user_conv_created
 	ON memory_summaries(user_id, conversation_id, created_at DESC);
 
+CREATE TABLE IF NOT EXISTS memory_facts (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	content TEXT NOT NULL,
+	source TEXT NOT NULL DEFAULT 'user',
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_memory_facts_user_updated
+	ON memory_facts(user_id, updated_at DESC);
+
 ALTER TABLE documents ADD COLUMN IF NOT EXISTS source_type TEXT NOT NULL DEFAULT '';
 ALTER TABLE documents ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
 ALTER TABLE documents ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '[]'::jsonb;
 ALTER TABLE documents ADD COLUMN IF NOT EXISTS path TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS source_id TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_documents_source_id ON documents(source_id) WHERE source_id <> '';
 
 CREATE TABLE IF NOT EXISTS orchestrations (
     id TEXT PRIMARY KEY,
@@ -175,6 +190,47 @@ CREATE TABLE IF NOT EXISTS agent_improvements (
 );
 CREATE INDEX IF NOT EXISTS idx_agent_improvements_status ON agent_improvements(status, created_at DESC);
 
+CREATE TABLE IF NOT EXISTS lexical_collection_stats (
+	collection TEXT PRIMARY KEY,
+	docs BIGINT NOT NULL DEFAULT 0,
+	total_length BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS lexical_term_stats (
+	collection TEXT NOT NULL,
+	term TEXT NOT NULL,
+	df BIGINT NOT NULL DEFAULT 0,
+	PRIMARY KEY (collection, term)
+);
+
+CREATE TABLE IF NOT EXISTS eval_cases (
+	id TEXT PRIMARY KEY,
+	question TEXT NOT NULL,
+	expected_document_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+	expected_filenames JSONB NOT NULL DEFAULT '[]'::jsonb,
+	ground_truth TEXT NOT NULL DEFAULT '',
+	source TEXT NOT NULL DEFAULT 'api',
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS eval_runs (
+	id TEXT PRIMARY KEY,
+	status TEXT NOT NULL,
+	config JSONB NOT NULL,
+	k INTEGER NOT NULL,
+	generate_answers BOOLEAN NOT NULL DEFAULT false,
+	case_count INTEGER NOT NULL DEFAULT 0,
+	failed INTEGER NOT NULL DEFAULT 0,
+	metrics JSONB NOT NULL DEFAULT '{}'::jsonb,
+	results JSONB NOT NULL DEFAULT '[]'::jsonb,
+	gate JSONB,
+	error TEXT NOT NULL DEFAULT '',
+	started_at TIMESTAMPTZ NOT NULL,
+	finished_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_eval_runs_started ON eval_runs(started_at DESC);
+
 CREATE TABLE IF NOT EXISTS scheduled_tasks (
 	id TEXT PRIMARY KEY,
 	user_id TEXT NOT NULL,
@@ -191,6 +247,171 @@ CREATE TABLE IF NOT EXISTS scheduled_tasks (
 );
 CREATE INDEX IF NOT EXISTS idx_scheduled_tasks_user_enabled
 	ON scheduled_tasks(user_id, enabled, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS obsidian_vaults (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	path TEXT NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT true,
+	interval_minutes INTEGER,
+	created_at TIMESTAMPTZ NOT NULL,
+	updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS obsidian_file_states (
+	vault_id TEXT NOT NULL REFERENCES obsidian_vaults(id) ON DELETE CASCADE,
+	rel_path TEXT NOT NULL,
+	hash TEXT NOT NULL,
+	document_id TEXT NOT NULL DEFAULT '',
+	updated_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (vault_id, rel_path)
+);
+
+CREATE TABLE IF NOT EXISTS obsidian_sync_runs (
+	id TEXT PRIMARY KEY,
+	vault_id TEXT NOT NULL REFERENCES obsidian_vaults(id) ON DELETE CASCADE,
+	triggered_by TEXT NOT NULL,
+	status TEXT NOT NULL,
+	uploaded INTEGER NOT NULL DEFAULT 0,
+	skipped INTEGER NOT NULL DEFAULT 0,
+	removed INTEGER NOT NULL DEFAULT 0,
+	failed INTEGER NOT NULL DEFAULT 0,
+	errors JSONB NOT NULL DEFAULT '[]'::jsonb,
+	started_at TIMESTAMPTZ NOT NULL,
+	finished_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_obsidian_sync_runs_vault_started
+	ON obsidian_sync_runs(vault_id, started_at DESC);
+
+CREATE TABLE IF NOT EXISTS users (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	is_admin BOOLEAN NOT NULL DEFAULT false,
+	created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	prefix TEXT NOT NULL,
+	key_hash TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL,
+	last_used_at TIMESTAMPTZ,
+	revoked_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_created ON api_keys(user_id, created_at DESC);
+
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS owner_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_documents_owner_created ON documents(owner_id, created_at DESC);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS groups JSONB NOT NULL DEFAULT '[]'::jsonb;
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT '';
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS shared_with JSONB NOT NULL DEFAULT '[]'::jsonb;
+UPDATE documents
+SET visibility = CASE WHEN owner_id = '' THEN 'public' ELSE 'private' END
+WHERE visibility = '';
+CREATE INDEX IF NOT EXISTS idx_documents_shared_with ON documents USING GIN (shared_with);
+
+ALTER TABLE conversations ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_conversations_user_updated ON conversations(user_id, updated_at DESC);
+
+ALTER TABLE eval_runs ADD COLUMN IF NOT EXISTS chunking JSONB;
+
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS content_hash TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_documents_content_hash ON documents(content_hash, owner_id)
+	WHERE content_hash <> '';
+
+ALTER TABLE documents ADD COLUMN IF NOT EXISTS parent_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_documents_parent ON documents(parent_id) WHERE parent_id <> '';
+
+CREATE TABLE IF NOT EXISTS ingest_jobs (
+	id TEXT PRIMARY KEY,
+	kind TEXT NOT NULL,
+	source TEXT NOT NULL,
+	owner_id TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	total INTEGER NOT NULL DEFAULT 0,
+	ingested INTEGER NOT NULL DEFAULT 0,
+	skipped INTEGER NOT NULL DEFAULT 0,
+	failed INTEGER NOT NULL DEFAULT 0,
+	error_message TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL,
+	started_at TIMESTAMPTZ,
+	finished_at TIMESTAMPTZ,
+	heartbeat_at TIMESTAMPTZ
+);
+ALTER TABLE ingest_jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_ingest_jobs_owner_created ON ingest_jobs(owner_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS ingest_job_files (
+	id BIGSERIAL PRIMARY KEY,
+	job_id TEXT NOT NULL REFERENCES ingest_jobs(id) ON DELETE CASCADE,
+	path TEXT NOT NULL,
+	status TEXT NOT NULL,
+	document_id TEXT NOT NULL DEFAULT '',
+	hash TEXT NOT NULL DEFAULT '',
+	size BIGINT NOT NULL DEFAULT 0,
+	error_message TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS idx_ingest_job_files_job ON ingest_job_files(job_id, id);
+
+CREATE TABLE IF NOT EXISTS agent_jobs (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	prompt TEXT NOT NULL,
+	source TEXT NOT NULL,
+	schedule_id TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	answer TEXT NOT NULL DEFAULT '',
+	error_message TEXT NOT NULL DEFAULT '',
+	attempts INT NOT NULL DEFAULT 0,
+	user_turn INT NOT NULL DEFAULT 0,
+	cancel_requested BOOLEAN NOT NULL DEFAULT FALSE,
+	checkpoints JSONB NOT NULL DEFAULT '[]'::jsonb,
+	created_at TIMESTAMPTZ NOT NULL,
+	started_at TIMESTAMPTZ,
+	heartbeat_at TIMESTAMPTZ,
+	finished_at TIMESTAMPTZ,
+	published_at TIMESTAMPTZ
+);
+ALTER TABLE agent_jobs ADD COLUMN IF NOT EXISTS published_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_agent_jobs_user ON agent_jobs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_jobs_active ON agent_jobs(status) WHERE status IN ('queued', 'running');
+
+CREATE TABLE IF NOT EXISTS agent_job_events (
+	seq BIGSERIAL PRIMARY KEY,
+	job_id TEXT NOT NULL REFERENCES agent_jobs(id) ON DELETE CASCADE,
+	type TEXT NOT NULL,
+	tool TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	content TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_agent_job_events_job ON agent_job_events(job_id, seq);
+
+CREATE TABLE IF NOT EXISTS agent_runs (
+	id TEXT PRIMARY KEY,
+	user_id TEXT NOT NULL,
+	conversation_id TEXT NOT NULL,
+	input TEXT NOT NULL,
+	intent TEXT NOT NULL DEFAULT '',
+	tier TEXT NOT NULL DEFAULT '',
+	model TEXT NOT NULL DEFAULT '',
+	system_prompt TEXT NOT NULL DEFAULT '',
+	answer TEXT NOT NULL DEFAULT '',
+	fallback_reason TEXT NOT NULL DEFAULT '',
+	iterations INT NOT NULL DEFAULT 0,
+	duration_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+	replay_of TEXT NOT NULL DEFAULT '',
+	orchestration_id TEXT NOT NULL DEFAULT '',
+	tools JSONB NOT NULL DEFAULT '[]'::jsonb,
+	steps JSONB NOT NULL DEFAULT '[]'::jsonb,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_agent_runs_user ON agent_runs(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_agent_runs_conversation ON agent_runs(conversation_id, created_at DESC);
 `
 	if _, err := tx.ExecContext(ctx, query); err != nil {
 		return fmt.Errorf("execute schema ddl: %w", err)
@@ -216,17 +437,22 @@ func (r *DocumentRepository) Create(ctx context.Context, doc *domain.Document) e
 	if err != nil {
 		return fmt.Errorf("marshal headers: %w", err)
 	}
+	sharedJSON, err := marshalSharedWith(doc.SharedWith)
+	if err != nil {
+		return err
+	}
 
 	_, err = r.db.ExecContext(ctx, `
 INSERT INTO documents (
 	id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
-	source_type, title, headers, path,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
 	status, error_message, created_at, updated_at
-) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17)
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23)
 `,
 		doc.ID, doc.Filename, doc.MimeType, doc.StoragePath, doc.Category, doc.Subcategory, tagsJSON,
 		doc.Confidence, doc.Summary,
-		doc.SourceType, doc.Title, headersJSON, doc.Path,
+		doc.SourceType, doc.Title, headersJSON, doc.Path, doc.SourceID, doc.ParentID, doc.OwnerID,
+		string(doc.EffectiveVisibility()), sharedJSON, doc.ContentHash,
 		string(doc.Status), doc.Error, doc.CreatedAt, doc.UpdatedAt,
 	)
 	if err != nil {
@@ -238,38 +464,83 @@ INSERT INTO documents (
 func (r *DocumentRepository) GetByID(ctx context.Context, id string) (*domain.Document, error) {
 	row := r.db.QueryRowContext(ctx, `
 SELECT id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
-	source_type, title, headers, path,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
 	status, error_message, created_at, updated_at
 FROM documents
 WHERE id = $1
 `, id)
 
-	var doc domain.Document
-	var tagsRaw []byte
-	var headersRaw []byte
-	var status string
-
-	err := row.Scan(
-		&doc.ID, &doc.Filename, &doc.MimeType, &doc.StoragePath, &doc.Category, &doc.Subcategory,
-		&tagsRaw, &doc.Confidence, &doc.Summary,
-		&doc.SourceType, &doc.Title, &headersRaw, &doc.Path,
-		&status, &doc.Error, &doc.CreatedAt, &doc.UpdatedAt,
-	)
+	doc, err := scanDocument(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by id", fmt.Errorf("id=%s", id))
 		}
-		return nil, fmt.Errorf("scan document: %w", err)
+		return nil, err
 	}
+	return doc, nil
+}
 
-	if err := json.Unmarshal(tagsRaw, &doc.Tags); err != nil {
-		return nil, fmt.Errorf("unmarshal tags: %w", err)
+func (r *DocumentRepository) GetBySourceID(ctx context.Context, sourceID string) (*domain.Document, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
+	status, error_message, created_at, updated_at
+FROM documents
+WHERE source_id = $1
+`, sourceID)
+
+	doc, err := scanDocument(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by source id", fmt.Errorf("source_id=%s", sourceID))
+		}
+		return nil, err
 	}
-	if err := json.Unmarshal(headersRaw, &doc.Headers); err != nil {
-		return nil, fmt.Errorf("unmarshal headers: %w", err)
+	return doc, nil
+}
+
+func (r *DocumentRepository) GetByContentHash(ctx context.Context, hash, ownerID string) (*domain.Document, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
+	status, error_message, created_at, updated_at
+FROM documents
+WHERE content_hash = $1 AND owner_id = $2
+ORDER BY created_at DESC
+LIMIT 1
+`, hash, ownerID)
+
+	doc, err := scanDocument(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrDocumentNotFound, "get document by content hash", fmt.Errorf("hash=%s", hash))
+		}
+		return nil, err
 	}
-	doc.Status = domain.DocumentStatus(status)
-	return &doc, nil
+	return doc, nil
+}
+
+// UpdateSource refreshes the source-derived fields of an existing document and
+// resets it to the given status so the pipeline re-processes it in place.
+func (r *DocumentRepository) UpdateSource(ctx context.Context, doc *domain.Document) error {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE documents
+SET filename = $2, mime_type = $3, storage_path = $4, source_type = $5, path = $6,
+	content_hash = $7, status = $8, error_message = $9, updated_at = $10
+WHERE id = $1
+`, doc.ID, doc.Filename, doc.MimeType, doc.StoragePath, doc.SourceType, doc.Path,
+		doc.ContentHash, string(doc.Status), doc.Error, doc.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update document source: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for update document source: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrDocumentNotFound, "update document source", fmt.Errorf("id=%s", doc.ID))
+	}
+	return nil
 }
 
 func (r *DocumentRepository) UpdateStatus(ctx context.Context, id string, status domain.DocumentStatus, errMessage string) error {
@@ -297,7 +568,7 @@ func (r *DocumentRepository) ListRecent(ctx context.Context, limit int) ([]domai
 	}
 	rows, err := r.db.QueryContext(ctx, `
 SELECT id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
-	source_type, title, headers, path,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
 	status, error_message, created_at, updated_at
 FROM documents
 ORDER BY created_at DESC
@@ -307,28 +578,202 @@ LIMIT $1`, limit)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var docs []domain.Document
-	for rows.Next() {
-		var doc domain.Document
-		var tagsRaw []byte
-		var headersRaw []byte
-		var status string
-		if err := rows.Scan(
-			&doc.ID, &doc.Filename, &doc.MimeType, &doc.StoragePath, &doc.Category, &doc.Subcategory,
-			&tagsRaw, &doc.Confidence, &doc.Summary,
-			&doc.SourceType, &doc.Title, &headersRaw, &doc.Path,
-			&status, &doc.Error, &doc.CreatedAt, &doc.UpdatedAt,
-		); err != nil {
-			return nil, fmt.Errorf("scan document row: %w", err)
+	return scanDocuments(rows)
+}
+
+// ListByFilter returns documents matching every non-empty filter field, newest first.
+// A non-positive limit returns all matches.
+func (r *DocumentRepository) ListByFilter(ctx context.Context, filter domain.DocumentFilter, limit int) ([]domain.Document, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	addIn := func(column string, values []string) {
+		if len(values) == 0 {
+			return
 		}
-		if err := json.Unmarshal(tagsRaw, &doc.Tags); err != nil {
-			return nil, fmt.Errorf("unmarshal tags: %w", err)
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
 		}
-		if err := json.Unmarshal(headersRaw, &doc.Headers); err != nil {
-			return nil, fmt.Errorf("unmarshal headers: %w", err)
+		conds = append(conds, fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ",")))
+	}
+
+	statuses := make([]string, len(filter.Statuses))
+	for i, st := range filter.Statuses {
+		statuses[i] = string(st)
+	}
+	addIn("source_type", filter.SourceTypes)
+	addIn("category", filter.Categories)
+	addIn("status", statuses)
+	addIn("owner_id", filter.OwnerIDs)
+	addIn("parent_id", filter.ParentIDs)
+	if len(filter.AccessTokens) > 0 {
+		var cond string
+		cond, args = accessCondition(filter.AccessTokens, args)
+		conds = append(conds, cond)
+	}
+	if filter.PathPrefix != "" {
+		args = append(args, filter.PathPrefix)
+		conds = append(conds, fmt.Sprintf("starts_with(path, $%d)", len(args)))
+	}
+
+	query := `
+SELECT id, filename, mime_type, storage_path, category, subcategory, tags, confidence, summary,
+	source_type, title, headers, path, source_id, parent_id, owner_id, visibility, shared_with, content_hash,
+	status, error_message, created_at, updated_at
+FROM documents`
+	if len(conds) > 0 {
+		query += "\nWHERE " + strings.Join(conds, " AND ")
+	}
+	query += "\nORDER BY created_at DESC"
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list documents by filter: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanDocuments(rows)
+}
+
+// accessCondition mirrors domain.Document.AccessTokens in SQL: a row matches
+// when it is public, owned by one of the token users, or shared with any of
+// the tokens.
+func accessCondition(tokens []string, args []any) (string, []any) {
+	var (
+		ors     []string
+		owners  []string
+		targets []string
+	)
+	for _, token := range tokens {
+		if token == domain.AccessTokenPublic {
+			ors = append(ors, "visibility = 'public'")
+			continue
+		}
+		targets = append(targets, token)
+		if id, ok := strings.CutPrefix(token, domain.UserAccessToken("")); ok && id != "" {
+			owners = append(owners, id)
+		}
+	}
+	placeholders := func(values []string) string {
+		out := make([]string, len(values))
+		for i, v := range values {
+			args = append(args, v)
+			out[i] = fmt.Sprintf("$%d", len(args))
+		}
+		return strings.Join(out, ",")
+	}
+	if len(owners) > 0 {
+		ors = append(ors, fmt.Sprintf("owner_id IN (%s)", placeholders(owners)))
+	}
+	if len(targets) > 0 {
+		ors = append(ors, fmt.Sprintf("(visibility = 'shared' AND shared_with ?| ARRAY[%s]::text[])", placeholders(targets)))
+	}
+	if len(ors) == 0 {
+		return "FALSE", args
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// UpdateACL replaces the visibility and share list of a document.
+func (r *DocumentRepository) UpdateACL(ctx context.Context, id string, visibility domain.DocumentVisibility, sharedWith []string) error {
+	sharedJSON, err := marshalSharedWith(sharedWith)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `
+UPDATE documents
+SET visibility = $2, shared_with = $3, updated_at = $4
+WHERE id = $1
+`, id, string(visibility), sharedJSON, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("update document acl: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for update document acl: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrDocumentNotFound, "update document acl", fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
+
+func marshalSharedWith(sharedWith []string) ([]byte, error) {
+	if sharedWith == nil {
+		sharedWith = []string{}
+	}
+	raw, err := json.Marshal(sharedWith)
+	if err != nil {
+		return nil, fmt.Errorf("marshal shared_with: %w", err)
+	}
+	return raw, nil
+}
+
+func (r *DocumentRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM documents WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete document: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for delete document: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrDocumentNotFound, "delete document", fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDocument(row rowScanner) (*domain.Document, error) {
+	var doc domain.Document
+	var tagsRaw []byte
+	var headersRaw []byte
+	var sharedRaw []byte
+	var visibility string
+	var status string
+
+	if err := row.Scan(
+		&doc.ID, &doc.Filename, &doc.MimeType, &doc.StoragePath, &doc.Category, &doc.Subcategory,
+		&tagsRaw, &doc.Confidence, &doc.Summary,
+		&doc.SourceType, &doc.Title, &headersRaw, &doc.Path, &doc.SourceID, &doc.ParentID, &doc.OwnerID,
+		&visibility, &sharedRaw, &doc.ContentHash,
+		&status, &doc.Error, &doc.CreatedAt, &doc.UpdatedAt,
+	); err != nil {
+		return nil, fmt.Errorf("scan document: %w", err)
+	}
+	if err := json.Unmarshal(tagsRaw, &doc.Tags); err != nil {
+		return nil, fmt.Errorf("unmarshal tags: %w", err)
+	}
+	if err := json.Unmarshal(headersRaw, &doc.Headers); err != nil {
+		return nil, fmt.Errorf("unmarshal headers: %w", err)
+	}
+	if err := json.Unmarshal(sharedRaw, &doc.SharedWith); err != nil {
+		return nil, fmt.Errorf("unmarshal shared_with: %w", err)
+	}
+	doc.Visibility = domain.DocumentVisibility(visibility)
+	doc.Status = domain.DocumentStatus(status)
+	return &doc, nil
+}
+
+func scanDocuments(rows *sql.Rows) ([]domain.Document, error) {
+	var docs []domain.Document
+	for rows.Next() {
+		doc, err := scanDocument(rows)
+		if err != nil {
+			return nil, err
 		}
-		doc.Status = domain.DocumentStatus(status)
-		docs = append(docs, doc)
+		docs = append(docs, *doc)
 	}
 	return docs, rows.Err()
 }
@@ -84,3 +84,126 @@ func TestSaveClassificationReturnsDomainNotFoundWhenNoRowsAffected(t *testing.T)
 		t.Fatalf("expectations: %v", err)
 	}
 }
+
+func TestDeleteReturnsDomainNotFoundWhenNoRowsAffected(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectExec("DELETE FROM documents").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListByFilterBuildsWhereClause(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE source_type IN \(\$1,\$2\) AND status IN \(\$3\) AND starts_with\(path, \$4\)`).
+		WithArgs("obsidian", "web", "failed", "notes/").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListByFilter(context.Background(), domain.DocumentFilter{
+		SourceTypes: []string{"obsidian", "web"},
+		Statuses:    []domain.DocumentStatus{domain.StatusFailed},
+		PathPrefix:  "notes/",
+	}, 0)
+	if err != nil {
+		t.Fatalf("ListByFilter() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetBySourceIDReturnsDomainNotFound(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("WHERE source_id = ").
+		WithArgs("obsidian:main:a.md").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetBySourceID(context.Background(), "obsidian:main:a.md")
+	if !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetByContentHashScopesByOwner(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE content_hash = \$1 AND owner_id = \$2`).
+		WithArgs("abc", "u-1").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetByContentHash(context.Background(), "abc", "u-1")
+	if !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListByFilterScopesByOwner(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE owner_id IN \(\$1,\$2\)\s+ORDER BY created_at DESC\s+LIMIT \$3`).
+		WithArgs("u-1", "", 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListByFilter(context.Background(), domain.DocumentFilter{OwnerIDs: []string{"u-1", ""}}, 20)
+	if err != nil {
+		t.Fatalf("ListByFilter() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListByFilterScopesByAccessTokens(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE \(visibility = 'public' OR owner_id IN \(\$1\) OR \(visibility = 'shared' AND shared_with \?\| ARRAY\[\$2,\$3\]::text\[\]\)\)`).
+		WithArgs("u-1", "user:u-1", "group:team", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	filter := domain.DocumentFilter{AccessTokens: []string{"*", "user:u-1", "group:team"}}
+	if _, err := repo.ListByFilter(context.Background(), filter, 10); err != nil {
+		t.Fatalf("ListByFilter() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUpdateACLReturnsDomainNotFound(t *testing.T) {
+	repo, mock, done := newRepoWithMock(t)
+	defer done()
+
+	mock.ExpectExec(`SET visibility = \$2, shared_with = \$3`).
+		WithArgs("missing", "shared", []byte(`["group:team"]`), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateACL(context.Background(), "missing", domain.VisibilityShared, []string{"group:team"})
+	if !domain.IsKind(err, domain.ErrDocumentNotFound) {
+		t.Fatalf("expected ErrDocumentNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
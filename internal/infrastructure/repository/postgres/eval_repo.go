@@ -0,0 +1,254 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// EvalRepository persists retrieval eval cases and run history to Postgres.
+type EvalRepository struct {
+	db *sql.DB
+}
+
+func NewEvalRepository(db *sql.DB) *EvalRepository {
+	return &EvalRepository{db: db}
+}
+
+// UpsertCase creates the case or replaces the one with the same ID. The
+// original created_at of a replaced case is kept and written back into c.
+func (r *EvalRepository) UpsertCase(ctx context.Context, c *domain.EvalCase) error {
+	idsJSON, err := json.Marshal(nonNilStrings(c.ExpectedDocumentIDs))
+	if err != nil {
+		return fmt.Errorf("marshal expected document ids: %w", err)
+	}
+	filesJSON, err := json.Marshal(nonNilStrings(c.ExpectedFilenames))
+	if err != nil {
+		return fmt.Errorf("marshal expected filenames: %w", err)
+	}
+	err = r.db.QueryRowContext(ctx, `
+INSERT INTO eval_cases (id, question, expected_document_ids, expected_filenames, ground_truth, source, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (id) DO UPDATE SET
+	question = EXCLUDED.question,
+	expected_document_ids = EXCLUDED.expected_document_ids,
+	expected_filenames = EXCLUDED.expected_filenames,
+	ground_truth = EXCLUDED.ground_truth,
+	source = EXCLUDED.source,
+	updated_at = EXCLUDED.updated_at
+RETURNING created_at
+`, c.ID, c.Question, idsJSON, filesJSON, c.GroundTruth, string(c.Source), c.CreatedAt, c.UpdatedAt).Scan(&c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert eval case: %w", err)
+	}
+	return nil
+}
+
+func (r *EvalRepository) ListCases(ctx context.Context) ([]domain.EvalCase, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, question, expected_document_ids, expected_filenames, ground_truth, source, created_at, updated_at
+FROM eval_cases
+ORDER BY created_at, id
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list eval cases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	cases := make([]domain.EvalCase, 0)
+	for rows.Next() {
+		var (
+			c         domain.EvalCase
+			idsJSON   []byte
+			filesJSON []byte
+			source    string
+		)
+		if err := rows.Scan(&c.ID, &c.Question, &idsJSON, &filesJSON, &c.GroundTruth, &source, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan eval case: %w", err)
+		}
+		if err := json.Unmarshal(idsJSON, &c.ExpectedDocumentIDs); err != nil {
+			return nil, fmt.Errorf("unmarshal expected document ids: %w", err)
+		}
+		if err := json.Unmarshal(filesJSON, &c.ExpectedFilenames); err != nil {
+			return nil, fmt.Errorf("unmarshal expected filenames: %w", err)
+		}
+		c.Source = domain.EvalCaseSource(source)
+		cases = append(cases, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate eval cases: %w", err)
+	}
+	return cases, nil
+}
+
+func (r *EvalRepository) DeleteCase(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM eval_cases WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete eval case: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete eval case rows affected: %w", err)
+	}
+	if n == 0 {
+		return domain.WrapError(domain.ErrEvalCaseNotFound, "delete eval case", fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
+
+func (r *EvalRepository) CreateRun(ctx context.Context, run *domain.EvalRun) error {
+	configJSON, err := json.Marshal(run.Config)
+	if err != nil {
+		return fmt.Errorf("marshal eval config: %w", err)
+	}
+	var chunkingJSON []byte
+	if len(run.Chunking) > 0 {
+		if chunkingJSON, err = json.Marshal(run.Chunking); err != nil {
+			return fmt.Errorf("marshal eval chunking: %w", err)
+		}
+	}
+	_, err = r.db.ExecContext(ctx, `
+INSERT INTO eval_runs (id, status, config, chunking, k, generate_answers, case_count, started_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`, run.ID, run.Status, configJSON, chunkingJSON, run.K, run.GenerateAnswers, run.CaseCount, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("insert eval run: %w", err)
+	}
+	return nil
+}
+
+func (r *EvalRepository) FinishRun(ctx context.Context, run *domain.EvalRun) error {
+	metricsJSON, err := json.Marshal(run.Metrics)
+	if err != nil {
+		return fmt.Errorf("marshal eval metrics: %w", err)
+	}
+	results := run.Results
+	if results == nil {
+		results = []domain.EvalCaseResult{}
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("marshal eval results: %w", err)
+	}
+	var gateJSON []byte
+	if run.Gate != nil {
+		if gateJSON, err = json.Marshal(run.Gate); err != nil {
+			return fmt.Errorf("marshal eval gate: %w", err)
+		}
+	}
+	_, err = r.db.ExecContext(ctx, `
+UPDATE eval_runs
+SET status = $2, failed = $3, metrics = $4, results = $5, gate = $6, error = $7, finished_at = $8
+WHERE id = $1
+`, run.ID, run.Status, run.Failed, metricsJSON, resultsJSON, gateJSON, run.Error, run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("finish eval run: %w", err)
+	}
+	return nil
+}
+
+func (r *EvalRepository) GetRun(ctx context.Context, id string) (*domain.EvalRun, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, status, config, chunking, k, generate_answers, case_count, failed, metrics, gate, error, started_at, finished_at, results
+FROM eval_runs
+WHERE id = $1
+`, id)
+	run, err := scanEvalRun(row, true)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.WrapError(domain.ErrEvalRunNotFound, "get eval run", fmt.Errorf("id=%s", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get eval run: %w", err)
+	}
+	return run, nil
+}
+
+func (r *EvalRepository) ListRuns(ctx context.Context, limit int) ([]domain.EvalRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, status, config, chunking, k, generate_answers, case_count, failed, metrics, gate, error, started_at, finished_at
+FROM eval_runs
+ORDER BY started_at DESC
+LIMIT $1
+`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list eval runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]domain.EvalRun, 0)
+	for rows.Next() {
+		run, err := scanEvalRun(rows, false)
+		if err != nil {
+			return nil, fmt.Errorf("scan eval run: %w", err)
+		}
+		runs = append(runs, *run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate eval runs: %w", err)
+	}
+	return runs, nil
+}
+
+// scanEvalRun reads the run columns in the order selected above; the results
+// column is expected last and only when withResults is set.
+func scanEvalRun(row rowScanner, withResults bool) (*domain.EvalRun, error) {
+	var (
+		run          domain.EvalRun
+		configJSON   []byte
+		chunkingJSON []byte
+		metricsJSON  []byte
+		gateJSON     []byte
+		resultsJSON  []byte
+		finishedAt   sql.NullTime
+	)
+	dest := []any{
+		&run.ID, &run.Status, &configJSON, &chunkingJSON, &run.K, &run.GenerateAnswers, &run.CaseCount, &run.Failed,
+		&metricsJSON, &gateJSON, &run.Error, &run.StartedAt, &finishedAt,
+	}
+	if withResults {
+		dest = append(dest, &resultsJSON)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(configJSON, &run.Config); err != nil {
+		return nil, fmt.Errorf("unmarshal eval config: %w", err)
+	}
+	if len(chunkingJSON) > 0 {
+		if err := json.Unmarshal(chunkingJSON, &run.Chunking); err != nil {
+			return nil, fmt.Errorf("unmarshal eval chunking: %w", err)
+		}
+	}
+	if err := json.Unmarshal(metricsJSON, &run.Metrics); err != nil {
+		return nil, fmt.Errorf("unmarshal eval metrics: %w", err)
+	}
+	if len(gateJSON) > 0 {
+		if err := json.Unmarshal(gateJSON, &run.Gate); err != nil {
+			return nil, fmt.Errorf("unmarshal eval gate: %w", err)
+		}
+	}
+	if len(resultsJSON) > 0 {
+		if err := json.Unmarshal(resultsJSON, &run.Results); err != nil {
+			return nil, fmt.Errorf("unmarshal eval results: %w", err)
+		}
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		run.FinishedAt = &t
+	}
+	return &run, nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
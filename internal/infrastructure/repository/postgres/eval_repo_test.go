@@ -0,0 +1,139 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func newEvalRepoWithMock(t *testing.T) (*EvalRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	return NewEvalRepository(db), mock, func() { _ = db.Close() }
+}
+
+func TestUpsertEvalCase_KeepsOriginalCreatedAt(t *testing.T) {
+	repo, mock, done := newEvalRepoWithMock(t)
+	defer done()
+
+	now := time.Now().UTC()
+	created := now.Add(-time.Hour)
+	mock.ExpectQuery("INSERT INTO eval_cases").
+		WithArgs("EX001", "Where is the VPN guide?", []byte(`[]`), []byte(`["vpn.md"]`), "", "api", now, now).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(created))
+
+	c := &domain.EvalCase{
+		ID: "EX001", Question: "Where is the VPN guide?", ExpectedFilenames: []string{"vpn.md"},
+		Source: domain.EvalCaseSourceAPI, CreatedAt: now, UpdatedAt: now,
+	}
+	if err := repo.UpsertCase(context.Background(), c); err != nil {
+		t.Fatalf("UpsertCase error: %v", err)
+	}
+	if !c.CreatedAt.Equal(created) {
+		t.Fatalf("expected created_at from the stored row, got %v", c.CreatedAt)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestDeleteEvalCase_NotFound(t *testing.T) {
+	repo, mock, done := newEvalRepoWithMock(t)
+	defer done()
+
+	mock.ExpectExec("DELETE FROM eval_cases").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteCase(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrEvalCaseNotFound) {
+		t.Fatalf("expected ErrEvalCaseNotFound, got %v", err)
+	}
+}
+
+func TestGetEvalRun_DecodesResultsAndGate(t *testing.T) {
+	repo, mock, done := newEvalRepoWithMock(t)
+	defer done()
+
+	started := time.Now().UTC().Add(-time.Minute)
+	finished := started.Add(30 * time.Second)
+	mock.ExpectQuery("SELECT id, status, config").
+		WithArgs("run-1").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "status", "config", "chunking", "k", "generate_answers", "case_count", "failed",
+			"metrics", "gate", "error", "started_at", "finished_at", "results",
+		}).AddRow(
+			"run-1", "ok", []byte(`{"mode":"hybrid"}`), []byte(`{"":{"strategy":"semantic","unit":"tokens"}}`), 5, false, 1, 0,
+			[]byte(`{"precision_at_k":0.2,"recall_at_k":1,"mrr":1,"ndcg":1}`),
+			[]byte(`{"baseline_run_id":"run-0","max_drop":0.02,"passed":true}`),
+			"", started, finished,
+			[]byte(`[{"case_id":"EX001","question":"q","retrieved":["vpn.md"],"relevant":1,"expected":1,"metrics":{"recall_at_k":1},"latency_ms":12}]`),
+		))
+
+	run, err := repo.GetRun(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("GetRun error: %v", err)
+	}
+	if run.Config.Mode != domain.RetrievalModeHybrid || run.Metrics.RecallAtK != 1 {
+		t.Fatalf("unexpected run: %+v", run)
+	}
+	if run.Chunking[""].Strategy != "semantic" || run.Chunking[""].Unit != "tokens" {
+		t.Fatalf("unexpected chunking: %+v", run.Chunking)
+	}
+	if run.Gate == nil || !run.Gate.Passed || run.Gate.BaselineRunID != "run-0" {
+		t.Fatalf("unexpected gate: %+v", run.Gate)
+	}
+	if len(run.Results) != 1 || run.Results[0].Retrieved[0] != "vpn.md" {
+		t.Fatalf("unexpected results: %+v", run.Results)
+	}
+	if run.FinishedAt == nil || !run.FinishedAt.Equal(finished) {
+		t.Fatalf("unexpected finished_at: %v", run.FinishedAt)
+	}
+}
+
+func TestGetEvalRun_NotFound(t *testing.T) {
+	repo, mock, done := newEvalRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("SELECT id, status, config").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.GetRun(context.Background(), "missing"); !domain.IsKind(err, domain.ErrEvalRunNotFound) {
+		t.Fatalf("expected ErrEvalRunNotFound, got %v", err)
+	}
+}
+
+func TestListEvalRuns_OmitsResults(t *testing.T) {
+	repo, mock, done := newEvalRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("SELECT id, status, config").
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "status", "config", "chunking", "k", "generate_answers", "case_count", "failed",
+			"metrics", "gate", "error", "started_at", "finished_at",
+		}).AddRow(
+			"run-1", "running", []byte(`{"mode":"semantic"}`), nil, 5, false, 3, 0,
+			[]byte(`{}`), nil, "", time.Now().UTC(), nil,
+		))
+
+	runs, err := repo.ListRuns(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("ListRuns error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Results != nil || runs[0].Gate != nil || runs[0].FinishedAt != nil {
+		t.Fatalf("unexpected runs: %+v", runs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// IngestJobRepository implements ports.IngestJobStore.
+type IngestJobRepository struct {
+	db *sql.DB
+}
+
+func NewIngestJobRepository(db *sql.DB) *IngestJobRepository {
+	return &IngestJobRepository{db: db}
+}
+
+const ingestJobSelect = `
+SELECT id, kind, source, owner_id, status, total, ingested, skipped, failed, error_message,
+	created_at, started_at, finished_at, heartbeat_at
+FROM ingest_jobs
+`
+
+func (r *IngestJobRepository) CreateIngestJob(ctx context.Context, job *domain.IngestJob) error {
+	if job.ID == "" {
+		job.ID = uuid.NewString()
+	}
+	if job.CreatedAt.IsZero() {
+		job.CreatedAt = time.Now().UTC()
+	}
+	if job.Status == "" {
+		job.Status = domain.IngestJobStatusQueued
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO ingest_jobs (id, kind, source, owner_id, status, created_at, heartbeat_at)
+VALUES ($1, $2, $3, $4, $5, $6, $6)
+`, job.ID, string(job.Kind), job.Source, job.OwnerID, job.Status, job.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert ingest job: %w", err)
+	}
+	return nil
+}
+
+func (r *IngestJobRepository) UpdateIngestJob(ctx context.Context, job *domain.IngestJob) error {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE ingest_jobs
+SET status = $2, total = $3, ingested = $4, skipped = $5, failed = $6, error_message = $7,
+	started_at = $8, finished_at = $9
+WHERE id = $1
+`, job.ID, job.Status, job.Total, job.Ingested, job.Skipped, job.Failed, job.Error,
+		nullTime(job.StartedAt), nullTime(job.FinishedAt))
+	if err != nil {
+		return fmt.Errorf("update ingest job: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for update ingest job: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrIngestJobNotFound, "update ingest job", fmt.Errorf("id=%s", job.ID))
+	}
+	return nil
+}
+
+// HeartbeatIngestJob records that the process running a job is alive.
+func (r *IngestJobRepository) HeartbeatIngestJob(ctx context.Context, id string, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE ingest_jobs SET heartbeat_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("heartbeat ingest job: %w", err)
+	}
+	return nil
+}
+
+// FailStaleIngestJobs marks queued and running jobs whose last heartbeat is
+// older than staleBefore as failed. Jobs of live processes keep beating and
+// are left alone.
+func (r *IngestJobRepository) FailStaleIngestJobs(ctx context.Context, message string, staleBefore, finishedAt time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE ingest_jobs
+SET status = $1, error_message = $2, finished_at = $3
+WHERE status IN ($4, $5) AND COALESCE(heartbeat_at, created_at) < $6
+`, domain.IngestJobStatusFailed, message, finishedAt, domain.IngestJobStatusQueued, domain.IngestJobStatusRunning, staleBefore)
+	if err != nil {
+		return 0, fmt.Errorf("fail stale ingest jobs: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("rows affected for fail stale ingest jobs: %w", err)
+	}
+	return int(rows), nil
+}
+
+func (r *IngestJobRepository) AddIngestJobFiles(ctx context.Context, jobID string, files []domain.IngestJobFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin ingest job files tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, f := range files {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO ingest_job_files (job_id, path, status, document_id, hash, size, error_message)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`, jobID, f.Path, f.Status, f.DocumentID, f.Hash, f.Size, f.Error); err != nil {
+			return fmt.Errorf("insert ingest job file %s: %w", f.Path, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit ingest job files tx: %w", err)
+	}
+	return nil
+}
+
+func (r *IngestJobRepository) GetIngestJob(ctx context.Context, id string) (*domain.IngestJob, error) {
+	job, err := scanIngestJob(r.db.QueryRowContext(ctx, ingestJobSelect+`WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrIngestJobNotFound, "get ingest job", fmt.Errorf("id=%s", id))
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *IngestJobRepository) ListIngestJobs(ctx context.Context, ownerID string, limit int) ([]domain.IngestJob, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, ingestJobSelect+`
+WHERE $1 = '' OR owner_id = $1
+ORDER BY created_at DESC
+LIMIT $2
+`, ownerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list ingest jobs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := make([]domain.IngestJob, 0)
+	for rows.Next() {
+		job, err := scanIngestJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ingest job rows: %w", err)
+	}
+	return jobs, nil
+}
+
+// ListIngestJobFiles returns all matching files when limit is not positive.
+func (r *IngestJobRepository) ListIngestJobFiles(ctx context.Context, jobID, status string, limit, offset int) ([]domain.IngestJobFile, error) {
+	query := `
+SELECT path, status, document_id, hash, size, error_message
+FROM ingest_job_files
+WHERE job_id = $1 AND ($2 = '' OR status = $2)
+ORDER BY id
+OFFSET $3
+`
+	args := []any{jobID, status, max(offset, 0)}
+	if limit > 0 {
+		query += `LIMIT $4`
+		args = append(args, limit)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list ingest job files: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	files := make([]domain.IngestJobFile, 0)
+	for rows.Next() {
+		var f domain.IngestJobFile
+		if err := rows.Scan(&f.Path, &f.Status, &f.DocumentID, &f.Hash, &f.Size, &f.Error); err != nil {
+			return nil, fmt.Errorf("scan ingest job file: %w", err)
+		}
+		files = append(files, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ingest job file rows: %w", err)
+	}
+	return files, nil
+}
+
+func scanIngestJob(row rowScanner) (*domain.IngestJob, error) {
+	var (
+		job         domain.IngestJob
+		kind        string
+		startedAt   sql.NullTime
+		finishedAt  sql.NullTime
+		heartbeatAt sql.NullTime
+	)
+	if err := row.Scan(
+		&job.ID, &kind, &job.Source, &job.OwnerID, &job.Status,
+		&job.Total, &job.Ingested, &job.Skipped, &job.Failed, &job.Error,
+		&job.CreatedAt, &startedAt, &finishedAt, &heartbeatAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan ingest job: %w", err)
+	}
+	job.Kind = domain.IngestJobKind(kind)
+	if startedAt.Valid {
+		t := startedAt.Time
+		job.StartedAt = &t
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		job.FinishedAt = &t
+	}
+	if heartbeatAt.Valid {
+		t := heartbeatAt.Time
+		job.HeartbeatAt = &t
+	}
+	return &job, nil
+}
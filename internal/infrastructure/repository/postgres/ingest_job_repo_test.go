@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestIngestJobRepositoryGetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewIngestJobRepository(db)
+	mock.ExpectQuery("FROM ingest_jobs").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetIngestJob(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrIngestJobNotFound) {
+		t.Fatalf("expected ErrIngestJobNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestIngestJobRepositoryUpdateWritesTimestamps(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	started := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	job := &domain.IngestJob{ID: "job-1", Status: domain.IngestJobStatusRunning, Total: 3, Ingested: 1, StartedAt: &started}
+
+	repo := NewIngestJobRepository(db)
+	mock.ExpectExec("UPDATE ingest_jobs").
+		WithArgs("job-1", domain.IngestJobStatusRunning, 3, 1, 0, 0, "", nullTime(&started), nullTime(nil)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.UpdateIngestJob(context.Background(), job); err != nil {
+		t.Fatalf("UpdateIngestJob() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestIngestJobRepositoryFailStaleJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	finished := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	staleBefore := finished.Add(-2 * time.Minute)
+	repo := NewIngestJobRepository(db)
+	mock.ExpectExec(`UPDATE ingest_jobs\s+SET status = \$1, error_message = \$2, finished_at = \$3\s+WHERE status IN \(\$4, \$5\) AND COALESCE\(heartbeat_at, created_at\) < \$6`).
+		WithArgs(domain.IngestJobStatusFailed, "interrupted", finished, domain.IngestJobStatusQueued, domain.IngestJobStatusRunning, staleBefore).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	n, err := repo.FailStaleIngestJobs(context.Background(), "interrupted", staleBefore, finished)
+	if err != nil || n != 2 {
+		t.Fatalf("FailStaleIngestJobs() = %d, %v", n, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestIngestJobRepositoryListFilesByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewIngestJobRepository(db)
+	mock.ExpectQuery(`FROM ingest_job_files\s+WHERE job_id = \$1 AND \(\$2 = '' OR status = \$2\)\s+ORDER BY id\s+OFFSET \$3\s+LIMIT \$4`).
+		WithArgs("job-1", domain.IngestFileFailed, 0, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"path", "status", "document_id", "hash", "size", "error_message"}).
+			AddRow("wiki/a.md", domain.IngestFileFailed, "", "", int64(10), "file exceeds 5 bytes"))
+
+	files, err := repo.ListIngestJobFiles(context.Background(), "job-1", domain.IngestFileFailed, 50, 0)
+	if err != nil {
+		t.Fatalf("ListIngestJobFiles() error = %v", err)
+	}
+	if len(files) != 1 || files[0].Path != "wiki/a.md" || files[0].Error == "" {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
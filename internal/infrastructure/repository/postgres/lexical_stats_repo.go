@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// LexicalStatsRepository stores BM25 corpus statistics per Qdrant
+// collection. Counters are updated with relative increments so concurrent
+// ingests do not lose each other's deltas.
+type LexicalStatsRepository struct {
+	db *sql.DB
+}
+
+func NewLexicalStatsRepository(db *sql.DB) *LexicalStatsRepository {
+	return &LexicalStatsRepository{db: db}
+}
+
+func (r *LexicalStatsRepository) AddLexicalStats(ctx context.Context, collection string, delta domain.LexicalStats) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin lexical stats tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO lexical_collection_stats (collection, docs, total_length)
+VALUES ($1, GREATEST($2, 0), GREATEST($3, 0))
+ON CONFLICT (collection) DO UPDATE SET
+	docs = GREATEST(lexical_collection_stats.docs + $2, 0),
+	total_length = GREATEST(lexical_collection_stats.total_length + $3, 0)
+`, collection, delta.Docs, delta.TotalLength)
+	if err != nil {
+		return fmt.Errorf("update lexical collection stats: %w", err)
+	}
+
+	if len(delta.DocFreq) > 0 {
+		dfJSON, err := json.Marshal(delta.DocFreq)
+		if err != nil {
+			return fmt.Errorf("marshal lexical doc freq: %w", err)
+		}
+		// Removing a term never seen before inserts a negative count; the
+		// prune below drops it together with terms that reached zero.
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO lexical_term_stats (collection, term, df)
+SELECT $1, d.key, d.value::bigint
+FROM jsonb_each_text($2::jsonb) AS d
+ON CONFLICT (collection, term) DO UPDATE SET
+	df = GREATEST(lexical_term_stats.df + EXCLUDED.df, 0)
+`, collection, dfJSON)
+		if err != nil {
+			return fmt.Errorf("update lexical term stats: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+DELETE FROM lexical_term_stats
+WHERE collection = $1 AND df <= 0 AND term IN (SELECT jsonb_object_keys($2::jsonb))
+`, collection, dfJSON); err != nil {
+			return fmt.Errorf("prune lexical term stats: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit lexical stats: %w", err)
+	}
+	return nil
+}
+
+func (r *LexicalStatsRepository) LexicalStats(ctx context.Context, collection string, terms []string) (*domain.LexicalStats, error) {
+	stats := &domain.LexicalStats{DocFreq: make(map[string]int64, len(terms))}
+	err := r.db.QueryRowContext(ctx, `
+SELECT docs, total_length FROM lexical_collection_stats WHERE collection = $1
+`, collection).Scan(&stats.Docs, &stats.TotalLength)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("get lexical collection stats: %w", err)
+	}
+	if len(terms) == 0 {
+		return stats, nil
+	}
+
+	termsJSON, err := json.Marshal(terms)
+	if err != nil {
+		return nil, fmt.Errorf("marshal lexical terms: %w", err)
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT term, df
+FROM lexical_term_stats
+WHERE collection = $1 AND term IN (SELECT jsonb_array_elements_text($2::jsonb))
+`, collection, termsJSON)
+	if err != nil {
+		return nil, fmt.Errorf("get lexical term stats: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var (
+			term string
+			df   int64
+		)
+		if err := rows.Scan(&term, &df); err != nil {
+			return nil, fmt.Errorf("scan lexical term stats: %w", err)
+		}
+		stats.DocFreq[term] = df
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate lexical term stats: %w", err)
+	}
+	return stats, nil
+}
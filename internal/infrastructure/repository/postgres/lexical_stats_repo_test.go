@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func newLexicalStatsRepoWithMock(t *testing.T) (*LexicalStatsRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	return NewLexicalStatsRepository(db), mock, func() { _ = db.Close() }
+}
+
+func TestAddLexicalStats_AppliesDeltaAndPrunes(t *testing.T) {
+	repo, mock, done := newLexicalStatsRepoWithMock(t)
+	defer done()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO lexical_collection_stats").
+		WithArgs("docs", int64(-2), int64(-7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO lexical_term_stats").
+		WithArgs("docs", []byte(`{"invoice":-2}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("DELETE FROM lexical_term_stats").
+		WithArgs("docs", []byte(`{"invoice":-2}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	delta := domain.LexicalStats{Docs: -2, TotalLength: -7, DocFreq: map[string]int64{"invoice": -2}}
+	if err := repo.AddLexicalStats(context.Background(), "docs", delta); err != nil {
+		t.Fatalf("AddLexicalStats error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestLexicalStats_MissingCollectionIsEmpty(t *testing.T) {
+	repo, mock, done := newLexicalStatsRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("SELECT docs, total_length FROM lexical_collection_stats").
+		WithArgs("docs").
+		WillReturnRows(sqlmock.NewRows([]string{"docs", "total_length"}))
+	mock.ExpectQuery("FROM lexical_term_stats").
+		WithArgs("docs", []byte(`["invoice","vpn"]`)).
+		WillReturnRows(sqlmock.NewRows([]string{"term", "df"}).AddRow("vpn", int64(3)))
+
+	stats, err := repo.LexicalStats(context.Background(), "docs", []string{"invoice", "vpn"})
+	if err != nil {
+		t.Fatalf("LexicalStats error: %v", err)
+	}
+	if stats.Docs != 0 || stats.DocFreq["vpn"] != 3 || stats.DocFreq["invoice"] != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
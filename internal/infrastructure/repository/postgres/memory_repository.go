@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
@@ -40,3 +41,117 @@ WHERE user_id = $1 AND conversation_id = $2
 	}
 	return turn, nil
 }
+
+func (r *MemoryRepository) DeleteSummaries(ctx context.Context, userID, conversationID string) error {
+	_, err := r.db.ExecContext(ctx, `
+DELETE FROM memory_summaries
+WHERE user_id = $1 AND conversation_id = $2
+`, userID, conversationID)
+	if err != nil {
+		return fmt.Errorf("delete memory summaries: %w", err)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateFact(ctx context.Context, fact *domain.MemoryFact) error {
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO memory_facts (id, user_id, content, source, created_at, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6)
+`, fact.ID, fact.UserID, fact.Content, string(fact.Source), fact.CreatedAt, fact.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create memory fact: %w", err)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) UpdateFact(ctx context.Context, fact *domain.MemoryFact) error {
+	res, err := r.db.ExecContext(ctx, `
+UPDATE memory_facts
+SET content = $3, source = $4, updated_at = $5
+WHERE id = $1 AND user_id = $2
+`, fact.ID, fact.UserID, fact.Content, string(fact.Source), fact.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update memory fact: %w", err)
+	}
+	return requireFactRowAffected(res, "update memory fact", fact.ID)
+}
+
+func (r *MemoryRepository) GetFact(ctx context.Context, userID, id string) (*domain.MemoryFact, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, user_id, content, source, created_at, updated_at
+FROM memory_facts
+WHERE id = $1 AND user_id = $2
+`, id, userID)
+	fact, err := scanMemoryFact(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.WrapError(domain.ErrMemoryFactNotFound, "get memory fact", fmt.Errorf("id=%s", id))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get memory fact: %w", err)
+	}
+	return fact, nil
+}
+
+func (r *MemoryRepository) ListFacts(ctx context.Context, userID string, limit int) ([]domain.MemoryFact, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, user_id, content, source, created_at, updated_at
+FROM memory_facts
+WHERE user_id = $1
+ORDER BY updated_at DESC
+LIMIT $2
+`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list memory facts: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]domain.MemoryFact, 0)
+	for rows.Next() {
+		fact, err := scanMemoryFact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan memory fact: %w", err)
+		}
+		out = append(out, *fact)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate memory facts: %w", err)
+	}
+	return out, nil
+}
+
+func (r *MemoryRepository) DeleteFact(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, `
+DELETE FROM memory_facts
+WHERE id = $1 AND user_id = $2
+`, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete memory fact: %w", err)
+	}
+	return requireFactRowAffected(res, "delete memory fact", id)
+}
+
+func scanMemoryFact(row rowScanner) (*domain.MemoryFact, error) {
+	var (
+		fact   domain.MemoryFact
+		source string
+	)
+	if err := row.Scan(&fact.ID, &fact.UserID, &fact.Content, &source, &fact.CreatedAt, &fact.UpdatedAt); err != nil {
+		return nil, err
+	}
+	fact.Source = domain.MemoryFactSource(source)
+	return &fact, nil
+}
+
+func requireFactRowAffected(res sql.Result, op, id string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", op, err)
+	}
+	if n == 0 {
+		return domain.WrapError(domain.ErrMemoryFactNotFound, op, fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
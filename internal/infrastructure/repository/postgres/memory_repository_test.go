@@ -77,3 +77,71 @@ func TestGetLastSummaryEndTurn_NoRows(t *testing.T) {
 		t.Fatalf("expected turn 0, got %d", turn)
 	}
 }
+
+func TestListFacts_ScopedToUser(t *testing.T) {
+	repo, mock, done := newMemRepoWithMock(t)
+	defer done()
+
+	now := time.Now().UTC()
+	mock.ExpectQuery("SELECT id, user_id, content, source, created_at, updated_at\\s+FROM memory_facts\\s+WHERE user_id = \\$1").
+		WithArgs("u-1", 200).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "content", "source", "created_at", "updated_at"}).
+			AddRow("f-1", "u-1", "staging DB is db-stage-2", "agent", now, now))
+
+	facts, err := repo.ListFacts(context.Background(), "u-1", 200)
+	if err != nil {
+		t.Fatalf("ListFacts error: %v", err)
+	}
+	if len(facts) != 1 || facts[0].Source != domain.MemoryFactSourceAgent || facts[0].Content != "staging DB is db-stage-2" {
+		t.Fatalf("unexpected facts: %+v", facts)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUpdateFact_OtherUserIsNotFound(t *testing.T) {
+	repo, mock, done := newMemRepoWithMock(t)
+	defer done()
+
+	now := time.Now().UTC()
+	mock.ExpectExec("UPDATE memory_facts").
+		WithArgs("f-1", "u-2", "new", "user", now).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.UpdateFact(context.Background(), &domain.MemoryFact{
+		ID: "f-1", UserID: "u-2", Content: "new", Source: domain.MemoryFactSourceUser, UpdatedAt: now,
+	})
+	if !domain.IsKind(err, domain.ErrMemoryFactNotFound) {
+		t.Fatalf("UpdateFact error = %v, want memory fact not found", err)
+	}
+}
+
+func TestDeleteFact_Success(t *testing.T) {
+	repo, mock, done := newMemRepoWithMock(t)
+	defer done()
+
+	mock.ExpectExec("DELETE FROM memory_facts").
+		WithArgs("f-1", "u-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.DeleteFact(context.Background(), "u-1", "f-1"); err != nil {
+		t.Fatalf("DeleteFact error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetFact_NotFound(t *testing.T) {
+	repo, mock, done := newMemRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("FROM memory_facts").
+		WithArgs("f-9", "u-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "content", "source", "created_at", "updated_at"}))
+
+	if _, err := repo.GetFact(context.Background(), "u-1", "f-9"); !domain.IsKind(err, domain.ErrMemoryFactNotFound) {
+		t.Fatalf("GetFact error = %v, want memory fact not found", err)
+	}
+}
@@ -117,7 +117,7 @@ WHERE id = $1
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("orchestration not found: id=%s", orchID)
+			return nil, domain.WrapError(domain.ErrOrchestrationNotFound, "get orchestration", fmt.Errorf("id=%s", orchID))
 		}
 		return nil, fmt.Errorf("scan orchestration: %w", err)
 	}
@@ -143,6 +143,33 @@ LIMIT $2
 	if err != nil {
 		return nil, fmt.Errorf("list orchestrations by user: %w", err)
 	}
+	return scanOrchestrations(rows)
+}
+
+// ListByConversation retrieves the most recent orchestrations of one of the
+// user's conversations.
+func (r *OrchestrationRepository) ListByConversation(ctx context.Context, userID, conversationID string, limit int) ([]domain.Orchestration, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, user_id, conversation_id, request, plan, steps, status, created_at, completed_at
+FROM orchestrations
+WHERE user_id = $1 AND conversation_id = $2
+ORDER BY created_at DESC
+LIMIT $3
+`, userID, conversationID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list orchestrations by conversation: %w", err)
+	}
+	return scanOrchestrations(rows)
+}
+
+func (r *OrchestrationRepository) DeleteByConversation(ctx context.Context, userID, conversationID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM orchestrations WHERE user_id = $1 AND conversation_id = $2`, userID, conversationID); err != nil {
+		return fmt.Errorf("delete orchestrations: %w", err)
+	}
+	return nil
+}
+
+func scanOrchestrations(rows *sql.Rows) ([]domain.Orchestration, error) {
 	defer func() { _ = rows.Close() }()
 
 	var result []domain.Orchestration
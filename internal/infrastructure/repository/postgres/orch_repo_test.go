@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestOrchestrationRepositoryGetNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewOrchestrationRepository(db)
+	mock.ExpectQuery("FROM orchestrations").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetByID(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrOrchestrationNotFound) {
+		t.Fatalf("expected ErrOrchestrationNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestOrchestrationRepositoryListByConversation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewOrchestrationRepository(db)
+	now := time.Now().UTC()
+	mock.ExpectQuery("WHERE user_id = \\$1 AND conversation_id = \\$2").
+		WithArgs("u-1", "c-1", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "conversation_id", "request", "plan", "steps", "status", "created_at", "completed_at"}).
+			AddRow("o-1", "u-1", "c-1", "q", []byte(`[{"id":"s1","agent":"researcher","task":"t"}]`),
+				[]byte(`[{"index":0,"step_id":"s1","agent":"researcher","task":"t","result":"r","status":"completed","started_at":"2026-01-01T00:00:00Z","duration_ms":5}]`),
+				"completed", now, now))
+
+	orchs, err := repo.ListByConversation(context.Background(), "u-1", "c-1", 10)
+	if err != nil {
+		t.Fatalf("ListByConversation() error = %v", err)
+	}
+	if len(orchs) != 1 || len(orchs[0].Plan) != 1 || orchs[0].Steps[0].StepID != "s1" || orchs[0].CompletedAt == nil {
+		t.Fatalf("unexpected orchestrations: %+v", orchs)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
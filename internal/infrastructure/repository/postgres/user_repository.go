@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// UserRepository implements ports.UserStore.
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	groupsJSON, err := marshalGroups(user.Groups)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+INSERT INTO users (id, name, is_admin, groups, created_at)
+VALUES ($1, $2, $3, $4, $5)
+`, user.ID, user.Name, user.Admin, groupsJSON, user.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, name, is_admin, groups, created_at
+FROM users
+WHERE id = $1
+`, id)
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrUserNotFound, "get user", fmt.Errorf("id=%s", id))
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *UserRepository) ListUsers(ctx context.Context) ([]domain.User, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, name, is_admin, groups, created_at
+FROM users
+ORDER BY created_at ASC
+`)
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) UpdateUserGroups(ctx context.Context, id string, groups []string) error {
+	groupsJSON, err := marshalGroups(groups)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET groups = $2 WHERE id = $1`, id, groupsJSON)
+	if err != nil {
+		return fmt.Errorf("update user groups: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for update user groups: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrUserNotFound, "update user groups", fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
+
+func (r *UserRepository) CreateAPIKey(ctx context.Context, key *domain.APIKey, keyHash string) error {
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO api_keys (id, user_id, name, prefix, key_hash, created_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`, key.ID, key.UserID, key.Name, key.Prefix, keyHash, key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("insert api key: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	row := r.db.QueryRowContext(ctx, `
+SELECT id, user_id, name, prefix, created_at, last_used_at, revoked_at
+FROM api_keys
+WHERE key_hash = $1 AND revoked_at IS NULL
+`, keyHash)
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrAPIKeyNotFound, "get api key by hash", errors.New("no active key"))
+		}
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *UserRepository) ListAPIKeys(ctx context.Context, userID string) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, user_id, name, prefix, created_at, last_used_at, revoked_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC
+`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	keys := make([]domain.APIKey, 0)
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *UserRepository) RevokeAPIKey(ctx context.Context, userID, keyID string) error {
+	result, err := r.db.ExecContext(ctx, `
+UPDATE api_keys
+SET revoked_at = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`, keyID, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("revoke api key: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for revoke api key: %w", err)
+	}
+	if rows == 0 {
+		return domain.WrapError(domain.ErrAPIKeyNotFound, "revoke api key", fmt.Errorf("id=%s", keyID))
+	}
+	return nil
+}
+
+func (r *UserRepository) TouchAPIKey(ctx context.Context, keyID string, usedAt time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, keyID, usedAt); err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
+
+func marshalGroups(groups []string) ([]byte, error) {
+	if groups == nil {
+		groups = []string{}
+	}
+	raw, err := json.Marshal(groups)
+	if err != nil {
+		return nil, fmt.Errorf("marshal groups: %w", err)
+	}
+	return raw, nil
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var (
+		user      domain.User
+		groupsRaw []byte
+	)
+	if err := row.Scan(&user.ID, &user.Name, &user.Admin, &groupsRaw, &user.CreatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan user: %w", err)
+	}
+	if err := json.Unmarshal(groupsRaw, &user.Groups); err != nil {
+		return nil, fmt.Errorf("unmarshal user groups: %w", err)
+	}
+	return &user, nil
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var (
+		key      domain.APIKey
+		lastUsed sql.NullTime
+		revoked  sql.NullTime
+	)
+	if err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.CreatedAt, &lastUsed, &revoked); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan api key: %w", err)
+	}
+	if lastUsed.Valid {
+		t := lastUsed.Time
+		key.LastUsedAt = &t
+	}
+	if revoked.Valid {
+		t := revoked.Time
+		key.RevokedAt = &t
+	}
+	return &key, nil
+}
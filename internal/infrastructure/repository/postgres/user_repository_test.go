@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func newUserRepoWithMock(t *testing.T) (*UserRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	return NewUserRepository(db), mock, func() { _ = db.Close() }
+}
+
+func TestGetAPIKeyByHashSkipsRevokedKeys(t *testing.T) {
+	repo, mock, done := newUserRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery(`WHERE key_hash = \$1 AND revoked_at IS NULL`).
+		WithArgs("hash").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetAPIKeyByHash(context.Background(), "hash")
+	if !domain.IsKind(err, domain.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetAPIKeyByHashScansNullableTimes(t *testing.T) {
+	repo, mock, done := newUserRepoWithMock(t)
+	defer done()
+
+	now := time.Now().UTC()
+	mock.ExpectQuery("FROM api_keys").
+		WithArgs("hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "prefix", "created_at", "last_used_at", "revoked_at"}).
+			AddRow("k-1", "u-1", "laptop", "paa_abcdefgh", now, now, nil))
+
+	key, err := repo.GetAPIKeyByHash(context.Background(), "hash")
+	if err != nil {
+		t.Fatalf("GetAPIKeyByHash() error = %v", err)
+	}
+	if key.UserID != "u-1" || key.LastUsedAt == nil || key.RevokedAt != nil {
+		t.Fatalf("unexpected key %+v", key)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestRevokeAPIKeyIsScopedToUser(t *testing.T) {
+	repo, mock, done := newUserRepoWithMock(t)
+	defer done()
+
+	mock.ExpectExec(`UPDATE api_keys\s+SET revoked_at = \$3\s+WHERE id = \$1 AND user_id = \$2`).
+		WithArgs("k-1", "u-2", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.RevokeAPIKey(context.Background(), "u-2", "k-1")
+	if !domain.IsKind(err, domain.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestGetUserReturnsDomainNotFound(t *testing.T) {
+	repo, mock, done := newUserRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("FROM users").
+		WithArgs("missing").
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetUser(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrUserNotFound) {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListUsersScansGroups(t *testing.T) {
+	repo, mock, done := newUserRepoWithMock(t)
+	defer done()
+
+	mock.ExpectQuery("SELECT id, name, is_admin, groups, created_at").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "is_admin", "groups", "created_at"}).
+			AddRow("u-1", "alice", false, []byte(`["team"]`), time.Now().UTC()))
+
+	users, err := repo.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if len(users) != 1 || len(users[0].Groups) != 1 || users[0].Groups[0] != "team" {
+		t.Fatalf("unexpected users %+v", users)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
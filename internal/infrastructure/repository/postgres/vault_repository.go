@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// vaultLockNamespace is the first key of the two-key advisory lock taken per
+// vault, keeping vault locks apart from other advisory locks in the database.
+const vaultLockNamespace int32 = 2026030401
+
+// VaultRepository implements ports.VaultStore and ports.VaultSyncStateStore.
+type VaultRepository struct {
+	db *sql.DB
+}
+
+func NewVaultRepository(db *sql.DB) *VaultRepository {
+	return &VaultRepository{db: db}
+}
+
+const vaultSelect = `
+SELECT v.id, v.name, v.path, v.enabled, v.interval_minutes, v.created_at, v.updated_at,
+	r.finished_at, COALESCE(r.status, ''),
+	CASE WHEN r.status = 'error' THEN COALESCE(r.errors->0->>'error', '') ELSE '' END
+FROM obsidian_vaults v
+LEFT JOIN LATERAL (
+	SELECT finished_at, status, errors
+	FROM obsidian_sync_runs
+	WHERE vault_id = v.id AND finished_at IS NOT NULL
+	ORDER BY started_at DESC
+	LIMIT 1
+) r ON true
+`
+
+func (r *VaultRepository) ListVaults(ctx context.Context) ([]domain.Vault, error) {
+	rows, err := r.db.QueryContext(ctx, vaultSelect+`ORDER BY v.name`)
+	if err != nil {
+		return nil, fmt.Errorf("list obsidian vaults: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	vaults := make([]domain.Vault, 0)
+	for rows.Next() {
+		vault, err := scanVault(rows)
+		if err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, *vault)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate obsidian vault rows: %w", err)
+	}
+	return vaults, nil
+}
+
+func (r *VaultRepository) GetVault(ctx context.Context, id string) (*domain.Vault, error) {
+	row := r.db.QueryRowContext(ctx, vaultSelect+`WHERE v.id = $1`, id)
+	vault, err := scanVault(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.WrapError(domain.ErrVaultNotFound, "get vault", fmt.Errorf("id=%s", id))
+		}
+		return nil, err
+	}
+	return vault, nil
+}
+
+func (r *VaultRepository) UpsertVault(ctx context.Context, vault *domain.Vault) error {
+	now := time.Now().UTC()
+	if vault.CreatedAt.IsZero() {
+		vault.CreatedAt = now
+	}
+	vault.UpdatedAt = now
+
+	var interval sql.NullInt32
+	if vault.IntervalMinutes != nil {
+		interval = sql.NullInt32{Int32: int32(*vault.IntervalMinutes), Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO obsidian_vaults (id, name, path, enabled, interval_minutes, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE SET
+	name = EXCLUDED.name,
+	path = EXCLUDED.path,
+	enabled = EXCLUDED.enabled,
+	interval_minutes = EXCLUDED.interval_minutes,
+	updated_at = EXCLUDED.updated_at
+`, vault.ID, vault.Name, vault.Path, vault.Enabled, interval, vault.CreatedAt, vault.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert obsidian vault: %w", err)
+	}
+	return nil
+}
+
+func (r *VaultRepository) DeleteVault(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM obsidian_vaults WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete obsidian vault: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("rows affected for delete obsidian vault: %w", err)
+	}
+	if affected == 0 {
+		return domain.WrapError(domain.ErrVaultNotFound, "delete vault", fmt.Errorf("id=%s", id))
+	}
+	return nil
+}
+
+func (r *VaultRepository) ListFileStates(ctx context.Context, vaultID string) ([]domain.VaultFileState, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT vault_id, rel_path, hash, document_id
+FROM obsidian_file_states
+WHERE vault_id = $1
+ORDER BY rel_path
+`, vaultID)
+	if err != nil {
+		return nil, fmt.Errorf("list obsidian file states: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	states := make([]domain.VaultFileState, 0)
+	for rows.Next() {
+		var st domain.VaultFileState
+		if err := rows.Scan(&st.VaultID, &st.RelPath, &st.Hash, &st.DocumentID); err != nil {
+			return nil, fmt.Errorf("scan obsidian file state: %w", err)
+		}
+		states = append(states, st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate obsidian file state rows: %w", err)
+	}
+	return states, nil
+}
+
+func (r *VaultRepository) UpsertFileStates(ctx context.Context, states []domain.VaultFileState) error {
+	if len(states) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin file states tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	for _, st := range states {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO obsidian_file_states (vault_id, rel_path, hash, document_id, updated_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (vault_id, rel_path) DO UPDATE SET
+	hash = EXCLUDED.hash,
+	document_id = EXCLUDED.document_id,
+	updated_at = EXCLUDED.updated_at
+`, st.VaultID, st.RelPath, st.Hash, st.DocumentID, now); err != nil {
+			return fmt.Errorf("upsert obsidian file state %s: %w", st.RelPath, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit file states tx: %w", err)
+	}
+	return nil
+}
+
+func (r *VaultRepository) DeleteFileStates(ctx context.Context, vaultID string, relPaths []string) error {
+	if len(relPaths) == 0 {
+		return nil
+	}
+	args := []any{vaultID}
+	placeholders := make([]string, 0, len(relPaths))
+	for _, rel := range relPaths {
+		args = append(args, rel)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+	query := `DELETE FROM obsidian_file_states WHERE vault_id = $1 AND rel_path IN (` + strings.Join(placeholders, ", ") + `)`
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("delete obsidian file states: %w", err)
+	}
+	return nil
+}
+
+// TryLock takes a session-level advisory lock on a dedicated connection. The
+// lock is released by unlock, or by Postgres itself if the process dies.
+func (r *VaultRepository) TryLock(ctx context.Context, vaultID string) (func(), bool, error) {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("acquire connection for vault lock: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1, hashtext($2))`, vaultLockNamespace, vaultID).Scan(&locked); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("try vault lock: %w", err)
+	}
+	if !locked {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.ExecContext(unlockCtx, `SELECT pg_advisory_unlock($1, hashtext($2))`, vaultLockNamespace, vaultID); err != nil {
+			slog.Warn("vault_unlock_failed", "vault_id", vaultID, "error", err)
+			// Discard the session so the lock cannot leak back into the pool.
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		_ = conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func (r *VaultRepository) CreateSyncRun(ctx context.Context, run *domain.VaultSyncRun) error {
+	if run.ID == "" {
+		run.ID = uuid.NewString()
+	}
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now().UTC()
+	}
+	if run.Status == "" {
+		run.Status = domain.VaultSyncStatusRunning
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO obsidian_sync_runs (id, vault_id, triggered_by, status, started_at)
+VALUES ($1, $2, $3, $4, $5)
+`, run.ID, run.VaultID, string(run.Trigger), run.Status, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("insert obsidian sync run: %w", err)
+	}
+	return nil
+}
+
+func (r *VaultRepository) FinishSyncRun(ctx context.Context, run *domain.VaultSyncRun) error {
+	if run.FinishedAt == nil {
+		now := time.Now().UTC()
+		run.FinishedAt = &now
+	}
+	syncErrors := run.Errors
+	if syncErrors == nil {
+		syncErrors = []domain.VaultSyncError{}
+	}
+	errorsJSON, err := json.Marshal(syncErrors)
+	if err != nil {
+		return fmt.Errorf("marshal sync errors: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+UPDATE obsidian_sync_runs
+SET status = $2, uploaded = $3, skipped = $4, removed = $5, failed = $6, errors = $7, finished_at = $8
+WHERE id = $1
+`, run.ID, run.Status, run.Uploaded, run.Skipped, run.Removed, run.Failed, errorsJSON, *run.FinishedAt)
+	if err != nil {
+		return fmt.Errorf("finish obsidian sync run: %w", err)
+	}
+	return nil
+}
+
+func (r *VaultRepository) ListSyncRuns(ctx context.Context, vaultID string, limit int) ([]domain.VaultSyncRun, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, vault_id, triggered_by, status, uploaded, skipped, removed, failed, errors, started_at, finished_at
+FROM obsidian_sync_runs
+WHERE vault_id = $1
+ORDER BY started_at DESC
+LIMIT $2
+`, vaultID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list obsidian sync runs: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	runs := make([]domain.VaultSyncRun, 0)
+	for rows.Next() {
+		var (
+			run        domain.VaultSyncRun
+			trigger    string
+			errorsJSON []byte
+			finishedAt sql.NullTime
+		)
+		if err := rows.Scan(
+			&run.ID, &run.VaultID, &trigger, &run.Status,
+			&run.Uploaded, &run.Skipped, &run.Removed, &run.Failed,
+			&errorsJSON, &run.StartedAt, &finishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan obsidian sync run: %w", err)
+		}
+		run.Trigger = domain.VaultSyncTrigger(trigger)
+		if len(errorsJSON) > 0 {
+			if err := json.Unmarshal(errorsJSON, &run.Errors); err != nil {
+				return nil, fmt.Errorf("unmarshal sync errors: %w", err)
+			}
+		}
+		if finishedAt.Valid {
+			t := finishedAt.Time
+			run.FinishedAt = &t
+		}
+		runs = append(runs, run)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate obsidian sync run rows: %w", err)
+	}
+	return runs, nil
+}
+
+func scanVault(row rowScanner) (*domain.Vault, error) {
+	var (
+		vault      domain.Vault
+		interval   sql.NullInt32
+		lastSyncAt sql.NullTime
+	)
+	if err := row.Scan(
+		&vault.ID, &vault.Name, &vault.Path, &vault.Enabled, &interval, &vault.CreatedAt, &vault.UpdatedAt,
+		&lastSyncAt, &vault.LastStatus, &vault.LastError,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("scan obsidian vault: %w", err)
+	}
+	if interval.Valid {
+		minutes := int(interval.Int32)
+		vault.IntervalMinutes = &minutes
+	}
+	if lastSyncAt.Valid {
+		t := lastSyncAt.Time
+		vault.LastSyncAt = &t
+	}
+	return &vault, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func TestVaultRepositoryGetVaultNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewVaultRepository(db)
+	mock.ExpectQuery("FROM obsidian_vaults").
+		WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err = repo.GetVault(context.Background(), "missing")
+	if !domain.IsKind(err, domain.ErrVaultNotFound) {
+		t.Fatalf("expected ErrVaultNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestVaultRepositoryTryLockNotAcquired(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewVaultRepository(db)
+	mock.ExpectQuery("pg_try_advisory_lock").
+		WithArgs(vaultLockNamespace, "main").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(false))
+
+	unlock, ok, err := repo.TryLock(context.Background(), "main")
+	if err != nil {
+		t.Fatalf("TryLock() error = %v", err)
+	}
+	if ok || unlock != nil {
+		t.Fatalf("expected lock not acquired")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestVaultRepositoryTryLockReleasesOnUnlock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewVaultRepository(db)
+	mock.ExpectQuery("pg_try_advisory_lock").
+		WithArgs(vaultLockNamespace, "main").
+		WillReturnRows(sqlmock.NewRows([]string{"locked"}).AddRow(true))
+	mock.ExpectExec("pg_advisory_unlock").
+		WithArgs(vaultLockNamespace, "main").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, ok, err := repo.TryLock(context.Background(), "main")
+	if err != nil || !ok {
+		t.Fatalf("TryLock() = %v, %v; want acquired", ok, err)
+	}
+	unlock()
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestVaultRepositoryFinishSyncRunStoresCountsAndErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New() error = %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	repo := NewVaultRepository(db)
+	finished := time.Now().UTC()
+	mock.ExpectExec("UPDATE obsidian_sync_runs").
+		WithArgs("run-1", domain.VaultSyncStatusPartial, 2, 3, 1, 1, []byte(`[{"file":"a.md","error":"boom"}]`), finished).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.FinishSyncRun(context.Background(), &domain.VaultSyncRun{
+		ID:         "run-1",
+		Status:     domain.VaultSyncStatusPartial,
+		Uploaded:   2,
+		Skipped:    3,
+		Removed:    1,
+		Failed:     1,
+		Errors:     []domain.VaultSyncError{{File: "a.md", Error: "boom"}},
+		FinishedAt: &finished,
+	})
+	if err != nil {
+		t.Fatalf("FinishSyncRun() error = %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
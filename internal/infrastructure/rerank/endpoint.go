@@ -0,0 +1,169 @@
+package rerank
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// Endpoint API dialects.
+const (
+	// APICohere is the Cohere/Jina request shape, also served by llama.cpp
+	// (--reranking), vLLM and Infinity: {model, query, documents, top_n} ->
+	// {results: [{index, relevance_score}]}.
+	APICohere = "cohere"
+	// APITEI is Hugging Face text-embeddings-inference: {query, texts} ->
+	// [{index, score}].
+	APITEI = "tei"
+)
+
+// EndpointReranker scores all candidates with a single request to a
+// dedicated cross-encoder rerank endpoint.
+type EndpointReranker struct {
+	url        string
+	apiKey     string
+	model      string
+	api        string
+	scoreMode  string
+	httpClient *http.Client
+}
+
+// NewEndpointReranker builds a reranker for baseURL. A URL that already ends
+// in /rerank is used as is; otherwise /v1/rerank (cohere) or /rerank (tei)
+// is appended. scoreMode is ScoreLogit or ScoreProbability; empty picks the
+// dialect's default: raw logits for tei, probabilities for cohere.
+func NewEndpointReranker(baseURL, apiKey, model, api, scoreMode string) *EndpointReranker {
+	api = strings.ToLower(strings.TrimSpace(api))
+	if api != APITEI {
+		api = APICohere
+	}
+	scoreMode = strings.ToLower(strings.TrimSpace(scoreMode))
+	if scoreMode != ScoreLogit && scoreMode != ScoreProbability {
+		scoreMode = ScoreProbability
+		if api == APITEI {
+			scoreMode = ScoreLogit
+		}
+	}
+	url := strings.TrimRight(baseURL, "/")
+	if !strings.HasSuffix(url, "/rerank") {
+		if api == APITEI {
+			url += "/rerank"
+		} else {
+			url += "/v1/rerank"
+		}
+	}
+	return &EndpointReranker{
+		url:        url,
+		apiKey:     apiKey,
+		model:      model,
+		api:        api,
+		scoreMode:  scoreMode,
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+type endpointResult struct {
+	Index          int      `json:"index"`
+	RelevanceScore *float64 `json:"relevance_score"`
+	Score          *float64 `json:"score"`
+}
+
+func (r *EndpointReranker) Rerank(ctx context.Context, query string, chunks []domain.RetrievedChunk, topN int) ([]domain.RetrievedChunk, error) {
+	if len(chunks) == 0 {
+		return chunks, nil
+	}
+	topN = clampTopN(topN, len(chunks))
+
+	passages := make([]string, topN)
+	for i := range passages {
+		passages[i] = truncatePassage(chunks[i].Text)
+	}
+	var reqBody map[string]any
+	if r.api == APITEI {
+		reqBody = map[string]any{"query": query, "texts": passages, "truncate": true, "raw_scores": r.scoreMode == ScoreLogit}
+	} else {
+		reqBody = map[string]any{"query": query, "documents": passages, "top_n": topN}
+		if r.model != "" {
+			reqBody["model"] = r.model
+		}
+	}
+
+	results, err := r.post(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make([]float64, topN)
+	seen := make([]bool, topN)
+	for _, res := range results {
+		if res.Index < 0 || res.Index >= topN {
+			return nil, fmt.Errorf("rerank endpoint: result index %d out of range", res.Index)
+		}
+		switch {
+		case res.RelevanceScore != nil:
+			raw[res.Index] = *res.RelevanceScore
+		case res.Score != nil:
+			raw[res.Index] = *res.Score
+		default:
+			return nil, fmt.Errorf("rerank endpoint: result %d has no score", res.Index)
+		}
+		seen[res.Index] = true
+	}
+	for i, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("rerank endpoint: no score for candidate %d", i)
+		}
+	}
+	return applyScores(chunks, topN, calibrate(raw, r.scoreMode)), nil
+}
+
+func (r *EndpointReranker) post(ctx context.Context, reqBody map[string]any) ([]endpointResult, error) {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal rerank request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create rerank request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rerank request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		if msg := strings.TrimSpace(string(respBody)); msg != "" {
+			return nil, fmt.Errorf("rerank endpoint status: %s: %s", resp.Status, msg)
+		}
+		return nil, fmt.Errorf("rerank endpoint status: %s", resp.Status)
+	}
+
+	if r.api == APITEI {
+		var results []endpointResult
+		if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+			return nil, fmt.Errorf("decode rerank response: %w", err)
+		}
+		return results, nil
+	}
+	var envelope struct {
+		Results []endpointResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("decode rerank response: %w", err)
+	}
+	return envelope.Results, nil
+}
@@ -0,0 +1,117 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+func testChunks() []domain.RetrievedChunk {
+	return []domain.RetrievedChunk{
+		{ChunkIndex: 0, Text: "chunk A", Score: 0.9},
+		{ChunkIndex: 1, Text: "chunk B", Score: 0.8},
+		{ChunkIndex: 2, Text: "chunk C", Score: 0.7},
+	}
+}
+
+func TestEndpointRerankerCohereSingleRequest(t *testing.T) {
+	calls := 0
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/v1/rerank" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s auth=%q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"results":[{"index":1,"relevance_score":0.92},{"index":0,"relevance_score":0.15}]}`))
+	}))
+	defer server.Close()
+
+	rr := NewEndpointReranker(server.URL, "key", "jina-reranker-v2", APICohere, "")
+	out, err := rr.Rerank(context.Background(), "q", testChunks(), 2)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected one request, got %d", calls)
+	}
+	if got["model"] != "jina-reranker-v2" || len(got["documents"].([]any)) != 2 {
+		t.Fatalf("unexpected request body: %v", got)
+	}
+	if out[0].ChunkIndex != 1 || out[0].Score != 0.92 || out[1].ChunkIndex != 0 || out[2].ChunkIndex != 2 {
+		t.Fatalf("unexpected order: %+v", out)
+	}
+}
+
+func TestEndpointRerankerTEICalibratesLogits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rerank" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`[{"index":2,"score":4.5},{"index":0,"score":-3.1},{"index":1,"score":0.2}]`))
+	}))
+	defer server.Close()
+
+	out, err := NewEndpointReranker(server.URL, "", "", APITEI, "").Rerank(context.Background(), "q", testChunks(), 0)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if out[0].ChunkIndex != 2 || out[2].ChunkIndex != 0 {
+		t.Fatalf("unexpected order: %+v", out)
+	}
+	for _, c := range out {
+		if c.Score < 0 || c.Score > 1 {
+			t.Fatalf("score %f not calibrated to [0,1]", c.Score)
+		}
+	}
+}
+
+func TestEndpointRerankerCalibratesEveryBatchAlike(t *testing.T) {
+	batches := []string{
+		`[{"index":0,"score":0.5},{"index":1,"score":0.2},{"index":2,"score":0.1}]`,
+		`[{"index":0,"score":0.5},{"index":1,"score":3.0},{"index":2,"score":-2.0}]`,
+	}
+	call := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["raw_scores"] != true {
+			t.Errorf("logit mode must ask for raw scores, got %v", body["raw_scores"])
+		}
+		_, _ = w.Write([]byte(batches[call]))
+		call++
+	}))
+	defer server.Close()
+
+	rr := NewEndpointReranker(server.URL, "", "", APITEI, ScoreLogit)
+	var scores []float64
+	for range batches {
+		out, err := rr.Rerank(context.Background(), "q", testChunks(), 0)
+		if err != nil {
+			t.Fatalf("Rerank() error = %v", err)
+		}
+		for _, c := range out {
+			if c.ChunkIndex == 0 {
+				scores = append(scores, c.Score)
+			}
+		}
+	}
+	if len(scores) != 2 || scores[0] != scores[1] || scores[0] == 0.5 {
+		t.Fatalf("the same raw score must calibrate the same in every batch, got %v", scores)
+	}
+}
+
+func TestEndpointRerankerMissingScoreIsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"results":[{"index":0,"relevance_score":0.5}]}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewEndpointReranker(server.URL+"/rerank", "", "", APICohere, "").Rerank(context.Background(), "q", testChunks(), 2); err == nil {
+		t.Fatalf("expected error for incomplete results")
+	}
+}
@@ -0,0 +1,78 @@
+package rerank
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// JSONGenerator is the slice of ports.AnswerGenerator the listwise reranker
+// needs.
+type JSONGenerator interface {
+	GenerateJSONFromPrompt(ctx context.Context, prompt string) (string, error)
+}
+
+// ListwiseReranker scores every candidate in one LLM prompt instead of one
+// call per chunk.
+type ListwiseReranker struct {
+	generator JSONGenerator
+}
+
+func NewListwiseReranker(generator JSONGenerator) *ListwiseReranker {
+	return &ListwiseReranker{generator: generator}
+}
+
+func (r *ListwiseReranker) Rerank(ctx context.Context, query string, chunks []domain.RetrievedChunk, topN int) ([]domain.RetrievedChunk, error) {
+	if len(chunks) == 0 {
+		return chunks, nil
+	}
+	topN = clampTopN(topN, len(chunks))
+
+	raw, err := r.generator.GenerateJSONFromPrompt(ctx, buildListwisePrompt(query, chunks[:topN]))
+	if err != nil {
+		return nil, err
+	}
+	scores, err := parseListwiseScores(raw, topN)
+	if err != nil {
+		return nil, err
+	}
+	return applyScores(chunks, topN, scores), nil
+}
+
+func buildListwisePrompt(query string, chunks []domain.RetrievedChunk) string {
+	var sb strings.Builder
+	sb.WriteString("Rate the relevance of each numbered document chunk to the query on a scale from 0 to 10.\n")
+	fmt.Fprintf(&sb, "Return ONLY a JSON object with exactly %d scores in passage order: {\"scores\": [<number>, ...]}\n\n", len(chunks))
+	fmt.Fprintf(&sb, "Query: %s\n", query)
+	for i, chunk := range chunks {
+		fmt.Fprintf(&sb, "\n[%d] (file: %s)\n%s\n", i+1, chunk.Filename, truncatePassage(chunk.Text))
+	}
+	return sb.String()
+}
+
+// parseListwiseScores reads {"scores": [...]} on the 0..10 scale and
+// normalizes it to 0..1.
+func parseListwiseScores(raw string, n int) ([]float64, error) {
+	start := strings.Index(raw, "{")
+	end := strings.LastIndex(raw, "}")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("parse listwise scores: no JSON object in response")
+	}
+	var result struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &result); err != nil {
+		return nil, fmt.Errorf("parse listwise scores: %w", err)
+	}
+	if len(result.Scores) != n {
+		return nil, fmt.Errorf("parse listwise scores: expected %d scores, got %d", n, len(result.Scores))
+	}
+	scores := make([]float64, n)
+	for i, s := range result.Scores {
+		scores[i] = min(max(s/10, 0), 1)
+	}
+	return scores, nil
+}
@@ -0,0 +1,82 @@
+package rerank
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeJSONGenerator struct {
+	calls  int
+	prompt string
+	reply  string
+	err    error
+	delay  time.Duration
+}
+
+func (f *fakeJSONGenerator) GenerateJSONFromPrompt(ctx context.Context, prompt string) (string, error) {
+	f.calls++
+	f.prompt = prompt
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	return f.reply, f.err
+}
+
+func TestListwiseRerankerScoresAllCandidatesInOneCall(t *testing.T) {
+	gen := &fakeJSONGenerator{reply: "Sure: {\"scores\": [2, 9, 5]}"}
+	out, err := NewListwiseReranker(gen).Rerank(context.Background(), "q", testChunks(), 3)
+	if err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if gen.calls != 1 {
+		t.Fatalf("expected one LLM call, got %d", gen.calls)
+	}
+	if !strings.Contains(gen.prompt, "[3]") || !strings.Contains(gen.prompt, "chunk C") {
+		t.Fatalf("prompt must list every candidate: %s", gen.prompt)
+	}
+	if out[0].ChunkIndex != 1 || out[0].Score != 0.9 || out[2].ChunkIndex != 0 {
+		t.Fatalf("unexpected order: %+v", out)
+	}
+}
+
+func TestListwiseRerankerRejectsWrongScoreCount(t *testing.T) {
+	gen := &fakeJSONGenerator{reply: `{"scores": [2, 9]}`}
+	if _, err := NewListwiseReranker(gen).Rerank(context.Background(), "q", testChunks(), 3); err == nil {
+		t.Fatalf("expected error for score count mismatch")
+	}
+}
+
+type orderReranker struct{ called bool }
+
+func (o *orderReranker) Rerank(_ context.Context, _ string, chunks []domain.RetrievedChunk, _ int) ([]domain.RetrievedChunk, error) {
+	o.called = true
+	return chunks, nil
+}
+
+func TestDegradingFallsBackOnErrorAndTimeout(t *testing.T) {
+	fallback := &orderReranker{}
+	failing := NewListwiseReranker(&fakeJSONGenerator{err: errors.New("boom")})
+	out, err := NewDegrading(failing, fallback, time.Second, nil).Rerank(context.Background(), "q", testChunks(), 3)
+	if err != nil || !fallback.called || len(out) != 3 {
+		t.Fatalf("expected fallback on error: err=%v called=%v", err, fallback.called)
+	}
+
+	fallback = &orderReranker{}
+	slow := NewListwiseReranker(&fakeJSONGenerator{reply: `{"scores":[1,2,3]}`, delay: time.Second})
+	started := time.Now()
+	if _, err := NewDegrading(slow, fallback, 20*time.Millisecond, nil).Rerank(context.Background(), "q", testChunks(), 3); err != nil {
+		t.Fatalf("Rerank() error = %v", err)
+	}
+	if !fallback.called || time.Since(started) > 500*time.Millisecond {
+		t.Fatalf("expected timeout to degrade quickly, called=%v elapsed=%s", fallback.called, time.Since(started))
+	}
+}
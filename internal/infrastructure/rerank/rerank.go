@@ -0,0 +1,123 @@
+// Package rerank implements ports.Reranker without one LLM call per chunk:
+// dedicated cross-encoder /rerank endpoints, a listwise LLM mode that scores
+// every candidate in one prompt, and a wrapper that bounds latency and
+// degrades to a cheaper reranker on failure.
+package rerank
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
+)
+
+// maxPassageChars bounds the text sent per candidate.
+const maxPassageChars = 1500
+
+// applyScores sets calibrated scores on the first topN chunks, sorts them by
+// score and keeps the remaining chunks after them in their original order.
+func applyScores(chunks []domain.RetrievedChunk, topN int, scores []float64) []domain.RetrievedChunk {
+	head := make([]domain.RetrievedChunk, topN)
+	copy(head, chunks[:topN])
+	for i := range head {
+		head[i].Score = scores[i]
+	}
+	sort.SliceStable(head, func(i, j int) bool {
+		return head[i].Score > head[j].Score
+	})
+	out := make([]domain.RetrievedChunk, 0, len(chunks))
+	out = append(out, head...)
+	return append(out, chunks[topN:]...)
+}
+
+// clampTopN bounds topN to the number of chunks; zero or less means all.
+func clampTopN(topN, n int) int {
+	if topN <= 0 || topN > n {
+		return n
+	}
+	return topN
+}
+
+// Score modes tell how a backend reports relevance. The mode is fixed per
+// backend, so a score means the same in every batch.
+const (
+	// ScoreLogit scores are unbounded logits and go through a sigmoid.
+	ScoreLogit = "logit"
+	// ScoreProbability scores are already in [0,1]; strays are clamped.
+	ScoreProbability = "probability"
+)
+
+// calibrate maps raw relevance scores to [0,1] according to mode so
+// reranked scores are comparable across backends.
+func calibrate(raw []float64, mode string) []float64 {
+	out := make([]float64, len(raw))
+	for i, s := range raw {
+		switch {
+		case math.IsNaN(s):
+			out[i] = 0
+		case mode == ScoreLogit:
+			out[i] = 1 / (1 + math.Exp(-s))
+		default:
+			out[i] = min(max(s, 0), 1)
+		}
+	}
+	return out
+}
+
+func truncatePassage(text string) string {
+	if len(text) <= maxPassageChars {
+		return text
+	}
+	// Cut on a rune boundary.
+	cut := maxPassageChars
+	for cut > 0 && text[cut]&0xC0 == 0x80 {
+		cut--
+	}
+	return text[:cut]
+}
+
+// Degrading bounds the primary reranker with a timeout and answers from
+// the fallback reranker when the primary fails or runs out of time.
+type Degrading struct {
+	primary  ports.Reranker
+	fallback ports.Reranker
+	timeout  time.Duration
+	logger   *slog.Logger
+}
+
+func NewDegrading(primary, fallback ports.Reranker, timeout time.Duration, logger *slog.Logger) *Degrading {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Degrading{primary: primary, fallback: fallback, timeout: timeout, logger: logger}
+}
+
+func (d *Degrading) Rerank(ctx context.Context, query string, chunks []domain.RetrievedChunk, topN int) ([]domain.RetrievedChunk, error) {
+	if len(chunks) == 0 {
+		return chunks, nil
+	}
+	callCtx := ctx
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	started := time.Now()
+	out, err := d.primary.Rerank(callCtx, query, chunks, topN)
+	if err == nil {
+		return out, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	d.logger.Warn("reranker_degraded",
+		"error", err,
+		"candidates", clampTopN(topN, len(chunks)),
+		"elapsed_ms", time.Since(started).Milliseconds(),
+	)
+	return d.fallback.Rerank(ctx, query, chunks, topN)
+}
@@ -3,11 +3,16 @@ package obsidian
 import (
 	"context"
 	"errors"
+	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 )
 
-// Adapter is a stub for Obsidian vault sync. Will be implemented when vault sync is built.
+// Adapter ingests notes from a registered Obsidian vault. Each note gets a
+// stable SourceID built from the vault ID and its vault-relative path, so
+// re-syncing an edited note updates the existing document.
 type Adapter struct{}
 
 func New() *Adapter {
@@ -16,6 +21,38 @@ func New() *Adapter {
 
 func (a *Adapter) SourceType() string { return "obsidian" }
 
-func (a *Adapter) Ingest(_ context.Context, _ domain.SourceRequest) (*domain.IngestResult, error) {
-	return nil, errors.New("obsidian source adapter not implemented")
+func (a *Adapter) Ingest(_ context.Context, req domain.SourceRequest) (*domain.IngestResult, error) {
+	if req.Body == nil {
+		return nil, errors.New("obsidian: body is required")
+	}
+	if strings.TrimSpace(req.VaultID) == "" {
+		return nil, errors.New("obsidian: vault id is required")
+	}
+	relPath := path.Clean(filepath.ToSlash(req.Path))
+	if relPath == "." || relPath == "" {
+		return nil, errors.New("obsidian: note path is required")
+	}
+
+	filename := req.Filename
+	if filename == "" {
+		filename = path.Base(relPath)
+	}
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = "text/markdown"
+	}
+
+	return &domain.IngestResult{
+		Filename:   filename,
+		MimeType:   mimeType,
+		Body:       req.Body,
+		SourceType: "obsidian",
+		Path:       relPath,
+		SourceID:   SourceID(req.VaultID, relPath),
+	}, nil
+}
+
+// SourceID returns the stable document identity for a note in a vault.
+func SourceID(vaultID, relPath string) string {
+	return "obsidian:" + vaultID + ":" + path.Clean(filepath.ToSlash(relPath))
 }
@@ -2,6 +2,7 @@ package obsidian
 
 import (
 	"context"
+	"io"
 	"strings"
 	"testing"
 
@@ -22,16 +23,44 @@ func TestSourceType(t *testing.T) {
 	}
 }
 
-func TestIngest_ReturnsNotImplemented(t *testing.T) {
+func TestIngest_BuildsStableSourceID(t *testing.T) {
 	a := New()
-	result, err := a.Ingest(context.Background(), domain.SourceRequest{})
-	if result != nil {
-		t.Fatalf("expected nil result, got %+v", result)
+	result, err := a.Ingest(context.Background(), domain.SourceRequest{
+		SourceType: "obsidian",
+		VaultID:    "main",
+		Path:       "projects/./plan.md",
+		Body:       strings.NewReader("# Plan"),
+	})
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
 	}
-	if err == nil {
-		t.Fatal("expected error")
+	if result.SourceID != "obsidian:main:projects/plan.md" {
+		t.Errorf("SourceID = %q", result.SourceID)
 	}
-	if !strings.Contains(err.Error(), "not implemented") {
-		t.Fatalf("expected 'not implemented' error, got: %v", err)
+	if result.Filename != "plan.md" {
+		t.Errorf("Filename = %q, want %q", result.Filename, "plan.md")
+	}
+	if result.Path != "projects/plan.md" {
+		t.Errorf("Path = %q, want %q", result.Path, "projects/plan.md")
+	}
+	if result.MimeType != "text/markdown" {
+		t.Errorf("MimeType = %q, want %q", result.MimeType, "text/markdown")
+	}
+	body, _ := io.ReadAll(result.Body)
+	if string(body) != "# Plan" {
+		t.Errorf("Body = %q", string(body))
+	}
+}
+
+func TestIngest_RequiresVaultAndPath(t *testing.T) {
+	a := New()
+	if _, err := a.Ingest(context.Background(), domain.SourceRequest{Path: "a.md", Body: strings.NewReader("")}); err == nil {
+		t.Fatal("expected error for missing vault id")
+	}
+	if _, err := a.Ingest(context.Background(), domain.SourceRequest{VaultID: "v", Body: strings.NewReader("")}); err == nil {
+		t.Fatal("expected error for missing path")
+	}
+	if _, err := a.Ingest(context.Background(), domain.SourceRequest{VaultID: "v", Path: "a.md"}); err == nil {
+		t.Fatal("expected error for missing body")
 	}
 }
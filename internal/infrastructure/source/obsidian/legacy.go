@@ -0,0 +1,90 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type legacyConfig struct {
+	Vaults []struct {
+		ID              string `json:"id"`
+		Name            string `json:"name"`
+		Path            string `json:"path"`
+		Enabled         bool   `json:"enabled"`
+		IntervalMinutes *int   `json:"interval_minutes,omitempty"`
+	} `json:"vaults"`
+}
+
+// LoadLegacyState reads the vault list and per-note sync state from the JSON
+// config and TSV state files used before vaults moved to Postgres. A missing
+// config file yields no vaults and no error.
+func LoadLegacyState(configPath, stateDir string) ([]domain.Vault, []domain.VaultFileState, error) {
+	if configPath == "" {
+		return nil, nil, nil
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read legacy obsidian config: %w", err)
+	}
+	var cfg legacyConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, nil, fmt.Errorf("parse legacy obsidian config: %w", err)
+	}
+
+	vaults := make([]domain.Vault, 0, len(cfg.Vaults))
+	var states []domain.VaultFileState
+	for _, v := range cfg.Vaults {
+		id := v.ID
+		if id == "" {
+			id = domain.VaultSlug(v.Name)
+		}
+		vaults = append(vaults, domain.Vault{
+			ID:              id,
+			Name:            v.Name,
+			Path:            v.Path,
+			Enabled:         v.Enabled,
+			IntervalMinutes: v.IntervalMinutes,
+		})
+		if stateDir == "" {
+			continue
+		}
+		rows, err := loadLegacyStateFile(filepath.Join(stateDir, id+".tsv"), id)
+		if err != nil {
+			return nil, nil, err
+		}
+		states = append(states, rows...)
+	}
+	return vaults, states, nil
+}
+
+func loadLegacyStateFile(path, vaultID string) ([]domain.VaultFileState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read legacy obsidian state: %w", err)
+	}
+	var rows []domain.VaultFileState
+	for line := range strings.SplitSeq(string(data), "\n") {
+		parts := strings.Split(line, "\t")
+		if len(parts) < 2 || parts[0] == "" {
+			continue
+		}
+		row := domain.VaultFileState{VaultID: vaultID, RelPath: parts[0], Hash: parts[1]}
+		if len(parts) > 2 {
+			row.DocumentID = parts[2]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,41 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadLegacyState(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "obsidian_vaults.json")
+	stateDir := filepath.Join(dir, "state")
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{"vaults":[{"name":"My Vault","path":"/vaults/my","enabled":true,"interval_minutes":5}],"default_interval_minutes":15}`
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(stateDir, "my_vault.tsv"), []byte("a.md\th1\tdoc-1\nb.md\th2\n\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	vaults, states, err := LoadLegacyState(configPath, stateDir)
+	if err != nil {
+		t.Fatalf("LoadLegacyState() error = %v", err)
+	}
+	if len(vaults) != 1 || vaults[0].ID != "my_vault" || *vaults[0].IntervalMinutes != 5 {
+		t.Fatalf("unexpected vaults: %+v", vaults)
+	}
+	if len(states) != 2 || states[0].DocumentID != "doc-1" || states[1].DocumentID != "" || states[1].VaultID != "my_vault" {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+}
+
+func TestLoadLegacyState_MissingConfig(t *testing.T) {
+	vaults, states, err := LoadLegacyState(filepath.Join(t.TempDir(), "missing.json"), "")
+	if err != nil || vaults != nil || states != nil {
+		t.Fatalf("expected empty result, got %v %v %v", vaults, states, err)
+	}
+}
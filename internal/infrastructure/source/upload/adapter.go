@@ -1,10 +1,14 @@
 package upload
 
 import (
+	"bufio"
 	"context"
 	"errors"
+	"fmt"
+	"io"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/mimetype"
 )
 
 type Adapter struct{}
@@ -15,15 +19,27 @@ func New() *Adapter {
 
 func (a *Adapter) SourceType() string { return "upload" }
 
+// Ingest passes the upload through, correcting the client's MIME type from
+// the file extension and the leading bytes of the body. Files expanded from
+// an archive or directory keep their relative path in req.Path.
 func (a *Adapter) Ingest(_ context.Context, req domain.SourceRequest) (*domain.IngestResult, error) {
 	if req.Body == nil {
 		return nil, errors.New("upload: body is required")
 	}
+	body := bufio.NewReaderSize(req.Body, mimetype.SniffLen)
+	head, err := body.Peek(mimetype.SniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("upload: read body: %w", err)
+	}
+	path := req.Path
+	if path == "" {
+		path = req.Filename
+	}
 	return &domain.IngestResult{
 		Filename:   req.Filename,
-		MimeType:   req.MimeType,
-		Body:       req.Body,
+		MimeType:   mimetype.Detect(req.Filename, req.MimeType, head),
+		Body:       body,
 		SourceType: "upload",
-		Path:       req.Filename,
+		Path:       path,
 	}, nil
 }
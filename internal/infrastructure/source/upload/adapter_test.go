@@ -44,6 +44,20 @@ func TestUploadAdapter_Ingest_Success(t *testing.T) {
 	}
 }
 
+func TestUploadAdapter_Ingest_KeepsArchivePath(t *testing.T) {
+	result, err := New().Ingest(context.Background(), domain.SourceRequest{
+		Filename: "setup.md",
+		Path:     "wiki/Team/setup.md",
+		Body:     bytes.NewBufferString("# Setup"),
+	})
+	if err != nil {
+		t.Fatalf("Ingest() error = %v", err)
+	}
+	if result.Path != "wiki/Team/setup.md" || result.Filename != "setup.md" {
+		t.Errorf("got path %q filename %q", result.Path, result.Filename)
+	}
+}
+
 func TestUploadAdapter_Ingest_NilBody(t *testing.T) {
 	a := New()
 	req := domain.SourceRequest{SourceType: "upload", Filename: "report.txt"}
@@ -52,3 +66,31 @@ func TestUploadAdapter_Ingest_NilBody(t *testing.T) {
 		t.Fatal("expected error for nil body")
 	}
 }
+
+func TestUploadAdapter_Ingest_CorrectsMimeType(t *testing.T) {
+	tests := []struct {
+		filename, declared, body, want string
+	}{
+		{"notes.rtf", "application/octet-stream", `{\rtf1 hi}`, "application/rtf"},
+		{"main.go", "application/octet-stream", "package main", "text/x-go"},
+		{"unnamed", "application/octet-stream", "<!DOCTYPE html><html></html>", "text/html"},
+		{"report.pdf", "application/pdf", "%PDF-1.7", "application/pdf"},
+	}
+	for _, tt := range tests {
+		result, err := New().Ingest(context.Background(), domain.SourceRequest{
+			Filename: tt.filename,
+			MimeType: tt.declared,
+			Body:     bytes.NewBufferString(tt.body),
+		})
+		if err != nil {
+			t.Fatalf("Ingest(%s) error = %v", tt.filename, err)
+		}
+		if result.MimeType != tt.want {
+			t.Errorf("Ingest(%s) MimeType = %q, want %q", tt.filename, result.MimeType, tt.want)
+		}
+		body, _ := io.ReadAll(result.Body)
+		if string(body) != tt.body {
+			t.Errorf("Ingest(%s) body = %q, want it unchanged", tt.filename, body)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/htmltext"
 )
 
 type Adapter struct {
@@ -58,7 +59,7 @@ func (a *Adapter) Ingest(ctx context.Context, req domain.SourceRequest) (*domain
 	mimeType := contentType
 
 	if isHTML(contentType) {
-		title, body := extractTextFromHTML(content)
+		title, body := htmltext.Extract(content)
 		content = body
 		if title != "" {
 			filename = title
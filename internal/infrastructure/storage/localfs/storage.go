@@ -44,3 +44,12 @@ func (s *Storage) Open(_ context.Context, key string) (io.ReadCloser, error) {
 	}
 	return f, nil
 }
+
+// Delete removes the stored object. Missing files are not an error.
+func (s *Storage) Delete(_ context.Context, key string) error {
+	path := filepath.Join(s.basePath, key)
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove file: %w", err)
+	}
+	return nil
+}
@@ -111,3 +111,22 @@ func TestSave_EmptyContent(t *testing.T) {
 		t.Fatalf("expected empty content, got %d bytes", len(data))
 	}
 }
+
+func TestDelete_RemovesFileAndIgnoresMissing(t *testing.T) {
+	s, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	ctx := context.Background()
+
+	_ = s.Save(ctx, "gone.txt", strings.NewReader("bye"))
+	if err := s.Delete(ctx, "gone.txt"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Open(ctx, "gone.txt"); err == nil {
+		t.Fatal("expected file to be removed")
+	}
+	if err := s.Delete(ctx, "gone.txt"); err != nil {
+		t.Fatalf("Delete() on missing file error = %v", err)
+	}
+}
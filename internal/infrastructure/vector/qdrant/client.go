@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -15,7 +16,9 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/resilience"
+	"github.com/kirillkom/personal-ai-assistant/internal/pkg/tokenizer"
 )
 
 const (
@@ -28,10 +31,16 @@ type Client struct {
 	collection string
 	httpClient *http.Client
 	executor   *resilience.Executor
+	stats      ports.LexicalStatsStore
 
 	ensureMu          sync.Mutex
 	ensuredCollection bool
 	ensuredVectorSize int
+	// serverIDF is set when the sparse vector has Qdrant's idf modifier, so
+	// queries must not be IDF-weighted again. idfKnown records that the
+	// collection schema has been inspected.
+	serverIDF bool
+	idfKnown  bool
 }
 
 func New(baseURL, collection string) *Client {
@@ -48,6 +57,8 @@ func NewWithOptions(baseURL, collection string, options Options) *Client {
 		collection: collection,
 		httpClient: httpClient,
 		executor:   options.ResilienceExecutor,
+		stats:      options.LexicalStats,
+		serverIDF:  options.LexicalStats == nil,
 	}
 }
 
@@ -112,12 +123,25 @@ func (c *Client) EnsureCollection(ctx context.Context, vectorSize int) error {
 }
 
 func (c *Client) IndexChunks(ctx context.Context, doc *domain.Document, chunks []string, vectors [][]float32) error {
+	return c.IndexAnnotatedChunks(ctx, doc, chunks, nil, vectors)
+}
+
+// IndexAnnotatedChunks stores each chunk's contextual prefix in the
+// "context" payload field and includes it in the chunk's sparse vector. The
+// chunk's locator is stored in the "page", "sheet" and "slide" fields.
+func (c *Client) IndexAnnotatedChunks(ctx context.Context, doc *domain.Document, chunks []string, annotations []domain.ChunkAnnotation, vectors [][]float32) error {
 	if len(chunks) == 0 || len(vectors) == 0 {
 		return nil
 	}
 	if len(chunks) != len(vectors) {
 		return fmt.Errorf("chunks/vectors mismatch")
 	}
+	if annotations == nil {
+		annotations = make([]domain.ChunkAnnotation, len(chunks))
+	}
+	if len(annotations) != len(chunks) {
+		return fmt.Errorf("chunks/annotations mismatch")
+	}
 
 	if err := c.ensureCollection(ctx, len(vectors[0])); err != nil {
 		return err
@@ -129,27 +153,41 @@ func (c *Client) IndexChunks(ctx context.Context, doc *domain.Document, chunks [
 		Payload map[string]any `json:"payload"`
 	}
 
+	lexDocs := make([]lexicalDoc, len(chunks))
+	for i := range chunks {
+		lexDocs[i] = analyzeLexicalDocument(contextualText(annotations[i].Context, chunks[i]), doc.Filename)
+	}
+	avgLen := c.avgDocLength(ctx, lexDocs)
+
 	points := make([]point, 0, len(chunks))
 	for i := range chunks {
-		sparse := encodeSparseDocument(chunks[i], doc.Filename)
+		sparse := lexDocs[i].encode(avgLen)
+		payload := map[string]any{
+			"doc_id":      doc.ID,
+			"filename":    doc.Filename,
+			"category":    doc.Category,
+			"subcategory": doc.Subcategory,
+			"chunk_index": i,
+			"text":        chunks[i],
+			"source_type": doc.SourceType,
+			"title":       doc.Title,
+			"path":        doc.Path,
+			"tags":        doc.Tags,
+			"owner_id":    doc.OwnerID,
+			"visibility":  string(doc.EffectiveVisibility()),
+			"acl":         doc.AccessTokens(),
+		}
+		if a := annotations[i]; a.Context != "" {
+			payload["context"] = a.Context
+		}
+		setLocatorPayload(payload, annotations[i].Locator)
 		points = append(points, point{
 			ID: uuid.NewString(),
 			Vector: map[string]any{
 				denseVectorName:  vectors[i],
 				sparseVectorName: sparse,
 			},
-			Payload: map[string]any{
-				"doc_id":      doc.ID,
-				"filename":    doc.Filename,
-				"category":    doc.Category,
-				"subcategory": doc.Subcategory,
-				"chunk_index": i,
-				"text":        chunks[i],
-				"source_type": doc.SourceType,
-				"title":       doc.Title,
-				"path":        doc.Path,
-				"tags":        doc.Tags,
-			},
+			Payload: payload,
 		})
 	}
 
@@ -173,22 +211,14 @@ func (c *Client) IndexChunks(ctx context.Context, doc *domain.Document, chunks [
 		}
 		return fmt.Errorf("qdrant upsert status: %s", resp.Status)
 	}
+	c.addLexicalStats(ctx, lexicalStatsDelta(lexDocs, 1))
 	return nil
 }
 
 func (c *Client) UpdateChunksPayload(ctx context.Context, docID string, sourceType string, payload map[string]any) error {
 	reqBody := map[string]any{
 		"payload": payload,
-		"filter": map[string]any{
-			"must": []map[string]any{
-				{
-					"key": "doc_id",
-					"match": map[string]any{
-						"value": docID,
-					},
-				},
-			},
-		},
+		"filter":  documentFilter(docID),
 	}
 
 	body, err := json.Marshal(reqBody)
@@ -213,6 +243,93 @@ func (c *Client) UpdateChunksPayload(ctx context.Context, docID string, sourceTy
 	return nil
 }
 
+// DeleteByDocumentID removes every point whose payload doc_id matches docID.
+// A missing collection is treated as already empty.
+func (c *Client) DeleteByDocumentID(ctx context.Context, docID string) error {
+	// The removed chunks' terms are read back first so they can be
+	// subtracted from the lexical statistics.
+	var removed []lexicalDoc
+	if c.stats != nil {
+		var err error
+		if removed, err = c.scrollLexicalDocs(ctx, docID); err != nil {
+			return err
+		}
+	}
+
+	reqBody := map[string]any{
+		"filter": documentFilter(docID),
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("marshal delete_points body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/delete?wait=true", c.baseURL, c.collection)
+	resp, err := c.doRequest(ctx, "delete_points", http.MethodPost, url, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		if msg := strings.TrimSpace(string(respBody)); msg != "" {
+			return fmt.Errorf("qdrant delete_points status: %s: %s", resp.Status, msg)
+		}
+		return fmt.Errorf("qdrant delete_points status: %s", resp.Status)
+	}
+	if len(removed) > 0 {
+		c.addLexicalStats(ctx, lexicalStatsDelta(removed, -1))
+	}
+	return nil
+}
+
+// ChunksByIndex returns the document's chunks with chunk_index in
+// [from, to], ordered by index. A missing collection has no chunks.
+func (c *Client) ChunksByIndex(ctx context.Context, docID string, _ string, from, to int) ([]domain.RetrievedChunk, error) {
+	if from < 0 {
+		from = 0
+	}
+	if to < from {
+		return nil, nil
+	}
+	filter := documentFilter(docID)
+	filter["must"] = append(filter["must"].([]map[string]any), map[string]any{
+		"key":   "chunk_index",
+		"range": map[string]any{"gte": from, "lte": to},
+	})
+	reqBody := map[string]any{
+		"filter":       filter,
+		"limit":        to - from + 1,
+		"with_payload": true,
+		"with_vector":  false,
+	}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("marshal scroll body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/scroll", c.baseURL, c.collection)
+	resp, err := c.doRequest(ctx, "scroll_points", http.MethodPost, url, body, "application/json")
+	if err != nil {
+		return nil, err
+	}
+	points, _, err := decodeScrollPage(resp)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.RetrievedChunk, 0, len(points))
+	for _, p := range points {
+		out = append(out, p.retrievedChunk())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChunkIndex < out[j].ChunkIndex })
+	return out, nil
+}
+
 func (c *Client) Search(
 	ctx context.Context,
 	queryVector []float32,
@@ -238,7 +355,8 @@ func (c *Client) SearchLexical(
 	limit int,
 	filter domain.SearchFilter,
 ) ([]domain.RetrievedChunk, error) {
-	sparse := encodeSparseQuery(queryText)
+	terms := tokenizer.Analyze(queryText)
+	sparse := encodeQueryTerms(terms, c.queryIDF(ctx, terms))
 	if len(sparse.Indices) == 0 {
 		return nil, nil
 	}
@@ -284,18 +402,46 @@ func (c *Client) queryPoints(ctx context.Context, reqBody map[string]any) ([]dom
 
 	out := make([]domain.RetrievedChunk, 0, len(points))
 	for _, r := range points {
-		out = append(out, domain.RetrievedChunk{
-			DocumentID: getStringPayload(r.Payload, "doc_id"),
-			Filename:   getStringPayload(r.Payload, "filename"),
-			Category:   getStringPayload(r.Payload, "category"),
-			ChunkIndex: getIntPayload(r.Payload, "chunk_index"),
-			Text:       getStringPayload(r.Payload, "text"),
-			Score:      r.Score,
-		})
+		out = append(out, r.retrievedChunk())
 	}
 	return out, nil
 }
 
+func (p queryPoint) retrievedChunk() domain.RetrievedChunk {
+	return domain.RetrievedChunk{
+		DocumentID: getStringPayload(p.Payload, "doc_id"),
+		Filename:   getStringPayload(p.Payload, "filename"),
+		Category:   getStringPayload(p.Payload, "category"),
+		Title:      getStringPayload(p.Payload, "title"),
+		Path:       getStringPayload(p.Payload, "path"),
+		SourceType: getStringPayload(p.Payload, "source_type"),
+		ChunkIndex: getIntPayload(p.Payload, "chunk_index"),
+		Text:       getStringPayload(p.Payload, "text"),
+		Score:      p.Score,
+		Locator:    locatorFromPayload(p.Payload),
+	}
+}
+
+func setLocatorPayload(payload map[string]any, l domain.Locator) {
+	if l.Page > 0 {
+		payload["page"] = l.Page
+	}
+	if l.Sheet != "" {
+		payload["sheet"] = l.Sheet
+	}
+	if l.Slide > 0 {
+		payload["slide"] = l.Slide
+	}
+}
+
+func locatorFromPayload(payload map[string]any) domain.Locator {
+	return domain.Locator{
+		Page:  max(getIntPayload(payload, "page"), 0),
+		Sheet: getStringPayload(payload, "sheet"),
+		Slide: max(getIntPayload(payload, "slide"), 0),
+	}
+}
+
 type queryPoint struct {
 	Score   float64        `json:"score"`
 	Payload map[string]any `json:"payload"`
@@ -362,6 +508,17 @@ func buildFilter(filter domain.SearchFilter) map[string]any {
 		})
 	}
 
+	// Points indexed before ACLs existed carry no acl and stay public, as
+	// they were; updating a document's ACL rewrites the payload.
+	if filter.AccessTokens != nil {
+		must = append(must, map[string]any{
+			"should": []map[string]any{
+				{"key": "acl", "match": map[string]any{"any": filter.AccessTokens}},
+				{"is_empty": map[string]any{"key": "acl"}},
+			},
+		})
+	}
+
 	if len(must) == 0 {
 		return nil
 	}
@@ -376,6 +533,12 @@ func (c *Client) ensureCollection(ctx context.Context, vectorSize int) error {
 	}
 	c.ensureMu.Unlock()
 
+	// With our own corpus statistics IDF is computed per query; otherwise
+	// Qdrant applies it.
+	sparseParams := map[string]any{}
+	if c.stats == nil {
+		sparseParams["modifier"] = "idf"
+	}
 	reqBody := map[string]any{
 		"vectors": map[string]any{
 			denseVectorName: map[string]any{
@@ -384,9 +547,7 @@ func (c *Client) ensureCollection(ctx context.Context, vectorSize int) error {
 			},
 		},
 		"sparse_vectors": map[string]any{
-			sparseVectorName: map[string]any{
-				"modifier": "idf",
-			},
+			sparseVectorName: sparseParams,
 		},
 	}
 
@@ -403,10 +564,11 @@ func (c *Client) ensureCollection(ctx context.Context, vectorSize int) error {
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode == http.StatusConflict {
-		if err := c.verifyCollectionSchema(ctx, vectorSize); err != nil {
+		serverIDF, err := c.verifyCollectionSchema(ctx, vectorSize)
+		if err != nil {
 			return err
 		}
-		c.markCollectionEnsured(vectorSize)
+		c.markCollectionEnsured(vectorSize, serverIDF)
 		return nil
 	}
 	if resp.StatusCode >= 300 {
@@ -416,24 +578,26 @@ func (c *Client) ensureCollection(ctx context.Context, vectorSize int) error {
 		}
 		return fmt.Errorf("qdrant ensure collection status: %s", resp.Status)
 	}
-	c.markCollectionEnsured(vectorSize)
+	c.markCollectionEnsured(vectorSize, c.stats == nil)
 	return nil
 }
 
-func (c *Client) verifyCollectionSchema(ctx context.Context, expectedVectorSize int) error {
+// verifyCollectionSchema checks an existing collection and reports whether
+// its sparse vector has Qdrant's idf modifier.
+func (c *Client) verifyCollectionSchema(ctx context.Context, expectedVectorSize int) (bool, error) {
 	url := fmt.Sprintf("%s/collections/%s", c.baseURL, c.collection)
 	resp, err := c.doRequest(ctx, "verify_collection", http.MethodGet, url, nil, "")
 	if err != nil {
-		return err
+		return false, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
 		if msg := strings.TrimSpace(string(body)); msg != "" {
-			return fmt.Errorf("verify collection status: %s: %s", resp.Status, msg)
+			return false, fmt.Errorf("verify collection status: %s: %s", resp.Status, msg)
 		}
-		return fmt.Errorf("verify collection status: %s", resp.Status)
+		return false, fmt.Errorf("verify collection status: %s", resp.Status)
 	}
 
 	var payload struct {
@@ -444,17 +608,13 @@ func (c *Client) verifyCollectionSchema(ctx context.Context, expectedVectorSize
 		} `json:"result"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return fmt.Errorf("decode verify collection response: %w", err)
+		return false, fmt.Errorf("decode verify collection response: %w", err)
 	}
 
 	if err := verifyDenseVectorConfig(payload.Result.Config.Params, expectedVectorSize); err != nil {
-		return err
+		return false, err
 	}
-	if err := verifySparseVectorConfig(payload.Result.Config.Params); err != nil {
-		return err
-	}
-
-	return nil
+	return verifySparseVectorConfig(payload.Result.Config.Params)
 }
 
 func verifyDenseVectorConfig(params map[string]any, expectedVectorSize int) error {
@@ -490,19 +650,24 @@ func verifyDenseVectorConfig(params map[string]any, expectedVectorSize int) erro
 	return nil
 }
 
-func verifySparseVectorConfig(params map[string]any) error {
+// verifySparseVectorConfig checks the sparse vector exists and reports
+// whether it has the idf modifier.
+func verifySparseVectorConfig(params map[string]any) (bool, error) {
 	sparseRaw, ok := params["sparse_vectors"]
 	if !ok {
-		return fmt.Errorf("qdrant collection is missing sparse_vectors config")
+		return false, fmt.Errorf("qdrant collection is missing sparse_vectors config")
 	}
 	sparse, ok := sparseRaw.(map[string]any)
 	if !ok {
-		return fmt.Errorf("qdrant sparse_vectors config has unexpected shape")
+		return false, fmt.Errorf("qdrant sparse_vectors config has unexpected shape")
 	}
-	if _, ok := sparse[sparseVectorName]; !ok {
-		return fmt.Errorf("qdrant collection is missing sparse vector %q", sparseVectorName)
+	textRaw, ok := sparse[sparseVectorName]
+	if !ok {
+		return false, fmt.Errorf("qdrant collection is missing sparse vector %q", sparseVectorName)
 	}
-	return nil
+	text, _ := textRaw.(map[string]any)
+	modifier, _ := text["modifier"].(string)
+	return strings.EqualFold(modifier, "idf"), nil
 }
 
 func asInt(v any) (int, bool) {
@@ -524,11 +689,13 @@ func asInt(v any) (int, bool) {
 	}
 }
 
-func (c *Client) markCollectionEnsured(vectorSize int) {
+func (c *Client) markCollectionEnsured(vectorSize int, serverIDF bool) {
 	c.ensureMu.Lock()
 	defer c.ensureMu.Unlock()
 	c.ensuredCollection = true
 	c.ensuredVectorSize = vectorSize
+	c.serverIDF = serverIDF
+	c.idfKnown = true
 }
 
 func getStringPayload(payload map[string]any, key string) string {
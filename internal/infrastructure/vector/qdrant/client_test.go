@@ -339,3 +339,87 @@ func TestSearchLexicalUsesSparseQueryAndVectorName(t *testing.T) {
 		t.Fatalf("expected sparse query values, got %#v", query)
 	}
 }
+
+func TestIndexChunksPayloadCarriesACL(t *testing.T) {
+	var upsertBody struct {
+		Points []struct {
+			Payload map[string]any `json:"payload"`
+		} `json:"points"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/collections/docs/points" {
+			_ = json.NewDecoder(r.Body).Decode(&upsertBody)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	doc := &domain.Document{
+		ID:         "doc-1",
+		OwnerID:    "alice",
+		Visibility: domain.VisibilityShared,
+		SharedWith: []string{"group:team"},
+	}
+	if err := New(server.URL, "docs").IndexChunks(context.Background(), doc, []string{"text"}, [][]float32{{0.1}}); err != nil {
+		t.Fatalf("IndexChunks() error = %v", err)
+	}
+	if len(upsertBody.Points) != 1 {
+		t.Fatalf("expected 1 point, got %d", len(upsertBody.Points))
+	}
+	payload := upsertBody.Points[0].Payload
+	acl, _ := payload["acl"].([]any)
+	if payload["owner_id"] != "alice" || payload["visibility"] != "shared" || len(acl) != 2 || acl[0] != "user:alice" || acl[1] != "group:team" {
+		t.Fatalf("unexpected ACL payload: %#v", payload)
+	}
+}
+
+func TestBuildFilterRestrictsByAccessTokens(t *testing.T) {
+	if f := buildFilter(domain.SearchFilter{}); f != nil {
+		t.Fatalf("expected no filter without criteria, got %#v", f)
+	}
+
+	f := buildFilter(domain.SearchFilter{AccessTokens: []string{"*", "user:alice"}})
+	must, ok := f["must"].([]map[string]any)
+	if !ok || len(must) != 1 {
+		t.Fatalf("expected a single must clause, got %#v", f)
+	}
+	should, ok := must[0]["should"].([]map[string]any)
+	if !ok || len(should) != 2 {
+		t.Fatalf("expected acl match OR legacy is_empty, got %#v", must[0])
+	}
+	if should[0]["key"] != "acl" {
+		t.Fatalf("expected acl match first, got %#v", should[0])
+	}
+}
+
+func TestChunksByIndexScrollsRangeOfDocument(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/collections/docs/points/scroll" {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_, _ = w.Write([]byte(`{"result":{"points":[
+			{"payload":{"doc_id":"doc-1","chunk_index":4,"text":"four","source_type":"obsidian"}},
+			{"payload":{"doc_id":"doc-1","chunk_index":3,"text":"three","source_type":"obsidian"}}
+		],"next_page_offset":null}}`))
+	}))
+	defer server.Close()
+
+	chunks, err := New(server.URL, "docs").ChunksByIndex(context.Background(), "doc-1", "obsidian", 3, 5)
+	if err != nil {
+		t.Fatalf("ChunksByIndex() error = %v", err)
+	}
+	if len(chunks) != 2 || chunks[0].ChunkIndex != 3 || chunks[1].Text != "four" || chunks[0].SourceType != "obsidian" {
+		t.Fatalf("unexpected chunks: %+v", chunks)
+	}
+	if body["limit"] != float64(3) {
+		t.Fatalf("expected limit 3, got %v", body["limit"])
+	}
+	must := body["filter"].(map[string]any)["must"].([]any)
+	rng := must[1].(map[string]any)
+	if rng["key"] != "chunk_index" || rng["range"].(map[string]any)["gte"] != float64(3) || rng["range"].(map[string]any)["lte"] != float64(5) {
+		t.Fatalf("unexpected range filter: %v", rng)
+	}
+}
@@ -0,0 +1,177 @@
+package qdrant
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+// scrollPageSize is how many points one scroll request reads.
+const scrollPageSize = 256
+
+// avgDocLength returns the average chunk length of the collection including
+// the chunks about to be indexed.
+func (c *Client) avgDocLength(ctx context.Context, batch []lexicalDoc) float64 {
+	if c.stats == nil {
+		return defaultAvgDocLength
+	}
+	var docs, total int64
+	if stats, err := c.stats.LexicalStats(ctx, c.collection, nil); err != nil {
+		slog.Warn("lexical_stats_read_failed", "collection", c.collection, "error", err)
+	} else {
+		docs, total = stats.Docs, stats.TotalLength
+	}
+	for _, doc := range batch {
+		docs++
+		total += int64(doc.length)
+	}
+	if docs == 0 || total == 0 {
+		return defaultAvgDocLength
+	}
+	return float64(total) / float64(docs)
+}
+
+// queryIDF returns BM25 IDF weights for terms from the collection
+// statistics, or nil when Qdrant applies IDF itself or statistics are
+// unavailable.
+func (c *Client) queryIDF(ctx context.Context, terms []string) func(string) float64 {
+	if c.stats == nil || len(terms) == 0 || c.usesServerIDF(ctx) {
+		return nil
+	}
+	stats, err := c.stats.LexicalStats(ctx, c.collection, terms)
+	if err != nil {
+		slog.Warn("lexical_stats_read_failed", "collection", c.collection, "error", err)
+		return nil
+	}
+	if stats.Docs == 0 {
+		return nil
+	}
+	return func(term string) float64 {
+		return bm25IDF(stats.Docs, stats.DocFreq[term])
+	}
+}
+
+// usesServerIDF reports whether the collection was created with Qdrant's
+// idf modifier. A search-only process has not ensured the collection yet, so
+// the schema is inspected once; if that fails, no IDF is applied.
+func (c *Client) usesServerIDF(ctx context.Context) bool {
+	c.ensureMu.Lock()
+	known, serverIDF := c.idfKnown, c.serverIDF
+	c.ensureMu.Unlock()
+	if known {
+		return serverIDF
+	}
+	serverIDF, err := c.verifyCollectionSchema(ctx, 0)
+	if err != nil {
+		return true
+	}
+	c.ensureMu.Lock()
+	c.serverIDF, c.idfKnown = serverIDF, true
+	c.ensureMu.Unlock()
+	return serverIDF
+}
+
+// addLexicalStats records indexed or deleted chunks. A failure only skews
+// term weights slightly, so it is logged instead of failing the write.
+func (c *Client) addLexicalStats(ctx context.Context, delta domain.LexicalStats) {
+	if c.stats == nil || delta.Docs == 0 {
+		return
+	}
+	if err := c.stats.AddLexicalStats(ctx, c.collection, delta); err != nil {
+		slog.Warn("lexical_stats_update_failed", "collection", c.collection, "docs", delta.Docs, "error", err)
+	}
+}
+
+// scrollLexicalDocs re-analyzes the stored chunks of docID. A missing
+// collection has no chunks.
+func (c *Client) scrollLexicalDocs(ctx context.Context, docID string) ([]lexicalDoc, error) {
+	var (
+		docs   []lexicalDoc
+		offset any
+	)
+	for {
+		reqBody := map[string]any{
+			"filter":       documentFilter(docID),
+			"limit":        scrollPageSize,
+			"with_payload": []string{"text", "context", "filename"},
+			"with_vector":  false,
+		}
+		if offset != nil {
+			reqBody["offset"] = offset
+		}
+		body, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("marshal scroll body: %w", err)
+		}
+
+		url := fmt.Sprintf("%s/collections/%s/points/scroll", c.baseURL, c.collection)
+		resp, err := c.doRequest(ctx, "scroll_points", http.MethodPost, url, body, "application/json")
+		if err != nil {
+			return nil, err
+		}
+		page, next, err := decodeScrollPage(resp)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page {
+			text := contextualText(getStringPayload(p.Payload, "context"), getStringPayload(p.Payload, "text"))
+			docs = append(docs, analyzeLexicalDocument(text, getStringPayload(p.Payload, "filename")))
+		}
+		if next == nil || len(page) == 0 {
+			return docs, nil
+		}
+		offset = next
+	}
+}
+
+func decodeScrollPage(resp *http.Response) ([]queryPoint, any, error) {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, nil
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		if msg := strings.TrimSpace(string(body)); msg != "" {
+			return nil, nil, fmt.Errorf("qdrant scroll status: %s: %s", resp.Status, msg)
+		}
+		return nil, nil, fmt.Errorf("qdrant scroll status: %s", resp.Status)
+	}
+	var envelope struct {
+		Result struct {
+			Points         []queryPoint `json:"points"`
+			NextPageOffset any          `json:"next_page_offset"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, nil, fmt.Errorf("decode scroll response: %w", err)
+	}
+	return envelope.Result.Points, envelope.Result.NextPageOffset, nil
+}
+
+// contextualText is the text a chunk is lexically indexed with: its
+// contextual prefix, if any, followed by the chunk.
+func contextualText(prefix, text string) string {
+	if prefix == "" {
+		return text
+	}
+	return prefix + "\n\n" + text
+}
+
+func documentFilter(docID string) map[string]any {
+	return map[string]any{
+		"must": []map[string]any{
+			{
+				"key": "doc_id",
+				"match": map[string]any{
+					"value": docID,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,175 @@
+package qdrant
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+)
+
+type fakeLexicalStats struct {
+	mu     sync.Mutex
+	totals domain.LexicalStats
+	df     map[string]int64
+}
+
+func (f *fakeLexicalStats) AddLexicalStats(_ context.Context, _ string, delta domain.LexicalStats) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.df == nil {
+		f.df = make(map[string]int64)
+	}
+	f.totals.Docs += delta.Docs
+	f.totals.TotalLength += delta.TotalLength
+	for term, n := range delta.DocFreq {
+		f.df[term] += n
+		if f.df[term] <= 0 {
+			delete(f.df, term)
+		}
+	}
+	return nil
+}
+
+func (f *fakeLexicalStats) LexicalStats(_ context.Context, _ string, terms []string) (*domain.LexicalStats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &domain.LexicalStats{Docs: f.totals.Docs, TotalLength: f.totals.TotalLength, DocFreq: map[string]int64{}}
+	for _, term := range terms {
+		if n, ok := f.df[term]; ok {
+			out.DocFreq[term] = n
+		}
+	}
+	return out, nil
+}
+
+func TestLexicalStatsFollowIndexAndDelete(t *testing.T) {
+	var createBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs":
+			_ = json.NewDecoder(r.Body).Decode(&createBody)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs/points":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/scroll":
+			_, _ = w.Write([]byte(`{"result":{"points":[
+				{"payload":{"doc_id":"doc-1","filename":"a.txt","text":"invoice payment"}},
+				{"payload":{"doc_id":"doc-1","filename":"a.txt","text":"invoice"}}
+			],"next_page_offset":null}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/delete":
+			_, _ = w.Write([]byte(`{"result":{"status":"completed"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	stats := &fakeLexicalStats{}
+	client := NewWithOptions(server.URL, "docs", Options{LexicalStats: stats})
+	doc := &domain.Document{ID: "doc-1", Filename: "a.txt"}
+	err := client.IndexChunks(context.Background(), doc, []string{"invoice payment", "invoice"}, [][]float32{{0.1}, {0.2}})
+	if err != nil {
+		t.Fatalf("IndexChunks() error = %v", err)
+	}
+
+	sparse := createBody["sparse_vectors"].(map[string]any)[sparseVectorName].(map[string]any)
+	if _, ok := sparse["modifier"]; ok {
+		t.Fatalf("collection with own statistics must not use the idf modifier: %#v", sparse)
+	}
+	if stats.totals.Docs != 2 || stats.df["invoice"] != 2 || stats.df["payment"] != 1 {
+		t.Fatalf("unexpected stats after index: %+v %v", stats.totals, stats.df)
+	}
+	if idf := client.queryIDF(context.Background(), []string{"invoice"}); idf == nil {
+		t.Fatalf("expected client-side idf with statistics")
+	}
+
+	if err := client.DeleteByDocumentID(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("DeleteByDocumentID() error = %v", err)
+	}
+	if stats.totals.Docs != 0 || stats.totals.TotalLength != 0 || len(stats.df) != 0 {
+		t.Fatalf("expected empty stats after delete, got %+v %v", stats.totals, stats.df)
+	}
+}
+
+func TestQueryIDFSkippedForServerIDFCollection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == "/collections/docs" {
+			_, _ = w.Write([]byte(`{"result":{"config":{"params":{
+				"vectors":{"dense":{"size":2,"distance":"Cosine"}},
+				"sparse_vectors":{"text":{"modifier":"idf"}}
+			}}}}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	stats := &fakeLexicalStats{totals: domain.LexicalStats{Docs: 10, TotalLength: 100}}
+	client := NewWithOptions(server.URL, "docs", Options{LexicalStats: stats})
+	if idf := client.queryIDF(context.Background(), []string{"invoice"}); idf != nil {
+		t.Fatalf("legacy collection already applies idf in Qdrant")
+	}
+}
+
+func TestIndexAnnotatedChunksIndexesPrefixLexically(t *testing.T) {
+	var upsertBody struct {
+		Points []struct {
+			Payload map[string]any `json:"payload"`
+		} `json:"points"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPut && r.URL.Path == "/collections/docs/points":
+			_ = json.NewDecoder(r.Body).Decode(&upsertBody)
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/scroll":
+			_, _ = w.Write([]byte(`{"result":{"points":[
+				{"payload":{"doc_id":"doc-1","filename":"a.txt","text":"set it to 30","context":"Section: Timeouts"}}
+			],"next_page_offset":null}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/collections/docs/points/delete":
+			_, _ = w.Write([]byte(`{"result":{"status":"completed"}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	stats := &fakeLexicalStats{}
+	client := NewWithOptions(server.URL, "docs", Options{LexicalStats: stats})
+	doc := &domain.Document{ID: "doc-1", Filename: "a.txt"}
+	annotations := []domain.ChunkAnnotation{{Context: "Section: Timeouts", Locator: domain.Locator{Page: 12}}}
+	err := client.IndexAnnotatedChunks(context.Background(), doc, []string{"set it to 30"}, annotations, [][]float32{{0.1}})
+	if err != nil {
+		t.Fatalf("IndexAnnotatedChunks() error = %v", err)
+	}
+
+	payload := upsertBody.Points[0].Payload
+	if payload["text"] != "set it to 30" || payload["context"] != "Section: Timeouts" {
+		t.Fatalf("unexpected payload: %#v", payload)
+	}
+	if payload["page"] != float64(12) {
+		t.Fatalf("expected page in payload, got %#v", payload)
+	}
+	if _, ok := payload["sheet"]; ok {
+		t.Fatalf("empty locator fields must not be stored: %#v", payload)
+	}
+	if got := locatorFromPayload(payload); got != (domain.Locator{Page: 12}) {
+		t.Fatalf("locatorFromPayload() = %+v", got)
+	}
+	if stats.df["timeout"] != 1 {
+		t.Fatalf("expected context terms in lexical stats, got %v", stats.df)
+	}
+
+	if err := client.DeleteByDocumentID(context.Background(), "doc-1"); err != nil {
+		t.Fatalf("DeleteByDocumentID() error = %v", err)
+	}
+	if len(stats.df) != 0 || stats.totals.TotalLength != 0 {
+		t.Fatalf("expected empty stats after delete, got %+v %v", stats.totals, stats.df)
+	}
+}
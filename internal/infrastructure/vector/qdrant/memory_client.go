@@ -198,6 +198,35 @@ func (c *MemoryClient) SearchSummaries(
 	return out, nil
 }
 
+// DeleteSummaries removes the memory vectors of one conversation.
+func (c *MemoryClient) DeleteSummaries(ctx context.Context, userID, conversationID string) error {
+	if strings.TrimSpace(userID) == "" || strings.TrimSpace(conversationID) == "" {
+		return fmt.Errorf("delete memory summaries: user id and conversation id are required")
+	}
+	body, err := json.Marshal(map[string]any{
+		"filter": buildMemoryFilter(userID, conversationID),
+	})
+	if err != nil {
+		return fmt.Errorf("marshal memory delete body: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/collections/%s/points/delete?wait=true", c.baseURL, c.collection)
+	resp, err := c.doRequest(ctx, "delete_points", http.MethodPost, url, body, "application/json")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// The collection is created lazily on first summary, so it may not exist yet.
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("memory delete status: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
 func buildMemoryFilter(userID, conversationID string) map[string]any {
 	must := []map[string]any{
 		{
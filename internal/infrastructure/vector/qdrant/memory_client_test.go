@@ -91,3 +91,29 @@ func TestMemoryClientSearchSummariesFilterAndDecode(t *testing.T) {
 	}
 }
 
+
+func TestMemoryClientDeleteSummariesFiltersByConversation(t *testing.T) {
+	var deleteBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/collections/memory/points/delete" {
+			_ = json.NewDecoder(r.Body).Decode(&deleteBody)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewMemoryClient(server.URL, "memory")
+	if err := client.DeleteSummaries(context.Background(), "u-1", ""); err == nil {
+		t.Fatal("expected error without conversation id")
+	}
+	if err := client.DeleteSummaries(context.Background(), "u-1", "c-1"); err != nil {
+		t.Fatalf("DeleteSummaries() error = %v", err)
+	}
+	filter, _ := deleteBody["filter"].(map[string]any)
+	must, _ := filter["must"].([]any)
+	if len(must) != 2 {
+		t.Fatalf("expected user and conversation conditions, got %#v", deleteBody)
+	}
+}
@@ -2,6 +2,7 @@ package qdrant
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 
@@ -50,6 +51,14 @@ func (m *MultiCollectionStore) IndexChunks(ctx context.Context, doc *domain.Docu
 	return client.IndexChunks(ctx, doc, chunks, vectors)
 }
 
+func (m *MultiCollectionStore) IndexAnnotatedChunks(ctx context.Context, doc *domain.Document, chunks []string, annotations []domain.ChunkAnnotation, vectors [][]float32) error {
+	client, ok := m.clients[doc.SourceType]
+	if !ok {
+		return fmt.Errorf("no collection for source_type %q", doc.SourceType)
+	}
+	return client.IndexAnnotatedChunks(ctx, doc, chunks, annotations, vectors)
+}
+
 func (m *MultiCollectionStore) Search(ctx context.Context, queryVector []float32, limit int, filter domain.SearchFilter) ([]domain.RetrievedChunk, error) {
 	return m.cascadeSearch(func(client *Client) ([]domain.RetrievedChunk, error) {
 		return client.Search(ctx, queryVector, limit, filter)
@@ -62,6 +71,29 @@ func (m *MultiCollectionStore) SearchLexical(ctx context.Context, queryText stri
 	}, limit, filter.SourceTypes)
 }
 
+// ChunksByIndex reads from the collection of sourceType. Chunks indexed
+// before source_type was stored carry none, so an unknown source type is
+// looked up in search order.
+func (m *MultiCollectionStore) ChunksByIndex(ctx context.Context, docID string, sourceType string, from, to int) ([]domain.RetrievedChunk, error) {
+	if client, ok := m.clients[sourceType]; ok {
+		return client.ChunksByIndex(ctx, docID, sourceType, from, to)
+	}
+	for _, st := range m.searchOrder {
+		client, ok := m.clients[st]
+		if !ok {
+			continue
+		}
+		chunks, err := client.ChunksByIndex(ctx, docID, st, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("collection %s: %w", st, err)
+		}
+		if len(chunks) > 0 {
+			return chunks, nil
+		}
+	}
+	return nil, nil
+}
+
 func (m *MultiCollectionStore) UpdateChunksPayload(ctx context.Context, docID string, sourceType string, payload map[string]any) error {
 	client, ok := m.clients[sourceType]
 	if !ok {
@@ -70,6 +102,18 @@ func (m *MultiCollectionStore) UpdateChunksPayload(ctx context.Context, docID st
 	return client.UpdateChunksPayload(ctx, docID, sourceType, payload)
 }
 
+// DeleteByDocumentID removes the document's points from every collection,
+// since the source type of a stale document is not always known.
+func (m *MultiCollectionStore) DeleteByDocumentID(ctx context.Context, docID string) error {
+	var errs []error
+	for st, client := range m.clients {
+		if err := client.DeleteByDocumentID(ctx, docID); err != nil {
+			errs = append(errs, fmt.Errorf("collection %s: %w", st, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (m *MultiCollectionStore) cascadeSearch(
 	searchFn func(*Client) ([]domain.RetrievedChunk, error),
 	limit int,
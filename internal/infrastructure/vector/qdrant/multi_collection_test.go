@@ -150,3 +150,38 @@ func TestMultiCollectionStore_IndexChunksUnknownSourceType(t *testing.T) {
 		t.Fatal("expected error for unknown source_type")
 	}
 }
+
+func TestMultiCollectionStore_DeleteByDocumentIDHitsEveryCollection(t *testing.T) {
+	var mu sync.Mutex
+	requestedURLs := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestedURLs = append(requestedURLs, r.URL.Path)
+		mu.Unlock()
+		if strings.Contains(r.URL.Path, "docs_web") {
+			// Collection never created: treated as already empty.
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"result":{"status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	store := NewMultiCollectionStore(server.URL, "docs", []string{"upload", "web"}, []string{"upload", "web"}, Options{})
+	if err := store.DeleteByDocumentID(context.Background(), "d1"); err != nil {
+		t.Fatalf("DeleteByDocumentID() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(requestedURLs) != 2 {
+		t.Fatalf("expected 2 delete requests, got %v", requestedURLs)
+	}
+	for _, u := range requestedURLs {
+		if !strings.HasSuffix(u, "/points/delete") {
+			t.Errorf("unexpected URL %s", u)
+		}
+	}
+}
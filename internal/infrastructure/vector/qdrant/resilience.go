@@ -9,12 +9,17 @@ import (
 	"strings"
 
 	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
+	"github.com/kirillkom/personal-ai-assistant/internal/core/ports"
 	"github.com/kirillkom/personal-ai-assistant/internal/infrastructure/resilience"
 )
 
 type Options struct {
 	HTTPClient         *http.Client
 	ResilienceExecutor *resilience.Executor
+	// LexicalStats maintains BM25 corpus statistics. Without it IDF is left
+	// to Qdrant's collection modifier and chunk lengths are normalized
+	// against a fixed average.
+	LexicalStats ports.LexicalStatsStore
 }
 
 type HTTPStatusError struct {
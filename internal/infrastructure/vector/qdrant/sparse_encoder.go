@@ -5,6 +5,7 @@ import (
 	"math"
 	"sort"
 
+	"github.com/kirillkom/personal-ai-assistant/internal/core/domain"
 	"github.com/kirillkom/personal-ai-assistant/internal/pkg/tokenizer"
 )
 
@@ -14,58 +15,117 @@ type sparseVector struct {
 }
 
 const (
-	docBM25K1      = 1.2
-	queryBM25K     = 1.2
-	filenameBoost  = 1.5
-	maxSparseTerms = 256
+	bm25K1        = 1.2
+	bm25B         = 0.75
+	filenameBoost = 1.5
+	// defaultAvgDocLength stands in for the corpus average chunk length
+	// until statistics exist.
+	defaultAvgDocLength = 256
+	maxSparseTerms      = 256
 )
 
-func encodeSparseDocument(text string, filename string) sparseVector {
-	termFreq := make(map[uint32]float64, 64)
-	appendTermFreq(termFreq, tokenizer.TokenizeUnicode(text), 1.0)
-	appendTermFreq(termFreq, tokenizer.TokenizeUnicode(filename), filenameBoost)
-	return termFreqToSparse(termFreq, docBM25K1)
+// lexicalDoc is an analyzed chunk: term frequencies (filename terms boosted)
+// and its length in terms.
+type lexicalDoc struct {
+	tf     map[string]float64
+	length int
 }
 
-func encodeSparseQuery(query string) sparseVector {
-	termFreq := make(map[uint32]float64, 32)
-	appendTermFreq(termFreq, tokenizer.TokenizeUnicode(query), 1.0)
-	return termFreqToSparse(termFreq, queryBM25K)
+func analyzeLexicalDocument(text string, filename string) lexicalDoc {
+	doc := lexicalDoc{tf: make(map[string]float64, 64)}
+	for _, term := range tokenizer.Analyze(text) {
+		doc.tf[term]++
+		doc.length++
+	}
+	for _, term := range tokenizer.Analyze(filename) {
+		doc.tf[term] += filenameBoost
+		doc.length++
+	}
+	return doc
+}
+
+// encode returns the BM25 term-frequency component of the chunk, normalized
+// by its length relative to avgLen. IDF is applied on the query side.
+func (d lexicalDoc) encode(avgLen float64) sparseVector {
+	if avgLen <= 0 {
+		avgLen = defaultAvgDocLength
+	}
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(d.length)/avgLen)
+	weights := make(map[uint32]float64, len(d.tf))
+	for term, tf := range d.tf {
+		weights[hashToken(term)] += tf * (bm25K1 + 1) / (tf + norm)
+	}
+	return topWeights(weights)
+}
+
+// lexicalStatsDelta is what indexing (sign 1) or deleting (sign -1) docs
+// changes in the collection statistics.
+func lexicalStatsDelta(docs []lexicalDoc, sign int64) domain.LexicalStats {
+	delta := domain.LexicalStats{Docs: sign * int64(len(docs)), DocFreq: make(map[string]int64)}
+	for _, doc := range docs {
+		delta.TotalLength += sign * int64(doc.length)
+		for term := range doc.tf {
+			delta.DocFreq[term] += sign
+		}
+	}
+	return delta
 }
 
-func appendTermFreq(dst map[uint32]float64, tokens []string, tokenWeight float64) {
-	for _, token := range tokens {
-		if token == "" {
-			continue
+// encodeQueryTerms weights each distinct term by its query frequency times
+// idf(term). A nil idf leaves IDF to Qdrant's collection modifier.
+func encodeQueryTerms(terms []string, idf func(term string) float64) sparseVector {
+	counts := make(map[string]float64, len(terms))
+	for _, term := range terms {
+		counts[term]++
+	}
+	weights := make(map[uint32]float64, len(counts))
+	for term, qtf := range counts {
+		w := qtf
+		if idf != nil {
+			w *= idf(term)
 		}
-		idx := hashToken(token)
-		dst[idx] += tokenWeight
+		weights[hashToken(term)] += w
 	}
+	return topWeights(weights)
 }
 
-func termFreqToSparse(tf map[uint32]float64, k float64) sparseVector {
-	if len(tf) == 0 {
+// bm25IDF is the Lucene variant of BM25 IDF, which stays positive for terms
+// present in most documents.
+func bm25IDF(docs, df int64) float64 {
+	if df > docs {
+		df = docs
+	}
+	return math.Log(1 + (float64(docs-df)+0.5)/(float64(df)+0.5))
+}
+
+// topWeights keeps the maxSparseTerms heaviest entries (ties broken by
+// index, so the result is deterministic) and returns them sorted by index.
+func topWeights(weights map[uint32]float64) sparseVector {
+	if len(weights) == 0 {
 		return sparseVector{}
 	}
-	indices := make([]uint32, 0, len(tf))
-	for idx := range tf {
-		indices = append(indices, idx)
+	indices := make([]uint32, 0, len(weights))
+	for idx, w := range weights {
+		if w > 0 && !math.IsNaN(w) && !math.IsInf(w, 0) {
+			indices = append(indices, idx)
+		}
 	}
-	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
 	if len(indices) > maxSparseTerms {
+		sort.Slice(indices, func(i, j int) bool {
+			wi, wj := weights[indices[i]], weights[indices[j]]
+			if wi != wj {
+				return wi > wj
+			}
+			return indices[i] < indices[j]
+		})
 		indices = indices[:maxSparseTerms]
 	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
 
 	values := make([]float32, 0, len(indices))
 	for _, idx := range indices {
-		tfValue := tf[idx]
-		weight := (tfValue * (k + 1.0)) / (tfValue + k)
-		if math.IsNaN(weight) || math.IsInf(weight, 0) {
-			weight = 0
-		}
-		values = append(values, float32(weight))
+		values = append(values, float32(weights[idx]))
 	}
-
 	return sparseVector{Indices: indices, Values: values}
 }
 
@@ -78,4 +138,3 @@ func hashToken(token string) uint32 {
 	}
 	return sum
 }
-
@@ -6,9 +6,9 @@ import (
 	"github.com/kirillkom/personal-ai-assistant/internal/pkg/tokenizer"
 )
 
-func TestEncodeSparseQueryDeterministic(t *testing.T) {
-	v1 := encodeSparseQuery("Risk level for DOC_0001")
-	v2 := encodeSparseQuery("Risk level for DOC_0001")
+func TestEncodeQueryTermsDeterministic(t *testing.T) {
+	v1 := encodeQueryTerms(tokenizer.Analyze("Risk level for DOC_0001"), nil)
+	v2 := encodeQueryTerms(tokenizer.Analyze("Risk level for DOC_0001"), nil)
 	if len(v1.Indices) != len(v2.Indices) || len(v1.Values) != len(v2.Values) {
 		t.Fatalf("vector sizes mismatch: v1=%d/%d v2=%d/%d", len(v1.Indices), len(v1.Values), len(v2.Indices), len(v2.Values))
 	}
@@ -22,8 +22,8 @@ func TestEncodeSparseQueryDeterministic(t *testing.T) {
 	}
 }
 
-func TestEncodeSparseQuerySortsIndices(t *testing.T) {
-	v := encodeSparseQuery("zulu alpha beta gamma")
+func TestEncodeQueryTermsSortsIndices(t *testing.T) {
+	v := encodeQueryTerms(tokenizer.Analyze("zulu alpha beta gamma"), nil)
 	if len(v.Indices) == 0 {
 		t.Fatalf("expected non-empty sparse vector")
 	}
@@ -34,8 +34,8 @@ func TestEncodeSparseQuerySortsIndices(t *testing.T) {
 	}
 }
 
-func TestEncodeSparseQueryEmptyNoiseInput(t *testing.T) {
-	v := encodeSparseQuery("___---!!!")
+func TestEncodeQueryTermsEmptyNoiseInput(t *testing.T) {
+	v := encodeQueryTerms(tokenizer.Analyze("___---!!!"), nil)
 	if len(v.Indices) != 0 || len(v.Values) != 0 {
 		t.Fatalf("expected empty sparse vector, got %+v", v)
 	}
@@ -60,3 +60,78 @@ func TestTokenizeAlphaNumUnicodeAndDigitsStability(t *testing.T) {
 		t.Fatalf("expected doc and 0001 tokens, got %v", tokens)
 	}
 }
+
+func TestLexicalDocEncodeNormalizesByLength(t *testing.T) {
+	short := analyzeLexicalDocument("invoice", "")
+	long := analyzeLexicalDocument("invoice payment vendor contract signature approval", "")
+	idx := hashToken("invoice")
+
+	weightOf := func(v sparseVector) float32 {
+		for i, got := range v.Indices {
+			if got == idx {
+				return v.Values[i]
+			}
+		}
+		return 0
+	}
+	ws, wl := weightOf(short.encode(3)), weightOf(long.encode(3))
+	if ws <= wl || wl <= 0 {
+		t.Fatalf("expected the short chunk to weigh the term higher: short=%f long=%f", ws, wl)
+	}
+}
+
+func TestEncodeQueryTermsAppliesIDF(t *testing.T) {
+	df := map[string]int64{"rare": 1, "common": 90}
+	v := encodeQueryTerms([]string{"rare", "common"}, func(term string) float64 {
+		return bm25IDF(100, df[term])
+	})
+	weights := make(map[uint32]float32, len(v.Indices))
+	for i, idx := range v.Indices {
+		weights[idx] = v.Values[i]
+	}
+	if weights[hashToken("rare")] <= weights[hashToken("common")] {
+		t.Fatalf("rare term must outweigh common term: %+v", weights)
+	}
+	if bm25IDF(100, 100) <= 0 {
+		t.Fatalf("idf must stay positive for a term in every document")
+	}
+}
+
+func TestTopWeightsKeepsHeaviestTermsDeterministically(t *testing.T) {
+	weights := make(map[uint32]float64, maxSparseTerms+10)
+	for i := uint32(1); i <= maxSparseTerms+10; i++ {
+		weights[i] = 1
+	}
+	weights[maxSparseTerms+10] = 5
+
+	v1, v2 := topWeights(weights), topWeights(weights)
+	if len(v1.Indices) != maxSparseTerms {
+		t.Fatalf("expected %d terms, got %d", maxSparseTerms, len(v1.Indices))
+	}
+	if v1.Indices[len(v1.Indices)-1] != maxSparseTerms+10 {
+		t.Fatalf("heaviest term must be kept, last index = %d", v1.Indices[len(v1.Indices)-1])
+	}
+	// Ties are broken by index: the lowest indices survive.
+	if v1.Indices[0] != 1 || v1.Indices[maxSparseTerms-2] != maxSparseTerms-1 {
+		t.Fatalf("unexpected tie-breaking: %v", v1.Indices[:3])
+	}
+	for i := range v1.Indices {
+		if v1.Indices[i] != v2.Indices[i] {
+			t.Fatalf("selection not deterministic at %d", i)
+		}
+	}
+}
+
+func TestLexicalStatsDeltaCountsDocumentFrequency(t *testing.T) {
+	docs := []lexicalDoc{
+		analyzeLexicalDocument("invoice invoice payment", ""),
+		analyzeLexicalDocument("invoice", ""),
+	}
+	delta := lexicalStatsDelta(docs, -1)
+	if delta.Docs != -2 || delta.TotalLength != -4 {
+		t.Fatalf("unexpected totals: %+v", delta)
+	}
+	if delta.DocFreq["invoice"] != -2 || delta.DocFreq["payment"] != -1 {
+		t.Fatalf("unexpected doc freq: %v", delta.DocFreq)
+	}
+}
@@ -0,0 +1,138 @@
+// Package archive reads the files of ZIP and TAR archives (optionally gzip
+// compressed) without extracting them to disk.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// ErrUnsupported is returned for files that are not a ZIP or TAR archive.
+var ErrUnsupported = errors.New("unsupported archive format: expected zip, tar or tar.gz")
+
+// Entry is a regular file inside an archive.
+type Entry struct {
+	// Name is the slash-separated path inside the archive, cleaned so it
+	// cannot start with "/" or climb out with "..".
+	Name string
+	Size int64
+}
+
+// Walk calls fn for every regular file of the archive at filePath, in
+// archive order. r yields the file content and is only valid during the
+// call. An error from fn stops the walk and is returned.
+func Walk(filePath string, fn func(e Entry, r io.Reader) error) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("open archive: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read archive: %w", err)
+	}
+	head = head[:n]
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewind archive: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		info, err := f.Stat()
+		if err != nil {
+			return fmt.Errorf("stat archive: %w", err)
+		}
+		return walkZip(f, info.Size(), fn)
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(bufio.NewReader(f))
+		if err != nil {
+			return fmt.Errorf("open gzip: %w", err)
+		}
+		defer func() { _ = gz.Close() }()
+		return walkTar(gz, fn)
+	case isTar(head):
+		return walkTar(f, fn)
+	}
+	return ErrUnsupported
+}
+
+func walkZip(r io.ReaderAt, size int64, fn func(Entry, io.Reader) error) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("open zip: %w", err)
+	}
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		name, ok := CleanName(zf.Name)
+		if !ok {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			// Report the entry with a failing reader so one unreadable
+			// entry (e.g. encrypted) does not stop the walk.
+			err = fn(Entry{Name: name, Size: int64(zf.UncompressedSize64)}, errReader{err})
+		} else {
+			err = fn(Entry{Name: name, Size: int64(zf.UncompressedSize64)}, rc)
+			_ = rc.Close()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkTar(r io.Reader, fn func(Entry, io.Reader) error) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read tar: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, ok := CleanName(hdr.Name)
+		if !ok {
+			continue
+		}
+		if err := fn(Entry{Name: name, Size: hdr.Size}, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// CleanName normalizes an archive path: backslashes become slashes, and
+// leading "/", "./" and ".." segments are dropped. It reports false for
+// names that are empty after cleaning.
+func CleanName(name string) (string, bool) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	cleaned := strings.TrimPrefix(path.Clean("/"+name), "/")
+	return cleaned, cleaned != "" && cleaned != "."
+}
+
+// isTar checks the ustar magic of the first header block.
+func isTar(head []byte) bool {
+	return len(head) >= 262 && bytes.Equal(head[257:262], []byte("ustar"))
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
@@ -0,0 +1,120 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type file struct{ name, body string }
+
+func writeTemp(t *testing.T, data []byte) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "archive")
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func zipBytes(t *testing.T, files []file) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = w.Write([]byte(f.body))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func tarBytes(t *testing.T, files []file) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	_ = tw.WriteHeader(&tar.Header{Name: "wiki/", Typeflag: tar.TypeDir, Mode: 0o755})
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0o644, Size: int64(len(f.body)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		_, _ = tw.Write([]byte(f.body))
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func collect(t *testing.T, p string) []file {
+	t.Helper()
+	var got []file
+	err := Walk(p, func(e Entry, r io.Reader) error {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		got = append(got, file{e.Name, string(body)})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	return got
+}
+
+func TestWalkFormats(t *testing.T) {
+	files := []file{{"wiki/a.md", "# A"}, {"../../etc/b.txt", "B"}}
+	want := []file{{"wiki/a.md", "# A"}, {"etc/b.txt", "B"}}
+
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	_, _ = zw.Write(tarBytes(t, files))
+	_ = zw.Close()
+
+	for name, data := range map[string][]byte{
+		"zip":    zipBytes(t, files),
+		"tar":    tarBytes(t, files),
+		"tar.gz": gz.Bytes(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := collect(t, writeTemp(t, data))
+			if len(got) != len(want) {
+				t.Fatalf("got %v, want %v", got, want)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("entry %d: got %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWalkUnsupported(t *testing.T) {
+	err := Walk(writeTemp(t, []byte("just text")), func(Entry, io.Reader) error { return nil })
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestWalkStopsOnCallbackError(t *testing.T) {
+	stop := errors.New("stop")
+	p := writeTemp(t, zipBytes(t, []file{{"a", "1"}, {"b", "2"}}))
+	calls := 0
+	err := Walk(p, func(Entry, io.Reader) error { calls++; return stop })
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("got err %v after %d calls", err, calls)
+	}
+}
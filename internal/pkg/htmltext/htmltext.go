@@ -1,4 +1,5 @@
-package web
+// Package htmltext converts HTML documents to plain text.
+package htmltext
 
 import (
 	"strings"
@@ -7,7 +8,9 @@ import (
 	"golang.org/x/net/html/atom"
 )
 
-func extractTextFromHTML(s string) (string, string) {
+// Extract returns the document title and the visible body text of HTML s,
+// one line per block element. Scripts and styles are dropped.
+func Extract(s string) (string, string) {
 	if s == "" {
 		return "", ""
 	}
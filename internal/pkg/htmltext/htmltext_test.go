@@ -1,8 +1,8 @@
-package web
+package htmltext
 
 import "testing"
 
-func TestExtractTextFromHTML(t *testing.T) {
+func TestExtract(t *testing.T) {
 	tests := []struct {
 		name      string
 		html      string
@@ -37,7 +37,7 @@ func TestExtractTextFromHTML(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			title, body := extractTextFromHTML(tt.html)
+			title, body := Extract(tt.html)
 			if title != tt.wantTitle {
 				t.Errorf("title = %q, want %q", title, tt.wantTitle)
 			}
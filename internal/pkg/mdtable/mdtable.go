@@ -0,0 +1,35 @@
+// Package mdtable renders rows of cells as Markdown pipe tables, the form
+// extracted tables take so chunkers and LLMs keep their structure.
+package mdtable
+
+import "strings"
+
+// Render returns rows as a pipe table whose first row is the header. Short
+// rows are padded, cell whitespace is collapsed and "|" is escaped. It
+// returns "" when there are no cells.
+func Render(rows [][]string) string {
+	width := 0
+	for _, r := range rows {
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(rows)+1)
+	for i, r := range rows {
+		var b strings.Builder
+		b.WriteString("|")
+		for c := 0; c < width; c++ {
+			cell := ""
+			if c < len(r) {
+				cell = strings.ReplaceAll(strings.Join(strings.Fields(r[c]), " "), "|", `\|`)
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		lines = append(lines, b.String())
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,26 @@
+package mdtable
+
+import "testing"
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		rows [][]string
+		want string
+	}{
+		{name: "empty", rows: nil, want: ""},
+		{name: "header only", rows: [][]string{{"A", "B"}}, want: "| A | B |\n| --- | --- |"},
+		{
+			name: "pads and escapes",
+			rows: [][]string{{"Name", "Note"}, {"x|y"}, {"z", " two\n lines "}},
+			want: "| Name | Note |\n| --- | --- |\n| x\\|y |  |\n| z | two lines |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.rows); got != tt.want {
+				t.Fatalf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
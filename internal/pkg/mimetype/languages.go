@@ -0,0 +1,63 @@
+package mimetype
+
+import "strings"
+
+// Language is a programming language recognized by file extension.
+type Language struct {
+	Name       string // lower-case identifier used by language-aware chunking
+	Label      string // human-readable name
+	MimeType   string
+	Extensions []string
+	Filenames  []string // extension-less names such as Dockerfile
+}
+
+// Languages lists the source code types ingested as code.
+var Languages = []Language{
+	{Name: "go", Label: "Go", MimeType: "text/x-go", Extensions: []string{".go"}},
+	{Name: "python", Label: "Python", MimeType: "text/x-python", Extensions: []string{".py", ".pyi"}},
+	{Name: "javascript", Label: "JavaScript", MimeType: "text/javascript", Extensions: []string{".js", ".mjs", ".cjs", ".jsx"}},
+	{Name: "typescript", Label: "TypeScript", MimeType: "text/x-typescript", Extensions: []string{".ts", ".tsx", ".mts"}},
+	{Name: "java", Label: "Java", MimeType: "text/x-java", Extensions: []string{".java"}},
+	{Name: "kotlin", Label: "Kotlin", MimeType: "text/x-kotlin", Extensions: []string{".kt", ".kts"}},
+	{Name: "csharp", Label: "C#", MimeType: "text/x-csharp", Extensions: []string{".cs"}},
+	{Name: "c", Label: "C", MimeType: "text/x-c", Extensions: []string{".c", ".h"}},
+	{Name: "cpp", Label: "C++", MimeType: "text/x-c++", Extensions: []string{".cpp", ".cc", ".cxx", ".hpp", ".hh"}},
+	{Name: "rust", Label: "Rust", MimeType: "text/x-rust", Extensions: []string{".rs"}},
+	{Name: "ruby", Label: "Ruby", MimeType: "text/x-ruby", Extensions: []string{".rb"}},
+	{Name: "php", Label: "PHP", MimeType: "text/x-php", Extensions: []string{".php"}},
+	{Name: "swift", Label: "Swift", MimeType: "text/x-swift", Extensions: []string{".swift"}},
+	{Name: "scala", Label: "Scala", MimeType: "text/x-scala", Extensions: []string{".scala"}},
+	{Name: "shell", Label: "Shell", MimeType: "text/x-shellscript", Extensions: []string{".sh", ".bash", ".zsh"}},
+	{Name: "sql", Label: "SQL", MimeType: "text/x-sql", Extensions: []string{".sql"}},
+	{Name: "yaml", Label: "YAML", MimeType: "text/x-yaml", Extensions: []string{".yaml", ".yml"}},
+	{Name: "toml", Label: "TOML", MimeType: "text/x-toml", Extensions: []string{".toml"}},
+	{Name: "json", Label: "JSON", MimeType: "application/json", Extensions: []string{".json"}},
+	{Name: "protobuf", Label: "Protocol Buffers", MimeType: "text/x-protobuf", Extensions: []string{".proto"}},
+	{Name: "dockerfile", Label: "Dockerfile", MimeType: "text/x-dockerfile", Filenames: []string{"dockerfile"}},
+	{Name: "makefile", Label: "Makefile", MimeType: "text/x-makefile", Filenames: []string{"makefile", "gnumakefile"}},
+}
+
+var (
+	languageByExtension = make(map[string]Language)
+	languageByFilename  = make(map[string]Language)
+	languageByMimeType  = make(map[string]Language)
+)
+
+func init() {
+	for _, lang := range Languages {
+		for _, ext := range lang.Extensions {
+			languageByExtension[ext] = lang
+		}
+		for _, name := range lang.Filenames {
+			languageByFilename[name] = lang
+		}
+		languageByMimeType[lang.MimeType] = lang
+	}
+}
+
+// LanguageFor returns the language of a source code MIME type.
+func LanguageFor(mimeType string) (Language, bool) {
+	base := strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	lang, ok := languageByMimeType[base]
+	return lang, ok
+}
@@ -0,0 +1,186 @@
+// Package mimetype resolves the MIME type of uploaded files from their
+// extension and content, since clients often send application/octet-stream
+// or a guess of their own.
+package mimetype
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// SniffLen is how many leading bytes Detect inspects.
+const SniffLen = 4096
+
+const (
+	EPUB     = "application/epub+zip"
+	PPTX     = "application/vnd.openxmlformats-officedocument.presentationml.presentation"
+	DOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	XLSX     = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ODT      = "application/vnd.oasis.opendocument.text"
+	ODS      = "application/vnd.oasis.opendocument.spreadsheet"
+	ODP      = "application/vnd.oasis.opendocument.presentation"
+	RTF      = "application/rtf"
+	EML      = "message/rfc822"
+	MBOX     = "application/mbox"
+	HTML     = "text/html"
+	Markdown = "text/markdown"
+)
+
+var byExtension = map[string]string{
+	".pdf":      "application/pdf",
+	".docx":     DOCX,
+	".xlsx":     XLSX,
+	".pptx":     PPTX,
+	".csv":      "text/csv",
+	".epub":     EPUB,
+	".odt":      ODT,
+	".ods":      ODS,
+	".odp":      ODP,
+	".rtf":      RTF,
+	".eml":      EML,
+	".mbox":     MBOX,
+	".mbx":      MBOX,
+	".html":     HTML,
+	".htm":      HTML,
+	".xhtml":    "application/xhtml+xml",
+	".md":       Markdown,
+	".markdown": Markdown,
+	".txt":      "text/plain",
+	".png":      "image/png",
+	".jpg":      "image/jpeg",
+	".jpeg":     "image/jpeg",
+	".gif":      "image/gif",
+	".webp":     "image/webp",
+	".tif":      "image/tiff",
+	".tiff":     "image/tiff",
+	".bmp":      "image/bmp",
+}
+
+// genericTypes say nothing about the content and are always re-detected.
+var genericTypes = map[string]bool{
+	"":                             true,
+	"application/octet-stream":     true,
+	"binary/octet-stream":          true,
+	"application/x-download":       true,
+	"application/zip":              true,
+	"application/x-zip-compressed": true,
+	"text/plain":                   true,
+}
+
+// Detect returns the MIME type of a file. A known extension wins, because
+// browsers send vendor types such as application/vnd.ms-excel for CSV. A
+// generic declared type is replaced by a sniffed one; any other declared
+// type is kept.
+func Detect(filename, declared string, head []byte) string {
+	ext := strings.ToLower(filepath.Ext(filename))
+	if t, ok := byExtension[ext]; ok {
+		return t
+	}
+	if lang, ok := languageByExtension[ext]; ok {
+		return lang.MimeType
+	}
+	if lang, ok := languageByFilename[strings.ToLower(filepath.Base(filename))]; ok {
+		return lang.MimeType
+	}
+
+	base := strings.ToLower(strings.TrimSpace(strings.SplitN(declared, ";", 2)[0]))
+	if !genericTypes[base] {
+		return declared
+	}
+	if sniffed := Sniff(head); sniffed != "" {
+		if base == "text/plain" && !strings.HasPrefix(sniffed, "text/") && sniffed != EML && sniffed != MBOX && sniffed != RTF {
+			// A text/plain declaration is only overridden by formats that
+			// are themselves text.
+			return declared
+		}
+		return sniffed
+	}
+	return declared
+}
+
+// Sniff guesses the MIME type from the leading bytes of a file, or returns
+// "" when the content is not recognized.
+func Sniff(head []byte) string {
+	if len(head) == 0 {
+		return ""
+	}
+	trimmed := bytes.TrimLeft(head, " \t\r\n\uFEFF")
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")):
+		return sniffZip(head)
+	case bytes.HasPrefix(trimmed, []byte(`{\rtf`)):
+		return RTF
+	case bytes.HasPrefix(head, []byte("From ")) && looksLikeMail(head[bytes.IndexByte(head, '\n')+1:]):
+		return MBOX
+	case looksLikeMail(head):
+		return EML
+	}
+	t := strings.SplitN(http.DetectContentType(head), ";", 2)[0]
+	switch t {
+	case "application/octet-stream", "text/plain":
+		return ""
+	case "text/xml":
+		if bytes.Contains(bytes.ToLower(head), []byte("<html")) {
+			return HTML
+		}
+	}
+	return t
+}
+
+// sniffZip tells ZIP-based formats apart. EPUB and OpenDocument store their
+// type uncompressed in a leading "mimetype" entry; OOXML packages name their
+// part directories in the local file headers.
+func sniffZip(head []byte) string {
+	if i := bytes.Index(head, []byte("mimetype")); i >= 0 && i < 64 {
+		rest := head[i+len("mimetype"):]
+		for _, t := range []string{EPUB, ODT, ODS, ODP} {
+			if bytes.HasPrefix(rest, []byte(t)) {
+				return t
+			}
+		}
+	}
+	switch {
+	case bytes.Contains(head, []byte("word/")):
+		return DOCX
+	case bytes.Contains(head, []byte("ppt/")):
+		return PPTX
+	case bytes.Contains(head, []byte("xl/")):
+		return XLSX
+	}
+	return ""
+}
+
+// mailHeaders are header names an RFC 822 message starts with.
+var mailHeaders = []string{
+	"return-path:", "received:", "from:", "to:", "subject:", "date:",
+	"message-id:", "mime-version:", "delivered-to:", "x-",
+}
+
+// looksLikeMail reports whether the first two lines are mail headers.
+func looksLikeMail(head []byte) bool {
+	lines := bytes.SplitN(head, []byte("\n"), 3)
+	if len(lines) < 2 {
+		return false
+	}
+	for i, line := range lines[:2] {
+		if i > 0 && len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			continue // folded header
+		}
+		lower := strings.ToLower(string(line))
+		if !strings.Contains(lower, ":") || !hasAnyPrefix(lower, mailHeaders) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
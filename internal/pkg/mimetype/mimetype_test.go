@@ -0,0 +1,43 @@
+package mimetype
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		declared string
+		head     string
+		want     string
+	}{
+		{"extension wins over vendor type", "report.csv", "application/vnd.ms-excel", "a,b", "text/csv"},
+		{"code by extension", "main.go", "application/octet-stream", "package main", "text/x-go"},
+		{"code by filename", "Dockerfile", "", "FROM alpine", "text/x-dockerfile"},
+		{"specific declared type kept", "upload", "application/pdf", "%PDF-1.4", "application/pdf"},
+		{"rtf sniffed", "notes", "application/octet-stream", `{\rtf1\ansi hello}`, RTF},
+		{"email sniffed", "message", "", "Received: from mx\nFrom: a@example.org\n\nbody", EML},
+		{"mbox sniffed", "archive", "application/octet-stream", "From a@example.org Mon Jan  1 00:00:00 2024\nFrom: a@example.org\nSubject: hi\n", MBOX},
+		{"epub sniffed", "book", "application/zip", "PK\x03\x04" + string(make([]byte, 26)) + "mimetype" + EPUB, EPUB},
+		{"pptx sniffed", "deck", "application/octet-stream", "PK\x03\x04....[Content_Types].xml....ppt/slides/slide1.xml", PPTX},
+		{"html sniffed", "page", "application/octet-stream", "<!DOCTYPE html><html><body>x</body></html>", "text/html"},
+		{"text/plain kept for binary", "file", "text/plain", "%PDF-1.4", "text/plain"},
+		{"unknown kept", "file", "application/octet-stream", "\x00\x01\x02", "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Detect(tt.filename, tt.declared, []byte(tt.head)); got != tt.want {
+				t.Fatalf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageFor(t *testing.T) {
+	lang, ok := LanguageFor("text/x-python; charset=utf-8")
+	if !ok || lang.Name != "python" {
+		t.Fatalf("LanguageFor() = %+v, %v", lang, ok)
+	}
+	if _, ok := LanguageFor("text/plain"); ok {
+		t.Fatal("text/plain is not a language")
+	}
+}
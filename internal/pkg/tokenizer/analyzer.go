@@ -0,0 +1,125 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Analyze turns text into index terms for lexical search: it tokenizes with
+// TokenizeUnicode, drops Russian and English stopwords and stems the rest.
+// Tokens containing digits (ticket numbers, versions, identifiers) are kept
+// verbatim so exact-match queries still hit.
+func Analyze(s string) []string {
+	tokens := TokenizeUnicode(s)
+	if tokens == nil {
+		return nil
+	}
+	out := tokens[:0]
+	for _, tok := range tokens {
+		tok = strings.ReplaceAll(tok, "ё", "е")
+		if IsStopword(tok) {
+			continue
+		}
+		out = append(out, Stem(tok))
+	}
+	return out
+}
+
+// IsStopword reports whether tok is a common Russian or English function
+// word that carries no meaning for retrieval.
+func IsStopword(tok string) bool {
+	_, ok := stopwords[tok]
+	return ok
+}
+
+// Stem reduces a lowercase token to its stem. Cyrillic words get a light
+// Russian suffix stripper, Latin words a Porter-style plural and verb form
+// stripper; anything else, or any token with a digit, is returned as is.
+func Stem(tok string) string {
+	script := unicode.Latin
+	for _, r := range tok {
+		if unicode.IsDigit(r) {
+			return tok
+		}
+		if unicode.Is(unicode.Cyrillic, r) {
+			script = unicode.Cyrillic
+		}
+	}
+	if script == unicode.Cyrillic {
+		return stemRussian(tok)
+	}
+	return stemEnglish(tok)
+}
+
+// russianEndings are inflectional endings, longest first so the longest
+// match wins.
+var russianEndings = []string{
+	"иями", "ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "иях", "ией",
+	"ать", "ять", "ить", "еть", "ует", "уют", "ают", "яют", "ешь", "ишь", "ете", "ите",
+	"ов", "ев", "ах", "ях", "ам", "ям", "ом", "ем", "ей", "ой", "ий", "ый", "ая", "яя",
+	"ое", "ее", "ые", "ие", "ую", "юю", "ия", "ья", "ью", "ют", "ет", "ит", "ат", "ят",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+}
+
+func stemRussian(tok string) string {
+	for _, end := range russianEndings {
+		if strings.HasSuffix(tok, end) && utf8.RuneCountInString(tok)-utf8.RuneCountInString(end) >= 3 {
+			return strings.TrimSuffix(tok, end)
+		}
+	}
+	return tok
+}
+
+func stemEnglish(tok string) string {
+	if len(tok) <= 3 {
+		return tok
+	}
+	switch {
+	case strings.HasSuffix(tok, "sses"):
+		tok = tok[:len(tok)-2]
+	case strings.HasSuffix(tok, "ies"):
+		tok = tok[:len(tok)-3] + "y"
+	case strings.HasSuffix(tok, "ss"), strings.HasSuffix(tok, "us"), strings.HasSuffix(tok, "is"):
+	case strings.HasSuffix(tok, "s"):
+		tok = tok[:len(tok)-1]
+	}
+	for _, suffix := range []string{"ing", "ed"} {
+		stem := strings.TrimSuffix(tok, suffix)
+		if stem != tok && len(stem) >= 3 && strings.ContainsAny(stem, "aeiouy") {
+			// "stopped" -> "stop", but keep "ll"/"ss" as in "called".
+			if n := len(stem); stem[n-1] == stem[n-2] && !strings.ContainsRune("lsz", rune(stem[n-1])) {
+				stem = stem[:n-1]
+			}
+			return stem
+		}
+	}
+	return tok
+}
+
+var stopwords = func() map[string]struct{} {
+	words := []string{
+		// English
+		"a", "about", "all", "an", "and", "any", "are", "as", "at", "be", "been", "but", "by",
+		"can", "could", "did", "do", "does", "for", "from", "had", "has", "have", "he", "her",
+		"his", "how", "i", "if", "in", "into", "is", "it", "its", "me", "my", "no", "not", "of",
+		"on", "or", "our", "she", "should", "so", "such", "than", "that", "the", "their", "them",
+		"then", "there", "these", "they", "this", "those", "to", "was", "we", "were", "what",
+		"when", "where", "which", "who", "why", "will", "with", "would", "you", "your",
+		// Russian
+		"а", "без", "более", "бы", "был", "была", "были", "было", "быть", "в", "вам", "вас",
+		"весь", "во", "вот", "все", "всего", "всех", "вы", "где", "да", "даже", "для", "до",
+		"его", "ее", "если", "есть", "еще", "же", "за", "здесь", "и", "из", "или", "им", "их",
+		"к", "как", "какая", "какой", "когда", "кто", "ли", "либо", "мне", "может", "мы", "на",
+		"над", "нам", "нас", "не", "него", "нее", "нет", "ни", "них", "но", "ну", "о", "об",
+		"однако", "он", "она", "они", "оно", "от", "очень", "по", "под", "при", "про", "с",
+		"со", "так", "также", "такой", "там", "те", "тем", "то", "того", "тоже", "той",
+		"только", "том", "ты", "у", "уже", "хотя", "чего", "чем", "что", "чтобы", "эта", "эти",
+		"это", "этого", "этой", "этом", "этот", "эту", "я",
+	}
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		m[w] = struct{}{}
+	}
+	return m
+}()
@@ -0,0 +1,48 @@
+package tokenizer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeDropsStopwordsAndStems(t *testing.T) {
+	got := Analyze("Где найти список документов для тикета OPS-1042?")
+	want := []string{"найт", "список", "документ", "тикет", "ops", "1042"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Analyze() = %v, want %v", got, want)
+	}
+
+	got = Analyze("The policies for running the stopped services")
+	want = []string{"policy", "run", "stop", "service"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Analyze() = %v, want %v", got, want)
+	}
+}
+
+func TestStemConflatesInflections(t *testing.T) {
+	cases := [][]string{
+		{"документ", "документа", "документов", "документами"},
+		{"ошибка", "ошибки", "ошибку"},
+		{"invoice", "invoices"},
+		{"deploy", "deployed", "deploying"},
+	}
+	for _, forms := range cases {
+		base := Stem(forms[0])
+		for _, form := range forms[1:] {
+			if got := Stem(form); got != base {
+				t.Errorf("Stem(%q) = %q, want %q", form, got, base)
+			}
+		}
+	}
+}
+
+func TestStemKeepsTokensWithDigits(t *testing.T) {
+	for _, tok := range []string{"v2", "2024", "x86"} {
+		if got := Stem(tok); got != tok {
+			t.Errorf("Stem(%q) = %q, want unchanged", tok, got)
+		}
+	}
+	if got := Analyze("ёлка"); len(got) != 1 || got[0] != Stem("елка") {
+		t.Fatalf("ё must fold to е, got %v", got)
+	}
+}
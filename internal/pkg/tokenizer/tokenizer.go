@@ -28,3 +28,39 @@ func TokenizeUnicode(s string) []string {
 	}
 	return out
 }
+
+// CountTokens approximates how many subword tokens an embedding model's
+// tokenizer (BPE / SentencePiece) produces for s: short words are one token,
+// longer ones are split into pieces of about six Latin or four non-Latin
+// runes, and every punctuation or symbol rune is a token of its own.
+func CountTokens(s string) int {
+	count := 0
+	wordLen, latin := 0, true
+	flush := func() {
+		if wordLen == 0 {
+			return
+		}
+		piece := 6
+		if !latin {
+			piece = 4
+		}
+		count += 1 + (wordLen-1)/piece
+		wordLen, latin = 0, true
+	}
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			wordLen++
+			if r > unicode.MaxASCII {
+				latin = false
+			}
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			count++
+		}
+	}
+	flush()
+	return count
+}
@@ -73,3 +73,21 @@ func TestTokenizeUnicode(t *testing.T) {
 		})
 	}
 }
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"hello world", 2},
+		{"internationalization", 4},
+		{"Привет, мир!", 5},
+		{"v1.2", 3},
+	}
+	for _, tt := range tests {
+		if got := CountTokens(tt.input); got != tt.want {
+			t.Errorf("CountTokens(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -38,5 +38,8 @@ export async function apiFetch<T>(
     throw new ApiError(resp.status, body || resp.statusText);
   }
 
+  if (resp.status === 204) {
+    return undefined as T;
+  }
   return resp.json() as Promise<T>;
 }
@@ -0,0 +1,47 @@
+import { apiFetch } from "./client";
+import type {
+  ConversationTranscript,
+  ServerConversation,
+  ServerConversationMessage,
+} from "./types";
+
+// The desktop app chats as user "desktop" (see chatStore), so history calls
+// use the same identity.
+const DESKTOP_USER_HEADERS = { "X-User-ID": "desktop" };
+
+export async function listConversations(limit = 100): Promise<ServerConversation[]> {
+  const resp = await apiFetch<{ conversations: ServerConversation[] }>(
+    `/v1/conversations?limit=${limit}`,
+    { headers: DESKTOP_USER_HEADERS },
+  );
+  return resp.conversations;
+}
+
+export async function getConversation(id: string): Promise<ConversationTranscript> {
+  return apiFetch<ConversationTranscript>(`/v1/conversations/${encodeURIComponent(id)}`, {
+    headers: DESKTOP_USER_HEADERS,
+  });
+}
+
+export async function searchConversations(query: string): Promise<ServerConversationMessage[]> {
+  const resp = await apiFetch<{ messages: ServerConversationMessage[] }>(
+    `/v1/conversations/search?q=${encodeURIComponent(query)}`,
+    { headers: DESKTOP_USER_HEADERS },
+  );
+  return resp.messages;
+}
+
+export async function renameConversation(id: string, title: string): Promise<ServerConversation> {
+  return apiFetch<ServerConversation>(`/v1/conversations/${encodeURIComponent(id)}`, {
+    method: "PATCH",
+    headers: DESKTOP_USER_HEADERS,
+    body: JSON.stringify({ title }),
+  });
+}
+
+export async function deleteConversation(id: string): Promise<void> {
+  await apiFetch<void>(`/v1/conversations/${encodeURIComponent(id)}`, {
+    method: "DELETE",
+    headers: DESKTOP_USER_HEADERS,
+  });
+}
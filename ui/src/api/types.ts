@@ -140,6 +140,31 @@ export interface AgentImprovement {
   applied_at: string | null;
 }
 
+// --- Conversations ---
+
+export interface ServerConversation {
+  user_id: string;
+  conversation_id: string;
+  title: string;
+  current_user_turn: number;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ServerConversationMessage {
+  id: string;
+  conversation_id: string;
+  role: string;
+  content: string;
+  tool_name?: string;
+  user_turn: number;
+  created_at: string;
+}
+
+export interface ConversationTranscript extends ServerConversation {
+  messages: ServerConversationMessage[];
+}
+
 // --- HTTP Tools ---
 
 export interface HTTPToolDef {
@@ -14,8 +14,14 @@ interface Props {
 }
 
 export function ChatPage({ pendingReference, onReferenceClear }: Props) {
-  const { conversations, activeId, setActiveId, createConversation, updateTitle } =
-    useConversationStore();
+  const {
+    conversations,
+    activeId,
+    setActiveId,
+    createConversation,
+    updateTitle,
+    syncFromServer,
+  } = useConversationStore();
 
   const {
     messages,
@@ -27,11 +33,15 @@ export function ChatPage({ pendingReference, onReferenceClear }: Props) {
     loadConversation,
   } = useChatStore();
 
-  // Load messages when component mounts with an active conversation
+  // Load messages when component mounts with an active conversation, then
+  // refresh the history list from the server.
   useEffect(() => {
     if (activeId) {
       loadConversation(activeId);
     }
+    syncFromServer().catch(() => {
+      // offline: keep the cached list
+    });
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, []);
 
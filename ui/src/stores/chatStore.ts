@@ -1,6 +1,7 @@
 import { create } from "zustand";
 import { persist } from "zustand/middleware";
 import { getApiUrl } from "../api/client";
+import { getConversation } from "../api/conversations";
 import type { ChatMessage, ToolStatusDelta, OrchestrationStepEvent } from "../api/types";
 import { useDashboardStore } from "./dashboardStore";
 
@@ -39,13 +40,33 @@ export const useChatStore = create<ChatState>()(
       orchSteps: [],
 
       loadConversation: (conversationId) => {
-        const saved = get().messagesByConversation[conversationId] ?? [];
+        const saved = get().messagesByConversation[conversationId];
         set({
-          messages: saved,
+          messages: saved ?? [],
           activeConversationId: conversationId,
           toolStatus: [],
           orchSteps: [],
         });
+        if (saved) return;
+
+        // Not cached locally (e.g. chatted from another device): fetch the
+        // transcript from the server.
+        getConversation(conversationId)
+          .then((transcript) => {
+            const loaded: ChatMessage[] = transcript.messages
+              .filter((m) => m.role === "user" || m.role === "assistant")
+              .map((m) => ({ role: m.role as ChatMessage["role"], content: m.content }));
+            set((s) => ({
+              messages: s.activeConversationId === conversationId ? loaded : s.messages,
+              messagesByConversation: {
+                ...s.messagesByConversation,
+                [conversationId]: loaded,
+              },
+            }));
+          })
+          .catch(() => {
+            // new chat that the server has not seen yet
+          });
       },
 
       sendMessage: async (content, conversationId, model) => {
@@ -1,5 +1,7 @@
 import { create } from "zustand";
 import { persist } from "zustand/middleware";
+import { ApiError } from "../api/client";
+import * as conversationsApi from "../api/conversations";
 
 export interface Conversation {
   id: string;
@@ -11,6 +13,13 @@ function generateId(): string {
   return `conv_${Date.now()}_${Math.random().toString(36).slice(2, 8)}`;
 }
 
+/** A chat that has not sent its first message yet is unknown to the server. */
+function ignoreNotFound(err: unknown) {
+  if (!(err instanceof ApiError && err.status === 404)) {
+    console.warn("conversation sync failed", err);
+  }
+}
+
 interface ConversationState {
   conversations: Conversation[];
   activeId: string | null;
@@ -19,6 +28,8 @@ interface ConversationState {
   createConversation: () => string;
   deleteConversation: (id: string) => void;
   updateTitle: (id: string, title: string) => void;
+  /** Replace the list with the server history, keeping unsent local chats. */
+  syncFromServer: () => Promise<void>;
 }
 
 export const useConversationStore = create<ConversationState>()(
@@ -49,14 +60,38 @@ export const useConversationStore = create<ConversationState>()(
           conversations: remaining,
           activeId: activeId === id ? (remaining[0]?.id ?? null) : activeId,
         });
+        conversationsApi.deleteConversation(id).catch(ignoreNotFound);
       },
 
-      updateTitle: (id, title) =>
+      updateTitle: (id, title) => {
         set((s) => ({
           conversations: s.conversations.map((c) =>
             c.id === id ? { ...c, title } : c,
           ),
-        })),
+        }));
+        conversationsApi.renameConversation(id, title).catch(ignoreNotFound);
+      },
+
+      syncFromServer: async () => {
+        const remote = await conversationsApi.listConversations();
+        const remoteIds = new Set(remote.map((c) => c.conversation_id));
+        const localOnly = get().conversations.filter(
+          (c) => !remoteIds.has(c.id) && c.title === "New chat",
+        );
+        const synced: Conversation[] = remote.map((c) => ({
+          id: c.conversation_id,
+          title: c.title || "Untitled",
+          createdAt: Date.parse(c.created_at),
+        }));
+        const conversations = [...localOnly, ...synced];
+        const { activeId } = get();
+        set({
+          conversations,
+          activeId: conversations.some((c) => c.id === activeId)
+            ? activeId
+            : (conversations[0]?.id ?? null),
+        });
+      },
     }),
     { name: "paa-conversations" },
   ),